@@ -0,0 +1,339 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// benchFields is the number of fields in each benchmark document, like in YCSB.
+const benchFields = 10
+
+// benchOp represents a kind of benchmark operation.
+type benchOp string
+
+const (
+	benchRead   benchOp = "read"
+	benchUpdate benchOp = "update"
+)
+
+// benchStats represents statistics of a single kind of benchmark operations.
+type benchStats struct {
+	Op         benchOp       `json:"op"`
+	Count      int           `json:"count"`
+	Errors     int           `json:"errors"`
+	Throughput float64       `json:"throughput"`
+	P50        time.Duration `json:"p50"`
+	P95        time.Duration `json:"p95"`
+	P99        time.Duration `json:"p99"`
+	Max        time.Duration `json:"max"`
+}
+
+// benchReport represents benchmark results for a single target.
+type benchReport struct {
+	Target string       `json:"target"`
+	Stats  []benchStats `json:"stats"`
+}
+
+// benchWorkerResult represents results of a single benchmark worker.
+type benchWorkerResult struct {
+	latencies map[benchOp][]time.Duration
+	errors    map[benchOp]int
+}
+
+// percentile returns the p-th percentile (0 < p <= 1) of the given sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	return sorted[max(i, 0)]
+}
+
+// benchSummarize returns statistics for the given latencies of successful operations,
+// the number of failed ones, and the duration of the run.
+func benchSummarize(op benchOp, latencies []time.Duration, errors int, elapsed time.Duration) benchStats {
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	res := benchStats{
+		Op:     op,
+		Count:  len(sorted),
+		Errors: errors,
+		P50:    percentile(sorted, 0.5),
+		P95:    percentile(sorted, 0.95),
+		P99:    percentile(sorted, 0.99),
+	}
+
+	if len(sorted) > 0 {
+		res.Max = sorted[len(sorted)-1]
+	}
+
+	if elapsed > 0 {
+		res.Throughput = float64(len(sorted)) / elapsed.Seconds()
+	}
+
+	return res
+}
+
+// benchValue returns a random string of the given length.
+func benchValue(r *rand.Rand, n int) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[r.IntN(len(letters))]
+	}
+
+	return string(b)
+}
+
+// benchDocument returns a benchmark document with the given key and approximate size.
+func benchDocument(r *rand.Rand, key, size int) bson.D {
+	doc := make(bson.D, 0, benchFields+1)
+	doc = append(doc, bson.E{Key: "_id", Value: key})
+
+	for i := range benchFields {
+		doc = append(doc, bson.E{Key: fmt.Sprintf("field%d", i), Value: benchValue(r, size/benchFields)})
+	}
+
+	return doc
+}
+
+// benchTargetName returns the given URI without password.
+func benchTargetName(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	return u.Redacted()
+}
+
+// benchLoad drops the benchmark collection and fills it with documents.
+func benchLoad(ctx context.Context, c *mongo.Collection, params *BenchParams) error {
+	if err := c.Drop(ctx); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	r := rand.New(rand.NewPCG(params.Seed, 0))
+
+	const batchSize = 100
+
+	for start := 0; start < params.Records; start += batchSize {
+		batch := make([]any, 0, batchSize)
+
+		for key := start; key < min(start+batchSize, params.Records); key++ {
+			batch = append(batch, benchDocument(r, key, params.DocumentSize))
+		}
+
+		if _, err := c.InsertMany(ctx, batch); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// benchWorker runs random operations against the benchmark collection until runCtx is done.
+//
+// Operations themselves use ctx so the last one is not interrupted.
+func benchWorker(ctx, runCtx context.Context, c *mongo.Collection, params *BenchParams, r *rand.Rand) *benchWorkerResult {
+	res := &benchWorkerResult{
+		latencies: map[benchOp][]time.Duration{},
+		errors:    map[benchOp]int{},
+	}
+
+	for runCtx.Err() == nil && ctx.Err() == nil {
+		key := r.IntN(params.Records)
+		filter := bson.D{{Key: "_id", Value: key}}
+
+		var op benchOp
+		var err error
+
+		start := time.Now()
+
+		if r.Float64() < params.ReadRatio {
+			op = benchRead
+			err = c.FindOne(ctx, filter).Err()
+		} else {
+			op = benchUpdate
+			field := fmt.Sprintf("field%d", r.IntN(benchFields))
+			value := benchValue(r, params.DocumentSize/benchFields)
+			update := bson.D{{Key: "$set", Value: bson.D{{Key: field, Value: value}}}}
+			_, err = c.UpdateOne(ctx, filter, update)
+		}
+
+		if err != nil {
+			res.errors[op]++
+			continue
+		}
+
+		res.latencies[op] = append(res.latencies[op], time.Since(start))
+	}
+
+	return res
+}
+
+// benchTarget runs the benchmark against a single target with the given MongoDB URI.
+func benchTarget(ctx context.Context, params *BenchParams, uri string, logger *slog.Logger) (*benchReport, error) {
+	name := benchTargetName(uri)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer client.Disconnect(ctx) //nolint:errcheck // safe to ignore
+
+	c := client.Database(params.Database).Collection("usertable")
+
+	logger.InfoContext(ctx, fmt.Sprintf("%s: loading %d documents", name, params.Records))
+
+	if err = benchLoad(ctx, c, params); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf("%s: running workload for %s", name, params.Duration))
+
+	runCtx, cancel := context.WithTimeout(ctx, params.Duration)
+	defer cancel()
+
+	results := make([]*benchWorkerResult, params.Concurrency)
+
+	var wg sync.WaitGroup
+
+	start := time.Now()
+
+	for i := range params.Concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			r := rand.New(rand.NewPCG(params.Seed, uint64(i)+1))
+			results[i] = benchWorker(ctx, runCtx, c, params, r)
+		}()
+	}
+
+	wg.Wait()
+
+	elapsed := time.Since(start)
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = c.Drop(ctx); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := &benchReport{Target: name}
+
+	for _, op := range []benchOp{benchRead, benchUpdate} {
+		var latencies []time.Duration
+		var errors int
+
+		for _, r := range results {
+			latencies = append(latencies, r.latencies[op]...)
+			errors += r.errors[op]
+		}
+
+		res.Stats = append(res.Stats, benchSummarize(op, latencies, errors, elapsed))
+	}
+
+	return res, nil
+}
+
+// printBenchReports prints benchmark reports as a table.
+func printBenchReports(w io.Writer, reports []*benchReport) error {
+	tw := tabwriter.NewWriter(w, 1, 1, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Target\tOp\tCount\tErrors\tOps/s\tp50\tp95\tp99\tMax")
+
+	for _, r := range reports {
+		for _, s := range r.Stats {
+			fmt.Fprintf(
+				tw, "%s\t%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\n",
+				r.Target, s.Op, s.Count, s.Errors, s.Throughput,
+				s.P50.Round(time.Microsecond), s.P95.Round(time.Microsecond),
+				s.P99.Round(time.Microsecond), s.Max.Round(time.Microsecond),
+			)
+		}
+	}
+
+	return tw.Flush()
+}
+
+// bench runs YCSB-style workload against all targets one by one and reports results.
+func bench(ctx context.Context, params *BenchParams, w io.Writer, logger *slog.Logger) error {
+	logger.DebugContext(ctx, fmt.Sprintf("bench: %+v", params))
+
+	if params.ReadRatio < 0 || params.ReadRatio > 1 {
+		return fmt.Errorf("read ratio must be between 0 and 1, got %v", params.ReadRatio)
+	}
+
+	if params.Records <= 0 || params.Concurrency <= 0 || params.DocumentSize < benchFields {
+		return fmt.Errorf(
+			"records and concurrency must be positive, and document size must be at least %d bytes", benchFields,
+		)
+	}
+
+	reports := make([]*benchReport, 0, len(params.Targets))
+
+	for _, uri := range params.Targets {
+		r, err := benchTarget(ctx, params, uri, logger)
+		if err != nil {
+			return fmt.Errorf("%s: %w", benchTargetName(uri), err)
+		}
+
+		reports = append(reports, r)
+	}
+
+	if err := printBenchReports(w, reports); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if params.Report == "" {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return os.WriteFile(params.Report, b, 0o666)
+}
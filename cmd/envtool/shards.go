@@ -0,0 +1,215 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// junitTestSuites represents the root element of JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite represents a single Go package in JUnit XML report.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase represents a single test or subtest in JUnit XML report.
+type junitTestCase struct {
+	Classname string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+// junitMessage represents failure or skip details in JUnit XML report.
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Output  string `xml:",chardata"`
+}
+
+// shardResultsFile returns the path of the file with `go test -json` output for the given shard.
+func shardResultsFile(dir string, index, total uint) string {
+	return filepath.Join(dir, fmt.Sprintf("shard-%d-of-%d.json", index, total))
+}
+
+// readTestEvents reads `go test -json` events from the given reader.
+func readTestEvents(r io.Reader) ([]testEvent, error) {
+	var res []testEvent
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for s.Scan() {
+		l := s.Bytes()
+
+		// skip non-JSON lines, such as build errors
+		if len(l) == 0 || l[0] != '{' {
+			continue
+		}
+
+		var event testEvent
+		if err := json.Unmarshal(l, &event); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res = append(res, event)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// junitReport builds JUnit XML report from `go test -json` events.
+func junitReport(events []testEvent) *junitTestSuites {
+	res := new(junitTestSuites)
+
+	suites := make(map[string]int) // package name -> index in res.Suites
+	times := make(map[string]float64)
+	outputs := make(map[string][]string)
+
+	for _, event := range events {
+		i, ok := suites[event.Package]
+		if !ok {
+			i = len(res.Suites)
+			suites[event.Package] = i
+			res.Suites = append(res.Suites, junitTestSuite{Name: event.Package})
+		}
+
+		suite := &res.Suites[i]
+		key := resultKey(event.Package, event.Test)
+
+		switch event.Action {
+		case "output":
+			outputs[key] = append(outputs[key], event.Output)
+			continue
+
+		case "pass", "fail", "skip":
+			// handled below
+
+		default:
+			continue
+		}
+
+		// the same package could be run by several shards
+		if event.Test == "" {
+			times[event.Package] += event.ElapsedSeconds
+			suite.Time = fmt.Sprintf("%.3f", times[event.Package])
+
+			continue
+		}
+
+		tc := junitTestCase{
+			Classname: event.Package,
+			Name:      event.Test,
+			Time:      fmt.Sprintf("%.3f", event.ElapsedSeconds),
+		}
+
+		switch event.Action {
+		case "fail":
+			tc.Failure = &junitMessage{Message: "Failed", Output: strings.Join(outputs[key], "")}
+			suite.Failures++
+			res.Failures++
+
+		case "skip":
+			tc.Skipped = &junitMessage{Message: "Skipped", Output: strings.Join(outputs[key], "")}
+			suite.Skipped++
+			res.Skipped++
+		}
+
+		suite.Tests++
+		res.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	return res
+}
+
+// mergeShardResults merges `go test -json` outputs of all shards with the given total
+// that were saved in the given directory.
+//
+// It writes merged events to `merged.json` and JUnit XML report to `merged.xml` in the same directory,
+// and returns the report and indexes of shards without results.
+func mergeShardResults(dir string, total uint) (*junitTestSuites, []uint, error) {
+	var events []testEvent
+	var missing []uint
+	var merged bytes.Buffer
+
+	for index := uint(1); index <= total; index++ {
+		b, err := os.ReadFile(shardResultsFile(dir, index, total))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				missing = append(missing, index)
+				continue
+			}
+
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		e, err := readTestEvents(bytes.NewReader(b))
+		if err != nil {
+			return nil, nil, lazyerrors.Errorf("shard %d: %w", index, err)
+		}
+
+		events = append(events, e...)
+		merged.Write(b)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "merged.json"), merged.Bytes(), 0o666); err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	report := junitReport(events)
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	b = append([]byte(xml.Header), b...)
+
+	if err = os.WriteFile(filepath.Join(dir, "merged.xml"), b, 0o666); err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	return report, missing, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeShardResults(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	shard1 := `{"Action":"start","Package":"example.com/pkg"}
+{"Action":"run","Package":"example.com/pkg","Test":"TestA"}
+{"Action":"output","Package":"example.com/pkg","Test":"TestA","Output":"--- PASS: TestA (0.50s)\n"}
+{"Action":"pass","Package":"example.com/pkg","Test":"TestA","Elapsed":0.5}
+{"Action":"run","Package":"example.com/pkg","Test":"TestC"}
+{"Action":"output","Package":"example.com/pkg","Test":"TestC","Output":"--- SKIP: TestC (0.00s)\n"}
+{"Action":"skip","Package":"example.com/pkg","Test":"TestC"}
+{"Action":"pass","Package":"example.com/pkg","Elapsed":1}
+`
+
+	shard3 := `{"Action":"start","Package":"example.com/pkg"}
+{"Action":"run","Package":"example.com/pkg","Test":"TestB"}
+{"Action":"output","Package":"example.com/pkg","Test":"TestB","Output":"    b_test.go:10: boom\n"}
+{"Action":"fail","Package":"example.com/pkg","Test":"TestB","Elapsed":0.25}
+{"Action":"fail","Package":"example.com/pkg","Elapsed":2}
+`
+
+	require.NoError(t, os.WriteFile(shardResultsFile(dir, 1, 3), []byte(shard1), 0o666))
+	require.NoError(t, os.WriteFile(shardResultsFile(dir, 3, 3), []byte(shard3), 0o666))
+
+	report, missing, err := mergeShardResults(dir, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{2}, missing)
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+
+	require.Len(t, report.Suites, 1)
+	suite := report.Suites[0]
+	assert.Equal(t, "example.com/pkg", suite.Name)
+	assert.Equal(t, "3.000", suite.Time)
+
+	require.Len(t, suite.Cases, 3)
+	assert.Equal(t, "TestA", suite.Cases[0].Name)
+	assert.Nil(t, suite.Cases[0].Failure)
+	assert.Equal(t, "TestC", suite.Cases[1].Name)
+	assert.NotNil(t, suite.Cases[1].Skipped)
+	assert.Equal(t, "TestB", suite.Cases[2].Name)
+	require.NotNil(t, suite.Cases[2].Failure)
+	assert.Equal(t, "    b_test.go:10: boom\n", suite.Cases[2].Failure.Output)
+
+	merged, err := os.ReadFile(filepath.Join(dir, "merged.json"))
+	require.NoError(t, err)
+	assert.Equal(t, shard1+shard3, string(merged))
+
+	assert.FileExists(t, filepath.Join(dir, "merged.xml"))
+}
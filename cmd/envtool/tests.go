@@ -217,7 +217,9 @@ func resultKey(packageName, testName string) string {
 }
 
 // runGoTest runs `go test` with given extra args.
-func runGoTest(runCtx context.Context, args []string, total uint, times bool, logger *slog.Logger) error {
+//
+// If jsonOut is not nil, `go test -json` output is also written to it.
+func runGoTest(runCtx context.Context, args []string, total uint, times bool, jsonOut io.Writer, logger *slog.Logger) error {
 	cmd := exec.CommandContext(runCtx, "go", append([]string{"test", "-json"}, args...)...)
 
 	logger.InfoContext(runCtx, fmt.Sprintf("Running %s", strings.Join(cmd.Args, " ")))
@@ -245,7 +247,12 @@ func runGoTest(runCtx context.Context, args []string, total uint, times bool, lo
 
 	var done int
 
-	d := json.NewDecoder(p)
+	var r io.Reader = p
+	if jsonOut != nil {
+		r = io.TeeReader(p, jsonOut)
+	}
+
+	d := json.NewDecoder(r)
 	d.DisallowUnknownFields()
 
 	totalTests := "?"
@@ -474,18 +481,8 @@ func runGoTest(runCtx context.Context, args []string, total uint, times bool, lo
 	return cmd.Wait()
 }
 
-// testsRun runs tests specified by the shard index and total or by the run regex
-// using `go test` with given extra args.
-func testsRun(ctx context.Context, params *TestsRunParams, logger *slog.Logger) error {
-	logger.DebugContext(ctx, fmt.Sprintf("testsRun: %+v", params))
-
-	args, total, err := testArgs("", params.ShardIndex, params.ShardTotal, params.Run, params.Skip, logger)
-	if err != nil {
-		return lazyerrors.Error(err)
-	}
-
-	args = append(args, params.Args...)
-
+// runGoTestWithTraces runs `go test` like [runGoTest], exporting traces of tests.
+func runGoTestWithTraces(ctx context.Context, args []string, total uint, jsonOut io.Writer, logger *slog.Logger) error {
 	ot, err := observability.NewOTelTraceExporter(&observability.OTelTraceExporterOpts{
 		Logger:  logger,
 		Service: "envtool-tests",
@@ -503,10 +500,91 @@ func testsRun(ctx context.Context, params *TestsRunParams, logger *slog.Logger)
 		close(done)
 	}()
 
-	err = runGoTest(ctx, args, total, true, logger)
+	err = runGoTest(ctx, args, total, true, jsonOut, logger)
 
 	cancel()
 	<-done
 
 	return err
 }
+
+// testsRun runs tests specified by the shard index and total or by the run regex
+// using `go test` with given extra args.
+func testsRun(ctx context.Context, params *TestsRunParams, logger *slog.Logger) error {
+	logger.DebugContext(ctx, fmt.Sprintf("testsRun: %+v", params))
+
+	args, total, err := testArgs("", params.ShardIndex, params.ShardTotal, params.Run, params.Skip, logger)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	args = append(args, params.Args...)
+
+	return runGoTestWithTraces(ctx, args, total, nil, logger)
+}
+
+// testsShard prints top-level test functions of the given shard.
+//
+// If RunAndMerge is set, it runs them using `go test` with given extra args instead,
+// saves `go test -json` output to the results directory,
+// and merges results of all shards with the same total saved there.
+func testsShard(ctx context.Context, params *TestsShardParams, w io.Writer, logger *slog.Logger) error {
+	logger.DebugContext(ctx, fmt.Sprintf("testsShard: %+v", params))
+
+	if !params.RunAndMerge {
+		testFuncs, err := listTestFuncs("", ".", logger)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if testFuncs, err = shardTestFuncs(params.Index, params.Total, testFuncs); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for _, t := range testFuncs {
+			fmt.Fprintln(w, t)
+		}
+
+		return nil
+	}
+
+	args, total, err := testArgs("", params.Index, params.Total, "", "", logger)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	args = append(args, params.Args...)
+
+	if err = os.MkdirAll(params.Results, 0o777); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	f, err := os.Create(shardResultsFile(params.Results, params.Index, params.Total))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer f.Close() //nolint:errcheck // closed explicitly below
+
+	runErr := runGoTestWithTraces(ctx, args, total, f, logger)
+
+	if err = f.Close(); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	report, missing, err := mergeShardResults(params.Results, params.Total)
+	if err != nil {
+		return errors.Join(runErr, lazyerrors.Error(err))
+	}
+
+	logger.InfoContext(ctx, fmt.Sprintf(
+		"Merged results of %d/%d shards into %s: %d tests, %d failed, %d skipped",
+		params.Total-uint(len(missing)), params.Total, params.Results, report.Tests, report.Failures, report.Skipped,
+	))
+
+	if len(missing) > 0 {
+		logger.WarnContext(ctx, fmt.Sprintf("Shards without results: %v", missing))
+	}
+
+	return runErr
+}
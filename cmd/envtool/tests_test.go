@@ -140,7 +140,7 @@ func TestRunGoTest(t *testing.T) {
 
 		l, buf := bufLogger()
 
-		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestNormal"}, 2, false, l)
+		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestNormal"}, 2, false, nil, l)
 		require.NoError(t, err)
 
 		expected := []string{
@@ -161,7 +161,7 @@ func TestRunGoTest(t *testing.T) {
 
 		l, buf := bufLogger()
 
-		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestWithSubtest/Third"}, 1, false, l)
+		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestWithSubtest/Third"}, 1, false, nil, l)
 		require.NoError(t, err)
 
 		expected := []string{
@@ -181,7 +181,7 @@ func TestRunGoTest(t *testing.T) {
 
 		l, buf := bufLogger()
 
-		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestWithSubtest/None"}, 1, false, l)
+		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestWithSubtest/None"}, 1, false, nil, l)
 		require.NoError(t, err)
 
 		expected := []string{
@@ -202,7 +202,7 @@ func TestRunGoTest(t *testing.T) {
 
 		l, buf := bufLogger()
 
-		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestError"}, 2, false, l)
+		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestError"}, 2, false, nil, l)
 
 		var exitErr *exec.ExitError
 		require.ErrorAs(t, err, &exitErr)
@@ -249,7 +249,7 @@ func TestRunGoTest(t *testing.T) {
 
 		l, buf := bufLogger()
 
-		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestSkip"}, 1, false, l)
+		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestSkip"}, 1, false, nil, l)
 		require.NoError(t, err)
 
 		expected := []string{
@@ -274,7 +274,7 @@ func TestRunGoTest(t *testing.T) {
 
 		l, buf := bufLogger()
 
-		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestPanic"}, 1, false, l)
+		err := runGoTest(ctx, []string{"./testdata", "-count=1", "-run=TestPanic"}, 1, false, nil, l)
 		require.Error(t, err)
 
 		expected := []string{
@@ -0,0 +1,375 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// loadgenOps contains all operations of the load generator workload in the report order.
+var loadgenOps = []string{"find", "insert", "update", "delete", "aggregate"}
+
+// loadgenCategories is the number of distinct `category` field values.
+const loadgenCategories = 10
+
+// loadgenParams represents `loadgen` command parameters.
+//
+//nolint:vet // for readability
+type loadgenParams struct {
+	URI         string         `default:"mongodb://127.0.0.1:27017/" help:"Target MongoDB URI."`
+	Database    string         `default:"loadgen"                    help:"Database name; the collection is dropped and recreated."`
+	Collection  string         `default:"loadgen"                    help:"Collection name."`
+	Documents   int            `default:"1000"                       help:"Number of documents to load before the run."`
+	PayloadSize int            `default:"100"                        help:"Size of the payload field in bytes."`
+	Mix         map[string]int `default:"find=60;insert=10;update=20;delete=5;aggregate=5" help:"Relative weights of operations."`
+	Concurrency int            `default:"4"                          help:"Number of concurrent clients."`
+	Duration    time.Duration  `default:"1m"                         help:"Workload duration."`
+	Seed        uint64         `default:"1"                          help:"Random seed; the same seed produces the same operations."`
+}
+
+// loadgenStats represents statistics of a single operation.
+type loadgenStats struct {
+	op         string
+	count      int
+	errors     int
+	throughput float64
+	p50        time.Duration
+	p90        time.Duration
+	p99        time.Duration
+	max        time.Duration
+}
+
+// loadgenWorker represents a single load generator client.
+//
+// Operations of each worker depend only on the seed and worker's index,
+// so runs with the same parameters replay the same workload.
+type loadgenWorker struct {
+	r         *rand.Rand
+	params    *loadgenParams
+	c         *mongo.Collection
+	index     int
+	inserted  int
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+// loadgenValidate checks load generator parameters.
+func loadgenValidate(params *loadgenParams) error {
+	if params.Documents <= 0 || params.Concurrency <= 0 || params.PayloadSize < 0 || params.Duration <= 0 {
+		return fmt.Errorf("documents, concurrency, and duration must be positive, payload size must not be negative")
+	}
+
+	var total int
+
+	for op, w := range params.Mix {
+		if !slices.Contains(loadgenOps, op) {
+			return fmt.Errorf("unknown operation %q, expected one of: %s", op, strings.Join(loadgenOps, ", "))
+		}
+
+		if w < 0 {
+			return fmt.Errorf("weight of %q must not be negative", op)
+		}
+
+		total += w
+	}
+
+	if total == 0 {
+		return fmt.Errorf("at least one operation should have a positive weight")
+	}
+
+	return nil
+}
+
+// nextOp returns the next operation according to the weights.
+func (w *loadgenWorker) nextOp() string {
+	var total int
+	for _, op := range loadgenOps {
+		total += w.params.Mix[op]
+	}
+
+	n := w.r.IntN(total)
+
+	for _, op := range loadgenOps {
+		if n < w.params.Mix[op] {
+			return op
+		}
+
+		n -= w.params.Mix[op]
+	}
+
+	panic("not reached")
+}
+
+// document returns a new document with the given key.
+func (w *loadgenWorker) document(key int64) bson.D {
+	payload := make([]byte, w.params.PayloadSize)
+	for i := range payload {
+		payload[i] = byte('a' + w.r.IntN(26))
+	}
+
+	return bson.D{
+		{Key: "_id", Value: key},
+		{Key: "category", Value: fmt.Sprintf("c%d", key%loadgenCategories)},
+		{Key: "value", Value: w.r.Int64N(int64(w.params.Documents))},
+		{Key: "payload", Value: string(payload)},
+	}
+}
+
+// do runs a single operation.
+func (w *loadgenWorker) do(ctx context.Context, op string) error {
+	filter := bson.D{{Key: "_id", Value: w.r.Int64N(int64(w.params.Documents))}}
+
+	switch op {
+	case "find":
+		err := w.c.FindOne(ctx, filter).Err()
+		if err == mongo.ErrNoDocuments {
+			err = nil
+		}
+
+		return err
+
+	case "insert":
+		// keys of inserted documents do not overlap between workers and loaded documents
+		w.inserted++
+		key := int64(w.params.Documents) + int64(w.index)<<32 + int64(w.inserted)
+
+		_, err := w.c.InsertOne(ctx, w.document(key))
+
+		return err
+
+	case "update":
+		_, err := w.c.UpdateOne(ctx, filter, bson.D{{Key: "$inc", Value: bson.D{{Key: "value", Value: int64(1)}}}})
+		return err
+
+	case "delete":
+		_, err := w.c.DeleteOne(ctx, filter)
+		return err
+
+	case "aggregate":
+		from := w.r.Int64N(int64(w.params.Documents))
+		pipeline := bson.A{
+			bson.D{{Key: "$match", Value: bson.D{{Key: "value", Value: bson.D{
+				{Key: "$gte", Value: from},
+				{Key: "$lt", Value: from + 100},
+			}}}}},
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$category"},
+				{Key: "count", Value: bson.D{{Key: "$sum", Value: int32(1)}}},
+			}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: int32(1)}}}},
+		}
+
+		cursor, err := w.c.Aggregate(ctx, pipeline)
+		if err != nil {
+			return err
+		}
+
+		var res []bson.D
+
+		return cursor.All(ctx, &res)
+
+	default:
+		panic(fmt.Sprintf("unknown operation %q", op))
+	}
+}
+
+// run runs operations until runCtx is done.
+//
+// Operations themselves use ctx so the last one is not interrupted.
+func (w *loadgenWorker) run(ctx, runCtx context.Context) {
+	for runCtx.Err() == nil && ctx.Err() == nil {
+		op := w.nextOp()
+		start := time.Now()
+
+		if err := w.do(ctx, op); err != nil {
+			w.errors[op]++
+			continue
+		}
+
+		w.latencies[op] = append(w.latencies[op], time.Since(start))
+	}
+}
+
+// loadgenPercentile returns the p-th percentile (0 < p <= 1) of the given sorted latencies.
+func loadgenPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+
+	return sorted[max(i, 0)]
+}
+
+// loadgenSummarize returns statistics of the given operation.
+func loadgenSummarize(op string, latencies []time.Duration, errors int, elapsed time.Duration) *loadgenStats {
+	slices.Sort(latencies)
+
+	res := &loadgenStats{
+		op:     op,
+		count:  len(latencies),
+		errors: errors,
+		p50:    loadgenPercentile(latencies, 0.5),
+		p90:    loadgenPercentile(latencies, 0.9),
+		p99:    loadgenPercentile(latencies, 0.99),
+	}
+
+	if len(latencies) > 0 {
+		res.max = latencies[len(latencies)-1]
+	}
+
+	if elapsed > 0 {
+		res.throughput = float64(len(latencies)) / elapsed.Seconds()
+	}
+
+	return res
+}
+
+// loadgenLoad drops the collection and fills it with documents.
+func loadgenLoad(ctx context.Context, c *mongo.Collection, params *loadgenParams) error {
+	if err := c.Drop(ctx); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	w := &loadgenWorker{
+		r:      rand.New(rand.NewPCG(params.Seed, 0)),
+		params: params,
+	}
+
+	const batchSize = 100
+
+	for start := 0; start < params.Documents; start += batchSize {
+		batch := make([]any, 0, batchSize)
+
+		for key := start; key < min(start+batchSize, params.Documents); key++ {
+			batch = append(batch, w.document(int64(key)))
+		}
+
+		if _, err := c.InsertMany(ctx, batch); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// loadgen runs the load generator workload against the target and prints a report.
+func loadgen(ctx context.Context, params *loadgenParams, out io.Writer, l *slog.Logger) error {
+	if err := loadgenValidate(params); err != nil {
+		return err
+	}
+
+	target := params.URI
+	if u, err := url.Parse(params.URI); err == nil {
+		target = u.Redacted()
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(params.URI))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer client.Disconnect(ctx) //nolint:errcheck // safe to ignore
+
+	c := client.Database(params.Database).Collection(params.Collection)
+
+	l.InfoContext(ctx, fmt.Sprintf("Loading %d documents into %s", params.Documents, target))
+
+	if err = loadgenLoad(ctx, c, params); err != nil {
+		return err
+	}
+
+	l.InfoContext(ctx, fmt.Sprintf("Running workload with %d clients for %s", params.Concurrency, params.Duration))
+
+	runCtx, cancel := context.WithTimeout(ctx, params.Duration)
+	defer cancel()
+
+	workers := make([]*loadgenWorker, params.Concurrency)
+
+	var wg sync.WaitGroup
+
+	start := time.Now()
+
+	for i := range workers {
+		workers[i] = &loadgenWorker{
+			r:         rand.New(rand.NewPCG(params.Seed, uint64(i)+1)),
+			params:    params,
+			c:         c,
+			index:     i,
+			latencies: map[string][]time.Duration{},
+			errors:    map[string]int{},
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			workers[i].run(ctx, runCtx)
+		}()
+	}
+
+	wg.Wait()
+
+	elapsed := time.Since(start)
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 1, 1, 2, ' ', 0)
+	fmt.Fprintln(tw, "Op\tCount\tErrors\tOps/s\tp50\tp90\tp99\tMax")
+
+	for _, op := range loadgenOps {
+		if params.Mix[op] == 0 {
+			continue
+		}
+
+		var latencies []time.Duration
+		var errors int
+
+		for _, w := range workers {
+			latencies = append(latencies, w.latencies[op]...)
+			errors += w.errors[op]
+		}
+
+		s := loadgenSummarize(op, latencies, errors, elapsed)
+		fmt.Fprintf(
+			tw, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\n",
+			s.op, s.count, s.errors, s.throughput,
+			s.p50.Round(time.Microsecond), s.p90.Round(time.Microsecond),
+			s.p99.Round(time.Microsecond), s.max.Round(time.Microsecond),
+		)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadgenValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *loadgenParams {
+		return &loadgenParams{
+			Documents:   10,
+			Mix:         map[string]int{"find": 1, "update": 0},
+			Concurrency: 1,
+			Duration:    time.Second,
+		}
+	}
+
+	require.NoError(t, loadgenValidate(valid()))
+
+	p := valid()
+	p.Mix = map[string]int{"upsert": 1}
+	assert.ErrorContains(t, loadgenValidate(p), `unknown operation "upsert"`)
+
+	p = valid()
+	p.Mix = map[string]int{"find": 0}
+	assert.ErrorContains(t, loadgenValidate(p), "at least one operation")
+
+	p = valid()
+	p.Concurrency = 0
+	assert.Error(t, loadgenValidate(p))
+}
+
+func TestLoadgenNextOp(t *testing.T) {
+	t.Parallel()
+
+	params := &loadgenParams{Mix: map[string]int{"find": 3, "delete": 1}}
+
+	ops := func() []string {
+		w := &loadgenWorker{r: rand.New(rand.NewPCG(42, 1)), params: params}
+
+		res := make([]string, 1000)
+		for i := range res {
+			res[i] = w.nextOp()
+		}
+
+		return res
+	}
+
+	first := ops()
+	assert.Equal(t, first, ops(), "the same seed should produce the same operations")
+
+	counts := map[string]int{}
+	for _, op := range first {
+		counts[op]++
+	}
+
+	assert.Len(t, counts, 2)
+	assert.InDelta(t, 750, counts["find"], 75)
+	assert.InDelta(t, 250, counts["delete"], 75)
+}
+
+func TestLoadgenSummarize(t *testing.T) {
+	t.Parallel()
+
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+
+	s := loadgenSummarize("find", latencies, 2, 10*time.Second)
+	assert.Equal(t, 100, s.count)
+	assert.Equal(t, 2, s.errors)
+	assert.Equal(t, 50*time.Millisecond, s.p50)
+	assert.Equal(t, 90*time.Millisecond, s.p90)
+	assert.Equal(t, 99*time.Millisecond, s.p99)
+	assert.Equal(t, 100*time.Millisecond, s.max)
+	assert.InDelta(t, 10.0, s.throughput, 0.001)
+
+	assert.Equal(t, &loadgenStats{op: "delete"}, loadgenSummarize("delete", nil, 0, time.Second))
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/clientconn"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/handler"
 	"github.com/FerretDB/FerretDB/internal/handler/registry"
 	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/debug"
@@ -44,6 +45,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/observability"
 	"github.com/FerretDB/FerretDB/internal/util/password"
+	"github.com/FerretDB/FerretDB/internal/util/secret"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 	"github.com/FerretDB/FerretDB/internal/util/telemetry"
 )
@@ -57,11 +59,18 @@ var cli struct {
 	Run  struct{} `cmd:"" default:"1"                             hidden:""`
 	Ping struct{} `cmd:"" help:"Ping existing FerretDB instance."`
 
-	Version     bool   `default:"false"           help:"Print version to stdout and exit." env:"-"`
-	Handler     string `default:"postgresql"      help:"${help_handler}"`
-	Mode        string `default:"${default_mode}" help:"${help_mode}"                      enum:"${enum_mode}"`
-	StateDir    string `default:"."               help:"Process state directory."`
-	ReplSetName string `default:""                help:"Replica set name."`
+	Loadgen loadgenParams `cmd:"" help:"Development: run reproducible CRUD and aggregation workload against given URI."`
+
+	SupportBundle supportBundleParams `cmd:"" help:"Download debug archive for bug reports from running FerretDB instance."`
+
+	Version      bool   `default:"false"           help:"Print version to stdout and exit." env:"-"`
+	Handler      string `default:"postgresql"      help:"${help_handler}"`
+	Mode         string `default:"${default_mode}" help:"${help_mode}"                      enum:"${enum_mode}"`
+	StateDir     string `default:"."               help:"Process state directory."`
+	StateKey     string `default:""                help:"Base64-encoded 32-byte key for state directory encryption."`
+	StateKeyFile string `default:""                help:"File with state directory encryption key."`
+	ReplSetName  string `default:""                help:"Replica set name."`
+	ExportURL    string `default:""                help:"Base URL for collection export and import (file:///path or s3://bucket/prefix)."`
 
 	Listen struct {
 		Addr        string `default:"127.0.0.1:27017" help:"Listen TCP address."`
@@ -70,6 +79,12 @@ var cli struct {
 		TLSCertFile string `default:""                help:"TLS cert file path."`
 		TLSKeyFile  string `default:""                help:"TLS key file path."`
 		TLSCaFile   string `default:""                help:"TLS CA file path."`
+
+		TLSMinVersion   string   `default:"1.2"   name:"tls-min-version"   enum:"1.0,1.1,1.2,1.3" help:"Minimal TLS version: ${enum}."`
+		TLSCipherSuites []string `                name:"tls-cipher-suites"                        help:"Allowed TLS 1.0-1.2 cipher suites; Go defaults if empty."`
+		TLSOCSPStapling bool     `default:"false" name:"tls-ocsp-stapling"                        help:"Staple OCSP responses for TLS cert."`
+
+		ProxyProtocol bool `default:"false" help:"Expect PROXY protocol header on TCP and TLS connections."`
 	} `embed:"" prefix:"listen-"`
 
 	Proxy struct {
@@ -81,20 +96,93 @@ var cli struct {
 
 	DebugAddr string `default:"127.0.0.1:8088" help:"Listen address for HTTP handlers for metrics, profiling, etc."`
 
+	Profile struct {
+		BlockRate     int `default:"10000" help:"Block profile rate in nanoseconds of blocking time, 0 disables."`
+		MutexFraction int `default:"0"     help:"Report 1 of N mutex contention events in mutex profile, 0 disables."`
+
+		PushURL      string        `default:""    help:"Base URL of continuous profiling server for pushing CPU and heap profiles."`
+		PushInterval time.Duration `default:"15s" help:"Interval of collecting and pushing profiles."`
+	} `embed:"" prefix:"profile-"`
+
+	Backup struct {
+		URL      string `default:"" help:"Base URL for backups (s3://bucket/prefix or file:///path)."`
+		SSE      string `default:"" help:"S3 server-side encryption of backups (AES256, aws:kms, or aws:kms:dsse)."`
+		KMSKeyID string `default:"" help:"AWS KMS key ID for server-side encryption of backups."`
+	} `embed:"" prefix:"backup-"`
+
+	Session struct {
+		Timeout         time.Duration `default:"30m" help:"Logical session timeout (whole minutes)."`
+		CleanupInterval time.Duration `default:"5m"  help:"Expired logical sessions cleanup interval."`
+	} `embed:"" prefix:"session-"`
+
 	// see setCLIPlugins
 	kong.Plugins
 
 	Setup struct {
-		Database string        `default:""    help:"Setup database during backend initialization."`
-		Username string        `default:""    help:"Setup user during backend initialization."`
-		Password string        `default:""    help:"Setup user's password."`
-		Timeout  time.Duration `default:"30s" help:"Setup timeout."`
+		Database     string        `default:""    help:"Setup database during backend initialization."`
+		Username     string        `default:""    help:"Setup user during backend initialization; admin user if database is not set."`
+		Password     string        `default:""    help:"Setup user's password."`
+		PasswordFile string        `default:""    help:"File with setup user's password."`
+		Timeout      time.Duration `default:"30s" help:"Setup timeout."`
 	} `embed:"" prefix:"setup-"`
 
+	SCRAM struct {
+		IterationCount       int `default:"10000" help:"Iteration count for new SCRAM-SHA-1 credentials."`
+		SHA256IterationCount int `default:"15000" help:"Iteration count for new SCRAM-SHA-256 credentials."`
+	} `embed:"" prefix:"scram-"`
+
+	Password struct {
+		MinLength         int  `default:"0"     help:"Minimal length of new passwords, 0 for unlimited."`
+		RequireComplexity bool `default:"false" help:"Require new passwords to contain three of four character classes."`
+	} `embed:"" prefix:"password-"`
+
+	Auth struct {
+		FailureDelay     time.Duration `default:"0s"  help:"Delay after a failed authentication attempt, doubled after each next one; 0 disables."`
+		FailureMaxDelay  time.Duration `default:"10s" help:"Maximal delay after failed authentication attempts."`
+		LockoutThreshold int           `default:"0"   help:"Number of failed authentication attempts before a temporary lockout, 0 disables."`
+		LockoutDuration  time.Duration `default:"15m" help:"Duration of the authentication lockout."`
+	} `embed:"" prefix:"auth-"`
+
+	Backend struct {
+		Timeout    time.Duration `default:"0s"    help:"Timeout of each backend call; 0 disables."`
+		HedgeReads bool          `default:"false" help:"Retry slow read queries concurrently after p99 latency."`
+	} `embed:"" prefix:"backend-"`
+
+	SecretsReloadInterval time.Duration `default:"10s" help:"Interval of checking secret files for changes."`
+
+	AutoMigrate bool `default:"true" help:"Apply FerretDB metadata migrations on startup." negatable:""`
+	ReadOnly    bool `default:"false" help:"Reject all write commands."`
+
+	Quota struct {
+		MaxCollections int   `default:"0" help:"Maximum number of collections in a database, 0 for unlimited."`
+		MaxDocuments   int64 `default:"0" help:"Maximum number of documents in a collection, 0 for unlimited."`
+		MaxSize        int64 `default:"0" help:"Maximum size of a collection in bytes, 0 for unlimited."`
+	} `embed:"" prefix:"quota-"`
+
+	MaxDocumentSize     int   `default:"16777216" help:"Maximum document size in bytes."`
+	ResultCacheSize     int64 `default:"0"        help:"Maximum size of read commands result cache in bytes, 0 to disable."`
+	CursorReadaheadSize int64 `default:"0"        help:"Maximum size of documents prefetched by cursors in bytes, 0 to disable."`
+
+	OperationMemoryLimit int64 `default:"104857600" help:"Maximum memory used by in-memory sort and group of an operation in bytes, 0 for unlimited."`
+
+	ConsistencyCheck struct {
+		Interval   time.Duration `default:"0s"  help:"Background consistency check interval, 0 disables."`
+		SampleSize int           `default:"100" help:"Number of documents per collection checked against indexes."`
+	} `embed:"" prefix:"consistency-check-"`
+
+	TenantPrefixes map[string]string `default:"" help:"Database name prefixes of tenant users, for example: alice=tenant1_;bob=tenant2_."`
+
+	Warmup struct {
+		Timeout     time.Duration `default:"0s" help:"Startup warm-up timeout before reporting readiness, 0 disables."`
+		Connections int           `default:"4"  help:"Number of backend connections established during warm-up."`
+	} `embed:"" prefix:"warmup-"`
+
 	Log struct {
-		Level  string `default:"${default_log_level}" help:"${help_log_level}"`
-		Format string `default:"console"              help:"${help_log_format}"                     enum:"${enum_log_format}"`
-		UUID   bool   `default:"false"                help:"Add instance UUID to all log messages." negatable:""`
+		Level         string        `default:"${default_log_level}" help:"${help_log_level}"`
+		Format        string        `default:"console"              help:"${help_log_format}"                                            enum:"${enum_log_format}"`
+		UUID          bool          `default:"false"                help:"Add instance UUID to all log messages."                        negatable:""`
+		SlowThreshold time.Duration `default:"0s"                   help:"Log commands that take longer than this duration; 0 disables."`
+		SlowExplain   bool          `default:"true"                 help:"Add execution plans of slow queries to the log."               negatable:""`
 	} `embed:"" prefix:"log-"`
 
 	MetricsUUID bool `default:"false" help:"Add instance UUID to all metrics." negatable:""`
@@ -105,13 +193,16 @@ var cli struct {
 		} `embed:"" prefix:"traces-"`
 	} `embed:"" prefix:"otel-"`
 
-	Telemetry telemetry.Flag `default:"undecided" help:"Enable or disable basic telemetry. See https://beacon.ferretdb.com."`
+	Telemetry      telemetry.Flag `default:"undecided" help:"Enable or disable basic telemetry. See https://beacon.ferretdb.com."`
+	TelemetryProxy string         `default:""          help:"HTTP proxy URL for telemetry reports (e.g. 'http://proxy:3128')."`
 
 	Test struct {
 		RecordsDir string `default:"" help:"Testing: directory for record files."`
 
 		DisablePushdown      bool `default:"false" help:"Experimental: disable pushdown."`
 		EnableNestedPushdown bool `default:"false" help:"Experimental: enable pushdown for dot notation."`
+		EnableSortPushdown   bool `default:"false" help:"Experimental: enable sort pushdown for indexed fields."`
+		EnableExplainSQL     bool `default:"false" help:"Experimental: add generated SQL queries to explain output."`
 
 		CappedCleanup struct {
 			Interval   time.Duration `default:"1m" help:"Experimental: capped collections cleanup interval."`
@@ -120,8 +211,16 @@ var cli struct {
 
 		EnableNewAuth bool `default:"false" help:"Experimental: enable new authentication."`
 
-		BatchSize            int `default:"100" help:"Experimental: maximum insertion batch size."`
-		MaxBsonObjectSizeMiB int `default:"16"  help:"Experimental: maximum BSON object size in MiB."`
+		BatchSize int `default:"100" help:"Experimental: maximum insertion batch size."`
+
+		RandSeed uint64 `default:"0" help:"Testing: seed for $rand and $sampleRate; 0 means random."`
+
+		Chaos struct {
+			DelayProbability    float64       `default:"0"  help:"Testing: probability of delaying a response."`
+			MaxDelay            time.Duration `default:"1s" help:"Testing: maximum response delay."`
+			TruncateProbability float64       `default:"0"  help:"Testing: probability of truncating a response and closing the connection."`
+			DropProbability     float64       `default:"0"  help:"Testing: probability of dropping a response."`
+		} `embed:"" prefix:"chaos-"`
 
 		Telemetry struct {
 			URL            string        `default:"https://beacon.ferretdb.com/" help:"Telemetry: reporting URL."`
@@ -139,7 +238,13 @@ var cli struct {
 //
 //nolint:lll // some tags are long
 var postgreSQLFlags struct {
-	PostgreSQLURL string `name:"postgresql-url" default:"postgres://127.0.0.1:5432/ferretdb" help:"PostgreSQL URL for 'postgresql' handler."`
+	PostgreSQLURL     string `name:"postgresql-url"      default:"postgres://127.0.0.1:5432/ferretdb" help:"PostgreSQL URL for 'postgresql' handler."`
+	PostgreSQLURLFile string `name:"postgresql-url-file" default:""                                   help:"File with PostgreSQL URL for 'postgresql' handler; reloaded on changes."`
+
+	PostgreSQLTLSCAFile     string `name:"postgresql-tls-ca-file"     default:""      help:"PostgreSQL TLS CA file path."`
+	PostgreSQLTLSCertFile   string `name:"postgresql-tls-cert-file"   default:""      help:"PostgreSQL TLS client cert file path."`
+	PostgreSQLTLSKeyFile    string `name:"postgresql-tls-key-file"    default:""      help:"PostgreSQL TLS client key file path."`
+	PostgreSQLTLSVerifyFull bool   `name:"postgresql-tls-verify-full" default:"false" help:"Verify PostgreSQL server host name against its TLS cert."`
 }
 
 // The sqliteFlags struct represents flags that are used by the "sqlite" backend.
@@ -149,18 +254,26 @@ var sqliteFlags struct {
 	SQLiteURL string `name:"sqlite-url" default:"file:data/" help:"SQLite URI (directory) for 'sqlite' handler."`
 }
 
+// The memoryFlags struct represents flags that are used by the "memory" backend.
+// There are none yet.
+//
+// See main_memory.go.
+var memoryFlags struct{}
+
 // The mySQLFlags struct represents flags that are used by the "mysql" backend.
 //
 // See main_mysql.go.
 var mySQLFlags struct {
-	MySQLURL string `name:"mysql-url" default:"mysql://127.0.0.1:3306/ferretdb" help:"MySQL URL for 'mysql' handler" hidden:""`
+	MySQLURL     string `name:"mysql-url"      default:"mysql://127.0.0.1:3306/ferretdb" help:"MySQL URL for 'mysql' handler"           hidden:""`
+	MySQLURLFile string `name:"mysql-url-file" default:""                                help:"File with MySQL URL for 'mysql' handler" hidden:""`
 }
 
 // The hanaFlags struct represents flags that are used by the "hana" handler.
 //
 // See main_hana.go.
 var hanaFlags struct {
-	HANAURL string `name:"hana-url" help:"SAP HANA URL for 'hana' handler"`
+	HANAURL     string `name:"hana-url"      help:"SAP HANA URL for 'hana' handler"`
+	HANAURLFile string `name:"hana-url-file" help:"File with SAP HANA URL for 'hana' handler"`
 }
 
 // handlerFlags is a map of handler names to their flags.
@@ -221,7 +334,7 @@ func main() {
 
 	switch ctx.Command() {
 	case "run":
-		run()
+		run(sanitizedConfig(ctx))
 	case "ping":
 		logger := setupLogger(cli.Log.Format, "")
 		checkFlags(logger)
@@ -237,6 +350,29 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "loadgen":
+		logger := setupLogger(cli.Log.Format, "")
+
+		ctx, stop := ctxutil.SigTerm(context.Background())
+		defer stop()
+
+		if err := loadgen(ctx, &cli.Loadgen, os.Stdout, logger); err != nil {
+			logger.LogAttrs(ctx, logging.LevelFatal, "Load generator failed", logging.Error(err))
+		}
+
+	case "support-bundle":
+		logger := setupLogger(cli.Log.Format, "")
+
+		ctx, stop := ctxutil.SigTerm(context.Background())
+		defer stop()
+
+		path, err := supportBundle(ctx, &cli.SupportBundle, cli.DebugAddr, logger)
+		if err != nil {
+			logger.LogAttrs(ctx, logging.LevelFatal, "Failed to download support bundle", logging.Error(err))
+		}
+
+		fmt.Fprintln(os.Stdout, path)
+
 	default:
 		panic("unknown sub-command")
 	}
@@ -262,7 +398,29 @@ func setupState() *state.Provider {
 		}
 	}
 
-	sp, err := state.NewProvider(f)
+	key := cli.StateKey
+
+	if cli.StateKeyFile != "" {
+		var err error
+		if key, err = secret.ReadFile(cli.StateKeyFile); err != nil {
+			log.Fatalf("Failed to read state encryption key: %s.", err)
+		}
+	}
+
+	var sp *state.Provider
+	var err error
+
+	if key == "" {
+		sp, err = state.NewProvider(f)
+	} else {
+		var k []byte
+		if k, err = state.ParseKey(key); err != nil {
+			log.Fatalf("Invalid state encryption key: %s.", err)
+		}
+
+		sp, err = state.NewEncryptedProvider(f, k)
+	}
+
 	if err != nil {
 		log.Fatal(stateFileProblem(f, err))
 	}
@@ -310,14 +468,42 @@ func setupLogger(format string, uuid string) *slog.Logger {
 func checkFlags(l *slog.Logger) {
 	ctx := context.Background()
 
-	if cli.Setup.Database != "" && !cli.Test.EnableNewAuth {
-		l.LogAttrs(ctx, logging.LevelFatal, "--setup-database requires --test-enable-new-auth")
+	if cli.Setup.Username != "" && !cli.Test.EnableNewAuth {
+		l.LogAttrs(ctx, logging.LevelFatal, "--setup-username requires --test-enable-new-auth")
 	}
 
-	if (cli.Setup.Database == "") != (cli.Setup.Username == "") {
+	if cli.Setup.Database != "" && cli.Setup.Username == "" {
 		l.LogAttrs(ctx, logging.LevelFatal, "--setup-database should be used together with --setup-username")
 	}
 
+	if cli.Quota.MaxCollections < 0 || cli.Quota.MaxDocuments < 0 || cli.Quota.MaxSize < 0 {
+		l.LogAttrs(ctx, logging.LevelFatal, "--quota-XXX flags should not be negative")
+	}
+
+	if cli.ResultCacheSize < 0 {
+		l.LogAttrs(ctx, logging.LevelFatal, "--result-cache-size should not be negative")
+	}
+
+	if cli.Log.SlowThreshold < 0 {
+		l.LogAttrs(ctx, logging.LevelFatal, "--log-slow-threshold should not be negative")
+	}
+
+	if cli.Profile.BlockRate < 0 || cli.Profile.MutexFraction < 0 {
+		l.LogAttrs(ctx, logging.LevelFatal, "--profile-XXX flags should not be negative")
+	}
+
+	if cli.Profile.PushInterval <= 0 {
+		l.LogAttrs(ctx, logging.LevelFatal, "--profile-push-interval should be positive")
+	}
+
+	if cli.ConsistencyCheck.Interval < 0 {
+		l.LogAttrs(ctx, logging.LevelFatal, "--consistency-check-interval should not be negative")
+	}
+
+	if cli.ConsistencyCheck.SampleSize <= 0 {
+		l.LogAttrs(ctx, logging.LevelFatal, "--consistency-check-sample-size should be positive")
+	}
+
 	if cli.Test.DisablePushdown && cli.Test.EnableNestedPushdown {
 		l.LogAttrs(
 			ctx,
@@ -325,6 +511,28 @@ func checkFlags(l *slog.Logger) {
 			"--test-disable-pushdown and --test-enable-nested-pushdown should not be set at the same time",
 		)
 	}
+
+	if cli.Test.DisablePushdown && cli.Test.EnableSortPushdown {
+		l.LogAttrs(
+			ctx,
+			logging.LevelFatal,
+			"--test-disable-pushdown and --test-enable-sort-pushdown should not be set at the same time",
+		)
+	}
+}
+
+// readSecretFile returns the content of the given file if it is set, and the flag value otherwise.
+func readSecretFile(ctx context.Context, l *slog.Logger, value, file string) string {
+	if file == "" {
+		return value
+	}
+
+	v, err := secret.ReadFile(file)
+	if err != nil {
+		l.LogAttrs(ctx, logging.LevelFatal, "Failed to read secret file", logging.Error(err))
+	}
+
+	return v
 }
 
 // dumpMetrics dumps all Prometheus metrics to stderr.
@@ -337,7 +545,9 @@ func dumpMetrics() {
 }
 
 // run sets up environment based on provided flags and runs FerretDB.
-func run() {
+//
+// The given sanitized configuration is included in the debug archive.
+func run(config map[string]string) {
 	// to increase a chance of resource finalizers to spot problems
 	if debugbuild.Enabled {
 		defer func() {
@@ -363,9 +573,6 @@ func run() {
 		return
 	}
 
-	// safe to always enable
-	runtime.SetBlockProfileRate(10000)
-
 	stateProvider := setupState()
 
 	metricsRegisterer := setupMetrics(stateProvider)
@@ -401,6 +608,9 @@ func run() {
 
 	checkFlags(logger)
 
+	runtime.SetBlockProfileRate(cli.Profile.BlockRate)
+	runtime.SetMutexProfileFraction(cli.Profile.MutexFraction)
+
 	if _, err := maxprocs.Set(maxprocs.Logger(func(format string, a ...any) {
 		logger.Debug(fmt.Sprintf(format, a...))
 	})); err != nil {
@@ -409,6 +619,10 @@ func run() {
 
 	ctx, stop := ctxutil.SigTerm(context.Background())
 
+	// canceled by `shutdown` command
+	ctx, shutdown := context.WithCancel(ctx)
+	defer shutdown()
+
 	go func() {
 		<-ctx.Done()
 		logger.Info("Stopping...")
@@ -417,8 +631,9 @@ func run() {
 		stop()
 	}()
 
-	// used to start debug handler with probes as soon as possible, even before listener is created
+	// used to start debug handler with probes as soon as possible, even before listener and handler are created
 	var listener atomic.Pointer[clientconn.Listener]
+	var warmupHandler atomic.Pointer[handler.Handler]
 
 	var wg sync.WaitGroup
 
@@ -433,6 +648,13 @@ func run() {
 				l: l,
 			}
 
+			if cli.Warmup.Timeout > 0 {
+				ready.warmedUp = func() bool {
+					h := warmupHandler.Load()
+					return h != nil && h.WarmedUp()
+				}
+			}
+
 			h, err := debug.Listen(&debug.ListenOpts{
 				TCPAddr: addr,
 				L:       l,
@@ -445,6 +667,7 @@ func run() {
 					return listener.Load().Listening()
 				},
 				Readyz: ready.Probe,
+				Config: config,
 			})
 			if err != nil {
 				l.LogAttrs(ctx, logging.LevelFatal, "Failed to create debug handler", logging.Error(err))
@@ -476,6 +699,29 @@ func run() {
 		}()
 	}
 
+	if u := cli.Profile.PushURL; u != "" {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			l := logging.WithName(logger, "pprof")
+
+			pp, err := observability.NewProfilePusher(&observability.ProfilePusherOpts{
+				Logger:   l,
+				Service:  "ferretdb",
+				Version:  version.Get().Version,
+				URL:      u,
+				Interval: cli.Profile.PushInterval,
+			})
+			if err != nil {
+				l.LogAttrs(ctx, logging.LevelFatal, "Failed to create profile pusher", logging.Error(err))
+			}
+
+			pp.Run(ctx)
+		}()
+	}
+
 	metrics := connmetrics.NewListenerMetrics()
 
 	wg.Add(1)
@@ -486,6 +732,7 @@ func run() {
 		l := logging.WithName(logger, "telemetry")
 		opts := &telemetry.NewReporterOpts{
 			URL:            cli.Test.Telemetry.URL,
+			Proxy:          cli.TelemetryProxy,
 			F:              &cli.Telemetry,
 			DNT:            os.Getenv("DO_NOT_TRACK"),
 			ExecName:       os.Args[0],
@@ -502,37 +749,113 @@ func run() {
 			l.LogAttrs(ctx, logging.LevelFatal, "Failed to create telemetry reporter", logging.Error(err))
 		}
 
+		metricsRegisterer.MustRegister(r)
+
 		r.Run(ctx)
 	}()
 
+	setupPassword := readSecretFile(ctx, logger, cli.Setup.Password, cli.Setup.PasswordFile)
+	mySQLURL := readSecretFile(ctx, logger, mySQLFlags.MySQLURL, mySQLFlags.MySQLURLFile)
+	hanaURL := readSecretFile(ctx, logger, hanaFlags.HANAURL, hanaFlags.HANAURLFile)
+
+	var postgreSQLURLFile *secret.File
+
+	if f := postgreSQLFlags.PostgreSQLURLFile; f != "" {
+		var err error
+		if postgreSQLURLFile, err = secret.NewFile(f, logging.WithName(logger, "secret")); err != nil {
+			logger.LogAttrs(ctx, logging.LevelFatal, "Failed to read secret file", logging.Error(err))
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			postgreSQLURLFile.Run(ctx, cli.SecretsReloadInterval)
+		}()
+	}
+
 	h, closeBackend, err := registry.NewHandler(cli.Handler, &registry.NewHandlerOpts{
 		Logger:        logger,
 		ConnMetrics:   metrics.ConnMetrics,
 		StateProvider: stateProvider,
 		TCPHost:       cli.Listen.Addr,
 		ReplSetName:   cli.ReplSetName,
+		ExportURL:     cli.ExportURL,
+
+		BackupURL:                  cli.Backup.URL,
+		BackupServerSideEncryption: cli.Backup.SSE,
+		BackupKMSKeyID:             cli.Backup.KMSKeyID,
+
+		SessionTimeout:         cli.Session.Timeout,
+		SessionCleanupInterval: cli.Session.CleanupInterval,
 
 		SetupDatabase: cli.Setup.Database,
 		SetupUsername: cli.Setup.Username,
-		SetupPassword: password.WrapPassword(cli.Setup.Password),
+		SetupPassword: password.WrapPassword(setupPassword),
 		SetupTimeout:  cli.Setup.Timeout,
 
-		PostgreSQLURL: postgreSQLFlags.PostgreSQLURL,
+		SCRAMIterationCount:       cli.SCRAM.IterationCount,
+		SCRAMSHA256IterationCount: cli.SCRAM.SHA256IterationCount,
+		PasswordMinLength:         cli.Password.MinLength,
+		PasswordRequireComplexity: cli.Password.RequireComplexity,
+
+		AuthFailureDelay:     cli.Auth.FailureDelay,
+		AuthFailureMaxDelay:  cli.Auth.FailureMaxDelay,
+		AuthLockoutThreshold: cli.Auth.LockoutThreshold,
+		AuthLockoutDuration:  cli.Auth.LockoutDuration,
+
+		DisableAutoMigrate: !cli.AutoMigrate,
+		ReadOnly:           cli.ReadOnly,
+
+		QuotaMaxCollections: cli.Quota.MaxCollections,
+		QuotaMaxDocuments:   cli.Quota.MaxDocuments,
+		QuotaMaxSize:        cli.Quota.MaxSize,
+
+		MaxBsonObjectSizeBytes: cli.MaxDocumentSize,
+		ResultCacheSize:        cli.ResultCacheSize,
+		CursorReadaheadSize:    cli.CursorReadaheadSize,
+		OperationMemoryLimit:   cli.OperationMemoryLimit,
+		SlowQueryThreshold:     cli.Log.SlowThreshold,
+		SlowQueryExplain:       cli.Log.SlowExplain,
+
+		BackendTimeout:    cli.Backend.Timeout,
+		BackendHedgeReads: cli.Backend.HedgeReads,
+
+		ConsistencyCheckInterval:   cli.ConsistencyCheck.Interval,
+		ConsistencyCheckSampleSize: cli.ConsistencyCheck.SampleSize,
+
+		TenantPrefixes: cli.TenantPrefixes,
+
+		WarmupTimeout:     cli.Warmup.Timeout,
+		WarmupConnections: cli.Warmup.Connections,
+
+		Shutdown: shutdown,
+
+		PostgreSQLURL:     postgreSQLFlags.PostgreSQLURL,
+		PostgreSQLURLFile: postgreSQLURLFile,
+
+		PostgreSQLTLSCAFile:     postgreSQLFlags.PostgreSQLTLSCAFile,
+		PostgreSQLTLSCertFile:   postgreSQLFlags.PostgreSQLTLSCertFile,
+		PostgreSQLTLSKeyFile:    postgreSQLFlags.PostgreSQLTLSKeyFile,
+		PostgreSQLTLSVerifyFull: postgreSQLFlags.PostgreSQLTLSVerifyFull,
 
 		SQLiteURL: sqliteFlags.SQLiteURL,
 
-		HANAURL: hanaFlags.HANAURL,
+		HANAURL: hanaURL,
 
-		MySQLURL: mySQLFlags.MySQLURL,
+		MySQLURL: mySQLURL,
 
 		TestOpts: registry.TestOpts{
 			DisablePushdown:         cli.Test.DisablePushdown,
 			EnableNestedPushdown:    cli.Test.EnableNestedPushdown,
+			EnableSortPushdown:      cli.Test.EnableSortPushdown,
+			EnableExplainSQL:        cli.Test.EnableExplainSQL,
 			CappedCleanupInterval:   cli.Test.CappedCleanup.Interval,
 			CappedCleanupPercentage: cli.Test.CappedCleanup.Percentage,
 			EnableNewAuth:           cli.Test.EnableNewAuth,
 			BatchSize:               cli.Test.BatchSize,
-			MaxBsonObjectSizeBytes:  cli.Test.MaxBsonObjectSizeMiB * 1024 * 1024,
+			RandSeed:                cli.Test.RandSeed,
 		},
 	})
 	if err != nil {
@@ -541,6 +864,19 @@ func run() {
 
 	defer closeBackend()
 
+	warmupHandler.Store(h)
+
+	chaos := &clientconn.ChaosOpts{
+		DelayProbability:    cli.Test.Chaos.DelayProbability,
+		MaxDelay:            cli.Test.Chaos.MaxDelay,
+		TruncateProbability: cli.Test.Chaos.TruncateProbability,
+		DropProbability:     cli.Test.Chaos.DropProbability,
+	}
+
+	if err = chaos.Validate(); err != nil {
+		logger.LogAttrs(ctx, logging.LevelFatal, "Invalid chaos injection flags", logging.Error(err))
+	}
+
 	l, err := clientconn.Listen(&clientconn.NewListenerOpts{
 		TCP:  cli.Listen.Addr,
 		Unix: cli.Listen.Unix,
@@ -550,6 +886,12 @@ func run() {
 		TLSKeyFile:  cli.Listen.TLSKeyFile,
 		TLSCAFile:   cli.Listen.TLSCaFile,
 
+		TLSMinVersion:   cli.Listen.TLSMinVersion,
+		TLSCipherSuites: cli.Listen.TLSCipherSuites,
+		TLSOCSPStapling: cli.Listen.TLSOCSPStapling,
+
+		ProxyProtocol: cli.Listen.ProxyProtocol,
+
 		ProxyAddr:        cli.Proxy.Addr,
 		ProxyTLSCertFile: cli.Proxy.TLSCertFile,
 		ProxyTLSKeyFile:  cli.Proxy.TLSKeyFile,
@@ -560,6 +902,7 @@ func run() {
 		Handler:        h,
 		Logger:         logger,
 		TestRecordsDir: cli.Test.RecordsDir,
+		Chaos:          chaos,
 	})
 	if err != nil {
 		logger.LogAttrs(ctx, logging.LevelFatal, "Failed to construct listener", logging.Error(err))
@@ -31,15 +31,24 @@ import (
 // command against the FerretDB instance specified by cli flags.
 type ReadyZ struct {
 	l *slog.Logger
+
+	// if set, it should return true when the startup warm-up is finished
+	warmedUp func() bool
 }
 
 // Probe executes ping queries to open listeners, and returns true if they succeed.
 // Any errors that occure are passed through ReadyZ.l listener.
 //
-// It is only executed if --setup-database flag is set.
+// If startup warm-up is enabled, it fails until the warm-up is finished.
+// Ping is only executed if --setup-database flag is set.
 func (ready *ReadyZ) Probe(ctx context.Context) bool {
 	l := ready.l
 
+	if ready.warmedUp != nil && !ready.warmedUp() {
+		l.InfoContext(ctx, "Warm-up is not finished yet")
+		return false
+	}
+
 	if cli.Setup.Database == "" {
 		l.InfoContext(ctx, "Setup database not specified - skipping ping")
 		return true
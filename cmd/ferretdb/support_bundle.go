@@ -0,0 +1,142 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// supportBundleParams represents `support-bundle` command parameters.
+type supportBundleParams struct {
+	Output  string        `default:""   help:"Output file path; archive name suggested by FerretDB in the current directory if empty."`
+	Timeout time.Duration `default:"1m" help:"Archive download timeout."`
+}
+
+// redacted replaces secret flag values.
+const redacted = "REDACTED"
+
+// sanitizedConfig returns values of all parsed flags with secrets redacted.
+func sanitizedConfig(kctx *kong.Context) map[string]string {
+	res := map[string]string{}
+
+	for _, f := range kctx.Flags() {
+		switch f.Name {
+		case "help", "version":
+			continue
+		}
+
+		res[f.Name] = sanitizeFlagValue(f.Name, fmt.Sprint(kctx.FlagValue(f)))
+	}
+
+	return res
+}
+
+// sanitizeFlagValue returns the flag value with secrets removed.
+//
+// Values of password and key flags are replaced completely;
+// passwords are removed from URLs.
+// Paths to files with secrets are kept as is.
+func sanitizeFlagValue(name, value string) string {
+	switch {
+	case value == "", strings.HasSuffix(name, "-file"):
+		return value
+	case strings.HasSuffix(name, "password"), strings.HasSuffix(name, "-key"), strings.Contains(value, "password="):
+		return redacted
+	}
+
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+
+	return value
+}
+
+// supportBundle downloads the debug archive from FerretDB instance
+// with the debug handler listening on the given address.
+//
+// It returns the path of the saved file.
+func supportBundle(ctx context.Context, params *supportBundleParams, debugAddr string, l *slog.Logger) (string, error) {
+	host, port, err := net.SplitHostPort(debugAddr)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, params.Timeout)
+	defer cancel()
+
+	u := fmt.Sprintf("http://%s/debug/archive", net.JoinHostPort(host, port))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	l.InfoContext(ctx, "Downloading support bundle...", slog.String("url", u))
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	defer res.Body.Close() //nolint:errcheck // we are only reading it
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: unexpected status %d", u, res.StatusCode)
+	}
+
+	path := params.Output
+	if path == "" {
+		path = fmt.Sprintf("ferretdb-%s.zip", time.Now().Format("2006-01-02-15-04-05"))
+
+		if _, p, err := mime.ParseMediaType(res.Header.Get("Content-Disposition")); err == nil && p["filename"] != "" {
+			path = filepath.Base(p["filename"])
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	if _, err = io.Copy(f, res.Body); err != nil {
+		_ = f.Close()
+		return "", lazyerrors.Error(err)
+	}
+
+	if err = f.Close(); err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	return path, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/clientconn"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/handler"
 	"github.com/FerretDB/FerretDB/internal/handler/registry"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
@@ -52,8 +53,25 @@ type Config struct {
 	// SQLite URI (directory) for `sqlite` handler.
 	// See https://www.sqlite.org/uri.html.
 	SQLiteURL string // For example: `file:data/`.
+
+	// Interceptors are called in order for each command before built-in handling.
+	// They could be used for auditing, rate limiting, request rewriting, etc.
+	Interceptors []Interceptor
 }
 
+// Interceptor is a part of the command handling chain.
+//
+// It may inspect or rewrite the request before calling next,
+// return a response or an error without calling next at all,
+// or inspect and replace the response returned by next.
+type Interceptor = handler.Interceptor
+
+// CommandInfo describes the command passed to [Interceptor].
+type CommandInfo = handler.CommandInfo
+
+// CommandFunc handles a single command; it is passed to [Interceptor] as the next part of the chain.
+type CommandFunc = handler.CommandFunc
+
 // ListenerConfig represents listener configuration.
 type ListenerConfig struct {
 	// Listen TCP address.
@@ -141,6 +159,8 @@ func New(config *Config) (*FerretDB, error) {
 		return nil, fmt.Errorf("failed to construct handler: %s", err)
 	}
 
+	h.Use(config.Interceptors...)
+
 	l, err := clientconn.Listen(&clientconn.NewListenerOpts{
 		TCP:  config.Listener.TCP,
 		Unix: config.Listener.Unix,
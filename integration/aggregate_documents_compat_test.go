@@ -27,6 +27,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
@@ -41,6 +42,8 @@ type aggregateStagesCompatTestCase struct {
 	resultType     compatTestCaseResultType // defaults to nonEmptyResult
 	resultPushdown resultPushdown           // defaults to noPushdown
 	skip           string                   // always skip this test case, must have issue number mentioned
+
+	failsForFerretDB string // expected to fail for FerretDB, must be an issue URL
 }
 
 // testAggregateStagesCompat tests aggregation stages compatibility test cases with all providers.
@@ -100,8 +103,10 @@ func testAggregateStagesCompatWithProviders(t *testing.T, providers shareddata.P
 			for i := range targetCollections {
 				targetCollection := targetCollections[i]
 				compatCollection := compatCollections[i]
-				t.Run(targetCollection.Name(), func(t *testing.T) {
-					t.Helper()
+				t.Run(targetCollection.Name(), func(tt *testing.T) {
+					tt.Helper()
+
+					t := failsForFerretDBCompat(tt, tc.failsForFerretDB)
 
 					targetCursor, targetErr := targetCollection.Aggregate(ctx, pipeline, opts)
 					compatCursor, compatErr := compatCollection.Aggregate(ctx, pipeline, opts)
@@ -155,6 +160,11 @@ func testAggregateStagesCompatWithProviders(t *testing.T, providers shareddata.P
 				})
 			}
 
+			// results of test cases that are expected to fail are not checked
+			if tc.failsForFerretDB != "" {
+				return
+			}
+
 			switch tc.resultType {
 			case nonEmptyResult:
 				assert.True(t, nonEmptyResults, "expected non-empty results")
@@ -889,6 +899,21 @@ func TestAggregateCompatGroupCount(t *testing.T) {
 				{"count", bson.D{{"$count", bson.D{}}}},
 			}}}},
 		},
+		"SumOne": {
+			pipeline: bson.A{bson.D{{"$group", bson.D{
+				{"_id", "$v"},
+				{"count", bson.D{{"$sum", int32(1)}}},
+			}}}},
+		},
+		"MatchSumOne": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "string"}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", "$v"},
+					{"count", bson.D{{"$sum", int32(1)}}},
+				}}},
+			},
+		},
 		"Duplicate": {
 			pipeline: bson.A{bson.D{{"$group", bson.D{
 				{"_id", "$v"},
@@ -1243,6 +1268,49 @@ func TestAggregateCompatGroupSum(t *testing.T) {
 	testAggregateStagesCompatWithProviders(t, providers, testCases)
 }
 
+func TestAggregateCompatGroupSumDecimal128(t *testing.T) {
+	t.Parallel()
+
+	providers := shareddata.Providers{
+		shareddata.Decimal128s,
+		shareddata.Int32s,
+		shareddata.Int64s,
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"GroupNullID": {
+			pipeline: bson.A{
+				// decimal addition rounds, so the order of values should be the same
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"sum", bson.D{{"$sum", "$v"}}},
+				}}},
+			},
+		},
+		"GroupByValue": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+				bson.D{{"$group", bson.D{
+					{"_id", "$v"},
+					{"sum", bson.D{{"$sum", "$v"}}},
+				}}},
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+			},
+		},
+		"Decimal128Literal": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{
+					{"_id", nil},
+					{"sum", bson.D{{"$sum", must.NotFail(primitive.ParseDecimal128("0.10"))}}},
+				}}},
+			},
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
+
 func TestAggregateCompatMatch(t *testing.T) {
 	t.Parallel()
 
@@ -1379,6 +1447,27 @@ func TestAggregateCompatSort(t *testing.T) {
 	testAggregateStagesCompat(t, testCases)
 }
 
+func TestAggregateCompatSortDecimal128(t *testing.T) {
+	t.Parallel()
+
+	providers := shareddata.Providers{
+		shareddata.Decimal128s,
+		shareddata.Int64s,
+		shareddata.Doubles,
+	}
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"Ascending": {
+			pipeline: bson.A{bson.D{{"$sort", bson.D{{"v", 1}, {"_id", 1}}}}},
+		},
+		"Descending": {
+			pipeline: bson.A{bson.D{{"$sort", bson.D{{"v", -1}, {"_id", 1}}}}},
+		},
+	}
+
+	testAggregateStagesCompatWithProviders(t, providers, testCases)
+}
+
 func TestAggregateCompatSortDotNotation(t *testing.T) {
 	t.Parallel()
 
@@ -1918,6 +2007,24 @@ func TestAggregateCompatProject(t *testing.T) {
 				bson.D{{"$project", bson.D{{"type", bson.D{{"$type", true}}}}}},
 			},
 		},
+		"TypeLiteral": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$project", bson.D{{"type", bson.D{{"$type", bson.D{{"$literal", "$v"}}}}}}}},
+			},
+		},
+		"IsNumber": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$project", bson.D{{"isNumber", bson.D{{"$isNumber", "$v"}}}}}},
+			},
+		},
+		"IsNumberNonExistent": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$project", bson.D{{"isNumber", bson.D{{"$isNumber", "$foo"}}}}}},
+			},
+		},
 		"ProjectManyOperators": {
 			pipeline: bson.A{
 				bson.D{{"$sort", bson.D{{"_id", -1}}}},
@@ -1925,6 +2032,24 @@ func TestAggregateCompatProject(t *testing.T) {
 			},
 			resultType: emptyResult,
 		},
+		"Literal": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$project", bson.D{{"literal", bson.D{{"$literal", "$v"}}}}}},
+			},
+		},
+		"FieldPath": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$project", bson.D{{"foo", "$v"}}}},
+			},
+		},
+		"Remove": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$project", bson.D{{"_id", "$$REMOVE"}, {"v", "$$REMOVE"}, {"foo", "bar"}}}},
+			},
+		},
 	}
 
 	testAggregateStagesCompat(t, testCases)
@@ -2082,6 +2207,528 @@ func TestAggregateCompatProjectSum(t *testing.T) {
 	testAggregateStagesCompat(t, testCases)
 }
 
+func TestAggregateCompatProjectConvert(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"ToInt": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", "int"},
+						{"onError", "error"},
+						{"onNull", "null"},
+					}}}},
+				}}},
+			},
+		},
+		"ToLong": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", int32(18)},
+						{"onError", "error"},
+						{"onNull", "null"},
+					}}}},
+				}}},
+			},
+		},
+		"ToDouble": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", "double"},
+						{"onError", "error"},
+					}}}},
+				}}},
+			},
+		},
+		"ToObjectID": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", "objectId"},
+						{"onError", "error"},
+					}}}},
+				}}},
+			},
+		},
+		"ToString": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", bson.A{"int", "long", "string", "bool", "objectId"}}}}}}},
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", "string"},
+						{"onError", "error"},
+					}}}},
+				}}},
+			},
+		},
+		"ToIntMissing": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$toInt", "$foo"}}}}}},
+			},
+		},
+		"ToTypeExpression": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{
+						{"input", "$v"},
+						{"to", bson.D{{"$literal", "long"}}},
+						{"onError", "error"},
+					}}}},
+				}}},
+			},
+		},
+		"ToNull": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", nil}}}}},
+				}}},
+			},
+		},
+		"UnknownType": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "foo"}}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"InvalidTypeNumber": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", int32(42)}}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"MissingTo": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{{"input", "$v"}}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"UnknownArgument": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{
+					{"v", bson.D{{"$convert", bson.D{{"input", "$v"}, {"to", "int"}, {"foo", "bar"}}}}},
+				}}},
+			},
+			resultType: emptyResult,
+		},
+		"ToIntTooManyArgs": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$toInt", bson.A{"$v", "$v"}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
+func TestAggregateCompatProjectStringOperators(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"Split": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$split", bson.A{"$v", "o"}}}}}}},
+			},
+		},
+		"Trim": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$trim", bson.D{{"input", "$v"}}}}}}}},
+			},
+		},
+		"LTrimChars": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$ltrim", bson.D{{"input", "$v"}, {"chars", "fz"}}}}}}}},
+			},
+		},
+		"RTrimChars": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$rtrim", bson.D{{"input", "$v"}, {"chars", "o"}}}}}}}},
+			},
+		},
+		"ReplaceOne": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$replaceOne", bson.D{{"input", "$v"}, {"find", "o"}, {"replacement", "0"}}}}}}}},
+			},
+		},
+		"ReplaceAll": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$replaceAll", bson.D{{"input", "$v"}, {"find", "o"}, {"replacement", "0"}}}}}}}},
+			},
+		},
+		"RegexMatch": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "^F"}, {"options", "i"}}}}}}}},
+			},
+		},
+		"RegexFind": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$regexFind", bson.D{{"input", "$v"}, {"regex", "(o)(x)?"}}}}}}}},
+			},
+		},
+		"RegexFindAll": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$regexFindAll", bson.D{{"input", "$v"}, {"regex", "o+"}}}}}}}},
+			},
+		},
+		"SplitMissing": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$split", bson.A{"$foo", "o"}}}}}}},
+			},
+		},
+		"SplitEmptySeparator": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$split", bson.A{"$v", ""}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"TrimMissingInput": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$trim", bson.D{{"chars", "o"}}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"ReplaceAllMissingFind": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$replaceAll", bson.D{{"input", "$v"}, {"replacement", "0"}}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"RegexMatchUnknownArgument": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$regexMatch", bson.D{{"input", "$v"}, {"regex", "o"}, {"foo", "bar"}}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
+func TestAggregateCompatProjectDateOperators(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"DateToString": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "date"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$dateToString", bson.D{{"date", "$v"}}}}}}}},
+			},
+		},
+		"DateToStringFormat": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "date"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$dateToString", bson.D{
+					{"date", "$v"},
+					{"format", "%G-W%V-%u %j %U %w %b %B %z %Z %%"},
+					{"timezone", "Europe/Berlin"},
+				}}}}}}},
+			},
+		},
+		"DateToStringOnNull": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$dateToString", bson.D{{"date", "$foo"}, {"onNull", "none"}}}}}}}},
+			},
+		},
+		"DateToStringUnmatchedPercent": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$dateToString", bson.D{{"date", "$v"}, {"format", "%Y%"}}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"DateFromStringRoundTrip": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "date"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$dateFromString", bson.D{
+					{"dateString", bson.D{{"$dateToString", bson.D{{"date", "$v"}, {"format", "%d.%m.%Y %H:%M:%S.%L"}}}}},
+					{"format", "%d.%m.%Y %H:%M:%S.%L"},
+				}}}}}}},
+			},
+		},
+		"DateFromStringOnError": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$dateFromString", bson.D{{"dateString", "$v"}, {"onError", "error"}}}}}}}},
+			},
+		},
+		"DateFromStringMissingDateString": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$dateFromString", bson.D{{"format", "%Y"}}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
+func TestAggregateCompatProjectObjectOperators(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"ObjectToArray": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "object"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$objectToArray", "$v"}}}}}},
+			},
+		},
+		"ObjectToArrayRoot": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$objectToArray", "$$ROOT"}}}}}},
+			},
+		},
+		"ArrayToObjectRoundTrip": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "object"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$arrayToObject", bson.D{{"$objectToArray", "$v"}}}}}}}},
+			},
+		},
+		"ArrayToObjectPairs": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$arrayToObject", bson.A{bson.A{
+					bson.A{"a", int32(1)},
+					bson.A{"b", "$_id"},
+					bson.A{"a", int32(3)},
+				}}}}}}}},
+			},
+		},
+		"ArrayToObjectInvalidKey": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{int32(1), int32(2)}}}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"MergeObjects": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "object"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$mergeObjects", bson.A{bson.D{{"foo", "bar"}}, "$v", "$missing"}}}}}}},
+			},
+		},
+		"MergeObjectsNotDocument": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$mergeObjects", bson.A{"$v"}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
+func TestAggregateCompatProjectSetOperators(t *testing.T) {
+	t.Parallel()
+
+	// the order of $setUnion and $setIntersection results is unspecified,
+	// so only results with a single element are compared
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"SetIntersection": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$setIntersection", bson.A{"$v", bson.A{int32(42)}}}}}}}},
+			},
+		},
+		"SetDifference": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$setDifference", bson.A{"$v", bson.A{int32(42), "foo"}}}}}}}},
+			},
+		},
+		"SetIsSubset": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$setIsSubset", bson.A{bson.A{int32(42)}, "$v"}}}}}}},
+			},
+		},
+		"AnyElementTrue": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$anyElementTrue", bson.A{"$v"}}}}}}},
+			},
+		},
+		"AllElementsTrue": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$allElementsTrue", bson.A{"$v"}}}}}}},
+			},
+		},
+		"SetUnionNotArray": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$setUnion", bson.A{"$v"}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"SetDifferenceArgs": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$setDifference", bson.A{"$v"}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
+func TestAggregateCompatProjectMathOperators(t *testing.T) {
+	t.Parallel()
+
+	// only operators with exact results are compared;
+	// results of trigonometric and logarithmic functions may differ in the last digit
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"Abs": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", bson.A{"int", "double"}}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$abs", "$v"}}}}}},
+			},
+		},
+		"Ceil": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "number"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$ceil", "$v"}}}}}},
+			},
+		},
+		"Floor": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "number"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$floor", "$v"}}}}}},
+			},
+		},
+		"Round": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "number"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$round", bson.A{"$v", int32(1)}}}}}}},
+			},
+		},
+		"RoundNegativePlace": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", bson.A{"int", "long"}}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$round", bson.A{"$v", int32(-1)}}}}}}},
+			},
+		},
+		"Trunc": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "number"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$trunc", bson.A{"$v", int32(2)}}}}}}},
+			},
+		},
+		"Pow": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "int"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$pow", bson.A{"$v", int32(2)}}}}}}},
+			},
+		},
+		"SqrtNotNumber": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$sqrt", "$v"}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"LogArgs": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$log", bson.A{"$v"}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
+func TestAggregateCompatProjectArrayOperators(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]aggregateStagesCompatTestCase{
+		"ArrayElemAt": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$arrayElemAt", bson.A{"$v", int32(1)}}}}}}},
+			},
+		},
+		"ArrayElemAtNegative": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$arrayElemAt", bson.A{"$v", int32(-1)}}}}}}},
+			},
+		},
+		"ArrayElemAtNotArray": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "string"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$arrayElemAt", bson.A{"$v", int32(0)}}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"First": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$first", "$v"}}}}}},
+			},
+		},
+		"Last": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$last", "$v"}}}}}},
+			},
+		},
+		"Slice": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$slice", bson.A{"$v", int32(-2)}}}}}}},
+			},
+		},
+		"SlicePosition": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$slice", bson.A{"$v", int32(1), int32(2)}}}}}}},
+			},
+		},
+		"IndexOfArray": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$indexOfArray", bson.A{"$v", int32(42)}}}}}}},
+			},
+		},
+		"ConcatArrays": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "array"}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$concatArrays", bson.A{"$v", bson.A{"foo"}}}}}}}},
+			},
+		},
+		"Range": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", bson.D{{"$type", "int"}, {"$gte", -100}, {"$lte", 100}}}}}},
+				bson.D{{"$project", bson.D{{"v", bson.D{{"$range", bson.A{int32(0), "$v", int32(10)}}}}}}},
+			},
+		},
+	}
+
+	testAggregateStagesCompat(t, testCases)
+}
+
 func TestAggregateCompatAddFields(t *testing.T) {
 	t.Parallel()
 
@@ -2314,6 +2961,24 @@ func TestAggregateCompatAddFields(t *testing.T) {
 				}}},
 			},
 		},
+		"Literal": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$addFields", bson.D{{"literal", bson.D{{"$literal", "$v"}}}}}},
+			},
+		},
+		"FieldPath": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$addFields", bson.D{{"foo", "$v"}}}},
+			},
+		},
+		"Remove": {
+			pipeline: bson.A{
+				bson.D{{"$sort", bson.D{{"_id", -1}}}},
+				bson.D{{"$addFields", bson.D{{"v", "$$REMOVE"}}}},
+			},
+		},
 	}
 
 	testAggregateStagesCompat(t, testCases)
@@ -17,12 +17,14 @@ package integration
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -61,6 +63,26 @@ func TestAggregateAddFieldsErrors(t *testing.T) {
 				Message: "Invalid $addFields :: caused by :: FieldPath field names may not start with '$'. Consider using $getField or $setField.",
 			},
 		},
+		"EmptyFieldPath": {
+			pipeline: bson.A{
+				bson.D{{"$addFields", bson.D{{"v", "$"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    16872,
+				Name:    "Location16872",
+				Message: "Invalid $addFields :: caused by :: '$' by itself is not a valid FieldPath",
+			},
+		},
+		"UndefinedVariable": {
+			pipeline: bson.A{
+				bson.D{{"$addFields", bson.D{{"v", "$$foo"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Invalid $addFields :: caused by :: Use of undefined variable: foo",
+			},
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			if tc.skip != "" {
@@ -82,6 +104,70 @@ func TestAggregateAddFieldsErrors(t *testing.T) {
 	}
 }
 
+func TestAggregateAddFields(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Scalars)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		res []bson.D // required, expected response
+	}{
+		"Literal": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$addFields", bson.D{{"s", bson.D{{"$literal", "$v"}}}}}},
+			},
+			res: []bson.D{{{"_id", "int32"}, {"v", int32(42)}, {"s", "$v"}}},
+		},
+		"FieldPath": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$addFields", bson.D{{"foo", "$v"}, {"missing", "$foo.bar"}}}},
+			},
+			res: []bson.D{{{"_id", "int32"}, {"v", int32(42)}, {"foo", int32(42)}}},
+		},
+		"Remove": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$addFields", bson.D{{"v", "$$REMOVE"}, {"foo", "$$REMOVE"}}}},
+			},
+			res: []bson.D{{{"_id", "int32"}}},
+		},
+		"SetRemove": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$set", bson.D{{"v", "$$REMOVE"}}}},
+			},
+			res: []bson.D{{{"_id", "int32"}}},
+		},
+		"RemoveMissingField": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$addFields", bson.D{{"v", "$foo"}}}},
+			},
+			res: []bson.D{{{"_id", "int32"}}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.res, "res must not be nil")
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+			defer cursor.Close(ctx)
+
+			var res []bson.D
+			err = cursor.All(ctx, &res)
+			require.NoError(t, err)
+			require.Equal(t, tc.res, res)
+		})
+	}
+}
+
 func TestAggregateGroupErrors(t *testing.T) {
 	t.Parallel()
 
@@ -267,7 +353,6 @@ func TestAggregateGroupErrors(t *testing.T) {
 				Name:    "Location17276",
 				Message: "Use of undefined variable: s",
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/2275",
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
@@ -286,6 +371,98 @@ func TestAggregateGroupErrors(t *testing.T) {
 	}
 }
 
+func TestAggregateGroupCount(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "int"}, {"v", int32(1)}},
+		bson.D{{"_id", "long"}, {"v", int64(1)}},
+		bson.D{{"_id", "double"}, {"v", 1.0}},
+		bson.D{{"_id", "string"}, {"v", "1"}},
+		bson.D{{"_id", "null"}, {"v", nil}},
+		bson.D{{"_id", "missing"}},
+		bson.D{{"_id", "array"}, {"v", bson.A{int32(1)}}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A // required, aggregation pipeline stages
+
+		numbers  int32    // expected count of the group of numbers equal to 1, if any
+		expected []bson.D // expected other groups in any order
+	}{
+		"Sum": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{{"_id", "$v"}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+			},
+			numbers: 3,
+			expected: []bson.D{
+				{{"_id", "1"}, {"count", int32(1)}},
+				{{"_id", nil}, {"count", int32(2)}},
+				{{"_id", bson.A{int32(1)}}, {"count", int32(1)}},
+			},
+		},
+		"Count": {
+			pipeline: bson.A{
+				bson.D{{"$group", bson.D{{"_id", nil}, {"n", bson.D{{"$count", bson.D{}}}}}}},
+			},
+			expected: []bson.D{{{"_id", nil}, {"n", int32(7)}}},
+		},
+		"MatchID": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "long"}}}},
+				bson.D{{"$group", bson.D{{"_id", "$v"}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+			},
+			numbers: 1,
+		},
+		"MatchIDEmpty": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "none"}}}},
+				bson.D{{"$group", bson.D{{"_id", nil}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+			},
+		},
+		"MatchField": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"v", int32(1)}}}},
+				bson.D{{"$group", bson.D{{"_id", "$v"}, {"count", bson.D{{"$sum", int32(1)}}}}}},
+			},
+			numbers: 3,
+			expected: []bson.D{
+				{{"_id", bson.A{int32(1)}}, {"count", int32(1)}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+
+			// the type of the group ID of equal numbers depends on the order of documents
+			var numbers int32
+			var others []bson.D
+
+			for _, doc := range res {
+				switch doc.Map()["_id"].(type) {
+				case int32, int64, float64:
+					numbers += doc.Map()["count"].(int32)
+				default:
+					others = append(others, doc)
+				}
+			}
+
+			assert.Equal(t, tc.numbers, numbers)
+			assert.ElementsMatch(t, tc.expected, others)
+		})
+	}
+}
+
 func TestAggregateProjectErrors(t *testing.T) {
 	t.Parallel()
 
@@ -582,68 +759,1482 @@ func TestAggregateProjectErrors(t *testing.T) {
 				}}},
 			},
 			err: &mongo.CommandError{
-				Code:    9,
-				Name:    "FailedToParse",
-				Message: "Invalid $project :: caused by :: '$' starts with an invalid character for a user variable name",
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Invalid $project :: caused by :: '$' starts with an invalid character for a user variable name",
+			},
+		},
+		"EmptyFieldPath": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", "$"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    16872,
+				Name:    "Location16872",
+				Message: "Invalid $project :: caused by :: '$' by itself is not a valid FieldPath",
+			},
+		},
+		"UndefinedVariable": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"v", "$$foo"}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    17276,
+				Name:    "Location17276",
+				Message: "Invalid $project :: caused by :: Use of undefined variable: foo",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, tc.pipeline)
+			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+		})
+	}
+}
+
+func TestAggregateProject(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Scalars)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		res  []bson.D // required, expected response
+		skip string   // optional, skip test with a specified reason
+	}{
+		"IDFalseValueTrue": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", true}}}},
+			},
+			res: []bson.D{{{"v", int32(42)}}},
+		},
+		"ValueTrueIDFalse": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$project", bson.D{{"v", true}, {"_id", false}}}},
+			},
+			res: []bson.D{{{"v", int32(42)}}},
+		},
+		"Literal": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$project", bson.D{
+					{"s", bson.D{{"$literal", "$v"}}},
+					{"n", bson.D{{"$literal", int32(1)}}},
+					{"a", bson.D{{"$literal", bson.A{int32(1), "$v"}}}},
+				}}},
+			},
+			res: []bson.D{{
+				{"_id", "int32"},
+				{"s", "$v"},
+				{"n", int32(1)},
+				{"a", bson.A{int32(1), "$v"}},
+			}},
+		},
+		"FieldPath": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$project", bson.D{{"_id", false}, {"foo", "$v"}, {"missing", "$foo"}}}},
+			},
+			res: []bson.D{{{"foo", int32(42)}}},
+		},
+		"Remove": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$project", bson.D{{"_id", "$$REMOVE"}, {"v", "$$REMOVE"}, {"foo", "bar"}}}},
+			},
+			res: []bson.D{{{"foo", "bar"}}},
+		},
+		"CurrentMissing": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
+				bson.D{{"$project", bson.D{{"v", true}, {"foo", "$$CURRENT.foo"}}}},
+			},
+			res: []bson.D{{{"_id", "int32"}, {"v", int32(42)}}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.skip != "" {
+				t.Skip(tc.skip)
+			}
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.res, "res must not be nil")
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+			defer cursor.Close(ctx)
+
+			var res []bson.D
+			err = cursor.All(ctx, &res)
+			require.NoError(t, err)
+			require.Equal(t, tc.res, res)
+		})
+	}
+}
+
+func TestAggregateProjectConvert(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	date := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	oid := primitive.NewObjectID()
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "convert"},
+		{"s", "42"},
+		{"foo", "foo"},
+		{"d", 42.9},
+		{"b", true},
+		{"date", date},
+		{"oid", oid.Hex()},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		expression bson.D // required, expression to project
+
+		res        any                 // expected value of projected field
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
+	}{
+		"StringToInt": {
+			expression: bson.D{{"$toInt", "$s"}},
+			res:        int32(42),
+		},
+		"DoubleToInt": {
+			expression: bson.D{{"$toInt", "$d"}},
+			res:        int32(42),
+		},
+		"StringToLong": {
+			expression: bson.D{{"$toLong", "$s"}},
+			res:        int64(42),
+		},
+		"BoolToDouble": {
+			expression: bson.D{{"$toDouble", "$b"}},
+			res:        float64(1),
+		},
+		"ZeroToBool": {
+			expression: bson.D{{"$toBool", int32(0)}},
+			res:        false,
+		},
+		"StringToBool": {
+			expression: bson.D{{"$toBool", "$s"}},
+			res:        true,
+		},
+		"IntToString": {
+			expression: bson.D{{"$toString", int32(42)}},
+			res:        "42",
+		},
+		"DateToString": {
+			expression: bson.D{{"$toString", "$date"}},
+			res:        "2024-01-02T03:04:05.678Z",
+		},
+		"DateToLong": {
+			expression: bson.D{{"$toLong", "$date"}},
+			res:        date.UnixMilli(),
+		},
+		"LongToDate": {
+			expression: bson.D{{"$toDate", date.UnixMilli()}},
+			res:        primitive.NewDateTimeFromTime(date),
+		},
+		"StringToDate": {
+			expression: bson.D{{"$toDate", "2024-01-02T03:04:05.678Z"}},
+			res:        primitive.NewDateTimeFromTime(date),
+		},
+		"StringToObjectID": {
+			expression: bson.D{{"$toObjectId", "$oid"}},
+			res:        oid,
+		},
+		"Nested": {
+			expression: bson.D{{"$toString", bson.D{{"$toInt", "$d"}}}},
+			res:        "42",
+		},
+		"Missing": {
+			expression: bson.D{{"$toInt", "$missing"}},
+			res:        nil,
+		},
+		"OnError": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$foo"}, {"to", "int"}, {"onError", int32(-1)}}}},
+			res:        int32(-1),
+		},
+		"OnErrorNotUsed": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$s"}, {"to", "int"}, {"onError", int32(-1)}}}},
+			res:        int32(42),
+		},
+		"OnNull": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$missing"}, {"to", "int"}, {"onNull", "$s"}}}},
+			res:        "42",
+		},
+		"ConversionFailure": {
+			expression: bson.D{{"$toInt", "$foo"}},
+			err: &mongo.CommandError{
+				Code: 241,
+				Name: "ConversionFailure",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"Failed to parse number 'foo' in $convert with no onError value: Did not consume whole string.",
+			},
+			altMessage: "Failed to parse number 'foo' in $convert with no onError value",
+		},
+		"UnsupportedConversion": {
+			expression: bson.D{{"$toInt", "$date"}},
+			err: &mongo.CommandError{
+				Code: 241,
+				Name: "ConversionFailure",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"Unsupported conversion from date to int in $convert with no onError value",
+			},
+			altMessage: "Unsupported conversion from date to int in $convert with no onError value",
+		},
+		"UnknownType": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$s"}, {"to", "foo"}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Invalid $project :: caused by :: Unknown type name: foo",
+			},
+		},
+		"MissingTo": {
+			expression: bson.D{{"$convert", bson.D{{"input", "$s"}}}},
+			err: &mongo.CommandError{
+				Code:    9,
+				Name:    "FailedToParse",
+				Message: "Invalid $project :: caused by :: Missing 'to' parameter to $convert",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Equal(t, []bson.D{{{"v", tc.res}}}, res)
+		})
+	}
+}
+
+func TestAggregateProjectStringOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "strings"},
+		{"s", " \t héllo wörld\u00a0"},
+		{"csv", "a,b,,c"},
+		{"v", int32(42)},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		expression bson.D // required, expression to project
+
+		res        any                 // expected value of projected field
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
+	}{
+		"Split": {
+			expression: bson.D{{"$split", bson.A{"$csv", ","}}},
+			res:        bson.A{"a", "b", "", "c"},
+		},
+		"SplitMissing": {
+			expression: bson.D{{"$split", bson.A{"$missing", ","}}},
+			res:        nil,
+		},
+		"Trim": {
+			expression: bson.D{{"$trim", bson.D{{"input", "$s"}}}},
+			res:        "héllo wörld",
+		},
+		"LTrim": {
+			expression: bson.D{{"$ltrim", bson.D{{"input", "$s"}}}},
+			res:        "héllo wörld\u00a0",
+		},
+		"RTrim": {
+			expression: bson.D{{"$rtrim", bson.D{{"input", "$s"}}}},
+			res:        " \t héllo wörld",
+		},
+		"TrimChars": {
+			expression: bson.D{{"$trim", bson.D{{"input", "éhé"}, {"chars", "é"}}}},
+			res:        "h",
+		},
+		"TrimNullChars": {
+			expression: bson.D{{"$trim", bson.D{{"input", "$s"}, {"chars", nil}}}},
+			res:        nil,
+		},
+		"ReplaceOne": {
+			expression: bson.D{{"$replaceOne", bson.D{{"input", "a,b,c"}, {"find", ","}, {"replacement", "-"}}}},
+			res:        "a-b,c",
+		},
+		"ReplaceAll": {
+			expression: bson.D{{"$replaceAll", bson.D{{"input", "$csv"}, {"find", ","}, {"replacement", "é"}}}},
+			res:        "aébééc",
+		},
+		"ReplaceAllNull": {
+			expression: bson.D{{"$replaceAll", bson.D{{"input", "$csv"}, {"find", "$missing"}, {"replacement", "-"}}}},
+			res:        nil,
+		},
+		"RegexMatch": {
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "$csv"}, {"regex", "B"}, {"options", "i"}}}},
+			res:        true,
+		},
+		"RegexMatchNull": {
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "$missing"}, {"regex", "B"}}}},
+			res:        false,
+		},
+		"RegexFind": {
+			expression: bson.D{{"$regexFind", bson.D{{"input", "$s"}, {"regex", "l+(o)?(x)?"}}}},
+			res:        bson.D{{"match", "llo"}, {"idx", int32(5)}, {"captures", bson.A{"o", nil}}},
+		},
+		"RegexFindNoMatch": {
+			expression: bson.D{{"$regexFind", bson.D{{"input", "$s"}, {"regex", primitive.Regex{Pattern: "^x"}}}}},
+			res:        nil,
+		},
+		"RegexFindAll": {
+			expression: bson.D{{"$regexFindAll", bson.D{{"input", "ö1b22"}, {"regex", "[0-9]+"}}}},
+			res: bson.A{
+				bson.D{{"match", "1"}, {"idx", int32(1)}, {"captures", bson.A{}}},
+				bson.D{{"match", "22"}, {"idx", int32(3)}, {"captures", bson.A{}}},
+			},
+		},
+		"SplitNotString": {
+			expression: bson.D{{"$split", bson.A{"$v", ","}}},
+			err: &mongo.CommandError{
+				Code: 40085,
+				Name: "Location40085",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$split requires an expression that evaluates to a string as a first argument, found: int",
+			},
+			altMessage: "$split requires an expression that evaluates to a string as a first argument, found: int",
+		},
+		"TrimNotString": {
+			expression: bson.D{{"$trim", bson.D{{"input", "$v"}}}},
+			err: &mongo.CommandError{
+				Code: 50699,
+				Name: "Location50699",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$trim requires its input to be a string, got 42 (of type int) instead.",
+			},
+			altMessage: "$trim requires its input to be a string, got 42 (of type int) instead.",
+		},
+		"RegexInvalidOption": {
+			expression: bson.D{{"$regexMatch", bson.D{{"input", "$csv"}, {"regex", "a"}, {"options", "z"}}}},
+			err: &mongo.CommandError{
+				Code:    51108,
+				Name:    "Location51108",
+				Message: "Invalid $project :: caused by :: $regexMatch invalid flag in regex options: z",
+			},
+			altMessage: "$regexMatch invalid flag in regex options: z",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Equal(t, []bson.D{{{"v", tc.res}}}, res)
+		})
+	}
+}
+
+func TestAggregateProjectDateOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "dates"},
+		{"date", primitive.NewDateTimeFromTime(time.Date(2024, 3, 5, 14, 7, 9, 123_000_000, time.UTC))},
+		{"s", "2024-03-05T14:07:09.123Z"},
+		{"local", "05/03/2024 09:07"},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		expression bson.D // required, expression to project
+
+		res        any                 // expected value of projected field
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
+	}{
+		"ToStringDefault": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}}}},
+			res:        "2024-03-05T14:07:09.123Z",
+		},
+		"ToStringTimezone": {
+			expression: bson.D{{"$dateToString", bson.D{
+				{"date", "$date"},
+				{"format", "%d/%m/%Y %H:%M"},
+				{"timezone", "America/New_York"},
+			}}},
+			res: "05/03/2024 09:07",
+		},
+		"ToStringWeeks": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"format", "%j %w %u %U %V %G"}}}},
+			res:        "065 3 2 09 10 2024",
+		},
+		"ToStringOffset": {
+			expression: bson.D{{"$dateToString", bson.D{
+				{"date", "$date"},
+				{"format", "%H:%M %z %Z %%"},
+				{"timezone", "+05:30"},
+			}}},
+			res: "19:37 +0530 +330 %",
+		},
+		"ToStringMonthNames": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"format", "%b %B"}}}},
+			res:        "Mar March",
+		},
+		"ToStringOnNull": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$missing"}, {"onNull", "none"}}}},
+			res:        "none",
+		},
+		"ToStringNullTimezone": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"timezone", nil}}}},
+			res:        nil,
+		},
+		"ToStringInvalidFormat": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"format", "%Q"}}}},
+			err: &mongo.CommandError{
+				Code:    18536,
+				Name:    "Location18536",
+				Message: "Invalid $project :: caused by :: Invalid format character '%Q' in format string",
+			},
+			altMessage: "Invalid format character '%Q' in format string",
+		},
+		"ToStringUnknownTimezone": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$date"}, {"timezone", "Mars/Base"}}}},
+			err: &mongo.CommandError{
+				Code:    40485,
+				Name:    "Location40485",
+				Message: "Invalid $project :: caused by :: unrecognized time zone identifier: \"Mars/Base\"",
+			},
+			altMessage: "unrecognized time zone identifier: \"Mars/Base\"",
+		},
+		"ToStringNotDate": {
+			expression: bson.D{{"$dateToString", bson.D{{"date", "$s"}}}},
+			err: &mongo.CommandError{
+				Code: 16006,
+				Name: "Location16006",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"can't convert from BSON type string to Date",
+			},
+			altMessage: "can't convert from BSON type string to Date",
+		},
+		"FromString": {
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "$s"}}}},
+			res:        primitive.NewDateTimeFromTime(time.Date(2024, 3, 5, 14, 7, 9, 123_000_000, time.UTC)),
+		},
+		"FromStringFormat": {
+			expression: bson.D{{"$dateFromString", bson.D{
+				{"dateString", "$local"},
+				{"format", "%d/%m/%Y %H:%M"},
+				{"timezone", "America/New_York"},
+			}}},
+			res: primitive.NewDateTimeFromTime(time.Date(2024, 3, 5, 14, 7, 0, 0, time.UTC)),
+		},
+		"FromStringISOWeek": {
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "2024-W10-2"}, {"format", "%G-W%V-%u"}}}},
+			res:        primitive.NewDateTimeFromTime(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)),
+		},
+		"FromStringOnError": {
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "not a date"}, {"onError", "bad"}}}},
+			res:        "bad",
+		},
+		"FromStringOnNull": {
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "$missing"}, {"onNull", "none"}}}},
+			res:        "none",
+		},
+		"FromStringInvalid": {
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "not a date"}}}},
+			err: &mongo.CommandError{
+				Code: 241,
+				Name: "ConversionFailure",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"Error parsing date string 'not a date'; 0: Unexpected character 'n'",
+			},
+			altMessage: "Error parsing date string 'not a date'",
+		},
+		"FromStringUnknownArgument": {
+			expression: bson.D{{"$dateFromString", bson.D{{"dateString", "$s"}, {"foo", 1}}}},
+			err: &mongo.CommandError{
+				Code:    40541,
+				Name:    "Location40541",
+				Message: "Invalid $project :: caused by :: Unrecognized argument to $dateFromString: foo",
+			},
+			altMessage: "Unrecognized argument to $dateFromString: foo",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Equal(t, []bson.D{{{"v", tc.res}}}, res)
+		})
+	}
+}
+
+func TestAggregateProjectObjectOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "objects"},
+		{"doc", bson.D{{"a", int32(1)}, {"b", "foo"}}},
+		{"kv", bson.A{bson.D{{"k", "x"}, {"v", true}}, bson.D{{"k", "y"}, {"v", nil}}}},
+		{"v", int32(42)},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		expression bson.D // required, expression to project
+
+		res        any                 // expected value of projected field
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
+	}{
+		"ObjectToArray": {
+			expression: bson.D{{"$objectToArray", "$doc"}},
+			res:        bson.A{bson.D{{"k", "a"}, {"v", int32(1)}}, bson.D{{"k", "b"}, {"v", "foo"}}},
+		},
+		"ObjectToArrayMissing": {
+			expression: bson.D{{"$objectToArray", "$missing"}},
+			res:        nil,
+		},
+		"ObjectToArrayNotDocument": {
+			expression: bson.D{{"$objectToArray", "$v"}},
+			err: &mongo.CommandError{
+				Code: 40390,
+				Name: "Location40390",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$objectToArray requires a document input, found: int",
+			},
+			altMessage: "$objectToArray requires a document input, found: int",
+		},
+		"ArrayToObjectPairs": {
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{"a", int32(1)}, bson.A{"b", int32(2)}, bson.A{"a", int32(3)}}}}},
+			res:        bson.D{{"a", int32(3)}, {"b", int32(2)}},
+		},
+		"ArrayToObjectKV": {
+			expression: bson.D{{"$arrayToObject", "$kv"}},
+			res:        bson.D{{"x", true}, {"y", nil}},
+		},
+		"ArrayToObjectEmpty": {
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{}}}},
+			res:        bson.D{},
+		},
+		"ArrayToObjectRoundTrip": {
+			expression: bson.D{{"$arrayToObject", bson.D{{"$objectToArray", "$doc"}}}},
+			res:        bson.D{{"a", int32(1)}, {"b", "foo"}},
+		},
+		"ArrayToObjectInvalidPair": {
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{bson.A{"a", int32(1), int32(2)}}}}},
+			err: &mongo.CommandError{
+				Code: 40397,
+				Name: "Location40397",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$arrayToObject requires an array of size 2 arrays,found array of size: 3",
+			},
+			altMessage: "$arrayToObject requires an array of size 2 arrays,found array of size: 3",
+		},
+		"ArrayToObjectMixed": {
+			expression: bson.D{{"$arrayToObject", bson.A{bson.A{bson.D{{"k", "a"}, {"v", int32(1)}}, bson.A{"b", int32(2)}}}}},
+			err: &mongo.CommandError{
+				Code: 40391,
+				Name: "Location40391",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$arrayToObject requires a consistent input format. " +
+					"Elements must all be arrays or all be objects. Object was detected, now found: array",
+			},
+			altMessage: "$arrayToObject requires a consistent input format. " +
+				"Elements must all be arrays or all be objects. Object was detected, now found: array",
+		},
+		"MergeObjects": {
+			expression: bson.D{{"$mergeObjects", bson.A{"$doc", "$missing", bson.D{{"b", "bar"}, {"c", int32(2)}}}}},
+			res:        bson.D{{"a", int32(1)}, {"b", "bar"}, {"c", int32(2)}},
+		},
+		"MergeObjectsEmpty": {
+			expression: bson.D{{"$mergeObjects", bson.A{}}},
+			res:        bson.D{},
+		},
+		"MergeObjectsNotDocument": {
+			expression: bson.D{{"$mergeObjects", bson.A{"$doc", "$v"}}},
+			err: &mongo.CommandError{
+				Code: 40400,
+				Name: "Location40400",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$mergeObjects requires object inputs, but input 42 is of type int",
+			},
+			altMessage: "$mergeObjects requires object inputs, but input 42 is of type int",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Equal(t, []bson.D{{{"v", tc.res}}}, res)
+		})
+	}
+}
+
+func TestAggregateProjectSetOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "sets"},
+		{"a", bson.A{int32(3), "foo", int32(1), int32(3), 1.0}},
+		{"b", bson.A{int64(1), "bar", int32(2)}},
+		{"flags", bson.A{true, int32(0), nil}},
+		{"v", int32(42)},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		expression bson.D // required, expression to project
+
+		res        any                 // expected value of projected field
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
+	}{
+		"SetUnion": {
+			expression: bson.D{{"$setUnion", bson.A{"$a", "$b"}}},
+			res:        bson.A{int32(1), int32(2), int32(3), "bar", "foo"},
+		},
+		"SetUnionMissing": {
+			expression: bson.D{{"$setUnion", bson.A{"$a", "$missing"}}},
+			res:        nil,
+		},
+		"SetUnionNotArray": {
+			expression: bson.D{{"$setUnion", bson.A{"$a", "$v"}}},
+			err: &mongo.CommandError{
+				Code: 17043,
+				Name: "Location17043",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"All operands of $setUnion must be arrays. One argument is of type: int",
+			},
+			altMessage: "All operands of $setUnion must be arrays. One argument is of type: int",
+		},
+		"SetIntersection": {
+			expression: bson.D{{"$setIntersection", bson.A{"$a", "$b"}}},
+			res:        bson.A{int32(1)},
+		},
+		"SetIntersectionEmpty": {
+			expression: bson.D{{"$setIntersection", bson.A{}}},
+			res:        bson.A{},
+		},
+		"SetDifference": {
+			expression: bson.D{{"$setDifference", bson.A{"$a", "$b"}}},
+			res:        bson.A{int32(3), "foo"},
+		},
+		"SetDifferenceNotArray": {
+			expression: bson.D{{"$setDifference", bson.A{"$a", "$v"}}},
+			err: &mongo.CommandError{
+				Code: 17049,
+				Name: "Location17049",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"both operands of $setDifference must be arrays. Second argument is of type: int",
+			},
+			altMessage: "both operands of $setDifference must be arrays. Second argument is of type: int",
+		},
+		"SetIsSubset": {
+			expression: bson.D{{"$setIsSubset", bson.A{bson.A{int32(1), "foo", 3.0}, "$a"}}},
+			res:        true,
+		},
+		"SetIsSubsetFalse": {
+			expression: bson.D{{"$setIsSubset", bson.A{"$a", "$b"}}},
+			res:        false,
+		},
+		"SetIsSubsetArgs": {
+			expression: bson.D{{"$setIsSubset", bson.A{"$a"}}},
+			err: &mongo.CommandError{
+				Code:    16020,
+				Name:    "Location16020",
+				Message: "Invalid $project :: caused by :: Expression $setIsSubset takes exactly 2 arguments. 1 were passed in.",
+			},
+			altMessage: "Expression $setIsSubset takes exactly 2 arguments. 1 were passed in.",
+		},
+		"AnyElementTrue": {
+			expression: bson.D{{"$anyElementTrue", bson.A{"$flags"}}},
+			res:        true,
+		},
+		"AllElementsTrue": {
+			expression: bson.D{{"$allElementsTrue", bson.A{"$flags"}}},
+			res:        false,
+		},
+		"AllElementsTrueEmpty": {
+			expression: bson.D{{"$allElementsTrue", bson.A{bson.A{}}}},
+			res:        true,
+		},
+		"AnyElementTrueNotArray": {
+			expression: bson.D{{"$anyElementTrue", "$v"}},
+			err: &mongo.CommandError{
+				Code: 17041,
+				Name: "Location17041",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$anyElementTrue's argument must be an array, but is int",
+			},
+			altMessage: "$anyElementTrue's argument must be an array, but is int",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Equal(t, []bson.D{{{"v", tc.res}}}, res)
+		})
+	}
+}
+
+func TestAggregateProjectMathOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "math"},
+		{"int", int32(-7)},
+		{"long", int64(math.MinInt64)},
+		{"double", 2.675},
+		{"s", "foo"},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		expression bson.D // required, expression to project
+
+		res        any                 // expected value of projected field
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
+	}{
+		"Abs": {
+			expression: bson.D{{"$abs", "$int"}},
+			res:        int32(7),
+		},
+		"AbsMinInt": {
+			expression: bson.D{{"$abs", int32(math.MinInt32)}},
+			res:        int64(-math.MinInt32),
+		},
+		"AbsMinLong": {
+			expression: bson.D{{"$abs", "$long"}},
+			err: &mongo.CommandError{
+				Code: 28680,
+				Name: "Location28680",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"can't take $abs of long long min",
+			},
+			altMessage: "can't take $abs of long long min",
+		},
+		"AbsNotNumber": {
+			expression: bson.D{{"$abs", "$s"}},
+			err: &mongo.CommandError{
+				Code: 28765,
+				Name: "Location28765",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$abs only supports numeric types, not string",
+			},
+			altMessage: "$abs only supports numeric types, not string",
+		},
+		"AbsMissing": {
+			expression: bson.D{{"$abs", "$missing"}},
+			res:        nil,
+		},
+		"Ceil": {
+			expression: bson.D{{"$ceil", "$double"}},
+			res:        3.0,
+		},
+		"FloorInt": {
+			expression: bson.D{{"$floor", "$int"}},
+			res:        int32(-7),
+		},
+		"Sqrt": {
+			expression: bson.D{{"$sqrt", int32(16)}},
+			res:        4.0,
+		},
+		"SqrtNegative": {
+			expression: bson.D{{"$sqrt", "$int"}},
+			err: &mongo.CommandError{
+				Code: 28714,
+				Name: "Location28714",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$sqrt's argument must be greater than or equal to 0",
+			},
+			altMessage: "$sqrt's argument must be greater than or equal to 0",
+		},
+		"Exp": {
+			expression: bson.D{{"$exp", int32(0)}},
+			res:        1.0,
+		},
+		"Ln": {
+			expression: bson.D{{"$ln", int32(1)}},
+			res:        0.0,
+		},
+		"LnNotPositive": {
+			expression: bson.D{{"$ln", "$int"}},
+			err: &mongo.CommandError{
+				Code: 28766,
+				Name: "Location28766",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$ln's argument must be a positive number, but is -7",
+			},
+			altMessage: "$ln's argument must be a positive number, but is -7",
+		},
+		"Log10": {
+			expression: bson.D{{"$log10", int64(1000)}},
+			res:        3.0,
+		},
+		"Log": {
+			expression: bson.D{{"$log", bson.A{int32(8), int32(2)}}},
+			res:        3.0,
+		},
+		"LogInvalidBase": {
+			expression: bson.D{{"$log", bson.A{int32(8), int32(1)}}},
+			err: &mongo.CommandError{
+				Code: 28759,
+				Name: "Location28759",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$log's base must be a positive number not equal to 1, but is 1",
+			},
+			altMessage: "$log's base must be a positive number not equal to 1, but is 1",
+		},
+		"PowInt": {
+			expression: bson.D{{"$pow", bson.A{"$int", int32(3)}}},
+			res:        int32(-343),
+		},
+		"PowLong": {
+			expression: bson.D{{"$pow", bson.A{int32(2), int32(40)}}},
+			res:        int64(1 << 40),
+		},
+		"PowOverflow": {
+			expression: bson.D{{"$pow", bson.A{int64(2), int32(64)}}},
+			res:        math.Pow(2, 64),
+		},
+		"PowNegativeExponent": {
+			expression: bson.D{{"$pow", bson.A{int32(2), int32(-1)}}},
+			res:        0.5,
+		},
+		"PowZeroNegativeExponent": {
+			expression: bson.D{{"$pow", bson.A{int32(0), int32(-1)}}},
+			err: &mongo.CommandError{
+				Code: 28764,
+				Name: "Location28764",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$pow cannot take a base of 0 and a negative exponent",
+			},
+			altMessage: "$pow cannot take a base of 0 and a negative exponent",
+		},
+		"Round": {
+			expression: bson.D{{"$round", bson.A{"$double", int32(2)}}},
+			res:        2.67,
+		},
+		"RoundHalfEven": {
+			expression: bson.D{{"$round", bson.A{2.5}}},
+			res:        2.0,
+		},
+		"RoundNegativePlace": {
+			expression: bson.D{{"$round", bson.A{int32(1250), int32(-2)}}},
+			res:        int32(1200),
+		},
+		"RoundPlaceOutOfRange": {
+			expression: bson.D{{"$round", bson.A{"$double", int32(101)}}},
+			err: &mongo.CommandError{
+				Code: 51083,
+				Name: "Location51083",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"cannot apply $round with precision value 101 value must be in [-20, 100]",
+			},
+			altMessage: "cannot apply $round with precision value 101 value must be in [-20, 100]",
+		},
+		"Trunc": {
+			expression: bson.D{{"$trunc", bson.A{"$double", int32(1)}}},
+			res:        2.6,
+		},
+		"TruncNegative": {
+			expression: bson.D{{"$trunc", bson.A{-2.675}}},
+			res:        -2.0,
+		},
+		"TruncLong": {
+			expression: bson.D{{"$trunc", bson.A{int64(-1299), int32(-2)}}},
+			res:        int64(-1200),
+		},
+		"Sin": {
+			expression: bson.D{{"$sin", int32(0)}},
+			res:        0.0,
+		},
+		"SinInfinity": {
+			expression: bson.D{{"$sin", math.Inf(1)}},
+			err: &mongo.CommandError{
+				Code: 50989,
+				Name: "Location50989",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"cannot apply $sin to inf, value must in (-inf,inf)",
+			},
+			altMessage: "cannot apply $sin to inf, value must in (-inf,inf)",
+		},
+		"Acos": {
+			expression: bson.D{{"$acos", int32(1)}},
+			res:        0.0,
+		},
+		"AcosOutOfRange": {
+			expression: bson.D{{"$acos", int32(2)}},
+			err: &mongo.CommandError{
+				Code: 50989,
+				Name: "Location50989",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"cannot apply $acos to 2, value must in [-1,1]",
+			},
+			altMessage: "cannot apply $acos to 2, value must in [-1,1]",
+		},
+		"AcoshInfinity": {
+			expression: bson.D{{"$acosh", math.Inf(1)}},
+			res:        math.Inf(1),
+		},
+		"Atan2": {
+			expression: bson.D{{"$atan2", bson.A{int32(1), int32(1)}}},
+			res:        math.Pi / 4,
+		},
+		"RadiansToDegrees": {
+			expression: bson.D{{"$radiansToDegrees", math.Pi}},
+			res:        180.0,
+		},
+		"DegreesToRadians": {
+			expression: bson.D{{"$degreesToRadians", int32(180)}},
+			res:        math.Pi,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
+			}
+
+			cursor, err := collection.Aggregate(ctx, pipeline)
+
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			AssertEqualDocumentsSlice(t, []bson.D{{{"v", tc.res}}}, res)
+		})
+	}
+}
+
+func TestAggregateProjectRand(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "rand1"}},
+		bson.D{{"_id", "rand2"}},
+	})
+	require.NoError(t, err)
+
+	t.Run("Range", func(t *testing.T) {
+		t.Parallel()
+
+		pipeline := bson.A{
+			bson.D{{"$project", bson.D{{"_id", false}, {"v", bson.D{{"$rand", bson.D{}}}}}}},
+		}
+
+		cursor, err := collection.Aggregate(ctx, pipeline)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		require.Len(t, res, 2)
+
+		for _, doc := range res {
+			v, ok := doc.Map()["v"].(float64)
+			require.True(t, ok, "expected double, got %T", doc.Map()["v"])
+			assert.GreaterOrEqual(t, v, 0.0)
+			assert.Less(t, v, 1.0)
+		}
+	})
+
+	t.Run("Arguments", func(t *testing.T) {
+		t.Parallel()
+
+		pipeline := bson.A{
+			bson.D{{"$project", bson.D{{"_id", false}, {"v", bson.D{{"$rand", bson.D{{"a", int32(1)}}}}}}}},
+		}
+
+		_, err := collection.Aggregate(ctx, pipeline)
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    3040500,
+			Name:    "Location3040500",
+			Message: "$rand does not currently accept arguments",
+		}, err)
+	})
+}
+
+func TestAggregateProjectArrayOperators(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "array"},
+		{"a", bson.A{int32(1), int64(2), 3.0, "foo", nil}},
+		{"empty", bson.A{}},
+		{"s", "foo"},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		expression bson.D // required, expression to project
+
+		res        any                 // expected value of projected field
+		missing    bool                // optional, true if projected field is missing
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
+	}{
+		"ArrayElemAt": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$a", int64(1)}}},
+			res:        int64(2),
+		},
+		"ArrayElemAtNegative": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$a", -2.0}}},
+			res:        "foo",
+		},
+		"ArrayElemAtOutOfBounds": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$a", int32(5)}}},
+			missing:    true,
+		},
+		"ArrayElemAtNull": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$missing", int32(0)}}},
+			res:        nil,
+		},
+		"ArrayElemAtNotArray": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$s", int32(0)}}},
+			err: &mongo.CommandError{
+				Code: 28689,
+				Name: "Location28689",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$arrayElemAt's first argument must be an array, but is string",
+			},
+			altMessage: "$arrayElemAt's first argument must be an array, but is string",
+		},
+		"ArrayElemAtIndexNotNumber": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$a", "1"}}},
+			err: &mongo.CommandError{
+				Code: 28690,
+				Name: "Location28690",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$arrayElemAt's second argument must be a numeric value, but is string",
+			},
+			altMessage: "$arrayElemAt's second argument must be a numeric value, but is string",
+		},
+		"ArrayElemAtIndexNotInt": {
+			expression: bson.D{{"$arrayElemAt", bson.A{"$a", 1.5}}},
+			err: &mongo.CommandError{
+				Code: 28691,
+				Name: "Location28691",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$arrayElemAt's second argument must be representable as a 32-bit integer: 1.5",
+			},
+			altMessage: "$arrayElemAt's second argument must be representable as a 32-bit integer: 1.5",
+		},
+		"First": {
+			expression: bson.D{{"$first", "$a"}},
+			res:        int32(1),
+		},
+		"FirstEmpty": {
+			expression: bson.D{{"$first", "$empty"}},
+			missing:    true,
+		},
+		"Last": {
+			expression: bson.D{{"$last", "$a"}},
+			res:        nil,
+		},
+		"LastNotArray": {
+			expression: bson.D{{"$last", "$s"}},
+			err: &mongo.CommandError{
+				Code: 28689,
+				Name: "Location28689",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$last's argument must be an array, but is string",
+			},
+			altMessage: "$last's argument must be an array, but is string",
+		},
+		"Slice": {
+			expression: bson.D{{"$slice", bson.A{"$a", int32(2)}}},
+			res:        bson.A{int32(1), int64(2)},
+		},
+		"SliceNegative": {
+			expression: bson.D{{"$slice", bson.A{"$a", int32(-2)}}},
+			res:        bson.A{"foo", nil},
+		},
+		"SlicePosition": {
+			expression: bson.D{{"$slice", bson.A{"$a", int32(-3), int32(2)}}},
+			res:        bson.A{3.0, "foo"},
+		},
+		"SlicePositionOutOfBounds": {
+			expression: bson.D{{"$slice", bson.A{"$a", int32(10), int32(2)}}},
+			res:        bson.A{},
+		},
+		"SliceArgs": {
+			expression: bson.D{{"$slice", bson.A{"$a"}}},
+			err: &mongo.CommandError{
+				Code:    28667,
+				Name:    "Location28667",
+				Message: "Invalid $project :: caused by :: Expression $slice takes at least 2 arguments, and at most 3, but 1 were passed in.",
+			},
+			altMessage: "Expression $slice takes at least 2 arguments, and at most 3, but 1 were passed in.",
+		},
+		"SliceNotPositive": {
+			expression: bson.D{{"$slice", bson.A{"$a", int32(0), int32(0)}}},
+			err: &mongo.CommandError{
+				Code: 28729,
+				Name: "Location28729",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"Third argument to $slice must be positive: 0",
+			},
+			altMessage: "Third argument to $slice must be positive: 0",
+		},
+		"IndexOfArray": {
+			expression: bson.D{{"$indexOfArray", bson.A{"$a", int32(3)}}},
+			res:        int32(2),
+		},
+		"IndexOfArrayRange": {
+			expression: bson.D{{"$indexOfArray", bson.A{"$a", int32(1), int32(1), int32(4)}}},
+			res:        int32(-1),
+		},
+		"IndexOfArrayNegativeStart": {
+			expression: bson.D{{"$indexOfArray", bson.A{"$a", int32(1), int32(-1)}}},
+			err: &mongo.CommandError{
+				Code: 40097,
+				Name: "Location40097",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$indexOfArray requires a nonnegative starting index, found: -1",
+			},
+			altMessage: "$indexOfArray requires a nonnegative starting index, found: -1",
+		},
+		"Range": {
+			expression: bson.D{{"$range", bson.A{int32(0), int32(10), int32(3)}}},
+			res:        bson.A{int32(0), int32(3), int32(6), int32(9)},
+		},
+		"RangeNegativeStep": {
+			expression: bson.D{{"$range", bson.A{int32(5), int64(0), -2.0}}},
+			res:        bson.A{int32(5), int32(3), int32(1)},
+		},
+		"RangeEmpty": {
+			expression: bson.D{{"$range", bson.A{int32(5), int32(0)}}},
+			res:        bson.A{},
+		},
+		"RangeZeroStep": {
+			expression: bson.D{{"$range", bson.A{int32(0), int32(5), int32(0)}}},
+			err: &mongo.CommandError{
+				Code: 34449,
+				Name: "Location34449",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$range requires a non-zero step value",
+			},
+			altMessage: "$range requires a non-zero step value",
+		},
+		"RangeTooLarge": {
+			expression: bson.D{{"$range", bson.A{int32(0), int32(math.MaxInt32)}}},
+			err: &mongo.CommandError{
+				Code: 146,
+				Name: "ExceededMemoryLimit",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$range would use too much memory (34359738352 bytes) and cannot spill to disk. " +
+					"Memory limit: 104857600 bytes",
+			},
+			altMessage: "$range would use too much memory (34359738352 bytes) and cannot spill to disk. " +
+				"Memory limit: 104857600 bytes",
+		},
+		"ConcatArrays": {
+			expression: bson.D{{"$concatArrays", bson.A{"$empty", bson.A{int32(1)}, bson.A{"foo"}}}},
+			res:        bson.A{int32(1), "foo"},
+		},
+		"ConcatArraysNull": {
+			expression: bson.D{{"$concatArrays", bson.A{"$a", "$missing"}}},
+			res:        nil,
+		},
+		"ConcatArraysNotArray": {
+			expression: bson.D{{"$concatArrays", bson.A{"$a", "$s"}}},
+			err: &mongo.CommandError{
+				Code: 28664,
+				Name: "Location28664",
+				Message: "PlanExecutor error during aggregation :: caused by :: " +
+					"$concatArrays only supports arrays, not string",
 			},
+			altMessage: "$concatArrays only supports arrays, not string",
 		},
 	} {
+		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
-			if tc.skip != "" {
-				t.Skip(tc.skip)
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
 			}
 
-			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
-			require.NotNil(t, tc.err, "err must not be nil")
+			cursor, err := collection.Aggregate(ctx, pipeline)
 
-			_, err := collection.Aggregate(ctx, tc.pipeline)
-			AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+
+			expected := []bson.D{{{"v", tc.res}}}
+			if tc.missing {
+				expected = []bson.D{{}}
+			}
+
+			AssertEqualDocumentsSlice(t, expected, res)
 		})
 	}
 }
 
-func TestAggregateProject(t *testing.T) {
+func TestAggregateProjectTypeOperators(t *testing.T) {
 	t.Parallel()
 
-	ctx, collection := setup.Setup(t, shareddata.Scalars)
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{
+		{"_id", "types"},
+		{"i", int32(1)},
+		{"l", int64(2)},
+		{"d", 3.5},
+		{"s", "foo"},
+		{"n", nil},
+		{"a", bson.A{int32(1)}},
+	})
+	require.NoError(t, err)
 
 	for name, tc := range map[string]struct { //nolint:vet // used for testing only
-		pipeline bson.A // required, aggregation pipeline stages
+		expression bson.D // required, expression to project
 
-		res  []bson.D // required, expected response
-		skip string   // optional, skip test with a specified reason
+		res        any                 // expected value of projected field
+		err        *mongo.CommandError // expected error
+		altMessage string              // optional, alternative error message
 	}{
-		"IDFalseValueTrue": {
-			pipeline: bson.A{
-				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
-				bson.D{{"$project", bson.D{{"_id", false}, {"v", true}}}},
-			},
-			res: []bson.D{{{"v", int32(42)}}},
+		"TypeMissing": {
+			expression: bson.D{{"$type", "$missing"}},
+			res:        "missing",
 		},
-		"ValueTrueIDFalse": {
-			pipeline: bson.A{
-				bson.D{{"$match", bson.D{{"_id", "int32"}}}},
-				bson.D{{"$project", bson.D{{"v", true}, {"_id", false}}}},
+		"TypeNull": {
+			expression: bson.D{{"$type", "$n"}},
+			res:        "null",
+		},
+		"TypeLiteral": {
+			expression: bson.D{{"$type", bson.D{{"$literal", "$s"}}}},
+			res:        "string",
+		},
+		"TypeArrayField": {
+			expression: bson.D{{"$type", "$a"}},
+			res:        "array",
+		},
+		"IsNumberInt": {
+			expression: bson.D{{"$isNumber", "$i"}},
+			res:        true,
+		},
+		"IsNumberLong": {
+			expression: bson.D{{"$isNumber", "$l"}},
+			res:        true,
+		},
+		"IsNumberDouble": {
+			expression: bson.D{{"$isNumber", "$d"}},
+			res:        true,
+		},
+		"IsNumberString": {
+			expression: bson.D{{"$isNumber", "$s"}},
+			res:        false,
+		},
+		"IsNumberNull": {
+			expression: bson.D{{"$isNumber", "$n"}},
+			res:        false,
+		},
+		"IsNumberMissing": {
+			expression: bson.D{{"$isNumber", "$missing"}},
+			res:        false,
+		},
+		"IsNumberArray": {
+			expression: bson.D{{"$isNumber", "$a"}},
+			res:        false,
+		},
+		"IsNumberSingleElementArray": {
+			expression: bson.D{{"$isNumber", bson.A{"$i"}}},
+			res:        true,
+		},
+		"IsNumberInvalidLen": {
+			expression: bson.D{{"$isNumber", bson.A{"$i", "$l"}}},
+			err: &mongo.CommandError{
+				Code: 16020,
+				Name: "Location16020",
+				Message: "Invalid $project :: caused by :: " +
+					"Expression $isNumber takes exactly 1 arguments. 2 were passed in.",
 			},
-			res: []bson.D{{{"v", int32(42)}}},
+			altMessage: "Expression $isNumber takes exactly 1 arguments. 2 were passed in.",
 		},
 	} {
+		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
-			if tc.skip != "" {
-				t.Skip(tc.skip)
+			t.Parallel()
+
+			pipeline := bson.A{
+				bson.D{{"$project", bson.D{{"_id", false}, {"v", tc.expression}}}},
 			}
 
-			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
-			require.NotNil(t, tc.res, "res must not be nil")
+			cursor, err := collection.Aggregate(ctx, pipeline)
+
+			if tc.err != nil {
+				if err == nil {
+					defer cursor.Close(ctx)
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+
+				return
+			}
 
-			cursor, err := collection.Aggregate(ctx, tc.pipeline)
 			require.NoError(t, err)
-			defer cursor.Close(ctx)
 
 			var res []bson.D
-			err = cursor.All(ctx, &res)
-			require.NoError(t, err)
-			require.Equal(t, tc.res, res)
+			require.NoError(t, cursor.All(ctx, &res))
+
+			AssertEqualDocumentsSlice(t, []bson.D{{{"v", tc.res}}}, res)
 		})
 	}
 }
@@ -1275,3 +2866,405 @@ func TestAggregateCommandCursor(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregateLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"item", "almonds"}, {"qty", int32(2)}},
+		bson.D{{"_id", 2}, {"item", bson.A{"pecans", "cookies"}}, {"qty", int32(1)}},
+		bson.D{{"_id", 3}},
+		bson.D{{"_id", 4}, {"item", primitive.Regex{Pattern: "^c"}}}, // matched by equality, not as a pattern
+	})
+	require.NoError(t, err)
+
+	foreign := collection.Database().Collection(collection.Name() + "_inventory")
+
+	_, err = foreign.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"sku", "almonds"}, {"instock", int32(120)}},
+		bson.D{{"_id", 2}, {"sku", "cookies"}, {"instock", int32(80)}},
+		bson.D{{"_id", 3}, {"sku", "pecans"}, {"instock", int32(70)}},
+		bson.D{{"_id", 4}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		res []bson.D // required, expected response
+	}{
+		"LocalForeignFields": {
+			pipeline: bson.A{
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"localField", "item"},
+					{"foreignField", "sku"},
+					{"as", "docs"},
+				}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+			res: []bson.D{
+				{{"_id", int32(1)}, {"item", "almonds"}, {"qty", int32(2)}, {"docs", bson.A{
+					bson.D{{"_id", int32(1)}, {"sku", "almonds"}, {"instock", int32(120)}},
+				}}},
+				{{"_id", int32(2)}, {"item", bson.A{"pecans", "cookies"}}, {"qty", int32(1)}, {"docs", bson.A{
+					bson.D{{"_id", int32(2)}, {"sku", "cookies"}, {"instock", int32(80)}},
+					bson.D{{"_id", int32(3)}, {"sku", "pecans"}, {"instock", int32(70)}},
+				}}},
+				{{"_id", int32(3)}, {"docs", bson.A{
+					bson.D{{"_id", int32(4)}},
+				}}},
+				{{"_id", int32(4)}, {"item", primitive.Regex{Pattern: "^c"}}, {"docs", bson.A{}}},
+			},
+		},
+		"NonExistentCollection": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", 1}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", "non-existent"},
+					{"localField", "item"},
+					{"foreignField", "sku"},
+					{"as", "docs"},
+				}}},
+			},
+			res: []bson.D{
+				{{"_id", int32(1)}, {"item", "almonds"}, {"qty", int32(2)}, {"docs", bson.A{}}},
+			},
+		},
+		"LetPipeline": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", 1}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"let", bson.D{{"item", "$item"}, {"qty", "$qty"}}},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$sku"}, {"regex", "$$item"}}}}}}}},
+						bson.D{{"$project", bson.D{{"_id", 0}, {"instock", 1}, {"ordered", "$$qty"}}}},
+					}},
+					{"as", "docs"},
+				}}},
+			},
+			res: []bson.D{
+				{{"_id", int32(1)}, {"item", "almonds"}, {"qty", int32(2)}, {"docs", bson.A{
+					bson.D{{"instock", int32(120)}, {"ordered", int32(2)}},
+				}}},
+			},
+		},
+		"FieldsAndPipeline": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", 2}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"localField", "item"},
+					{"foreignField", "sku"},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"instock", bson.D{{"$gt", 75}}}}}},
+						bson.D{{"$project", bson.D{{"_id", 0}, {"sku", 1}}}},
+					}},
+					{"as", "docs"},
+				}}},
+			},
+			res: []bson.D{
+				{{"_id", int32(2)}, {"item", bson.A{"pecans", "cookies"}}, {"qty", int32(1)}, {"docs", bson.A{
+					bson.D{{"sku", "cookies"}},
+				}}},
+			},
+		},
+		"DotNotationAs": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", 1}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"pipeline", bson.A{
+						bson.D{{"$count", "n"}},
+					}},
+					{"as", "stats.docs"},
+				}}},
+			},
+			res: []bson.D{
+				{{"_id", int32(1)}, {"item", "almonds"}, {"qty", int32(2)}, {"stats", bson.D{{"docs", bson.A{
+					bson.D{{"n", int32(4)}},
+				}}}}},
+			},
+		},
+		"CommandLet": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", 1}}}},
+				bson.D{{"$lookup", bson.D{
+					{"from", foreign.Name()},
+					{"pipeline", bson.A{
+						bson.D{{"$match", bson.D{{"$expr", bson.D{{"$regexMatch", bson.D{{"input", "$sku"}, {"regex", "$$sku"}}}}}}}},
+						bson.D{{"$project", bson.D{{"_id", 1}}}},
+					}},
+					{"as", "docs"},
+				}}},
+			},
+			res: []bson.D{
+				{{"_id", int32(1)}, {"item", "almonds"}, {"qty", int32(2)}, {"docs", bson.A{
+					bson.D{{"_id", int32(3)}},
+				}}},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.res, "res must not be nil")
+
+			opts := options.Aggregate().SetLet(bson.D{{"sku", "pecans"}})
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline, opts)
+			require.NoError(t, err)
+			defer cursor.Close(ctx)
+
+			var res []bson.D
+			err = cursor.All(ctx, &res)
+			require.NoError(t, err)
+			AssertEqualDocumentsSlice(t, tc.res, res)
+		})
+	}
+}
+
+func TestAggregateLookupErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", 1}})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for test only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		err *mongo.CommandError // required
+	}{
+		"NotDocument": {
+			pipeline: bson.A{bson.D{{"$lookup", "foo"}}},
+			err:      &mongo.CommandError{Code: 9, Name: "FailedToParse"},
+		},
+		"UnknownArgument": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"}, {"localField", "a"}, {"foreignField", "b"}, {"as", "c"}, {"unknown", 1},
+			}}}},
+			err: &mongo.CommandError{Code: 9, Name: "FailedToParse"},
+		},
+		"MissingAs": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"}, {"localField", "a"}, {"foreignField", "b"},
+			}}}},
+			err: &mongo.CommandError{Code: 9, Name: "FailedToParse"},
+		},
+		"LocalFieldType": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"}, {"localField", 1}, {"foreignField", "b"}, {"as", "c"},
+			}}}},
+			err: &mongo.CommandError{Code: 9, Name: "FailedToParse"},
+		},
+		"MissingForeignField": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"}, {"localField", "a"}, {"as", "c"},
+			}}}},
+			err: &mongo.CommandError{Code: 9, Name: "FailedToParse"},
+		},
+		"PipelineType": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"}, {"pipeline", "a"}, {"as", "c"},
+			}}}},
+			err: &mongo.CommandError{Code: 9, Name: "FailedToParse"},
+		},
+		"InvalidLetName": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"}, {"let", bson.D{{"Foo", 1}}}, {"pipeline", bson.A{}}, {"as", "c"},
+			}}}},
+			err: &mongo.CommandError{Code: 16870, Name: "Location16870"},
+		},
+		"InvalidPipelineStage": {
+			pipeline: bson.A{bson.D{{"$lookup", bson.D{
+				{"from", "foo"}, {"pipeline", bson.A{bson.D{{"$match", "foo"}}}}, {"as", "c"},
+			}}}},
+			err: &mongo.CommandError{Code: 15959, Name: "Location15959"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, tc.pipeline)
+			AssertMatchesCommandError(t, *tc.err, err)
+		})
+	}
+}
+
+func TestAggregateFacet(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"type", "a"}, {"v", int32(10)}},
+		bson.D{{"_id", 2}, {"type", "b"}, {"v", int32(20)}},
+		bson.D{{"_id", 3}, {"type", "a"}, {"v", int32(30)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		res []bson.D // required, expected response
+	}{
+		"CountGroupTop": {
+			pipeline: bson.A{
+				bson.D{{"$facet", bson.D{
+					{"total", bson.A{bson.D{{"$count", "n"}}}},
+					{"byType", bson.A{
+						bson.D{{"$group", bson.D{{"_id", "$type"}, {"sum", bson.D{{"$sum", "$v"}}}}}},
+						bson.D{{"$sort", bson.D{{"_id", 1}}}},
+					}},
+					{"top", bson.A{
+						bson.D{{"$sort", bson.D{{"v", -1}}}},
+						bson.D{{"$limit", 1}},
+						bson.D{{"$project", bson.D{{"_id", 1}}}},
+					}},
+				}}},
+			},
+			res: []bson.D{{
+				{"total", bson.A{bson.D{{"n", int32(3)}}}},
+				{"byType", bson.A{
+					bson.D{{"_id", "a"}, {"sum", int32(40)}},
+					bson.D{{"_id", "b"}, {"sum", int32(20)}},
+				}},
+				{"top", bson.A{bson.D{{"_id", int32(3)}}}},
+			}},
+		},
+		"IndependentCopies": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", 1}}}},
+				bson.D{{"$facet", bson.D{
+					{"set", bson.A{bson.D{{"$set", bson.D{{"v", "foo"}}}}}},
+					{"all", bson.A{}},
+				}}},
+			},
+			res: []bson.D{{
+				{"set", bson.A{bson.D{{"_id", int32(1)}, {"type", "a"}, {"v", "foo"}}}},
+				{"all", bson.A{bson.D{{"_id", int32(1)}, {"type", "a"}, {"v", int32(10)}}}},
+			}},
+		},
+		"Lookup": {
+			pipeline: bson.A{
+				bson.D{{"$facet", bson.D{{"joined", bson.A{
+					bson.D{{"$match", bson.D{{"_id", 1}}}},
+					bson.D{{"$lookup", bson.D{
+						{"from", collection.Name()},
+						{"localField", "type"},
+						{"foreignField", "type"},
+						{"pipeline", bson.A{bson.D{{"$project", bson.D{{"_id", 1}}}}}},
+						{"as", "same"},
+					}}},
+					bson.D{{"$project", bson.D{{"_id", 0}, {"same", 1}}}},
+				}}}}},
+			},
+			res: []bson.D{{
+				{"joined", bson.A{bson.D{{"same", bson.A{bson.D{{"_id", int32(1)}}, bson.D{{"_id", int32(3)}}}}}}},
+			}},
+		},
+		"NoInput": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", "none"}}}},
+				bson.D{{"$facet", bson.D{{"total", bson.A{bson.D{{"$count", "n"}}}}}}},
+			},
+			res: []bson.D{{{"total", bson.A{}}}},
+		},
+		"NextStages": {
+			pipeline: bson.A{
+				bson.D{{"$facet", bson.D{{"docs", bson.A{bson.D{{"$sort", bson.D{{"_id", 1}}}}}}}}},
+				bson.D{{"$unwind", "$docs"}},
+				bson.D{{"$project", bson.D{{"_id", "$docs._id"}}}},
+			},
+			res: []bson.D{
+				{{"_id", int32(1)}},
+				{{"_id", int32(2)}},
+				{{"_id", int32(3)}},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.res, "res must not be nil")
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline)
+			require.NoError(t, err)
+			defer cursor.Close(ctx)
+
+			var res []bson.D
+			err = cursor.All(ctx, &res)
+			require.NoError(t, err)
+			AssertEqualDocumentsSlice(t, tc.res, res)
+		})
+	}
+}
+
+func TestAggregateFacetErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for test only
+		pipeline bson.A // required, aggregation pipeline stages
+
+		err *mongo.CommandError // required
+	}{
+		"NotDocument": {
+			pipeline: bson.A{bson.D{{"$facet", "foo"}}},
+			err:      &mongo.CommandError{Code: 40169, Name: "Location40169"},
+		},
+		"Empty": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{}}}},
+			err:      &mongo.CommandError{Code: 40169, Name: "Location40169"},
+		},
+		"NotArray": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", "bar"}}}}},
+			err:      &mongo.CommandError{Code: 40170, Name: "Location40170"},
+		},
+		"StageNotDocument": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", bson.A{"bar"}}}}}},
+			err:      &mongo.CommandError{Code: 40171, Name: "Location40171"},
+		},
+		"DollarName": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"$foo", bson.A{}}}}}},
+			err:      &mongo.CommandError{Code: 16410, Name: "Location16410"},
+		},
+		"DotName": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo.bar", bson.A{}}}}}},
+			err:      &mongo.CommandError{Code: 16412, Name: "Location16412"},
+		},
+		"NestedFacet": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", bson.A{
+				bson.D{{"$facet", bson.D{{"bar", bson.A{}}}}},
+			}}}}}},
+			err: &mongo.CommandError{Code: 40600, Name: "Location40600"},
+		},
+		"InvalidStage": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"foo", bson.A{
+				bson.D{{"$match", "bar"}},
+			}}}}}},
+			err: &mongo.CommandError{Code: 15959, Name: "Location15959"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.pipeline, "pipeline must not be nil")
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			_, err := collection.Aggregate(ctx, tc.pipeline)
+			AssertMatchesCommandError(t, *tc.err, err)
+		})
+	}
+}
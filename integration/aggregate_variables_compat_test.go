@@ -40,7 +40,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 				bson.D{{"$group", bson.D{{"_id", "$$ROOT"}}}},
 				bson.D{{"$sort", bson.D{{"_id", 1}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1992",
 		},
 		"GroupIDTwice": {
 			pipeline: bson.A{
@@ -49,7 +48,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 				bson.D{{"$group", bson.D{{"_id", "$$ROOT"}}}},
 				bson.D{{"$sort", bson.D{{"_id", 1}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1992",
 		},
 		"GroupIDFieldID": {
 			pipeline: bson.A{
@@ -57,7 +55,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 				bson.D{{"$group", bson.D{{"_id", "$$ROOT._id"}}}},
 				bson.D{{"$sort", bson.D{{"_id", 1}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1992",
 		},
 		"GroupIDFieldValue": {
 			pipeline: bson.A{
@@ -93,7 +90,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 				}}},
 				bson.D{{"$sort", bson.D{{"_id", 1}}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1992",
 		},
 		"GroupSumAccumulator": {
 			pipeline: bson.A{
@@ -111,7 +107,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 					{"sum", bson.D{{"$sum", "$$ROOT"}}},
 				}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1992",
 		},
 		"ProjectTypeOperator": {
 			pipeline: bson.A{
@@ -119,7 +114,6 @@ func TestAggregateVariablesCompatRoot(t *testing.T) {
 					{"type", bson.D{{"$type", "$$ROOT"}}},
 				}}},
 			},
-			skip: "https://github.com/FerretDB/FerretDB/issues/1992",
 		},
 		"Set": {
 			pipeline: bson.A{
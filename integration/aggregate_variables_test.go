@@ -0,0 +1,115 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateVariablesSystem(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"v", int32(1)}},
+		bson.D{{"_id", 2}, {"v", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	t.Run("RootCurrent", func(t *testing.T) {
+		t.Parallel()
+
+		cursor, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$group", bson.D{
+				{"_id", bson.D{{"root", "$$ROOT"}, {"v", "$$CURRENT.v"}}},
+				{"sum", bson.D{{"$sum", "$$ROOT.v"}}},
+			}}},
+			bson.D{{"$sort", bson.D{{"_id", 1}}}},
+		})
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		expected := []bson.D{
+			{{"_id", bson.D{{"root", bson.D{{"_id", int32(1)}, {"v", int32(1)}}}, {"v", int32(1)}}}, {"sum", int32(1)}},
+			{{"_id", bson.D{{"root", bson.D{{"_id", int32(2)}, {"v", int32(2)}}}, {"v", int32(2)}}}, {"sum", int32(2)}},
+		}
+		AssertEqualDocumentsSlice(t, expected, res)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		t.Parallel()
+
+		cursor, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$group", bson.D{{"_id", "$$REMOVE"}, {"sum", bson.D{{"$sum", "$v"}}}}}},
+		})
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		AssertEqualDocumentsSlice(t, []bson.D{{{"_id", nil}, {"sum", int32(3)}}}, res)
+	})
+
+	t.Run("NowClusterTime", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Now().Add(-time.Second)
+
+		cursor, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$project", bson.D{{"now", "$$NOW"}, {"clusterTime", "$$CLUSTER_TIME"}}}},
+		})
+		require.NoError(t, err)
+
+		var res []bson.M
+		require.NoError(t, cursor.All(ctx, &res))
+		require.Len(t, res, 2)
+
+		now, ok := res[0]["now"].(primitive.DateTime)
+		require.True(t, ok, "%T", res[0]["now"])
+		assert.True(t, now.Time().After(start), "%s", now.Time())
+
+		clusterTime, ok := res[0]["clusterTime"].(primitive.Timestamp)
+		require.True(t, ok, "%T", res[0]["clusterTime"])
+		assert.GreaterOrEqual(t, int64(clusterTime.T), start.Unix())
+
+		// values are the same for all documents
+		assert.Equal(t, res[0]["now"], res[1]["now"])
+		assert.Equal(t, res[0]["clusterTime"], res[1]["clusterTime"])
+	})
+
+	t.Run("UserRoles", func(t *testing.T) {
+		t.Parallel()
+
+		cursor, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$match", bson.D{{"_id", 1}}}},
+			bson.D{{"$project", bson.D{{"roles", "$$USER_ROLES"}}}},
+		})
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		AssertEqualDocumentsSlice(t, []bson.D{{{"_id", int32(1)}, {"roles", bson.A{}}}}, res)
+	})
+}
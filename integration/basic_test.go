@@ -266,6 +266,7 @@ func TestCollectionName(t *testing.T) {
 
 	collectionName300 := strings.Repeat("aB", 150)
 	collectionName235 := strings.Repeat("a", 235)
+	collectionNameNonLatin240 := strings.Repeat("コ", 80)
 
 	cases := map[string]struct {
 		collection string // collection name, defaults to empty string
@@ -289,6 +290,18 @@ func TestCollectionName(t *testing.T) {
 		"LongEnough": {
 			collection: collectionName235,
 		},
+		"NonLatinTooLong": {
+			collection: collectionNameNonLatin240,
+			err: &mongo.CommandError{
+				Name: "InvalidNamespace",
+				Code: 73,
+				Message: fmt.Sprintf(
+					"Fully qualified namespace is too long. Namespace: TestCollectionName.%s Max: 255",
+					collectionNameNonLatin240,
+				),
+			},
+			altMessage: fmt.Sprintf("Invalid collection name: %s", collectionNameNonLatin240),
+		},
 		"Short": {
 			collection: "a",
 		},
@@ -413,6 +426,15 @@ func TestDatabaseName(t *testing.T) {
 			"63ok": {
 				db: strings.Repeat("a", 63),
 			},
+			"NonLatin": {
+				db: "データベース",
+			},
+			"NonLatin62Bytes": {
+				db: strings.Repeat("б", 31),
+			},
+			"SpecialCharacters": {
+				db: "+-*<>=~!@^&|`()[],;:",
+			},
 		} {
 			name, tc := name, tc
 			t.Run(name, func(t *testing.T) {
@@ -436,6 +458,7 @@ func TestDatabaseName(t *testing.T) {
 		ctx, collection := setup.Setup(t)
 
 		dbName64 := strings.Repeat("a", 64)
+		dbNameNonLatin64 := strings.Repeat("б", 32)
 
 		cases := map[string]struct {
 			db string // database name, defaults to empty string
@@ -453,6 +476,15 @@ func TestDatabaseName(t *testing.T) {
 				},
 				altMessage: fmt.Sprintf("Invalid namespace specified '%s.TestDatabaseName-Err'", dbName64),
 			},
+			"NonLatinTooLong": {
+				db: dbNameNonLatin64,
+				err: &mongo.CommandError{
+					Name:    "InvalidNamespace",
+					Code:    73,
+					Message: "db name must be at most 63 characters, found: 64",
+				},
+				altMessage: fmt.Sprintf("Invalid namespace specified '%s.TestDatabaseName-Err'", dbNameNonLatin64),
+			},
 			"WithASlash": {
 				db: "/",
 				err: &mongo.CommandError{
@@ -517,6 +549,45 @@ func TestDatabaseName(t *testing.T) {
 	})
 }
 
+func TestDatabaseNameDifferCase(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	// make sure that the database exists
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "foo"}})
+	require.NoError(t, err)
+
+	dbName := collection.Database().Name()
+	upperName := strings.ToUpper(dbName)
+	require.NotEqual(t, dbName, upperName)
+
+	db := collection.Database().Client().Database(upperName)
+	t.Cleanup(func() {
+		// database should not exist, but drop it just in case
+		_ = db.Drop(ctx)
+	})
+
+	expected := mongo.CommandError{
+		Name: "DatabaseDifferCase",
+		Code: 13297,
+		Message: fmt.Sprintf(
+			"db already exists with different case already have: [%s] trying to create [%s]",
+			dbName, upperName,
+		),
+	}
+
+	_, err = db.Collection(collection.Name()).InsertOne(ctx, bson.D{{"_id", "foo"}})
+	AssertEqualCommandError(t, expected, err)
+
+	err = db.CreateCollection(ctx, collection.Name())
+	AssertEqualCommandError(t, expected, err)
+
+	names, err := collection.Database().Client().ListDatabaseNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.NotContains(t, names, upperName)
+}
+
 func TestDebugError(t *testing.T) {
 	setup.SkipForMongoDB(t, "FerretDB-specific command")
 
@@ -15,6 +15,8 @@
 package integration
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"runtime"
@@ -134,6 +136,42 @@ func TestCommandsAdministrationCreateDropListDatabases(t *testing.T) {
 	testutil.AssertEqual(t, expected, actual)
 }
 
+func TestCommandsAdministrationDropDatabaseCollections(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	db := collection.Database()
+
+	for i := range 5 {
+		_, err := db.Collection(fmt.Sprintf("%s_%d", collection.Name(), i)).InsertOne(ctx, bson.D{{"_id", int32(i)}})
+		require.NoError(t, err)
+	}
+
+	err := db.RunCommand(ctx, bson.D{{"dropDatabase", 1}}).Err()
+	require.NoError(t, err)
+
+	names, err := db.ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	names, err = db.Client().ListDatabaseNames(ctx, bson.D{{"name", db.Name()}})
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	// finished drops are not reported
+	var res bson.D
+	err = db.Client().Database("admin").RunCommand(
+		ctx,
+		bson.D{{"currentOp", int32(1)}, {"desc", "DropDatabase"}, {"ns", db.Name()}},
+	).Decode(&res)
+	require.NoError(t, err)
+
+	inprog, ok := res.Map()["inprog"].(bson.A)
+	require.True(t, ok)
+	assert.Empty(t, inprog)
+}
+
 func TestCommandsAdministrationListDatabases(t *testing.T) {
 	t.Parallel()
 
@@ -489,6 +527,14 @@ func TestCommandsAdministrationGetParameter(t *testing.T) {
 				"ok":    float64(1),
 			},
 		},
+		"GetParameter_SCRAMIterationCount": {
+			command: bson.D{{"getParameter", 1}, {"scramIterationCount", 1}, {"scramSHA256IterationCount", 1}},
+			expected: map[string]any{
+				"scramIterationCount":       int32(10000),
+				"scramSHA256IterationCount": int32(15000),
+				"ok":                        float64(1),
+			},
+		},
 		"GetParameter_Zero": {
 			command: bson.D{{"getParameter", 0}, {"quiet", 1}, {"comment", "getParameter test"}},
 			expected: map[string]any{
@@ -1249,6 +1295,87 @@ func TestCommandsAdministrationDataSizeErrors(t *testing.T) {
 	}
 }
 
+func TestCommandsAdministrationDBHash(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	docs := []any{
+		bson.D{{"_id", int32(2)}, {"v", "bar"}},
+		bson.D{{"_id", int32(1)}, {"v", "foo"}},
+		bson.D{{"_id", int32(3)}, {"v", int64(42)}},
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	err = db.CreateCollection(ctx, collection.Name()+"_empty")
+	require.NoError(t, err)
+
+	// documents are hashed in _id order
+	h := md5.New()
+	for _, i := range []int{1, 0, 2} {
+		b, err := bson.Marshal(docs[i])
+		require.NoError(t, err)
+
+		_, err = h.Write(b)
+		require.NoError(t, err)
+	}
+
+	expected := hex.EncodeToString(h.Sum(nil))
+	empty := hex.EncodeToString(md5.New().Sum(nil))
+
+	var actual bson.D
+	err = db.RunCommand(ctx, bson.D{{"dbHash", int32(1)}}).Decode(&actual)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, actual)
+	assert.Equal(t, float64(1), doc.Remove("ok"))
+
+	collections, ok := doc.Remove("collections").(*types.Document)
+	require.True(t, ok)
+	assert.Equal(t, []string{collection.Name(), collection.Name() + "_empty"}, collections.Keys())
+	assert.Equal(t, expected, must.NotFail(collections.Get(collection.Name())))
+	assert.Equal(t, empty, must.NotFail(collections.Get(collection.Name()+"_empty")))
+
+	assert.Len(t, doc.Remove("md5"), 32)
+
+	uuids, ok := doc.Remove("uuids").(*types.Document)
+	require.True(t, ok)
+	assert.Equal(t, collections.Keys(), uuids.Keys())
+
+	// only requested collections are hashed
+	err = db.RunCommand(ctx, bson.D{
+		{"dbHash", int32(1)},
+		{"collections", bson.A{collection.Name()}},
+	}).Decode(&actual)
+	require.NoError(t, err)
+
+	doc = ConvertDocument(t, actual)
+	collections, ok = doc.Remove("collections").(*types.Document)
+	require.True(t, ok)
+	assert.Equal(t, []string{collection.Name()}, collections.Keys())
+	assert.Equal(t, expected, must.NotFail(collections.Get(collection.Name())))
+}
+
+func TestCommandsAdministrationDBHashErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	err := collection.Database().RunCommand(ctx, bson.D{
+		{"dbHash", int32(1)},
+		{"collections", "foo"},
+	}).Err()
+
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    14,
+		Name:    "TypeMismatch",
+		Message: "BSON field 'dbHash.collections' is the wrong type 'string', expected type 'array'",
+	}, err)
+}
+
 func TestCommandsAdministrationDBStats(t *testing.T) {
 	t.Parallel()
 
@@ -1445,6 +1572,41 @@ func TestCommandsAdministrationServerStatus(t *testing.T) {
 	assert.LessOrEqual(t, int32(1), must.NotFail(catalogStats.Get("capped")))
 }
 
+func TestCommandsAdministrationServerStatusDrivers(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "drivers field is FerretDB-specific")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"serverStatus", int32(1)}}).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+
+	drivers, ok := must.NotFail(doc.Get("drivers")).(*types.Array)
+	require.True(t, ok)
+
+	var found bool
+
+	for i := 0; i < drivers.Len(); i++ {
+		d := must.NotFail(drivers.Get(i)).(*types.Document)
+		if must.NotFail(d.Get("name")) != "mongo-go-driver" {
+			continue
+		}
+
+		found = true
+
+		assert.NotEmpty(t, must.NotFail(d.Get("version")))
+		assert.Positive(t, must.NotFail(d.Get("count")))
+	}
+
+	assert.True(t, found)
+}
+
 func TestCommandsAdministrationServerStatusMetrics(t *testing.T) {
 	t.Parallel()
 
@@ -1885,6 +2047,28 @@ func TestCommandsAdministrationCompactErrors(t *testing.T) {
 	}
 }
 
+func TestCommandsAdministrationAnalyze(t *testing.T) {
+	setup.SkipForMongoDB(t, "analyze requires a feature flag in MongoDB")
+
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		Providers: []shareddata.Provider{shareddata.DocumentsStrings},
+	})
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"analyze", s.Collection.Name()}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+
+	err = s.Collection.Database().RunCommand(s.Ctx, bson.D{{"analyze", "non-existent"}}).Decode(&res)
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    26,
+		Name:    "NamespaceNotFound",
+		Message: fmt.Sprintf("Couldn't find collection %s.non-existent", s.Collection.Database().Name()),
+	}, err)
+}
+
 func TestCommandsAdministrationCurrentOp(t *testing.T) {
 	t.Parallel()
 
@@ -1904,3 +2088,246 @@ func TestCommandsAdministrationCurrentOp(t *testing.T) {
 	_, ok := must.NotFail(doc.Get("inprog")).(*types.Array)
 	assert.True(t, ok)
 }
+
+func TestCommandsAdministrationCurrentOpOwn(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(
+		s.Ctx,
+		bson.D{
+			{"currentOp", int32(1)},
+			{"$ownOps", true},
+			{"command.comment", "current-op-own"},
+			{"comment", "current-op-own"},
+		},
+	).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+
+	inprog := must.NotFail(doc.Get("inprog")).(*types.Array)
+	require.Equal(t, 1, inprog.Len())
+
+	op := must.NotFail(inprog.Get(0)).(*types.Document)
+
+	assert.Equal(t, "command", must.NotFail(op.Get("op")))
+	assert.Equal(t, "current-op-own", must.NotFail(op.GetByPath(types.NewStaticPath("command", "comment"))))
+	assert.Equal(t, "mongo-go-driver", must.NotFail(op.GetByPath(types.NewStaticPath("clientMetadata", "driver", "name"))))
+}
+
+func TestCommandsAdministrationCurrentOpStage(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	pipeline := bson.A{
+		bson.D{{"$currentOp", bson.D{{"allUsers", true}}}},
+		bson.D{{"$match", bson.D{{"command.comment", "current-op-stage"}}}},
+		bson.D{{"$project", bson.D{{"_id", 0}, {"op", 1}, {"command.aggregate", 1}}}},
+	}
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(
+		s.Ctx,
+		bson.D{
+			{"aggregate", int32(1)},
+			{"pipeline", pipeline},
+			{"cursor", bson.D{}},
+			{"comment", "current-op-stage"},
+		},
+	).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+
+	assert.Equal(t, "admin.$cmd.aggregate", must.NotFail(doc.GetByPath(types.NewStaticPath("cursor", "ns"))))
+
+	firstBatch := must.NotFail(doc.GetByPath(types.NewStaticPath("cursor", "firstBatch"))).(*types.Array)
+	require.Equal(t, 1, firstBatch.Len())
+
+	op := must.NotFail(firstBatch.Get(0)).(*types.Document)
+	assert.Equal(t, "command", must.NotFail(op.Get("op")))
+	assert.EqualValues(t, 1, must.NotFail(op.GetByPath(types.NewStaticPath("command", "aggregate"))))
+
+	t.Run("NotFirstStage", func(t *testing.T) {
+		t.Parallel()
+
+		err := s.Collection.Database().RunCommand(
+			s.Ctx,
+			bson.D{
+				{"aggregate", int32(1)},
+				{"pipeline", bson.A{bson.D{{"$match", bson.D{}}}, bson.D{{"$currentOp", bson.D{}}}}},
+				{"cursor", bson.D{}},
+			},
+		).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    40602,
+			Name:    "Location40602",
+			Message: "$currentOp is only valid as the first stage in a pipeline",
+		}, err)
+	})
+
+	t.Run("Collection", func(t *testing.T) {
+		t.Parallel()
+
+		err := s.Collection.Database().RunCommand(
+			s.Ctx,
+			bson.D{
+				{"aggregate", s.Collection.Name()},
+				{"pipeline", bson.A{bson.D{{"$currentOp", bson.D{}}}}},
+				{"cursor", bson.D{}},
+			},
+		).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    73,
+			Name:    "InvalidNamespace",
+			Message: "$currentOp must be run against the 'admin' database with {aggregate: 1}",
+		}, err)
+	})
+
+	t.Run("InvalidOption", func(t *testing.T) {
+		t.Parallel()
+
+		err := s.Collection.Database().RunCommand(
+			s.Ctx,
+			bson.D{
+				{"aggregate", int32(1)},
+				{"pipeline", bson.A{bson.D{{"$currentOp", bson.D{{"allUsers", "yes"}}}}}},
+				{"cursor", bson.D{}},
+			},
+		).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    9,
+			Name:    "FailedToParse",
+			Message: "The 'allUsers' parameter of the $currentOp stage must be a boolean value, but found: string",
+		}, err)
+	})
+}
+
+func TestCommandsAdministrationKillOp(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+	db := s.Collection.Database()
+
+	var res bson.D
+	err := db.RunCommand(s.Ctx, bson.D{{"killOp", int32(1)}, {"op", int32(math.MaxInt32)}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"info", "attempting to kill op"}, {"ok", float64(1)}}, res)
+
+	err = db.RunCommand(s.Ctx, bson.D{{"killOp", int32(1)}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    2,
+		Name:    "BadValue",
+		Message: `Did not provide "op" field`,
+	}, err)
+
+	err = s.Collection.Database().Client().Database(testutil.DatabaseName(t)).RunCommand(
+		s.Ctx,
+		bson.D{{"killOp", int32(1)}, {"op", int32(1)}},
+	).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    13,
+		Name:    "Unauthorized",
+		Message: "killOp may only be run against the admin database.",
+	}, err)
+}
+
+func TestCommandsAdministrationReadOnly(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "readOnly can't be set at runtime")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+	db := s.Collection.Database()
+
+	var res bson.D
+	err := db.RunCommand(s.Ctx, bson.D{{"setParameter", 1}, {"readOnly", true}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"was", false}, {"ok", float64(1)}}, res)
+
+	err = db.RunCommand(s.Ctx, bson.D{{"insert", s.Collection.Name()}, {"documents", bson.A{bson.D{{"_id", "foo"}}}}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    10107,
+		Name:    "NotWritablePrimary",
+		Message: "not primary: FerretDB is running in read-only mode",
+	}, err)
+
+	err = db.RunCommand(s.Ctx, bson.D{{"find", s.Collection.Name()}}).Err()
+	require.NoError(t, err)
+
+	err = db.RunCommand(s.Ctx, bson.D{{"hello", 1}}).Decode(&res)
+	require.NoError(t, err)
+	assert.Equal(t, true, res.Map()["readOnly"])
+	assert.Equal(t, true, res.Map()["isWritablePrimary"])
+
+	err = db.RunCommand(s.Ctx, bson.D{{"getParameter", 1}, {"readOnly", 1}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"readOnly", true}, {"ok", float64(1)}}, res)
+
+	err = db.RunCommand(s.Ctx, bson.D{{"setParameter", 1}, {"readOnly", false}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"was", true}, {"ok", float64(1)}}, res)
+
+	_, err = s.Collection.InsertOne(s.Ctx, bson.D{{"_id", "foo"}})
+	require.NoError(t, err)
+}
+
+func TestCommandsAdministrationLegacy(tt *testing.T) {
+	tt.Parallel()
+
+	ctx, collection := setup.Setup(tt)
+	db := collection.Database()
+
+	tt.Run("GetLastError", func(tt *testing.T) {
+		tt.Parallel()
+
+		t := setup.FailsForMongoDB(tt, "MongoDB removed getLastError command")
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"getLastError", int32(1)}, {"w", int32(1)}}).Decode(&res)
+		require.NoError(t, err)
+
+		m := res.Map()
+		assert.Nil(t, m["err"])
+		assert.Equal(t, int32(0), m["n"])
+		assert.Equal(t, float64(1), m["ok"])
+	})
+
+	tt.Run("ResetError", func(tt *testing.T) {
+		tt.Parallel()
+
+		t := setup.FailsForMongoDB(tt, "MongoDB removed resetError command")
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{{"resetError", int32(1)}}).Decode(&res)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), res.Map()["ok"])
+	})
+
+	tt.Run("CopyDB", func(t *testing.T) {
+		t.Parallel()
+
+		err := db.Client().Database("admin").RunCommand(ctx, bson.D{
+			{"copydb", int32(1)},
+			{"fromdb", db.Name()},
+			{"todb", db.Name() + "_copy"},
+		}).Err()
+
+		var cmdErr mongo.CommandError
+		require.ErrorAs(t, err, &cmdErr)
+		assert.Equal(t, int32(59), cmdErr.Code)
+		assert.Equal(t, "CommandNotFound", cmdErr.Name)
+	})
+}
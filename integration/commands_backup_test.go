@@ -0,0 +1,285 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestCommandsBackupRestore(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "createBackup and restoreBackup are FerretDB-specific")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{BackupURL: "file://" + filepath.ToSlash(tt.TempDir())},
+	})
+	ctx, collection := s.Ctx, s.Collection
+	db := collection.Database()
+	adminDB := db.Client().Database("admin")
+
+	docs := make([]any, 1200)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", fmt.Sprintf("v%d", i)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{"v", -1}},
+		Options: options.Index().SetUnique(true).SetName("v_unique"),
+	})
+	require.NoError(t, err)
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(1024 * 1024).SetMaxDocuments(100)
+	require.NoError(t, db.CreateCollection(ctx, "capped", opts))
+
+	_, err = db.Collection("capped").InsertOne(ctx, bson.D{{"_id", "capped"}})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = adminDB.RunCommand(ctx, bson.D{{"createBackup", db.Name()}}).Decode(&res)
+	require.NoError(t, err)
+
+	m := res.Map()
+	name, _ := m["name"].(string)
+	assert.Regexp(t, `^`+db.Name()+`/\d{8}T\d{6}\.\d{3}Z\.bson$`, name)
+	assert.Equal(t, int32(2), m["collections"])
+	assert.Equal(t, int64(1201), m["documents"])
+	assert.Equal(t, float64(1), m["ok"])
+
+	err = adminDB.RunCommand(ctx, bson.D{{"restoreBackup", name}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    48,
+		Name:    "NamespaceExists",
+		Message: "Collection " + db.Name() + "." + collection.Name() + " already exists.",
+	}, err)
+
+	restoredDB := db.Client().Database(db.Name() + "_restored")
+	t.Cleanup(func() {
+		assert.NoError(t, restoredDB.Drop(ctx))
+	})
+
+	err = adminDB.RunCommand(ctx, bson.D{{"restoreBackup", name}, {"to", restoredDB.Name()}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"collections", int32(2)}, {"documents", int64(1201)}, {"ok", float64(1)}}, res)
+
+	for _, c := range []string{collection.Name(), "capped"} {
+		assert.Equal(t, FindAll(t, ctx, db.Collection(c)), FindAll(t, ctx, restoredDB.Collection(c)))
+	}
+
+	cursor, err := restoredDB.Collection(collection.Name()).Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+
+	expectedIndexes := []bson.D{
+		{{"v", int32(2)}, {"key", bson.D{{"_id", int32(1)}}}, {"name", "_id_"}},
+		{{"v", int32(2)}, {"key", bson.D{{"v", int32(-1)}}}, {"name", "v_unique"}, {"unique", true}},
+	}
+	assert.Equal(t, expectedIndexes, indexes)
+
+	var specs []bson.D
+	cursor, err = restoredDB.ListCollections(ctx, bson.D{{"name", "capped"}})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &specs))
+	require.Len(t, specs, 1)
+
+	options := specs[0].Map()["options"].(bson.D).Map()
+	assert.Equal(t, true, options["capped"])
+	assert.EqualValues(t, 1024*1024, options["size"])
+	assert.EqualValues(t, 100, options["max"])
+}
+
+func TestCommandsBackupRestorePointInTime(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "createBackup and restoreBackup are FerretDB-specific")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{BackupURL: "file://" + filepath.ToSlash(tt.TempDir())},
+	})
+	ctx, collection := s.Ctx, s.Collection
+	db := collection.Database()
+	adminDB := db.Client().Database("admin")
+	local := db.Client().Database("local")
+
+	// OpLog collection could be already created by other tests
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(1024 * 1024)
+	if err := local.CreateCollection(ctx, "oplog.rs", opts); err != nil {
+		AssertMatchesCommandError(t, mongo.CommandError{Code: 48, Name: "NamespaceExists"}, err)
+	}
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "one"}},
+		bson.D{{"_id", int32(2)}, {"v", "two"}},
+		bson.D{{"_id", int32(3)}, {"v", "three"}},
+	})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = adminDB.RunCommand(ctx, bson.D{{"createBackup", db.Name()}}).Decode(&res)
+	require.NoError(t, err)
+
+	name := res.Map()["name"].(string)
+	start := res.Map()["startClusterTime"].(primitive.Timestamp)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", int32(4)}, {"v", "four"}})
+	require.NoError(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$set", bson.D{{"v", "updated"}}}})
+	require.NoError(t, err)
+
+	_, err = collection.DeleteOne(ctx, bson.D{{"_id", int32(2)}})
+	require.NoError(t, err)
+
+	var last bson.D
+	err = local.Collection("oplog.rs").FindOne(ctx, bson.D{}, options.FindOne().SetSort(bson.D{{"$natural", -1}})).Decode(&last)
+	require.NoError(t, err)
+
+	until := last.Map()["ts"].(primitive.Timestamp)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", int32(5)}, {"v", "five"}})
+	require.NoError(t, err)
+
+	restoredDB := db.Client().Database(db.Name() + "_pitr")
+	t.Cleanup(func() {
+		assert.NoError(t, restoredDB.Drop(ctx))
+	})
+
+	err = adminDB.RunCommand(ctx, bson.D{
+		{"restoreBackup", name},
+		{"to", restoredDB.Name()},
+		{"untilClusterTime", primitive.Timestamp{T: start.T - 1}},
+	}).Err()
+	AssertMatchesCommandError(t, mongo.CommandError{Code: 2, Name: "BadValue"}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{
+		{"restoreBackup", name},
+		{"to", restoredDB.Name()},
+		{"untilClusterTime", until},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	expected := bson.D{
+		{"collections", int32(1)},
+		{"documents", int64(3)},
+		{"replayed", int64(3)},
+		{"ok", float64(1)},
+	}
+	AssertEqualDocuments(t, expected, res)
+
+	expectedDocs := []bson.D{
+		{{"_id", int32(1)}, {"v", "updated"}},
+		{{"_id", int32(3)}, {"v", "three"}},
+		{{"_id", int32(4)}, {"v", "four"}},
+	}
+	AssertEqualDocumentsSlice(t, expectedDocs, FindAll(t, ctx, restoredDB.Collection(collection.Name())))
+}
+
+func TestCommandsBackupRestoreErrors(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "createBackup and restoreBackup are FerretDB-specific")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{BackupURL: "file://" + filepath.ToSlash(tt.TempDir())},
+	})
+	ctx, db := s.Ctx, s.Collection.Database()
+	adminDB := db.Client().Database("admin")
+
+	err := adminDB.RunCommand(ctx, bson.D{{"createBackup", db.Name() + "_missing"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    26,
+		Name:    "NamespaceNotFound",
+		Message: "database " + db.Name() + "_missing not found",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"restoreBackup", "missing.bson"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    96,
+		Name:    "OperationFailed",
+		Message: `Object "missing.bson" does not exist`,
+	}, err)
+
+	_, err = db.Collection("test").InsertOne(ctx, bson.D{{"_id", "test"}})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = adminDB.RunCommand(ctx, bson.D{{"createBackup", db.Name()}}).Decode(&res)
+	require.NoError(t, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{
+		{"restoreBackup", res.Map()["name"]},
+		{"to", db.Name() + "_pitr"},
+		{"untilClusterTime", primitive.Timestamp{T: uint32(time.Now().Unix()) + 10}},
+	}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    26,
+		Name:    "NamespaceNotFound",
+		Message: "Point-in-time recovery requires OpLog collection local.oplog.rs",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"restoreBackup", "missing.bson"}, {"untilClusterTime", int32(42)}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    14,
+		Name:    "TypeMismatch",
+		Message: "BSON field 'restoreBackup.untilClusterTime' is the wrong type 'int', expected type 'timestamp'",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"restoreBackup", "missing.bson"}, {"to", int32(42)}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    14,
+		Name:    "TypeMismatch",
+		Message: "BSON field 'restoreBackup.to' is the wrong type 'int', expected type 'string'",
+	}, err)
+}
+
+func TestCommandsBackupNotConfigured(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "createBackup and restoreBackup are FerretDB-specific")
+
+	ctx, collection := setup.Setup(tt)
+	adminDB := collection.Database().Client().Database("admin")
+
+	err := adminDB.RunCommand(ctx, bson.D{{"createBackup", collection.Database().Name()}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    20,
+		Name:    "IllegalOperation",
+		Message: "createBackup requires FerretDB to be started with --backup-url flag",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"restoreBackup", "backup.bson"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    20,
+		Name:    "IllegalOperation",
+		Message: "restoreBackup requires FerretDB to be started with --backup-url flag",
+	}, err)
+}
@@ -48,6 +48,29 @@ func TestCommandsDiagnosticConnectionStatus(t *testing.T) {
 	assert.Equal(t, float64(1), ok)
 }
 
+func TestCommandsDiagnosticConnectionStatusShowPrivileges(t *testing.T) {
+	t.Parallel()
+	ctx, collection := setup.Setup(t)
+
+	var actual bson.D
+	err := collection.Database().RunCommand(ctx, bson.D{{"connectionStatus", 1}, {"showPrivileges", true}}).Decode(&actual)
+	require.NoError(t, err)
+
+	m := actual.Map()
+	assert.Equal(t, float64(1), m["ok"])
+
+	authInfo, ok := m["authInfo"].(bson.D)
+	require.True(t, ok)
+	assert.IsType(t, bson.A{}, authInfo.Map()["authenticatedUserPrivileges"])
+
+	err = collection.Database().RunCommand(ctx, bson.D{{"connectionStatus", 1}}).Decode(&actual)
+	require.NoError(t, err)
+
+	authInfo, ok = actual.Map()["authInfo"].(bson.D)
+	require.True(t, ok)
+	assert.NotContains(t, authInfo.Map(), "authenticatedUserPrivileges")
+}
+
 func TestCommandsDiagnosticExplain(t *testing.T) {
 	t.Parallel()
 	s := setup.SetupWithOpts(t, &setup.SetupOpts{
@@ -270,6 +293,35 @@ func TestCommandsDiagnosticListCommands(t *testing.T) {
 	commands := must.NotFail(actual.Get("commands")).(*types.Document)
 	listCommands := must.NotFail(commands.Get("listCommands")).(*types.Document)
 	assert.NotEmpty(t, must.NotFail(listCommands.Get("help")).(string))
+
+	for name, expected := range map[string]struct {
+		requiresAuth bool
+		secondaryOk  bool
+		adminOnly    bool
+		apiVersions  *types.Array
+	}{
+		"find": {
+			requiresAuth: true,
+			secondaryOk:  true,
+			apiVersions:  must.NotFail(types.NewArray("1")),
+		},
+		"insert": {
+			requiresAuth: true,
+			apiVersions:  must.NotFail(types.NewArray("1")),
+		},
+		"ping": {
+			secondaryOk: true,
+			apiVersions: must.NotFail(types.NewArray("1")),
+		},
+	} {
+		cmd := must.NotFail(commands.Get(name)).(*types.Document)
+
+		assert.Equal(t, expected.requiresAuth, must.NotFail(cmd.Get("requiresAuth")), name)
+		assert.Equal(t, expected.secondaryOk, must.NotFail(cmd.Get("secondaryOk")), name)
+		assert.Equal(t, expected.adminOnly, must.NotFail(cmd.Get("adminOnly")), name)
+		assert.Equal(t, expected.apiVersions, must.NotFail(cmd.Get("apiVersions")), name)
+		assert.Equal(t, must.NotFail(types.NewArray()), must.NotFail(cmd.Get("deprecatedApiVersions")), name)
+	}
 }
 
 func TestCommandsDiagnosticValidate(t *testing.T) {
@@ -0,0 +1,232 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// setupExport setups a collection with documents and FerretDB configured with a temporary export directory.
+func setupExport(tt *testing.T, n int) (*setup.SetupResult, string) {
+	tt.Helper()
+
+	dir := tt.TempDir()
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{ExportURL: "file://" + filepath.ToSlash(dir)},
+	})
+
+	docs := make([]any, n)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", fmt.Sprintf("v%d", i)}}
+	}
+
+	_, err := s.Collection.InsertMany(s.Ctx, docs)
+	require.NoError(tt, err)
+
+	return s, dir
+}
+
+func TestCommandsExportImportCollection(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "exportCollection and importCollection are FerretDB-specific")
+
+	s, dir := setupExport(tt, 2500)
+	ctx, collection := s.Ctx, s.Collection
+	adminDB := collection.Database().Client().Database("admin")
+	ns := collection.Database().Name() + "." + collection.Name()
+
+	var res bson.D
+	err := adminDB.RunCommand(ctx, bson.D{{"exportCollection", ns}, {"to", "dump/coll.bson"}}).Decode(&res)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "dump", "coll.bson"))
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(data)
+	expected := bson.D{
+		{"exported", int64(2500)},
+		{"bytes", int64(len(data))},
+		{"sha256", hex.EncodeToString(sum[:])},
+		{"ok", float64(1)},
+	}
+	AssertEqualDocuments(t, expected, res)
+
+	imported := collection.Database().Collection(collection.Name() + "_imported")
+	importedNS := imported.Database().Name() + "." + imported.Name()
+
+	err = adminDB.RunCommand(ctx, bson.D{{"importCollection", importedNS}, {"from", "dump/coll.bson"}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"imported", int64(2500)}, {"ok", float64(1)}}, res)
+
+	opts := options.Find().SetSort(bson.D{{"_id", 1}})
+
+	cursor, err := collection.Find(ctx, bson.D{}, opts)
+	require.NoError(t, err)
+
+	var expectedDocs []bson.D
+	require.NoError(t, cursor.All(ctx, &expectedDocs))
+
+	cursor, err = imported.Find(ctx, bson.D{}, opts)
+	require.NoError(t, err)
+
+	var actualDocs []bson.D
+	require.NoError(t, cursor.All(ctx, &actualDocs))
+
+	AssertEqualDocumentsSlice(t, expectedDocs, actualDocs)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"importCollection", importedNS}, {"from", "dump/coll.bson"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    11000,
+		Name:    "DuplicateKey",
+		Message: "E11000 duplicate key error collection: " + importedNS,
+	}, err)
+}
+
+func TestCommandsExportImportCollectionErrors(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "exportCollection and importCollection are FerretDB-specific")
+
+	s, dir := setupExport(tt, 10)
+	ctx, collection := s.Ctx, s.Collection
+	adminDB := collection.Database().Client().Database("admin")
+	ns := collection.Database().Name() + "." + collection.Name()
+
+	err := adminDB.RunCommand(ctx, bson.D{{"exportCollection", ns}, {"to", "coll.bson"}}).Err()
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "coll.bson"))
+	require.NoError(t, err)
+
+	corrupted := bytes.Replace(data, []byte("v1\x00"), []byte("x1\x00"), 1)
+	require.NotEqual(t, data, corrupted)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "corrupted.bson"), corrupted, 0o666))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "truncated.bson"), data[:len(data)-10], 0o666))
+
+	for name, tc := range map[string]struct {
+		command  bson.D
+		database string
+		err      mongo.CommandError
+	}{
+		"NotAdmin": {
+			command:  bson.D{{"exportCollection", ns}, {"to", "coll.bson"}},
+			database: collection.Database().Name(),
+			err: mongo.CommandError{
+				Code:    13,
+				Name:    "Unauthorized",
+				Message: "exportCollection may only be run against the admin database.",
+			},
+		},
+		"MissingTo": {
+			command: bson.D{{"exportCollection", ns}},
+			err: mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `required parameter "to" is missing`,
+			},
+		},
+		"InvalidName": {
+			command: bson.D{{"exportCollection", ns}, {"to", "../coll.bson"}},
+			err: mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `Invalid object name "../coll.bson"`,
+			},
+		},
+		"NamespaceNotFound": {
+			command: bson.D{{"exportCollection", ns + "_missing"}, {"to", "missing.bson"}},
+			err: mongo.CommandError{
+				Code:    26,
+				Name:    "NamespaceNotFound",
+				Message: "ns not found " + ns + "_missing",
+			},
+		},
+		"ObjectNotFound": {
+			command: bson.D{{"importCollection", ns + "_missing"}, {"from", "missing.bson"}},
+			err: mongo.CommandError{
+				Code:    96,
+				Name:    "OperationFailed",
+				Message: `Object "missing.bson" does not exist`,
+			},
+		},
+		"Corrupted": {
+			command: bson.D{{"importCollection", ns + "_corrupted"}, {"from", "corrupted.bson"}},
+			err: mongo.CommandError{
+				Code:    96,
+				Name:    "OperationFailed",
+				Message: `Failed to import "corrupted.bson" after 0 documents: invalid export stream: checksum mismatch for documents 1-10`,
+			},
+		},
+		"Truncated": {
+			command: bson.D{{"importCollection", ns + "_truncated"}, {"from", "truncated.bson"}},
+			err: mongo.CommandError{
+				Code:    96,
+				Name:    "OperationFailed",
+				Message: `Failed to import "truncated.bson" after 10 documents: invalid export stream: unexpected end of stream`,
+			},
+		},
+	} {
+		tt.Run(name, func(tt *testing.T) {
+			t := setup.FailsForMongoDB(tt, "exportCollection and importCollection are FerretDB-specific")
+
+			db := adminDB
+			if tc.database != "" {
+				db = collection.Database().Client().Database(tc.database)
+			}
+
+			err := db.RunCommand(ctx, tc.command).Err()
+			AssertEqualCommandError(t, tc.err, err)
+		})
+	}
+}
+
+func TestCommandsExportImportCollectionNotConfigured(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "exportCollection and importCollection are FerretDB-specific")
+
+	ctx, collection := setup.Setup(tt)
+	adminDB := collection.Database().Client().Database("admin")
+	ns := collection.Database().Name() + "." + collection.Name()
+
+	err := adminDB.RunCommand(ctx, bson.D{{"exportCollection", ns}, {"to", "coll.bson"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    20,
+		Name:    "IllegalOperation",
+		Message: "exportCollection requires FerretDB to be started with --export-url flag",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"importCollection", ns}, {"from", "coll.bson"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    20,
+		Name:    "IllegalOperation",
+		Message: "importCollection requires FerretDB to be started with --export-url flag",
+	}, err)
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestCommandsFerretDBFeatures(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "ferretdbFeatures is FerretDB-specific")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"ferretdbFeatures", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	m := res.Map()
+	assert.Equal(t, float64(1), m["ok"])
+
+	// features returns features of the given kind by name
+	features := func(kind string) map[string]bson.M {
+		arr, ok := m[kind].(bson.A)
+		require.True(t, ok, kind)
+		require.NotEmpty(t, arr, kind)
+
+		res := make(map[string]bson.M, len(arr))
+
+		for _, v := range arr {
+			f := v.(bson.D).Map()
+			res[f["name"].(string)] = f
+		}
+
+		return res
+	}
+
+	stages := features("stages")
+	assert.Equal(t, bson.M{"name": "$match", "status": "full"}, stages["$match"])
+	assert.Equal(t, "partial", stages["$group"]["status"])
+	assert.Equal(t, "partial", stages["$lookup"]["status"])
+	assert.Equal(t, "unsupported", stages["$graphLookup"]["status"])
+	assert.NotEmpty(t, stages["$graphLookup"]["issue"])
+
+	operators := features("aggregationOperators")
+	assert.Equal(t, "full", operators["$sum"]["status"])
+	assert.Equal(t, "unsupported", operators["$concat"]["status"])
+
+	query := features("queryOperators")
+	assert.Equal(t, "full", query["$eq"]["status"])
+	assert.Equal(t, "partial", query["$elemMatch"]["status"])
+	assert.Equal(t, "https://github.com/FerretDB/FerretDB/issues/730", query["$elemMatch"]["issue"])
+	assert.Equal(t, "unsupported", query["$where"]["status"])
+
+	update := features("updateOperators")
+	assert.Equal(t, "full", update["$set"]["status"])
+
+	indexTypes := features("indexTypes")
+	assert.Equal(t, "full", indexTypes["compound"]["status"])
+	assert.Equal(t, "unsupported", indexTypes["text"]["status"])
+}
+
+func TestCommandsFerretDBFeaturesNonAdmin(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "ferretdbFeatures is FerretDB-specific")
+
+	ctx, collection := setup.Setup(tt)
+
+	err := collection.Database().RunCommand(ctx, bson.D{{"ferretdbFeatures", 1}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    13,
+		Name:    "Unauthorized",
+		Message: "ferretdbFeatures may only be run against the admin database.",
+	}, err)
+}
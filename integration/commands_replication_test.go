@@ -22,6 +22,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 
@@ -83,3 +85,57 @@ func TestCommandsReplication(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandsReplicationAppendOplogNote(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	adminDB := collection.Database().Client().Database("admin")
+	local := collection.Database().Client().Database("local")
+
+	// OpLog collection could be already created by other tests
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(1024 * 1024)
+	if err := local.CreateCollection(ctx, "oplog.rs", opts); err != nil {
+		AssertMatchesCommandError(t, mongo.CommandError{Code: 48, Name: "NamespaceExists"}, err)
+	}
+
+	data := bson.D{{"msg", t.Name()}}
+	maxClusterTime := primitive.Timestamp{T: uint32(time.Now().Unix()) + 1, I: 1}
+
+	var res bson.D
+	err := adminDB.RunCommand(ctx, bson.D{
+		{"appendOplogNote", int32(1)},
+		{"data", data},
+		{"maxClusterTime", maxClusterTime},
+	}).Decode(&res)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), res.Map()["ok"])
+
+	var note bson.D
+	err = local.Collection("oplog.rs").FindOne(ctx, bson.D{{"op", "n"}, {"o", data}}).Decode(&note)
+	require.NoError(t, err)
+
+	ts := note.Map()["ts"].(primitive.Timestamp)
+	assert.True(t, ts.After(maxClusterTime), "%v is not after %v", ts, maxClusterTime)
+
+	err = adminDB.RunCommand(ctx, bson.D{
+		{"appendOplogNote", int32(1)},
+		{"data", data},
+		{"maxClusterTime", maxClusterTime},
+	}).Err()
+	AssertMatchesCommandError(t, mongo.CommandError{Code: 209, Name: "StaleClusterTime"}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"appendOplogNote", int32(1)}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    40414,
+		Name:    "Location40414",
+		Message: "BSON field 'appendOplogNote.data' is missing but a required field",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"appendOplogNote", int32(1)}, {"data", "note"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    14,
+		Name:    "TypeMismatch",
+		Message: "BSON field 'appendOplogNote.data' is the wrong type 'string', expected type 'object'",
+	}, err)
+}
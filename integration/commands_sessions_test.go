@@ -0,0 +1,249 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// setupSessionCursor inserts documents and returns a cursor opened in a new explicit session
+// with the session ID document.
+func setupSessionCursor(t *testing.T, s *setup.SetupResult) (*mongo.Cursor, bson.Raw) {
+	t.Helper()
+
+	_, err := s.Collection.InsertMany(s.Ctx, []any{bson.D{{"_id", 1}}, bson.D{{"_id", 2}}, bson.D{{"_id", 3}}})
+	require.NoError(t, err)
+
+	sess, err := s.Collection.Database().Client().StartSession()
+	require.NoError(t, err)
+	t.Cleanup(func() { sess.EndSession(s.Ctx) })
+
+	cursor, err := s.Collection.Find(
+		mongo.NewSessionContext(s.Ctx, sess),
+		bson.D{},
+		options.Find().SetBatchSize(1).SetSort(bson.D{{"_id", 1}}),
+	)
+	require.NoError(t, err)
+
+	return cursor, sess.ID()
+}
+
+func TestCommandsSessionsStartSession(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"startSession", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	m := res.Map()
+	assert.Equal(t, float64(1), m["ok"])
+	assert.Equal(t, int32(30), m["timeoutMinutes"])
+
+	id, ok := m["id"].(bson.D)
+	require.True(t, ok)
+
+	uuid, ok := id.Map()["id"].(primitive.Binary)
+	require.True(t, ok)
+	assert.Equal(t, byte(4), uuid.Subtype)
+	assert.Len(t, uuid.Data, 16)
+
+	err = s.Collection.Database().RunCommand(s.Ctx, bson.D{{"refreshSessions", bson.A{id}}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+
+	err = s.Collection.Database().RunCommand(s.Ctx, bson.D{{"endSessions", bson.A{id}}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+}
+
+func TestCommandsSessionsEndSessionsBatch(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	// drivers send up to 10 000 sessions at once
+	ids := make(bson.A, 10_000)
+	for i := range ids {
+		ids[i] = bson.D{{"id", primitive.Binary{Subtype: 4, Data: []byte(fmt.Sprintf("session%09d", i))}}}
+	}
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"endSessions", ids}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+}
+
+func TestCommandsSessionsKillSessions(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	cursor, id := setupSessionCursor(t, s)
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"killSessions", bson.A{id}}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+
+	// the first batch is already fetched
+	require.True(t, cursor.Next(s.Ctx))
+	require.False(t, cursor.Next(s.Ctx))
+
+	var ce mongo.CommandError
+	require.ErrorAs(t, cursor.Err(), &ce)
+	assert.Equal(t, int32(43), ce.Code)
+}
+
+func TestCommandsSessionsKillAllSessions(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	cursor, id := setupSessionCursor(t, s)
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"killAllSessions", bson.A{
+		bson.D{{"user", "no-such-user"}, {"db", "admin"}},
+	}}}).Decode(&res)
+
+	if setup.IsMongoDB(t) {
+		require.NoError(t, err)
+		AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+	} else {
+		// FerretDB test user is not the administrative user and can kill only its own sessions
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    13,
+			Name:    "Unauthorized",
+			Message: "not authorized to kill sessions of user no-such-user@admin",
+		}, err)
+	}
+
+	// cursors of other users are not affected
+	require.True(t, cursor.Next(s.Ctx))
+	require.True(t, cursor.Next(s.Ctx))
+
+	t.Run("EndSessions", func(tt *testing.T) {
+		t := setup.FailsForMongoDB(tt, "endSessions does not kill cursors immediately")
+
+		err = s.Collection.Database().RunCommand(s.Ctx, bson.D{{"endSessions", bson.A{id}}}).Decode(&res)
+		require.NoError(t, err)
+		AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+
+		require.False(t, cursor.Next(s.Ctx))
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, cursor.Err(), &ce)
+		assert.Equal(t, int32(43), ce.Code)
+	})
+}
+
+func TestCommandsSessionsErrors(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	for name, tc := range map[string]struct {
+		command bson.D
+		err     *mongo.CommandError
+	}{
+		"KillSessionsNotArray": {
+			command: bson.D{{"killSessions", "foo"}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field 'killSessions' is the wrong type, expected type 'array'",
+			},
+		},
+		"RefreshSessionsMissingID": {
+			command: bson.D{{"refreshSessions", bson.A{bson.D{}}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field 'refreshSessions.id' is missing but a required field",
+			},
+		},
+		"EndSessionsWrongIDType": {
+			command: bson.D{{"endSessions", bson.A{bson.D{{"id", "foo"}}}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field 'endSessions.id' is the wrong type 'string', expected type 'binData'",
+			},
+		},
+	} {
+		t.Run(name, func(tt *testing.T) {
+			tt.Parallel()
+
+			t := setup.FailsForMongoDB(tt, "error messages are different")
+
+			err := s.Collection.Database().RunCommand(s.Ctx, tc.command).Err()
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
+
+//nolint:paralleltest // we change a global parameter
+func TestCommandsSessionsSetParameter(tt *testing.T) {
+	t := setup.FailsForMongoDB(tt, "localLogicalSessionTimeoutMinutes can't be set at runtime")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+	db := s.Collection.Database()
+
+	var res bson.D
+	err := db.RunCommand(s.Ctx, bson.D{{"setParameter", 1}, {"localLogicalSessionTimeoutMinutes", int32(31)}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"was", int32(30)}, {"ok", float64(1)}}, res)
+
+	t.Cleanup(func() {
+		err = db.RunCommand(s.Ctx, bson.D{{"setParameter", 1}, {"localLogicalSessionTimeoutMinutes", int32(30)}}).Err()
+		require.NoError(t, err)
+	})
+
+	err = db.RunCommand(s.Ctx, bson.D{{"hello", 1}}).Decode(&res)
+	require.NoError(t, err)
+	assert.Equal(t, int32(31), res.Map()["logicalSessionTimeoutMinutes"])
+
+	err = db.RunCommand(s.Ctx, bson.D{{"getParameter", 1}, {"localLogicalSessionTimeoutMinutes", 1}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"localLogicalSessionTimeoutMinutes", int32(31)}, {"ok", float64(1)}}, res)
+
+	err = db.RunCommand(s.Ctx, bson.D{{"setParameter", 1}, {"noSuchParameter", 1}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    72,
+		Name:    "InvalidOptions",
+		Message: "attempted to set unrecognized parameter [noSuchParameter], use help:true to see options ",
+	}, err)
+
+	err = db.RunCommand(s.Ctx, bson.D{{"setParameter", 1}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    72,
+		Name:    "InvalidOptions",
+		Message: "no option found to set, use help:true to see options ",
+	}, err)
+}
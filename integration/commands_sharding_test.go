@@ -0,0 +1,161 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestCommandsShardingListShards(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "listShards is only supported by mongos")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"listShards", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	m := res.Map()
+	assert.Equal(t, float64(1), m["ok"])
+
+	shards, ok := m["shards"].(bson.A)
+	require.True(t, ok)
+	require.Len(t, shards, 1)
+
+	shard := shards[0].(bson.D).Map()
+	assert.NotEmpty(t, shard["_id"])
+	assert.IsType(t, "", shard["host"])
+	assert.Equal(t, int32(1), shard["state"])
+}
+
+func TestCommandsShardingGetShardMap(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "getShardMap is only supported by mongos")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	var res bson.D
+	err := s.Collection.Database().RunCommand(s.Ctx, bson.D{{"getShardMap", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	keys := CollectKeys(t, res)
+	assert.Equal(t, []string{"map", "hosts", "connStrings", "ok"}, keys)
+}
+
+func TestCommandsShardingShardCollection(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "shardCollection is only supported by mongos")
+
+	ctx, collection := setup.Setup(tt)
+	ns := collection.Database().Name() + "." + collection.Name()
+	adminDB := collection.Database().Client().Database("admin")
+
+	var res bson.D
+	err := adminDB.RunCommand(ctx, bson.D{{"enableSharding", collection.Database().Name()}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"ok", float64(1)}}, res)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"shardCollection", ns}, {"key", bson.D{{"v", "hashed"}}}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"collectionsharded", ns}, {"ok", float64(1)}}, res)
+
+	names, err := collection.Database().ListCollectionNames(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Contains(t, names, collection.Name())
+}
+
+func TestCommandsShardingErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	ns := collection.Database().Name() + "." + collection.Name()
+
+	for name, tc := range map[string]struct {
+		dbName  string
+		command bson.D
+
+		err             *mongo.CommandError // required
+		failsForMongoDB string              // optional, expected failure reason for MongoDB
+	}{
+		"NotAdmin": {
+			dbName:  collection.Database().Name(),
+			command: bson.D{{"listShards", 1}},
+			err: &mongo.CommandError{
+				Code:    13,
+				Name:    "Unauthorized",
+				Message: "listShards may only be run against the admin database.",
+			},
+			failsForMongoDB: "listShards is only supported by mongos",
+		},
+		"MissingKey": {
+			dbName:  "admin",
+			command: bson.D{{"shardCollection", ns}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field 'shardCollection.key' is missing but a required field",
+			},
+			failsForMongoDB: "shardCollection is only supported by mongos",
+		},
+		"InvalidKey": {
+			dbName:  "admin",
+			command: bson.D{{"shardCollection", ns}, {"key", bson.D{{"v", -1}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `Shard key { v: -1 } must have values of 1 or "hashed"`,
+			},
+			failsForMongoDB: "shardCollection is only supported by mongos",
+		},
+		"InvalidNamespace": {
+			dbName:  "admin",
+			command: bson.D{{"shardCollection", "invalid"}, {"key", bson.D{{"v", 1}}}},
+			err: &mongo.CommandError{
+				Code:    73,
+				Name:    "InvalidNamespace",
+				Message: "Invalid namespace specified 'invalid'",
+			},
+			failsForMongoDB: "shardCollection is only supported by mongos",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(tt *testing.T) {
+			tt.Parallel()
+
+			t := setup.FailsForMongoDB(tt, tc.failsForMongoDB)
+
+			require.NotNil(t, tc.err, "err must not be nil")
+
+			var res bson.D
+			err := collection.Database().Client().Database(tc.dbName).RunCommand(ctx, tc.command).Decode(&res)
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/FerretDB/FerretDB/internal/util/must"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestCountCommand(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+	ctx, coll := s.Ctx, s.Collection
+
+	_, err := coll.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"v", "foo"}},
+		bson.D{{"_id", 2}, {"v", "bar"}},
+		bson.D{{"_id", 3}, {"v", "foo"}},
+		bson.D{{"_id", 4}, {"v", "bar"}},
+		bson.D{{"_id", 5}, {"v", "foo"}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		command  bson.D
+		expected int32
+	}{
+		"Empty": {
+			command:  bson.D{},
+			expected: 5,
+		},
+		"EmptyQuery": {
+			command:  bson.D{{"query", bson.D{}}},
+			expected: 5,
+		},
+		"QueryComment": {
+			command:  bson.D{{"query", bson.D{{"$comment", "count"}}}},
+			expected: 5,
+		},
+		"Skip": {
+			command:  bson.D{{"skip", 2}},
+			expected: 3,
+		},
+		"SkipAll": {
+			command:  bson.D{{"skip", 10}},
+			expected: 0,
+		},
+		"Limit": {
+			command:  bson.D{{"limit", 2}},
+			expected: 2,
+		},
+		"LimitNegative": {
+			command:  bson.D{{"limit", -2}},
+			expected: 2,
+		},
+		"LimitNegativeDouble": {
+			command:  bson.D{{"limit", -3.5}},
+			expected: 3,
+		},
+		"SkipLimit": {
+			command:  bson.D{{"skip", 4}, {"limit", 3}},
+			expected: 1,
+		},
+		"Query": {
+			command:  bson.D{{"query", bson.D{{"v", "foo"}}}},
+			expected: 3,
+		},
+		"QuerySkipLimit": {
+			command:  bson.D{{"query", bson.D{{"v", "foo"}}}, {"skip", 1}, {"limit", 1}},
+			expected: 1,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var res bson.D
+			err := coll.Database().RunCommand(ctx, append(bson.D{{"count", coll.Name()}}, tc.command...)).Decode(&res)
+			require.NoError(t, err)
+
+			doc := ConvertDocument(t, res)
+			assert.Equal(t, tc.expected, must.NotFail(doc.Get("n")))
+		})
+	}
+}
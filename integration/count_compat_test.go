@@ -172,6 +172,10 @@ func TestCountCompat(t *testing.T) {
 			filter: bson.D{},
 			limit:  int64(len(shareddata.Strings.Docs()) + 1),
 		},
+		"LimitNegative": {
+			filter: bson.D{},
+			limit:  -2,
+		},
 
 		"SkipSimple": {
 			filter:  bson.D{},
@@ -353,3 +353,345 @@ func TestCreateCappedCommandInvalidSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestCreatePartitioned(t *testing.T) {
+	setup.SkipForMongoDB(t, "Partitioned collections are FerretDB-specific")
+
+	t.Parallel()
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		command bson.D
+	}{
+		"Hashed": {
+			command: bson.D{{"partitionKey", bson.D{{"v", "hashed"}}}, {"partitions", 4}},
+		},
+		"Ranged": {
+			command: bson.D{{"partitionKey", bson.D{{"v", 1}}}, {"partitionBounds", bson.A{10, 20}}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, collection := setup.Setup(t)
+
+			command := append(bson.D{{"create", collection.Name()}}, tc.command...)
+			err := collection.Database().RunCommand(ctx, command).Err()
+
+			if !setup.IsPostgreSQL(t) {
+				AssertEqualCommandError(t, mongo.CommandError{
+					Code:    238,
+					Name:    "NotImplemented",
+					Message: "Partitioned collections are not supported by this backend",
+				}, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			_, err = collection.InsertMany(ctx, []any{
+				bson.D{{"_id", 1}, {"v", 5}},
+				bson.D{{"_id", 2}, {"v", 15}},
+				bson.D{{"_id", 3}, {"v", "foo"}},
+				bson.D{{"_id", 4}},
+			})
+			require.NoError(t, err)
+
+			var doc bson.D
+			err = collection.FindOne(ctx, bson.D{{"v", 15}}).Decode(&doc)
+			require.NoError(t, err)
+			assert.Equal(t, bson.D{{"_id", int32(2)}, {"v", int32(15)}}, doc)
+
+			// moves the document to another partition
+			_, err = collection.UpdateOne(ctx, bson.D{{"_id", 2}}, bson.D{{"$set", bson.D{{"v", 25}}}})
+			require.NoError(t, err)
+
+			err = collection.FindOne(ctx, bson.D{{"v", 25}}).Decode(&doc)
+			require.NoError(t, err)
+			assert.Equal(t, bson.D{{"_id", int32(2)}, {"v", int32(25)}}, doc)
+
+			_, err = collection.InsertOne(ctx, bson.D{{"_id", 5}, {"v", bson.A{1, 2}}})
+			require.Error(t, err)
+
+			n, err := collection.CountDocuments(ctx, bson.D{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), n)
+		})
+	}
+}
+
+func TestCreatePartitionedInvalidSpec(t *testing.T) {
+	setup.SkipForMongoDB(t, "Partitioned collections are FerretDB-specific")
+
+	t.Parallel()
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		command bson.D
+		err     *mongo.CommandError
+	}{
+		"WrongKeyType": {
+			command: bson.D{{"partitionKey", "v"}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field 'create.partitionKey' is the wrong type 'string', expected type 'object'",
+			},
+		},
+		"EmptyKey": {
+			command: bson.D{{"partitionKey", bson.D{}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Shard key cannot be empty",
+			},
+		},
+		"CompoundKey": {
+			command: bson.D{{"partitionKey", bson.D{{"a", 1}, {"b", 1}}}},
+			err: &mongo.CommandError{
+				Code:    238,
+				Name:    "NotImplemented",
+				Message: "Compound partition keys are not supported",
+			},
+		},
+		"DottedKey": {
+			command: bson.D{{"partitionKey", bson.D{{"a.b", 1}}}},
+			err: &mongo.CommandError{
+				Code:    238,
+				Name:    "NotImplemented",
+				Message: "Partition key must be a top-level field",
+			},
+		},
+		"Capped": {
+			command: bson.D{{"capped", true}, {"size", 1000}, {"partitionKey", bson.D{{"v", 1}}}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "Capped collections cannot be partitioned",
+			},
+		},
+		"PartitionsWithoutKey": {
+			command: bson.D{{"partitions", 4}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "the 'partitionKey' field is required when 'partitions' or 'partitionBounds' is set",
+			},
+		},
+		"BoundsForHashed": {
+			command: bson.D{{"partitionKey", bson.D{{"v", "hashed"}}}, {"partitionBounds", bson.A{1}}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "the 'partitionBounds' field can't be used with hashed partition key",
+			},
+		},
+		"PartitionsForRanged": {
+			command: bson.D{{"partitionKey", bson.D{{"v", 1}}}, {"partitions", 4}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "the 'partitions' field can't be used with ranged partition key",
+			},
+		},
+		"TooManyPartitions": {
+			command: bson.D{{"partitionKey", bson.D{{"v", "hashed"}}}, {"partitions", 2000}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "the 'partitions' field must not be greater than 1024",
+			},
+		},
+		"WrongBoundType": {
+			command: bson.D{{"partitionKey", bson.D{{"v", 1}}}, {"partitionBounds", bson.A{true}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "the 'partitionBounds' values must be finite numbers or strings",
+			},
+		},
+		"UnorderedBounds": {
+			command: bson.D{{"partitionKey", bson.D{{"v", 1}}}, {"partitionBounds", bson.A{20, 10}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "the 'partitionBounds' values must be of the same type and in strictly ascending order",
+			},
+		},
+		"MixedBounds": {
+			command: bson.D{{"partitionKey", bson.D{{"v", 1}}}, {"partitionBounds", bson.A{10, "foo"}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "the 'partitionBounds' values must be of the same type and in strictly ascending order",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, collection := setup.Setup(t)
+
+			command := append(bson.D{{"create", collection.Name()}}, tc.command...)
+
+			var res bson.D
+			err := collection.Database().RunCommand(ctx, command).Decode(&res)
+			AssertEqualCommandError(t, *tc.err, err)
+			require.Nil(t, res)
+		})
+	}
+}
+
+func TestCreateStorageEngine(t *testing.T) {
+	setup.SkipForMongoDB(t, "PostgreSQL storage options are FerretDB-specific")
+
+	t.Parallel()
+
+	for name, tc := range map[string]struct { //nolint:vet // used for testing only
+		storageEngine any
+		err           *mongo.CommandError // if nil, the collection should be created
+	}{
+		"OtherEngine": {
+			storageEngine: bson.D{{"wiredTiger", bson.D{{"configString", "block_compressor=zstd"}}}},
+		},
+		"PostgreSQL": {
+			storageEngine: bson.D{{"postgresql", bson.D{
+				{"fillfactor", 70},
+				{"toastCompression", "pglz"},
+				{"tablespace", "pg_default"},
+			}}},
+		},
+		"WrongType": {
+			storageEngine: "postgresql",
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "BSON field 'create.storageEngine' is the wrong type 'string', expected type 'object'",
+			},
+		},
+		"WrongEngineType": {
+			storageEngine: bson.D{{"wiredTiger", "foo"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "'storageEngine.wiredTiger' has to be an embedded document.",
+			},
+		},
+		"FillFactor": {
+			storageEngine: bson.D{{"postgresql", bson.D{{"fillfactor", 5}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "'storageEngine.postgresql.fillfactor' must be a whole number between 10 and 100, got 5",
+			},
+		},
+		"ToastCompression": {
+			storageEngine: bson.D{{"postgresql", bson.D{{"toastCompression", "zstd"}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `'storageEngine.postgresql.toastCompression' must be "pglz" or "lz4", got "zstd"`,
+			},
+		},
+		"Tablespace": {
+			storageEngine: bson.D{{"postgresql", bson.D{{"tablespace", ""}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `'storageEngine.postgresql.tablespace' must be a non-empty string, got ""`,
+			},
+		},
+		"UnknownOption": {
+			storageEngine: bson.D{{"postgresql", bson.D{{"autovacuum_enabled", false}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Unknown storage option 'storageEngine.postgresql.autovacuum_enabled'",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, collection := setup.Setup(t)
+
+			command := bson.D{{"create", collection.Name()}, {"storageEngine", tc.storageEngine}}
+			err := collection.Database().RunCommand(ctx, command).Err()
+
+			if tc.err != nil {
+				AssertEqualCommandError(t, *tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			_, err = collection.InsertOne(ctx, bson.D{{"_id", "foo"}})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateVersioned(t *testing.T) {
+	setup.SkipForMongoDB(t, "Versioned collections are FerretDB-specific")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	err := db.RunCommand(ctx, bson.D{{"create", collection.Name()}, {"versioned", "yes"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    14,
+		Name:    "TypeMismatch",
+		Message: "BSON field 'versioned' is the wrong type 'string', expected types '[bool, long, int, decimal, double]'",
+	}, err)
+
+	err = db.RunCommand(ctx, bson.D{{"create", collection.Name()}, {"versioned", true}}).Err()
+	require.NoError(t, err)
+
+	specs, err := db.ListCollectionSpecifications(ctx, bson.D{{"name", collection.Name()}})
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, `{"versioned": true}`, specs[0].Options.String())
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", 1}, {"v", "a"}, {"_version", int64(42)}})
+	require.NoError(t, err)
+
+	var doc bson.D
+	require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", 1}}).Decode(&doc))
+	AssertEqualDocuments(t, bson.D{{"_id", int32(1)}, {"v", "a"}, {"_version", int64(1)}}, doc)
+
+	res, err := collection.UpdateOne(ctx, bson.D{{"_id", 1}, {"_version", 1}}, bson.D{{"$set", bson.D{{"v", "b"}}}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.ModifiedCount)
+
+	// filter by the stale version does not match
+	res, err = collection.UpdateOne(ctx, bson.D{{"_id", 1}, {"_version", 1}}, bson.D{{"$set", bson.D{{"v", "c"}}}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.MatchedCount)
+
+	// replacement with the stale version is rejected
+	_, err = collection.ReplaceOne(ctx, bson.D{{"_id", 1}}, bson.D{{"v", "c"}, {"_version", int64(1)}})
+	AssertEqualWriteError(t, mongo.WriteError{
+		Code:    112,
+		Message: "Document version 1 is stale, the current version is 2",
+	}, err)
+
+	// replacement with the current version is accepted
+	_, err = collection.ReplaceOne(ctx, bson.D{{"_id", 1}}, bson.D{{"v", "c"}, {"_version", int64(2)}})
+	require.NoError(t, err)
+
+	err = collection.FindOneAndUpdate(
+		ctx,
+		bson.D{{"_id", 1}},
+		bson.D{{"$set", bson.D{{"v", "d"}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&doc)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"_id", int32(1)}, {"v", "d"}, {"_version", int64(4)}}, doc)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", 2}}, bson.D{{"$set", bson.D{{"v", "a"}}}}, options.Update().SetUpsert(true))
+	require.NoError(t, err)
+
+	require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", 2}}).Decode(&doc))
+	AssertEqualDocuments(t, bson.D{{"_id", int32(2)}, {"v", "a"}, {"_version", int64(1)}}, doc)
+}
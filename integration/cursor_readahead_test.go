@@ -0,0 +1,80 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestCursorReadahead(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		// small enough to stop prefetching before the whole batch
+		BackendOptions: &setup.BackendOpts{CursorReadaheadSize: 1 << 10},
+	})
+	ctx, coll := s.Ctx, s.Collection
+
+	docs := make([]any, 200)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", "foo"}}
+	}
+
+	_, err := coll.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	t.Run("Find", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.Find().SetBatchSize(7).SetSort(bson.D{{"_id", 1}})
+
+		cursor, err := coll.Find(ctx, bson.D{}, opts)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		require.Len(t, res, len(docs))
+
+		for i, doc := range res {
+			assert.Equal(t, int32(i), doc.Map()["_id"])
+		}
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.Aggregate().SetBatchSize(7)
+
+		cursor, err := coll.Aggregate(ctx, bson.A{bson.D{{"$sort", bson.D{{"_id", 1}}}}}, opts)
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+
+		require.Len(t, res, len(docs))
+
+		for i, doc := range res {
+			assert.Equal(t, int32(i), doc.Map()["_id"])
+		}
+	})
+}
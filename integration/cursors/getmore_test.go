@@ -890,6 +890,107 @@ func TestCursorsGetMoreExhausted(t *testing.T) {
 	integration.AssertEqualCommandError(t, expectedErr, err)
 }
 
+func TestCursorsGetMoreLimit(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+
+	collection := s.Collection
+	db, ctx := collection.Database(), s.Ctx
+
+	arr, _ := integration.GenerateDocuments(0, 10)
+
+	_, err := collection.InsertMany(ctx, arr)
+	require.NoError(t, err)
+
+	t.Run("LimitReachedInGetMore", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"limit", 4},
+			{"batchSize", 2},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		firstBatch, cursorID := getFirstBatch(t, res)
+		require.Equal(t, 2, firstBatch.Len())
+		require.NotZero(t, cursorID)
+
+		err = db.RunCommand(ctx, bson.D{
+			{"getMore", cursorID},
+			{"collection", collection.Name()},
+			{"batchSize", 2},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		nextBatch, nextID := getNextBatch(t, res)
+		require.Equal(t, 2, nextBatch.Len())
+		assert.Equal(t, int64(0), nextID)
+
+		err = db.RunCommand(ctx, bson.D{
+			{"getMore", cursorID},
+			{"collection", collection.Name()},
+		}).Err()
+
+		expectedErr := mongo.CommandError{
+			Code:    43,
+			Name:    "CursorNotFound",
+			Message: fmt.Sprintf("cursor id %d not found", cursorID),
+		}
+		integration.AssertEqualCommandError(t, expectedErr, err)
+	})
+
+	t.Run("LimitReachedInFirstBatch", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"limit", 2},
+			{"batchSize", 2},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		firstBatch, cursorID := getFirstBatch(t, res)
+		require.Equal(t, 2, firstBatch.Len())
+		assert.Equal(t, int64(0), cursorID)
+	})
+
+	t.Run("SingleBatch", func(t *testing.T) {
+		t.Parallel()
+
+		var res bson.D
+		err := db.RunCommand(ctx, bson.D{
+			{"find", collection.Name()},
+			{"limit", 4},
+			{"batchSize", 2},
+			{"singleBatch", true},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		firstBatch, cursorID := getFirstBatch(t, res)
+		require.Equal(t, 2, firstBatch.Len())
+		assert.Equal(t, int64(0), cursorID)
+	})
+
+	t.Run("DriverNegativeLimit", func(t *testing.T) {
+		t.Parallel()
+
+		// the driver sends negative limit as positive limit with singleBatch
+		cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetLimit(-3).SetBatchSize(2))
+		require.NoError(t, err)
+
+		defer cursor.Close(ctx)
+
+		var docs []bson.D
+		require.NoError(t, cursor.All(ctx, &docs))
+		assert.Len(t, docs, 2)
+		assert.Equal(t, int64(0), cursor.ID())
+	})
+}
+
 func TestCursorsGetMoreCommandMaxTimeMSCursor(t *testing.T) {
 	// do not run tests in parallel to avoid using too many backend connections
 
@@ -1309,3 +1410,72 @@ func TestCursorsGetMoreAfterInsertion(t *testing.T) {
 		)
 	})
 }
+
+func TestCursorsGetMoreAggregatePipeline(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	docs := make([]any, 20)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", bson.A{int32(i), int32(i + 100)}}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	var expected []bson.D
+	for i := 5; i < 20; i++ {
+		expected = append(expected, bson.D{{"_id", int32(i)}, {"v", int32(i)}}, bson.D{{"_id", int32(i)}, {"v", int32(i + 100)}})
+	}
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A
+		ordered  bool
+	}{
+		"Streaming": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", bson.D{{"$gte", 5}}}}}},
+				bson.D{{"$unwind", "$v"}},
+				bson.D{{"$addFields", bson.D{{"extra", true}}}},
+				bson.D{{"$project", bson.D{{"extra", 0}}}},
+				bson.D{{"$skip", 0}},
+				bson.D{{"$limit", 100}},
+			},
+		},
+		"Blocking": {
+			pipeline: bson.A{
+				bson.D{{"$unwind", "$v"}},
+				bson.D{{"$match", bson.D{{"_id", bson.D{{"$gte", 5}}}}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}, {"v", 1}}}},
+				bson.D{{"$project", bson.D{{"v", 1}}}},
+			},
+			ordered: true,
+		},
+	} {
+		name, tc := name, tc
+
+		for _, batchSize := range []int32{1, 2, 7} {
+			batchSize := batchSize
+
+			t.Run(fmt.Sprintf("%s/BatchSize%d", name, batchSize), func(t *testing.T) {
+				t.Parallel()
+
+				cursor, err := collection.Aggregate(ctx, tc.pipeline, options.Aggregate().SetBatchSize(batchSize))
+				require.NoError(t, err)
+
+				assert.LessOrEqual(t, cursor.RemainingBatchLength(), int(batchSize))
+
+				var res []bson.D
+				require.NoError(t, cursor.All(ctx, &res))
+
+				if tc.ordered {
+					integration.AssertEqualDocumentsSlice(t, expected, res)
+					return
+				}
+
+				assert.ElementsMatch(t, expected, res)
+			})
+		}
+	}
+}
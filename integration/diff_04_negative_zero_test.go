@@ -20,8 +20,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 func TestDiffNegativeZero(t *testing.T) {
@@ -80,3 +83,55 @@ func TestDiffNegativeZero(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffNegativeZeroDecimal128(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		insert bson.D
+		update bson.D
+		filter bson.D
+	}{
+		"Insert": {
+			insert: bson.D{{"_id", "1"}, {"v", must.NotFail(primitive.ParseDecimal128("-0.00"))}},
+			filter: bson.D{{"_id", "1"}},
+		},
+		"UpdateNegativeMulZero": {
+			insert: bson.D{{"_id", "negative"}, {"v", must.NotFail(primitive.ParseDecimal128("-1.0"))}},
+			update: bson.D{{"$mul", bson.D{{"v", must.NotFail(primitive.ParseDecimal128("0"))}}}},
+			filter: bson.D{{"_id", "negative"}},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.InsertOne(ctx, tc.insert)
+			require.NoError(t, err)
+
+			if tc.update != nil {
+				_, err = collection.UpdateOne(ctx, tc.filter, tc.update)
+				require.NoError(t, err)
+			}
+
+			var res bson.D
+			err = collection.FindOne(ctx, tc.filter).Decode(&res)
+			require.NoError(t, err)
+
+			doc := ConvertDocument(t, res)
+			v, _ := doc.Get("v")
+			actual, ok := v.(types.Decimal128)
+			require.True(t, ok)
+			require.True(t, actual.IsZero())
+
+			if setup.IsMongoDB(t) {
+				require.True(t, actual.Signbit())
+				return
+			}
+
+			require.False(t, actual.Signbit())
+		})
+	}
+}
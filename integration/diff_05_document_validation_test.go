@@ -21,11 +21,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 func TestDiffDocumentValidation(t *testing.T) {
@@ -71,6 +73,27 @@ func TestDiffDocumentValidation(t *testing.T) {
 					Message: `invalid value: { "foo": -Inf } (infinity values are not allowed)`,
 				}}},
 			},
+			"Decimal128Infinity": {
+				doc: bson.D{{"foo", must.NotFail(primitive.ParseDecimal128("Infinity"))}},
+				err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+					Code:    2,
+					Message: `invalid value: { "foo": Infinity } (infinity values are not allowed)`,
+				}}},
+			},
+			"Decimal128NegativeInfinity": {
+				doc: bson.D{{"foo", must.NotFail(primitive.ParseDecimal128("-Infinity"))}},
+				err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+					Code:    2,
+					Message: `invalid value: { "foo": -Infinity } (infinity values are not allowed)`,
+				}}},
+			},
+			"Decimal128NaN": {
+				doc: bson.D{{"foo", must.NotFail(primitive.ParseDecimal128("NaN"))}},
+				err: mongo.WriteException{WriteErrors: []mongo.WriteError{{
+					Code:    2,
+					Message: `invalid value: { "foo": NaN } (NaN values are not allowed)`,
+				}}},
+			},
 		} {
 			name, tc := name, tc
 			t.Run(name, func(t *testing.T) {
@@ -20,9 +20,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 func TestDiffUpdateProduceInfinity(t *testing.T) {
@@ -39,11 +41,32 @@ func TestDiffUpdateProduceInfinity(t *testing.T) {
 		return
 	}
 
-	expected := mongo.CommandError{
+	expected := mongo.WriteError{
 		Code: 2,
-		Name: "BadValue",
 		Message: `update produces invalid value: { "v": +Inf }` +
 			` (update operations that produce infinity values are not allowed)`,
 	}
-	AssertEqualCommandError(t, expected, err)
+	AssertEqualWriteError(t, expected, err)
+}
+
+func TestDiffUpdateProduceInfinityDecimal128(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "decimal"}, {"v", must.NotFail(primitive.ParseDecimal128("1E+6144"))}})
+	require.NoError(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", "decimal"}}, bson.D{{"$mul", bson.D{{"v", int32(10)}}}})
+
+	if setup.IsMongoDB(t) {
+		require.NoError(t, err)
+		return
+	}
+
+	expected := mongo.WriteError{
+		Code: 2,
+		Message: `update produces invalid value: { "v": Infinity }` +
+			` (update operations that produce infinity values are not allowed)`,
+	}
+	AssertEqualWriteError(t, expected, err)
 }
@@ -34,7 +34,6 @@ func TestDiffDatabaseName(t *testing.T) {
 
 	for name, dbName := range map[string]string{
 		"ReservedPrefix": "_ferretdb_xxx",
-		"NonLatin":       "データベース",
 	} {
 		name, dbName := name, dbName
 		t.Run(name, func(t *testing.T) {
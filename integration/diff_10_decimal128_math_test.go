@@ -0,0 +1,50 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestDiffDecimal128Math(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "decimal"}, {"v", must.NotFail(primitive.ParseDecimal128("2"))}})
+	require.NoError(t, err)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.D{{"$project", bson.D{{"sqrt", bson.D{{"$sqrt", "$v"}}}}}},
+	})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+
+	expected := "1.41421356237310"
+	if setup.IsMongoDB(t) {
+		expected = "1.414213562373095048801688724209698"
+	}
+
+	require.Equal(t, []bson.D{{{"_id", "decimal"}, {"sqrt", must.NotFail(primitive.ParseDecimal128(expected))}}}, res)
+}
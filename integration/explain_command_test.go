@@ -15,14 +15,18 @@
 package integration
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 func TestExplainCommandQueryErrors(t *testing.T) {
@@ -90,6 +94,22 @@ func TestExplainCommandQueryErrors(t *testing.T) {
 				Message: "BSON field 'skip' value must be >= 0, actual value '-1'",
 			},
 		},
+		"UpdateBatch": {
+			command: bson.D{
+				{"explain", bson.D{
+					{"update", collection.Name()},
+					{"updates", bson.A{
+						bson.D{{"q", bson.D{}}, {"u", bson.D{{"$set", bson.D{{"v", 1}}}}}},
+						bson.D{{"q", bson.D{}}, {"u", bson.D{{"$set", bson.D{{"v", 2}}}}}},
+					}},
+				}},
+			},
+			err: &mongo.CommandError{
+				Code:    16,
+				Name:    "InvalidLength",
+				Message: "explained write batches must be of size 1",
+			},
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -143,3 +163,179 @@ func TestExplainLimitInt(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, res)
 }
+
+func TestExplainWriteCommands(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", int32(1)}},
+		bson.D{{"_id", int32(2)}, {"v", int32(2)}},
+		bson.D{{"_id", int32(3)}, {"v", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		command  bson.D
+		expected map[string]int32 // expected execution stages counters
+	}{
+		"Update": {
+			command: bson.D{
+				{"update", collection.Name()},
+				{"updates", bson.A{bson.D{
+					{"q", bson.D{{"v", int32(2)}}},
+					{"u", bson.D{{"$set", bson.D{{"v", int32(3)}}}}},
+					{"multi", true},
+				}}},
+			},
+			expected: map[string]int32{"nMatched": 2, "nWouldModify": 2, "nWouldUpsert": 0},
+		},
+		"UpdateNotModified": {
+			command: bson.D{
+				{"update", collection.Name()},
+				{"updates", bson.A{bson.D{
+					{"q", bson.D{{"v", int32(2)}}},
+					{"u", bson.D{{"$set", bson.D{{"v", int32(2)}}}}},
+				}}},
+			},
+			expected: map[string]int32{"nMatched": 1, "nWouldModify": 0, "nWouldUpsert": 0},
+		},
+		"Upsert": {
+			command: bson.D{
+				{"update", collection.Name()},
+				{"updates", bson.A{bson.D{
+					{"q", bson.D{{"v", int32(42)}}},
+					{"u", bson.D{{"$set", bson.D{{"foo", int32(1)}}}}},
+					{"upsert", true},
+				}}},
+			},
+			expected: map[string]int32{"nMatched": 0, "nWouldModify": 0, "nWouldUpsert": 1},
+		},
+		"Delete": {
+			command: bson.D{
+				{"delete", collection.Name()},
+				{"deletes", bson.A{bson.D{{"q", bson.D{{"v", int32(2)}}}, {"limit", int32(0)}}}},
+			},
+			expected: map[string]int32{"nWouldDelete": 2},
+		},
+		"DeleteOne": {
+			command: bson.D{
+				{"delete", collection.Name()},
+				{"deletes", bson.A{bson.D{{"q", bson.D{}}, {"limit", int32(1)}}}},
+			},
+			expected: map[string]int32{"nWouldDelete": 1},
+		},
+		"FindAndModifyRemove": {
+			command: bson.D{
+				{"findAndModify", collection.Name()},
+				{"query", bson.D{{"v", int32(2)}}},
+				{"remove", true},
+			},
+			expected: map[string]int32{"nWouldDelete": 1},
+		},
+		"FindAndModifyUpdate": {
+			command: bson.D{
+				{"findAndModify", collection.Name()},
+				{"query", bson.D{{"v", int32(1)}}},
+				{"update", bson.D{{"$inc", bson.D{{"v", int32(1)}}}}},
+			},
+			expected: map[string]int32{"nMatched": 1, "nWouldModify": 1, "nWouldUpsert": 0},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var res bson.D
+			err := collection.Database().RunCommand(ctx, bson.D{
+				{"explain", tc.command},
+				{"verbosity", "executionStats"},
+			}).Decode(&res)
+			require.NoError(t, err)
+
+			assert.NotNil(t, res.Map()["queryPlanner"])
+
+			stats, ok := res.Map()["executionStats"].(bson.D)
+			require.True(t, ok, "executionStats: %v", res)
+
+			stages, ok := stats.Map()["executionStages"].(bson.D)
+			require.True(t, ok, "executionStages: %v", stats)
+
+			for k, v := range tc.expected {
+				assert.EqualValues(t, v, stages.Map()[k], k)
+			}
+		})
+	}
+
+	t.Run("QueryPlanner", func(t *testing.T) {
+		var res bson.D
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"explain", bson.D{
+				{"delete", collection.Name()},
+				{"deletes", bson.A{bson.D{{"q", bson.D{}}, {"limit", int32(0)}}}},
+			}},
+			{"verbosity", "queryPlanner"},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		assert.NotNil(t, res.Map()["queryPlanner"])
+		assert.Nil(t, res.Map()["executionStats"])
+	})
+
+	// nothing should be changed by explained commands
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var actual []bson.D
+	require.NoError(t, cursor.All(ctx, &actual))
+
+	expected := []bson.D{
+		{{"_id", int32(1)}, {"v", int32(1)}},
+		{{"_id", int32(2)}, {"v", int32(2)}},
+		{{"_id", int32(3)}, {"v", int32(2)}},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestExplainSQL(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "generated SQL is a FerretDB extension")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{EnableExplainSQL: true},
+	})
+	ctx, collection := s.Ctx, s.Collection
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "foo"}, {"v", int32(42)}})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"explain", bson.D{
+			{"find", collection.Name()},
+			{"filter", bson.D{{"_id", "foo"}}},
+		}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+
+	if setup.IsMemory(t) {
+		assert.False(t, doc.Has("sql"))
+		return
+	}
+
+	sql, ok := must.NotFail(doc.Get("sql")).(*types.Document)
+	require.True(t, ok)
+
+	query, ok := must.NotFail(sql.Get("query")).(string)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(query, "SELECT "), query)
+	assert.NotContains(t, query, "EXPLAIN")
+
+	args, ok := must.NotFail(sql.Get("args")).(*types.Array)
+	require.True(t, ok)
+
+	if !setup.PushdownDisabled() {
+		assert.NotZero(t, args.Len())
+	}
+}
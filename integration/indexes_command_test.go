@@ -15,6 +15,8 @@
 package integration
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -62,6 +64,114 @@ func TestListIndexesCommandNonExistentNS(t *testing.T) {
 	AssertEqualCommandError(t, expected, err)
 }
 
+func TestListIndexesCommandOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Composites)
+
+	indexes := bson.A{
+		bson.D{
+			{"key", bson.D{{"v", 1}}},
+			{"name", "v_1"},
+			{"sparse", true},
+			{"hidden", true},
+		},
+		bson.D{
+			{"key", bson.D{{"foo", -1}}},
+			{"name", "foo_-1"},
+			{"partialFilterExpression", bson.D{{"bar", bson.D{{"$gt", int32(1)}}}}},
+		},
+		bson.D{
+			{"key", bson.D{{"ts", 1}}},
+			{"name", "ts_1"},
+			{"expireAfterSeconds", int32(3600)},
+		},
+		bson.D{
+			{"key", bson.D{{"foo", 1}, {"h", "hashed"}}},
+			{"name", "foo_1_h_hashed"},
+		},
+	}
+
+	err := collection.Database().RunCommand(ctx, bson.D{
+		{"createIndexes", collection.Name()},
+		{"indexes", indexes},
+	}).Err()
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"listIndexes", collection.Name()},
+		{"cursor", bson.D{{"batchSize", int32(2)}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	c, ok := res.Map()["cursor"].(bson.D)
+	require.True(t, ok)
+
+	cursorID, ok := c.Map()["id"].(int64)
+	require.True(t, ok)
+	assert.NotZero(t, cursorID)
+
+	firstBatch, ok := c.Map()["firstBatch"].(bson.A)
+	require.True(t, ok)
+	assert.Len(t, firstBatch, 2)
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var actual []bson.D
+	require.NoError(t, cursor.All(ctx, &actual))
+
+	expected := []bson.D{
+		{{"v", int32(2)}, {"key", bson.D{{"_id", int32(1)}}}, {"name", "_id_"}},
+		{{"v", int32(2)}, {"key", bson.D{{"v", int32(1)}}}, {"name", "v_1"}, {"sparse", true}, {"hidden", true}},
+		{
+			{"v", int32(2)},
+			{"key", bson.D{{"foo", int32(-1)}}},
+			{"name", "foo_-1"},
+			{"partialFilterExpression", bson.D{{"bar", bson.D{{"$gt", int32(1)}}}}},
+		},
+		{
+			{"v", int32(2)},
+			{"key", bson.D{{"ts", int32(1)}}},
+			{"name", "ts_1"},
+			{"expireAfterSeconds", int32(3600)},
+		},
+		{{"v", int32(2)}, {"key", bson.D{{"foo", int32(1)}, {"h", "hashed"}}}, {"name", "foo_1_h_hashed"}},
+	}
+
+	// MongoDB returns indexes in the creation order, FerretDB sorts them by name
+	sortByName := func(indexes []bson.D) {
+		slices.SortFunc(indexes, func(a, b bson.D) int {
+			return strings.Compare(a.Map()["name"].(string), b.Map()["name"].(string))
+		})
+	}
+
+	sortByName(expected)
+	sortByName(actual)
+	assert.Equal(t, expected, actual)
+
+	t.Run("OptionsConflict", func(t *testing.T) {
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"createIndexes", collection.Name()},
+			{"indexes", bson.A{bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}}}},
+		}).Err()
+
+		var ce mongo.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, int32(85), ce.Code)
+		assert.Equal(t, "IndexOptionsConflict", ce.Name)
+	})
+
+	t.Run("SameOptions", func(t *testing.T) {
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{"createIndexes", collection.Name()},
+			{"indexes", bson.A{indexes[0]}},
+		}).Err()
+		require.NoError(t, err)
+	})
+}
+
 func TestDropIndexesCommandErrors(t *testing.T) {
 	t.Parallel()
 
@@ -155,6 +265,58 @@ func TestDropIndexesCommandErrors(t *testing.T) {
 				Message: "can't find index with key: { _id: -1 }",
 			},
 		},
+		"IndexIDByName": {
+			toDrop: "_id_",
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "cannot drop _id index",
+			},
+		},
+		"IndexIDInArray": {
+			toCreate: []mongo.IndexModel{
+				{Keys: bson.D{{"v", 1}}},
+			},
+			toDrop: bson.A{"v_1", "_id_"},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "cannot drop _id index",
+			},
+		},
+		"WildcardInArray": {
+			toCreate: []mongo.IndexModel{
+				{Keys: bson.D{{"v", 1}}},
+			},
+			toDrop: bson.A{"v_1", "*"},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "'*' is not a valid index name when dropping multiple indexes",
+			},
+		},
+		"KeyPrefix": {
+			toCreate: []mongo.IndexModel{
+				{Keys: bson.D{{"v", 1}, {"foo", 1}}},
+			},
+			toDrop: bson.D{{"v", 1}},
+			err: &mongo.CommandError{
+				Code:    27,
+				Name:    "IndexNotFound",
+				Message: "can't find index with key: { v: 1 }",
+			},
+		},
+		"KeyExtension": {
+			toCreate: []mongo.IndexModel{
+				{Keys: bson.D{{"v", 1}}},
+			},
+			toDrop: bson.D{{"v", 1}, {"foo", 1}},
+			err: &mongo.CommandError{
+				Code:    27,
+				Name:    "IndexNotFound",
+				Message: "can't find index with key: { v: 1, foo: 1 }",
+			},
+		},
 		"NonExistentMultipleKeyIndex": {
 			toDrop: bson.D{
 				{"non-existent1", -1},
@@ -232,6 +394,52 @@ func TestCreateIndexesCommandInvalidSpec(t *testing.T) {
 				Message: "Must specify at least one index to create",
 			},
 		},
+		"HiddenID": {
+			indexes: bson.A{bson.D{{"key", bson.D{{"_id", 1}}}, {"name", "_id_"}, {"hidden", true}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "can't hide _id index",
+			},
+		},
+		"TTLCompound": {
+			indexes: bson.A{bson.D{{"key", bson.D{{"v", 1}, {"foo", 1}}}, {"name", "v_1_foo_1"}, {"expireAfterSeconds", 1}}},
+			err: &mongo.CommandError{
+				Code: 67,
+				Name: "CannotCreateIndex",
+				Message: "TTL indexes are single-field indexes, compound indexes do not support TTL. " +
+					"Index spec: { key: { v: 1, foo: 1 }, name: \"v_1_foo_1\", expireAfterSeconds: 1, v: 2 }",
+			},
+			altMessage: "TTL indexes are single-field indexes, compound indexes do not support TTL. " +
+				"Index spec: { key: { v: 1, foo: 1 }, name: \"v_1_foo_1\" }",
+		},
+		"SparseWrongType": {
+			indexes: bson.A{bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}, {"sparse", "true"}}},
+			err: &mongo.CommandError{
+				Code: 14,
+				Name: "TypeMismatch",
+				Message: "Error in specification { key: { v: 1 }, name: \"v_1\", sparse: \"true\" } " +
+					":: caused by :: The field 'sparse' must be a bool, but got string",
+			},
+			altMessage: "Error in specification { key: { v: 1 }, name: \"v_1\" } " +
+				":: caused by :: The field 'sparse' must be of type bool, but got string",
+		},
+		"HashedUnique": {
+			indexes: bson.A{bson.D{{"key", bson.D{{"v", "hashed"}}}, {"name", "v_hashed"}, {"unique", true}}},
+			err: &mongo.CommandError{
+				Code:    16764,
+				Name:    "Location16764",
+				Message: "Currently hashed indexes cannot guarantee uniqueness. Use a regular index.",
+			},
+		},
+		"HashedMultiple": {
+			indexes: bson.A{bson.D{{"key", bson.D{{"v", "hashed"}, {"foo", "hashed"}}}, {"name", "v_foo"}}},
+			err: &mongo.CommandError{
+				Code:    31303,
+				Name:    "Location31303",
+				Message: `A maximum of one index field is allowed to be hashed but found 2 for 'key' { v: "hashed", foo: "hashed" }`,
+			},
+		},
 		"MissingIndexes": {
 			missingIndexes: true,
 			err: &mongo.CommandError{
@@ -525,6 +733,48 @@ func TestCreateIndexesCommandInvalidCollection(t *testing.T) {
 	}
 }
 
+func TestCreateIndexesCommandCommitQuorum(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Composites)
+
+	command := bson.D{
+		{"createIndexes", collection.Name()},
+		{"indexes", bson.A{
+			bson.D{{"key", bson.D{{"v", 1}}}, {"name", "v_1"}},
+			bson.D{{"key", bson.D{{"foo", -1}}}, {"name", "foo_-1"}},
+		}},
+		{"commitQuorum", "majority"},
+	}
+
+	var res bson.D
+	err := collection.Database().RunCommand(ctx, command).Decode(&res)
+	require.NoError(t, err)
+
+	m := res.Map()
+	assert.Equal(t, int32(1), m["numIndexesBefore"])
+	assert.Equal(t, int32(3), m["numIndexesAfter"])
+	assert.Equal(t, float64(1), m["ok"])
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+	assert.Len(t, indexes, 3)
+
+	// finished builds are not reported
+	err = collection.Database().Client().Database("admin").RunCommand(
+		ctx,
+		bson.D{{"currentOp", int32(1)}, {"desc", "IndexBuildsCoordinator"}, {"command.createIndexes", collection.Name()}},
+	).Decode(&res)
+	require.NoError(t, err)
+
+	inprog, ok := res.Map()["inprog"].(bson.A)
+	require.True(t, ok)
+	assert.Empty(t, inprog)
+}
+
 func TestDropIndexesCommandInvalidCollection(t *testing.T) {
 	t.Parallel()
 
@@ -544,6 +794,15 @@ func TestDropIndexesCommandInvalidCollection(t *testing.T) {
 				Message: "ns not found TestDropIndexesCommandInvalidCollection-NonExistentCollection.non-existent",
 			},
 		},
+		"NonExistentWildcard": {
+			collectionName: "non-existent",
+			indexName:      "*",
+			err: &mongo.CommandError{
+				Code:    26,
+				Name:    "NamespaceNotFound",
+				Message: "ns not found TestDropIndexesCommandInvalidCollection-NonExistentWildcard.non-existent",
+			},
+		},
 		"InvalidTypeCollection": {
 			collectionName: 42,
 			indexName:      "index",
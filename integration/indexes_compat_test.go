@@ -138,6 +138,16 @@ func TestCreateIndexesCompat(t *testing.T) {
 				},
 			},
 		},
+		"Hashed": {
+			models: []mongo.IndexModel{
+				{Keys: bson.D{{"hashed-field", "hashed"}}},
+			},
+		},
+		"HashedCompound": {
+			models: []mongo.IndexModel{
+				{Keys: bson.D{{"foo", 1}, {"hashed-field", "hashed"}}},
+			},
+		},
 
 		"MultiDirectionDifferentIndexes": {
 			models: []mongo.IndexModel{
@@ -230,3 +230,35 @@ func TestInsertTooLargeDocument(tt *testing.T) {
 	_, err = collection.InsertOne(ctx, doc)
 	require.NoError(t, err)
 }
+
+func TestInsertSmallMaxDocumentSize(tt *testing.T) {
+	t := setup.FailsForMongoDB(tt, "maximum BSON document size is only configurable for FerretDB")
+
+	tt.Parallel()
+
+	size := 1024
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{BackendOptions: &setup.BackendOpts{MaxBsonObjectSizeBytes: size}})
+	ctx, collection := s.Ctx, s.Collection
+
+	var res bson.D
+	err := collection.Database().RunCommand(ctx, bson.D{{"hello", int32(1)}}).Decode(&res)
+	require.NoError(t, err)
+	assert.Equal(t, int32(size), res.Map()["maxBsonObjectSize"])
+
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"insert", collection.Name()},
+		{"documents", bson.A{
+			bson.D{{"_id", "small"}},
+			bson.D{{"_id", "large"}, {"v", strings.Repeat("a", size)}},
+		}},
+	}).Err()
+	AssertEqualWriteError(t, mongo.WriteError{
+		Index:   1,
+		Code:    2,
+		Message: "object to insert too large. size in bytes: 1052, max size: 1024",
+	}, err)
+
+	n, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
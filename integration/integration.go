@@ -48,6 +48,20 @@ const (
 	emptyResult
 )
 
+// failsForFerretDBCompat returns testtb.TB for the compat test case subtest.
+//
+// If the issue URL is set, the returned TB expects the subtest to fail for FerretDB.
+// Then the subtest fails if it passes, so that outdated expectations are not left behind.
+func failsForFerretDBCompat(tb testtb.TB, url string) testtb.TB {
+	tb.Helper()
+
+	if url == "" {
+		return tb
+	}
+
+	return setup.FailsForFerretDB(tb, url)
+}
+
 // convert converts given driver value (bson.D, bson.A, etc) to FerretDB types package value.
 //
 // It then can be used with all types helpers such as testutil.AssertEqual.
@@ -98,6 +112,9 @@ func convert(t testtb.TB, v any) any {
 		return types.NewTimestamp(time.Unix(int64(v.T), 0), v.I)
 	case int64:
 		return v
+	case primitive.Decimal128:
+		h, l := v.GetBytes()
+		return types.Decimal128{L: l, H: h}
 	default:
 		t.Fatalf("unexpected type %T", v)
 		panic("not reached")
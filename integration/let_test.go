@@ -0,0 +1,247 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestLetFind(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"v", int32(1)}},
+		bson.D{{"_id", 2}, {"v", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		filter bson.D
+		let    bson.D
+
+		expected []bson.D
+	}{
+		"True": {
+			filter:   bson.D{{"$expr", "$$flag"}},
+			let:      bson.D{{"flag", true}},
+			expected: []bson.D{{{"_id", int32(1)}, {"v", int32(1)}}, {{"_id", int32(2)}, {"v", int32(2)}}},
+		},
+		"False": {
+			filter:   bson.D{{"$expr", "$$flag"}},
+			let:      bson.D{{"flag", false}},
+			expected: []bson.D{},
+		},
+		"DotNotation": {
+			filter:   bson.D{{"$expr", "$$doc.flag"}},
+			let:      bson.D{{"doc", bson.D{{"flag", false}}}},
+			expected: []bson.D{},
+		},
+		"Or": {
+			filter:   bson.D{{"$or", bson.A{bson.D{{"$expr", "$$flag"}}, bson.D{{"v", int32(2)}}}}},
+			let:      bson.D{{"flag", false}},
+			expected: []bson.D{{{"_id", int32(2)}, {"v", int32(2)}}},
+		},
+		"NotExpr": {
+			filter:   bson.D{{"v", "$$v"}},
+			let:      bson.D{{"v", int32(1)}},
+			expected: []bson.D{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := options.Find().SetSort(bson.D{{"_id", 1}}).SetLet(tc.let)
+
+			cursor, err := collection.Find(ctx, tc.filter, opts)
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			AssertEqualDocumentsSlice(t, tc.expected, res)
+		})
+	}
+}
+
+func TestLetAggregate(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"v", int32(1)}},
+		bson.D{{"_id", 2}, {"v", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A
+		let      bson.D
+
+		expected []bson.D
+	}{
+		"AddFields": {
+			pipeline: bson.A{
+				bson.D{{"$addFields", bson.D{{"sum", bson.D{{"$sum", bson.A{"$v", "$$inc"}}}}}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+			let: bson.D{{"inc", int32(10)}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"v", int32(1)}, {"sum", int32(11)}},
+				{{"_id", int32(2)}, {"v", int32(2)}, {"sum", int32(12)}},
+			},
+		},
+		"ProjectDocument": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"doc", "$$doc"}, {"name", "$$doc.name"}}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+			let: bson.D{{"doc", bson.D{{"name", "foo"}, {"n", int32(1)}}}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"doc", bson.D{{"name", "foo"}, {"n", int32(1)}}}, {"name", "foo"}},
+				{{"_id", int32(2)}, {"doc", bson.D{{"name", "foo"}, {"n", int32(1)}}}, {"name", "foo"}},
+			},
+		},
+		"Match": {
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"$expr", "$$flag"}}}},
+			},
+			let:      bson.D{{"flag", false}},
+			expected: []bson.D{},
+		},
+		"Literal": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"s", bson.D{{"$literal", "$$s"}}}}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+			let: bson.D{{"s", "foo"}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"s", "$$s"}},
+				{{"_id", int32(2)}, {"s", "$$s"}},
+			},
+		},
+		"Expression": {
+			pipeline: bson.A{
+				bson.D{{"$project", bson.D{{"sum", "$$sum"}}}},
+				bson.D{{"$sort", bson.D{{"_id", 1}}}},
+			},
+			let: bson.D{{"sum", bson.D{{"$sum", bson.A{int32(1), int32(2)}}}}},
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"sum", int32(3)}},
+				{{"_id", int32(2)}, {"sum", int32(3)}},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, tc.pipeline, options.Aggregate().SetLet(tc.let))
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			AssertEqualDocumentsSlice(t, tc.expected, res)
+		})
+	}
+}
+
+func TestLetWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"v", int32(1)}},
+		bson.D{{"_id", 2}, {"v", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	filter := bson.D{{"$expr", "$$flag"}}
+
+	res, err := collection.UpdateMany(
+		ctx, filter, bson.D{{"$set", bson.D{{"v", int32(42)}}}},
+		options.Update().SetLet(bson.D{{"flag", false}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.MatchedCount)
+
+	res, err = collection.UpdateMany(
+		ctx, filter, bson.D{{"$set", bson.D{{"v", int32(42)}}}},
+		options.Update().SetLet(bson.D{{"flag", true}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.ModifiedCount)
+
+	var doc bson.D
+	err = collection.FindOneAndUpdate(
+		ctx, filter, bson.D{{"$set", bson.D{{"v", int32(43)}}}},
+		options.FindOneAndUpdate().SetLet(bson.D{{"flag", true}}).SetSort(bson.D{{"_id", 1}}),
+	).Decode(&doc)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"_id", int32(1)}, {"v", int32(42)}}, doc)
+
+	del, err := collection.DeleteMany(ctx, filter, options.Delete().SetLet(bson.D{{"flag", false}}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), del.DeletedCount)
+
+	del, err = collection.DeleteMany(ctx, filter, options.Delete().SetLet(bson.D{{"flag", true}}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), del.DeletedCount)
+}
+
+func TestLetErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	for name, tc := range map[string]struct {
+		let bson.D
+
+		err *mongo.CommandError
+	}{
+		"UppercaseName": {
+			let: bson.D{{"Foo", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    16870,
+				Name:    "Location16870",
+				Message: "'Foo' starts with an invalid character for a user variable name",
+			},
+		},
+		"InvalidChar": {
+			let: bson.D{{"foo-bar", int32(1)}},
+			err: &mongo.CommandError{
+				Code:    16871,
+				Name:    "Location16871",
+				Message: "'foo-bar' contains an invalid character for a variable name: '-'",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := collection.Find(ctx, bson.D{}, options.Find().SetLet(tc.let))
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
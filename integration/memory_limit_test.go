@@ -0,0 +1,248 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestOperationMemoryLimit(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{OperationMemoryLimit: 1 << 10},
+	})
+	ctx, coll := s.Ctx, s.Collection
+
+	if setup.IsMongoDB(t) {
+		t.Skip("MongoDB does not allow to set such a small memory limit per command")
+	}
+
+	docs := make([]any, 100)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", int32(i % 10)}}
+	}
+
+	_, err := coll.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A
+		err      *mongo.CommandError
+	}{
+		"Sort": {
+			pipeline: bson.A{bson.D{{"$sort", bson.D{{"v", -1}}}}},
+			err: &mongo.CommandError{
+				Code: 292,
+				Name: "QueryExceededMemoryLimitNoDiskUseAllowed",
+				Message: "Sort exceeded memory limit of 1024 bytes, but did not opt in to external sorting. " +
+					"Pass allowDiskUse:true to opt in.",
+			},
+		},
+		"Group": {
+			pipeline: bson.A{bson.D{{"$group", bson.D{{"_id", "$v"}}}}},
+			err: &mongo.CommandError{
+				Code:    292,
+				Name:    "QueryExceededMemoryLimitNoDiskUseAllowed",
+				Message: "Exceeded memory limit for $group, but didn't allow external sort. Pass allowDiskUse:true to opt in.",
+			},
+		},
+		"Facet": {
+			pipeline: bson.A{bson.D{{"$facet", bson.D{{"first", bson.A{bson.D{{"$limit", 1}}}}}}}},
+			err: &mongo.CommandError{
+				Code:    292,
+				Name:    "QueryExceededMemoryLimitNoDiskUseAllowed",
+				Message: "Exceeded memory limit for $facet, but didn't allow external sort. Pass allowDiskUse:true to opt in.",
+			},
+		},
+		"FacetCopies": {
+			// input fits into the limit, but its copies for sub-pipelines do not
+			pipeline: bson.A{
+				bson.D{{"$match", bson.D{{"_id", bson.D{{"$lt", 5}}}}}},
+				bson.D{{"$facet", bson.D{
+					{"first", bson.A{bson.D{{"$limit", 1}}}},
+					{"last", bson.A{bson.D{{"$skip", 4}}}},
+				}}},
+			},
+			err: &mongo.CommandError{
+				Code:    292,
+				Name:    "QueryExceededMemoryLimitNoDiskUseAllowed",
+				Message: "Exceeded memory limit for $facet, but didn't allow external sort. Pass allowDiskUse:true to opt in.",
+			},
+		},
+		"Lookup": {
+			pipeline: bson.A{
+				bson.D{{"$limit", 1}},
+				bson.D{{"$lookup", bson.D{
+					{"from", coll.Name()},
+					{"localField", "v"},
+					{"foreignField", "v"},
+					{"as", "joined"},
+				}}},
+			},
+			err: &mongo.CommandError{
+				Code:    292,
+				Name:    "QueryExceededMemoryLimitNoDiskUseAllowed",
+				Message: "Exceeded memory limit for $lookup, but didn't allow external sort. Pass allowDiskUse:true to opt in.",
+			},
+		},
+		"Limit": {
+			pipeline: bson.A{bson.D{{"$limit", 1}}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := coll.Aggregate(ctx, tc.pipeline)
+			if tc.err != nil {
+				AssertEqualCommandError(t, *tc.err, err)
+			} else {
+				require.NoError(t, err)
+				require.NoError(t, cursor.Close(ctx))
+			}
+
+			cursor, err = coll.Aggregate(ctx, tc.pipeline, options.Aggregate().SetAllowDiskUse(true))
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			assert.NotEmpty(t, res)
+		})
+	}
+
+	t.Run("Find", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.Find().SetSort(bson.D{{"v", 1}})
+
+		_, err := coll.Find(ctx, bson.D{}, opts)
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code: 292,
+			Name: "QueryExceededMemoryLimitNoDiskUseAllowed",
+			Message: "Sort exceeded memory limit of 1024 bytes, but did not opt in to external sorting. " +
+				"Pass allowDiskUse:true to opt in.",
+		}, err)
+
+		cursor, err := coll.Find(ctx, bson.D{}, opts.SetAllowDiskUse(true))
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		assert.Len(t, res, len(docs))
+	})
+}
+
+func TestOperationMemoryLimitSpill(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{OperationMemoryLimit: 1 << 10},
+	})
+	ctx, coll := s.Ctx, s.Collection
+
+	if setup.IsMongoDB(t) {
+		t.Skip("MongoDB does not allow to set such a small memory limit per command")
+	}
+
+	docs := make([]any, 500)
+	for i := range docs {
+		// mix number types to check that they are grouped together
+		var v any = int32(i % 10)
+		if i%2 == 0 {
+			v = float64(i % 10)
+		}
+
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", v}}
+	}
+
+	_, err := coll.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	t.Run("Sort", func(t *testing.T) {
+		t.Parallel()
+
+		pipeline := bson.A{bson.D{{"$sort", bson.D{{"v", -1}, {"_id", 1}}}}}
+
+		cursor, err := coll.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+		require.NoError(t, err)
+
+		var res []struct {
+			ID int32   `bson:"_id"`
+			V  float64 `bson:"v"`
+		}
+		require.NoError(t, cursor.All(ctx, &res))
+		require.Len(t, res, len(docs))
+
+		for i := 1; i < len(res); i++ {
+			prev, cur := res[i-1], res[i]
+			if prev.V == cur.V {
+				assert.Less(t, prev.ID, cur.ID)
+			} else {
+				assert.Greater(t, prev.V, cur.V)
+			}
+		}
+	})
+
+	t.Run("Group", func(t *testing.T) {
+		t.Parallel()
+
+		pipeline := bson.A{
+			bson.D{{"$group", bson.D{{"_id", "$v"}, {"count", bson.D{{"$sum", 1}}}}}},
+			bson.D{{"$sort", bson.D{{"_id", 1}}}},
+		}
+
+		cursor, err := coll.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+		require.NoError(t, err)
+
+		var res []struct {
+			ID    float64 `bson:"_id"`
+			Count int32   `bson:"count"`
+		}
+		require.NoError(t, cursor.All(ctx, &res))
+		require.Len(t, res, 10)
+
+		for i, r := range res {
+			assert.Equal(t, float64(i), r.ID)
+			assert.Equal(t, int32(len(docs)/10), r.Count)
+		}
+	})
+
+	t.Run("Find", func(t *testing.T) {
+		t.Parallel()
+
+		opts := options.Find().SetSort(bson.D{{"_id", -1}}).SetAllowDiskUse(true)
+
+		cursor, err := coll.Find(ctx, bson.D{}, opts)
+		require.NoError(t, err)
+
+		var res []struct {
+			ID int32 `bson:"_id"`
+		}
+		require.NoError(t, cursor.All(ctx, &res))
+		require.Len(t, res, len(docs))
+
+		for i, r := range res {
+			assert.Equal(t, int32(len(docs)-1-i), r.ID)
+		}
+	})
+}
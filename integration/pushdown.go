@@ -60,7 +60,8 @@ func (res resultPushdown) pushdownExpected(t testtb.TB) bool {
 	switch {
 	case setup.IsPostgreSQL(t):
 		return res&pgPushdown == pgPushdown
-	case setup.IsSQLite(t):
+	case setup.IsSQLite(t), setup.IsMemory(t):
+		// in-memory backend pushes down the same filters as SQLite
 		return res&sqlitePushdown == sqlitePushdown
 	case setup.IsMongoDB(t):
 		return false
@@ -1419,3 +1419,58 @@ func TestQueryComparisonCompatMultipleOperators(t *testing.T) {
 
 	testQueryCompat(t, testCases)
 }
+
+func TestQueryComparisonCompatDecimal128(t *testing.T) {
+	t.Parallel()
+
+	providers := shareddata.Providers{
+		shareddata.Decimal128s,
+		shareddata.Int32s,
+		shareddata.Int64s,
+		shareddata.Doubles,
+	}
+
+	testCases := map[string]queryCompatTestCase{
+		"Eq": {
+			filter: bson.D{{"v", bson.D{{"$eq", must.NotFail(primitive.ParseDecimal128("42.13"))}}}},
+		},
+		"EqWhole": {
+			filter: bson.D{{"v", bson.D{{"$eq", must.NotFail(primitive.ParseDecimal128("42.0"))}}}},
+		},
+		"EqZero": {
+			filter: bson.D{{"v", bson.D{{"$eq", must.NotFail(primitive.ParseDecimal128("-0E+10"))}}}},
+		},
+		"EqInt32": {
+			filter: bson.D{{"v", bson.D{{"$eq", int32(42)}}}},
+		},
+		"EqDouble": {
+			filter: bson.D{{"v", bson.D{{"$eq", 42.13}}}},
+		},
+		"Gt": {
+			filter: bson.D{{"v", bson.D{{"$gt", must.NotFail(primitive.ParseDecimal128("42.125"))}}}},
+		},
+		"GtInt64": {
+			filter: bson.D{{"v", bson.D{{"$gt", int64(math.MaxInt64)}}}},
+		},
+		"GtDouble": {
+			filter: bson.D{{"v", bson.D{{"$gt", math.MaxFloat64}}}},
+		},
+		"Lt": {
+			filter: bson.D{{"v", bson.D{{"$lt", must.NotFail(primitive.ParseDecimal128("0.1"))}}}},
+		},
+		"Lte": {
+			filter: bson.D{{"v", bson.D{{"$lte", must.NotFail(primitive.ParseDecimal128("0.1"))}}}},
+		},
+		"Ne": {
+			filter: bson.D{{"v", bson.D{{"$ne", must.NotFail(primitive.ParseDecimal128("0"))}}}},
+		},
+		"In": {
+			filter: bson.D{{"v", bson.D{{"$in", bson.A{
+				must.NotFail(primitive.ParseDecimal128("42")),
+				must.NotFail(primitive.ParseDecimal128("9223372036854775807")),
+			}}}}},
+		},
+	}
+
+	testQueryCompatWithProviders(t, providers, testCases)
+}
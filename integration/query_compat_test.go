@@ -49,6 +49,8 @@ type queryCompatTestCase struct {
 
 	skipIDCheck bool   // skip check collected IDs, use it when no ids returned from query
 	skip        string // always skip this test case, must have issue number mentioned
+
+	failsForFerretDB string // expected to fail for FerretDB, must be an issue URL
 }
 
 func testQueryCompatWithProviders(t *testing.T, providers shareddata.Providers, testCases map[string]queryCompatTestCase) {
@@ -107,8 +109,10 @@ func testQueryCompatWithProviders(t *testing.T, providers shareddata.Providers,
 			for i := range targetCollections {
 				targetCollection := targetCollections[i]
 				compatCollection := compatCollections[i]
-				t.Run(targetCollection.Name(), func(t *testing.T) {
-					t.Helper()
+				t.Run(targetCollection.Name(), func(tt *testing.T) {
+					tt.Helper()
+
+					t := failsForFerretDBCompat(tt, tc.failsForFerretDB)
 
 					targetIdx, tagetErr := targetCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
 						Keys: bson.D{{"v", 1}},
@@ -179,6 +183,11 @@ func testQueryCompatWithProviders(t *testing.T, providers shareddata.Providers,
 				})
 			}
 
+			// results of test cases that are expected to fail are not checked
+			if tc.failsForFerretDB != "" {
+				return
+			}
+
 			switch tc.resultType {
 			case nonEmptyResult:
 				assert.True(t, nonEmptyResults, "expected non-empty results")
@@ -503,6 +512,29 @@ func TestQueryCompatSort(t *testing.T) {
 	testQueryCompat(t, testCases)
 }
 
+func TestQueryCompatSortDecimal128(t *testing.T) {
+	t.Parallel()
+
+	providers := shareddata.Providers{
+		shareddata.Decimal128s,
+		shareddata.Int64s,
+		shareddata.Doubles,
+	}
+
+	testCases := map[string]queryCompatTestCase{
+		"Asc": {
+			filter: bson.D{},
+			sort:   bson.D{{"v", 1}, {"_id", 1}},
+		},
+		"Desc": {
+			filter: bson.D{},
+			sort:   bson.D{{"v", -1}, {"_id", 1}},
+		},
+	}
+
+	testQueryCompatWithProviders(t, providers, testCases)
+}
+
 func TestQueryCompatSortDotNotation(t *testing.T) {
 	t.Parallel()
 
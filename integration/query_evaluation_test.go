@@ -225,3 +225,75 @@ func TestQueryEvaluationExprErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryEvaluationSampleRate(t *testing.T) {
+	t.Parallel()
+	ctx, collection := setup.Setup(t, shareddata.Scalars)
+
+	all, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct { //nolint:vet // used for test only
+		filter bson.D // required
+
+		expectedCount int64               // optional, expected number of matched documents
+		err           *mongo.CommandError // optional, expected error
+	}{
+		"Zero": {
+			filter:        bson.D{{"$sampleRate", 0.0}},
+			expectedCount: 0,
+		},
+		"One": {
+			filter:        bson.D{{"$sampleRate", int32(1)}},
+			expectedCount: all,
+		},
+		"String": {
+			filter: bson.D{{"$sampleRate", "0.5"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "argument to $sampleRate must be a numeric type",
+			},
+		},
+		"Negative": {
+			filter: bson.D{{"$sampleRate", -0.1}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "numeric argument to $sampleRate must be in [0, 1]",
+			},
+		},
+		"TooLarge": {
+			filter: bson.D{{"$sampleRate", int64(2)}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "numeric argument to $sampleRate must be in [0, 1]",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NotNil(t, tc.filter, "filter must not be nil")
+
+			cursor, err := collection.Find(ctx, tc.filter)
+			if tc.err != nil {
+				if err == nil {
+					err = cursor.All(ctx, new([]bson.D))
+				}
+
+				AssertEqualCommandError(t, *tc.err, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var actual []bson.D
+			require.NoError(t, cursor.All(ctx, &actual))
+			assert.Len(t, actual, int(tc.expectedCount))
+		})
+	}
+}
@@ -16,6 +16,7 @@ package integration
 
 import (
 	"math"
+	"slices"
 	"testing"
 	"time"
 
@@ -985,3 +986,200 @@ func TestQueryShowRecordIDErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryNaturalOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	// insertion order differs from _id order
+	ids := []int32{3, 1, 4, 0, 2}
+	for _, id := range ids {
+		_, err := collection.InsertOne(ctx, bson.D{{"_id", id}})
+		require.NoError(t, err)
+	}
+
+	reversed := slices.Clone(ids)
+	slices.Reverse(reversed)
+
+	for name, tc := range map[string]struct {
+		opts     *options.FindOptions
+		pipeline bson.A
+		hint     any
+		expected []int32
+	}{
+		"SortAsc": {
+			opts:     options.Find().SetSort(bson.D{{"$natural", 1}}),
+			expected: ids,
+		},
+		"SortDesc": {
+			opts:     options.Find().SetSort(bson.D{{"$natural", -1}}),
+			expected: reversed,
+		},
+		"HintAsc": {
+			opts:     options.Find().SetHint(bson.D{{"$natural", 1}}),
+			expected: ids,
+		},
+		"HintDesc": {
+			opts:     options.Find().SetHint(bson.D{{"$natural", -1}}),
+			expected: reversed,
+		},
+		"HintWithSort": {
+			opts:     options.Find().SetHint(bson.D{{"$natural", -1}}).SetSort(bson.D{{"_id", 1}}),
+			expected: []int32{0, 1, 2, 3, 4},
+		},
+		"AggregateSortDesc": {
+			pipeline: bson.A{bson.D{{"$sort", bson.D{{"$natural", -1}}}}},
+			expected: reversed,
+		},
+		"AggregateHintDesc": {
+			pipeline: bson.A{},
+			hint:     bson.D{{"$natural", -1}},
+			expected: reversed,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cursor *mongo.Cursor
+			var err error
+
+			if tc.pipeline != nil {
+				opts := options.Aggregate()
+				if tc.hint != nil {
+					opts.SetHint(tc.hint)
+				}
+
+				cursor, err = collection.Aggregate(ctx, tc.pipeline, opts)
+			} else {
+				cursor, err = collection.Find(ctx, bson.D{}, tc.opts)
+			}
+
+			require.NoError(t, err)
+
+			var res []struct {
+				ID int32 `bson:"_id"`
+			}
+			require.NoError(t, cursor.All(ctx, &res))
+
+			actual := make([]int32, len(res))
+			for i, r := range res {
+				actual[i] = r.ID
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestQueryMinMax(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", 1}}})
+	require.NoError(t, err)
+
+	// index order differs from _id order
+	for i := range 10 {
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", int32(i)}, {"v", int32(9 - i)}})
+		require.NoError(t, err)
+	}
+
+	for name, tc := range map[string]struct {
+		opts *options.FindOptions
+
+		expected []bson.D
+		err      *mongo.CommandError
+	}{
+		"MinMax": {
+			opts: options.Find().SetHint("v_1").SetMin(bson.D{{"v", 3}}).SetMax(bson.D{{"v", 6}}),
+			expected: []bson.D{
+				{{"_id", int32(6)}, {"v", int32(3)}},
+				{{"_id", int32(5)}, {"v", int32(4)}},
+				{{"_id", int32(4)}, {"v", int32(5)}},
+			},
+		},
+		"Min": {
+			opts: options.Find().SetHint(bson.D{{"v", 1}}).SetMin(bson.D{{"v", 8}}),
+			expected: []bson.D{
+				{{"_id", int32(1)}, {"v", int32(8)}},
+				{{"_id", int32(0)}, {"v", int32(9)}},
+			},
+		},
+		"MaxSort": {
+			opts: options.Find().SetHint("v_1").SetMax(bson.D{{"v", 2}}).SetSort(bson.D{{"_id", 1}}),
+			expected: []bson.D{
+				{{"_id", int32(8)}, {"v", int32(1)}},
+				{{"_id", int32(9)}, {"v", int32(0)}},
+			},
+		},
+		"ReturnKey": {
+			opts: options.Find().SetHint("v_1").SetMin(bson.D{{"v", 7}}).SetReturnKey(true),
+			expected: []bson.D{
+				{{"v", int32(7)}},
+				{{"v", int32(8)}},
+				{{"v", int32(9)}},
+			},
+		},
+		"NoHint": {
+			opts: options.Find().SetMin(bson.D{{"v", 3}}),
+			err: &mongo.CommandError{
+				Code:    51173,
+				Name:    "Location51173",
+				Message: "When using min()/max() a hint of which index to use must be provided",
+			},
+		},
+		"FieldsMismatch": {
+			opts: options.Find().SetHint("v_1").SetMin(bson.D{{"v", 3}}).SetMax(bson.D{{"w", 6}}),
+			err: &mongo.CommandError{
+				Code:    51176,
+				Name:    "Location51176",
+				Message: "min() and max() must have the same field names",
+			},
+		},
+		"IndexMismatch": {
+			opts: options.Find().SetHint("_id_").SetMin(bson.D{{"v", 3}}),
+			err: &mongo.CommandError{
+				Code:    51174,
+				Name:    "Location51174",
+				Message: "The index chosen is not equivalent to min/max bounds",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Find(ctx, bson.D{}, tc.opts)
+			if tc.err != nil {
+				AssertEqualCommandError(t, *tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			AssertEqualDocumentsSlice(t, tc.expected, res)
+		})
+	}
+}
+
+func TestQueryReturnKeyNoIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{bson.D{{"_id", "a"}, {"v", 1}}, bson.D{{"_id", "b"}, {"v", 2}}})
+	require.NoError(t, err)
+
+	// without index, no fields are returned
+	cursor, err := collection.Find(ctx, bson.D{{"v", bson.D{{"$gt", 0}}}}, options.Find().SetReturnKey(true))
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	AssertEqualDocumentsSlice(t, []bson.D{{}, {}}, res)
+}
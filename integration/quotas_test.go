@@ -0,0 +1,130 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestQuotasMaxCollections(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "quotas are FerretDB-specific")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{QuotaMaxCollections: 2},
+	})
+	ctx, db := s.Ctx, s.Collection.Database()
+
+	require.NoError(t, db.CreateCollection(ctx, "foo"))
+
+	_, err := db.Collection("bar").InsertOne(ctx, bson.D{{"_id", 1}})
+	require.NoError(t, err)
+
+	expected := mongo.CommandError{
+		Code:    12501,
+		Name:    "Location12501",
+		Message: "quota exceeded: database " + db.Name() + " can't have more than 2 collections",
+	}
+
+	err = db.CreateCollection(ctx, "baz")
+	AssertEqualCommandError(t, expected, err)
+
+	_, err = db.Collection("baz").InsertOne(ctx, bson.D{{"_id", 1}})
+	AssertEqualCommandError(t, expected, err)
+
+	_, err = db.Collection("baz").UpdateOne(ctx, bson.D{}, bson.D{{"$set", bson.D{{"v", 1}}}}, options.Update().SetUpsert(true))
+	AssertEqualCommandError(t, expected, err)
+
+	_, err = db.Collection("baz").Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", 1}}})
+	AssertEqualCommandError(t, expected, err)
+
+	// existing collections are not affected
+	_, err = db.Collection("foo").InsertOne(ctx, bson.D{{"_id", 1}})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Collection("bar").Drop(ctx))
+	require.NoError(t, db.CreateCollection(ctx, "baz"))
+}
+
+func TestQuotasMaxDocuments(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "quotas are FerretDB-specific")
+
+	if setup.IsPostgreSQL(t) {
+		t.Skip("the number of documents is estimated from table statistics")
+	}
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{QuotaMaxDocuments: 3},
+	})
+	ctx, collection := s.Ctx, s.Collection
+
+	msg := "quota exceeded: collection " + collection.Database().Name() + "." + collection.Name() +
+		" can't have more than 3 documents"
+
+	_, err := collection.InsertMany(ctx, []any{bson.D{{"_id", 1}}, bson.D{{"_id", 2}}})
+	require.NoError(t, err)
+
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"insert", collection.Name()},
+		{"documents", bson.A{bson.D{{"_id", 3}}, bson.D{{"_id", 4}}, bson.D{{"_id", 5}}}},
+	}).Err()
+	AssertEqualWriteError(t, mongo.WriteError{Index: 1, Code: 12501, Message: msg}, err)
+
+	// updates of existing documents are allowed
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", 1}}, bson.D{{"$set", bson.D{{"v", 1}}}})
+	require.NoError(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", 6}}, bson.D{{"$set", bson.D{{"v", 1}}}}, options.Update().SetUpsert(true))
+	AssertEqualWriteError(t, mongo.WriteError{Code: 12501, Message: msg}, err)
+
+	_, err = collection.DeleteOne(ctx, bson.D{{"_id", 1}})
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", 1}})
+	require.NoError(t, err)
+
+	n, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestQuotasMaxSize(tt *testing.T) {
+	tt.Parallel()
+
+	t := setup.FailsForMongoDB(tt, "quotas are FerretDB-specific")
+
+	s := setup.SetupWithOpts(tt, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{QuotaMaxSize: 1},
+	})
+	ctx, collection := s.Ctx, s.Collection
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", 1}})
+	AssertEqualWriteError(t, mongo.WriteError{
+		Code: 12501,
+		Message: "quota exceeded: collection " + collection.Database().Name() + "." + collection.Name() +
+			" can't be larger than 1 bytes",
+	}, err)
+}
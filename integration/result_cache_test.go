@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestResultCache(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		BackendOptions: &setup.BackendOpts{ResultCacheSize: 1 << 20},
+	})
+	ctx, coll := s.Ctx, s.Collection
+
+	_, err := coll.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"v", "foo"}},
+		bson.D{{"_id", 2}, {"v", "bar"}},
+	})
+	require.NoError(t, err)
+
+	// check results twice to get them from the cache the second time
+	check := func(t *testing.T, expectedIDs, expectedValues []any) {
+		t.Helper()
+
+		for range 2 {
+			cursor, err := coll.Find(ctx, bson.D{}, nil)
+			require.NoError(t, err)
+
+			var docs []bson.D
+			require.NoError(t, cursor.All(ctx, &docs))
+
+			AssertEqualDocumentsSlice(t, makeResultCacheDocs(expectedIDs, expectedValues), docs)
+
+			count, err := coll.CountDocuments(ctx, bson.D{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(expectedIDs)), count)
+
+			values, err := coll.Distinct(ctx, "v", bson.D{})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, expectedValues, values)
+		}
+	}
+
+	check(t, []any{int32(1), int32(2)}, []any{"foo", "bar"})
+
+	_, err = coll.InsertOne(ctx, bson.D{{"_id", 3}, {"v", "baz"}})
+	require.NoError(t, err)
+
+	check(t, []any{int32(1), int32(2), int32(3)}, []any{"foo", "bar", "baz"})
+
+	_, err = coll.UpdateOne(ctx, bson.D{{"_id", 3}}, bson.D{{"$set", bson.D{{"v", "qux"}}}})
+	require.NoError(t, err)
+
+	check(t, []any{int32(1), int32(2), int32(3)}, []any{"foo", "bar", "qux"})
+
+	_, err = coll.DeleteOne(ctx, bson.D{{"_id", 1}})
+	require.NoError(t, err)
+
+	check(t, []any{int32(2), int32(3)}, []any{"bar", "qux"})
+
+	require.NoError(t, coll.Drop(ctx))
+
+	check(t, nil, nil)
+}
+
+// makeResultCacheDocs returns documents with the given _id and v fields.
+func makeResultCacheDocs(ids, values []any) []bson.D {
+	res := make([]bson.D, len(ids))
+	for i := range ids {
+		res[i] = bson.D{{"_id", ids[i]}, {"v", values[i]}}
+	}
+
+	return res
+}
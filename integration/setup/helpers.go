@@ -41,6 +41,13 @@ func IsSQLite(tb testtb.TB) bool {
 	return *targetBackendF == "ferretdb-sqlite"
 }
 
+// IsMemory returns true if the current test is running for in-memory backend.
+//
+// This function should not be used lightly.
+func IsMemory(tb testtb.TB) bool {
+	return *targetBackendF == "ferretdb-memory"
+}
+
 // IsMongoDB returns true if the current test is running for MongoDB.
 //
 // This function should not be used lightly.
@@ -125,6 +125,13 @@ func setupListener(tb testtb.TB, ctx context.Context, logger *slog.Logger, opts
 		require.Empty(tb, *hanaURLF, "-hana-url must be empty for %q", *targetBackendF)
 		handler = "sqlite"
 
+	case "ferretdb-memory":
+		require.Empty(tb, *postgreSQLURLF, "-postgresql-url must be empty for %q", *targetBackendF)
+		require.Empty(tb, *sqliteURLF, "-sqlite-url must be empty for %q", *targetBackendF)
+		require.Empty(tb, *mysqlURLF, "-mysql-url must be empty for %q", *targetBackendF)
+		require.Empty(tb, *hanaURLF, "-hana-url must be empty for %q", *targetBackendF)
+		handler = "memory"
+
 	case "ferretdb-mysql":
 		require.Empty(tb, *postgreSQLURLF, "-postgresql-url must be empty for %q", *targetBackendF)
 		require.Empty(tb, *sqliteURLF, "-sqlite-url must be empty for %q", *targetBackendF)
@@ -178,6 +185,17 @@ func setupListener(tb testtb.TB, ctx context.Context, logger *slog.Logger, opts
 		Logger:        logger,
 		ConnMetrics:   listenerMetrics.ConnMetrics,
 		StateProvider: sp,
+		ExportURL:     opts.ExportURL,
+		BackupURL:     opts.BackupURL,
+
+		QuotaMaxCollections: opts.QuotaMaxCollections,
+		QuotaMaxDocuments:   opts.QuotaMaxDocuments,
+		QuotaMaxSize:        opts.QuotaMaxSize,
+
+		MaxBsonObjectSizeBytes: opts.MaxBsonObjectSizeBytes,
+		ResultCacheSize:        opts.ResultCacheSize,
+		CursorReadaheadSize:    opts.CursorReadaheadSize,
+		OperationMemoryLimit:   opts.OperationMemoryLimit,
 
 		PostgreSQLURL: postgreSQLURLF,
 		SQLiteURL:     sqliteURL,
@@ -186,11 +204,12 @@ func setupListener(tb testtb.TB, ctx context.Context, logger *slog.Logger, opts
 
 		TestOpts: registry.TestOpts{
 			DisablePushdown:         *disablePushdownF,
+			EnableExplainSQL:        opts.EnableExplainSQL,
 			CappedCleanupPercentage: opts.CappedCleanupPercentage,
 			CappedCleanupInterval:   opts.CappedCleanupInterval,
 			EnableNewAuth:           !opts.DisableNewAuth,
 			BatchSize:               *batchSizeF,
-			MaxBsonObjectSizeBytes:  opts.MaxBsonObjectSizeBytes,
+			RandSeed:                *randSeedF,
 		},
 	}
 
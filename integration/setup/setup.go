@@ -52,6 +52,7 @@ var (
 	hanaURLF       = flag.String("hana-url", "", "in-process FerretDB: Hana URL for 'hana' handler.")
 
 	batchSizeF = flag.Int("batch-size", 100, "maximum insertion batch size")
+	randSeedF  = flag.Uint64("rand-seed", 0, "in-process FerretDB: seed for $rand and $sampleRate; 0 means random")
 
 	compatURLF = flag.String("compat-url", "", "compat system's (MongoDB) URL for compatibility tests; if empty, they are skipped")
 
@@ -66,7 +67,7 @@ var (
 
 // Other globals.
 var (
-	allBackends = []string{"ferretdb-postgresql", "ferretdb-sqlite", "ferretdb-mysql", "ferretdb-hana", "mongodb"}
+	allBackends = []string{"ferretdb-postgresql", "ferretdb-sqlite", "ferretdb-memory", "ferretdb-mysql", "ferretdb-hana", "mongodb"}
 )
 
 // SetupOpts represents setup options.
@@ -104,8 +105,32 @@ type BackendOpts struct {
 	// MaxBsonObjectSizeBytes is the maximum allowed size of a document, if not set FerretDB sets the default.
 	MaxBsonObjectSizeBytes int
 
+	// ResultCacheSize is the maximum size of read commands result cache in bytes, if not set the cache is disabled.
+	ResultCacheSize int64
+
+	// CursorReadaheadSize is the maximum size of documents prefetched by cursors in bytes, if not set readahead is disabled.
+	CursorReadaheadSize int64
+
+	// OperationMemoryLimit is the maximum memory used by in-memory sort and group of an operation in bytes,
+	// if not set the limit is disabled.
+	OperationMemoryLimit int64
+
 	// DisableNewAuth true uses the old backend authentication.
 	DisableNewAuth bool
+
+	// EnableExplainSQL adds generated SQL queries to explain output.
+	EnableExplainSQL bool
+
+	// ExportURL is a base URL for exportCollection and importCollection commands.
+	ExportURL string
+
+	// BackupURL is a base URL for createBackup and restoreBackup commands.
+	BackupURL string
+
+	// Quotas; zero values disable them.
+	QuotaMaxCollections int
+	QuotaMaxDocuments   int64
+	QuotaMaxSize        int64
 }
 
 // SetupResult represents setup results.
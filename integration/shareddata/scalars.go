@@ -319,6 +319,32 @@ var Int64s = &Values[string]{
 	},
 }
 
+// Decimal128s contains 128-bit decimal floating point values for tests.
+//
+// It is not a part of AllProviders; use it explicitly in tests for Decimal128 values.
+var Decimal128s = &Values[string]{
+	name: "Decimal128s",
+	data: map[string]any{
+		"decimal128":          must.NotFail(primitive.ParseDecimal128("42.13")),
+		"decimal128-whole":    must.NotFail(primitive.ParseDecimal128("42")),
+		"decimal128-trailing": must.NotFail(primitive.ParseDecimal128("42.1300")),
+		"decimal128-zero":     must.NotFail(primitive.ParseDecimal128("0")),
+		"decimal128-zero-exp": must.NotFail(primitive.ParseDecimal128("0E+3")),
+		"decimal128-negative": must.NotFail(primitive.ParseDecimal128("-42.13")),
+		"decimal128-fraction": must.NotFail(primitive.ParseDecimal128("0.1")),
+		"decimal128-exp":      must.NotFail(primitive.ParseDecimal128("1.5E+3")),
+		"decimal128-max":      must.NotFail(primitive.ParseDecimal128("9.999999999999999999999999999999999E+6144")),
+		"decimal128-min":      must.NotFail(primitive.ParseDecimal128("-9.999999999999999999999999999999999E+6144")),
+		"decimal128-smallest": must.NotFail(primitive.ParseDecimal128("1E-6176")),
+		"decimal128-digits":   must.NotFail(primitive.ParseDecimal128("1234567890123456789012345678901234")),
+
+		// decimal values near the edges of other number types
+		"decimal128-int64-max-plus": must.NotFail(primitive.ParseDecimal128("9223372036854775808")),
+		"decimal128-prec-max-plus":  must.NotFail(primitive.ParseDecimal128("9007199254740992.5")),
+		"decimal128-double-max":     must.NotFail(primitive.ParseDecimal128("1.797693134862316E+309")),
+	},
+}
+
 // Unsets contains unset value for tests.
 var Unsets = &Values[string]{
 	name: "Unsets",
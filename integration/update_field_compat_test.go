@@ -123,6 +123,12 @@ func TestUpdateFieldCompatInc(t *testing.T) {
 		"Int32Negative": {
 			update: bson.D{{"$inc", bson.D{{"v", int32(-42)}}}},
 		},
+		"Int32Max": {
+			update: bson.D{{"$inc", bson.D{{"v", int32(math.MaxInt32)}}}},
+		},
+		"Int32Min": {
+			update: bson.D{{"$inc", bson.D{{"v", int32(math.MinInt32)}}}},
+		},
 		"Int64Max": {
 			update: bson.D{{"$inc", bson.D{{"v", math.MaxInt64}}}},
 		},
@@ -146,6 +152,46 @@ func TestUpdateFieldCompatInc(t *testing.T) {
 	testUpdateCompat(t, testCases)
 }
 
+func TestUpdateFieldCompatIncDecimal128(t *testing.T) {
+	t.Parallel()
+
+	providers := []shareddata.Provider{
+		shareddata.Decimal128s,
+		shareddata.Int32s,
+		shareddata.Int64s,
+		shareddata.Doubles,
+	}
+
+	testCases := map[string]updateCompatTestCase{
+		"Decimal128": {
+			update:    bson.D{{"$inc", bson.D{{"v", must.NotFail(primitive.ParseDecimal128("42.13"))}}}},
+			providers: providers,
+		},
+		"Decimal128Negative": {
+			update:    bson.D{{"$inc", bson.D{{"v", must.NotFail(primitive.ParseDecimal128("-1.5"))}}}},
+			providers: providers,
+		},
+		"Decimal128Zero": {
+			update:    bson.D{{"$inc", bson.D{{"v", must.NotFail(primitive.ParseDecimal128("0.00"))}}}},
+			providers: providers,
+		},
+		"Int32": {
+			update:    bson.D{{"$inc", bson.D{{"v", int32(42)}}}},
+			providers: []shareddata.Provider{shareddata.Decimal128s},
+		},
+		"Double": {
+			update:    bson.D{{"$inc", bson.D{{"v", 42.13}}}},
+			providers: []shareddata.Provider{shareddata.Decimal128s},
+		},
+		"Missing": {
+			update:    bson.D{{"$inc", bson.D{{"missing", must.NotFail(primitive.ParseDecimal128("42.13"))}}}},
+			providers: providers,
+		},
+	}
+
+	testUpdateCompat(t, testCases)
+}
+
 func TestUpdateFieldCompatIncComplex(t *testing.T) {
 	t.Parallel()
 
@@ -222,7 +268,6 @@ func TestUpdateFieldCompatIncComplex(t *testing.T) {
 		},
 		"DotNotatArrayFieldNotExist": {
 			update: bson.D{{"$inc", bson.D{{"v.array.foo", int32(1)}}}},
-			skip:   "TODO: fix namespace error",
 		},
 		"DotNotatArrFieldExist": {
 			update: bson.D{{"$inc", bson.D{{"v.array.0", int32(1)}}}},
@@ -620,7 +665,6 @@ func TestUpdateFieldCompatUnset(t *testing.T) {
 		},
 		"DotArrayField": {
 			update: bson.D{{"$unset", bson.D{{"v.array.0", ""}}}},
-			skip:   "https://github.com/FerretDB/FerretDB/issues/1242",
 		},
 		"DotNotationArrNonExistentPath": {
 			update:     bson.D{{"$unset", bson.D{{"non.0.existent", int32(1)}}}},
@@ -1278,6 +1322,48 @@ func TestUpdateFieldCompatMul(t *testing.T) {
 	testUpdateCompat(t, testCases)
 }
 
+func TestUpdateFieldCompatMulDecimal128(t *testing.T) {
+	t.Parallel()
+
+	// multipliers that produce -0 or infinity are not used;
+	// see https://docs.ferretdb.io/diff/
+	providers := []shareddata.Provider{
+		shareddata.Decimal128s,
+		shareddata.Int32s,
+		shareddata.Int64s,
+		shareddata.Doubles,
+	}
+
+	testCases := map[string]updateCompatTestCase{
+		"Half": {
+			update:    bson.D{{"$mul", bson.D{{"v", must.NotFail(primitive.ParseDecimal128("0.5"))}}}},
+			providers: providers,
+		},
+		"Trailing": {
+			update:    bson.D{{"$mul", bson.D{{"v", must.NotFail(primitive.ParseDecimal128("1.00"))}}}},
+			providers: providers,
+		},
+		"Fraction": {
+			update:    bson.D{{"$mul", bson.D{{"v", must.NotFail(primitive.ParseDecimal128("0.1"))}}}},
+			providers: providers,
+		},
+		"Int32": {
+			update:    bson.D{{"$mul", bson.D{{"v", int32(2)}}}},
+			providers: []shareddata.Provider{shareddata.Decimal128s},
+		},
+		"Double": {
+			update:    bson.D{{"$mul", bson.D{{"v", 0.1}}}},
+			providers: []shareddata.Provider{shareddata.Decimal128s},
+		},
+		"Missing": {
+			update:    bson.D{{"$mul", bson.D{{"missing", must.NotFail(primitive.ParseDecimal128("1.5"))}}}},
+			providers: providers,
+		},
+	}
+
+	testUpdateCompat(t, testCases)
+}
+
 func TestUpdateFieldCompatBit(t *testing.T) {
 	t.Parallel()
 
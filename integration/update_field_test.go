@@ -17,10 +17,12 @@ package integration
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -133,6 +135,34 @@ func TestUpdateFieldSetIDDoc(t *testing.T) {
 	AssertEqualDocumentsSlice(t, expected, FindAll(t, ctx, collection))
 }
 
+func TestUpdateFieldCurrentDateTimestamp(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "ts"}})
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Second)
+
+	var prev primitive.Timestamp
+
+	for range 10 {
+		_, err = collection.UpdateByID(ctx, "ts", bson.D{{"$currentDate", bson.D{{"v", bson.D{{"$type", "timestamp"}}}}}})
+		require.NoError(t, err)
+
+		var res struct {
+			V primitive.Timestamp `bson:"v"`
+		}
+		require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", "ts"}}).Decode(&res))
+
+		assert.GreaterOrEqual(t, res.V.T, uint32(start.Unix()))
+		assert.True(t, primitive.CompareTimestamp(prev, res.V) < 0, "%v is not after %v", res.V, prev)
+
+		prev = res.V
+	}
+}
+
 func TestUpdateFieldSetUpdateManyUpsert(t *testing.T) {
 	t.Parallel()
 
@@ -363,6 +393,91 @@ func TestUpdateCommandUpsert(t *testing.T) {
 	}
 }
 
+func TestUpdateCommandWriteErrors(t *testing.T) {
+	t.Parallel()
+
+	updates := bson.A{
+		bson.D{{"q", bson.D{{"_id", "int"}}}, {"u", bson.D{{"$inc", bson.D{{"v", int32(1)}}}}}},
+		bson.D{{"q", bson.D{{"_id", "string"}}}, {"u", bson.D{{"$inc", bson.D{{"v", int32(1)}}}}}},
+		bson.D{{"q", bson.D{{"_id", "long"}}}, {"u", bson.D{{"$inc", bson.D{{"v", int32(1)}}}}}},
+		bson.D{{"q", bson.D{{"_id", "new"}}}, {"u", bson.D{{"$set", bson.D{{"v", int32(1)}}}}}, {"upsert", true}},
+	}
+
+	for name, tc := range map[string]struct {
+		ordered bool
+
+		n         int32
+		nModified int32
+		upserted  bool
+		expected  []bson.D
+	}{
+		"Ordered": {
+			ordered:   true,
+			n:         1,
+			nModified: 1,
+			expected: []bson.D{
+				{{"_id", "int"}, {"v", int32(43)}},
+				{{"_id", "long"}, {"v", int64(42)}},
+				{{"_id", "string"}, {"v", "foo"}},
+			},
+		},
+		"Unordered": {
+			ordered:   false,
+			n:         3,
+			nModified: 2,
+			upserted:  true,
+			expected: []bson.D{
+				{{"_id", "int"}, {"v", int32(43)}},
+				{{"_id", "long"}, {"v", int64(43)}},
+				{{"_id", "new"}, {"v", int32(1)}},
+				{{"_id", "string"}, {"v", "foo"}},
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, collection := setup.Setup(t)
+
+			_, err := collection.InsertMany(ctx, []any{
+				bson.D{{"_id", "int"}, {"v", int32(42)}},
+				bson.D{{"_id", "string"}, {"v", "foo"}},
+				bson.D{{"_id", "long"}, {"v", int64(42)}},
+			})
+			require.NoError(t, err)
+
+			raw, err := collection.Database().RunCommand(ctx, bson.D{
+				{"update", collection.Name()},
+				{"updates", updates},
+				{"ordered", tc.ordered},
+			}).Raw()
+
+			var we mongo.WriteException
+			require.ErrorAs(t, err, &we)
+			require.Len(t, we.WriteErrors, 1)
+			assert.Equal(t, 1, we.WriteErrors[0].Index)
+			assert.Equal(t, 14, we.WriteErrors[0].Code)
+
+			var res bson.D
+			require.NoError(t, bson.Unmarshal(raw, &res))
+
+			doc := ConvertDocument(t, res)
+			assert.Equal(t, tc.n, must.NotFail(doc.Get("n")))
+			assert.Equal(t, tc.nModified, must.NotFail(doc.Get("nModified")))
+
+			if tc.upserted {
+				expected := must.NotFail(types.NewArray(must.NotFail(types.NewDocument("index", int32(3), "_id", "new"))))
+				assert.Equal(t, expected, must.NotFail(doc.Get("upserted")))
+			} else {
+				assert.False(t, doc.Has("upserted"))
+			}
+
+			AssertEqualDocumentsSlice(t, tc.expected, FindAll(t, ctx, collection))
+		})
+	}
+}
+
 func TestUpdateFieldErrors(t *testing.T) {
 	t.Parallel()
 
@@ -383,7 +498,6 @@ func TestUpdateFieldErrors(t *testing.T) {
 				Message: "cannot use the part (v of v.foo) to traverse the element " +
 					"({v: [ { foo: [ { bar: \"hello\" }, { bar: \"world\" } ] } ]})",
 			},
-			altMessage: "cannot use path 'v.foo' to traverse the document",
 		},
 		"SetImmutableID": {
 			id:     "array-documents-nested",
@@ -417,7 +531,6 @@ func TestUpdateFieldErrors(t *testing.T) {
 				Message: "cannot use the part (v of v.-1) to traverse the element " +
 					"({v: [ { foo: [ { bar: \"hello\" }, { bar: \"world\" } ] } ]})",
 			},
-			altMessage: "cannot use path 'v.-1' to traverse the document",
 		},
 		"RenameUnsuitableValue": {
 			id:     "array-documents-nested",
@@ -426,7 +539,34 @@ func TestUpdateFieldErrors(t *testing.T) {
 				Code:    28,
 				Message: "cannot use the part (bar of v.0.foo.0.bar.z) to traverse the element ({bar: \"hello\"})",
 			},
-			altMessage: "types.getByPath: can't access string by path \"z\"",
+		},
+		"RenameSourceArrayElement": {
+			id:     "array-documents-nested",
+			update: bson.D{{"$rename", bson.D{{"v.0.foo", "f"}}}},
+			err: &mongo.WriteError{
+				Code: 2,
+				Message: "The source field cannot be an array element, 'v.0.foo' in doc " +
+					"with _id: \"array-documents-nested\" has an array field called 'v'",
+			},
+		},
+		"RenameDestinationArrayElement": {
+			id:       "document-composite",
+			update:   bson.D{{"$rename", bson.D{{"v.foo", "v.array.3"}}}},
+			provider: shareddata.Composites,
+			err: &mongo.WriteError{
+				Code: 2,
+				Message: "The destination field cannot be an array element, 'v.array.3' in doc " +
+					"with _id: \"document-composite\" has an array field called 'array'",
+			},
+		},
+		"RenameDestinationArrayField": {
+			id:       "document-composite",
+			update:   bson.D{{"$rename", bson.D{{"v.foo", "v.array.foo"}}}},
+			provider: shareddata.Composites,
+			err: &mongo.WriteError{
+				Code:    28,
+				Message: "Cannot create field 'foo' in element {array: [ 42, \"foo\", null ]}",
+			},
 		},
 		"IncTypeMismatch": {
 			id:     "array-documents-nested",
@@ -445,6 +585,23 @@ func TestUpdateFieldErrors(t *testing.T) {
 					"{v: [ { foo: [ { bar: \"hello\" }, { bar: \"world\" } ] } ]}",
 			},
 		},
+		"IncArrayNegativeIndex": {
+			id:     "array-documents-nested",
+			update: bson.D{{"$inc", bson.D{{"v.-1", 1}}}},
+			err: &mongo.WriteError{
+				Code: 28,
+				Message: "Cannot create field '-1' in element " +
+					"{v: [ { foo: [ { bar: \"hello\" }, { bar: \"world\" } ] } ]}",
+			},
+		},
+		"SetScalarInArray": {
+			id:     "array-documents-nested",
+			update: bson.D{{"$set", bson.D{{"v.0.foo.0.bar.baz", 1}}}},
+			err: &mongo.WriteError{
+				Code:    28,
+				Message: "Cannot create field 'baz' in element {bar: \"hello\"}",
+			},
+		},
 		"IncNonNumeric": {
 			id:     "array-documents-nested",
 			update: bson.D{{"$inc", bson.D{{"v.0.foo.0.bar", 1}}}},
@@ -509,7 +509,7 @@ func TestAuthenticationPLAIN(t *testing.T) {
 
 			t := setup.FailsForMongoDB(tt, "PLAIN mechanism is not supported by MongoDB")
 
-			if setup.IsSQLite(t) && tc.failsForSQLite != "" {
+			if (setup.IsSQLite(t) || setup.IsMemory(t)) && tc.failsForSQLite != "" {
 				t = testfail.Expected(tt, tc.failsForSQLite)
 			}
 
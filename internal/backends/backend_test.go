@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
@@ -56,6 +57,8 @@ func TestVersion(t *testing.T) {
 				assert.True(t, strings.HasPrefix(s.BackendVersion, "16.4 ("), "%s", s.BackendName)
 			case "SQLite":
 				assert.Equal(t, "3.46.0", s.BackendVersion)
+			case "memory":
+				assert.Equal(t, version.Get().Version, s.BackendVersion)
 			case "hana":
 				assert.Equal(t, "4.00.000.00.1693911223", s.BackendVersion)
 			default:
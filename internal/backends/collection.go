@@ -18,6 +18,7 @@ import (
 	"cmp"
 	"context"
 	"slices"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -43,6 +44,8 @@ const DefaultIndexName = "_id_"
 // See collectionContract and its methods for additional details.
 type Collection interface {
 	Query(context.Context, *QueryParams) (*QueryResult, error)
+	Count(context.Context, *CountParams) (*CountResult, error)
+	GroupCount(context.Context, *GroupCountParams) (*GroupCountResult, error)
 	Explain(context.Context, *ExplainParams) (*ExplainResult, error)
 	InsertAll(context.Context, *InsertAllParams) (*InsertAllResult, error)
 	UpdateAll(context.Context, *UpdateAllParams) (*UpdateAllResult, error)
@@ -85,7 +88,8 @@ type QueryParams struct {
 
 // QueryResult represents the results of Collection.Query method.
 type QueryResult struct {
-	Iter types.DocumentsIterator
+	Iter         types.DocumentsIterator
+	SortPushdown bool
 }
 
 // Query executes a query against the collection.
@@ -96,14 +100,27 @@ type QueryResult struct {
 // It also can be used to close the returned iterator and free underlying resources,
 // but doing so is not necessary - the handler will do that anyway.
 //
+// The returned iterator should see a consistent snapshot of the collection taken when the query started,
+// even if it is consumed over several getMore commands.
+// Documents inserted, updated, or deleted after that should not be visible to it.
+// SQL backends do that by running the query in a read-only transaction
+// (with REPEATABLE READ isolation level where applicable) that is rolled back when the iterator is closed;
+// the memory backend copies the list of (never modified in place) documents.
+//
 // Filter may be ignored, or safely applied partially or entirely.
 // Extra documents will be filtered out by the handler.
 //
-// Sort should have one of the following forms: nil, {}, {"$natural": int64(1)} or {"$natural": int64(-1)}.
-// Other field names are not supported.
-// If non-empty, it should be applied.
+// Sort should have one of the following forms: nil, {}, {"$natural": int64(1)}, {"$natural": int64(-1)},
+// or a document with field paths as keys and int64(1) or int64(-1) as values.
+// $natural sort, if present, should be applied.
+// For capped collections, natural order is the RecordID order;
+// for other collections, it is the backend's storage order (usually the insertion order).
+// Sort by fields may be applied only if the backend can satisfy it with an existing index;
+// the QueryResult's SortPushdown field is set to true in that case.
+// Otherwise, documents are returned in any order, and the handler sorts them.
 //
 // Limit, if non-zero, should be applied.
+// The handler does not set it together with sort by fields.
 func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*QueryResult, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "Query")
 	defer span.End()
@@ -112,16 +129,44 @@ func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*
 		params = new(QueryParams)
 	}
 
-	if params.Sort.Len() != 0 {
-		must.BeTrue(params.Sort.Len() == 1)
-		sortValue := params.Sort.Map()["$natural"].(int64)
+	checkSort(params.Sort)
+	must.BeTrue(params.Limit == 0 || params.Sort.Len() == 0 || params.Sort.Has("$natural"))
 
-		if sortValue != -1 && sortValue != 1 {
-			panic("sort value must be 1 (for ascending) or -1 (for descending)")
-		}
+	res, err := cc.c.Query(ctx, params)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, "")
 	}
 
-	res, err := cc.c.Query(ctx, params)
+	checkError(err)
+
+	return res, err
+}
+
+// CountParams represents the parameters of Collection.Count method.
+type CountParams struct {
+	Comment string
+}
+
+// CountResult represents the results of Collection.Count method.
+type CountResult struct {
+	Count int64
+}
+
+// Count returns the number of documents in the collection.
+//
+// If database or collection does not exist it returns 0.
+//
+// The result should be exact for small collections,
+// but it may be an estimate based on backend statistics for large collections.
+func (cc *collectionContract) Count(ctx context.Context, params *CountParams) (*CountResult, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "Count")
+	defer span.End()
+
+	if params == nil {
+		params = new(CountParams)
+	}
+
+	res, err := cc.c.Count(ctx, params)
 	if err != nil {
 		span.SetStatus(otelcodes.Error, "")
 	}
@@ -131,6 +176,59 @@ func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*
 	return res, err
 }
 
+// GroupCountParams represents the parameters of Collection.GroupCount method.
+type GroupCountParams struct {
+	Filter  *types.Document
+	GroupBy string
+	Comment string
+}
+
+// GroupCountResult represents the results of Collection.GroupCount method.
+type GroupCountResult struct {
+	Groups []GroupCount
+}
+
+// GroupCount represents a single group returned by Collection.GroupCount method.
+type GroupCount struct {
+	ID    any
+	Count int64
+}
+
+// GroupCount returns the number of documents matching the filter, grouped by the value of the given field.
+//
+// If database or collection does not exist it returns no groups.
+//
+// Filter should be applied exactly, without returning extra documents.
+// If that is not possible for the given filter, ErrorCodeGroupCountNotSupported error is returned,
+// and the handler groups documents itself.
+//
+// GroupBy is a top-level field name; empty GroupBy puts all matching documents into a single group with null ID.
+// Documents with missing and null values are counted in groups with null ID.
+// If the backend can't group by the given field, ErrorCodeGroupCountNotSupported error is returned.
+//
+// Groups with IDs that are equal according to BSON comparison rules (for example, int32(1) and float64(1))
+// may be returned separately; the handler merges them.
+// Groups without documents are not returned.
+func (cc *collectionContract) GroupCount(ctx context.Context, params *GroupCountParams) (*GroupCountResult, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "GroupCount")
+	defer span.End()
+
+	if params == nil {
+		params = new(GroupCountParams)
+	}
+
+	must.BeTrue(!strings.ContainsRune(params.GroupBy, '.'))
+
+	res, err := cc.c.GroupCount(ctx, params)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, "")
+	}
+
+	checkError(err, ErrorCodeGroupCountNotSupported)
+
+	return res, err
+}
+
 // ExplainParams represents the parameters of Collection.Explain method.
 type ExplainParams struct {
 	Filter *types.Document
@@ -144,6 +242,11 @@ type ExplainResult struct {
 	FilterPushdown bool
 	SortPushdown   bool
 	LimitPushdown  bool
+
+	// SQL query that would be executed for the given parameters (without EXPLAIN) and its arguments.
+	// Empty for backends that do not use SQL.
+	Query string
+	Args  []any
 }
 
 // Explain return a backend-specific execution plan for the given query.
@@ -157,6 +260,11 @@ type ExplainResult struct {
 //
 // The ExplainResult's SortPushdown field is set to true if the backend could have applied the whole requested sorting.
 // If it was possible to apply it only partially or not at all, that field should be set to false.
+//
+// The ExplainResult's Query and Args fields are set to the generated SQL query and its arguments
+// if the backend uses SQL and the collection exists.
+//
+// Sort has the same forms as for Query.
 func (cc *collectionContract) Explain(ctx context.Context, params *ExplainParams) (*ExplainResult, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "Explain")
 	defer span.End()
@@ -165,14 +273,7 @@ func (cc *collectionContract) Explain(ctx context.Context, params *ExplainParams
 		params = new(ExplainParams)
 	}
 
-	if params.Sort.Len() != 0 {
-		must.BeTrue(params.Sort.Len() == 1)
-		sortValue := params.Sort.Map()["$natural"].(int64)
-
-		if sortValue != -1 && sortValue != 1 {
-			panic("sort value must be 1 (for ascending) or -1 (for descending)")
-		}
-	}
+	checkSort(params.Sort)
 
 	res, err := cc.c.Explain(ctx, params)
 	if err != nil {
@@ -201,6 +302,11 @@ type InsertAllResult struct{}
 // All documents are expected to be valid and include _id fields.
 // They will be frozen.
 //
+// Documents should be stored as is: Query should return them with the same order of fields
+// (including fields of nested documents) and the same value types,
+// even if values of different numeric types are equal.
+// The same applies to UpdateAll.
+//
 // Both database and collection may or may not exist; they should be created automatically if needed.
 func (cc *collectionContract) InsertAll(ctx context.Context, params *InsertAllParams) (*InsertAllResult, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "InsertAll")
@@ -222,6 +328,20 @@ func (cc *collectionContract) InsertAll(ctx context.Context, params *InsertAllPa
 	return res, err
 }
 
+// VersionField is the name of the document field maintained by the handler in versioned collections.
+const VersionField = "_version"
+
+// PreviousVersion returns the VersionField value that the stored document should have
+// for the given document of versioned collection to be updated.
+//
+// Missing stored value is treated as 0.
+func PreviousVersion(doc *types.Document) int64 {
+	v, _ := doc.Get(VersionField)
+	n, _ := v.(int64)
+
+	return n - 1
+}
+
 // UpdateAllParams represents the parameters of Collection.Update method.
 type UpdateAllParams struct {
 	Docs []*types.Document
@@ -241,6 +361,10 @@ type UpdateAllResult struct {
 // All documents are expected to be valid and include _id fields.
 // They will be frozen.
 //
+// For versioned collections, a document is updated only if the stored document's VersionField value
+// is equal to PreviousVersion of the given one; other documents are skipped without an error.
+// The handler uses that to detect concurrent modifications.
+//
 // Database or collection may not exist; that's not an error.
 func (cc *collectionContract) UpdateAll(ctx context.Context, params *UpdateAllParams) (*UpdateAllResult, error) {
 	ctx, span := otel.Tracer("").Start(ctx, "UpdateAll")
@@ -375,12 +499,19 @@ type IndexInfo struct {
 	Name   string
 	Key    []IndexKeyPair
 	Unique bool
+
+	// Options contains other index options exactly as they were specified, such as `sparse`.
+	// Backends store them as is. It may be nil.
+	Options *types.Document
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
+//
+// Hashed fields are used only for equality matches; Descending is always false for them.
 type IndexKeyPair struct {
 	Field      string
 	Descending bool
+	Hashed     bool
 }
 
 // ListIndexes returns a list of collection indexes.
@@ -468,3 +599,20 @@ func (cc *collectionContract) DropIndexes(ctx context.Context, params *DropIndex
 var (
 	_ Collection = (*collectionContract)(nil)
 )
+
+// checkSort panics if the given sort document has unexpected form.
+func checkSort(sort *types.Document) {
+	if sort.Len() == 0 {
+		return
+	}
+
+	if sort.Has("$natural") {
+		must.BeTrue(sort.Len() == 1)
+	}
+
+	for _, v := range sort.Values() {
+		if sortValue := v.(int64); sortValue != -1 && sortValue != 1 {
+			panic("sort value must be 1 (for ascending) or -1 (for descending)")
+		}
+	}
+}
@@ -15,8 +15,10 @@
 package backends_test // to avoid import cycle
 
 import (
+	"math"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -246,6 +248,90 @@ func TestCollectionInsertAllQueryExplain(t *testing.T) {
 	}
 }
 
+func TestCollectionFieldOrder(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	// keys are in the order that is not preserved by sorting them in any way,
+	// and values of different types are equal when compared as numbers
+	doc := must.NotFail(types.NewDocument(
+		"_id", int32(1),
+		"b", int32(1),
+		"a", int64(1),
+		"10", float64(1),
+		"2", "1",
+		"aa", must.NotFail(types.NewDocument(
+			"z", must.NotFail(types.NewDocument("y", types.Null, "x", false)),
+			"a", types.MakeArray(0),
+			"m", must.NotFail(types.NewDocument()),
+		)),
+		"é", must.NotFail(types.NewArray(
+			must.NotFail(types.NewDocument("d", int32(2), "c", int64(2))),
+			int64(2), int32(2), float64(2.5),
+			types.Timestamp(42),
+			types.Binary{Subtype: types.BinaryGeneric, B: []byte{42}},
+			types.Regex{Pattern: "^foo", Options: "i"},
+		)),
+		"A", types.ObjectID{1},
+		"edge", must.NotFail(types.NewArray(
+			int64(math.MaxInt64), int64(math.MinInt64), int32(math.MinInt32),
+			math.MaxFloat64, math.SmallestNonzeroFloat64, float64(1<<53+2),
+			time.Date(2024, 2, 29, 23, 59, 59, 999_000_000, time.UTC),
+		)),
+	))
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+				require.NoError(t, err)
+			})
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc.DeepCopy()}})
+			require.NoError(t, err)
+
+			queryRes, err := coll.Query(ctx, nil)
+			require.NoError(t, err)
+
+			docs, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+			require.NoError(t, err)
+			testutil.AssertEqualSlices(t, []*types.Document{doc}, docs)
+
+			// the order of fields is the order of the updated document, not the stored one
+			updated := must.NotFail(types.NewDocument("_id", int32(1)))
+
+			keys := doc.Keys()
+			for i := len(keys) - 1; i > 0; i-- {
+				updated.Set(keys[i], must.NotFail(doc.Get(keys[i])))
+			}
+
+			updateRes, err := coll.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{updated.DeepCopy()}})
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), updateRes.Updated)
+
+			queryRes, err = coll.Query(ctx, nil)
+			require.NoError(t, err)
+
+			docs, err = iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+			require.NoError(t, err)
+			testutil.AssertEqualSlices(t, []*types.Document{updated}, docs)
+		})
+	}
+}
+
 func TestCappedCollectionInsertAllDeleteAll(t *testing.T) {
 	t.Parallel()
 
@@ -401,6 +487,426 @@ func TestCollectionUpdateAll(t *testing.T) {
 				})
 				assert.True(t, present)
 			})
+
+			t.Run("Versioned", func(t *testing.T) {
+				t.Parallel()
+
+				dbName, collName := testutil.DatabaseName(t), testutil.CollectionName(t)
+
+				db, err := b.Database(dbName)
+				require.NoError(t, err)
+
+				t.Cleanup(func() {
+					err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+					require.NoError(t, err)
+				})
+
+				err = db.CreateCollection(ctx, &backends.CreateCollectionParams{
+					Name:      collName,
+					Versioned: true,
+				})
+				require.NoError(t, err)
+
+				collRes, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: collName})
+				require.NoError(t, err)
+				require.Len(t, collRes.Collections, 1)
+				assert.True(t, collRes.Collections[0].Versioned)
+
+				coll, err := db.Collection(collName)
+				require.NoError(t, err)
+
+				_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+					Docs: []*types.Document{
+						must.NotFail(types.NewDocument("_id", int32(1), backends.VersionField, int64(1), "v", "a")),
+						must.NotFail(types.NewDocument("_id", int32(2), "v", "a")),
+					},
+				})
+				require.NoError(t, err)
+
+				updateRes, err := coll.UpdateAll(ctx, &backends.UpdateAllParams{
+					Docs: []*types.Document{
+						must.NotFail(types.NewDocument("_id", int32(1), backends.VersionField, int64(2), "v", "b")),
+						must.NotFail(types.NewDocument("_id", int32(2), backends.VersionField, int64(1), "v", "b")),
+					},
+				})
+				require.NoError(t, err)
+				assert.Equal(t, int32(2), updateRes.Updated)
+
+				// stale documents are skipped
+				updateRes, err = coll.UpdateAll(ctx, &backends.UpdateAllParams{
+					Docs: []*types.Document{
+						must.NotFail(types.NewDocument("_id", int32(1), backends.VersionField, int64(2), "v", "c")),
+						must.NotFail(types.NewDocument("_id", int32(2), backends.VersionField, int64(2), "v", "c")),
+					},
+				})
+				require.NoError(t, err)
+				assert.Equal(t, int32(1), updateRes.Updated)
+
+				queryRes, err := coll.Query(ctx, nil)
+				require.NoError(t, err)
+
+				docs, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+				require.NoError(t, err)
+				require.Len(t, docs, 2)
+
+				for _, doc := range docs {
+					switch must.NotFail(doc.Get("_id")) {
+					case int32(1):
+						assert.Equal(t, "b", must.NotFail(doc.Get("v")))
+					case int32(2):
+						assert.Equal(t, "c", must.NotFail(doc.Get("v")))
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestCollectionCount(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			res, err := coll.Count(ctx, &backends.CountParams{Comment: "count"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), res.Count)
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+				Docs: []*types.Document{
+					must.NotFail(types.NewDocument("_id", int32(1))),
+					must.NotFail(types.NewDocument("_id", int32(2))),
+				},
+			})
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+				require.NoError(t, err)
+			})
+
+			res, err = coll.Count(ctx, nil)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), res.Count)
+		})
+	}
+}
+
+func TestCollectionGroupCount(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			res, err := coll.GroupCount(ctx, &backends.GroupCountParams{GroupBy: "v"})
+			require.NoError(t, err)
+			assert.Empty(t, res.Groups)
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+				Docs: []*types.Document{
+					must.NotFail(types.NewDocument("_id", "1", "v", "foo")),
+					must.NotFail(types.NewDocument("_id", "2", "v", "foo")),
+					must.NotFail(types.NewDocument("_id", "3", "v", int32(42))),
+					must.NotFail(types.NewDocument("_id", "4", "v", must.NotFail(types.NewArray("foo")))),
+					must.NotFail(types.NewDocument("_id", "5", "v", types.Null)),
+					must.NotFail(types.NewDocument("_id", "6")),
+				},
+			})
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+				require.NoError(t, err)
+			})
+
+			res, err = coll.GroupCount(ctx, &backends.GroupCountParams{GroupBy: "v"})
+			require.NoError(t, err)
+
+			// null and missing values may be returned as separate groups
+			var groups []backends.GroupCount
+			var nulls int64
+
+			for _, g := range res.Groups {
+				if g.ID == types.Null {
+					nulls += g.Count
+					continue
+				}
+
+				groups = append(groups, g)
+			}
+
+			assert.ElementsMatch(t, []backends.GroupCount{
+				{ID: "foo", Count: 2},
+				{ID: int32(42), Count: 1},
+				{ID: must.NotFail(types.NewArray("foo")), Count: 1},
+			}, groups)
+			assert.Equal(t, int64(2), nulls)
+
+			res, err = coll.GroupCount(ctx, nil)
+			require.NoError(t, err)
+			assert.Equal(t, []backends.GroupCount{{ID: types.Null, Count: 6}}, res.Groups)
+
+			res, err = coll.GroupCount(ctx, &backends.GroupCountParams{
+				Filter:  must.NotFail(types.NewDocument("_id", "3")),
+				GroupBy: "v",
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []backends.GroupCount{{ID: int32(42), Count: 1}}, res.Groups)
+
+			_, err = coll.GroupCount(ctx, &backends.GroupCountParams{
+				Filter:  must.NotFail(types.NewDocument("v", "foo")),
+				GroupBy: "v",
+			})
+			assertErrorCode(t, err, backends.ErrorCodeGroupCountNotSupported)
+		})
+	}
+}
+
+func TestCollectionIndexOptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+				require.NoError(t, err)
+			})
+
+			options := must.NotFail(types.NewDocument(
+				"sparse", true,
+				"partialFilterExpression", must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$gt", int32(1))))),
+				"expireAfterSeconds", int32(3600),
+			))
+
+			_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{
+				Indexes: []backends.IndexInfo{
+					{Name: "v_1", Key: []backends.IndexKeyPair{{Field: "v"}}, Options: options},
+					{Name: "w_1", Key: []backends.IndexKeyPair{{Field: "w"}}},
+				},
+			})
+			require.NoError(t, err)
+
+			res, err := coll.ListIndexes(ctx, nil)
+			require.NoError(t, err)
+			require.Len(t, res.Indexes, 3)
+
+			assert.Nil(t, res.Indexes[0].Options) // _id_
+			testutil.AssertEqual(t, options, res.Indexes[1].Options)
+			assert.Nil(t, res.Indexes[2].Options)
+		})
+	}
+}
+
+func TestCollectionQuerySortIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+				require.NoError(t, err)
+			})
+
+			_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{
+				Indexes: []backends.IndexInfo{{
+					Name: "a_1_b_-1",
+					Key:  []backends.IndexKeyPair{{Field: "a"}, {Field: "b", Descending: true}},
+				}},
+			})
+			require.NoError(t, err)
+
+			sorted := []*types.Document{
+				must.NotFail(types.NewDocument("_id", int32(3))),
+				must.NotFail(types.NewDocument("_id", int32(4), "a", int32(1), "b", int32(2))),
+				must.NotFail(types.NewDocument("_id", int32(1), "a", int32(1), "b", int32(1))),
+				must.NotFail(types.NewDocument("_id", int32(2), "a", int32(2), "b", int32(1))),
+			}
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+				Docs: []*types.Document{sorted[2], sorted[3], sorted[0], sorted[1]},
+			})
+			require.NoError(t, err)
+
+			for sortName, tc := range map[string]struct {
+				sort     *types.Document
+				expected []*types.Document
+			}{
+				"Index": {
+					sort:     must.NotFail(types.NewDocument("a", int64(1), "b", int64(-1))),
+					expected: sorted,
+				},
+				"Reversed": {
+					sort:     must.NotFail(types.NewDocument("a", int64(-1), "b", int64(1))),
+					expected: []*types.Document{sorted[3], sorted[2], sorted[1], sorted[0]},
+				},
+				"Mixed": {
+					sort: must.NotFail(types.NewDocument("a", int64(1), "b", int64(1))),
+				},
+				"NotPrefix": {
+					sort: must.NotFail(types.NewDocument("b", int64(-1))),
+				},
+			} {
+				sortName, tc := sortName, tc
+				t.Run(sortName, func(t *testing.T) {
+					t.Parallel()
+
+					queryRes, err := coll.Query(ctx, &backends.QueryParams{Sort: tc.sort})
+					require.NoError(t, err)
+
+					docs, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+					require.NoError(t, err)
+					require.Len(t, docs, len(sorted))
+
+					explainRes, err := coll.Explain(ctx, &backends.ExplainParams{Sort: tc.sort})
+					require.NoError(t, err)
+					assert.Equal(t, queryRes.SortPushdown, explainRes.SortPushdown)
+
+					if tc.expected == nil || name != "postgresql" {
+						assert.False(t, queryRes.SortPushdown)
+						return
+					}
+
+					require.True(t, queryRes.SortPushdown)
+					testutil.AssertEqualSlices(t, tc.expected, docs)
+				})
+			}
+		})
+	}
+}
+
+func TestCollectionQuerySnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	for name, b := range testBackends(t) {
+		name, b := name, b
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dbName := testutil.DatabaseName(t)
+			collName := testutil.CollectionName(t)
+
+			db, err := b.Database(dbName)
+			require.NoError(t, err)
+
+			coll, err := db.Collection(collName)
+			require.NoError(t, err)
+
+			t.Cleanup(func() {
+				err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+				require.NoError(t, err)
+			})
+
+			// enough documents to not fit into driver's and network buffers,
+			// so the iterator has to read them from the database after changes are made
+			expected := make([]*types.Document, 1000)
+			for i := range expected {
+				expected[i] = must.NotFail(types.NewDocument("_id", int32(i), "v", "old"))
+			}
+
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: expected})
+			require.NoError(t, err)
+
+			queryRes, err := coll.Query(ctx, nil)
+			require.NoError(t, err)
+
+			t.Cleanup(queryRes.Iter.Close)
+
+			_, first, err := queryRes.Iter.Next()
+			require.NoError(t, err)
+
+			docs := []*types.Document{first}
+
+			// changes made while the iterator is open should not be visible to it
+			_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+				Docs: []*types.Document{must.NotFail(types.NewDocument("_id", int32(len(expected)), "v", "new"))},
+			})
+			require.NoError(t, err)
+
+			updated := make([]*types.Document, len(expected))
+			for i := range updated {
+				updated[i] = must.NotFail(types.NewDocument("_id", int32(i), "v", "new"))
+			}
+
+			_, err = coll.UpdateAll(ctx, &backends.UpdateAllParams{Docs: updated[:len(updated)/2]})
+			require.NoError(t, err)
+
+			_, err = coll.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{int32(998), int32(999)}})
+			require.NoError(t, err)
+
+			rest, err := iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+			require.NoError(t, err)
+
+			docs = append(docs, rest...)
+
+			slices.SortFunc(docs, func(a, b *types.Document) int {
+				return int(must.NotFail(a.Get("_id")).(int32) - must.NotFail(b.Get("_id")).(int32))
+			})
+
+			testutil.AssertEqualSlices(t, expected, docs)
+
+			// new queries see the changes
+			queryRes, err = coll.Query(ctx, nil)
+			require.NoError(t, err)
+
+			docs, err = iterator.ConsumeValues[struct{}, *types.Document](queryRes.Iter)
+			require.NoError(t, err)
+			assert.Len(t, docs, len(expected)-2+1, name)
 		})
 	}
 }
@@ -47,7 +47,8 @@ type Database interface {
 
 // databaseContract implements Database interface.
 type databaseContract struct {
-	db Database
+	db   Database
+	name string
 }
 
 // DatabaseContract wraps Database and enforces its contract.
@@ -56,9 +57,10 @@ type databaseContract struct {
 // The handler should not use that function.
 //
 // See databaseContract and its methods for additional details.
-func DatabaseContract(db Database) Database {
+func DatabaseContract(db Database, name string) Database {
 	return &databaseContract{
-		db: db,
+		db:   db,
+		name: name,
 	}
 }
 
@@ -68,7 +70,7 @@ func DatabaseContract(db Database) Database {
 func (dbc *databaseContract) Collection(name string) (Collection, error) {
 	var res Collection
 
-	err := validateCollectionName(name)
+	err := validateCollectionName(dbc.name, name)
 	if err == nil {
 		res, err = dbc.db.Collection(name)
 	}
@@ -94,6 +96,7 @@ type CollectionInfo struct {
 	UUID            string
 	CappedSize      int64
 	CappedDocuments int64
+	Versioned       bool
 	_               struct{} // prevent unkeyed literals
 }
 
@@ -137,7 +140,39 @@ type CreateCollectionParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+
+	// PartitionKey is the name of top-level field used to partition collection's documents.
+	// Backends that do not support partitioning return ErrorCodeCollectionPartitioningNotSupported.
+	PartitionKey string
+
+	// PartitionHashed is true if documents are partitioned by the hash of the partition key value,
+	// and false if they are partitioned by ranges of that value.
+	PartitionHashed bool
+
+	// Partitions is the number of partitions for hashed partitioning.
+	Partitions int64
+
+	// PartitionBounds are split points for ranged partitioning.
+	PartitionBounds []any
+
+	// StorageOptions are PostgreSQL-specific storage options.
+	// Other backends ignore them.
+	StorageOptions StorageOptions
+
+	// Versioned is true if the handler maintains VersionField of collection's documents.
+	// See Collection.UpdateAll.
+	Versioned bool
+
+	_ struct{} // prevent unkeyed literals
+}
+
+// StorageOptions represents collection storage options set by advanced operators.
+//
+// Zero values mean that the default is used.
+type StorageOptions struct {
+	FillFactor       int64  // table fill factor percentage
+	ToastCompression string // compression method for large documents
+	Tablespace       string // tablespace name
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -145,6 +180,11 @@ func (ccp *CreateCollectionParams) Capped() bool {
 	return ccp.CappedSize > 0 // TODO https://github.com/FerretDB/FerretDB/issues/3631
 }
 
+// Partitioned returns true if partitioned collection creation is requested.
+func (ccp *CreateCollectionParams) Partitioned() bool {
+	return ccp.PartitionKey != ""
+}
+
 // CreateCollection creates a new collection with valid name in the database; it should not already exist.
 //
 // Database may or may not exist; it should be created automatically if needed.
@@ -154,8 +194,12 @@ func (dbc *databaseContract) CreateCollection(ctx context.Context, params *Creat
 
 	must.BeTrue(params.CappedSize >= 0)
 	must.BeTrue(params.CappedDocuments >= 0)
+	must.BeTrue(params.Partitions >= 0)
+	must.BeTrue(params.Partitioned() || (params.Partitions == 0 && params.PartitionBounds == nil))
+	must.BeTrue(!params.Partitioned() || !params.Capped())
+	must.BeTrue(params.StorageOptions.FillFactor >= 0 && params.StorageOptions.FillFactor <= 100)
 
-	err := validateCollectionName(params.Name)
+	err := validateCollectionName(dbc.name, params.Name)
 	if err == nil {
 		err = dbc.db.CreateCollection(ctx, params)
 	}
@@ -164,7 +208,12 @@ func (dbc *databaseContract) CreateCollection(ctx context.Context, params *Creat
 		span.SetStatus(otelcodes.Error, "")
 	}
 
-	checkError(err, ErrorCodeCollectionNameIsInvalid, ErrorCodeCollectionAlreadyExists)
+	checkError(
+		err,
+		ErrorCodeCollectionNameIsInvalid,
+		ErrorCodeCollectionAlreadyExists,
+		ErrorCodeCollectionPartitioningNotSupported,
+	)
 
 	return err
 }
@@ -181,7 +230,7 @@ func (dbc *databaseContract) DropCollection(ctx context.Context, params *DropCol
 	ctx, span := otel.Tracer("").Start(ctx, "DropCollection")
 	defer span.End()
 
-	err := validateCollectionName(params.Name)
+	err := validateCollectionName(dbc.name, params.Name)
 	if err == nil {
 		err = dbc.db.DropCollection(ctx, params)
 	}
@@ -209,10 +258,10 @@ func (dbc *databaseContract) RenameCollection(ctx context.Context, params *Renam
 	ctx, span := otel.Tracer("").Start(ctx, "RenameCollection")
 	defer span.End()
 
-	err := validateCollectionName(params.OldName)
+	err := validateCollectionName(dbc.name, params.OldName)
 
 	if err == nil {
-		err = validateCollectionName(params.NewName)
+		err = validateCollectionName(dbc.name, params.NewName)
 	}
 
 	if err == nil {
@@ -50,6 +50,16 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 	return c.c.DeleteAll(ctx, params)
 }
 
+// Count implements backends.Collection interface.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	return c.c.Count(ctx, params)
+}
+
+// GroupCount implements backends.Collection interface.
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	return c.c.GroupCount(ctx, params)
+}
+
 // Explain implements backends.Collection interface.
 func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
 	return c.c.Explain(ctx, params)
@@ -179,6 +179,16 @@ func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllPa
 	return res, nil
 }
 
+// Count implements backends.Collection interface.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	return c.origC.Count(ctx, params)
+}
+
+// GroupCount implements backends.Collection interface.
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	return c.origC.GroupCount(ctx, params)
+}
+
 // Explain implements backends.Collection interface.
 func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
 	return c.origC.Explain(ctx, params)
@@ -211,8 +221,15 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 
 // oplogCollection returns the OpLog collection if it exist.
 //
+// It returns nil for collections of the OpLog database itself,
+// as changes there (including OpLog records) are not recorded, like in MongoDB.
+//
 // The returned collection is not wrapped with OpLog functionality to prevent recursive calls.
 func (c *collection) oplogCollection(ctx context.Context) backends.Collection {
+	if c.dbName == oplogDatabase {
+		return nil
+	}
+
 	db := must.NotFail(c.origB.Database(oplogDatabase))
 
 	cList, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: oplogCollection})
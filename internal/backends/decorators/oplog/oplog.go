@@ -26,7 +26,7 @@ import (
 type document struct {
 	o  *types.Document
 	ns string
-	op string // i, d, u
+	op string // i, d, u, n
 	o2 *types.Document
 }
 
@@ -52,3 +52,15 @@ func (d *document) marshal(t time.Time) (*types.Document, error) {
 
 	return res, nil
 }
+
+// NewNote returns a new no-op OpLog record with the given data and time.
+//
+// Such records are used as markers by tools like backup utilities.
+func NewNote(data *types.Document, t time.Time) (*types.Document, error) {
+	d := &document{
+		o:  data,
+		op: "n",
+	}
+
+	return d.marshal(t)
+}
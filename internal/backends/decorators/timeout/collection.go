@@ -0,0 +1,180 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeout
+
+import (
+	"context"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+)
+
+// collection implements backends.Collection interface by delegating all methods to the wrapped collection
+// with the statement timeout and hedging read queries.
+type collection struct {
+	origC backends.Collection
+	s     *shared
+}
+
+// newCollection creates a new collection that wraps the given collection.
+func newCollection(origC backends.Collection, s *shared) backends.Collection {
+	return &collection{
+		origC: origC,
+		s:     s,
+	}
+}
+
+// Query implements backends.Collection interface.
+//
+// The statement timeout applies only to the initial query, not to the iteration over results.
+func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*backends.QueryResult, error) {
+	// we can't use context.WithTimeout there as the returned iterator uses the context
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	var timer *time.Timer
+	if c.s.timeout > 0 {
+		timer = time.AfterFunc(c.s.timeout, func() { cancel(ErrTimeout) })
+	}
+
+	res, cancelAttempt, err := hedge(
+		ctx,
+		c.s,
+		func(ctx context.Context) (*backends.QueryResult, error) { return c.origC.Query(ctx, params) },
+		func(res *backends.QueryResult) { res.Iter.Close() },
+	)
+
+	if timer != nil && !timer.Stop() && err == nil {
+		// the timer fired just after the query returned
+		res.Iter.Close()
+		cancelAttempt()
+
+		err = context.Cause(ctx)
+	}
+
+	if err != nil {
+		err = c.s.timeoutError(ctx, err)
+		cancel(nil)
+
+		return nil, err
+	}
+
+	iter := res.Iter
+	res.Iter = iterator.WithClose(iter, func() {
+		iter.Close()
+		cancelAttempt()
+		cancel(nil)
+	})
+
+	return res, nil
+}
+
+// InsertAll implements backends.Collection interface.
+func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.InsertAllResult, error) {
+		return c.origC.InsertAll(ctx, params)
+	})
+}
+
+// UpdateAll implements backends.Collection interface.
+func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.UpdateAllResult, error) {
+		return c.origC.UpdateAll(ctx, params)
+	})
+}
+
+// DeleteAll implements backends.Collection interface.
+func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.DeleteAllResult, error) {
+		return c.origC.DeleteAll(ctx, params)
+	})
+}
+
+// Count implements backends.Collection interface.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.CountResult, error) {
+		res, cancel, err := hedge(
+			ctx,
+			c.s,
+			func(ctx context.Context) (*backends.CountResult, error) { return c.origC.Count(ctx, params) },
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		cancel()
+
+		return res, nil
+	})
+}
+
+// GroupCount implements backends.Collection interface.
+//
+//nolint:lll // for readability
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.GroupCountResult, error) {
+		return c.origC.GroupCount(ctx, params)
+	})
+}
+
+// Explain implements backends.Collection interface.
+func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.ExplainResult, error) {
+		return c.origC.Explain(ctx, params)
+	})
+}
+
+// Stats implements backends.Collection interface.
+func (c *collection) Stats(ctx context.Context, params *backends.CollectionStatsParams) (*backends.CollectionStatsResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.CollectionStatsResult, error) {
+		return c.origC.Stats(ctx, params)
+	})
+}
+
+// Compact implements backends.Collection interface.
+func (c *collection) Compact(ctx context.Context, params *backends.CompactParams) (*backends.CompactResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.CompactResult, error) {
+		return c.origC.Compact(ctx, params)
+	})
+}
+
+// ListIndexes implements backends.Collection interface.
+func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndexesParams) (*backends.ListIndexesResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.ListIndexesResult, error) {
+		return c.origC.ListIndexes(ctx, params)
+	})
+}
+
+// CreateIndexes implements backends.Collection interface.
+//
+//nolint:lll // for readability
+func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.CreateIndexesResult, error) {
+		return c.origC.CreateIndexes(ctx, params)
+	})
+}
+
+// DropIndexes implements backends.Collection interface.
+func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndexesParams) (*backends.DropIndexesResult, error) {
+	return call(ctx, c.s, func(ctx context.Context) (*backends.DropIndexesResult, error) {
+		return c.origC.DropIndexes(ctx, params)
+	})
+}
+
+// check interfaces
+var (
+	_ backends.Collection = (*collection)(nil)
+)
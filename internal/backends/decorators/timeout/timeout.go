@@ -0,0 +1,314 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timeout provides decorators that limit the duration of backend calls
+// and hedge slow read queries.
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "backend"
+)
+
+const (
+	// latencySamples is the number of the most recent latencies used to compute p99.
+	latencySamples = 1000
+
+	// minLatencySamples is the number of samples required before hedging is enabled.
+	minLatencySamples = 100
+
+	// recomputeEvery is the number of samples after which p99 is recomputed.
+	recomputeEvery = 50
+)
+
+// ErrTimeout is returned (possibly wrapped) when the backend call exceeds the statement timeout.
+var ErrTimeout = errors.New("backend statement timeout exceeded")
+
+// Opts represents decorator options.
+type Opts struct {
+	// Timeout limits the duration of all backend calls.
+	// For queries, it limits only the initial query, not the iteration over results.
+	// Zero value disables the timeout.
+	Timeout time.Duration
+
+	// HedgeReads enables hedged read queries.
+	// If a read query does not complete within the p99 latency of recent read queries,
+	// the second attempt is started, and the first completed one is used.
+	HedgeReads bool
+}
+
+// shared contains state shared by backend, databases, and collections.
+type shared struct {
+	timeout time.Duration
+	hedge   bool
+
+	latency *latency
+
+	timeouts   prometheus.Counter
+	hedges     prometheus.Counter
+	hedgesWins prometheus.Counter
+}
+
+// newShared creates a new shared state.
+func newShared(opts *Opts) *shared {
+	return &shared{
+		timeout: opts.Timeout,
+		hedge:   opts.HedgeReads,
+		latency: new(latency),
+		timeouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "timeouts_total",
+			Help:      "Total number of backend calls that exceeded the statement timeout.",
+		}),
+		hedges: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "hedged_reads_total",
+			Help:      "Total number of hedged read attempts.",
+		}),
+		hedgesWins: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "hedged_reads_wins_total",
+			Help:      "Total number of hedged read attempts that completed first.",
+		}),
+	}
+}
+
+// timeoutError returns an error for the call that was canceled due to the timeout.
+func (s *shared) timeoutError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(context.Cause(ctx), ErrTimeout) {
+		return err
+	}
+
+	s.timeouts.Inc()
+
+	return fmt.Errorf("%w (%s)", ErrTimeout, s.timeout)
+}
+
+// call calls f with the statement timeout.
+func call[T any](ctx context.Context, s *shared, f func(context.Context) (T, error)) (T, error) {
+	if s.timeout <= 0 {
+		return f(ctx)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, s.timeout, ErrTimeout)
+	defer cancel()
+
+	res, err := f(ctx)
+
+	return res, s.timeoutError(ctx, err)
+}
+
+// callErr is a variant of [call] for functions that return only an error.
+func callErr(ctx context.Context, s *shared, f func(context.Context) error) error {
+	_, err := call(ctx, s, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	})
+
+	return err
+}
+
+// attempt represents the result of a single read attempt.
+type attempt[T any] struct {
+	res T
+	err error
+	i   int
+}
+
+// hedge calls f and, if hedging is enabled and f does not return within p99 latency,
+// calls f again concurrently.
+//
+// The first successful result is returned together with the function
+// that cancels the context of the successful attempt; it should be called once the result is not needed.
+// Results of other attempts are passed to discard (if not nil), and their contexts are canceled.
+//
+//nolint:lll // for readability
+func hedge[T any](ctx context.Context, s *shared, f func(context.Context) (T, error), discard func(T)) (T, context.CancelFunc, error) {
+	delay, ok := s.latency.p99()
+	if !s.hedge || !ok {
+		start := time.Now()
+
+		ctx, cancel := context.WithCancel(ctx)
+
+		res, err := f(ctx)
+		if err != nil {
+			cancel()
+			return res, nil, err
+		}
+
+		if s.hedge {
+			s.latency.add(time.Since(start))
+		}
+
+		return res, cancel, nil
+	}
+
+	ch := make(chan attempt[T], 2)
+
+	var cancels []context.CancelFunc
+
+	run := func() {
+		i := len(cancels)
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			start := time.Now()
+
+			res, err := f(ctx)
+			if err == nil {
+				s.latency.add(time.Since(start))
+			}
+
+			ch <- attempt[T]{res: res, err: err, i: i}
+		}()
+	}
+
+	run()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	running := 1
+
+	var firstErr error
+
+	for {
+		select {
+		case <-timer.C:
+			s.hedges.Inc()
+
+			run()
+			running++
+
+		case a := <-ch:
+			running--
+
+			if a.err != nil {
+				cancels[a.i]()
+
+				if firstErr == nil {
+					firstErr = a.err
+				}
+
+				if running == 0 {
+					var zero T
+					return zero, nil, firstErr
+				}
+
+				continue
+			}
+
+			if a.i > 0 {
+				s.hedgesWins.Inc()
+			}
+
+			for i, cancel := range cancels {
+				if i != a.i {
+					cancel()
+				}
+			}
+
+			if running > 0 {
+				go func() {
+					if l := <-ch; l.err == nil && discard != nil {
+						discard(l.res)
+					}
+				}()
+			}
+
+			return a.res, cancels[a.i], nil
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (s *shared) Describe(ch chan<- *prometheus.Desc) {
+	s.timeouts.Describe(ch)
+	s.hedges.Describe(ch)
+	s.hedgesWins.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (s *shared) Collect(ch chan<- prometheus.Metric) {
+	s.timeouts.Collect(ch)
+	s.hedges.Collect(ch)
+	s.hedgesWins.Collect(ch)
+}
+
+// latency tracks the latencies of recent read queries.
+type latency struct {
+	rw      sync.RWMutex
+	samples []time.Duration // ring buffer
+	next    int             // next index in samples
+	added   int             // samples added since the last recomputation
+	p       time.Duration   // cached p99
+}
+
+// add records a new latency sample.
+func (l *latency) add(d time.Duration) {
+	l.rw.Lock()
+	defer l.rw.Unlock()
+
+	if len(l.samples) < latencySamples {
+		l.samples = append(l.samples, d)
+	} else {
+		l.samples[l.next] = d
+	}
+
+	l.next = (l.next + 1) % latencySamples
+
+	l.added++
+	if l.added < recomputeEvery && l.p != 0 {
+		return
+	}
+
+	if len(l.samples) < minLatencySamples {
+		return
+	}
+
+	sorted := slices.Clone(l.samples)
+	slices.Sort(sorted)
+
+	l.p = sorted[len(sorted)*99/100]
+	l.added = 0
+}
+
+// p99 returns the p99 latency and true if there are enough samples.
+func (l *latency) p99() (time.Duration, bool) {
+	l.rw.RLock()
+	defer l.rw.RUnlock()
+
+	return l.p, l.p != 0
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*shared)(nil)
+)
@@ -0,0 +1,228 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeout
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// testCollection implements only methods of backends.Collection interface used by tests.
+type testCollection struct {
+	backends.Collection
+	query func(ctx context.Context) (*backends.QueryResult, error)
+	count func(ctx context.Context) (*backends.CountResult, error)
+}
+
+// Query implements backends.Collection interface.
+func (c *testCollection) Query(ctx context.Context, _ *backends.QueryParams) (*backends.QueryResult, error) {
+	return c.query(ctx)
+}
+
+// Count implements backends.Collection interface.
+func (c *testCollection) Count(ctx context.Context, _ *backends.CountParams) (*backends.CountResult, error) {
+	return c.count(ctx)
+}
+
+// DeleteAll implements backends.Collection interface.
+func (c *testCollection) DeleteAll(ctx context.Context, _ *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// primeLatency adds latency samples so hedging is enabled with the given p99.
+func primeLatency(s *shared, p99 time.Duration) {
+	for range latencySamples {
+		s.latency.add(p99)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Call", func(t *testing.T) {
+		t.Parallel()
+
+		s := newShared(&Opts{Timeout: 10 * time.Millisecond})
+		c := newCollection(new(testCollection), s)
+
+		_, err := c.DeleteAll(context.Background(), new(backends.DeleteAllParams))
+		require.ErrorIs(t, err, ErrTimeout)
+		assert.Equal(t, 1.0, testutil.ToFloat64(s.timeouts))
+	})
+
+	t.Run("QueryTimeout", func(t *testing.T) {
+		t.Parallel()
+
+		s := newShared(&Opts{Timeout: 10 * time.Millisecond})
+		c := newCollection(&testCollection{
+			query: func(ctx context.Context) (*backends.QueryResult, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}, s)
+
+		_, err := c.Query(context.Background(), new(backends.QueryParams))
+		require.ErrorIs(t, err, ErrTimeout)
+	})
+
+	t.Run("QueryIterator", func(t *testing.T) {
+		t.Parallel()
+
+		var queryCtx context.Context
+
+		s := newShared(&Opts{Timeout: 10 * time.Millisecond})
+		c := newCollection(&testCollection{
+			query: func(ctx context.Context) (*backends.QueryResult, error) {
+				queryCtx = ctx
+				doc := must.NotFail(types.NewDocument("_id", int32(1)))
+				return &backends.QueryResult{Iter: iterator.Values(iterator.ForSlice([]*types.Document{doc}))}, nil
+			},
+		}, s)
+
+		res, err := c.Query(context.Background(), new(backends.QueryParams))
+		require.NoError(t, err)
+
+		// iteration is not limited by the timeout
+		time.Sleep(20 * time.Millisecond)
+		require.NoError(t, queryCtx.Err())
+
+		docs, err := iterator.ConsumeValues(res.Iter)
+		require.NoError(t, err)
+		assert.Len(t, docs, 1)
+
+		res.Iter.Close()
+		require.Error(t, queryCtx.Err())
+	})
+}
+
+func TestHedge(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SecondWins", func(t *testing.T) {
+		t.Parallel()
+
+		s := newShared(&Opts{HedgeReads: true})
+		primeLatency(s, time.Millisecond)
+
+		var calls atomic.Int32
+		firstCanceled := make(chan struct{})
+
+		c := newCollection(&testCollection{
+			count: func(ctx context.Context) (*backends.CountResult, error) {
+				if calls.Add(1) == 1 {
+					<-ctx.Done()
+					close(firstCanceled)
+
+					return nil, ctx.Err()
+				}
+
+				return &backends.CountResult{Count: 42}, nil
+			},
+		}, s)
+
+		res, err := c.Count(context.Background(), new(backends.CountParams))
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), res.Count)
+
+		<-firstCanceled
+		assert.Equal(t, int32(2), calls.Load())
+		assert.Equal(t, 1.0, testutil.ToFloat64(s.hedges))
+		assert.Equal(t, 1.0, testutil.ToFloat64(s.hedgesWins))
+	})
+
+	t.Run("FirstWins", func(t *testing.T) {
+		t.Parallel()
+
+		s := newShared(&Opts{HedgeReads: true})
+		primeLatency(s, time.Hour)
+
+		c := newCollection(&testCollection{
+			count: func(ctx context.Context) (*backends.CountResult, error) {
+				return &backends.CountResult{Count: 42}, nil
+			},
+		}, s)
+
+		res, err := c.Count(context.Background(), new(backends.CountParams))
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), res.Count)
+		assert.Equal(t, 0.0, testutil.ToFloat64(s.hedges))
+	})
+
+	t.Run("BothFail", func(t *testing.T) {
+		t.Parallel()
+
+		s := newShared(&Opts{HedgeReads: true})
+		primeLatency(s, time.Millisecond)
+
+		errFirst := errors.New("first")
+		var calls atomic.Int32
+
+		c := newCollection(&testCollection{
+			query: func(ctx context.Context) (*backends.QueryResult, error) {
+				if calls.Add(1) == 1 {
+					time.Sleep(10 * time.Millisecond)
+					return nil, errFirst
+				}
+
+				time.Sleep(20 * time.Millisecond)
+
+				return nil, errors.New("second")
+			},
+		}, s)
+
+		_, err := c.Query(context.Background(), new(backends.QueryParams))
+		require.ErrorIs(t, err, errFirst)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestLatency(t *testing.T) {
+	t.Parallel()
+
+	var l latency
+
+	_, ok := l.p99()
+	assert.False(t, ok)
+
+	for i := range minLatencySamples {
+		l.add(time.Duration(i+1) * time.Millisecond)
+	}
+
+	p, ok := l.p99()
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, p)
+
+	// old samples are replaced
+	for range latencySamples {
+		l.add(time.Millisecond)
+	}
+
+	p, ok = l.p99()
+	require.True(t, ok)
+	assert.Equal(t, time.Millisecond, p)
+}
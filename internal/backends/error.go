@@ -37,10 +37,18 @@ const (
 	ErrorCodeCollectionNameIsInvalid
 	ErrorCodeCollectionDoesNotExist
 	ErrorCodeCollectionAlreadyExists
+	ErrorCodeCollectionPartitioningNotSupported
 
 	ErrorCodeInsertDuplicateID
+
+	ErrorCodeGroupCountNotSupported
 )
 
+// ErrAuthenticationFailed is returned (possibly wrapped) when the backend rejects credentials of the connection.
+//
+// It is an opaque error, not *Error, because it could be returned by any Backend, Database or Collection method.
+var ErrAuthenticationFailed = errors.New("backend authentication failed")
+
 // Error represents a backend error returned by all Backend, Database and Collection methods.
 type Error struct {
 	// This internal error can't be accessed by the caller; it exists only for debugging.
@@ -13,12 +13,14 @@ func _() {
 	_ = x[ErrorCodeCollectionNameIsInvalid-3]
 	_ = x[ErrorCodeCollectionDoesNotExist-4]
 	_ = x[ErrorCodeCollectionAlreadyExists-5]
-	_ = x[ErrorCodeInsertDuplicateID-6]
+	_ = x[ErrorCodeCollectionPartitioningNotSupported-6]
+	_ = x[ErrorCodeInsertDuplicateID-7]
+	_ = x[ErrorCodeGroupCountNotSupported-8]
 }
 
-const _ErrorCode_name = "ErrorCodeDatabaseNameIsInvalidErrorCodeDatabaseDoesNotExistErrorCodeCollectionNameIsInvalidErrorCodeCollectionDoesNotExistErrorCodeCollectionAlreadyExistsErrorCodeInsertDuplicateID"
+const _ErrorCode_name = "ErrorCodeDatabaseNameIsInvalidErrorCodeDatabaseDoesNotExistErrorCodeCollectionNameIsInvalidErrorCodeCollectionDoesNotExistErrorCodeCollectionAlreadyExistsErrorCodeCollectionPartitioningNotSupportedErrorCodeInsertDuplicateIDErrorCodeGroupCountNotSupported"
 
-var _ErrorCode_index = [...]uint8{0, 30, 59, 91, 122, 154, 180}
+var _ErrorCode_index = [...]uint8{0, 30, 59, 91, 122, 154, 197, 223, 254}
 
 func (i ErrorCode) String() string {
 	i -= 1
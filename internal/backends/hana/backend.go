@@ -19,15 +19,20 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"regexp"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/sqlguard"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
 
+// databaseNameRe validates database names; they are not quoted in all queries.
+var databaseNameRe = regexp.MustCompile("^[a-zA-Z0-9_-]{1,63}$")
+
 // backend implements backends.Backend interface.
 type backend struct {
 	hdb *fsql.DB
@@ -51,7 +56,7 @@ func NewBackend(params *NewBackendParams) (backends.Backend, error) {
 		return nil, err
 	}
 
-	hdb := fsql.WrapDB(db, "hana", params.L)
+	hdb := fsql.WrapDB(db, "hana", sqlguard.HANA, params.L)
 	hdb.BatchSize = params.BatchSize
 
 	return backends.BackendContract(&backend{
@@ -110,6 +115,13 @@ func (b *backend) Status(ctx context.Context, params *backends.StatusParams) (*b
 
 // Database implements backends.Backend interface.
 func (b *backend) Database(name string) (backends.Database, error) {
+	if !databaseNameRe.MatchString(name) {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseNameIsInvalid,
+			fmt.Errorf("database name %q is not supported by SAP HANA backend", name),
+		)
+	}
+
 	return newDatabase(b.hdb, name), nil
 }
 
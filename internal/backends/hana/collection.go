@@ -17,6 +17,7 @@ package hana
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
 
 	"github.com/SAP/go-hdb/driver"
@@ -78,7 +79,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	if !db {
 		return &backends.QueryResult{
-			Iter: newQueryIterator(ctx, nil),
+			Iter: newQueryIterator(ctx, nil, nil),
 		}, nil
 	}
 
@@ -89,25 +90,71 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	if !col {
 		return &backends.QueryResult{
-			Iter: newQueryIterator(ctx, nil),
+			Iter: newQueryIterator(ctx, nil, nil),
 		}, nil
 	}
 
-	sql, err := c.generateQuery(params.Filter, params.Sort)
+	q, err := c.generateQuery(params.Filter, params.Sort)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	rows, err := c.hdb.QueryContext(ctx, sql)
+	tx, err := c.hdb.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	rows, err := tx.QueryContext(ctx, q)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, lazyerrors.Error(err)
+	}
+
 	return &backends.QueryResult{
-		Iter: newQueryIterator(ctx, rows),
+		Iter: newQueryIterator(ctx, tx, rows),
 	}, nil
 }
 
+// Count implements backends.Collection interface.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	var res backends.CountResult
+
+	db, err := databaseExists(ctx, c.hdb, c.database)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !db {
+		return &res, nil
+	}
+
+	col, err := collectionExists(ctx, c.hdb, c.database, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !col {
+		return &res, nil
+	}
+
+	queryCountDocuments := "SELECT count(*) FROM %q.%q"
+	queryCountDocuments = fmt.Sprintf(queryCountDocuments, c.database, c.name)
+
+	if res.Count, err = querySingleInt(queryCountDocuments, ctx, c.hdb); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
+// GroupCount implements backends.Collection interface.
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	return nil, backends.NewError(
+		backends.ErrorCodeGroupCountNotSupported,
+		lazyerrors.New("grouping is not supported"),
+	)
+}
+
 // InsertAll implements backends.Collection interface.
 func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
 	err := createDatabaseIfNotExists(ctx, c.hdb, c.database)
@@ -320,6 +367,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 
 	return &backends.ExplainResult{
 		QueryPlanner: &explainDoc,
+		Query:        querySQL,
 	}, nil
 }
 
@@ -36,7 +36,7 @@ func newDatabase(hdb *fsql.DB, name string) backends.Database {
 	return backends.DatabaseContract(&database{
 		hdb:  hdb,
 		name: name,
-	})
+	}, name)
 }
 
 // Collection implements backends.Database interface.
@@ -105,6 +105,13 @@ func (db *database) ListCollections(
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	if params.Partitioned() {
+		return backends.NewError(
+			backends.ErrorCodeCollectionPartitioningNotSupported,
+			lazyerrors.New("partitioned collections are not supported"),
+		)
+	}
+
 	exists, err := collectionExists(ctx, db.hdb, db.name, params.Name)
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -42,7 +42,7 @@ func makeFilter(table, key, op string, value any) string {
 
 	switch v := value.(type) {
 	case *types.Document, *types.Array, types.Binary,
-		types.NullType, types.Regex, types.Timestamp:
+		types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 	// type not supported for pushdown
 	case float64:
 		// If value is not safe double, fetch all numbers out of safe range.
@@ -137,7 +137,7 @@ func prepareWhereClause(table string, filter *types.Document) (string, error) {
 				}
 			}
 
-		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp:
+		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 			// type not supported for pushdown
 
 		case float64, string, types.ObjectID, bool, time.Time, int32, int64:
@@ -158,12 +158,15 @@ func prepareWhereClause(table string, filter *types.Document) (string, error) {
 	return whereClause, nil
 }
 
+// prepareOrderByClause returns ORDER BY clause for given sort document.
+//
+// Sort by fields is not supported; an empty clause is returned for it.
 func prepareOrderByClause(sort *types.Document) (string, error) {
-	if sort.Len() != 1 {
+	v, _ := sort.Get("$natural")
+	if v == nil {
 		return "", nil
 	}
 
-	v := must.NotFail(sort.Get("$natural"))
 	var order string
 
 	switch v.(int64) {
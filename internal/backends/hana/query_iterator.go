@@ -30,14 +30,20 @@ import (
 
 // queryIterator implements iterator.Interface to fetch documents from the database.
 type queryIterator struct {
+	tx    *fsql.Tx
 	rows  *fsql.Rows
 	token *resource.Token
 	ctx   context.Context
 	m     sync.Mutex
 }
 
-func newQueryIterator(ctx context.Context, rows *fsql.Rows) types.DocumentsIterator {
+// newQueryIterator returns a new queryIterator for the given rows
+// of the query executed in the given read-only transaction.
+//
+// Iterator's Close method closes rows and rolls back the transaction.
+func newQueryIterator(ctx context.Context, tx *fsql.Tx, rows *fsql.Rows) types.DocumentsIterator {
 	iter := &queryIterator{
+		tx:    tx,
 		rows:  rows,
 		token: resource.NewToken(),
 		ctx:   ctx,
@@ -108,5 +114,10 @@ func (iter *queryIterator) close() {
 		iter.rows = nil
 	}
 
+	if iter.tx != nil {
+		_ = iter.tx.Rollback()
+		iter.tx = nil
+	}
+
 	resource.Untrack(iter, iter.token)
 }
@@ -24,6 +24,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/hana"
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
@@ -87,6 +88,23 @@ func testBackends(t *testing.T) map[string]*testBackend {
 		}
 	}
 
+	{
+		sp, err := state.NewProvider("")
+		require.NoError(t, err)
+
+		b, err := memory.NewBackend(&memory.NewBackendParams{
+			L: logging.WithName(l, "memory"),
+			P: sp,
+		})
+		require.NoError(t, err)
+		t.Cleanup(b.Close)
+
+		res["memory"] = &testBackend{
+			Backend: b,
+			sp:      sp,
+		}
+	}
+
 	if hanaURL := os.Getenv("FERRETDB_HANA_URL"); hanaURL != "" {
 		sp, err := state.NewProvider("")
 		require.NoError(t, err)
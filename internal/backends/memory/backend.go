@@ -0,0 +1,214 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/exp/maps"
+
+	"github.com/FerretDB/FerretDB/build/version"
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "memory"
+)
+
+// backend implements backends.Backend interface.
+type backend struct {
+	l  *slog.Logger
+	sp *state.Provider
+
+	// rw protects dbs and all collections' data.
+	rw  sync.RWMutex
+	dbs map[string]map[string]*collectionData // database name -> collection name -> collection
+}
+
+// NewBackendParams represents the parameters of NewBackend function.
+//
+//nolint:vet // for readability
+type NewBackendParams struct {
+	L *slog.Logger
+	P *state.Provider
+	_ struct{} // prevent unkeyed literals
+}
+
+// NewBackend creates a new Backend.
+func NewBackend(params *NewBackendParams) (backends.Backend, error) {
+	return backends.BackendContract(&backend{
+		l:   params.L,
+		sp:  params.P,
+		dbs: map[string]map[string]*collectionData{},
+	}), nil
+}
+
+// Close implements backends.Backend interface.
+func (b *backend) Close() {
+	b.rw.Lock()
+	defer b.rw.Unlock()
+
+	b.dbs = map[string]map[string]*collectionData{}
+}
+
+// Status implements backends.Backend interface.
+func (b *backend) Status(ctx context.Context, params *backends.StatusParams) (*backends.StatusResult, error) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	var res backends.StatusResult
+
+	for _, colls := range b.dbs {
+		res.CountCollections += int64(len(colls))
+
+		for _, c := range colls {
+			if c.capped() {
+				res.CountCappedCollections++
+			}
+		}
+	}
+
+	return &res, nil
+}
+
+// Database implements backends.Backend interface.
+func (b *backend) Database(name string) (backends.Database, error) {
+	return newDatabase(b, name), nil
+}
+
+// ListDatabases implements backends.Backend interface.
+//
+//nolint:lll // for readability
+func (b *backend) ListDatabases(ctx context.Context, params *backends.ListDatabasesParams) (*backends.ListDatabasesResult, error) {
+	b.rw.RLock()
+	list := maps.Keys(b.dbs)
+	b.rw.RUnlock()
+
+	sort.Strings(list)
+
+	if params != nil && params.Name != "" {
+		list = slices.DeleteFunc(list, func(name string) bool { return name != params.Name })
+	}
+
+	res := &backends.ListDatabasesResult{
+		Databases: make([]backends.DatabaseInfo, len(list)),
+	}
+
+	for i, dbName := range list {
+		res.Databases[i] = backends.DatabaseInfo{
+			Name: dbName,
+		}
+	}
+
+	return res, nil
+}
+
+// DropDatabase implements backends.Backend interface.
+func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabaseParams) error {
+	b.rw.Lock()
+	defer b.rw.Unlock()
+
+	if _, ok := b.dbs[params.Name]; !ok {
+		return backends.NewError(backends.ErrorCodeDatabaseDoesNotExist, nil)
+	}
+
+	delete(b.dbs, params.Name)
+
+	return nil
+}
+
+// collectionGetOrCreate returns existing or newly created collection.
+// Database is created automatically if needed.
+//
+// Returned boolean value indicates whether the collection was created.
+//
+// It does not hold the lock.
+func (b *backend) collectionGetOrCreate(dbName, name string, cappedSize, cappedDocuments int64) (*collectionData, bool) {
+	colls := b.dbs[dbName]
+	if colls == nil {
+		colls = map[string]*collectionData{}
+		b.dbs[dbName] = colls
+
+		// do it only once because version can't change
+		if b.sp.Get().BackendVersion == "" {
+			err := b.sp.Update(func(s *state.State) {
+				s.BackendName = "memory"
+				s.BackendVersion = version.Get().Version
+			})
+			if err != nil {
+				b.l.Error("memory.backend: failed to update state", logging.Error(err))
+			}
+		}
+	}
+
+	if c := colls[name]; c != nil {
+		return c, false
+	}
+
+	c := newCollectionData(name, cappedSize, cappedDocuments)
+	colls[name] = c
+
+	return c, true
+}
+
+// collectionGet returns existing collection or nil.
+//
+// It does not hold the lock.
+func (b *backend) collectionGet(dbName, name string) *collectionData {
+	return b.dbs[dbName][name]
+}
+
+// Describe implements prometheus.Collector.
+func (b *backend) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sizeDesc
+}
+
+// Collect implements prometheus.Collector.
+func (b *backend) Collect(ch chan<- prometheus.Metric) {
+	b.rw.RLock()
+	defer b.rw.RUnlock()
+
+	for dbName, colls := range b.dbs {
+		for _, c := range colls {
+			ch <- prometheus.MustNewConstMetric(
+				sizeDesc,
+				prometheus.GaugeValue,
+				float64(c.sizeCollection()+c.sizeIndexes()),
+				dbName, c.name,
+			)
+		}
+	}
+}
+
+// sizeDesc describes the estimated size of collection's documents and indexes.
+var sizeDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, subsystem, "size_bytes"),
+	"Estimated size of collection's documents and indexes in bytes.",
+	[]string{"db", "collection"}, nil,
+)
+
+// check interfaces
+var (
+	_ backends.Backend = (*backend)(nil)
+)
@@ -0,0 +1,401 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// collection implements backends.Collection interface.
+type collection struct {
+	b      *backend
+	dbName string
+	name   string
+}
+
+// newCollection creates a new Collection.
+func newCollection(b *backend, dbName, name string) backends.Collection {
+	return backends.CollectionContract(&collection{
+		b:      b,
+		dbName: dbName,
+		name:   name,
+	})
+}
+
+// Query implements backends.Collection interface.
+func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*backends.QueryResult, error) {
+	c.b.rw.RLock()
+
+	var docs []*document
+
+	if coll := c.b.collectionGet(c.dbName, c.name); coll != nil {
+		docs = c.find(coll, params.Filter)
+	}
+
+	c.b.rw.RUnlock()
+
+	if v, _ := params.Sort.Get("$natural"); v == int64(-1) {
+		slices.Reverse(docs)
+	}
+
+	if params.Limit != 0 && int64(len(docs)) > params.Limit {
+		docs = docs[:params.Limit]
+	}
+
+	return &backends.QueryResult{
+		Iter: newQueryIterator(ctx, docs, params.OnlyRecordIDs),
+	}, nil
+}
+
+// Count implements backends.Collection interface.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	c.b.rw.RLock()
+	defer c.b.rw.RUnlock()
+
+	var res backends.CountResult
+
+	if coll := c.b.collectionGet(c.dbName, c.name); coll != nil {
+		res.Count = int64(len(coll.docs))
+	}
+
+	return &res, nil
+}
+
+// GroupCount implements backends.Collection interface.
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	if _, ok := idFilter(params.Filter); !ok && params.Filter.Len() != 0 {
+		return nil, backends.NewError(
+			backends.ErrorCodeGroupCountNotSupported,
+			lazyerrors.Errorf("filter %v can't be applied exactly", types.FormatAnyValue(params.Filter)),
+		)
+	}
+
+	c.b.rw.RLock()
+	defer c.b.rw.RUnlock()
+
+	var res backends.GroupCountResult
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return &res, nil
+	}
+
+	for _, d := range c.find(coll, params.Filter) {
+		var id any = types.Null
+
+		if params.GroupBy != "" {
+			if v, _ := d.doc.Get(params.GroupBy); v != nil {
+				id = v
+			}
+		}
+
+		i := slices.IndexFunc(res.Groups, func(g backends.GroupCount) bool {
+			return types.CompareForAggregation(g.ID, id) == types.Equal
+		})
+		if i < 0 {
+			// stored documents are frozen
+			switch v := id.(type) {
+			case *types.Document:
+				id = v.DeepCopy()
+			case *types.Array:
+				id = v.DeepCopy()
+			}
+
+			res.Groups = append(res.Groups, backends.GroupCount{ID: id})
+			i = len(res.Groups) - 1
+		}
+
+		res.Groups[i].Count++
+	}
+
+	return &res, nil
+}
+
+// find returns stored documents matching the _id filter, if any, in insertion order.
+//
+// The caller should hold the lock.
+func (c *collection) find(coll *collectionData, filter *types.Document) []*document {
+	id, ok := idFilter(filter)
+	if !ok {
+		return coll.documents()
+	}
+
+	seq, ok := coll.lookupID(id)
+	if !ok {
+		return nil
+	}
+
+	return []*document{coll.docs[seq]}
+}
+
+// idFilter returns the _id value if the filter could be applied using the default index.
+//
+// That logic should be in sync with other backends.
+// TODO https://github.com/FerretDB/FerretDB/issues/3235
+func idFilter(filter *types.Document) (any, bool) {
+	if filter.Len() != 1 {
+		return nil, false
+	}
+
+	v, _ := filter.Get("_id")
+	switch v.(type) {
+	case string, types.ObjectID:
+		return v, true
+	default:
+		return nil, false
+	}
+}
+
+// newQueryIterator returns an iterator over deep copies of the given stored documents.
+func newQueryIterator(ctx context.Context, docs []*document, onlyRecordIDs bool) types.DocumentsIterator {
+	var n int
+
+	return iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		if err := context.Cause(ctx); err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		if n >= len(docs) {
+			return unused, nil, iterator.ErrIteratorDone
+		}
+
+		d := docs[n].doc
+		n++
+
+		if onlyRecordIDs {
+			doc := must.NotFail(types.NewDocument())
+			doc.SetRecordID(d.RecordID())
+
+			return unused, doc, nil
+		}
+
+		return unused, d.DeepCopy(), nil
+	})
+}
+
+// InsertAll implements backends.Collection interface.
+func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	c.b.rw.Lock()
+	defer c.b.rw.Unlock()
+
+	coll, _ := c.b.collectionGetOrCreate(c.dbName, c.name, 0, 0)
+
+	if err := coll.insert(params.Docs); err != nil {
+		return nil, err
+	}
+
+	return new(backends.InsertAllResult), nil
+}
+
+// UpdateAll implements backends.Collection interface.
+func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	c.b.rw.Lock()
+	defer c.b.rw.Unlock()
+
+	var res backends.UpdateAllResult
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return &res, nil
+	}
+
+	updated, err := coll.update(params.Docs)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res.Updated = updated
+
+	return &res, nil
+}
+
+// DeleteAll implements backends.Collection interface.
+func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	c.b.rw.Lock()
+	defer c.b.rw.Unlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return &backends.DeleteAllResult{Deleted: 0}, nil
+	}
+
+	var deleted int32
+	if params.RecordIDs == nil {
+		deleted = coll.deleteIDs(params.IDs)
+	} else {
+		deleted = coll.deleteRecordIDs(params.RecordIDs)
+	}
+
+	return &backends.DeleteAllResult{
+		Deleted: deleted,
+	}, nil
+}
+
+// Explain implements backends.Collection interface.
+func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
+	natural, _ := params.Sort.Get("$natural")
+
+	res := &backends.ExplainResult{
+		SortPushdown:  natural != nil,
+		LimitPushdown: params.Limit != 0,
+	}
+
+	plan := "COLLSCAN"
+	if _, ok := idFilter(params.Filter); ok {
+		plan = "IDHACK"
+		res.FilterPushdown = true
+	}
+
+	res.QueryPlanner = must.NotFail(types.NewDocument("Plan", plan))
+
+	return res, nil
+}
+
+// Stats implements backends.Collection interface.
+func (c *collection) Stats(ctx context.Context, params *backends.CollectionStatsParams) (*backends.CollectionStatsResult, error) {
+	c.b.rw.RLock()
+	defer c.b.rw.RUnlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	indexSizes := make([]backends.IndexSize, 0, len(coll.indexes))
+	for name, idx := range coll.indexes {
+		indexSizes = append(indexSizes, backends.IndexSize{
+			Name: name,
+			Size: pages(idx.size),
+		})
+	}
+
+	sort.Slice(indexSizes, func(i, j int) bool { return indexSizes[i].Name < indexSizes[j].Name })
+
+	sizeCollection, sizeIndexes := coll.sizeCollection(), coll.sizeIndexes()
+
+	return &backends.CollectionStatsResult{
+		CountDocuments: int64(len(coll.docs)),
+		SizeTotal:      sizeCollection + sizeIndexes,
+		SizeIndexes:    sizeIndexes,
+		SizeCollection: sizeCollection,
+		IndexSizes:     indexSizes,
+	}, nil
+}
+
+// Compact implements backends.Collection interface.
+//
+// There is nothing to compact; it only checks that the collection exists.
+func (c *collection) Compact(ctx context.Context, params *backends.CompactParams) (*backends.CompactResult, error) {
+	c.b.rw.RLock()
+	defer c.b.rw.RUnlock()
+
+	if _, ok := c.b.dbs[c.dbName]; !ok {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	if c.b.collectionGet(c.dbName, c.name) == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	return new(backends.CompactResult), nil
+}
+
+// ListIndexes implements backends.Collection interface.
+func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndexesParams) (*backends.ListIndexesResult, error) {
+	c.b.rw.RLock()
+	defer c.b.rw.RUnlock()
+
+	coll := c.b.collectionGet(c.dbName, c.name)
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	res := backends.ListIndexesResult{
+		Indexes: make([]backends.IndexInfo, 0, len(coll.indexes)),
+	}
+
+	for _, idx := range coll.indexes {
+		info := backends.IndexInfo{
+			Name:   idx.info.Name,
+			Key:    slices.Clone(idx.info.Key),
+			Unique: idx.info.Unique,
+		}
+
+		if idx.info.Options != nil {
+			info.Options = idx.info.Options.DeepCopy()
+		}
+
+		res.Indexes = append(res.Indexes, info)
+	}
+
+	sort.Slice(res.Indexes, func(i, j int) bool {
+		return res.Indexes[i].Name < res.Indexes[j].Name
+	})
+
+	return &res, nil
+}
+
+// CreateIndexes implements backends.Collection interface.
+func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
+	c.b.rw.Lock()
+	defer c.b.rw.Unlock()
+
+	coll, _ := c.b.collectionGetOrCreate(c.dbName, c.name, 0, 0)
+
+	if err := coll.createIndexes(params.Indexes); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return new(backends.CreateIndexesResult), nil
+}
+
+// DropIndexes implements backends.Collection interface.
+func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndexesParams) (*backends.DropIndexesResult, error) {
+	c.b.rw.Lock()
+	defer c.b.rw.Unlock()
+
+	if coll := c.b.collectionGet(c.dbName, c.name); coll != nil {
+		coll.dropIndexes(params.Indexes)
+	}
+
+	return new(backends.DropIndexesResult), nil
+}
+
+// check interfaces
+var (
+	_ backends.Collection = (*collection)(nil)
+)
@@ -0,0 +1,116 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestCollectionUniqueIndexes(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := NewBackend(&NewBackendParams{L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	db, err := b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	coll, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", "a")),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", "b")),
+	}})
+	require.NoError(t, err)
+
+	_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: []backends.IndexInfo{{
+		Name:   "v_1",
+		Key:    []backends.IndexKeyPair{{Field: "v"}},
+		Unique: true,
+	}}})
+	require.NoError(t, err)
+
+	t.Run("InsertAll", func(t *testing.T) {
+		_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", int32(3), "v", "c")),
+			must.NotFail(types.NewDocument("_id", int32(4), "v", "a")),
+		}})
+		assert.True(t, backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID), "err = %v", err)
+	})
+
+	t.Run("UpdateAll", func(t *testing.T) {
+		_, err = coll.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", int32(1), "v", "c")),
+			must.NotFail(types.NewDocument("_id", int32(2), "v", "c")),
+		}})
+		assert.Error(t, err)
+	})
+
+	t.Run("CreateIndexes", func(t *testing.T) {
+		_, err = coll.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", int32(5), "v", "b", "w", int32(1))),
+		}})
+		require.Error(t, err)
+
+		_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: []backends.IndexInfo{{
+			Name: "w_1",
+			Key:  []backends.IndexKeyPair{{Field: "w"}},
+		}, {
+			Name:   "_id_v",
+			Key:    []backends.IndexKeyPair{{Field: "v"}, {Field: "_id"}},
+			Unique: true,
+		}, {
+			Name:   "w_-1",
+			Key:    []backends.IndexKeyPair{{Field: "w", Descending: true}},
+			Unique: true,
+		}}})
+		assert.Error(t, err)
+
+		res, err := coll.ListIndexes(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, res.Indexes, 2)
+		assert.Equal(t, backends.DefaultIndexName, res.Indexes[0].Name)
+		assert.Equal(t, "v_1", res.Indexes[1].Name)
+	})
+
+	// all failed operations should have been rolled back
+	res, err := coll.Query(ctx, nil)
+	require.NoError(t, err)
+
+	docs, err := iterator.ConsumeValues[struct{}, *types.Document](res.Iter)
+	require.NoError(t, err)
+	testutil.AssertEqualSlices(t, []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", "a")),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", "b")),
+	}, docs)
+}
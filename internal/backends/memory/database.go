@@ -0,0 +1,159 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// database implements backends.Database interface.
+type database struct {
+	b    *backend
+	name string
+}
+
+// newDatabase creates a new Database.
+func newDatabase(b *backend, name string) backends.Database {
+	return backends.DatabaseContract(&database{
+		b:    b,
+		name: name,
+	}, name)
+}
+
+// Collection implements backends.Database interface.
+func (db *database) Collection(name string) (backends.Collection, error) {
+	return newCollection(db.b, db.name, name), nil
+}
+
+// ListCollections implements backends.Database interface.
+//
+//nolint:lll // for readability
+func (db *database) ListCollections(ctx context.Context, params *backends.ListCollectionsParams) (*backends.ListCollectionsResult, error) {
+	db.b.rw.RLock()
+	defer db.b.rw.RUnlock()
+
+	var res []backends.CollectionInfo
+
+	for name, c := range db.b.dbs[db.name] {
+		if params != nil && params.Name != "" && params.Name != name {
+			continue
+		}
+
+		res = append(res, c.info())
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return &backends.ListCollectionsResult{
+		Collections: res,
+	}, nil
+}
+
+// CreateCollection implements backends.Database interface.
+func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	if params.Partitioned() {
+		return backends.NewError(
+			backends.ErrorCodeCollectionPartitioningNotSupported,
+			lazyerrors.New("partitioned collections are not supported"),
+		)
+	}
+
+	db.b.rw.Lock()
+	defer db.b.rw.Unlock()
+
+	c, created := db.b.collectionGetOrCreate(db.name, params.Name, params.CappedSize, params.CappedDocuments)
+	if !created {
+		return backends.NewError(backends.ErrorCodeCollectionAlreadyExists, nil)
+	}
+
+	c.versioned = params.Versioned
+
+	return nil
+}
+
+// DropCollection implements backends.Database interface.
+func (db *database) DropCollection(ctx context.Context, params *backends.DropCollectionParams) error {
+	db.b.rw.Lock()
+	defer db.b.rw.Unlock()
+
+	if db.b.collectionGet(db.name, params.Name) == nil {
+		return backends.NewError(backends.ErrorCodeCollectionDoesNotExist, nil)
+	}
+
+	delete(db.b.dbs[db.name], params.Name)
+
+	return nil
+}
+
+// RenameCollection implements backends.Database interface.
+func (db *database) RenameCollection(ctx context.Context, params *backends.RenameCollectionParams) error {
+	db.b.rw.Lock()
+	defer db.b.rw.Unlock()
+
+	c := db.b.collectionGet(db.name, params.OldName)
+	if c == nil {
+		return backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("old database %q or collection %q does not exist", db.name, params.OldName),
+		)
+	}
+
+	if db.b.collectionGet(db.name, params.NewName) != nil {
+		return backends.NewError(
+			backends.ErrorCodeCollectionAlreadyExists,
+			lazyerrors.Errorf("new database %q and collection %q already exists", db.name, params.NewName),
+		)
+	}
+
+	colls := db.b.dbs[db.name]
+	delete(colls, params.OldName)
+
+	c.name = params.NewName
+	colls[params.NewName] = c
+
+	return nil
+}
+
+// Stats implements backends.Database interface.
+func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
+	db.b.rw.RLock()
+	defer db.b.rw.RUnlock()
+
+	colls, ok := db.b.dbs[db.name]
+	if !ok {
+		return nil, backends.NewError(backends.ErrorCodeDatabaseDoesNotExist, lazyerrors.Errorf("no database %s", db.name))
+	}
+
+	var res backends.DatabaseStatsResult
+
+	for _, c := range colls {
+		res.CountDocuments += int64(len(c.docs))
+		res.SizeCollections += c.sizeCollection()
+		res.SizeIndexes += c.sizeIndexes()
+	}
+
+	res.SizeTotal = res.SizeCollections + res.SizeIndexes
+
+	return &res, nil
+}
+
+// check interfaces
+var (
+	_ backends.Database = (*database)(nil)
+)
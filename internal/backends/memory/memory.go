@@ -0,0 +1,476 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory provides in-memory backend.
+//
+// All data is stored in Go maps and is lost when the backend is closed.
+// The backend is intended for tests, demos, and other ephemeral workloads
+// that should not depend on any external database.
+//
+// # Design principles
+//
+//  1. A single lock protects all databases and collections, like in the SQLite backend.
+//     Every operation is performed under that lock, so it is atomic.
+//  2. Stored documents are frozen and never modified; they are replaced instead.
+//     Queries return deep copies.
+//  3. Indexes map encoded key values to documents. They enforce uniqueness
+//     and are used for filtering by `_id`.
+//  4. Sizes returned by stats are estimations based on BSON encoding,
+//     rounded up to whole pages like in on-disk storage.
+package memory
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+const (
+	// indexEntrySize is an estimated size of a single index entry in addition to its key.
+	indexEntrySize = 16
+
+	// pageSize is a unit of reported sizes.
+	pageSize = 4096
+)
+
+// document represents a stored document.
+type document struct {
+	doc  *types.Document // frozen
+	size int64
+}
+
+// index represents a collection index.
+type index struct {
+	info backends.IndexInfo
+	keys map[string][]int64 // encoded key -> document sequence numbers
+	size int64
+}
+
+// collectionData represents a collection with its documents and indexes.
+//
+// Its methods do not hold any locks; the caller should.
+type collectionData struct {
+	name            string
+	uuid            string
+	cappedSize      int64
+	cappedDocuments int64
+	versioned       bool
+
+	seq     int64               // last document sequence number
+	order   []int64             // sequence numbers in insertion order
+	docs    map[int64]*document // sequence number -> document
+	indexes map[string]*index   // index name -> index
+	size    int64               // total size of documents
+}
+
+// newCollectionData creates a new empty collection with the default index.
+func newCollectionData(name string, cappedSize, cappedDocuments int64) *collectionData {
+	c := &collectionData{
+		name:            name,
+		uuid:            uuid.NewString(),
+		cappedSize:      cappedSize,
+		cappedDocuments: cappedDocuments,
+		docs:            map[int64]*document{},
+		indexes:         map[string]*index{},
+	}
+
+	c.indexes[backends.DefaultIndexName] = &index{
+		info: backends.IndexInfo{
+			Name:   backends.DefaultIndexName,
+			Key:    []backends.IndexKeyPair{{Field: "_id"}},
+			Unique: true,
+		},
+		keys: map[string][]int64{},
+	}
+
+	return c
+}
+
+// capped returns true if collection is capped.
+func (c *collectionData) capped() bool {
+	return c.cappedSize > 0 // TODO https://github.com/FerretDB/FerretDB/issues/3631
+}
+
+// info returns collection information.
+func (c *collectionData) info() backends.CollectionInfo {
+	return backends.CollectionInfo{
+		Name:            c.name,
+		UUID:            c.uuid,
+		CappedSize:      c.cappedSize,
+		CappedDocuments: c.cappedDocuments,
+		Versioned:       c.versioned,
+	}
+}
+
+// sizeCollection returns the size of documents in whole pages.
+func (c *collectionData) sizeCollection() int64 {
+	return pages(c.size)
+}
+
+// sizeIndexes returns the total size of all indexes in whole pages.
+func (c *collectionData) sizeIndexes() int64 {
+	var res int64
+	for _, idx := range c.indexes {
+		res += pages(idx.size)
+	}
+
+	return res
+}
+
+// documents returns stored documents in insertion order.
+//
+// Returned documents are frozen and should not be modified.
+func (c *collectionData) documents() []*document {
+	res := make([]*document, len(c.order))
+	for i, seq := range c.order {
+		res[i] = c.docs[seq]
+	}
+
+	return res
+}
+
+// lookupID returns the sequence number of the document with the given _id, if any.
+func (c *collectionData) lookupID(id any) (int64, bool) {
+	seqs := c.indexes[backends.DefaultIndexName].keys[indexKey([]any{id})]
+	if len(seqs) == 0 {
+		return 0, false
+	}
+
+	return seqs[0], true
+}
+
+// insert adds documents to the collection.
+//
+// If any document violates a unique index, nothing is inserted.
+func (c *collectionData) insert(docs []*types.Document) error {
+	added := make(map[string]map[string]struct{}, len(c.indexes)) // index name -> keys added by this call
+
+	for _, doc := range docs {
+		for name, idx := range c.indexes {
+			if !idx.info.Unique {
+				continue
+			}
+
+			key := idx.keyFor(doc)
+
+			if _, dup := added[name][key]; dup || len(idx.keys[key]) > 0 {
+				return backends.NewError(
+					backends.ErrorCodeInsertDuplicateID,
+					lazyerrors.Errorf("duplicate key %s for index %q", key, name),
+				)
+			}
+
+			if added[name] == nil {
+				added[name] = map[string]struct{}{}
+			}
+
+			added[name][key] = struct{}{}
+		}
+	}
+
+	for _, doc := range docs {
+		stored := doc.DeepCopy()
+		if !c.capped() {
+			stored.SetRecordID(0)
+		}
+
+		stored.Freeze()
+
+		d, err := newDocument(stored)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		c.seq++
+		c.order = append(c.order, c.seq)
+		c.put(c.seq, d)
+	}
+
+	return nil
+}
+
+// update replaces documents with the same _id values.
+// Documents that do not exist are skipped.
+//
+// If any document violates a unique index, nothing is updated.
+func (c *collectionData) update(docs []*types.Document) (int32, error) {
+	type change struct {
+		seq int64
+		old *document
+	}
+
+	var changes []change
+
+	rollback := func() {
+		for i := len(changes) - 1; i >= 0; i-- {
+			c.remove(changes[i].seq)
+			c.put(changes[i].seq, changes[i].old)
+		}
+	}
+
+	for _, doc := range docs {
+		seq, ok := c.lookupID(must.NotFail(doc.Get("_id")))
+		if !ok {
+			continue
+		}
+
+		old := c.docs[seq]
+
+		if c.versioned {
+			// stored document should not be changed since it was read by the handler
+			v, _ := old.doc.Get(backends.VersionField)
+			if n, _ := v.(int64); n != backends.PreviousVersion(doc) {
+				continue
+			}
+		}
+
+		stored := doc.DeepCopy()
+		stored.SetRecordID(old.doc.RecordID())
+		stored.Freeze()
+
+		d, err := newDocument(stored)
+		if err != nil {
+			rollback()
+			return 0, lazyerrors.Error(err)
+		}
+
+		c.remove(seq)
+
+		if err = c.checkUnique(stored); err != nil {
+			c.put(seq, old)
+			rollback()
+
+			return 0, lazyerrors.Error(err)
+		}
+
+		c.put(seq, d)
+		changes = append(changes, change{seq: seq, old: old})
+	}
+
+	return int32(len(changes)), nil
+}
+
+// deleteIDs deletes documents with the given _id values and returns the number of deleted documents.
+func (c *collectionData) deleteIDs(ids []any) int32 {
+	var deleted []int64
+
+	for _, id := range ids {
+		if seq, ok := c.lookupID(id); ok {
+			c.remove(seq)
+			deleted = append(deleted, seq)
+		}
+	}
+
+	c.removeFromOrder(deleted)
+
+	return int32(len(deleted))
+}
+
+// deleteRecordIDs deletes documents with the given record IDs and returns the number of deleted documents.
+func (c *collectionData) deleteRecordIDs(recordIDs []int64) int32 {
+	set := make(map[int64]struct{}, len(recordIDs))
+	for _, id := range recordIDs {
+		set[id] = struct{}{}
+	}
+
+	var deleted []int64
+
+	for _, seq := range c.order {
+		if _, ok := set[c.docs[seq].doc.RecordID()]; ok {
+			c.remove(seq)
+			deleted = append(deleted, seq)
+		}
+	}
+
+	c.removeFromOrder(deleted)
+
+	return int32(len(deleted))
+}
+
+// createIndexes creates indexes that do not exist yet.
+//
+// If any unique index can't be created because of existing documents, no indexes are created.
+func (c *collectionData) createIndexes(infos []backends.IndexInfo) error {
+	var created []string
+
+	for _, info := range infos {
+		if _, ok := c.indexes[info.Name]; ok {
+			continue
+		}
+
+		idx := &index{
+			info: info,
+			keys: map[string][]int64{},
+		}
+
+		if info.Options != nil {
+			idx.info.Options = info.Options.DeepCopy()
+		}
+
+		for _, seq := range c.order {
+			key := idx.keyFor(c.docs[seq].doc)
+
+			if info.Unique && len(idx.keys[key]) > 0 {
+				c.dropIndexes(created)
+				return lazyerrors.Errorf("duplicate key %s for index %q", key, info.Name)
+			}
+
+			idx.add(key, seq)
+		}
+
+		c.indexes[info.Name] = idx
+		created = append(created, info.Name)
+	}
+
+	return nil
+}
+
+// dropIndexes drops indexes with the given names, if they exist.
+func (c *collectionData) dropIndexes(names []string) {
+	for _, name := range names {
+		delete(c.indexes, name)
+	}
+}
+
+// checkUnique returns an error if the given document violates a unique index.
+func (c *collectionData) checkUnique(doc *types.Document) error {
+	for name, idx := range c.indexes {
+		if !idx.info.Unique {
+			continue
+		}
+
+		if key := idx.keyFor(doc); len(idx.keys[key]) > 0 {
+			return lazyerrors.Errorf("duplicate key %s for index %q", key, name)
+		}
+	}
+
+	return nil
+}
+
+// put stores the document with the given sequence number and adds it to all indexes.
+//
+// The sequence number should be already present in the order.
+func (c *collectionData) put(seq int64, d *document) {
+	c.docs[seq] = d
+	c.size += d.size
+
+	for _, idx := range c.indexes {
+		idx.add(idx.keyFor(d.doc), seq)
+	}
+}
+
+// remove removes the document with the given sequence number from the storage and all indexes,
+// but not from the order.
+func (c *collectionData) remove(seq int64) {
+	d := c.docs[seq]
+
+	for _, idx := range c.indexes {
+		idx.remove(idx.keyFor(d.doc), seq)
+	}
+
+	c.size -= d.size
+	delete(c.docs, seq)
+}
+
+// removeFromOrder removes given sequence numbers from the order.
+func (c *collectionData) removeFromOrder(seqs []int64) {
+	if len(seqs) == 0 {
+		return
+	}
+
+	set := make(map[int64]struct{}, len(seqs))
+	for _, seq := range seqs {
+		set[seq] = struct{}{}
+	}
+
+	c.order = slices.DeleteFunc(c.order, func(seq int64) bool {
+		_, ok := set[seq]
+		return ok
+	})
+}
+
+// newDocument returns a stored document for the given frozen document.
+func newDocument(doc *types.Document) (*document, error) {
+	b, err := must.NotFail(bson.FromDocument(doc)).Encode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &document{
+		doc:  doc,
+		size: int64(len(b)),
+	}, nil
+}
+
+// keyFor returns the encoded index key for the given document.
+func (idx *index) keyFor(doc *types.Document) string {
+	values := make([]any, len(idx.info.Key))
+
+	for i, pair := range idx.info.Key {
+		values[i] = types.Null
+
+		path, err := types.NewPathFromString(pair.Field)
+		if err != nil {
+			continue
+		}
+
+		if v, err := doc.GetByPath(path); err == nil {
+			values[i] = v
+		}
+	}
+
+	return indexKey(values)
+}
+
+// add adds the document sequence number for the given key.
+func (idx *index) add(key string, seq int64) {
+	idx.keys[key] = append(idx.keys[key], seq)
+	idx.size += int64(len(key)) + indexEntrySize
+}
+
+// remove removes the document sequence number for the given key.
+func (idx *index) remove(key string, seq int64) {
+	seqs := slices.DeleteFunc(idx.keys[key], func(s int64) bool { return s == seq })
+	if len(seqs) == 0 {
+		delete(idx.keys, key)
+	} else {
+		idx.keys[key] = seqs
+	}
+
+	idx.size -= int64(len(key)) + indexEntrySize
+}
+
+// pages returns the given size rounded up to whole pages.
+func pages(size int64) int64 {
+	return (size + pageSize - 1) / pageSize * pageSize
+}
+
+// indexKey encodes the given values as a single index key.
+func indexKey(values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(must.NotFail(sjson.MarshalSingleValue(v)))
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(parts, ","))
+}
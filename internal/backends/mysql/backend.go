@@ -17,7 +17,9 @@ package mysql
 import (
 	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
+	"regexp"
 	"slices"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,6 +30,9 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
 
+// databaseNameRe validates database names; they are not quoted in all queries.
+var databaseNameRe = regexp.MustCompile("^[a-zA-Z0-9_-]{1,63}$")
+
 // backend implements backends.Backend interface.
 type backend struct {
 	r *metadata.Registry
@@ -116,6 +121,13 @@ func (b *backend) Status(ctx context.Context, params *backends.StatusParams) (*b
 
 // Database implements backends.Backend interface.
 func (b *backend) Database(name string) (backends.Database, error) {
+	if !databaseNameRe.MatchString(name) {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseNameIsInvalid,
+			fmt.Errorf("database name %q is not supported by MySQL backend", name),
+		)
+	}
+
 	return newDatabase(b.r, name), nil
 }
 
@@ -91,7 +91,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	q += where
 
-	sort, sortArgs := prepareOrderByClause(params.Sort)
+	sort, sortArgs := prepareOrderByClause(params.Sort, meta.Capped())
 
 	q += sort
 	args = append(args, sortArgs...)
@@ -111,6 +111,44 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 	}, nil
 }
 
+// Count implements backends.Collection interface.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	p, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if p == nil {
+		return new(backends.CountResult), nil
+	}
+
+	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if meta == nil {
+		return new(backends.CountResult), nil
+	}
+
+	var res backends.CountResult
+	q := prepareCountClause(c.dbName, meta.TableName, params.Comment)
+
+	if err = p.QueryRowContext(ctx, q).Scan(&res.Count); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
+// GroupCount implements backends.Collection interface.
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	return nil, backends.NewError(
+		backends.ErrorCodeGroupCountNotSupported,
+		lazyerrors.New("grouping is not supported"),
+	)
+}
+
 // InsertAll implements backends.Collection interface.
 func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
 	if _, err := c.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
@@ -195,6 +233,15 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		metadata.IDColumn,
 	)
 
+	if meta.Versioned {
+		// stored document should not be changed since it was read by the handler
+		q += fmt.Sprintf(
+			` AND COALESCE(%s->'$.%s', CAST('0' AS JSON)) = CAST(? AS JSON)`,
+			metadata.DefaultColumn,
+			backends.VersionField,
+		)
+	}
+
 	err = p.InTransaction(ctx, func(tx *fsql.Tx) error {
 		for _, doc := range params.Docs {
 			var b []byte
@@ -207,11 +254,15 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 			id, _ := doc.Get("_id")
 			must.NotBeZero(id)
 
-			arg := must.NotFail(sjson.MarshalSingleValue(id))
+			args := []any{b, must.NotFail(sjson.MarshalSingleValue(id))}
+
+			if meta.Versioned {
+				args = append(args, must.NotFail(sjson.MarshalSingleValue(backends.PreviousVersion(doc))))
+			}
 
 			var stats sql.Result
 
-			stats, err = tx.ExecContext(ctx, q, b, arg)
+			stats, err = tx.ExecContext(ctx, q, args...)
 			if err != nil {
 				return lazyerrors.Error(err)
 			}
@@ -327,7 +378,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		Capped: meta.Capped(),
 	}
 
-	q := `EXPLAIN FORMAT=JSON ` + prepareSelectClause(opts)
+	q := prepareSelectClause(opts)
 
 	where, args, err := prepareWhereClause(params.Filter)
 	if err != nil {
@@ -338,7 +389,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 
 	q += where
 
-	sort, sortArgs := prepareOrderByClause(params.Sort)
+	sort, sortArgs := prepareOrderByClause(params.Sort, meta.Capped())
 
 	q += sort
 	args = append(args, sortArgs...)
@@ -349,8 +400,11 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		res.LimitPushdown = true
 	}
 
+	res.Query = q
+	res.Args = args
+
 	var b []byte
-	if err = p.QueryRowContext(ctx, q, args...).Scan(&b); err != nil {
+	if err = p.QueryRowContext(ctx, `EXPLAIN FORMAT=JSON `+q, args...).Scan(&b); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -523,15 +577,17 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:    index.Name,
+			Unique:  index.Unique,
+			Key:     make([]backends.IndexKeyPair, len(index.Key)),
+			Options: index.Options,
 		}
 
 		for j, key := range index.Key {
 			res.Indexes[i].Key[j] = backends.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Hashed:     key.Hashed,
 			}
 		}
 	}
@@ -546,15 +602,17 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:    index.Name,
+			Key:     make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:  index.Unique,
+			Options: index.Options,
 		}
 
 		for j, key := range index.Key {
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Hashed:     key.Hashed,
 			}
 		}
 	}
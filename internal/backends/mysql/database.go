@@ -35,7 +35,7 @@ func newDatabase(r *metadata.Registry, name string) backends.Database {
 	return backends.DatabaseContract(&database{
 		r:    r,
 		name: name,
-	})
+	}, name)
 }
 
 // Collection implements backends.Database interface.
@@ -80,6 +80,7 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 			UUID:            c.UUID,
 			CappedSize:      c.CappedSize,
 			CappedDocuments: c.CappedDocuments,
+			Versioned:       c.Versioned,
 		}
 	}
 
@@ -90,11 +91,19 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	if params.Partitioned() {
+		return backends.NewError(
+			backends.ErrorCodeCollectionPartitioningNotSupported,
+			lazyerrors.New("partitioned collections are not supported"),
+		)
+	}
+
 	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
 		DBName:          db.name,
 		Name:            params.Name,
 		CappedSize:      params.CappedSize,
 		CappedDocuments: params.CappedDocuments,
+		Versioned:       params.Versioned,
 	})
 	if err != nil {
 		return lazyerrors.Error(err)
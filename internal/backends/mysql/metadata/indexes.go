@@ -29,16 +29,18 @@ type Indexes []IndexInfo
 
 // IndexInfo represents information about a single index.
 type IndexInfo struct {
-	Name   string
-	Index  string
-	Key    []IndexKeyPair
-	Unique bool
+	Name    string
+	Index   string
+	Key     []IndexKeyPair
+	Unique  bool
+	Options *types.Document
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
 type IndexKeyPair struct {
 	Field      string
 	Descending bool
+	Hashed     bool
 }
 
 // deepCopy returns a deep copy.
@@ -52,6 +54,10 @@ func (indexes Indexes) deepCopy() Indexes {
 			Key:    slices.Clone(index.Key),
 			Unique: index.Unique,
 		}
+
+		if index.Options != nil {
+			res[i].Options = index.Options.DeepCopy()
+		}
 	}
 
 	return res
@@ -65,20 +71,30 @@ func (indexes Indexes) marshal() *types.Array {
 		key := types.MakeDocument(len(index.Key))
 
 		for _, pair := range index.Key {
-			order := int32(1)
-			if pair.Descending {
+			var order any = int32(1)
+
+			switch {
+			case pair.Hashed:
+				order = "hashed"
+			case pair.Descending:
 				order = int32(-1)
 			}
 
 			key.Set(pair.Field, order)
 		}
 
-		res.Append(must.NotFail(types.NewDocument(
+		doc := must.NotFail(types.NewDocument(
 			"name", index.Name,
 			"index", index.Index,
 			"key", key,
 			"unique", index.Unique,
-		)))
+		))
+
+		if index.Options != nil {
+			doc.Set("options", index.Options)
+		}
+
+		res.Append(doc)
 	}
 
 	return res
@@ -109,25 +125,25 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 		key := make([]IndexKeyPair, keyDoc.Len())
 
 		for j, f := range fields {
-			descending := false
-			if orders[j].(int32) == -1 {
-				descending = true
-			}
-
 			key[j] = IndexKeyPair{
 				Field:      f,
-				Descending: descending,
+				Descending: orders[j] == int32(-1),
+				Hashed:     orders[j] == "hashed",
 			}
 		}
 
 		v, _ = index.Get("unique")
 		unique, _ := v.(bool)
 
+		v, _ = index.Get("options")
+		options, _ := v.(*types.Document)
+
 		res[i] = IndexInfo{
-			Name:   must.NotFail(index.Get("name")).(string),
-			Index:  must.NotFail(index.Get("index")).(string),
-			Key:    key,
-			Unique: unique,
+			Name:    must.NotFail(index.Get("name")).(string),
+			Index:   must.NotFail(index.Get("index")).(string),
+			Key:     key,
+			Unique:  unique,
+			Options: options,
 		}
 	}
 
@@ -54,6 +54,7 @@ type Collection struct {
 	Indexes         Indexes
 	CappedSize      int64
 	CappedDocuments int64
+	Versioned       bool
 }
 
 // deepCopy returns a deep copy.
@@ -69,6 +70,7 @@ func (c *Collection) deepCopy() *Collection {
 		Indexes:         c.Indexes.deepCopy(),
 		CappedSize:      c.CappedSize,
 		CappedDocuments: c.CappedDocuments,
+		Versioned:       c.Versioned,
 	}
 }
 
@@ -124,6 +126,7 @@ func (c *Collection) marshal() *types.Document {
 		"indexes", c.Indexes.marshal(),
 		"cappedSize", c.CappedSize,
 		"cappedDocuments", c.CappedDocuments,
+		"versioned", c.Versioned,
 	))
 }
 
@@ -166,6 +169,10 @@ func (c *Collection) unmarshal(doc *types.Document) error {
 		c.CappedSize = v.(int64)
 	}
 
+	if v, _ := doc.Get("versioned"); v != nil {
+		c.Versioned = v.(bool)
+	}
+
 	return nil
 }
 
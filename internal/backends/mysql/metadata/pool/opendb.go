@@ -25,6 +25,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/sqlguard"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
 
@@ -65,5 +66,5 @@ func openDB(uri string, l *slog.Logger, sp *state.Provider) (*fsql.DB, error) {
 		}
 	}
 
-	return fsql.WrapDB(db, "", l), nil
+	return fsql.WrapDB(db, "", sqlguard.MySQL, l), nil
 }
@@ -454,6 +454,7 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
+	Versioned       bool
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -531,6 +532,7 @@ func (r *Registry) collectionCreate(ctx context.Context, p *fsql.DB, params *Col
 		TableName:       tableName,
 		CappedSize:      params.CappedSize,
 		CappedDocuments: params.CappedDocuments,
+		Versioned:       params.Versioned,
 	}
 
 	q := fmt.Sprintf(`CREATE TABLE %s.%s (`, dbName, tableName)
@@ -846,6 +848,7 @@ func (r *Registry) indexesCreate(ctx context.Context, p *fsql.DB, dbName, collec
 
 		q = "ALTER TABLE %s.%s"
 
+		// InnoDB has no hash indexes, so hashed fields are indexed as regular ones
 		columns := make([]string, len(index.Key))
 
 		for i, key := range index.Key {
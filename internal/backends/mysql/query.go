@@ -49,11 +49,7 @@ func prepareSelectClause(params *selectParams) string {
 		params = new(selectParams)
 	}
 
-	if params.Comment != "" {
-		params.Comment = strings.ReplaceAll(params.Comment, "/*", "/ *")
-		params.Comment = strings.ReplaceAll(params.Comment, "*/", "* /")
-		params.Comment = `/* ` + params.Comment + ` */`
-	}
+	params.Comment = prepareComment(params.Comment)
 
 	if params.Capped && params.OnlyRecordIDs {
 		return fmt.Sprintf(
@@ -82,12 +78,36 @@ func prepareSelectClause(params *selectParams) string {
 	)
 }
 
-func prepareOrderByClause(sort *types.Document) (string, []any) {
-	if sort.Len() != 1 {
+// prepareCountClause returns SELECT clause that counts all rows of provided schema and table name.
+func prepareCountClause(schema, table, comment string) string {
+	return fmt.Sprintf(`SELECT %s COUNT(*) FROM %q.%q`, prepareComment(comment), schema, table)
+}
+
+// prepareComment returns SQL comment for the given query comment, or empty string.
+func prepareComment(comment string) string {
+	if comment == "" {
+		return ""
+	}
+
+	comment = strings.ReplaceAll(comment, "/*", "/ *")
+	comment = strings.ReplaceAll(comment, "*/", "* /")
+
+	return `/* ` + comment + ` */`
+}
+
+// prepareOrderByClause returns ORDER BY clause for given sort document.
+//
+// Sort by fields is not supported; an empty clause is returned for it.
+//
+// $natural sort uses RecordID column for capped collections.
+// Tables of other collections have no column to order by,
+// so an empty clause is returned, and rows are returned in the table scan order.
+func prepareOrderByClause(sort *types.Document, capped bool) (string, []any) {
+	v, _ := sort.Get("$natural")
+	if v == nil || !capped {
 		return "", nil
 	}
 
-	v := must.NotFail(sort.Get("$natural"))
 	var order string
 
 	switch v.(int64) {
@@ -179,7 +199,7 @@ func prepareWhereClause(sqlFilters *types.Document) (string, []any, error) {
 
 					switch v := v.(type) {
 					case *types.Document, *types.Array, types.Binary,
-						types.NullType, types.Regex, types.Timestamp:
+						types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 					// type not supported for pushdown
 
 					case float64, bool, int32, int64:
@@ -211,7 +231,7 @@ func prepareWhereClause(sqlFilters *types.Document) (string, []any, error) {
 				}
 			}
 
-		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp:
+		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 			// type not supported for pushdown
 
 		case float64, string, types.ObjectID, bool, time.Time, int32, int64:
@@ -241,7 +261,7 @@ func filterEqual(k string, v any) (filter string, args []any) {
 
 	switch v := v.(type) {
 	case *types.Document, *types.Array, types.Binary,
-		types.NullType, types.Regex, types.Timestamp:
+		types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 		// type not supported for pushdown
 
 	case float64:
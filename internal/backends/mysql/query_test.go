@@ -321,8 +321,9 @@ func TestPrepareOrderByClause(t *testing.T) {
 	t.Parallel()
 
 	for name, tc := range map[string]struct { //nolint:vet // used for test only
-		sort *types.Document
-		skip string
+		sort   *types.Document
+		capped bool
+		skip   string
 
 		orderBy string
 		args    []any
@@ -351,12 +352,22 @@ func TestPrepareOrderByClause(t *testing.T) {
 		},
 		"NaturalAscending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id`,
 		},
 		"NaturalDescending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id DESC`,
 		},
+		"NaturalAscendingNotCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			orderBy: ``,
+		},
+		"NaturalDescendingNotCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			orderBy: ``,
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -366,7 +377,7 @@ func TestPrepareOrderByClause(t *testing.T) {
 				t.Skip(tc.skip)
 			}
 
-			orderBy, args := prepareOrderByClause(tc.sort)
+			orderBy, args := prepareOrderByClause(tc.sort, tc.capped)
 
 			assert.Equal(t, tc.orderBy, orderBy)
 			assert.Equal(t, tc.args, args)
@@ -17,14 +17,18 @@ package postgresql
 import (
 	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/secret"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
 
@@ -38,6 +42,8 @@ type backend struct {
 //nolint:vet // for readability
 type NewBackendParams struct {
 	URI       string
+	URIFile   *secret.File // if set, URI is ignored, and changes of the file are applied to new connections
+	TLS       *TLSParams   // if set, overrides TLS parameters of URI
 	L         *slog.Logger
 	P         *state.Provider
 	BatchSize int
@@ -46,11 +52,40 @@ type NewBackendParams struct {
 
 // NewBackend creates a new Backend.
 func NewBackend(params *NewBackendParams) (backends.Backend, error) {
-	r, err := metadata.NewRegistry(params.URI, params.BatchSize, params.L, params.P)
+	uri := params.URI
+	if params.URIFile != nil {
+		uri = params.URIFile.Value()
+	}
+
+	if params.TLS.enabled() {
+		if err := params.TLS.check(); err != nil {
+			return nil, err
+		}
+	}
+
+	uri, err := params.TLS.apply(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := metadata.NewRegistry(uri, params.BatchSize, params.L, params.P)
 	if err != nil {
 		return nil, err
 	}
 
+	if params.URIFile != nil {
+		params.URIFile.OnChange(func(u string) {
+			u, err := params.TLS.apply(u)
+			if err == nil {
+				err = r.SetURI(u)
+			}
+
+			if err != nil {
+				params.L.Error("Failed to apply new PostgreSQL URL", logging.Error(err))
+			}
+		})
+	}
+
 	return backends.BackendContract(&backend{
 		r: r,
 	}), nil
@@ -117,6 +152,14 @@ func (b *backend) Status(ctx context.Context, params *backends.StatusParams) (*b
 
 // Database implements backends.Backend interface.
 func (b *backend) Database(name string) (backends.Database, error) {
+	// databases are stored in schemas, and that prefix is reserved for system schemas
+	if strings.HasPrefix(name, "pg_") {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseNameIsInvalid,
+			fmt.Errorf("database name %q has prefix reserved by PostgreSQL", name),
+		)
+	}
+
 	return newDatabase(b.r, name), nil
 }
 
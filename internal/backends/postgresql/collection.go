@@ -59,7 +59,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	if p == nil {
 		return &backends.QueryResult{
-			Iter: newQueryIterator(ctx, nil, params.OnlyRecordIDs),
+			Iter: newQueryIterator(ctx, nil, nil, params.OnlyRecordIDs),
 		}, nil
 	}
 
@@ -70,7 +70,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 
 	if meta == nil {
 		return &backends.QueryResult{
-			Iter: newQueryIterator(ctx, nil, params.OnlyRecordIDs),
+			Iter: newQueryIterator(ctx, nil, nil, params.OnlyRecordIDs),
 		}, nil
 	}
 
@@ -92,9 +92,15 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		return nil, lazyerrors.Error(err)
 	}
 
+	if meta.Partitioned() {
+		var partitionArgs []any
+		where, partitionArgs = addPartitionClause(&placeholder, where, meta.PartitionKey, params.Filter)
+		args = append(args, partitionArgs...)
+	}
+
 	q += where
 
-	sort, sortArgs := prepareOrderByClause(params.Sort)
+	sort, sortArgs := prepareOrderByClause(params.Sort, meta.Indexes, meta.Capped())
 
 	q += sort
 	args = append(args, sortArgs...)
@@ -104,16 +110,155 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		args = append(args, params.Limit)
 	}
 
-	rows, err := p.Query(ctx, q, args...)
+	// the transaction is kept open until the iterator is closed,
+	// so it sees a consistent snapshot even if it is consumed over several getMore commands
+	tx, err := p.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	rows, err := tx.Query(ctx, q, args...)
 	if err != nil {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
 		return nil, lazyerrors.Error(err)
 	}
 
 	return &backends.QueryResult{
-		Iter: newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		Iter:         newQueryIterator(ctx, tx, rows, params.OnlyRecordIDs),
+		SortPushdown: sort != "",
 	}, nil
 }
 
+// Count implements backends.Collection interface.
+//
+// For tables with at least countEstimateThreshold rows according to the planner statistics,
+// it returns that estimate instead of counting rows.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	p, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if p == nil {
+		return new(backends.CountResult), nil
+	}
+
+	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if meta == nil {
+		return new(backends.CountResult), nil
+	}
+
+	var res backends.CountResult
+
+	// reltuples is -1 for tables that were not vacuumed or analyzed yet, and for partitioned tables
+	q := `SELECT reltuples::bigint FROM pg_class WHERE oid = $1::regclass`
+	table := pgx.Identifier{c.dbName, meta.TableName}.Sanitize()
+
+	if err = p.QueryRow(ctx, q, table).Scan(&res.Count); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if res.Count >= countEstimateThreshold {
+		return &res, nil
+	}
+
+	q = prepareCountClause(c.dbName, meta.TableName, params.Comment)
+
+	if err = p.QueryRow(ctx, q).Scan(&res.Count); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
+// GroupCount implements backends.Collection interface.
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	p, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if p == nil {
+		return new(backends.GroupCountResult), nil
+	}
+
+	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if meta == nil {
+		return new(backends.GroupCountResult), nil
+	}
+
+	var placeholder metadata.Placeholder
+
+	q, args := prepareGroupCountClause(&placeholder, c.dbName, meta.TableName, params.Comment, params.GroupBy)
+
+	where, whereArgs, ok := prepareGroupCountWhereClause(&placeholder, params.Filter)
+	if !ok {
+		return nil, backends.NewError(
+			backends.ErrorCodeGroupCountNotSupported,
+			lazyerrors.Errorf("filter %v can't be applied exactly", types.FormatAnyValue(params.Filter)),
+		)
+	}
+
+	q += where
+	args = append(args, whereArgs...)
+
+	var res backends.GroupCountResult
+
+	if params.GroupBy == "" {
+		var count int64
+		if err = p.QueryRow(ctx, q, args...).Scan(&count); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if count > 0 {
+			res.Groups = []backends.GroupCount{{ID: types.Null, Count: count}}
+		}
+
+		return &res, nil
+	}
+
+	q += ` GROUP BY 1, 2`
+
+	rows, err := p.Query(ctx, q, args...)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value, schema []byte
+		var group backends.GroupCount
+
+		if err = rows.Scan(&value, &schema, &group.Count); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		group.ID = types.Null
+
+		if value != nil {
+			if group.ID, err = sjson.UnmarshalSingleValue(value, schema); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		res.Groups = append(res.Groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
 // InsertAll implements backends.Collection interface.
 func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
 	if _, err := c.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
@@ -149,7 +294,7 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 			var q string
 			var args []any
 
-			q, args, err = prepareInsertStatement(c.dbName, meta.TableName, meta.Capped(), batch)
+			q, args, err = prepareInsertStatement(c.dbName, meta.TableName, meta.Capped(), meta.PartitionKey, batch)
 			if err != nil {
 				return lazyerrors.Error(err)
 			}
@@ -201,6 +346,27 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		metadata.IDColumn,
 	)
 
+	if meta.Partitioned() {
+		// changed partition key value moves the row to another partition
+		q = fmt.Sprintf(
+			`UPDATE %s SET %s = $1, %s = $3 WHERE %s = $2`,
+			pgx.Identifier{c.dbName, meta.TableName}.Sanitize(),
+			metadata.DefaultColumn,
+			metadata.PartitionKeyColumn,
+			metadata.IDColumn,
+		)
+	}
+
+	if meta.Versioned {
+		// stored document should not be changed since it was read by the handler
+		n := 3
+		if meta.Partitioned() {
+			n = 4
+		}
+
+		q += fmt.Sprintf(` AND COALESCE(%s->'%s', '0') = $%d`, metadata.DefaultColumn, backends.VersionField, n)
+	}
+
 	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
 		for _, doc := range params.Docs {
 			var b []byte
@@ -211,10 +377,23 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 			id, _ := doc.Get("_id")
 			must.NotBeZero(id)
 
-			arg := must.NotFail(sjson.MarshalSingleValue(id))
+			args := []any{b, must.NotFail(sjson.MarshalSingleValue(id))}
+
+			if meta.Partitioned() {
+				var v string
+				if v, err = partitionKeyValue(doc, meta.PartitionKey); err != nil {
+					return lazyerrors.Error(err)
+				}
+
+				args = append(args, v)
+			}
+
+			if meta.Versioned {
+				args = append(args, must.NotFail(sjson.MarshalSingleValue(backends.PreviousVersion(doc))))
+			}
 
 			var tag pgconn.CommandTag
-			if tag, err = tx.Exec(ctx, q, b, arg); err != nil {
+			if tag, err = tx.Exec(ctx, q, args...); err != nil {
 				return lazyerrors.Error(err)
 			}
 
@@ -330,7 +509,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		Capped: meta.Capped(),
 	}
 
-	q := `EXPLAIN (VERBOSE true, FORMAT JSON) ` + prepareSelectClause(opts)
+	q := prepareSelectClause(opts)
 
 	var placeholder metadata.Placeholder
 
@@ -341,9 +520,15 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 
 	res.FilterPushdown = where != ""
 
+	if meta.Partitioned() {
+		var partitionArgs []any
+		where, partitionArgs = addPartitionClause(&placeholder, where, meta.PartitionKey, params.Filter)
+		args = append(args, partitionArgs...)
+	}
+
 	q += where
 
-	sort, sortArgs := prepareOrderByClause(params.Sort)
+	sort, sortArgs := prepareOrderByClause(params.Sort, meta.Indexes, meta.Capped())
 	res.SortPushdown = sort != ""
 
 	q += sort
@@ -355,8 +540,11 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		res.LimitPushdown = true
 	}
 
+	res.Query = q
+	res.Args = args
+
 	var b []byte
-	if err = p.QueryRow(ctx, q, args...).Scan(&b); err != nil {
+	if err = p.QueryRow(ctx, `EXPLAIN (VERBOSE true, FORMAT JSON) `+q, args...).Scan(&b); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -530,15 +718,17 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 	for i, index := range coll.Indexes {
 		res.Indexes[i] = backends.IndexInfo{
-			Name:   index.Name,
-			Unique: index.Unique,
-			Key:    make([]backends.IndexKeyPair, len(index.Key)),
+			Name:    index.Name,
+			Unique:  index.Unique,
+			Key:     make([]backends.IndexKeyPair, len(index.Key)),
+			Options: index.Options,
 		}
 
 		for j, key := range index.Key {
 			res.Indexes[i].Key[j] = backends.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Hashed:     key.Hashed,
 			}
 		}
 	}
@@ -555,15 +745,17 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
-			Name:   index.Name,
-			Key:    make([]metadata.IndexKeyPair, len(index.Key)),
-			Unique: index.Unique,
+			Name:    index.Name,
+			Key:     make([]metadata.IndexKeyPair, len(index.Key)),
+			Unique:  index.Unique,
+			Options: index.Options,
 		}
 
 		for j, key := range index.Key {
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Hashed:     key.Hashed,
 			}
 		}
 	}
@@ -35,7 +35,7 @@ func newDatabase(r *metadata.Registry, name string) backends.Database {
 	return backends.DatabaseContract(&database{
 		r:    r,
 		name: name,
-	})
+	}, name)
 }
 
 // Collection implements backends.Database interface.
@@ -79,6 +79,7 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 			UUID:            c.UUID,
 			CappedSize:      c.CappedSize,
 			CappedDocuments: c.CappedDocuments,
+			Versioned:       c.Versioned,
 		}
 	}
 
@@ -90,10 +91,18 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
 	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
-		DBName:          db.name,
-		Name:            params.Name,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
+		DBName:           db.name,
+		Name:             params.Name,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		PartitionKey:     params.PartitionKey,
+		PartitionHashed:  params.PartitionHashed,
+		Partitions:       params.Partitions,
+		PartitionBounds:  params.PartitionBounds,
+		Versioned:        params.Versioned,
+		FillFactor:       params.StorageOptions.FillFactor,
+		ToastCompression: params.StorageOptions.ToastCompression,
+		Tablespace:       params.StorageOptions.Tablespace,
 	})
 	if err != nil {
 		return lazyerrors.Error(err)
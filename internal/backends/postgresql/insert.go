@@ -29,11 +29,23 @@ import (
 // prepareInsertStatement returns a statement and arguments for inserting the given documents.
 //
 // If capped is true, it returns a statement and arguments for inserting record IDs and documents.
-func prepareInsertStatement(schema, tableName string, capped bool, docs []*types.Document) (string, []any, error) {
+//
+// If partitionKey is not empty, it returns a statement and arguments for inserting documents
+// and their partition key values.
+func prepareInsertStatement(schema, tableName string, capped bool, partitionKey string, docs []*types.Document) (string, []any, error) { //nolint:lll // for readability
 	var placeholder metadata.Placeholder
 	var args []any
 	rows := make([]string, len(docs))
 
+	columns := []string{metadata.DefaultColumn}
+	if capped {
+		columns = []string{metadata.RecordIDColumn, metadata.DefaultColumn}
+	}
+
+	if partitionKey != "" {
+		columns = append(columns, metadata.PartitionKeyColumn)
+	}
+
 	for i, doc := range docs {
 		b, err := sjson.Marshal(doc)
 		if err != nil {
@@ -41,25 +53,55 @@ func prepareInsertStatement(schema, tableName string, capped bool, docs []*types
 		}
 
 		if capped {
-			rows[i] = "(" + placeholder.Next() + ", " + placeholder.Next() + ")"
-			args = append(args, doc.RecordID(), string(b))
-
-			continue
+			args = append(args, doc.RecordID())
 		}
 
-		rows[i] = "(" + placeholder.Next() + ")"
 		args = append(args, string(b))
-	}
 
-	columns := metadata.DefaultColumn
-	if capped {
-		columns = strings.Join([]string{metadata.RecordIDColumn, metadata.DefaultColumn}, ", ")
+		if partitionKey != "" {
+			var v string
+			if v, err = partitionKeyValue(doc, partitionKey); err != nil {
+				return "", nil, lazyerrors.Error(err)
+			}
+
+			args = append(args, v)
+		}
+
+		placeholders := make([]string, len(columns))
+		for j := range placeholders {
+			placeholders[j] = placeholder.Next()
+		}
+
+		rows[i] = "(" + strings.Join(placeholders, ", ") + ")"
 	}
 
 	return fmt.Sprintf(
 		`INSERT INTO %s (%s) VALUES %s`,
 		pgx.Identifier{schema, tableName}.Sanitize(),
-		columns,
+		strings.Join(columns, ", "),
 		strings.Join(rows, ", "),
 	), args, nil
 }
+
+// partitionKeyValue returns the value of the partition key field of the given document
+// in the form stored in the partition key column.
+//
+// Missing field is stored as null.
+// Arrays are not allowed, as documents with them could not be found by partition pruning.
+func partitionKeyValue(doc *types.Document, partitionKey string) (string, error) {
+	v, _ := doc.Get(partitionKey)
+
+	switch v.(type) {
+	case nil:
+		v = types.Null
+	case *types.Array:
+		return "", lazyerrors.Errorf("partition key %q cannot contain array values", partitionKey)
+	}
+
+	b, err := sjson.MarshalSingleValue(v)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	return string(b), nil
+}
@@ -16,7 +16,9 @@ package metadata
 
 import (
 	"errors"
+	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -33,12 +35,133 @@ type IndexInfo struct {
 	PgIndex string
 	Key     []IndexKeyPair
 	Unique  bool
+	Options *types.Document
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
 type IndexKeyPair struct {
 	Field      string
 	Descending bool
+	Hashed     bool
+}
+
+// sparse returns true if the index contains only documents with at least one of the indexed fields.
+func (index *IndexInfo) sparse() bool {
+	if index.Options == nil {
+		return false
+	}
+
+	v, _ := index.Options.Get("sparse")
+	sparse, _ := v.(bool)
+
+	return sparse
+}
+
+// keyPath returns SQL expression for the value of the given (possibly dot-separated) field.
+func keyPath(field string) string {
+	// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
+	fs := strings.Split(field, ".")
+	transformedParts := make([]string, len(fs))
+
+	for j, f := range fs {
+		// It's important to sanitize field data here, as it's a user-provided value.
+		transformedParts[j] = quoteString(f)
+	}
+
+	return fmt.Sprintf("%s->%s", DefaultColumn, strings.Join(transformedParts, " -> "))
+}
+
+// hashOnly returns true if the index uses PostgreSQL hash index access method.
+//
+// That method supports only a single column, so it is used only for a single hashed field.
+func (index *IndexInfo) hashOnly() bool {
+	return len(index.Key) == 1 && index.Key[0].Hashed
+}
+
+// keyColumn returns SQL expression of the index column for the given key.
+//
+// Missing fields are placed before all values, like in MongoDB.
+func (index *IndexInfo) keyColumn(key IndexKeyPair) string {
+	if key.Hashed {
+		if index.hashOnly() {
+			return fmt.Sprintf("((%s))", keyPath(key.Field))
+		}
+
+		// compound indexes with a hashed field use btree with hash values
+		return fmt.Sprintf("(jsonb_hash_extended(%s, 0))", keyPath(key.Field))
+	}
+
+	var res string
+
+	// missing fields are indexed as nulls, so unique indexes allow only one document without the field
+	if index.Unique {
+		res = fmt.Sprintf("(COALESCE(%s, 'null'::jsonb))", keyPath(key.Field))
+	} else {
+		res = fmt.Sprintf("((%s))", keyPath(key.Field))
+	}
+
+	if key.Descending {
+		return res + " DESC NULLS LAST"
+	}
+
+	return res + " NULLS FIRST"
+}
+
+// sortColumns returns ORDER BY expressions for the given sort document
+// if the index could be used for it, or nil otherwise.
+//
+// Sort fields should match the first fields of the index key,
+// with either all the same or all reversed orders.
+// Hashed fields can't be used for sorting.
+func (index *IndexInfo) sortColumns(sort *types.Document) []string {
+	if index.sparse() || sort.Len() > len(index.Key) {
+		return nil
+	}
+
+	fields := sort.Keys()
+	orders := sort.Values()
+	res := make([]string, len(fields))
+
+	var reverse bool
+
+	for i, f := range fields {
+		key := index.Key[i]
+		if key.Field != f || key.Hashed {
+			return nil
+		}
+
+		descending := orders[i].(int64) == -1
+
+		if i == 0 {
+			reverse = descending != key.Descending
+		}
+
+		if (descending != key.Descending) != reverse {
+			return nil
+		}
+
+		res[i] = index.keyColumn(IndexKeyPair{Field: f, Descending: descending})
+	}
+
+	return res
+}
+
+// SortColumns returns ORDER BY expressions for the given sort document
+// if one of the indexes could be used for it, or nil otherwise.
+//
+// See [IndexInfo.sortColumns] for details.
+func (indexes Indexes) SortColumns(sort *types.Document) []string {
+	if sort.Len() == 0 {
+		return nil
+	}
+
+	for _, index := range indexes {
+		if res := index.sortColumns(sort); res != nil {
+			return res
+		}
+	}
+
+	return nil
 }
 
 // deepCopy returns a deep copy.
@@ -52,6 +175,10 @@ func (indexes Indexes) deepCopy() Indexes {
 			Key:     slices.Clone(index.Key),
 			Unique:  index.Unique,
 		}
+
+		if index.Options != nil {
+			res[i].Options = index.Options.DeepCopy()
+		}
 	}
 
 	return res
@@ -65,20 +192,30 @@ func (indexes Indexes) marshal() *types.Array {
 		key := types.MakeDocument(len(index.Key))
 
 		for _, pair := range index.Key {
-			order := int32(1)
-			if pair.Descending {
+			var order any = int32(1)
+
+			switch {
+			case pair.Hashed:
+				order = "hashed"
+			case pair.Descending:
 				order = int32(-1)
 			}
 
 			key.Set(pair.Field, order)
 		}
 
-		res.Append(must.NotFail(types.NewDocument(
+		doc := must.NotFail(types.NewDocument(
 			"pgindex", index.PgIndex,
 			"name", index.Name,
 			"key", key,
 			"unique", index.Unique,
-		)))
+		))
+
+		if index.Options != nil {
+			doc.Set("options", index.Options)
+		}
+
+		res.Append(doc)
 	}
 
 	return res
@@ -109,14 +246,10 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 		key := make([]IndexKeyPair, keyDoc.Len())
 
 		for j, f := range fields {
-			descending := false
-			if orders[j].(int32) == -1 {
-				descending = true
-			}
-
 			key[j] = IndexKeyPair{
 				Field:      f,
-				Descending: descending,
+				Descending: orders[j] == int32(-1),
+				Hashed:     orders[j] == "hashed",
 			}
 		}
 
@@ -124,11 +257,15 @@ func (s *Indexes) unmarshal(a *types.Array) error {
 		v, _ = index.Get("unique")
 		unique, _ := v.(bool)
 
+		v, _ = index.Get("options")
+		options, _ := v.(*types.Document)
+
 		res[i] = IndexInfo{
 			Name:    must.NotFail(index.Get("name")).(string),
 			PgIndex: must.NotFail(index.Get("pgindex")).(string),
 			Key:     key,
 			Unique:  unique,
+			Options: options,
 		}
 	}
 
@@ -35,6 +35,9 @@ const (
 
 	// RecordIDColumn is a name for RecordID column to store capped collection record id.
 	RecordIDColumn = backends.ReservedPrefix + "record_id"
+
+	// PartitionKeyColumn is a name for column to store partition key value of partitioned collection.
+	PartitionKeyColumn = backends.ReservedPrefix + "partition_key"
 )
 
 // Collection represents collection metadata.
@@ -48,6 +51,9 @@ type Collection struct {
 	Indexes         Indexes
 	CappedSize      int64
 	CappedDocuments int64
+	PartitionKey    string
+	PartitionHashed bool
+	Versioned       bool
 }
 
 // deepCopy returns a deep copy.
@@ -63,6 +69,9 @@ func (c *Collection) deepCopy() *Collection {
 		Indexes:         c.Indexes.deepCopy(),
 		CappedSize:      c.CappedSize,
 		CappedDocuments: c.CappedDocuments,
+		PartitionKey:    c.PartitionKey,
+		PartitionHashed: c.PartitionHashed,
+		Versioned:       c.Versioned,
 	}
 }
 
@@ -71,6 +80,11 @@ func (c Collection) Capped() bool {
 	return c.CappedSize > 0
 }
 
+// Partitioned returns true if collection is partitioned.
+func (c Collection) Partitioned() bool {
+	return c.PartitionKey != ""
+}
+
 // Value implements driver.Valuer interface.
 func (c Collection) Value() (driver.Value, error) {
 	b, err := sjson.Marshal(c.marshal())
@@ -118,6 +132,9 @@ func (c *Collection) marshal() *types.Document {
 		"indexes", c.Indexes.marshal(),
 		"cappedSize", c.CappedSize,
 		"cappedDocs", c.CappedDocuments,
+		"partitionKey", c.PartitionKey,
+		"partitionHashed", c.PartitionHashed,
+		"versioned", c.Versioned,
 	))
 }
 
@@ -162,6 +179,18 @@ func (c *Collection) unmarshal(doc *types.Document) error {
 		c.CappedDocuments = v.(int64)
 	}
 
+	if v, _ := doc.Get("partitionKey"); v != nil {
+		c.PartitionKey = v.(string)
+	}
+
+	if v, _ := doc.Get("partitionHashed"); v != nil {
+		c.PartitionHashed = v.(bool)
+	}
+
+	if v, _ := doc.Get("versioned"); v != nil {
+		c.Versioned = v.(bool)
+	}
+
 	return nil
 }
 
@@ -25,6 +25,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
 
+	"github.com/FerretDB/FerretDB/internal/util/debugbuild"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/state"
@@ -72,11 +73,17 @@ func openDB(uri string, l *slog.Logger, sp *state.Provider) (*pgxpool.Pool, erro
 	// TODO https://github.com/FerretDB/FerretDB/issues/3554
 
 	// try to log everything; logger's configuration will skip extra levels if needed
-	config.ConnConfig.Tracer = &tracelog.TraceLog{
+	tl := &tracelog.TraceLog{
 		Logger:   logging.NewPgxLogger(l),
 		LogLevel: tracelog.LogLevelTrace,
 	}
 
+	config.ConnConfig.Tracer = tl
+
+	if debugbuild.Enabled {
+		config.ConnConfig.Tracer = &guardTracer{TraceLog: tl}
+	}
+
 	config.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
 
 	// see https://github.com/jackc/pgx/issues/1726#issuecomment-1711612138
@@ -18,13 +18,18 @@
 package pool
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/url"
 	"sync"
 
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/resource"
@@ -41,27 +46,23 @@ const (
 //
 //nolint:vet // for readability
 type Pool struct {
-	baseURI url.URL
-	l       *slog.Logger
-	sp      *state.Provider
+	l  *slog.Logger
+	sp *state.Provider
 
-	rw    sync.RWMutex
-	pools map[string]*pgxpool.Pool // by full URI
+	rw      sync.RWMutex
+	baseURI url.URL
+	pools   map[string]*pgxpool.Pool // by full URI
 
 	token *resource.Token
 }
 
 // New creates a new Pool.
 func New(u string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
-	baseURI, err := url.Parse(u)
+	baseURI, err := parseBaseURI(u)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	values := baseURI.Query()
-	setDefaultValues(values)
-	baseURI.RawQuery = values.Encode()
-
 	p := &Pool{
 		baseURI: *baseURI,
 		l:       l,
@@ -75,6 +76,49 @@ func New(u string, l *slog.Logger, sp *state.Provider) (*Pool, error) {
 	return p, nil
 }
 
+// parseBaseURI parses the given base URI and sets default values.
+func parseBaseURI(u string) (*url.URL, error) {
+	baseURI, err := url.Parse(u)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	values := baseURI.Query()
+	setDefaultValues(values)
+	baseURI.RawQuery = values.Encode()
+
+	return baseURI, nil
+}
+
+// SetBaseURI replaces the base URI, for example, after credentials rotation or host change.
+//
+// New connections use the new URI.
+// All pools derived from the old base URI, including ones with per-user credentials,
+// are removed, and their connections are closed after they are released.
+func (p *Pool) SetBaseURI(u string) error {
+	baseURI, err := parseBaseURI(u)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	p.rw.Lock()
+	defer p.rw.Unlock()
+
+	p.baseURI = *baseURI
+
+	old := p.pools
+	p.pools = make(map[string]*pgxpool.Pool, len(old))
+
+	for _, pool := range old {
+		// Close blocks until all connections are released
+		go pool.Close()
+	}
+
+	p.l.Info("Pool: base URI changed", slog.Int("closed_pools", len(old)))
+
+	return nil
+}
+
 // Close closes all connections in the pool.
 func (p *Pool) Close() {
 	p.rw.Lock()
@@ -89,21 +133,30 @@ func (p *Pool) Close() {
 	resource.Untrack(p, p.token)
 }
 
-// Get returns a pool of connections to PostgreSQL database for that username/password combination.
-func (p *Pool) Get(username, password string) (*pgxpool.Pool, error) {
-	// do not log password or full URL
+// uri returns the full URI for that username/password combination.
+//
+// It should be called with the lock held.
+func (p *Pool) uri(username, password string) string {
+	uri := p.baseURI
 
 	// replace authentication info only if it is passed
-	uri := p.baseURI
 	if username != "" {
 		uri.User = url.UserPassword(username, password)
 	}
 
-	u := uri.String()
+	return uri.String()
+}
+
+// Get returns a pool of connections to PostgreSQL database for that username/password combination.
+//
+// If PostgreSQL rejects credentials, the returned error wraps [backends.ErrAuthenticationFailed].
+func (p *Pool) Get(username, password string) (*pgxpool.Pool, error) {
+	// do not log password or full URL
 
 	// fast path
 
 	p.rw.RLock()
+	u := p.uri(username, password)
 	res := p.pools[u]
 	p.rw.RUnlock()
 
@@ -117,7 +170,10 @@ func (p *Pool) Get(username, password string) (*pgxpool.Pool, error) {
 	p.rw.Lock()
 	defer p.rw.Unlock()
 
-	// a concurrent connection might have created a pool already; check again
+	// base URI might have been changed, and a concurrent connection might have created a pool already;
+	// check again
+	u = p.uri(username, password)
+
 	if res = p.pools[u]; res != nil {
 		p.l.Debug("Pool: found existing pool (after acquiring lock)", slog.String("username", username))
 		return res, nil
@@ -126,6 +182,14 @@ func (p *Pool) Get(username, password string) (*pgxpool.Pool, error) {
 	res, err := openDB(u, p.l, p.sp)
 	if err != nil {
 		p.l.Warn("Pool: connection failed", slog.String("username", username), logging.Error(err))
+
+		// let the handler track failed authentication attempts
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == pgerrcode.InvalidPassword ||
+			pgErr.Code == pgerrcode.InvalidAuthorizationSpecification) {
+			return nil, lazyerrors.Error(fmt.Errorf("%w: %w", backends.ErrAuthenticationFailed, err))
+		}
+
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -0,0 +1,45 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pool
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/tracelog"
+
+	"github.com/FerretDB/FerretDB/internal/util/sqlguard"
+)
+
+// guardTracer wraps [*tracelog.TraceLog] to check all queries with [sqlguard.Assert].
+//
+// It is used only in debug builds.
+type guardTracer struct {
+	*tracelog.TraceLog
+}
+
+// TraceQueryStart implements [pgx.QueryTracer].
+func (t *guardTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	sqlguard.Assert(sqlguard.PostgreSQL, data.SQL, data.Args)
+
+	return t.TraceLog.TraceQueryStart(ctx, conn, data)
+}
+
+// check interfaces
+var (
+	_ pgx.QueryTracer   = (*guardTracer)(nil)
+	_ pgx.ConnectTracer = (*guardTracer)(nil)
+	_ pgx.PrepareTracer = (*guardTracer)(nil)
+)
@@ -101,6 +101,11 @@ func NewRegistry(u string, batchSize int, l *slog.Logger, sp *state.Provider) (*
 	return r, nil
 }
 
+// SetURI replaces the base URI; see [pool.Pool.SetBaseURI].
+func (r *Registry) SetURI(u string) error {
+	return r.p.SetBaseURI(u)
+}
+
 // Close closes the registry.
 func (r *Registry) Close() {
 	r.p.Close()
@@ -445,7 +450,17 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+	PartitionKey    string
+	PartitionHashed bool
+	Partitions      int64
+	PartitionBounds []any
+	Versioned       bool
+
+	FillFactor       int64
+	ToastCompression string
+	Tablespace       string
+
+	_ struct{} // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -453,6 +468,23 @@ func (ccp *CollectionCreateParams) Capped() bool {
 	return ccp.CappedSize > 0 // TODO https://github.com/FerretDB/FerretDB/issues/3631
 }
 
+// Partitioned returns true if partitioned collection creation is requested.
+func (ccp *CollectionCreateParams) Partitioned() bool {
+	return ccp.PartitionKey != ""
+}
+
+// partitionsCount returns the number of partitions to create for the collection.
+func (ccp *CollectionCreateParams) partitionsCount() int {
+	switch {
+	case !ccp.Partitioned():
+		return 0
+	case ccp.PartitionHashed:
+		return int(ccp.Partitions)
+	default:
+		return len(ccp.PartitionBounds) + 1
+	}
+}
+
 // CollectionCreate creates a collection in the database.
 // Database will be created automatically if needed.
 //
@@ -478,6 +510,9 @@ func (r *Registry) CollectionCreate(ctx context.Context, params *CollectionCreat
 // Returned boolean value indicates whether the collection was created.
 // If collection already exists, (false, nil) is returned.
 //
+// Table name is derived from the collection name, truncated to fit PostgreSQL identifier limit,
+// and suffixed with the collection name hash; the original name is stored in the metadata.
+//
 // It does not hold the lock.
 func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params *CollectionCreateParams) (bool, error) {
 	dbName, collectionName := params.DBName, params.Name
@@ -499,11 +534,17 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 	var tableName string
 	list := maps.Values(colls)
 
+	// reserve space for partition table name suffixes
+	var suffixPartition string
+	if n := params.partitionsCount(); n > 0 {
+		suffixPartition = partitionTableName("", n-1)
+	}
+
 	for {
 		tableName = specialCharacters.ReplaceAllString(strings.ToLower(collectionName), "_")
 
 		suffixHash := fmt.Sprintf("_%08x", s)
-		if l := maxTableNameLength - len(suffixHash); len(tableName) > l {
+		if l := maxTableNameLength - len(suffixHash) - len(suffixPartition); len(tableName) > l {
 			tableName = tableName[:l]
 		}
 
@@ -523,6 +564,9 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 		TableName:       tableName,
 		CappedSize:      params.CappedSize,
 		CappedDocuments: params.CappedDocuments,
+		PartitionKey:    params.PartitionKey,
+		PartitionHashed: params.PartitionHashed,
+		Versioned:       params.Versioned,
 	}
 
 	q := fmt.Sprintf(`CREATE TABLE %s (`, pgx.Identifier{dbName, tableName}.Sanitize())
@@ -531,10 +575,57 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 		q += fmt.Sprintf(`%s bigint PRIMARY KEY, `, RecordIDColumn)
 	}
 
-	q += fmt.Sprintf(`%s jsonb)`, DefaultColumn)
+	q += fmt.Sprintf(`%s jsonb`, DefaultColumn)
 
-	if _, err = p.Exec(ctx, q); err != nil {
-		return false, lazyerrors.Error(err)
+	switch params.ToastCompression {
+	case "":
+		// use the default
+	case "pglz", "lz4":
+		// partitions inherit compression method from the partitioned table
+		q += ` COMPRESSION ` + params.ToastCompression
+	default:
+		return false, lazyerrors.Errorf("unexpected compression method %q", params.ToastCompression)
+	}
+
+	if !params.Partitioned() {
+		q += `)` + prepareStorageClause(params)
+
+		if _, err = p.Exec(ctx, q); err != nil {
+			return false, lazyerrors.Error(err)
+		}
+	} else {
+		method := "RANGE"
+		if params.PartitionHashed {
+			method = "HASH"
+		}
+
+		q += fmt.Sprintf(`, %[1]s jsonb NOT NULL) PARTITION BY %[2]s (%[1]s)`, PartitionKeyColumn, method)
+
+		// partitioned tables can't have storage parameters, only partitions can
+		if params.Tablespace != "" {
+			q += ` TABLESPACE ` + pgx.Identifier{params.Tablespace}.Sanitize()
+		}
+
+		var partitions []string
+
+		if partitions, err = preparePartitionsStatements(dbName, tableName, params); err != nil {
+			return false, lazyerrors.Error(err)
+		}
+
+		stmts := append([]string{q}, partitions...)
+
+		err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
+			for _, stmt := range stmts {
+				if _, err = tx.Exec(ctx, stmt); err != nil {
+					return lazyerrors.Error(err)
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return false, lazyerrors.Error(err)
+		}
 	}
 
 	q = fmt.Sprintf(
@@ -567,6 +658,72 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 	return true, nil
 }
 
+// partitionTableName returns the name of the partition table with the given number.
+func partitionTableName(tableName string, i int) string {
+	return fmt.Sprintf("%s_p%d", tableName, i)
+}
+
+// preparePartitionsStatements returns statements that create partitions of the partitioned table.
+//
+// For hashed partitioning, it creates the requested number of partitions.
+// For ranged partitioning, it creates partitions between given bounds and
+// two partitions for values below the first bound and above the last one.
+func preparePartitionsStatements(dbName, tableName string, params *CollectionCreateParams) ([]string, error) {
+	n := params.partitionsCount()
+	res := make([]string, n)
+
+	// bounds values are constants in DDL statements, so they can't be passed as parameters
+	bounds := make([]string, 0, n+1)
+	bounds = append(bounds, "MINVALUE")
+
+	for _, b := range params.PartitionBounds {
+		v, err := sjson.MarshalSingleValue(b)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		bounds = append(bounds, quoteString(string(v)))
+	}
+
+	bounds = append(bounds, "MAXVALUE")
+
+	for i := range n {
+		q := fmt.Sprintf(
+			`CREATE TABLE %s PARTITION OF %s FOR VALUES `,
+			pgx.Identifier{dbName, partitionTableName(tableName, i)}.Sanitize(),
+			pgx.Identifier{dbName, tableName}.Sanitize(),
+		)
+
+		if params.PartitionHashed {
+			q += fmt.Sprintf(`WITH (MODULUS %d, REMAINDER %d)`, n, i)
+		} else {
+			q += fmt.Sprintf(`FROM (%s) TO (%s)`, bounds[i], bounds[i+1])
+		}
+
+		res[i] = q + prepareStorageClause(params)
+	}
+
+	return res, nil
+}
+
+// prepareStorageClause returns WITH and TABLESPACE clauses of CREATE TABLE statement
+// for the given storage options.
+//
+// Storage options should be already validated.
+func prepareStorageClause(params *CollectionCreateParams) string {
+	var res string
+
+	if params.FillFactor != 0 {
+		res += fmt.Sprintf(` WITH (fillfactor = %d)`, params.FillFactor)
+	}
+
+	if params.Tablespace != "" {
+		res += ` TABLESPACE ` + pgx.Identifier{params.Tablespace}.Sanitize()
+	}
+
+	return res
+}
+
 // CollectionGet returns a copy of collection metadata.
 // It can be safely modified by a caller.
 //
@@ -817,24 +974,28 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 			q += "UNIQUE "
 		}
 
-		q += "INDEX %s ON %s (%s)"
+		q += "INDEX %s ON %s "
+
+		if index.hashOnly() {
+			q += "USING hash "
+		}
+
+		q += "(%s)"
+
+		sparse := index.sparse()
 
 		columns := make([]string, len(index.Key))
+		exists := make([]string, len(index.Key))
 
 		for i, key := range index.Key {
-			// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
-			fs := strings.Split(key.Field, ".")
-			transformedParts := make([]string, len(fs))
-
-			for j, f := range fs {
-				// It's important to sanitize field.Field data here, as it's a user-provided value.
-				transformedParts[j] = quoteString(f)
-			}
+			columns[i] = index.keyColumn(key)
+			exists[i] = fmt.Sprintf("(%s) IS NOT NULL", keyPath(key.Field))
+		}
 
-			columns[i] = fmt.Sprintf("((%s->%s))", DefaultColumn, strings.Join(transformedParts, " -> "))
-			if key.Descending {
-				columns[i] += " DESC"
-			}
+		// unique indexes of partitioned tables must include the partition key,
+		// so uniqueness is enforced for each partition key value separately
+		if index.Unique && c.Partitioned() {
+			columns = append(columns, PartitionKeyColumn)
 		}
 
 		q = fmt.Sprintf(
@@ -844,6 +1005,11 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 			strings.Join(columns, ", "),
 		)
 
+		// sparse indexes contain only documents with at least one of the indexed fields
+		if sparse {
+			q += " WHERE " + strings.Join(exists, " OR ")
+		}
+
 		if _, err = p.Exec(ctx, q); err != nil {
 			_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
 			return lazyerrors.Error(err)
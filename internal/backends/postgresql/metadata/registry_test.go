@@ -714,3 +714,51 @@ func TestLongIndexNames(t *testing.T) {
 		})
 	}
 }
+
+func TestLongCollectionNames(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+	r, db, dbName := createDatabase(t, ctx)
+
+	// subtests are not parallel because metadata is reloaded
+	for name, tc := range map[string]struct {
+		collectionName string
+		tableName      string
+	}{
+		"Long": {
+			collectionName: "Collection" + strings.Repeat("eF", 120),
+			tableName:      "collection" + strings.Repeat("ef", 22) + "_30831561",
+		},
+		"LongSamePrefix": {
+			collectionName: "Collection" + strings.Repeat("eF", 119) + "eG",
+			tableName:      "collection" + strings.Repeat("ef", 22) + "_2f8313ce",
+		},
+		"LongNonLatin": {
+			collectionName: strings.Repeat("コ", 80),
+			tableName:      "__59078845",
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			created, err := r.CollectionCreate(ctx, &CollectionCreateParams{DBName: dbName, Name: tc.collectionName})
+			require.NoError(t, err)
+			require.True(t, created)
+
+			c, err := r.CollectionGet(ctx, dbName, tc.collectionName)
+			require.NoError(t, err)
+			require.NotNil(t, c)
+			assert.Equal(t, tc.collectionName, c.Name)
+			assert.Equal(t, tc.tableName, c.TableName)
+			assert.LessOrEqual(t, len(c.TableName), maxTableNameLength)
+
+			// reload metadata from the database to check that the original name is preserved
+			err = r.initCollections(ctx, dbName, db)
+			require.NoError(t, err)
+
+			list, err := r.CollectionList(ctx, dbName)
+			require.NoError(t, err)
+			assert.Contains(t, list, c)
+		})
+	}
+}
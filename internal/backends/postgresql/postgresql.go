@@ -31,6 +31,10 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
+// countEstimateThreshold is the number of rows in the table starting from which
+// the planner statistics are used instead of counting rows.
+const countEstimateThreshold = 1_000_000
+
 // stats represents information about statistics of tables and indexes.
 type stats struct {
 	countDocuments  int64
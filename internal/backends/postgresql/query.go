@@ -17,6 +17,7 @@ package postgresql
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -51,11 +52,7 @@ func prepareSelectClause(params *selectParams) string {
 		params = new(selectParams)
 	}
 
-	if params.Comment != "" {
-		params.Comment = strings.ReplaceAll(params.Comment, "/*", "/ *")
-		params.Comment = strings.ReplaceAll(params.Comment, "*/", "* /")
-		params.Comment = `/* ` + params.Comment + ` */`
-	}
+	params.Comment = prepareComment(params.Comment)
 
 	if params.Capped && params.OnlyRecordIDs {
 		return fmt.Sprintf(
@@ -84,6 +81,27 @@ func prepareSelectClause(params *selectParams) string {
 	)
 }
 
+// prepareCountClause returns SELECT clause that counts all rows of provided schema and table name.
+func prepareCountClause(schema, table, comment string) string {
+	return fmt.Sprintf(
+		`SELECT %s COUNT(*) FROM %s`,
+		prepareComment(comment),
+		pgx.Identifier{schema, table}.Sanitize(),
+	)
+}
+
+// prepareComment returns SQL comment for the given query comment, or empty string.
+func prepareComment(comment string) string {
+	if comment == "" {
+		return ""
+	}
+
+	comment = strings.ReplaceAll(comment, "/*", "/ *")
+	comment = strings.ReplaceAll(comment, "*/", "* /")
+
+	return `/* ` + comment + ` */`
+}
+
 // prepareWhereClause adds WHERE clause with given filters to the query and returns the query and arguments.
 func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (string, []any, error) {
 	var filters []string
@@ -172,7 +190,7 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 
 					switch v := v.(type) {
 					case *types.Document, *types.Array, types.Binary,
-						types.NullType, types.Regex, types.Timestamp:
+						types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 						// type not supported for pushdown
 
 					case float64, bool, int32, int64:
@@ -212,7 +230,7 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 				}
 			}
 
-		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp:
+		case *types.Array, types.Binary, types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 			// type not supported for pushdown
 
 		case float64, string, types.ObjectID, bool, time.Time, int32, int64:
@@ -234,16 +252,81 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 	return filter, args, nil
 }
 
+// addPartitionClause adds a condition on the partition key column to the given WHERE clause
+// that allows PostgreSQL to prune partitions for equality filters on the partition key.
+// It returns the new WHERE clause and additional arguments.
+//
+// The WHERE clause is returned unchanged if the filter can't be used for pruning.
+func addPartitionClause(p *metadata.Placeholder, where, partitionKey string, sqlFilters *types.Document) (string, []any) {
+	var found int
+
+	for _, k := range sqlFilters.Keys() {
+		if k == partitionKey {
+			found++
+		}
+	}
+
+	// filter could have duplicate keys
+	if found != 1 {
+		return where, nil
+	}
+
+	v, _ := sqlFilters.Get(partitionKey)
+
+	if d, ok := v.(*types.Document); ok && d.Len() == 1 {
+		v, _ = d.Get("$eq")
+	}
+
+	switch v := v.(type) {
+	case float64:
+		// don't prune for values that are not compared precisely, the same way as filterEqual does
+		if v > types.MaxSafeDouble || v < -types.MaxSafeDouble || math.IsNaN(v) {
+			return where, nil
+		}
+
+	case int64:
+		if v > int64(types.MaxSafeDouble) || v < -int64(types.MaxSafeDouble) {
+			return where, nil
+		}
+
+	case string, types.ObjectID, bool, time.Time, int32:
+		// values of those types are stored in the partition key column as is
+
+	default:
+		// missing values, documents, arrays, and values of other types are not used for pruning
+		return where, nil
+	}
+
+	filter := fmt.Sprintf(`%s = %s`, metadata.PartitionKeyColumn, p.Next())
+	args := []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}
+
+	if where == "" {
+		return ` WHERE ` + filter, args
+	}
+
+	return where + ` AND ` + filter, args
+}
+
 // prepareOrderByClause returns ORDER BY clause with arguments for given sort document.
 //
 // The provided sort document should be already validated.
-// Provided document should only contain a single value.
-func prepareOrderByClause(sort *types.Document) (string, []any) {
-	if sort.Len() != 1 {
+// Sort by fields is applied only if one of the given indexes could be used for it;
+// otherwise, an empty clause is returned.
+// $natural sort uses RecordID column for capped collections and physical row location (ctid) otherwise.
+func prepareOrderByClause(sort *types.Document, indexes metadata.Indexes, capped bool) (string, []any) {
+	if sort.Len() == 0 {
+		return "", nil
+	}
+
+	v, _ := sort.Get("$natural")
+	if v == nil {
+		if columns := indexes.SortColumns(sort); columns != nil {
+			return " ORDER BY " + strings.Join(columns, ", "), nil
+		}
+
 		return "", nil
 	}
 
-	v := must.NotFail(sort.Get("$natural"))
 	var order string
 
 	switch v.(int64) {
@@ -255,7 +338,12 @@ func prepareOrderByClause(sort *types.Document) (string, []any) {
 		panic("not reachable")
 	}
 
-	return fmt.Sprintf(" ORDER BY %s%s", metadata.RecordIDColumn, order), nil
+	column := "ctid"
+	if capped {
+		column = metadata.RecordIDColumn
+	}
+
+	return fmt.Sprintf(" ORDER BY %s%s", column, order), nil
 }
 
 // filterEqual returns the proper SQL filter with arguments that filters documents
@@ -266,7 +354,7 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 
 	switch v := v.(type) {
 	case *types.Document, *types.Array, types.Binary,
-		types.NullType, types.Regex, types.Timestamp:
+		types.NullType, types.Regex, types.Timestamp, types.Decimal128:
 		// type not supported for pushdown
 
 	case float64:
@@ -329,3 +417,54 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 
 	return
 }
+
+// prepareGroupCountClause returns SELECT clause that counts rows of provided schema and table name
+// grouped by the value and the sjson schema of the given top-level field, and its arguments.
+//
+// For empty groupBy, it returns SELECT clause that counts all rows.
+func prepareGroupCountClause(p *metadata.Placeholder, schema, table, comment, groupBy string) (string, []any) {
+	if groupBy == "" {
+		return prepareCountClause(schema, table, comment), nil
+	}
+
+	q := fmt.Sprintf(
+		`SELECT %[1]s %[2]s->%[3]s, %[2]s->'$s'->'p'->%[3]s, COUNT(*) FROM %[4]s`,
+		prepareComment(comment),
+		metadata.DefaultColumn,
+		p.Next(),
+		pgx.Identifier{schema, table}.Sanitize(),
+	)
+
+	return q, []any{groupBy}
+}
+
+// prepareGroupCountWhereClause returns WHERE clause and its arguments that apply the given filter exactly.
+//
+// Only empty filters and filters by _id string or ObjectID are supported;
+// false is returned for other filters.
+func prepareGroupCountWhereClause(p *metadata.Placeholder, filter *types.Document) (string, []any, bool) {
+	if filter.Len() == 0 {
+		return "", nil, true
+	}
+
+	if filter.Len() != 1 {
+		return "", nil, false
+	}
+
+	v, _ := filter.Get("_id")
+	switch v.(type) {
+	case string, types.ObjectID:
+	default:
+		return "", nil, false
+	}
+
+	// string and ObjectID values have the same sjson representation, so the type is checked too
+	where := fmt.Sprintf(
+		` WHERE %[1]s->'_id' = %[2]s AND %[1]s->'$s'->'p'->'_id'->'t' = '"%[3]s"'`,
+		metadata.DefaultColumn,
+		p.Next(),
+		sjson.GetTypeOfValue(v),
+	)
+
+	return where, []any{string(must.NotFail(sjson.MarshalSingleValue(v)))}, true
+}
@@ -36,24 +36,27 @@ type queryIterator struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
 	ctx           context.Context
+	tx            pgx.Tx   // protected by m
 	rows          pgx.Rows // protected by m
 	token         *resource.Token
 	m             sync.Mutex
 	onlyRecordIDs bool
 }
 
-// newQueryIterator returns a new queryIterator for the given Rows.
+// newQueryIterator returns a new queryIterator for the given Rows
+// of the query executed in the given read-only transaction.
 //
-// Iterator's Close method closes rows.
+// Iterator's Close method closes rows and rolls back the transaction.
 // They are also closed by the Next method on any error, including context cancellation,
 // to make sure that the database connection is released as early as possible.
 // In that case, the iterator's Close method should still be called.
 //
-// Nil rows are possible and return already done iterator.
+// Nil transaction and rows are possible and return already done iterator.
 // It still should be Closed.
-func newQueryIterator(ctx context.Context, rows pgx.Rows, onlyRecordIDs bool) types.DocumentsIterator {
+func newQueryIterator(ctx context.Context, tx pgx.Tx, rows pgx.Rows, onlyRecordIDs bool) types.DocumentsIterator {
 	iter := &queryIterator{
 		ctx:           ctx,
+		tx:            tx,
 		rows:          rows,
 		onlyRecordIDs: onlyRecordIDs,
 		token:         resource.NewToken(),
@@ -149,6 +152,12 @@ func (iter *queryIterator) close() {
 		iter.rows = nil
 	}
 
+	if iter.tx != nil {
+		// use context without cancellation to release the connection even if the query was canceled
+		_ = iter.tx.Rollback(context.WithoutCancel(iter.ctx))
+		iter.tx = nil
+	}
+
 	resource.Untrack(iter, iter.token)
 }
 
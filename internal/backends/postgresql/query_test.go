@@ -322,56 +322,161 @@ func TestPrepareWhereClause(t *testing.T) {
 	}
 }
 
-func TestPrepareOrderByClause(t *testing.T) {
+func TestAddPartitionClause(t *testing.T) {
 	t.Parallel()
 
+	partitionFilter := ` WHERE ` + metadata.PartitionKeyColumn + ` = $1`
+
 	for name, tc := range map[string]struct { //nolint:vet // used for test only
-		sort *types.Document
-		skip string
+		where  string
+		filter *types.Document
+
+		expected string
+		args     []any
+	}{
+		"NoFilter": {},
+		"OtherKey": {
+			filter: must.NotFail(types.NewDocument("foo", "bar")),
+		},
+		"String": {
+			filter:   must.NotFail(types.NewDocument("v", "foo")),
+			expected: partitionFilter,
+			args:     []any{`"foo"`},
+		},
+		"EqInt32": {
+			filter:   must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$eq", int32(42))))),
+			expected: partitionFilter,
+			args:     []any{`42`},
+		},
+		"AndWhere": {
+			where:    ` WHERE foo`,
+			filter:   must.NotFail(types.NewDocument("v", true)),
+			expected: ` WHERE foo AND ` + metadata.PartitionKeyColumn + ` = $1`,
+			args:     []any{`true`},
+		},
+		"Ne": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewDocument("$ne", int32(42))))),
+		},
+		"Array": {
+			filter: must.NotFail(types.NewDocument("v", must.NotFail(types.NewArray(int32(42))))),
+		},
+		"UnsafeDouble": {
+			filter: must.NotFail(types.NewDocument("v", float64(1<<54))),
+		},
+		"Duplicate": {
+			filter: must.NotFail(types.NewDocument("v", "foo", "v", "bar")),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, args := addPartitionClause(new(metadata.Placeholder), tc.where, "v", tc.filter)
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestPrepareOrderByClause(t *testing.T) {
+	t.Parallel()
+
+	indexes := metadata.Indexes{
+		{
+			Name: "a_1_b_-1",
+			Key:  []metadata.IndexKeyPair{{Field: "a"}, {Field: "b", Descending: true}},
+		},
+		{
+			Name:   "v.foo_1",
+			Key:    []metadata.IndexKeyPair{{Field: "v.foo"}},
+			Unique: true,
+		},
+		{
+			Name:    "s_1",
+			Key:     []metadata.IndexKeyPair{{Field: "s"}},
+			Options: must.NotFail(types.NewDocument("sparse", true)),
+		},
+		{
+			Name: "h_hashed",
+			Key:  []metadata.IndexKeyPair{{Field: "h", Hashed: true}},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		sort   *types.Document
+		capped bool
 
 		orderBy string
 		args    []any
 	}{
-		"Ascending": {
-			skip:    "https://github.com/FerretDB/FerretDB/issues/3181",
+		"SortNil": {
+			orderBy: "",
+			args:    nil,
+		},
+		"NoIndex": {
 			sort:    must.NotFail(types.NewDocument("field", int64(1))),
-			orderBy: ` ORDER BY _jsonb->$1`,
-			args:    []any{"field"},
+			orderBy: "",
 		},
-		"Descending": {
-			skip:    "https://github.com/FerretDB/FerretDB/issues/3181",
-			sort:    must.NotFail(types.NewDocument("field", int64(-1))),
-			orderBy: ` ORDER BY _jsonb->$1 DESC`,
-			args:    []any{"field"},
+		"IndexPrefix": {
+			sort:    must.NotFail(types.NewDocument("a", int64(1))),
+			orderBy: ` ORDER BY ((_jsonb->'a')) NULLS FIRST`,
 		},
-		"SortNil": {
+		"Index": {
+			sort:    must.NotFail(types.NewDocument("a", int64(1), "b", int64(-1))),
+			orderBy: ` ORDER BY ((_jsonb->'a')) NULLS FIRST, ((_jsonb->'b')) DESC NULLS LAST`,
+		},
+		"IndexReversed": {
+			sort:    must.NotFail(types.NewDocument("a", int64(-1), "b", int64(1))),
+			orderBy: ` ORDER BY ((_jsonb->'a')) DESC NULLS LAST, ((_jsonb->'b')) NULLS FIRST`,
+		},
+		"IndexMixed": {
+			sort:    must.NotFail(types.NewDocument("a", int64(1), "b", int64(1))),
 			orderBy: "",
-			args:    nil,
 		},
-		"SortDotNotation": {
-			skip:    "https://github.com/FerretDB/FerretDB/issues/3181",
-			sort:    must.NotFail(types.NewDocument("field.embedded", int64(-1))),
+		"IndexNotPrefix": {
+			sort:    must.NotFail(types.NewDocument("b", int64(-1))),
+			orderBy: "",
+		},
+		"IndexExtension": {
+			sort:    must.NotFail(types.NewDocument("a", int64(1), "b", int64(-1), "c", int64(1))),
+			orderBy: "",
+		},
+		"UniqueDotNotation": {
+			sort:    must.NotFail(types.NewDocument("v.foo", int64(-1))),
+			orderBy: ` ORDER BY (COALESCE(_jsonb->'v' -> 'foo', 'null'::jsonb)) DESC NULLS LAST`,
+		},
+		"Sparse": {
+			sort:    must.NotFail(types.NewDocument("s", int64(1))),
+			orderBy: "",
+		},
+		"Hashed": {
+			sort:    must.NotFail(types.NewDocument("h", int64(1))),
 			orderBy: "",
-			args:    nil,
 		},
 		"NaturalAscending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id`,
 		},
 		"NaturalDescending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id DESC`,
 		},
+		"NaturalAscendingNotCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			orderBy: ` ORDER BY ctid`,
+		},
+		"NaturalDescendingNotCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			orderBy: ` ORDER BY ctid DESC`,
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			if tc.skip != "" {
-				t.Skip(tc.skip)
-			}
-
-			orderBy, args := prepareOrderByClause(tc.sort)
+			orderBy, args := prepareOrderByClause(tc.sort, indexes, tc.capped)
 
 			assert.Equal(t, tc.orderBy, orderBy)
 			assert.Equal(t, tc.args, args)
@@ -0,0 +1,131 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+)
+
+// TLSParams represents TLS parameters for PostgreSQL connections.
+//
+// They take precedence over sslrootcert, sslcert, and sslkey URL parameters.
+// The sslmode URL parameter is only raised by them, never lowered.
+type TLSParams struct {
+	CAFile     string // root CA certificate file used to verify the server certificate
+	CertFile   string // client certificate file
+	KeyFile    string // client private key file
+	VerifyFull bool   // verify that the server host name matches the certificate
+}
+
+// sslModes contains PostgreSQL sslmode values from the least to the most secure.
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// enabled returns true if any TLS parameter is set.
+func (p *TLSParams) enabled() bool {
+	return p != nil && (p.CAFile != "" || p.CertFile != "" || p.KeyFile != "" || p.VerifyFull)
+}
+
+// check verifies that TLS files exist, could be parsed, and are not expired.
+func (p *TLSParams) check() error {
+	if p.CAFile != "" {
+		b, err := os.ReadFile(p.CAFile)
+		if err != nil {
+			return fmt.Errorf("PostgreSQL TLS CA file: %w", err)
+		}
+
+		if !x509.NewCertPool().AppendCertsFromPEM(b) {
+			return fmt.Errorf("PostgreSQL TLS CA file %q: failed to parse", p.CAFile)
+		}
+	}
+
+	if (p.CertFile == "") != (p.KeyFile == "") {
+		return errors.New("PostgreSQL TLS cert and key files should be set together")
+	}
+
+	if p.CertFile == "" {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(p.CertFile, p.KeyFile)
+	if err != nil {
+		return fmt.Errorf("PostgreSQL TLS file pair: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return fmt.Errorf("PostgreSQL TLS cert file: %w", err)
+	}
+
+	if now := time.Now(); now.After(leaf.NotAfter) || now.Before(leaf.NotBefore) {
+		return fmt.Errorf(
+			"PostgreSQL TLS cert file %q: certificate is valid from %s to %s",
+			p.CertFile, leaf.NotBefore.Format(time.RFC3339), leaf.NotAfter.Format(time.RFC3339),
+		)
+	}
+
+	return nil
+}
+
+// apply returns PostgreSQL URL with TLS parameters set.
+func (p *TLSParams) apply(u string) (string, error) {
+	if !p.enabled() {
+		return u, nil
+	}
+
+	uri, err := url.Parse(u)
+	if err != nil {
+		return "", err
+	}
+
+	values := uri.Query()
+
+	var mode string
+
+	switch {
+	case p.VerifyFull:
+		mode = "verify-full"
+	case p.CAFile != "":
+		mode = "verify-ca"
+	default:
+		mode = "require"
+	}
+
+	// keep more secure mode from the URL, for example, verify-full with client certificate flags only
+	if current := values.Get("sslmode"); slices.Index(sslModes, current) > slices.Index(sslModes, mode) {
+		mode = current
+	}
+
+	values.Set("sslmode", mode)
+
+	if p.CAFile != "" {
+		values.Set("sslrootcert", p.CAFile)
+	}
+
+	if p.CertFile != "" {
+		values.Set("sslcert", p.CertFile)
+		values.Set("sslkey", p.KeyFile)
+	}
+
+	uri.RawQuery = values.Encode()
+
+	return uri.String(), nil
+}
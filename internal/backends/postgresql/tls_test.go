@@ -0,0 +1,167 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCert writes self-signed certificate and key files valid until notAfter.
+func writeTestCert(t *testing.T, dir, name string, notAfter time.Time) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notAfter.Add(-2 * time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+"-cert.pem")
+	keyFile = filepath.Join(dir, name+"-key.pem")
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return
+}
+
+func TestTLSParams(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	caFile, _ := writeTestCert(t, dir, "ca", time.Now().Add(time.Hour))
+	certFile, keyFile := writeTestCert(t, dir, "client", time.Now().Add(time.Hour))
+	expiredCertFile, expiredKeyFile := writeTestCert(t, dir, "expired", time.Now().Add(-time.Hour))
+
+	const uri = "postgres://127.0.0.1:5432/ferretdb?sslmode=disable"
+
+	for name, tc := range map[string]struct {
+		params   *TLSParams
+		uri      string // defaults to uri constant
+		expected string
+		err      string
+	}{
+		"Nil": {
+			expected: uri,
+		},
+		"Empty": {
+			params:   new(TLSParams),
+			expected: uri,
+		},
+		"Require": {
+			params: &TLSParams{CertFile: certFile, KeyFile: keyFile},
+			expected: "postgres://127.0.0.1:5432/ferretdb?" +
+				"sslcert=" + url.QueryEscape(certFile) + "&sslkey=" + url.QueryEscape(keyFile) + "&sslmode=require",
+		},
+		"RequireKeepVerifyFull": {
+			params: &TLSParams{CertFile: certFile, KeyFile: keyFile},
+			uri:    "postgres://127.0.0.1:5432/ferretdb?sslmode=verify-full",
+			expected: "postgres://127.0.0.1:5432/ferretdb?" +
+				"sslcert=" + url.QueryEscape(certFile) + "&sslkey=" + url.QueryEscape(keyFile) + "&sslmode=verify-full",
+		},
+		"RequireKeepVerifyCA": {
+			params: &TLSParams{CertFile: certFile, KeyFile: keyFile},
+			uri:    "postgres://127.0.0.1:5432/ferretdb?sslmode=verify-ca",
+			expected: "postgres://127.0.0.1:5432/ferretdb?" +
+				"sslcert=" + url.QueryEscape(certFile) + "&sslkey=" + url.QueryEscape(keyFile) + "&sslmode=verify-ca",
+		},
+		"VerifyCA": {
+			params:   &TLSParams{CAFile: caFile},
+			expected: "postgres://127.0.0.1:5432/ferretdb?sslmode=verify-ca&sslrootcert=" + url.QueryEscape(caFile),
+		},
+		"VerifyCAKeepVerifyFull": {
+			params:   &TLSParams{CAFile: caFile},
+			uri:      "postgres://127.0.0.1:5432/ferretdb?sslmode=verify-full",
+			expected: "postgres://127.0.0.1:5432/ferretdb?sslmode=verify-full&sslrootcert=" + url.QueryEscape(caFile),
+		},
+		"VerifyFull": {
+			params: &TLSParams{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, VerifyFull: true},
+			expected: "postgres://127.0.0.1:5432/ferretdb?" +
+				"sslcert=" + url.QueryEscape(certFile) + "&sslkey=" + url.QueryEscape(keyFile) +
+				"&sslmode=verify-full&sslrootcert=" + url.QueryEscape(caFile),
+		},
+		"NoCAFile": {
+			params: &TLSParams{CAFile: filepath.Join(dir, "missing.pem")},
+			err:    "PostgreSQL TLS CA file: open " + filepath.Join(dir, "missing.pem") + ": no such file or directory",
+		},
+		"InvalidCAFile": {
+			params: &TLSParams{CAFile: keyFile},
+			err:    `PostgreSQL TLS CA file "` + keyFile + `": failed to parse`,
+		},
+		"NoKeyFile": {
+			params: &TLSParams{CertFile: certFile},
+			err:    "PostgreSQL TLS cert and key files should be set together",
+		},
+		"MismatchedKeyFile": {
+			params: &TLSParams{CertFile: certFile, KeyFile: expiredKeyFile},
+			err:    "PostgreSQL TLS file pair: tls: private key does not match public key",
+		},
+		"Expired": {
+			params: &TLSParams{CertFile: expiredCertFile, KeyFile: expiredKeyFile},
+			err:    `PostgreSQL TLS cert file "` + expiredCertFile + `": certificate is valid from`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.params.enabled() {
+				err := tc.params.check()
+				if tc.err != "" {
+					require.ErrorContains(t, err, tc.err)
+					return
+				}
+
+				require.NoError(t, err)
+			}
+
+			u := tc.uri
+			if u == "" {
+				u = uri
+			}
+
+			actual, err := tc.params.apply(u)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
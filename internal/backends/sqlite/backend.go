@@ -17,8 +17,11 @@ package sqlite
 import (
 	"cmp"
 	"context"
+	"fmt"
 	"log/slog"
+	"runtime"
 	"slices"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -92,6 +95,19 @@ func (b *backend) Status(ctx context.Context, params *backends.StatusParams) (*b
 
 // Database implements backends.Backend interface.
 func (b *backend) Database(name string) (backends.Database, error) {
+	// database name is used in the file name and in the SQLite URI
+	invalidChars := "?#%"
+	if runtime.GOOS == "windows" {
+		invalidChars += `*<>:|`
+	}
+
+	if strings.ContainsAny(name, invalidChars) {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseNameIsInvalid,
+			fmt.Errorf("database name %q contains character not supported by SQLite backend", name),
+		)
+	}
+
 	return newDatabase(b.r, name), nil
 }
 
@@ -16,6 +16,7 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"sort"
@@ -54,14 +55,14 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 	db := c.r.DatabaseGetExisting(ctx, c.dbName)
 	if db == nil {
 		return &backends.QueryResult{
-			Iter: newQueryIterator(ctx, nil, params.OnlyRecordIDs),
+			Iter: newQueryIterator(ctx, nil, nil, params.OnlyRecordIDs),
 		}, nil
 	}
 
 	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
 	if meta == nil {
 		return &backends.QueryResult{
-			Iter: newQueryIterator(ctx, nil, params.OnlyRecordIDs),
+			Iter: newQueryIterator(ctx, nil, nil, params.OnlyRecordIDs),
 		}, nil
 	}
 
@@ -82,23 +83,132 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 	}
 
 	q += whereClause
-	q += prepareOrderByClause(params.Sort)
+	q += prepareOrderByClause(params.Sort, meta.Capped())
 
 	if params.Limit != 0 {
 		q += ` LIMIT ?`
 		args = append(args, params.Limit)
 	}
 
-	rows, err := db.QueryContext(ctx, q, args...)
+	// the read transaction is kept open until the iterator is closed,
+	// so it sees a consistent snapshot even if it is consumed over several getMore commands
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	rows, err := tx.QueryContext(ctx, q, args...)
 	if err != nil {
+		_ = tx.Rollback()
 		return nil, lazyerrors.Error(err)
 	}
 
 	return &backends.QueryResult{
-		Iter: newQueryIterator(ctx, rows, params.OnlyRecordIDs),
+		Iter: newQueryIterator(ctx, tx, rows, params.OnlyRecordIDs),
 	}, nil
 }
 
+// Count implements backends.Collection interface.
+func (c *collection) Count(ctx context.Context, params *backends.CountParams) (*backends.CountResult, error) {
+	db := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if db == nil {
+		return new(backends.CountResult), nil
+	}
+
+	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if meta == nil {
+		return new(backends.CountResult), nil
+	}
+
+	var res backends.CountResult
+	if err := db.QueryRowContext(ctx, prepareCountClause(meta.TableName, params.Comment)).Scan(&res.Count); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
+// GroupCount implements backends.Collection interface.
+func (c *collection) GroupCount(ctx context.Context, params *backends.GroupCountParams) (*backends.GroupCountResult, error) {
+	where, whereArgs, ok := prepareGroupCountWhereClause(params.Filter)
+	if !ok {
+		return nil, backends.NewError(
+			backends.ErrorCodeGroupCountNotSupported,
+			lazyerrors.Errorf("filter %v can't be applied exactly", types.FormatAnyValue(params.Filter)),
+		)
+	}
+
+	// double quotes can't be escaped in SQLite JSON path labels
+	if strings.ContainsRune(params.GroupBy, '"') {
+		return nil, backends.NewError(
+			backends.ErrorCodeGroupCountNotSupported,
+			lazyerrors.Errorf("can't group by field %q", params.GroupBy),
+		)
+	}
+
+	db := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if db == nil {
+		return new(backends.GroupCountResult), nil
+	}
+
+	meta := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if meta == nil {
+		return new(backends.GroupCountResult), nil
+	}
+
+	q, args := prepareGroupCountClause(meta.TableName, params.Comment, params.GroupBy)
+	q += where
+	args = append(args, whereArgs...)
+
+	var res backends.GroupCountResult
+
+	if params.GroupBy == "" {
+		var count int64
+		if err := db.QueryRowContext(ctx, q, args...).Scan(&count); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if count > 0 {
+			res.Groups = []backends.GroupCount{{ID: types.Null, Count: count}}
+		}
+
+		return &res, nil
+	}
+
+	q += ` GROUP BY 1, 2`
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value, schema []byte
+		var group backends.GroupCount
+
+		if err = rows.Scan(&value, &schema, &group.Count); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		group.ID = types.Null
+
+		if value != nil {
+			if group.ID, err = sjson.UnmarshalSingleValue(value, schema); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		res.Groups = append(res.Groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &res, nil
+}
+
 // InsertAll implements backends.Collection interface.
 func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
 	if _, err := c.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{DBName: c.dbName, Name: c.name}); err != nil {
@@ -160,6 +270,11 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 
 	q := fmt.Sprintf(`UPDATE %q SET %s = ? WHERE %s = ?`, meta.TableName, metadata.DefaultColumn, metadata.IDColumn)
 
+	if meta.Settings.Versioned {
+		// stored document should not be changed since it was read by the handler
+		q += fmt.Sprintf(` AND COALESCE(%s->'$.%s', '0') = ?`, metadata.DefaultColumn, backends.VersionField)
+	}
+
 	err := db.InTransaction(ctx, func(tx *fsql.Tx) error {
 		for _, doc := range params.Docs {
 			b, err := sjson.Marshal(doc)
@@ -170,9 +285,13 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 			id, _ := doc.Get("_id")
 			must.NotBeZero(id)
 
-			arg := string(must.NotFail(sjson.MarshalSingleValue(id)))
+			args := []any{string(b), string(must.NotFail(sjson.MarshalSingleValue(id)))}
+
+			if meta.Settings.Versioned {
+				args = append(args, string(must.NotFail(sjson.MarshalSingleValue(backends.PreviousVersion(doc)))))
+			}
 
-			r, err := tx.ExecContext(ctx, q, string(b), arg)
+			r, err := tx.ExecContext(ctx, q, args...)
 			if err != nil {
 				return lazyerrors.Error(err)
 			}
@@ -285,10 +404,10 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		}
 	}
 
-	orderByClause := prepareOrderByClause(params.Sort)
+	orderByClause := prepareOrderByClause(params.Sort, meta.Capped())
 	sortPushdown := orderByClause != ""
 
-	q := `EXPLAIN QUERY PLAN ` + selectClause + whereClause + orderByClause
+	q := selectClause + whereClause + orderByClause
 
 	var limitPushdown bool
 
@@ -298,7 +417,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		limitPushdown = true
 	}
 
-	rows, err := db.QueryContext(ctx, q, args...)
+	rows, err := db.QueryContext(ctx, `EXPLAIN QUERY PLAN `+q, args...)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -332,6 +451,8 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		FilterPushdown: filterPushdown,
 		SortPushdown:   sortPushdown,
 		LimitPushdown:  limitPushdown,
+		Query:          q,
+		Args:           args,
 	}, nil
 }
 
@@ -484,6 +605,14 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 			res.Indexes[i].Key[j] = backends.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Hashed:     key.Hashed,
+			}
+		}
+
+		if index.Options != nil {
+			var err error
+			if res.Indexes[i].Options, err = sjson.Unmarshal(index.Options); err != nil {
+				return nil, lazyerrors.Error(err)
 			}
 		}
 	}
@@ -509,6 +638,14 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
+				Hashed:     key.Hashed,
+			}
+		}
+
+		if index.Options != nil {
+			var err error
+			if indexes[i].Options, err = sjson.Marshal(index.Options); err != nil {
+				return nil, lazyerrors.Error(err)
 			}
 		}
 	}
@@ -35,7 +35,7 @@ func newDatabase(r *metadata.Registry, name string) backends.Database {
 	return backends.DatabaseContract(&database{
 		r:    r,
 		name: name,
-	})
+	}, name)
 }
 
 // Collection implements backends.Database interface.
@@ -78,6 +78,7 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 			UUID:            c.Settings.UUID,
 			CappedSize:      c.Settings.CappedSize,
 			CappedDocuments: c.Settings.CappedDocuments,
+			Versioned:       c.Settings.Versioned,
 		}
 	}
 
@@ -88,11 +89,19 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	if params.Partitioned() {
+		return backends.NewError(
+			backends.ErrorCodeCollectionPartitioningNotSupported,
+			lazyerrors.New("partitioned collections are not supported"),
+		)
+	}
+
 	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
 		DBName:          db.name,
 		Name:            params.Name,
 		CappedSize:      params.CappedSize,
 		CappedDocuments: params.CappedDocuments,
+		Versioned:       params.Versioned,
 	})
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -221,6 +221,7 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
+	Versioned       bool
 	_               struct{} // prevent unkeyed literals
 }
 
@@ -310,6 +311,7 @@ func (r *Registry) collectionCreate(ctx context.Context, params *CollectionCreat
 			UUID:            uuid.NewString(),
 			CappedSize:      params.CappedSize,
 			CappedDocuments: params.CappedDocuments,
+			Versioned:       params.Versioned,
 		},
 	}
 
@@ -471,13 +473,14 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 			q += "UNIQUE "
 		}
 
-		q += "INDEX %q ON %q (%s)"
+		q += "INDEX %s ON %q (%s)"
 
+		// SQLite has no hash indexes, so hashed fields are indexed as regular ones
 		columns := make([]string, len(index.Key))
 		for i, key := range index.Key {
 			fields := strings.Split(key.Field, ".")
 			for j, f := range fields {
-				fields[j] = fmt.Sprintf("%q", f)
+				fields[j] = quoteIdentifier(f)
 			}
 
 			columns[i] = fmt.Sprintf("%s->%s", DefaultColumn, strings.Join(fields, "->"))
@@ -488,7 +491,7 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 
 		q = fmt.Sprintf(
 			q,
-			c.TableName+"_"+index.Name,
+			quoteIdentifier(c.TableName+"_"+index.Name),
 			c.TableName,
 			strings.Join(columns, ", "),
 		)
@@ -549,7 +552,7 @@ func (r *Registry) indexesDrop(ctx context.Context, dbName, collectionName strin
 			continue
 		}
 
-		q := fmt.Sprintf("DROP INDEX %q", c.TableName+"_"+name)
+		q := "DROP INDEX " + quoteIdentifier(c.TableName+"_"+name)
 		if _, err := db.ExecContext(ctx, q); err != nil {
 			return lazyerrors.Error(err)
 		}
@@ -567,6 +570,14 @@ func (r *Registry) indexesDrop(ctx context.Context, dbName, collectionName strin
 	return nil
 }
 
+// quoteIdentifier returns SQLite identifier quoted with double quotes.
+//
+// It should be used for user-provided index names and fields where parameters are not allowed;
+// generated table names are safe to format with %q.
+func quoteIdentifier(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
 // Describe implements prometheus.Collector.
 func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(r, ch)
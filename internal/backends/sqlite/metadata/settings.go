@@ -29,19 +29,22 @@ type Settings struct {
 	Indexes         []IndexInfo `json:"indexes"`
 	CappedSize      int64       `json:"cappedSize"`
 	CappedDocuments int64       `json:"cappedDocuments"`
+	Versioned       bool        `json:"versioned,omitempty"`
 }
 
 // IndexInfo represents information about a single index.
 type IndexInfo struct {
-	Name   string         `json:"name"`
-	Key    []IndexKeyPair `json:"key"`
-	Unique bool           `json:"unique"`
+	Name    string          `json:"name"`
+	Key     []IndexKeyPair  `json:"key"`
+	Unique  bool            `json:"unique"`
+	Options json.RawMessage `json:"options,omitempty"` // sjson-encoded document
 }
 
 // IndexKeyPair consists of a field name and a sort order that are part of the index.
 type IndexKeyPair struct {
 	Field      string `json:"field"`
 	Descending bool   `json:"descending"`
+	Hashed     bool   `json:"hashed,omitempty"`
 }
 
 // deepCopy returns a deep copy.
@@ -50,9 +53,10 @@ func (s Settings) deepCopy() Settings {
 
 	for i, index := range s.Indexes {
 		indexes[i] = IndexInfo{
-			Name:   index.Name,
-			Key:    slices.Clone(index.Key),
-			Unique: index.Unique,
+			Name:    index.Name,
+			Key:     slices.Clone(index.Key),
+			Unique:  index.Unique,
+			Options: slices.Clone(index.Options),
 		}
 	}
 
@@ -61,6 +65,7 @@ func (s Settings) deepCopy() Settings {
 		Indexes:         indexes,
 		CappedSize:      s.CappedSize,
 		CappedDocuments: s.CappedDocuments,
+		Versioned:       s.Versioned,
 	}
 }
 
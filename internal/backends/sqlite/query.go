@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -29,11 +30,7 @@ import (
 //
 // For capped collection, it returns select clause for recordID column and default column.
 func prepareSelectClause(table, comment string, capped, onlyRecordIDs bool) string {
-	if comment != "" {
-		comment = strings.ReplaceAll(comment, "/*", "/ *")
-		comment = strings.ReplaceAll(comment, "*/", "* /")
-		comment = `/* ` + comment + ` */`
-	}
+	comment = prepareComment(comment)
 
 	if capped && onlyRecordIDs {
 		return fmt.Sprintf(`SELECT %s %s FROM %q`, comment, metadata.RecordIDColumn, table)
@@ -46,16 +43,34 @@ func prepareSelectClause(table, comment string, capped, onlyRecordIDs bool) stri
 	return fmt.Sprintf(`SELECT %s %s FROM %q`, comment, metadata.DefaultColumn, table)
 }
 
+// prepareCountClause returns SELECT clause that counts all rows of provided table name.
+func prepareCountClause(table, comment string) string {
+	return fmt.Sprintf(`SELECT %s COUNT(*) FROM %q`, prepareComment(comment), table)
+}
+
+// prepareComment returns SQL comment for the given query comment, or empty string.
+func prepareComment(comment string) string {
+	if comment == "" {
+		return ""
+	}
+
+	comment = strings.ReplaceAll(comment, "/*", "/ *")
+	comment = strings.ReplaceAll(comment, "*/", "* /")
+
+	return `/* ` + comment + ` */`
+}
+
 // prepareOrderByClause returns ORDER BY clause for given sort document.
 //
 // The provided sort document should be already validated.
-// Provided document should only contain a single value.
-func prepareOrderByClause(sort *types.Document) string {
-	if sort.Len() != 1 {
+// Sort by fields is not supported; an empty clause is returned for it.
+// $natural sort uses RecordID column for capped collections and implicit rowid otherwise.
+func prepareOrderByClause(sort *types.Document, capped bool) string {
+	v, _ := sort.Get("$natural")
+	if v == nil {
 		return ""
 	}
 
-	v := must.NotFail(sort.Get("$natural"))
 	var order string
 
 	switch v.(int64) {
@@ -67,5 +82,57 @@ func prepareOrderByClause(sort *types.Document) string {
 		panic("not reachable")
 	}
 
-	return fmt.Sprintf(" ORDER BY %s%s", metadata.RecordIDColumn, order)
+	column := "rowid"
+	if capped {
+		column = metadata.RecordIDColumn
+	}
+
+	return fmt.Sprintf(" ORDER BY %s%s", column, order)
+}
+
+// prepareGroupCountWhereClause returns WHERE clause and its arguments that apply the given filter exactly.
+//
+// Only empty filters and filters by _id string or ObjectID are supported;
+// false is returned for other filters.
+func prepareGroupCountWhereClause(filter *types.Document) (string, []any, bool) {
+	if filter.Len() == 0 {
+		return "", nil, true
+	}
+
+	if filter.Len() != 1 {
+		return "", nil, false
+	}
+
+	v, _ := filter.Get("_id")
+	switch v.(type) {
+	case string, types.ObjectID:
+	default:
+		return "", nil, false
+	}
+
+	// string and ObjectID values have the same sjson representation, so the type is checked too
+	where := fmt.Sprintf(` WHERE %s = ? AND %s->'$."$s".p._id.t' = ?`, metadata.IDColumn, metadata.DefaultColumn)
+	args := []any{
+		string(must.NotFail(sjson.MarshalSingleValue(v))),
+		`"` + sjson.GetTypeOfValue(v) + `"`,
+	}
+
+	return where, args, true
+}
+
+// prepareGroupCountClause returns SELECT clause that counts rows of provided table name
+// grouped by the value and the sjson schema of the given top-level field, and its arguments.
+//
+// For empty groupBy, it returns SELECT clause that counts all rows.
+func prepareGroupCountClause(table, comment, groupBy string) (string, []any) {
+	comment = prepareComment(comment)
+
+	if groupBy == "" {
+		return fmt.Sprintf(`SELECT %s COUNT(*) FROM %q`, comment, table), nil
+	}
+
+	q := fmt.Sprintf(`SELECT %[1]s %[2]s->?, %[2]s->?, COUNT(*) FROM %[3]q`, comment, metadata.DefaultColumn, table)
+	args := []any{`$."` + groupBy + `"`, `$."$s".p."` + groupBy + `"`}
+
+	return q, args
 }
@@ -35,24 +35,27 @@ type queryIterator struct {
 	// the order of fields is weird to make the struct smaller due to alignment
 
 	ctx           context.Context
+	tx            *fsql.Tx   // protected by m
 	rows          *fsql.Rows // protected by m
 	token         *resource.Token
 	m             sync.Mutex
 	onlyRecordIDs bool
 }
 
-// newQueryIterator returns a new queryIterator for the given *sql.Rows.
+// newQueryIterator returns a new queryIterator for the given *sql.Rows
+// of the query executed in the given read-only transaction.
 //
-// Iterator's Close method closes rows.
+// Iterator's Close method closes rows and rolls back the transaction.
 // They are also closed by the Next method on any error, including context cancellation,
 // to make sure that the database connection is released as early as possible.
 // In that case, the iterator's Close method should still be called.
 //
-// Nil rows are possible and return already done iterator.
+// Nil transaction and rows are possible and return already done iterator.
 // It still should be Close'd.
-func newQueryIterator(ctx context.Context, rows *fsql.Rows, onlyRecordIDs bool) types.DocumentsIterator {
+func newQueryIterator(ctx context.Context, tx *fsql.Tx, rows *fsql.Rows, onlyRecordIDs bool) types.DocumentsIterator {
 	iter := &queryIterator{
 		ctx:           ctx,
+		tx:            tx,
 		rows:          rows,
 		onlyRecordIDs: onlyRecordIDs,
 		token:         resource.NewToken(),
@@ -148,6 +151,11 @@ func (iter *queryIterator) close() {
 		iter.rows = nil
 	}
 
+	if iter.tx != nil {
+		_ = iter.tx.Rollback()
+		iter.tx = nil
+	}
+
 	resource.Untrack(iter, iter.token)
 }
 
@@ -80,6 +80,7 @@ func TestPrepareOrderByClause(t *testing.T) {
 
 	for name, tc := range map[string]struct { //nolint:vet // used for test only
 		sort    *types.Document
+		capped  bool
 		skip    string
 		orderBy string
 	}{
@@ -98,12 +99,22 @@ func TestPrepareOrderByClause(t *testing.T) {
 		},
 		"NaturalAscending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id`,
 		},
 		"NaturalDescending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id DESC`,
 		},
+		"NaturalAscendingNotCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			orderBy: ` ORDER BY rowid`,
+		},
+		"NaturalDescendingNotCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			orderBy: ` ORDER BY rowid DESC`,
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -113,7 +124,7 @@ func TestPrepareOrderByClause(t *testing.T) {
 				t.Skip(tc.skip)
 			}
 
-			orderBy := prepareOrderByClause(tc.sort)
+			orderBy := prepareOrderByClause(tc.sort, tc.capped)
 
 			assert.Equal(t, tc.orderBy, orderBy)
 		})
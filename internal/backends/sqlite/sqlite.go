@@ -61,16 +61,14 @@ func collectionsStats(ctx context.Context, db *fsql.DB, list []*metadata.Collect
 	var err error
 
 	if refresh {
-		queries := make([]string, len(list))
-		for i, c := range list {
-			queries[i] = fmt.Sprintf("ANALYZE %q;", c.TableName)
-		}
-
 		// SQLite ANALYZE does not allow multiple tables as arguments, hence
-		// build multiple queries such as `ANALYZE 'table1'; ANALYZE 'table2';`.
-		q := strings.Join(queries, "")
-		if _, err = db.ExecContext(ctx, q); err != nil {
-			return nil, lazyerrors.Error(err)
+		// run a separate query for each table; multiple statements in a single query
+		// are rejected by sqlguard in debug builds.
+		for _, c := range list {
+			q := fmt.Sprintf("ANALYZE %q", c.TableName)
+			if _, err = db.ExecContext(ctx, q); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
 		}
 	}
 
@@ -15,16 +15,22 @@
 package backends
 
 import (
-	"regexp"
+	"errors"
+	"fmt"
 	"strings"
 	"unicode/utf8"
 )
 
-// databaseNameRe validates database name.
-var databaseNameRe = regexp.MustCompile("^[a-zA-Z0-9_-]{1,63}$")
+const (
+	// maxDatabaseNameLength is the maximum length of the database name in bytes.
+	maxDatabaseNameLength = 63
 
-// collectionNameRe validates collection names.
-var collectionNameRe = regexp.MustCompile("^[^\\.$\x00][^$\x00]{0,234}$")
+	// maxNamespaceLength is the maximum length of the fully qualified `database.collection` namespace in bytes.
+	maxNamespaceLength = 255
+)
+
+// databaseNameInvalidChars contains characters that are not allowed in database names.
+const databaseNameInvalidChars = "/\\. \"$\x00"
 
 // ReservedPrefix for names: databases, collections, schemas, tables, indexes, columns, etc.
 const ReservedPrefix = "_ferretdb_"
@@ -32,29 +38,39 @@ const ReservedPrefix = "_ferretdb_"
 // validateDatabaseName checks that database name is valid for FerretDB.
 //
 // It follows MongoDB restrictions plus
-//   - allows only basic latin letters, digits, and basic punctuation;
+//   - allows only valid UTF-8 strings;
 //   - disallows `_ferretdb_` prefix.
 //
-// That validation is quite restrictive because
-// we expect it to be easy for users to change database names in their software/configuration if needed.
-//
-// Backends can do their own additional validation.
+// Backends can do their own additional validation for their identifier limits
+// and return ErrorCodeDatabaseNameIsInvalid from [Backend.Database].
 func validateDatabaseName(name string) error {
-	if !databaseNameRe.MatchString(name) {
-		return NewError(ErrorCodeDatabaseNameIsInvalid, nil)
-	}
+	switch {
+	case name == "":
+		return NewError(ErrorCodeDatabaseNameIsInvalid, errors.New("database name is empty"))
+
+	case len(name) > maxDatabaseNameLength:
+		return NewError(
+			ErrorCodeDatabaseNameIsInvalid,
+			fmt.Errorf("database name must be at most %d bytes, found: %d", maxDatabaseNameLength, len(name)),
+		)
 
-	if strings.HasPrefix(name, ReservedPrefix) {
-		return NewError(ErrorCodeDatabaseNameIsInvalid, nil)
+	case !utf8.ValidString(name):
+		return NewError(ErrorCodeDatabaseNameIsInvalid, errors.New("database name is not a valid UTF-8 string"))
+
+	case strings.ContainsAny(name, databaseNameInvalidChars):
+		return NewError(ErrorCodeDatabaseNameIsInvalid, fmt.Errorf("database name %q contains invalid character", name))
+
+	case strings.HasPrefix(name, ReservedPrefix):
+		return NewError(ErrorCodeDatabaseNameIsInvalid, fmt.Errorf("database name %q has reserved prefix", name))
 	}
 
 	return nil
 }
 
-// validateCollectionName checks that collection name is valid for FerretDB.
+// validateCollectionName checks that collection name in the given database is valid for FerretDB.
 //
 // It follows MongoDB restrictions plus:
-//   - allows only UTF-8 characters;
+//   - allows only valid UTF-8 strings;
 //   - allows `system.` prefix ("system" collections are just regular collections);
 //   - disallows `.` prefix (MongoDB fails to work with such collections correctly too);
 //   - disallows `_ferretdb_` prefix.
@@ -63,17 +79,25 @@ func validateDatabaseName(name string) error {
 // we expect it to be hard for users to change collection names in their software.
 //
 // Backends can do their own additional validation.
-func validateCollectionName(name string) error {
-	if !collectionNameRe.MatchString(name) {
-		return NewError(ErrorCodeCollectionNameIsInvalid, nil)
-	}
+func validateCollectionName(dbName, name string) error {
+	switch {
+	case name == "":
+		return NewError(ErrorCodeCollectionNameIsInvalid, errors.New("collection name is empty"))
 
-	if strings.HasPrefix(name, ReservedPrefix) {
-		return NewError(ErrorCodeCollectionNameIsInvalid, nil)
-	}
+	case len(dbName)+1+len(name) > maxNamespaceLength:
+		return NewError(
+			ErrorCodeCollectionNameIsInvalid,
+			fmt.Errorf("fully qualified namespace must be at most %d bytes, found: %d", maxNamespaceLength, len(dbName)+1+len(name)),
+		)
+
+	case !utf8.ValidString(name):
+		return NewError(ErrorCodeCollectionNameIsInvalid, errors.New("collection name is not a valid UTF-8 string"))
+
+	case strings.HasPrefix(name, "."), strings.ContainsAny(name, "$\x00"):
+		return NewError(ErrorCodeCollectionNameIsInvalid, fmt.Errorf("collection name %q contains invalid character", name))
 
-	if !utf8.ValidString(name) {
-		return NewError(ErrorCodeCollectionNameIsInvalid, nil)
+	case strings.HasPrefix(name, ReservedPrefix):
+		return NewError(ErrorCodeCollectionNameIsInvalid, fmt.Errorf("collection name %q has reserved prefix", name))
 	}
 
 	return nil
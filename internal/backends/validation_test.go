@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backends
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDatabaseName(t *testing.T) {
+	t.Parallel()
+
+	for name, valid := range map[string]bool{
+		"db":                    true,
+		"データベース":                true,
+		"+-*<>=~!@^&|`()[],;:":  true,
+		strings.Repeat("a", 63): true,
+		strings.Repeat("б", 31): true,
+
+		"":                      false,
+		strings.Repeat("a", 64): false,
+		strings.Repeat("б", 32): false,
+		"a/b":                   false,
+		`a\b`:                   false,
+		"a.b":                   false,
+		"a b":                   false,
+		`a"b`:                   false,
+		"a$b":                   false,
+		"a\x00b":                false,
+		"a\xffb":                false,
+		"_ferretdb_db":          false,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateDatabaseName(name)
+			if valid {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, ErrorCodeIs(err, ErrorCodeDatabaseNameIsInvalid), "%v", err)
+		})
+	}
+}
+
+func TestValidateCollectionName(t *testing.T) {
+	t.Parallel()
+
+	for name, valid := range map[string]bool{
+		"coll":                   true,
+		"system.coll":            true,
+		"a.b":                    true,
+		"コレクション":                 true,
+		strings.Repeat("a", 252): true,
+		strings.Repeat("б", 126): true,
+
+		"":                       false,
+		strings.Repeat("a", 253): false,
+		strings.Repeat("б", 127): false,
+		".coll":                  false,
+		"a$b":                    false,
+		"a\x00b":                 false,
+		"a\xffb":                 false,
+		"_ferretdb_coll":         false,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// "db." takes 3 bytes of the namespace
+			err := validateCollectionName("db", name)
+			if valid {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, ErrorCodeIs(err, ErrorCodeCollectionNameIsInvalid), "%v", err)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// ChaosOpts represents options for chaos injection into responses.
+//
+// It is used only for testing drivers' retry and timeout behavior.
+// Zero value disables chaos injection.
+type ChaosOpts struct {
+	// DelayProbability is a probability of delaying a response by a random duration up to MaxDelay.
+	DelayProbability float64
+	MaxDelay         time.Duration
+
+	// TruncateProbability is a probability of sending a truncated response and closing the connection.
+	TruncateProbability float64
+
+	// DropProbability is a probability of not sending a response at all.
+	DropProbability float64
+}
+
+// chaosAction represents a chaos action for a single response.
+type chaosAction int
+
+const (
+	chaosNone chaosAction = iota
+	chaosDrop
+	chaosTruncate
+)
+
+// Validate checks that probabilities are in the [0, 1] range.
+func (opts *ChaosOpts) Validate() error {
+	for name, p := range map[string]float64{
+		"delay":    opts.DelayProbability,
+		"truncate": opts.TruncateProbability,
+		"drop":     opts.DropProbability,
+	} {
+		if p < 0 || p > 1 {
+			return fmt.Errorf("%s probability should be in the [0, 1] range, got %v", name, p)
+		}
+	}
+
+	if opts.DelayProbability > 0 && opts.MaxDelay <= 0 {
+		return fmt.Errorf("max delay should be positive if delay probability is set")
+	}
+
+	return nil
+}
+
+// enabled returns true if any chaos should be injected.
+func (opts *ChaosOpts) enabled() bool {
+	return opts != nil && (opts.DelayProbability > 0 || opts.TruncateProbability > 0 || opts.DropProbability > 0)
+}
+
+// action returns a random chaos action for the next response.
+//
+// Drop takes precedence over truncation.
+func (opts *ChaosOpts) action(r *rand.Rand) chaosAction {
+	switch {
+	case r.Float64() < opts.DropProbability:
+		return chaosDrop
+	case r.Float64() < opts.TruncateProbability:
+		return chaosTruncate
+	default:
+		return chaosNone
+	}
+}
+
+// delay returns a random delay for the next response; it may be zero.
+func (opts *ChaosOpts) delay(r *rand.Rand) time.Duration {
+	if opts.MaxDelay <= 0 || r.Float64() >= opts.DelayProbability {
+		return 0
+	}
+
+	return time.Duration(r.Int64N(int64(opts.MaxDelay)) + 1)
+}
+
+// write writes the response to w after an optional delay, applying a random chaos action.
+//
+// If returned action is chaosTruncate, the connection should be closed after flushing w.
+func (opts *ChaosOpts) write(ctx context.Context, r *rand.Rand, w *bufio.Writer, header *wire.MsgHeader, body wire.MsgBody) (chaosAction, error) { //nolint:lll // for readability
+	ctxutil.Sleep(ctx, opts.delay(r))
+
+	action := opts.action(r)
+
+	switch action {
+	case chaosDrop:
+		return action, nil
+
+	case chaosTruncate:
+		var buf bytes.Buffer
+
+		bufw := bufio.NewWriter(&buf)
+		if err := wire.WriteMessage(bufw, header, body); err != nil {
+			return action, lazyerrors.Error(err)
+		}
+
+		if err := bufw.Flush(); err != nil {
+			return action, lazyerrors.Error(err)
+		}
+
+		// write at least one byte and never the whole message
+		b := buf.Bytes()
+		if _, err := w.Write(b[:1+r.IntN(len(b)-1)]); err != nil {
+			return action, lazyerrors.Error(err)
+		}
+
+		return action, nil
+
+	default:
+		if err := wire.WriteMessage(w, header, body); err != nil {
+			return action, lazyerrors.Error(err)
+		}
+
+		return action, nil
+	}
+}
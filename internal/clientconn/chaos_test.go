@@ -0,0 +1,104 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"bufio"
+	"bytes"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestChaosValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, new(ChaosOpts).Validate())
+	assert.NoError(t, (&ChaosOpts{DelayProbability: 1, MaxDelay: time.Second, DropProbability: 0.5}).Validate())
+
+	assert.Error(t, (&ChaosOpts{DropProbability: 1.5}).Validate())
+	assert.Error(t, (&ChaosOpts{TruncateProbability: -0.1}).Validate())
+	assert.Error(t, (&ChaosOpts{DelayProbability: 0.5}).Validate())
+}
+
+func TestChaosWrite(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	msg := wire.MustOpMsg("ok", float64(1))
+	b, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		RequestID:     1,
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	var expected bytes.Buffer
+	expectedW := bufio.NewWriter(&expected)
+	require.NoError(t, wire.WriteMessage(expectedW, header, msg))
+	require.NoError(t, expectedW.Flush())
+
+	for name, tc := range map[string]struct {
+		opts   *ChaosOpts
+		action chaosAction
+	}{
+		"None": {
+			opts:   &ChaosOpts{DelayProbability: 1, MaxDelay: time.Millisecond},
+			action: chaosNone,
+		},
+		"Drop": {
+			opts:   &ChaosOpts{DropProbability: 1, TruncateProbability: 1},
+			action: chaosDrop,
+		},
+		"Truncate": {
+			opts:   &ChaosOpts{TruncateProbability: 1},
+			action: chaosTruncate,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := rand.New(rand.NewPCG(1, 2))
+
+			var buf bytes.Buffer
+			w := bufio.NewWriter(&buf)
+
+			action, err := tc.opts.write(ctx, r, w, header, msg)
+			require.NoError(t, err)
+			require.NoError(t, w.Flush())
+			assert.Equal(t, tc.action, action)
+
+			switch action {
+			case chaosNone:
+				assert.Equal(t, expected.Bytes(), buf.Bytes())
+			case chaosDrop:
+				assert.Empty(t, buf.Bytes())
+			case chaosTruncate:
+				assert.NotEmpty(t, buf.Bytes())
+				assert.Less(t, buf.Len(), expected.Len())
+				assert.Equal(t, expected.Bytes()[:buf.Len()], buf.Bytes())
+			}
+		})
+	}
+}
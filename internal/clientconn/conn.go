@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math/rand/v2"
 	"net"
 	"net/netip"
 	"os"
@@ -66,6 +67,9 @@ const (
 	// DiffProxyMode both handles requests and proxies them, then logs the diff.
 	// Only the proxy response is sent to the client.
 	DiffProxyMode Mode = "diff-proxy"
+	// ShadowMode handles requests, then asynchronously mirrors them to the proxy and counts mismatched responses.
+	// Only the FerretDB response is sent to the client, without waiting for the proxy.
+	ShadowMode Mode = "shadow"
 )
 
 // AllModes includes all operation modes, with the first one being the default.
@@ -74,6 +78,7 @@ var AllModes = []string{
 	string(ProxyMode),
 	string(DiffNormalMode),
 	string(DiffProxyMode),
+	string(ShadowMode),
 }
 
 // conn represents client connection.
@@ -86,6 +91,9 @@ type conn struct {
 	proxy          *proxy.Router
 	lastRequestID  atomic.Int32
 	testRecordsDir string // if empty, no records are created
+	chaos          *ChaosOpts
+	chaosRand      *rand.Rand
+	shadow         *shadow // only in shadow mode
 }
 
 // newConnOpts represents newConn options.
@@ -102,6 +110,7 @@ type newConnOpts struct {
 	proxyTLSCAFile   string
 
 	testRecordsDir string // if empty, no records are created
+	chaos          *ChaosOpts
 }
 
 // newConn creates a new client connection for given net.Conn.
@@ -114,14 +123,27 @@ func newConn(opts *newConnOpts) (*conn, error) {
 	}
 
 	var p *proxy.Router
-	if opts.mode != NormalMode {
+	var s *shadow
+
+	switch opts.mode {
+	case NormalMode:
+	case ShadowMode:
+		// shadow target's failures should not affect clients
+		router, err := proxy.New(opts.proxyAddr, opts.proxyTLSCertFile, opts.proxyTLSKeyFile, opts.proxyTLSCAFile)
+		if err != nil {
+			opts.l.Warn("Failed to connect to shadow target", logging.Error(err))
+			opts.connMetrics.Shadow.WithLabelValues("", shadowError).Inc()
+		}
+
+		s = newShadow(router, opts.connMetrics.Shadow, opts.l)
+	default:
 		var err error
 		if p, err = proxy.New(opts.proxyAddr, opts.proxyTLSCertFile, opts.proxyTLSKeyFile, opts.proxyTLSCAFile); err != nil {
 			return nil, lazyerrors.Error(err)
 		}
 	}
 
-	return &conn{
+	c := &conn{
 		netConn:        opts.netConn,
 		mode:           opts.mode,
 		l:              opts.l,
@@ -129,7 +151,15 @@ func newConn(opts *newConnOpts) (*conn, error) {
 		m:              opts.connMetrics,
 		proxy:          p,
 		testRecordsDir: opts.testRecordsDir,
-	}, nil
+		shadow:         s,
+	}
+
+	if opts.chaos.enabled() {
+		c.chaos = opts.chaos
+		c.chaosRand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+
+	return c, nil
 }
 
 // run runs the client connection until ctx is canceled, client disconnects,
@@ -243,6 +273,10 @@ func (c *conn) run(ctx context.Context) (err error) {
 			c.proxy.Close()
 		}
 
+		if c.shadow != nil {
+			c.shadow.close()
+		}
+
 		// c.netConn is closed by the caller
 	}()
 
@@ -270,9 +304,15 @@ func (c *conn) run(ctx context.Context) (err error) {
 		// send request to proxy first (unless we are in normal mode)
 		// because FerretDB's handling could modify reqBody's documents,
 		// creating a data race
+		// marshal request for shadow target before FerretDB's handling could modify it
+		var shadowReq []byte
+		if c.shadow != nil {
+			shadowReq, _ = reqBody.MarshalBinary()
+		}
+
 		var proxyHeader *wire.MsgHeader
 		var proxyBody wire.MsgBody
-		if c.mode != NormalMode {
+		if c.mode != NormalMode && c.mode != ShadowMode {
 			if c.proxy == nil {
 				panic("proxy addr was nil")
 			}
@@ -290,7 +330,7 @@ func (c *conn) run(ctx context.Context) (err error) {
 		}
 
 		// log proxy response after the normal response to make it less confusing
-		if c.mode != NormalMode {
+		if c.mode != NormalMode && c.mode != ShadowMode {
 			if level := c.logResponse(ctx, "Proxy response", proxyHeader, proxyBody, false); level > diffLogLevel {
 				diffLogLevel = level
 			}
@@ -348,14 +388,34 @@ func (c *conn) run(ctx context.Context) (err error) {
 			panic("no response to send to client")
 		}
 
-		if err = wire.WriteMessage(bufw, resHeader, resBody); err != nil {
-			return
+		if c.chaos == nil {
+			if err = wire.WriteMessage(bufw, resHeader, resBody); err != nil {
+				return
+			}
+		} else {
+			var action chaosAction
+			if action, err = c.chaos.write(ctx, c.chaosRand, bufw, resHeader, resBody); err != nil {
+				return
+			}
+
+			switch action {
+			case chaosDrop:
+				c.l.WarnContext(ctx, "Chaos: response dropped", slog.Int("request_id", int(reqHeader.RequestID)))
+			case chaosTruncate:
+				c.l.WarnContext(ctx, "Chaos: response truncated", slog.Int("request_id", int(reqHeader.RequestID)))
+				resCloseConn = true
+			case chaosNone:
+			}
 		}
 
 		if err = bufw.Flush(); err != nil {
 			return
 		}
 
+		if shadowReq != nil {
+			c.shadow.send(&shadowRequest{header: reqHeader, body: shadowReq, res: resBody})
+		}
+
 		if resCloseConn {
 			err = errors.New("fatal error")
 			return
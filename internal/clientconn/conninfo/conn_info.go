@@ -41,6 +41,10 @@ type ConnInfo struct {
 	username string // protected by rw
 	password string // protected by rw
 
+	appName       string // protected by rw
+	driverName    string // protected by rw
+	driverVersion string // protected by rw
+
 	rw sync.RWMutex
 
 	metadataRecv bool // protected by rw
@@ -101,6 +105,25 @@ func (connInfo *ConnInfo) SetMetadataRecv() {
 	connInfo.metadataRecv = true
 }
 
+// ClientMetadata returns stored application name, driver name and version
+// from the client metadata sent in the first hello command.
+func (connInfo *ConnInfo) ClientMetadata() (appName, driverName, driverVersion string) {
+	connInfo.rw.RLock()
+	defer connInfo.rw.RUnlock()
+
+	return connInfo.appName, connInfo.driverName, connInfo.driverVersion
+}
+
+// SetClientMetadata stores application name, driver name and version from the client metadata.
+func (connInfo *ConnInfo) SetClientMetadata(appName, driverName, driverVersion string) {
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
+
+	connInfo.appName = appName
+	connInfo.driverName = driverName
+	connInfo.driverVersion = driverVersion
+}
+
 // SetBypassBackendAuth marks the connection as not requiring backend authentication.
 func (connInfo *ConnInfo) SetBypassBackendAuth() {
 	connInfo.rw.Lock()
@@ -117,6 +140,15 @@ func (connInfo *ConnInfo) BypassBackendAuth() bool {
 	return connInfo.bypassBackendAuth
 }
 
+// LocalPeer returns true if the client is connected over a Unix socket or from the loopback address.
+func (connInfo *ConnInfo) LocalPeer() bool {
+	if !connInfo.Peer.IsValid() {
+		return true
+	}
+
+	return connInfo.Peer.Addr().Unmap().IsLoopback()
+}
+
 // Ctx returns a derived context with the given ConnInfo.
 func Ctx(ctx context.Context, connInfo *ConnInfo) context.Context {
 	return context.WithValue(ctx, connInfoKey, connInfo)
@@ -25,9 +25,13 @@ import (
 )
 
 // ConnMetrics represents metrics of an individual conn or a collection of conns.
+//
+// Counters updated for every request do not take locks; see [CounterVec].
 type ConnMetrics struct {
-	Requests  *prometheus.CounterVec
-	Responses *prometheus.CounterVec
+	Requests  *CounterVec
+	Responses *CounterVec
+	Drivers   *prometheus.CounterVec
+	Shadow    *CounterVec
 }
 
 // commandMetrics represents command results metrics.
@@ -39,7 +43,7 @@ type commandMetrics struct {
 // newConnMetrics creates connection metrics.
 func newConnMetrics() *ConnMetrics {
 	return &ConnMetrics{
-		Requests: prometheus.NewCounterVec(
+		Requests: NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Subsystem: subsystem,
@@ -48,7 +52,7 @@ func newConnMetrics() *ConnMetrics {
 			},
 			[]string{"opcode", "command"},
 		),
-		Responses: prometheus.NewCounterVec(
+		Responses: NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
 				Subsystem: subsystem,
@@ -57,6 +61,24 @@ func newConnMetrics() *ConnMetrics {
 			},
 			[]string{"opcode", "command", "argument", "result"},
 		),
+		Drivers: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "drivers_total",
+				Help:      "Total number of client handshakes by driver name and version.",
+			},
+			[]string{"driver", "version"},
+		),
+		Shadow: NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "shadow_responses_total",
+				Help:      "Total number of requests mirrored to the shadow target by comparison result.",
+			},
+			[]string{"command", "result"},
+		),
 	}
 }
 
@@ -64,12 +86,16 @@ func newConnMetrics() *ConnMetrics {
 func (cm *ConnMetrics) Describe(ch chan<- *prometheus.Desc) {
 	cm.Requests.Describe(ch)
 	cm.Responses.Describe(ch)
+	cm.Drivers.Describe(ch)
+	cm.Shadow.Describe(ch)
 }
 
 // Collect implements [prometheus.Collector].
 func (cm *ConnMetrics) Collect(ch chan<- prometheus.Metric) {
 	cm.Requests.Collect(ch)
 	cm.Responses.Collect(ch)
+	cm.Drivers.Collect(ch)
+	cm.Shadow.Collect(ch)
 }
 
 // GetResponses returns a map with all response metrics:
@@ -138,6 +164,46 @@ func (cm *ConnMetrics) GetResponses() map[string]map[string]map[string]commandMe
 	return res
 }
 
+// GetDrivers returns a map with all driver metrics:
+//
+// driver name (e.g. "mongo-go-driver", "nodejs") ->
+// driver version (e.g. "1.14.0") ->
+// count.
+func (cm *ConnMetrics) GetDrivers() map[string]map[string]int {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		cm.Drivers.Collect(metrics)
+		close(metrics)
+	}()
+
+	res := map[string]map[string]int{}
+
+	for m := range metrics {
+		var content dto.Metric
+		must.NoError(m.Write(&content))
+
+		var driver, version string
+		for _, label := range content.GetLabel() {
+			switch label.GetName() {
+			case "driver":
+				driver = label.GetValue()
+			case "version":
+				version = label.GetValue()
+			default:
+				panic(fmt.Sprintf("%s is not a valid label. Allowed: [driver, version]", label.GetName()))
+			}
+		}
+
+		if _, ok := res[driver]; !ok {
+			res[driver] = map[string]int{}
+		}
+
+		res[driver][version] += int(content.GetCounter().GetValue())
+	}
+
+	return res
+}
+
 // check interfaces
 var (
 	_ prometheus.Collector = (*ConnMetrics)(nil)
@@ -38,3 +38,19 @@ func TestGetResponses(t *testing.T) {
 	}
 	assert.Equal(t, expected, m.GetResponses())
 }
+
+func TestGetDrivers(t *testing.T) {
+	m := newConnMetrics()
+	m.Drivers.WithLabelValues("mongo-go-driver", "1.14.0").Inc()
+	m.Drivers.WithLabelValues("mongo-go-driver", "1.14.0").Inc()
+	m.Drivers.WithLabelValues("nodejs", "6.3.0").Inc()
+	expected := map[string]map[string]int{
+		"mongo-go-driver": {
+			"1.14.0": 2,
+		},
+		"nodejs": {
+			"6.3.0": 1,
+		},
+	}
+	assert.Equal(t, expected, m.GetDrivers())
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connmetrics
+
+import (
+	"fmt"
+	"maps"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// cacheLineSize is the assumed size of CPU cache line.
+const cacheLineSize = 64
+
+// maxShards limits memory used by each counter on machines with many CPUs.
+const maxShards = 16
+
+// FNV-1a parameters used for hashing label values.
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// shard is a part of a counter value padded to occupy the whole cache line.
+type shard struct {
+	v atomic.Uint64
+	_ [cacheLineSize - 8]byte
+}
+
+// Counter is a monotonically increasing integer counter without locks.
+//
+// Increments are spread over padded shards to avoid cache line contention
+// between goroutines running on different CPUs.
+type Counter struct {
+	labelValues []string
+	shards      []shard
+}
+
+// Inc increments the counter by 1.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increments the counter by the given value.
+func (c *Counter) Add(v uint64) {
+	// per-thread random source is cheap and does not need synchronization
+	c.shards[rand.Uint32()&uint32(len(c.shards)-1)].v.Add(v)
+}
+
+// Value returns the current counter value.
+func (c *Counter) Value() uint64 {
+	var res uint64
+	for i := range c.shards {
+		res += c.shards[i].v.Load()
+	}
+
+	return res
+}
+
+// CounterVec is a lock-free alternative to [prometheus.CounterVec] for counters updated on hot paths.
+//
+// Once some combination of label values was seen, the lookup of its counter does not take locks
+// and does not allocate memory.
+//
+//nolint:vet // for readability
+type CounterVec struct {
+	desc      *prometheus.Desc
+	labelsLen int
+	shardsLen int
+
+	// copy-on-write map of label values hashes to counters;
+	// the mutex is held only while new label values combination is added
+	counters   atomic.Pointer[map[uint64][]*Counter]
+	countersMu sync.Mutex
+}
+
+// NewCounterVec creates a new counter vector with the given options and label names.
+func NewCounterVec(opts prometheus.CounterOpts, labelNames []string) *CounterVec {
+	fqName := prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name)
+
+	// the number of shards should be a power of two
+	shardsLen := min(1<<bits.Len(uint(runtime.GOMAXPROCS(0)-1)), maxShards)
+
+	cv := &CounterVec{
+		desc:      prometheus.NewDesc(fqName, opts.Help, labelNames, opts.ConstLabels),
+		labelsLen: len(labelNames),
+		shardsLen: shardsLen,
+	}
+
+	cv.counters.Store(&map[uint64][]*Counter{})
+
+	return cv
+}
+
+// hashLabelValues returns FNV-1a hash of the given label values.
+func hashLabelValues(lvs []string) uint64 {
+	h := uint64(fnvOffset64)
+
+	for _, lv := range lvs {
+		for i := range len(lv) {
+			h ^= uint64(lv[i])
+			h *= fnvPrime64
+		}
+
+		// separator that can't be present in valid UTF-8 strings
+		h ^= 0xff
+		h *= fnvPrime64
+	}
+
+	return h
+}
+
+// lookup returns the counter for the given label values and their hash from the given map, or nil.
+func lookup(m map[uint64][]*Counter, h uint64, lvs []string) *Counter {
+	for _, c := range m[h] {
+		if slices.Equal(c.labelValues, lvs) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// WithLabelValues returns the counter for the given label values, creating it if needed.
+//
+// It panics if the number of label values does not match the number of label names.
+func (cv *CounterVec) WithLabelValues(lvs ...string) *Counter {
+	if len(lvs) != cv.labelsLen {
+		panic(fmt.Sprintf("expected %d label values, got %d", cv.labelsLen, len(lvs)))
+	}
+
+	h := hashLabelValues(lvs)
+
+	if c := lookup(*cv.counters.Load(), h, lvs); c != nil {
+		return c
+	}
+
+	// slow path, only taken for new label values combinations
+	cv.countersMu.Lock()
+	defer cv.countersMu.Unlock()
+
+	old := *cv.counters.Load()
+
+	if c := lookup(old, h, lvs); c != nil {
+		return c
+	}
+
+	c := &Counter{
+		labelValues: slices.Clone(lvs),
+		shards:      make([]shard, cv.shardsLen),
+	}
+
+	m := maps.Clone(old)
+	m[h] = append(slices.Clip(old[h]), c)
+	cv.counters.Store(&m)
+
+	return c
+}
+
+// Describe implements [prometheus.Collector].
+func (cv *CounterVec) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cv.desc
+}
+
+// Collect implements [prometheus.Collector].
+func (cv *CounterVec) Collect(ch chan<- prometheus.Metric) {
+	for _, cs := range *cv.counters.Load() {
+		for _, c := range cs {
+			ch <- prometheus.MustNewConstMetric(cv.desc, prometheus.CounterValue, float64(c.Value()), c.labelValues...)
+		}
+	}
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*CounterVec)(nil)
+)
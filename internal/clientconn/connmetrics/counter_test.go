@@ -0,0 +1,95 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package connmetrics
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounterVec(t *testing.T) {
+	t.Parallel()
+
+	cv := NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "test",
+			Name:      "requests_total",
+			Help:      "Test counter.",
+		},
+		[]string{"opcode", "command"},
+	)
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range 1000 {
+				cv.WithLabelValues("OP_MSG", "find").Inc()
+			}
+
+			cv.WithLabelValues("OP_MSG", "insert").Add(2)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, uint64(8000), cv.WithLabelValues("OP_MSG", "find").Value())
+	assert.Same(t, cv.WithLabelValues("OP_MSG", "find"), cv.WithLabelValues("OP_MSG", "find"))
+
+	expected := `
+		# HELP test_requests_total Test counter.
+		# TYPE test_requests_total counter
+		test_requests_total{command="find",opcode="OP_MSG"} 8000
+		test_requests_total{command="insert",opcode="OP_MSG"} 16
+	`
+	require.NoError(t, promtestutil.CollectAndCompare(cv, strings.NewReader(expected)))
+
+	assert.Panics(t, func() { cv.WithLabelValues("OP_MSG") })
+}
+
+func BenchmarkCounterVec(b *testing.B) {
+	opts := prometheus.CounterOpts{Name: "bench_total"}
+	labels := []string{"opcode", "command"}
+
+	b.Run("Prometheus", func(b *testing.B) {
+		cv := prometheus.NewCounterVec(opts, labels)
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				cv.WithLabelValues("OP_MSG", "find").Inc()
+			}
+		})
+	})
+
+	b.Run("Sharded", func(b *testing.B) {
+		cv := NewCounterVec(opts, labels)
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				cv.WithLabelValues("OP_MSG", "find").Inc()
+			}
+		})
+	})
+}
@@ -69,10 +69,13 @@ type Cursor struct {
 	r            *Registry
 	l            *slog.Logger
 	token        *resource.Token
-	removed      chan struct{} // protected by m
+	removed      chan struct{}   // protected by m
+	prefetched   []prefetchedDoc // protected by m
+	prefetchErr  error           // protected by m
 	ID           int64
 	lastRecordID int64 // protected by m
 	m            sync.Mutex
+	prefetching  bool // protected by m
 }
 
 // newCursor creates a new cursor.
@@ -135,7 +138,13 @@ func (c *Cursor) Next() (struct{}, *types.Document, error) {
 		return struct{}{}, nil, iterator.ErrIteratorDone
 	}
 
-	zero, doc, err := c.iter.Next()
+	var zero struct{}
+
+	doc, err := c.nextPrefetched()
+	if doc == nil && err == nil {
+		zero, doc, err = c.iter.Next()
+	}
+
 	if doc != nil {
 		recordID := doc.RecordID()
 		c.lastRecordID = recordID
@@ -163,6 +172,7 @@ func (c *Cursor) Close() {
 	c.l.Debug("Closing cursor's iterator")
 	c.iter.Close()
 	c.iter = nil
+	c.releasePrefetched()
 
 	c.m.Unlock()
 
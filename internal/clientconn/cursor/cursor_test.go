@@ -32,7 +32,7 @@ import (
 func TestCursor(t *testing.T) {
 	t.Parallel()
 
-	r := NewRegistry(testutil.Logger(t))
+	r := NewRegistry(testutil.Logger(t), 0)
 	t.Cleanup(r.Close)
 
 	ctx := testutil.Ctx(t)
@@ -168,3 +168,76 @@ func TestCursor(t *testing.T) {
 		})
 	})
 }
+
+func TestCursorReadahead(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	all := make([]*types.Document, 10)
+	for i := range all {
+		all[i] = must.NotFail(types.NewDocument("v", int32(i)))
+		all[i].SetRecordID(int64(i + 1))
+	}
+
+	params := &NewParams{
+		Type: Normal,
+	}
+
+	t.Run("Consume", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRegistry(testutil.Logger(t), 1024*1024)
+		t.Cleanup(r.Close)
+
+		c := r.NewCursor(ctx, iterator.Values(iterator.ForSlice(all)), params)
+
+		first, err := iterator.ConsumeValuesN(c, 3)
+		require.NoError(t, err)
+
+		c.Readahead(3)
+
+		assert.Eventually(t, func() bool {
+			c.m.Lock()
+			defer c.m.Unlock()
+
+			return !c.prefetching
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.Positive(t, r.readaheadUsed.Load())
+
+		rest, err := iterator.ConsumeValues(c)
+		require.NoError(t, err)
+		assert.Equal(t, all, append(first, rest...))
+
+		assert.Zero(t, r.readaheadUsed.Load())
+		assert.Nil(t, r.Get(c.ID), "cursor should be removed")
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		t.Parallel()
+
+		// smaller than any document
+		r := NewRegistry(testutil.Logger(t), 1)
+		t.Cleanup(r.Close)
+
+		c := r.NewCursor(ctx, iterator.Values(iterator.ForSlice(all)), params)
+
+		c.Readahead(5)
+
+		assert.Eventually(t, func() bool {
+			c.m.Lock()
+			defer c.m.Unlock()
+
+			return !c.prefetching
+		}, 5*time.Second, 10*time.Millisecond)
+
+		c.m.Lock()
+		assert.Len(t, c.prefetched, 1)
+		c.m.Unlock()
+
+		c.Close()
+
+		assert.Zero(t, r.readaheadUsed.Load())
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cursor
+
+import (
+	"log/slog"
+
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// Readahead starts prefetching up to n next documents of the normal cursor in the background,
+// so the next `getMore` command does not wait for the backend.
+//
+// It does nothing if readahead is disabled for the registry, for tailable cursors,
+// and if prefetching is already running.
+// The total size of prefetched documents of all cursors is bounded by the registry;
+// it may be exceeded by at most one document per cursor.
+func (c *Cursor) Readahead(n int) {
+	if c.r.readaheadSize <= 0 || c.Type != Normal || n <= 0 {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.iter == nil || c.prefetching || c.prefetchErr != nil {
+		return
+	}
+
+	c.prefetching = true
+
+	c.r.wg.Add(1)
+
+	go func() {
+		defer c.r.wg.Done()
+
+		c.prefetch(n)
+	}()
+}
+
+// prefetch reads up to n documents from the underlying iterator into the buffer.
+func (c *Cursor) prefetch(n int) {
+	var count int
+
+	defer func() {
+		c.m.Lock()
+		c.prefetching = false
+		c.m.Unlock()
+
+		c.l.Debug("Readahead finished", slog.Int("count", count))
+	}()
+
+	// the lock is taken for each document, so Next can consume already prefetched documents
+	for count < n {
+		if !c.prefetchOne() {
+			return
+		}
+
+		count++
+	}
+}
+
+// prefetchOne reads a single document from the underlying iterator into the buffer.
+// It returns false if prefetching should stop.
+func (c *Cursor) prefetchOne() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.iter == nil || c.prefetchErr != nil || c.r.readaheadUsed.Load() >= c.r.readaheadSize {
+		return false
+	}
+
+	_, doc, err := c.iter.Next()
+	if err != nil {
+		// returned by Next after all prefetched documents are consumed
+		c.prefetchErr = err
+		return false
+	}
+
+	size := documentSize(doc)
+	c.r.readaheadUsed.Add(size)
+
+	c.prefetched = append(c.prefetched, prefetchedDoc{doc: doc, size: size})
+
+	return true
+}
+
+// nextPrefetched returns the next prefetched document, the error that stopped prefetching,
+// or (nil, nil) if there is neither.
+//
+// It should be called with c.m held.
+func (c *Cursor) nextPrefetched() (*types.Document, error) {
+	if len(c.prefetched) == 0 {
+		return nil, c.prefetchErr
+	}
+
+	p := c.prefetched[0]
+	c.prefetched[0] = prefetchedDoc{}
+	c.prefetched = c.prefetched[1:]
+
+	c.r.readaheadUsed.Add(-p.size)
+
+	return p.doc, nil
+}
+
+// releasePrefetched drops all prefetched documents.
+//
+// It should be called with c.m held.
+func (c *Cursor) releasePrefetched() {
+	for _, p := range c.prefetched {
+		c.r.readaheadUsed.Add(-p.size)
+	}
+
+	c.prefetched = nil
+	c.prefetchErr = nil
+}
+
+// prefetchedDoc represents a prefetched document with its estimated size.
+type prefetchedDoc struct {
+	doc  *types.Document
+	size int64
+}
+
+// documentSize returns the size of the document encoded as BSON.
+func documentSize(doc *types.Document) int64 {
+	d, err := bson.FromDocument(doc)
+	if err != nil {
+		// the document will fail to encode later anyway
+		return 0
+	}
+
+	b, err := d.Encode()
+	if err != nil {
+		return 0
+	}
+
+	return int64(len(b))
+}
@@ -22,6 +22,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/exp/maps"
 
@@ -55,15 +56,22 @@ type Registry struct {
 	l  *slog.Logger
 	wg sync.WaitGroup
 
+	readaheadSize int64
+	readaheadUsed atomic.Int64
+
 	created  *prometheus.CounterVec
 	duration *prometheus.HistogramVec
 }
 
 // NewRegistry creates a new Registry.
-func NewRegistry(l *slog.Logger) *Registry {
+//
+// The readaheadSize is the maximum total size in bytes of documents prefetched by all cursors;
+// zero value disables readahead.
+func NewRegistry(l *slog.Logger, readaheadSize int64) *Registry {
 	return &Registry{
-		m: map[int64]*Cursor{},
-		l: l,
+		m:             map[int64]*Cursor{},
+		l:             l,
+		readaheadSize: readaheadSize,
 		created: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
@@ -117,6 +125,9 @@ type NewParams struct {
 	Collection string
 	Username   string
 
+	// Logical session the cursor was created in; zero if none.
+	Session uuid.UUID
+
 	Type         Type
 	ShowRecordID bool
 
@@ -48,6 +48,8 @@ type Listener struct {
 	unixListener net.Listener
 	tlsListener  net.Listener
 
+	ocspStapler *tlsutil.OCSPStapler // nil if OCSP stapling is disabled
+
 	tcpListenerReady  chan struct{}
 	unixListenerReady chan struct{}
 	tlsListenerReady  chan struct{}
@@ -64,6 +66,17 @@ type NewListenerOpts struct {
 	TLSKeyFile  string
 	TLSCAFile   string
 
+	// TLSMinVersion and TLSCipherSuites restrict TLS versions and cipher suites accepted by TLS listener.
+	// See [tlsutil.ServerOpts].
+	TLSMinVersion   string
+	TLSCipherSuites []string
+
+	// TLSOCSPStapling enables stapling of OCSP responses fetched from the certificate's OCSP responder.
+	TLSOCSPStapling bool
+
+	// ProxyProtocol enables PROXY protocol for TCP and TLS listeners.
+	ProxyProtocol bool
+
 	ProxyAddr        string
 	ProxyTLSCertFile string
 	ProxyTLSKeyFile  string
@@ -74,6 +87,9 @@ type NewListenerOpts struct {
 	Handler        *handler.Handler
 	Logger         *slog.Logger
 	TestRecordsDir string // if empty, no records are created
+
+	// Chaos enables chaos injection into responses if set and not zero.
+	Chaos *ChaosOpts
 }
 
 // Listen creates a new listener and starts listening on configured interfaces.
@@ -103,6 +119,10 @@ func Listen(opts *NewListenerOpts) (*Listener, error) {
 			return nil, lazyerrors.Error(err)
 		}
 
+		if l.ProxyProtocol {
+			l.tcpListener = &proxyProtocolListener{Listener: l.tcpListener}
+		}
+
 		close(l.tcpListenerReady)
 		ll.InfoContext(ctx, fmt.Sprintf("Listening on TCP %s...", l.TCPAddr()))
 	}
@@ -124,10 +144,33 @@ func Listen(opts *NewListenerOpts) (*Listener, error) {
 			return nil, err
 		}
 
-		if l.tlsListener, err = tls.Listen("tcp", l.TLS, config); err != nil {
+		serverOpts := &tlsutil.ServerOpts{
+			MinVersion:   l.TLSMinVersion,
+			CipherSuites: l.TLSCipherSuites,
+		}
+		if err = serverOpts.Apply(config); err != nil {
+			return nil, err
+		}
+
+		if l.TLSOCSPStapling {
+			if l.ocspStapler, err = tlsutil.NewOCSPStapler(&config.Certificates[0], logging.WithName(opts.Logger, "ocsp")); err != nil {
+				return nil, err
+			}
+
+			config.Certificates = nil
+			config.GetCertificate = l.ocspStapler.GetCertificate
+		}
+
+		if l.tlsListener, err = net.Listen("tcp", l.TLS); err != nil {
 			return nil, lazyerrors.Error(err)
 		}
 
+		if l.ProxyProtocol {
+			l.tlsListener = &proxyProtocolListener{Listener: l.tlsListener}
+		}
+
+		l.tlsListener = tls.NewListener(l.tlsListener, config)
+
 		close(l.tlsListenerReady)
 		ll.InfoContext(ctx, fmt.Sprintf("Listening on TLS %s...", l.TLSAddr()))
 	}
@@ -193,6 +236,16 @@ func (l *Listener) Run(ctx context.Context) {
 		}()
 	}
 
+	if l.ocspStapler != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			l.ocspStapler.Run(ctx)
+		}()
+	}
+
 	<-ctx.Done()
 
 	if l.tcpListener != nil {
@@ -281,6 +334,7 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 				proxyTLSCAFile:   l.ProxyTLSCAFile,
 
 				testRecordsDir: l.TestRecordsDir,
+				chaos:          l.Chaos,
 			}
 
 			conn, connErr := newConn(opts)
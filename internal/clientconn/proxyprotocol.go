@@ -0,0 +1,241 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// proxyProtocolHeaderTimeout is the maximum time to wait for PROXY protocol header.
+const proxyProtocolHeaderTimeout = 5 * time.Second
+
+// proxyProtocolV1MaxLen is the maximum length of PROXY protocol v1 header, including CRLF.
+const proxyProtocolV1MaxLen = 107
+
+// proxyProtocolV2Signature is the signature of PROXY protocol v2 header.
+var proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// errProxyProtocol is returned for invalid PROXY protocol headers.
+var errProxyProtocol = errors.New("invalid PROXY protocol header")
+
+// proxyProtocolListener wraps a listener and returns connections
+// that read PROXY protocol (v1 or v2) header sent by a load balancer before any other data.
+//
+// See https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt.
+type proxyProtocolListener struct {
+	net.Listener
+}
+
+// Accept implements [net.Listener].
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proxyProtocolConn{
+		Conn: c,
+		r:    bufio.NewReader(c),
+	}, nil
+}
+
+// proxyProtocolConn is a connection with PROXY protocol header.
+//
+// The header is read lazily on the first Read or RemoteAddr call,
+// so a slow client does not block the accept loop.
+type proxyProtocolConn struct {
+	net.Conn
+
+	r    *bufio.Reader
+	once sync.Once
+
+	remoteAddr net.Addr // nil if header does not contain the client address
+	err        error
+}
+
+// readHeader reads PROXY protocol header once.
+func (c *proxyProtocolConn) readHeader() {
+	c.once.Do(func() {
+		if c.err = c.Conn.SetReadDeadline(time.Now().Add(proxyProtocolHeaderTimeout)); c.err != nil {
+			return
+		}
+
+		if c.remoteAddr, c.err = readProxyProtocolHeader(c.r); c.err != nil {
+			return
+		}
+
+		c.err = c.Conn.SetReadDeadline(time.Time{})
+	})
+}
+
+// Read implements [net.Conn].
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	c.readHeader()
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.r.Read(b)
+}
+
+// RemoteAddr implements [net.Conn].
+//
+// It returns the client address from PROXY protocol header if it is present,
+// and the address of the load balancer otherwise.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.readHeader()
+
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+// readProxyProtocolHeader reads PROXY protocol v1 or v2 header from r.
+//
+// It returns nil address for LOCAL (health check) connections
+// and for unknown or non-TCP address families.
+func readProxyProtocolHeader(r *bufio.Reader) (net.Addr, error) {
+	b, err := r.Peek(len(proxyProtocolV2Signature))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if bytes.Equal(b, proxyProtocolV2Signature) {
+		return readProxyProtocolV2(r)
+	}
+
+	if bytes.HasPrefix(b, []byte("PROXY ")) {
+		return readProxyProtocolV1(r)
+	}
+
+	return nil, lazyerrors.Error(errProxyProtocol)
+}
+
+// readProxyProtocolV1 reads human-readable PROXY protocol v1 header from r.
+func readProxyProtocolV1(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+
+	for len(line) < proxyProtocolV1MaxLen {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		line = append(line, b)
+
+		if b == '\n' {
+			break
+		}
+	}
+
+	s, ok := strings.CutSuffix(string(line), "\r\n")
+	if !ok {
+		return nil, lazyerrors.Errorf("%w: v1 header is not terminated", errProxyProtocol)
+	}
+
+	fields := strings.Split(s, " ")
+
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, lazyerrors.Errorf("%w: %q", errProxyProtocol, s)
+	}
+
+	addr, err := netip.ParseAddr(fields[2])
+	if err != nil {
+		return nil, lazyerrors.Errorf("%w: %s", errProxyProtocol, err)
+	}
+
+	if addr.Is4() != (fields[1] == "TCP4") {
+		return nil, lazyerrors.Errorf("%w: address %s does not match %s", errProxyProtocol, addr, fields[1])
+	}
+
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, lazyerrors.Errorf("%w: %s", errProxyProtocol, err)
+	}
+
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, uint16(port))), nil
+}
+
+// readProxyProtocolV2 reads binary PROXY protocol v2 header from r.
+func readProxyProtocolV2(r *bufio.Reader) (net.Addr, error) {
+	header := make([]byte, len(proxyProtocolV2Signature)+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	verCmd, famProto := header[12], header[13]
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if verCmd>>4 != 2 {
+		return nil, lazyerrors.Errorf("%w: unexpected version %d", errProxyProtocol, verCmd>>4)
+	}
+
+	switch verCmd & 0x0f {
+	case 0x00: // LOCAL
+		return nil, nil
+	case 0x01: // PROXY
+	default:
+		return nil, lazyerrors.Errorf("%w: unexpected command %d", errProxyProtocol, verCmd&0x0f)
+	}
+
+	var addrLen int
+
+	switch famProto {
+	case 0x11: // TCP over IPv4
+		addrLen = 4
+	case 0x21: // TCP over IPv6
+		addrLen = 16
+	default:
+		return nil, nil
+	}
+
+	if len(payload) < 2*addrLen+4 {
+		return nil, lazyerrors.Errorf("%w: address block is too short", errProxyProtocol)
+	}
+
+	addr, _ := netip.AddrFromSlice(payload[:addrLen])
+	port := binary.BigEndian.Uint16(payload[2*addrLen:])
+
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(addr, port)), nil
+}
+
+// check interfaces
+var (
+	_ net.Listener = (*proxyProtocolListener)(nil)
+	_ net.Conn     = (*proxyProtocolConn)(nil)
+)
@@ -0,0 +1,140 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyProtocolV2Header returns PROXY protocol v2 header with the given command, family, and payload.
+func proxyProtocolV2Header(cmd, fam byte, payload []byte) []byte {
+	b := append([]byte(nil), proxyProtocolV2Signature...)
+	b = append(b, 0x20|cmd, fam)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(payload)))
+
+	return append(b, payload...)
+}
+
+func TestReadProxyProtocolHeader(t *testing.T) {
+	t.Parallel()
+
+	ipv4 := []byte{192, 0, 2, 1, 198, 51, 100, 1, 0xc3, 0x50, 0x69, 0x89}
+
+	ipv6 := make([]byte, 36)
+	ipv6[15] = 1
+	ipv6[31] = 2
+	binary.BigEndian.PutUint16(ipv6[32:], 12345)
+
+	for name, tc := range map[string]struct {
+		header   []byte
+		expected string // empty for no address
+		err      bool
+	}{
+		"V1TCP4": {
+			header:   []byte("PROXY TCP4 192.0.2.1 198.51.100.1 50000 27017\r\n"),
+			expected: "192.0.2.1:50000",
+		},
+		"V1TCP6": {
+			header:   []byte("PROXY TCP6 2001:db8::1 2001:db8::2 50000 27017\r\n"),
+			expected: "[2001:db8::1]:50000",
+		},
+		"V1Unknown": {
+			header: []byte("PROXY UNKNOWN\r\n"),
+		},
+		"V1Mismatch": {
+			header: []byte("PROXY TCP4 2001:db8::1 2001:db8::2 50000 27017\r\n"),
+			err:    true,
+		},
+		"V1NotTerminated": {
+			header: append([]byte("PROXY TCP4 "), bytes.Repeat([]byte("1"), proxyProtocolV1MaxLen)...),
+			err:    true,
+		},
+		"V2TCP4": {
+			header:   proxyProtocolV2Header(0x01, 0x11, ipv4),
+			expected: "192.0.2.1:50000",
+		},
+		"V2TCP6": {
+			header:   proxyProtocolV2Header(0x01, 0x21, ipv6),
+			expected: "[::1]:12345",
+		},
+		"V2Local": {
+			header: proxyProtocolV2Header(0x00, 0x00, nil),
+		},
+		"V2Short": {
+			header: proxyProtocolV2Header(0x01, 0x21, ipv4),
+			err:    true,
+		},
+		"NoHeader": {
+			header: []byte("\x10\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"),
+			err:    true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := bufio.NewReader(io.MultiReader(bytes.NewReader(tc.header), bytes.NewReader([]byte("data"))))
+
+			addr, err := readProxyProtocolHeader(r)
+			if tc.err {
+				assert.ErrorIs(t, err, errProxyProtocol)
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.expected == "" {
+				assert.Nil(t, addr)
+			} else {
+				require.NotNil(t, addr)
+				assert.Equal(t, tc.expected, addr.String())
+			}
+
+			rest, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, "data", string(rest))
+		})
+	}
+}
+
+func TestProxyProtocolConn(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	go func() {
+		_, _ = client.Write([]byte("PROXY TCP4 192.0.2.1 198.51.100.1 50000 27017\r\nhello"))
+	}()
+
+	c := &proxyProtocolConn{Conn: server, r: bufio.NewReader(server)}
+
+	assert.Equal(t, "192.0.2.1:50000", c.RemoteAddr().String())
+
+	b := make([]byte, 5)
+	_, err := io.ReadFull(c, b)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+}
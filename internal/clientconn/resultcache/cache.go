@@ -0,0 +1,193 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resultcache provides a size-bounded LRU cache of read commands results.
+//
+// Results are cached per database. Every cached result is tagged with the database version
+// taken before the command was executed; write commands increment that version,
+// making all previously cached results for the database stale.
+package resultcache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "result_cache"
+)
+
+// Version represents a version of the database's data at some point in time.
+type Version struct {
+	db     uint64
+	global uint64
+}
+
+// entry represents a cached result.
+type entry struct {
+	key     string
+	res     []byte
+	version Version
+}
+
+// Cache is a size-bounded LRU cache of read commands results.
+//
+//nolint:vet // for readability
+type Cache struct {
+	rw       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List // of *entry; the most recently used first
+	versions map[string]uint64
+	global   uint64
+	size     int64
+	maxSize  int64
+
+	requests *prometheus.CounterVec
+}
+
+// New creates a new cache with the given maximum total size of keys and results in bytes.
+func New(maxSize int64) *Cache {
+	return &Cache{
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+		versions: map[string]uint64{},
+		maxSize:  maxSize,
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "requests_total",
+				Help:      "Total number of result cache lookups.",
+			},
+			[]string{"result"},
+		),
+	}
+}
+
+// Version returns the current version of the given database.
+//
+// It should be taken before the command is executed and passed to [Cache.Put].
+func (c *Cache) Version(db string) Version {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	return c.version(db)
+}
+
+// version returns the current version of the given database.
+//
+// It should be called with the lock held.
+func (c *Cache) version(db string) Version {
+	return Version{db: c.versions[db], global: c.global}
+}
+
+// Get returns a cached result for the given database and key, or nil.
+func (c *Cache) Get(db, key string) []byte {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	el := c.entries[key]
+	if el == nil {
+		c.requests.WithLabelValues("miss").Inc()
+		return nil
+	}
+
+	e := el.Value.(*entry)
+	if e.version != c.version(db) {
+		c.remove(el)
+		c.requests.WithLabelValues("miss").Inc()
+
+		return nil
+	}
+
+	c.lru.MoveToFront(el)
+	c.requests.WithLabelValues("hit").Inc()
+
+	return e.res
+}
+
+// Put stores the result for the given database and key.
+//
+// The result is not stored if the database was changed since the given version was taken,
+// or if it is larger than an eighth of the cache size.
+func (c *Cache) Put(db, key string, version Version, res []byte) {
+	size := int64(len(key) + len(res))
+	if size > c.maxSize/8 {
+		return
+	}
+
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	if version != c.version(db) {
+		return
+	}
+
+	if el := c.entries[key]; el != nil {
+		c.remove(el)
+	}
+
+	c.entries[key] = c.lru.PushFront(&entry{
+		key:     key,
+		res:     res,
+		version: version,
+	})
+	c.size += size
+
+	for c.size > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Invalidate makes all cached results for the given database stale.
+// Empty database name makes all cached results stale.
+func (c *Cache) Invalidate(db string) {
+	c.rw.Lock()
+	defer c.rw.Unlock()
+
+	if db == "" {
+		c.global++
+		return
+	}
+
+	c.versions[db]++
+}
+
+// remove removes the given element.
+//
+// It should be called with the lock held.
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*entry)
+	delete(c.entries, e.key)
+	c.size -= int64(len(e.key) + len(e.res))
+}
+
+// Describe implements [prometheus.Collector].
+func (c *Cache) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (c *Cache) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*Cache)(nil)
+)
@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resultcache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	c := New(1024)
+
+	v := c.Version("db1")
+	c.Put("db1", "key1", v, []byte("res1"))
+	c.Put("db2", "key2", c.Version("db2"), []byte("res2"))
+
+	assert.Equal(t, []byte("res1"), c.Get("db1", "key1"))
+	assert.Equal(t, []byte("res2"), c.Get("db2", "key2"))
+	assert.Nil(t, c.Get("db1", "key3"))
+
+	t.Run("Invalidate", func(t *testing.T) {
+		c.Invalidate("db1")
+
+		assert.Nil(t, c.Get("db1", "key1"))
+		assert.Equal(t, []byte("res2"), c.Get("db2", "key2"))
+
+		// stale version
+		c.Put("db1", "key1", v, []byte("res1"))
+		assert.Nil(t, c.Get("db1", "key1"))
+
+		c.Invalidate("")
+		assert.Nil(t, c.Get("db2", "key2"))
+	})
+
+	t.Run("Metrics", func(t *testing.T) {
+		expected := `
+			# HELP ferretdb_result_cache_requests_total Total number of result cache lookups.
+			# TYPE ferretdb_result_cache_requests_total counter
+			ferretdb_result_cache_requests_total{result="hit"} 3
+			ferretdb_result_cache_requests_total{result="miss"} 4
+		`
+		require.NoError(t, promtestutil.CollectAndCompare(c, strings.NewReader(expected)))
+	})
+}
+
+func TestCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	// 8 entries of 8 bytes each
+	c := New(64)
+
+	// too large
+	c.Put("db", "key", c.Version("db"), []byte("result"))
+	assert.Nil(t, c.Get("db", "key"))
+
+	for i := 1; i <= 9; i++ {
+		c.Put("db", fmt.Sprintf("k%02d", i), c.Version("db"), []byte("res00"))
+	}
+
+	// the least recently used entry was evicted
+	assert.Nil(t, c.Get("db", "k01"))
+
+	// make k02 the most recently used
+	assert.NotNil(t, c.Get("db", "k02"))
+
+	c.Put("db", "k10", c.Version("db"), []byte("res00"))
+	c.Put("db", "k11", c.Version("db"), []byte("res00"))
+
+	assert.NotNil(t, c.Get("db", "k02"))
+	assert.Nil(t, c.Get("db", "k03"))
+	assert.Nil(t, c.Get("db", "k04"))
+	assert.NotNil(t, c.Get("db", "k05"))
+}
@@ -0,0 +1,267 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package session provides logical sessions registry.
+package session
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "sessions"
+)
+
+// Session represents a logical session.
+type Session struct {
+	lastUse time.Time
+
+	// Owner of the session; empty if the session was started without authentication.
+	Username string
+	DB       string
+
+	ID uuid.UUID
+}
+
+// LastUse returns the last time the session was used or refreshed.
+func (s *Session) LastUse() time.Time {
+	return s.lastUse
+}
+
+// Registry stores logical sessions.
+//
+// Sessions that were not used for the configured timeout are expired;
+// they are not returned by the registry and are removed by [Registry.RemoveExpired].
+//
+//nolint:vet // for readability
+type Registry struct {
+	rw      sync.RWMutex
+	m       map[uuid.UUID]*Session
+	timeout time.Duration
+
+	l *slog.Logger
+
+	created prometheus.Counter
+	ended   prometheus.Counter
+	expired prometheus.Counter
+}
+
+// NewRegistry creates a new Registry with the given session timeout.
+func NewRegistry(timeout time.Duration, l *slog.Logger) *Registry {
+	return &Registry{
+		m:       map[uuid.UUID]*Session{},
+		timeout: timeout,
+		l:       l,
+		created: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "created_total",
+			Help:      "Total number of logical sessions created.",
+		}),
+		ended: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "ended_total",
+			Help:      "Total number of logical sessions ended or killed by clients.",
+		}),
+		expired: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "expired_total",
+			Help:      "Total number of logical sessions expired after the timeout.",
+		}),
+	}
+}
+
+// Timeout returns the session timeout.
+func (r *Registry) Timeout() time.Duration {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	return r.timeout
+}
+
+// SetTimeout sets the session timeout.
+// It affects already stored sessions too.
+func (r *Registry) SetTimeout(timeout time.Duration) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.timeout = timeout
+}
+
+// Refresh creates a session with the given ID and owner,
+// or updates the last use time of the existing session.
+func (r *Registry) Refresh(id uuid.UUID, username, db string) {
+	now := time.Now()
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	if s := r.m[id]; s != nil {
+		s.lastUse = now
+		return
+	}
+
+	r.l.Debug("Creating session", slog.String("id", id.String()), slog.String("username", username))
+	r.created.Inc()
+
+	r.m[id] = &Session{
+		lastUse:  now,
+		Username: username,
+		DB:       db,
+		ID:       id,
+	}
+}
+
+// Get returns a copy of the stored session by ID, or nil.
+func (r *Registry) Get(id uuid.UUID) *Session {
+	now := time.Now()
+
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	s := r.m[id]
+	if s == nil || r.isExpired(s, now) {
+		return nil
+	}
+
+	res := *s
+
+	return &res
+}
+
+// All returns copies of all stored sessions that are not expired.
+func (r *Registry) All() []*Session {
+	now := time.Now()
+
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	res := make([]*Session, 0, len(r.m))
+
+	for _, s := range r.m {
+		if r.isExpired(s, now) {
+			continue
+		}
+
+		c := *s
+		res = append(res, &c)
+	}
+
+	return res
+}
+
+// Remove removes sessions with the given IDs from the registry and returns the number of removed sessions.
+// Unknown IDs are ignored.
+func (r *Registry) Remove(ids ...uuid.UUID) int {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var n int
+
+	for _, id := range ids {
+		if _, ok := r.m[id]; !ok {
+			continue
+		}
+
+		delete(r.m, id)
+		n++
+	}
+
+	r.l.Debug("Sessions removed", slog.Int("requested", len(ids)), slog.Int("removed", n))
+	r.ended.Add(float64(n))
+
+	return n
+}
+
+// RemoveExpired removes expired sessions from the registry and returns their IDs.
+func (r *Registry) RemoveExpired() []uuid.UUID {
+	now := time.Now()
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	var res []uuid.UUID
+
+	for id, s := range r.m {
+		if !r.isExpired(s, now) {
+			continue
+		}
+
+		r.l.Debug("Removing expired session", slog.String("id", id.String()))
+		delete(r.m, id)
+
+		res = append(res, id)
+	}
+
+	r.expired.Add(float64(len(res)))
+
+	return res
+}
+
+// Describe implements [prometheus.Collector].
+func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(r, ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (r *Registry) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+
+	r.rw.RLock()
+
+	var active int
+
+	for _, s := range r.m {
+		if !r.isExpired(s, now) {
+			active++
+		}
+	}
+
+	r.rw.RUnlock()
+
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, subsystem, "active"),
+			"The current number of active logical sessions.",
+			nil, nil,
+		),
+		prometheus.GaugeValue,
+		float64(active),
+	)
+
+	r.created.Collect(ch)
+	r.ended.Collect(ch)
+	r.expired.Collect(ch)
+}
+
+// isExpired returns true if the session was not used for the timeout.
+//
+// It should be called with the lock held.
+func (r *Registry) isExpired(s *Session, now time.Time) bool {
+	return now.Sub(s.lastUse) > r.timeout
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*Registry)(nil)
+)
@@ -0,0 +1,127 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(time.Hour, testutil.Logger(t))
+
+	id1, id2 := uuid.New(), uuid.New()
+
+	r.Refresh(id1, "user1", "admin")
+	r.Refresh(id2, "", "")
+
+	s := r.Get(id1)
+	require.NotNil(t, s)
+	assert.Equal(t, "user1", s.Username)
+	assert.Equal(t, "admin", s.DB)
+
+	lastUse := s.LastUse()
+
+	// the owner is not changed by refresh
+	r.Refresh(id1, "user2", "test")
+
+	s = r.Get(id1)
+	require.NotNil(t, s)
+	assert.Equal(t, "user1", s.Username)
+	assert.False(t, s.LastUse().Before(lastUse))
+
+	assert.Len(t, r.All(), 2)
+
+	r.Remove(id1, uuid.New())
+
+	assert.Nil(t, r.Get(id1))
+	assert.NotNil(t, r.Get(id2))
+	assert.Len(t, r.All(), 1)
+}
+
+func TestRegistryExpired(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(time.Millisecond, testutil.Logger(t))
+
+	id := uuid.New()
+	r.Refresh(id, "", "")
+
+	time.Sleep(10 * time.Millisecond)
+
+	assert.Nil(t, r.Get(id))
+	assert.Empty(t, r.All())
+
+	r.SetTimeout(time.Hour)
+	assert.NotNil(t, r.Get(id))
+
+	r.SetTimeout(time.Millisecond)
+	assert.Equal(t, []uuid.UUID{id}, r.RemoveExpired())
+	assert.Empty(t, r.RemoveExpired())
+
+	r.SetTimeout(time.Hour)
+	assert.Nil(t, r.Get(id))
+}
+
+func TestRegistryMetrics(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(time.Hour, testutil.Logger(t))
+
+	id1, id2, id3 := uuid.New(), uuid.New(), uuid.New()
+
+	r.Refresh(id1, "", "")
+	r.Refresh(id2, "", "")
+	r.Refresh(id3, "", "")
+	r.Refresh(id3, "", "")
+
+	assert.Equal(t, 1, r.Remove(id1, uuid.New()))
+
+	r.SetTimeout(0)
+	assert.Len(t, r.RemoveExpired(), 2)
+
+	r.SetTimeout(time.Hour)
+	r.Refresh(id1, "", "")
+
+	problems, err := promtestutil.CollectAndLint(r)
+	require.NoError(t, err)
+	require.Empty(t, problems)
+
+	expected := `
+		# HELP ferretdb_sessions_active The current number of active logical sessions.
+		# TYPE ferretdb_sessions_active gauge
+		ferretdb_sessions_active 1
+		# HELP ferretdb_sessions_created_total Total number of logical sessions created.
+		# TYPE ferretdb_sessions_created_total counter
+		ferretdb_sessions_created_total 4
+		# HELP ferretdb_sessions_ended_total Total number of logical sessions ended or killed by clients.
+		# TYPE ferretdb_sessions_ended_total counter
+		ferretdb_sessions_ended_total 1
+		# HELP ferretdb_sessions_expired_total Total number of logical sessions expired after the timeout.
+		# TYPE ferretdb_sessions_expired_total counter
+		ferretdb_sessions_expired_total 2
+	`
+	assert.NoError(t, promtestutil.CollectAndCompare(r, strings.NewReader(expected)))
+}
@@ -0,0 +1,355 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/pmezard/go-difflib/difflib"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/handler/proxy"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+)
+
+const (
+	// shadowQueueSize is the number of requests waiting to be mirrored per connection;
+	// requests are dropped if the shadow target is slower than FerretDB.
+	shadowQueueSize = 100
+
+	// shadowTimeout is the maximum duration of a single mirrored request.
+	shadowTimeout = 30 * time.Second
+)
+
+// Results of mirrored requests used as metrics labels.
+const (
+	shadowMatch    = "match"
+	shadowMismatch = "mismatch"
+	shadowIgnored  = "ignored"
+	shadowError    = "error"
+	shadowDropped  = "dropped"
+)
+
+// shadowIgnoredCommands contains commands which responses are mirrored, but not compared,
+// because they contain information about the server itself.
+var shadowIgnoredCommands = map[string]struct{}{
+	"buildInfo":        {},
+	"collStats":        {},
+	"connectionStatus": {},
+	"currentOp":        {},
+	"dataSize":         {},
+	"dbStats":          {},
+	"explain":          {},
+	"getCmdLineOpts":   {},
+	"getLog":           {},
+	"getParameter":     {},
+	"hello":            {},
+	"hostInfo":         {},
+	"isMaster":         {},
+	"ismaster":         {},
+	"listCommands":     {},
+	"saslContinue":     {},
+	"saslStart":        {},
+	"serverStatus":     {},
+	"validate":         {},
+	"whatsmyuri":       {},
+}
+
+// shadowVolatileFields contains top-level response fields that differ between servers and are not compared.
+var shadowVolatileFields = []string{
+	"$clusterTime",
+	"operationTime",
+	"electionId",
+	"opTime",
+}
+
+// shadowRequest represents a request to be mirrored.
+type shadowRequest struct {
+	header *wire.MsgHeader
+	body   []byte // marshaled before FerretDB handled it
+	res    wire.MsgBody
+}
+
+// shadow asynchronously mirrors requests of a single client connection to the shadow target
+// and compares responses with FerretDB's.
+type shadow struct {
+	l        *slog.Logger
+	router   *proxy.Router // nil after an error
+	m        *connmetrics.CounterVec
+	requests chan *shadowRequest
+	done     chan struct{}
+
+	// FerretDB cursor ID -> shadow target cursor ID
+	cursors map[int64]int64
+}
+
+// newShadow creates a new shadow and starts mirroring requests.
+func newShadow(router *proxy.Router, m *connmetrics.CounterVec, l *slog.Logger) *shadow {
+	s := &shadow{
+		l:        l,
+		router:   router,
+		m:        m,
+		requests: make(chan *shadowRequest, shadowQueueSize),
+		done:     make(chan struct{}),
+		cursors:  map[int64]int64{},
+	}
+
+	go s.run()
+
+	return s
+}
+
+// send queues a request to be mirrored; it does not block.
+func (s *shadow) send(req *shadowRequest) {
+	select {
+	case s.requests <- req:
+	default:
+		s.m.WithLabelValues("", shadowDropped).Inc()
+	}
+}
+
+// close waits for queued requests to be mirrored and closes the connection to the shadow target.
+func (s *shadow) close() {
+	close(s.requests)
+	<-s.done
+
+	if s.router != nil {
+		s.router.Close()
+	}
+}
+
+// run mirrors queued requests until the queue is closed.
+func (s *shadow) run() {
+	defer close(s.done)
+
+	for req := range s.requests {
+		if req.header.OpCode != wire.OpCodeMsg {
+			continue
+		}
+
+		command, result := s.mirror(req)
+		s.m.WithLabelValues(command, result).Inc()
+	}
+}
+
+// mirror sends a single request to the shadow target and compares responses.
+// It returns the command name and the comparison result.
+func (s *shadow) mirror(req *shadowRequest) (string, string) {
+	var msg wire.OpMsg
+	if err := msg.UnmarshalBinaryNocopy(req.body); err != nil {
+		return "", shadowError
+	}
+
+	doc, err := msg.RawSection0().Decode()
+	if err != nil {
+		return "", shadowError
+	}
+
+	command := doc.Command()
+
+	if s.router == nil {
+		return command, shadowError
+	}
+
+	body := wire.MsgBody(&msg)
+
+	if rewritten := s.rewriteCursors(doc); rewritten {
+		var m *wire.OpMsg
+		if m, err = wire.NewOpMsg(doc); err != nil {
+			return command, shadowError
+		}
+
+		body = m
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shadowTimeout)
+	defer cancel()
+
+	resHeader, resBody, err := s.router.Send(ctx, req.header, body)
+	if err != nil {
+		s.l.WarnContext(ctx, "Shadow target failed, mirroring stopped", logging.Error(err))
+		s.router.Close()
+		s.router = nil
+
+		return command, shadowError
+	}
+
+	if resHeader.OpCode != wire.OpCodeMsg {
+		return command, shadowError
+	}
+
+	resMsg, ok := req.res.(*wire.OpMsg)
+	if !ok {
+		return command, shadowError
+	}
+
+	res, err := resMsg.RawSection0().DecodeDeep()
+	if err != nil {
+		return command, shadowError
+	}
+
+	shadowRes, err := resBody.(*wire.OpMsg).RawSection0().DecodeDeep()
+	if err != nil {
+		return command, shadowError
+	}
+
+	s.trackCursors(command, doc, res, shadowRes)
+
+	if _, ok := shadowIgnoredCommands[command]; ok {
+		return command, shadowIgnored
+	}
+
+	if shadowEqual(res, shadowRes) {
+		return command, shadowMatch
+	}
+
+	if s.l.Enabled(ctx, slog.LevelWarn) {
+		diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(wirebson.LogMessageBlock(res)),
+			FromFile: "res body",
+			B:        difflib.SplitLines(wirebson.LogMessageBlock(shadowRes)),
+			ToFile:   "shadow body",
+			Context:  1,
+		})
+
+		s.l.WarnContext(ctx, "Shadow response mismatch for "+command+":\n"+diff)
+	}
+
+	return command, shadowMismatch
+}
+
+// rewriteCursors replaces FerretDB cursor IDs in getMore and killCursors requests with shadow target's ones.
+// It returns true if the document was changed.
+func (s *shadow) rewriteCursors(doc *wirebson.Document) bool {
+	switch doc.Command() {
+	case "getMore":
+		id, ok := doc.Get("getMore").(int64)
+		if !ok {
+			return false
+		}
+
+		if shadowID, ok := s.cursors[id]; ok {
+			return doc.Replace("getMore", shadowID) == nil
+		}
+
+	case "killCursors":
+		raw, ok := doc.Get("cursors").(wirebson.RawArray)
+		if !ok {
+			return false
+		}
+
+		cursors, err := raw.Decode()
+		if err != nil {
+			return false
+		}
+
+		ids := wirebson.MakeArray(cursors.Len())
+
+		for i := range cursors.Len() {
+			id := cursors.Get(i)
+
+			if fid, ok := id.(int64); ok {
+				if shadowID, ok := s.cursors[fid]; ok {
+					id = shadowID
+				}
+
+				delete(s.cursors, fid)
+			}
+
+			if err = ids.Add(id); err != nil {
+				return false
+			}
+		}
+
+		return doc.Replace("cursors", ids) == nil
+	}
+
+	return false
+}
+
+// trackCursors remembers shadow target's cursor IDs matching FerretDB's ones.
+func (s *shadow) trackCursors(command string, doc, res, shadowRes *wirebson.Document) {
+	id, shadowID := shadowCursorID(res), shadowCursorID(shadowRes)
+
+	if command == "getMore" && id == 0 {
+		if fid, ok := doc.Get("getMore").(int64); ok {
+			delete(s.cursors, fid)
+		}
+	}
+
+	if id != 0 && shadowID != 0 {
+		s.cursors[id] = shadowID
+	}
+}
+
+// shadowCursorID returns cursor ID from the response or 0.
+func shadowCursorID(res *wirebson.Document) int64 {
+	cursor, ok := res.Get("cursor").(*wirebson.Document)
+	if !ok {
+		return 0
+	}
+
+	id, _ := cursor.Get("id").(int64)
+
+	return id
+}
+
+// shadowEqual returns true if responses are the same after removing server-specific fields.
+func shadowEqual(res, shadowRes *wirebson.Document) bool {
+	a, errA := shadowNormalize(res).Encode()
+	b, errB := shadowNormalize(shadowRes).Encode()
+
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+// shadowNormalize removes volatile fields from the response and replaces cursor ID with a flag of its presence.
+// Error messages are removed too, as they are often different; only error codes are compared.
+//
+// It modifies the given document.
+func shadowNormalize(res *wirebson.Document) *wirebson.Document {
+	for _, f := range shadowVolatileFields {
+		res.Remove(f)
+	}
+
+	if ok, _ := res.Get("ok").(float64); ok != 1 {
+		code := res.Get("code")
+		if code == nil {
+			code = wirebson.Null
+		}
+
+		return wirebson.MustDocument("ok", ok, "code", code)
+	}
+
+	if writeErrors, ok := res.Get("writeErrors").(*wirebson.Array); ok {
+		for i := range writeErrors.Len() {
+			if we, ok := writeErrors.Get(i).(*wirebson.Document); ok {
+				we.Remove("errmsg")
+			}
+		}
+	}
+
+	if cursor, ok := res.Get("cursor").(*wirebson.Document); ok {
+		if id, ok := cursor.Get("id").(int64); ok {
+			_ = cursor.Replace("id", id != 0)
+		}
+	}
+
+	return res
+}
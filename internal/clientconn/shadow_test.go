@@ -0,0 +1,117 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShadowEqual(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		res, shadowRes *wirebson.Document
+		expected       bool
+	}{
+		"Cursor": {
+			res: wirebson.MustDocument(
+				"cursor", wirebson.MustDocument("firstBatch", wirebson.MustArray(int32(1)), "id", int64(42), "ns", "db.c"),
+				"ok", float64(1),
+			),
+			shadowRes: wirebson.MustDocument(
+				"cursor", wirebson.MustDocument("firstBatch", wirebson.MustArray(int32(1)), "id", int64(7), "ns", "db.c"),
+				"ok", float64(1),
+				"$clusterTime", wirebson.MustDocument("clusterTime", wirebson.Timestamp(1)),
+				"operationTime", wirebson.Timestamp(1),
+			),
+			expected: true,
+		},
+		"CursorExhausted": {
+			res: wirebson.MustDocument(
+				"cursor", wirebson.MustDocument("firstBatch", wirebson.MustArray(), "id", int64(42), "ns", "db.c"),
+				"ok", float64(1),
+			),
+			shadowRes: wirebson.MustDocument(
+				"cursor", wirebson.MustDocument("firstBatch", wirebson.MustArray(), "id", int64(0), "ns", "db.c"),
+				"ok", float64(1),
+			),
+		},
+		"Type": {
+			res:       wirebson.MustDocument("n", int32(1), "ok", float64(1)),
+			shadowRes: wirebson.MustDocument("n", int64(1), "ok", float64(1)),
+		},
+		"ErrorMessage": {
+			res:       wirebson.MustDocument("ok", float64(0), "errmsg", "foo", "code", int32(2), "codeName", "BadValue"),
+			shadowRes: wirebson.MustDocument("ok", float64(0), "errmsg", "bar", "code", int32(2), "codeName", "BadValue"),
+			expected:  true,
+		},
+		"ErrorCode": {
+			res:       wirebson.MustDocument("ok", float64(0), "errmsg", "foo", "code", int32(2), "codeName", "BadValue"),
+			shadowRes: wirebson.MustDocument("ok", float64(0), "errmsg", "foo", "code", int32(9), "codeName", "FailedToParse"),
+		},
+		"WriteErrors": {
+			res: wirebson.MustDocument(
+				"n", int32(0),
+				"writeErrors", wirebson.MustArray(wirebson.MustDocument("index", int32(0), "code", int32(11000), "errmsg", "foo")),
+				"ok", float64(1),
+			),
+			shadowRes: wirebson.MustDocument(
+				"n", int32(0),
+				"writeErrors", wirebson.MustArray(wirebson.MustDocument("index", int32(0), "code", int32(11000), "errmsg", "bar")),
+				"ok", float64(1),
+			),
+			expected: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, shadowEqual(tc.res, tc.shadowRes))
+		})
+	}
+}
+
+func TestShadowCursors(t *testing.T) {
+	t.Parallel()
+
+	s := &shadow{cursors: map[int64]int64{}}
+
+	find := wirebson.MustDocument("find", "c", "$db", "db")
+	res := wirebson.MustDocument("cursor", wirebson.MustDocument("id", int64(42)), "ok", float64(1))
+	shadowRes := wirebson.MustDocument("cursor", wirebson.MustDocument("id", int64(7)), "ok", float64(1))
+	s.trackCursors("find", find, res, shadowRes)
+	assert.Equal(t, map[int64]int64{42: 7}, s.cursors)
+
+	getMore := wirebson.MustDocument("getMore", int64(42), "collection", "c", "$db", "db")
+	require.True(t, s.rewriteCursors(getMore))
+	assert.Equal(t, int64(7), getMore.Get("getMore"))
+
+	unknown := wirebson.MustDocument("getMore", int64(1), "collection", "c", "$db", "db")
+	assert.False(t, s.rewriteCursors(unknown))
+
+	raw, err := wirebson.MustDocument("killCursors", "c", "cursors", wirebson.MustArray(int64(42), int64(1)), "$db", "db").Encode()
+	require.NoError(t, err)
+
+	killCursors, err := raw.Decode()
+	require.NoError(t, err)
+
+	require.True(t, s.rewriteCursors(killCursors))
+	assert.Equal(t, wirebson.MustArray(int64(7), int64(1)), killCursors.Get("cursors"))
+	assert.Empty(t, s.cursors)
+}
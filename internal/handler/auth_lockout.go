@@ -0,0 +1,335 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+)
+
+// authLockoutMaxEntries is the maximum number of tracked username/address pairs.
+const authLockoutMaxEntries = 10_000
+
+// authLockoutKey identifies tracked authentication attempts.
+type authLockoutKey struct {
+	username string
+	addr     netip.Addr // invalid for Unix domain sockets
+}
+
+// authLockoutEntry represents failed authentication attempts for a single key.
+type authLockoutEntry struct {
+	failures    int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// authLockoutOpts represents authLockout configuration.
+type authLockoutOpts struct {
+	// Delay is the delay after the first failed attempt; it doubles after each next failure
+	// up to MaxDelay. Zero value disables delays.
+	Delay    time.Duration
+	MaxDelay time.Duration
+
+	// Threshold is the number of consecutive failed attempts after which further attempts
+	// are rejected for Duration. Zero value disables lockouts.
+	Threshold int
+	Duration  time.Duration
+}
+
+// authLockout tracks failed authentication attempts per username and client address.
+//
+// Failures are forgotten after a successful authentication,
+// or when there were no attempts for the lockout duration (or the maximum delay, if lockouts are disabled).
+// When the maximum number of entries is reached, the least recently failed entries that are not locked out are evicted.
+type authLockout struct {
+	opts *authLockoutOpts
+
+	rw         sync.RWMutex
+	entries    map[authLockoutKey]*authLockoutEntry
+	maxEntries int
+
+	failures *prometheus.CounterVec
+	lockouts prometheus.Counter
+
+	now func() time.Time
+}
+
+// newAuthLockout creates a new authLockout.
+func newAuthLockout(opts *authLockoutOpts) *authLockout {
+	return &authLockout{
+		opts:       opts,
+		entries:    map[authLockoutKey]*authLockoutEntry{},
+		maxEntries: authLockoutMaxEntries,
+		failures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "auth_failures_total",
+				Help:      "Total number of failed authentication attempts.",
+			},
+			[]string{"locked"},
+		),
+		lockouts: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "auth_lockouts_total",
+				Help:      "Total number of temporary authentication lockouts.",
+			},
+		),
+		now: time.Now,
+	}
+}
+
+// enabled returns true if failed attempts should be tracked.
+func (al *authLockout) enabled() bool {
+	return al.opts.Delay > 0 || al.opts.Threshold > 0
+}
+
+// resetAfter returns the duration without attempts after which failures are forgotten.
+func (al *authLockout) resetAfter() time.Duration {
+	return max(al.opts.Duration, al.opts.MaxDelay)
+}
+
+// lockedUntil returns the time until which authentication attempts for the given key are rejected,
+// or zero time if they are allowed.
+func (al *authLockout) lockedUntil(key authLockoutKey) time.Time {
+	if !al.enabled() {
+		return time.Time{}
+	}
+
+	al.rw.RLock()
+	defer al.rw.RUnlock()
+
+	e := al.entries[key]
+	if e == nil || !al.now().Before(e.lockedUntil) {
+		return time.Time{}
+	}
+
+	return e.lockedUntil
+}
+
+// failed records a failed authentication attempt, or a rejected attempt if locked is true.
+//
+// It returns the delay before the failure should be reported to the client,
+// and true if that attempt locked further attempts out.
+func (al *authLockout) failed(key authLockoutKey, locked bool) (time.Duration, bool) {
+	labels := prometheus.Labels{"locked": "false"}
+	if locked {
+		labels["locked"] = "true"
+	}
+
+	al.failures.With(labels).Inc()
+
+	if !al.enabled() || locked {
+		return 0, false
+	}
+
+	now := al.now()
+
+	al.rw.Lock()
+	defer al.rw.Unlock()
+
+	e := al.entries[key]
+	if e == nil && !al.makeRoom(now) {
+		// all entries are locked out; the attempt is not tracked, but still delayed
+		return al.opts.Delay, false
+	}
+
+	if e == nil || now.Sub(e.last) > al.resetAfter() {
+		e = new(authLockoutEntry)
+		al.entries[key] = e
+	}
+
+	e.failures++
+	e.last = now
+
+	var delay time.Duration
+
+	if al.opts.Delay > 0 {
+		delay = al.opts.Delay << min(e.failures-1, 30)
+		if delay <= 0 || delay > al.opts.MaxDelay {
+			delay = al.opts.MaxDelay
+		}
+	}
+
+	if al.opts.Threshold > 0 && e.failures >= al.opts.Threshold {
+		e.failures = 0
+		e.lockedUntil = now.Add(al.opts.Duration)
+
+		al.lockouts.Inc()
+
+		return delay, true
+	}
+
+	return delay, false
+}
+
+// succeeded forgets failed authentication attempts for the given key.
+func (al *authLockout) succeeded(key authLockoutKey) {
+	if !al.enabled() {
+		return
+	}
+
+	// that is called after every successful command authenticated with the PLAIN mechanism,
+	// so avoid taking the write lock if there is nothing to forget
+	al.rw.RLock()
+	_, ok := al.entries[key]
+	al.rw.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	al.rw.Lock()
+	defer al.rw.Unlock()
+
+	delete(al.entries, key)
+}
+
+// makeRoom ensures that a new entry could be added without exceeding the maximum number of entries.
+//
+// It removes expired entries first, then the least recently failed entry that is not locked out.
+// It returns false if there is still no room because all entries are locked out.
+//
+// It should be called with the write lock held.
+func (al *authLockout) makeRoom(now time.Time) bool {
+	if len(al.entries) < al.maxEntries {
+		return true
+	}
+
+	resetAfter := al.resetAfter()
+
+	var oldestKey authLockoutKey
+	var oldest *authLockoutEntry
+
+	for k, e := range al.entries {
+		if now.Before(e.lockedUntil) {
+			continue
+		}
+
+		if now.Sub(e.last) > resetAfter {
+			delete(al.entries, k)
+			continue
+		}
+
+		if oldest == nil || e.last.Before(oldest.last) {
+			oldestKey, oldest = k, e
+		}
+	}
+
+	if len(al.entries) < al.maxEntries {
+		return true
+	}
+
+	if oldest == nil {
+		return false
+	}
+
+	delete(al.entries, oldestKey)
+
+	return true
+}
+
+// Describe implements [prometheus.Collector].
+func (al *authLockout) Describe(ch chan<- *prometheus.Desc) {
+	al.failures.Describe(ch)
+	al.lockouts.Describe(ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (al *authLockout) Collect(ch chan<- prometheus.Metric) {
+	al.failures.Collect(ch)
+	al.lockouts.Collect(ch)
+}
+
+// authLockoutKeyFor returns the key for the given user and the client from the context.
+func authLockoutKeyFor(ctx context.Context, dbName, username string) authLockoutKey {
+	return authLockoutKey{
+		username: dbName + "." + username,
+		addr:     conninfo.Get(ctx).Peer.Addr(),
+	}
+}
+
+// checkAuthLockout returns an error if authentication attempts of the given user
+// from the client are temporarily locked out.
+func (h *Handler) checkAuthLockout(ctx context.Context, dbName, username string) error {
+	until := h.authLockout.lockedUntil(authLockoutKeyFor(ctx, dbName, username))
+	if until.IsZero() {
+		return nil
+	}
+
+	h.auditL.WarnContext(
+		ctx, "Authentication attempt rejected due to lockout",
+		slog.String("user", username), slog.String("db", dbName),
+		slog.String("peer", conninfo.Get(ctx).Peer.String()), slog.Time("locked_until", until),
+	)
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrAuthenticationFailed,
+		"Authentication failed.",
+		"checkAuthLockout",
+	)
+}
+
+// authFailed records a failed authentication attempt, logs it, and waits for the configured delay.
+// The locked argument should be true if the attempt was rejected by [Handler.checkAuthLockout].
+func (h *Handler) authFailed(ctx context.Context, dbName, username string, locked bool) {
+	delay, lockedOut := h.authLockout.failed(authLockoutKeyFor(ctx, dbName, username), locked)
+
+	if locked {
+		return
+	}
+
+	attrs := []any{
+		slog.String("user", username), slog.String("db", dbName),
+		slog.String("peer", conninfo.Get(ctx).Peer.String()), slog.Duration("delay", delay),
+	}
+
+	if lockedOut {
+		h.auditL.WarnContext(ctx, "Authentication failed, further attempts are locked out", append(
+			attrs, slog.Duration("lockout", h.AuthLockoutDuration),
+		)...)
+	} else {
+		h.auditL.WarnContext(ctx, "Authentication failed", attrs...)
+	}
+
+	ctxutil.Sleep(ctx, delay)
+}
+
+// authSucceeded forgets failed authentication attempts and logs a successful one.
+func (h *Handler) authSucceeded(ctx context.Context, dbName, username string) {
+	h.authLockout.succeeded(authLockoutKeyFor(ctx, dbName, username))
+
+	h.auditL.InfoContext(
+		ctx, "Authentication succeeded",
+		slog.String("user", username), slog.String("db", dbName),
+		slog.String("peer", conninfo.Get(ctx).Peer.String()),
+	)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*authLockout)(nil)
+)
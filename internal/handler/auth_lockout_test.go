@@ -0,0 +1,228 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestAuthLockout(t *testing.T) {
+	t.Parallel()
+
+	al := newAuthLockout(&authLockoutOpts{
+		Delay:     time.Second,
+		MaxDelay:  3 * time.Second,
+		Threshold: 4,
+		Duration:  time.Minute,
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	al.now = func() time.Time { return now }
+
+	key := authLockoutKey{username: "admin.user", addr: netip.MustParseAddr("192.0.2.1")}
+	other := authLockoutKey{username: "admin.user", addr: netip.MustParseAddr("192.0.2.2")}
+
+	for i, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		delay, locked := al.failed(key, false)
+		assert.Equal(t, expected, delay, "attempt %d", i+1)
+		assert.False(t, locked, "attempt %d", i+1)
+		assert.Zero(t, al.lockedUntil(key))
+	}
+
+	delay, locked := al.failed(key, false)
+	assert.Equal(t, 3*time.Second, delay)
+	assert.True(t, locked)
+	assert.Equal(t, now.Add(time.Minute), al.lockedUntil(key))
+	assert.Zero(t, al.lockedUntil(other), "other addresses are not locked out")
+
+	delay, locked = al.failed(key, true)
+	assert.Zero(t, delay)
+	assert.False(t, locked)
+
+	now = now.Add(time.Minute)
+	assert.Zero(t, al.lockedUntil(key))
+
+	delay, _ = al.failed(key, false)
+	assert.Equal(t, time.Second, delay, "failures are reset after lockout")
+
+	delay, _ = al.failed(key, false)
+	assert.Equal(t, 2*time.Second, delay)
+
+	al.succeeded(key)
+
+	delay, _ = al.failed(key, false)
+	assert.Equal(t, time.Second, delay, "failures are reset after success")
+
+	now = now.Add(2 * time.Minute)
+
+	delay, _ = al.failed(key, false)
+	assert.Equal(t, time.Second, delay, "failures are forgotten after a while")
+}
+
+func TestAuthLockoutDisabled(t *testing.T) {
+	t.Parallel()
+
+	al := newAuthLockout(&authLockoutOpts{MaxDelay: time.Second, Duration: time.Minute})
+	key := authLockoutKey{username: "admin.user"}
+
+	for range 10 {
+		delay, locked := al.failed(key, false)
+		assert.Zero(t, delay)
+		assert.False(t, locked)
+	}
+
+	assert.Zero(t, al.lockedUntil(key))
+	assert.Empty(t, al.entries)
+}
+
+func TestAuthLockoutMaxEntries(t *testing.T) {
+	t.Parallel()
+
+	al := newAuthLockout(&authLockoutOpts{
+		Delay:     time.Second,
+		MaxDelay:  3 * time.Second,
+		Threshold: 2,
+		Duration:  time.Minute,
+	})
+	al.maxEntries = 3
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	al.now = func() time.Time { return now }
+
+	key := func(i int) authLockoutKey {
+		return authLockoutKey{username: fmt.Sprintf("admin.user%d", i), addr: netip.MustParseAddr("192.0.2.1")}
+	}
+
+	for i := range 10 {
+		now = now.Add(time.Second)
+
+		al.failed(key(i), false)
+		assert.LessOrEqual(t, len(al.entries), al.maxEntries)
+	}
+
+	assert.Contains(t, al.entries, key(9))
+	assert.NotContains(t, al.entries, key(6), "the oldest entries are evicted")
+
+	for i := range 3 {
+		_, locked := al.failed(key(i), false)
+		assert.False(t, locked)
+
+		_, locked = al.failed(key(i), false)
+		assert.True(t, locked)
+	}
+
+	delay, locked := al.failed(key(100), false)
+	assert.Equal(t, time.Second, delay, "new attempts are still delayed")
+	assert.False(t, locked)
+	assert.NotContains(t, al.entries, key(100), "locked out entries are not evicted")
+	assert.Len(t, al.entries, al.maxEntries)
+
+	for i := range 3 {
+		assert.NotZero(t, al.lockedUntil(key(i)))
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	al.failed(key(100), false)
+	assert.Contains(t, al.entries, key(100), "expired entries are removed")
+	assert.LessOrEqual(t, len(al.entries), al.maxEntries)
+}
+
+// plainAuthBackend is a backend that rejects credentials other than "password".
+type plainAuthBackend struct {
+	backends.Backend
+}
+
+// ListDatabases implements backends.Backend interface.
+func (b *plainAuthBackend) ListDatabases(ctx context.Context, params *backends.ListDatabasesParams) (*backends.ListDatabasesResult, error) { //nolint:lll // for readability
+	if _, password, _, _ := conninfo.Get(ctx).Auth(); password != "password" {
+		return nil, lazyerrors.Error(backends.ErrAuthenticationFailed)
+	}
+
+	return b.Backend.ListDatabases(ctx, params)
+}
+
+func TestAuthLockoutPLAIN(t *testing.T) {
+	t.Parallel()
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := memory.NewBackend(&memory.NewBackendParams{L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+
+	t.Cleanup(b.Close)
+
+	h := newTestHandler(t, &NewOpts{
+		Backend:              &plainAuthBackend{Backend: b},
+		StateProvider:        sp,
+		AuthLockoutThreshold: 2,
+		AuthLockoutDuration:  time.Minute,
+	})
+
+	// attempt authenticates a new connection with the PLAIN mechanism and runs a command
+	attempt := func(username, password string) error {
+		ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+		payload := types.Binary{B: []byte("\x00" + username + "\x00" + password)}
+		if err := h.saslStartPlain(ctx, "admin", must.NotFail(types.NewDocument("payload", payload))); err != nil {
+			return err
+		}
+
+		_, err := h.Commands()["listDatabases"].Handler(ctx, wire.MustOpMsg("listDatabases", int32(1), "$db", "admin"))
+
+		return err
+	}
+
+	t.Run("Lockout", func(t *testing.T) {
+		t.Parallel()
+
+		for range 2 {
+			assert.ErrorIs(t, attempt("alice", "wrong"), backends.ErrAuthenticationFailed)
+		}
+
+		var cmdErr *handlererrors.CommandError
+		require.ErrorAs(t, attempt("alice", "password"), &cmdErr)
+		assert.Equal(t, handlererrors.ErrAuthenticationFailed, cmdErr.Code())
+	})
+
+	t.Run("Succeeded", func(t *testing.T) {
+		t.Parallel()
+
+		assert.ErrorIs(t, attempt("bob", "wrong"), backends.ErrAuthenticationFailed)
+		require.NoError(t, attempt("bob", "password"))
+
+		assert.ErrorIs(t, attempt("bob", "wrong"), backends.ErrAuthenticationFailed)
+		require.NoError(t, attempt("bob", "password"), "failures are reset after success")
+	})
+}
@@ -19,8 +19,6 @@ import (
 	"fmt"
 	"log/slog"
 
-	"github.com/FerretDB/wire"
-
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 )
@@ -30,10 +28,20 @@ type command struct {
 	// anonymous indicates that the command does not require authentication.
 	anonymous bool
 
+	// adminOnly indicates that the command should be run against the admin database.
+	adminOnly bool
+
+	// secondaryOk indicates that the command does not modify data
+	// and could be run on a secondary replica set member.
+	secondaryOk bool
+
+	// stableAPI indicates that the command is a part of Stable API version 1.
+	stableAPI bool
+
 	// Handler processes this command.
 	//
 	// The passed context is canceled when the client disconnects.
-	Handler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error)
+	Handler CommandFunc
 
 	// Help is shown in the `listCommands` command output.
 	// If empty, that command is hidden, but still can be used.
@@ -45,212 +53,399 @@ func (h *Handler) initCommands() {
 	h.commands = map[string]*command{
 		// sorted alphabetically
 		"aggregate": {
-			Handler: h.MsgAggregate,
-			Help:    "Returns aggregated data.",
+			Handler:     h.MsgAggregate,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns aggregated data.",
+		},
+		"analyze": {
+			Handler: h.MsgAnalyze,
+			Help:    "Refreshes the statistics of a collection used for query planning.",
+		},
+		"appendOplogNote": {
+			Handler:   h.MsgAppendOplogNote,
+			adminOnly: true,
+			Help:      "Adds a no-op note to the OpLog.",
 		},
 		"buildInfo": {
-			Handler:   h.MsgBuildInfo,
-			anonymous: true,
-			Help:      "Returns a summary of the build information.",
+			Handler:     h.MsgBuildInfo,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "Returns a summary of the build information.",
 		},
 		"buildinfo": { // old lowercase variant
-			Handler:   h.MsgBuildInfo,
-			anonymous: true,
-			Help:      "", // hidden
+			Handler:     h.MsgBuildInfo,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "", // hidden
 		},
 		"collMod": {
-			Handler: h.MsgCollMod,
-			Help:    "Adds options to a collection or modify view definitions.",
+			Handler:   h.MsgCollMod,
+			stableAPI: true,
+			Help:      "Adds options to a collection or modify view definitions.",
 		},
 		"collStats": {
-			Handler: h.MsgCollStats,
-			Help:    "Returns storage data for a collection.",
+			Handler:     h.MsgCollStats,
+			secondaryOk: true,
+			Help:        "Returns storage data for a collection.",
 		},
 		"compact": {
 			Handler: h.MsgCompact,
 			Help:    "Reduces the disk space collection takes and refreshes its statistics.",
 		},
 		"connectionStatus": {
-			Handler:   h.MsgConnectionStatus,
-			anonymous: true,
+			Handler:     h.MsgConnectionStatus,
+			anonymous:   true,
+			secondaryOk: true,
 			Help: "Returns information about the current connection, " +
 				"specifically the state of authenticated users and their available permissions.",
 		},
+		"copydb": {
+			Handler: h.MsgCopyDB,
+			Help:    "", // hidden, removed in MongoDB 4.2
+		},
 		"count": {
-			Handler: h.MsgCount,
-			Help:    "Returns the count of documents that's matched by the query.",
+			Handler:     h.MsgCount,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns the count of documents that's matched by the query.",
 		},
 		"create": {
-			Handler: h.MsgCreate,
-			Help:    "Creates the collection.",
+			Handler:   h.MsgCreate,
+			stableAPI: true,
+			Help:      "Creates the collection.",
+		},
+		"createBackup": {
+			Handler:     h.MsgCreateBackup,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Creates a logical backup of a database in the configured storage.",
 		},
 		"createIndexes": {
-			Handler: h.MsgCreateIndexes,
-			Help:    "Creates indexes on a collection.",
+			Handler:   h.MsgCreateIndexes,
+			stableAPI: true,
+			Help:      "Creates indexes on a collection.",
 		},
 		"currentOp": {
-			Handler: h.MsgCurrentOp,
-			Help:    "Returns information about operations currently in progress.",
+			Handler:     h.MsgCurrentOp,
+			secondaryOk: true,
+			Help:        "Returns information about operations currently in progress.",
 		},
 		"dataSize": {
-			Handler: h.MsgDataSize,
-			Help:    "Returns the size of the collection in bytes.",
+			Handler:     h.MsgDataSize,
+			secondaryOk: true,
+			Help:        "Returns the size of the collection in bytes.",
+		},
+		"dbHash": {
+			Handler:     h.MsgDBHash,
+			secondaryOk: true,
+			Help:        "Returns the hash values of collections in the database.",
 		},
 		"dbStats": {
-			Handler: h.MsgDBStats,
-			Help:    "Returns the statistics of the database.",
+			Handler:     h.MsgDBStats,
+			secondaryOk: true,
+			Help:        "Returns the statistics of the database.",
 		},
 		"dbstats": { // old lowercase variant
-			Handler: h.MsgDBStats,
-			Help:    "", // hidden
+			Handler:     h.MsgDBStats,
+			secondaryOk: true,
+			Help:        "", // hidden
 		},
 		"debugError": {
-			Handler: h.MsgDebugError,
-			Help:    "Returns error for debugging.",
+			Handler:     h.MsgDebugError,
+			secondaryOk: true,
+			Help:        "Returns error for debugging.",
 		},
 		"delete": {
-			Handler: h.MsgDelete,
-			Help:    "Deletes documents matched by the query.",
+			Handler:   h.MsgDelete,
+			stableAPI: true,
+			Help:      "Deletes documents matched by the query.",
 		},
 		"distinct": {
-			Handler: h.MsgDistinct,
-			Help:    "Returns an array of distinct values for the given field.",
+			Handler:     h.MsgDistinct,
+			secondaryOk: true,
+			Help:        "Returns an array of distinct values for the given field.",
 		},
 		"drop": {
-			Handler: h.MsgDrop,
-			Help:    "Drops the collection.",
+			Handler:   h.MsgDrop,
+			stableAPI: true,
+			Help:      "Drops the collection.",
 		},
 		"dropDatabase": {
-			Handler: h.MsgDropDatabase,
-			Help:    "Drops production database.",
+			Handler:   h.MsgDropDatabase,
+			stableAPI: true,
+			Help:      "Drops production database.",
 		},
 		"dropIndexes": {
-			Handler: h.MsgDropIndexes,
-			Help:    "Drops indexes on a collection.",
+			Handler:   h.MsgDropIndexes,
+			stableAPI: true,
+			Help:      "Drops indexes on a collection.",
+		},
+		"enableSharding": {
+			Handler:   h.MsgEnableSharding,
+			adminOnly: true,
+			Help:      "Enables sharding on a database.",
+		},
+		"endSessions": {
+			Handler:     h.MsgEndSessions,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Expires logical sessions.",
 		},
 		"explain": {
-			Handler: h.MsgExplain,
-			Help:    "Returns the execution plan.",
+			Handler:     h.MsgExplain,
+			secondaryOk: true,
+			Help:        "Returns the execution plan.",
+		},
+		"exportCollection": {
+			Handler:     h.MsgExportCollection,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Exports a collection to the configured storage.",
+		},
+		"ferretdbFeatures": {
+			Handler:     h.MsgFerretDBFeatures,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Returns supported operators, aggregation stages, and index types.",
 		},
 		"find": {
-			Handler: h.MsgFind,
-			Help:    "Returns documents matched by the query.",
+			Handler:     h.MsgFind,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns documents matched by the query.",
 		},
 		"findAndModify": {
-			Handler: h.MsgFindAndModify,
-			Help:    "Updates or deletes, and returns a document matched by the query.",
+			Handler:   h.MsgFindAndModify,
+			stableAPI: true,
+			Help:      "Updates or deletes, and returns a document matched by the query.",
 		},
 		"findandmodify": { // old lowercase variant
 			Handler: h.MsgFindAndModify,
 			Help:    "", // hidden
 		},
 		"getCmdLineOpts": {
-			Handler: h.MsgGetCmdLineOpts,
-			Help:    "Returns a summary of all runtime and configuration options.",
+			Handler:     h.MsgGetCmdLineOpts,
+			secondaryOk: true,
+			Help:        "Returns a summary of all runtime and configuration options.",
 		},
 		"getFreeMonitoringStatus": {
-			Handler: h.MsgGetFreeMonitoringStatus,
-			Help:    "Returns a status of the free monitoring.",
+			Handler:     h.MsgGetFreeMonitoringStatus,
+			secondaryOk: true,
+			Help:        "Returns a status of the free monitoring.",
+		},
+		"getLastError": {
+			Handler:     h.MsgGetLastError,
+			secondaryOk: true,
+			Help:        "Returns the error status of the preceding write operation (deprecated).",
+		},
+		"getlasterror": { // old lowercase variant
+			Handler:     h.MsgGetLastError,
+			secondaryOk: true,
+			Help:        "", // hidden
 		},
 		"getLog": {
-			Handler: h.MsgGetLog,
-			Help:    "Returns the most recent logged events from memory.",
+			Handler:     h.MsgGetLog,
+			secondaryOk: true,
+			Help:        "Returns the most recent logged events from memory.",
 		},
 		"getMore": {
-			Handler: h.MsgGetMore,
-			Help:    "Returns the next batch of documents from a cursor.",
+			Handler:     h.MsgGetMore,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns the next batch of documents from a cursor.",
+		},
+		"getShardMap": {
+			Handler:     h.MsgGetShardMap,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Returns the map of shards.",
 		},
 		"getParameter": {
-			Handler: h.MsgGetParameter,
-			Help:    "Returns the value of the parameter.",
+			Handler:     h.MsgGetParameter,
+			secondaryOk: true,
+			Help:        "Returns the value of the parameter.",
 		},
 		"hello": {
-			Handler:   h.MsgHello,
-			anonymous: true,
-			Help:      "Returns the role of the FerretDB instance.",
+			Handler:     h.MsgHello,
+			anonymous:   true,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns the role of the FerretDB instance.",
 		},
 		"hostInfo": {
-			Handler: h.MsgHostInfo,
-			Help:    "Returns a summary of the system information.",
+			Handler:     h.MsgHostInfo,
+			secondaryOk: true,
+			Help:        "Returns a summary of the system information.",
+		},
+		"importCollection": {
+			Handler:   h.MsgImportCollection,
+			adminOnly: true,
+			Help:      "Imports a collection from the configured storage.",
 		},
 		"insert": {
-			Handler: h.MsgInsert,
-			Help:    "Inserts documents into the database.",
+			Handler:   h.MsgInsert,
+			stableAPI: true,
+			Help:      "Inserts documents into the database.",
 		},
 		"isMaster": {
-			Handler:   h.MsgIsMaster,
-			anonymous: true,
-			Help:      "Returns the role of the FerretDB instance.",
+			Handler:     h.MsgIsMaster,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "Returns the role of the FerretDB instance.",
 		},
 		"ismaster": { // old lowercase variant
-			Handler:   h.MsgIsMaster,
-			anonymous: true,
-			Help:      "", // hidden
+			Handler:     h.MsgIsMaster,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "", // hidden
+		},
+		"killAllSessions": {
+			Handler:     h.MsgKillAllSessions,
+			secondaryOk: true,
+			Help:        "Kills all logical sessions of the given users.",
 		},
 		"killCursors": {
-			Handler: h.MsgKillCursors,
-			Help:    "Closes server cursors.",
+			Handler:     h.MsgKillCursors,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Closes server cursors.",
+		},
+		"killOp": {
+			Handler:     h.MsgKillOp,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Terminates an operation as specified by the operation ID.",
+		},
+		"killSessions": {
+			Handler:     h.MsgKillSessions,
+			secondaryOk: true,
+			Help:        "Kills the given logical sessions.",
 		},
 		"listCollections": {
-			Handler: h.MsgListCollections,
-			Help:    "Returns the information of the collections and views in the database.",
+			Handler:     h.MsgListCollections,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns the information of the collections and views in the database.",
 		},
 		"listCommands": {
-			Handler: h.MsgListCommands,
-			Help:    "Returns a list of currently supported commands.",
+			Handler:     h.MsgListCommands,
+			secondaryOk: true,
+			Help:        "Returns a list of currently supported commands.",
 		},
 		"listDatabases": {
-			Handler: h.MsgListDatabases,
-			Help:    "Returns a summary of all the databases.",
+			Handler:     h.MsgListDatabases,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns a summary of all the databases.",
 		},
 		"listIndexes": {
-			Handler: h.MsgListIndexes,
-			Help:    "Returns a summary of indexes of the specified collection.",
+			Handler:     h.MsgListIndexes,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns a summary of indexes of the specified collection.",
+		},
+		"listShards": {
+			Handler:     h.MsgListShards,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Returns a list of shards.",
 		},
 		"logout": {
-			Handler:   h.MsgLogout,
-			anonymous: true,
-			Help:      "Logs out from the current session.",
+			Handler:     h.MsgLogout,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "Logs out from the current session.",
 		},
 		"ping": {
-			Handler:   h.MsgPing,
-			anonymous: true,
-			Help:      "Returns a pong response.",
+			Handler:     h.MsgPing,
+			anonymous:   true,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Returns a pong response.",
+		},
+		"refreshSessions": {
+			Handler:     h.MsgRefreshSessions,
+			secondaryOk: true,
+			stableAPI:   true,
+			Help:        "Updates the last-use time of the given logical sessions.",
 		},
 		"renameCollection": {
 			Handler: h.MsgRenameCollection,
 			Help:    "Changes the name of an existing collection.",
 		},
+		"replSetStepDown": {
+			Handler:     h.MsgReplSetStepDown,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Asks the primary member of the replica set to step down; a no-op for FerretDB.",
+		},
+		"resetError": {
+			Handler: h.MsgResetError,
+			Help:    "Resets the last error status (deprecated).",
+		},
+		"restoreBackup": {
+			Handler:   h.MsgRestoreBackup,
+			adminOnly: true,
+			Help:      "Restores a database from a logical backup in the configured storage.",
+		},
 		"saslStart": {
-			Handler:   h.MsgSASLStart,
-			anonymous: true,
-			Help:      "", // hidden
+			Handler:     h.MsgSASLStart,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "", // hidden
 		},
 		"saslContinue": {
-			Handler:   h.MsgSASLContinue,
-			anonymous: true,
-			Help:      "", // hidden
+			Handler:     h.MsgSASLContinue,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "", // hidden
 		},
 		"serverStatus": {
-			Handler: h.MsgServerStatus,
-			Help:    "Returns an overview of the databases state.",
+			Handler:     h.MsgServerStatus,
+			secondaryOk: true,
+			Help:        "Returns an overview of the databases state.",
 		},
 		"setFreeMonitoring": {
 			Handler: h.MsgSetFreeMonitoring,
 			Help:    "Toggles free monitoring.",
 		},
+		"setParameter": {
+			Handler:   h.MsgSetParameter,
+			adminOnly: true,
+			Help:      "Sets the value of a parameter.",
+		},
+		"shardCollection": {
+			Handler:   h.MsgShardCollection,
+			adminOnly: true,
+			Help:      "Shards a collection.",
+		},
+		"shutdown": {
+			Handler:     h.MsgShutdown,
+			adminOnly:   true,
+			secondaryOk: true,
+			Help:        "Waits for operations in progress to finish and stops FerretDB.",
+		},
+		"startSession": {
+			Handler:     h.MsgStartSession,
+			secondaryOk: true,
+			Help:        "Starts a new logical session.",
+		},
 		"update": {
-			Handler: h.MsgUpdate,
-			Help:    "Updates documents that are matched by the query.",
+			Handler:   h.MsgUpdate,
+			stableAPI: true,
+			Help:      "Updates documents that are matched by the query.",
 		},
 		"validate": {
-			Handler: h.MsgValidate,
-			Help:    "Validates collection.",
+			Handler:     h.MsgValidate,
+			secondaryOk: true,
+			Help:        "Validates collection.",
 		},
 		"whatsmyuri": {
-			Handler:   h.MsgWhatsMyURI,
-			anonymous: true,
-			Help:      "Returns peer information.",
+			Handler:     h.MsgWhatsMyURI,
+			anonymous:   true,
+			secondaryOk: true,
+			Help:        "Returns peer information.",
 		},
 		// please keep sorted alphabetically
 	}
@@ -274,24 +469,22 @@ func (h *Handler) initCommands() {
 			Help:    "Updates user.",
 		}
 		h.commands["usersInfo"] = &command{
-			Handler: h.MsgUsersInfo,
-			Help:    "Returns information about users.",
+			Handler:     h.MsgUsersInfo,
+			secondaryOk: true,
+			Help:        "Returns information about users.",
 		}
 		// please keep sorted alphabetically
 	}
 
 	for name, cmd := range h.commands {
-		if h.EnableNewAuth && !cmd.anonymous {
-			cmdHandler := h.commands[name].Handler
-
-			h.commands[name].Handler = func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
-				if err := checkSCRAMConversation(ctx, name, h.L); err != nil {
-					return nil, err
-				}
-
-				return cmdHandler(ctx, msg)
-			}
+		info := &CommandInfo{
+			Name:      name,
+			Anonymous: cmd.anonymous,
+			AdminOnly: cmd.adminOnly,
+			Write:     !cmd.secondaryOk,
 		}
+
+		cmd.Handler = h.intercept(info, cmd.Handler)
 	}
 }
 
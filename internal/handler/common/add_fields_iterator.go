@@ -16,7 +16,10 @@ package common
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -41,6 +44,23 @@ func AddFieldsIterator(iter types.DocumentsIterator, closer *iterator.MultiClose
 	return res
 }
 
+// ValidateAddFields checks path expressions and variables of new fields
+// before any documents are processed.
+func ValidateAddFields(newField *types.Document) error {
+	for _, key := range newField.Keys() {
+		v, ok := must.NotFail(newField.Get(key)).(string)
+		if !ok || !strings.HasPrefix(v, "$") {
+			continue
+		}
+
+		if _, err := aggregations.NewExpression(v, nil); err != nil {
+			return processAddFieldsError(err)
+		}
+	}
+
+	return nil
+}
+
 // addFieldsIterator is returned by AddFieldsIterator.
 type addFieldsIterator struct {
 	iter     types.DocumentsIterator
@@ -74,6 +94,29 @@ func (iter *addFieldsIterator) Next() (struct{}, *types.Document, error) {
 			if err = processAddFieldsError(err); err != nil {
 				return unused, nil, err
 			}
+
+			// the field is removed if the operator returns the missing value
+			if val == nil {
+				doc.Remove(key)
+				continue
+			}
+
+		case string:
+			if !strings.HasPrefix(v, "$") {
+				break
+			}
+
+			expression, err := aggregations.NewExpression(v, nil)
+			if err = processAddFieldsError(err); err != nil {
+				return unused, nil, err
+			}
+
+			// the field is removed if the expression evaluates to the missing value,
+			// such as a path to the non-existent field or `$$REMOVE`
+			if val, err = expression.Evaluate(doc); err != nil {
+				doc.Remove(key)
+				continue
+			}
 		}
 
 		doc.Set(key, val)
@@ -94,6 +137,11 @@ func processAddFieldsError(err error) error {
 		return nil
 	}
 
+	var exErr *aggregations.ExpressionError
+	if errors.As(err, &exErr) {
+		return processAddFieldsExpressionError(exErr)
+	}
+
 	var opErr operators.OperatorError
 
 	if !errors.As(err, &opErr) {
@@ -132,11 +180,65 @@ func processAddFieldsError(err error) error {
 			"Invalid $addFields :: caused by :: "+opErr.Error(),
 			"$addFields (stage)",
 		)
+	case operators.ErrFailedToParse:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"Invalid $addFields :: caused by :: "+opErr.Error(),
+			"$addFields (stage)",
+		)
+	case operators.ErrBadValue:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Invalid $addFields :: caused by :: "+opErr.Error(),
+			"$addFields (stage)",
+		)
+	case operators.ErrConversionFailure:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrConversionFailure,
+			opErr.Error(),
+			"$addFields (stage)",
+		)
 	default:
 		return lazyerrors.Error(err)
 	}
 }
 
+// processAddFieldsExpressionError takes internal error related to path expression or variable evaluation and
+// returns proper CommandError that can be returned by $addFields aggregation stage.
+func processAddFieldsExpressionError(exErr *aggregations.ExpressionError) error {
+	switch exErr.Code() {
+	case aggregations.ErrEmptyFieldPath:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGroupInvalidFieldPath,
+			"Invalid $addFields :: caused by :: '$' by itself is not a valid FieldPath",
+			"$addFields (stage)",
+		)
+	case aggregations.ErrUndefinedVariable:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUndefinedVariable,
+			fmt.Sprintf("Invalid $addFields :: caused by :: Use of undefined variable: %s", exErr.Name()),
+			"$addFields (stage)",
+		)
+	case aggregations.ErrEmptyVariable:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"Invalid $addFields :: caused by :: empty variable names are not allowed",
+			"$addFields (stage)",
+		)
+	case aggregations.ErrNotExpression:
+		// handled by the caller and this should not be reachable
+		fallthrough
+	case aggregations.ErrInvalidExpression:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"Invalid $addFields :: caused by :: '$' starts with an invalid character for a user variable name",
+			"$addFields (stage)",
+		)
+	default:
+		return lazyerrors.Error(exErr)
+	}
+}
+
 // check interfaces
 var (
 	_ types.DocumentsIterator = (*addFieldsIterator)(nil)
@@ -15,6 +15,7 @@
 package aggregations
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -80,9 +81,13 @@ func (e *ExpressionError) Name() string {
 // Expression for access field in document should be prefixed with a dollar sign $ followed by field key.
 // For accessing embedded document or array, a dollar sign $ should be followed by dot notation.
 // Options can be provided to specify how to access fields in embedded array.
+//
+// System variables `$$ROOT` and `$$CURRENT` access the whole document or its fields,
+// `$$REMOVE` evaluates to the missing value.
 type Expression struct {
-	opts commonpath.FindValuesOpts
-	path types.Path
+	opts   commonpath.FindValuesOpts
+	path   types.Path
+	remove bool
 }
 
 // NewExpression returns Expression from dollar sign $ prefixed string.
@@ -112,8 +117,22 @@ func NewExpression(expression string, opts *commonpath.FindValuesOpts) (*Express
 			return nil, newExpressionError(ErrInvalidExpression, v)
 		}
 
-		// TODO https://github.com/FerretDB/FerretDB/issues/2275
-		return nil, newExpressionError(ErrUndefinedVariable, v)
+		name, rest, _ := strings.Cut(v, ".")
+
+		switch name {
+		case "ROOT", "CURRENT":
+			// there are no nested pipelines, so $$CURRENT is always the same as $$ROOT
+			if rest == "" {
+				return &Expression{opts: *opts}, nil
+			}
+
+			val = rest
+		case "REMOVE":
+			return &Expression{opts: *opts, remove: true}, nil
+		default:
+			// other variables are substituted before expression is created
+			return nil, newExpressionError(ErrUndefinedVariable, name)
+		}
 	case strings.HasPrefix(expression, "$"):
 		// dollar sign $ prefixed string indicates Expression accesses field or embedded fields
 		val = strings.TrimPrefix(expression, "$")
@@ -145,8 +164,16 @@ func NewExpression(expression string, opts *commonpath.FindValuesOpts) (*Express
 // It returns error if field value was not found. With embedded array field being exception,
 // that case it returns empty array instead of error.
 func (e *Expression) Evaluate(doc *types.Document) (any, error) {
+	if e.remove {
+		return nil, errors.New("$$REMOVE evaluates to missing value")
+	}
+
 	path := e.path
 
+	if path.Len() == 0 {
+		return doc.DeepCopy(), nil
+	}
+
 	if path.Len() == 1 {
 		val, err := doc.Get(path.String())
 		if err != nil {
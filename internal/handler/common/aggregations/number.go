@@ -17,12 +17,16 @@ package aggregations
 import (
 	"math"
 	"math/big"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
 )
 
 // SumNumbers accumulate numbers and returns the result of summation.
 // The result has the same type as the input, except when the result
 // cannot be presented accurately. Then int32 is converted to int64,
-// and int64 is converted to float64. It ignores non-number values.
+// and int64 is converted to float64. If any value is Decimal128,
+// the result is Decimal128. It ignores non-number values.
 // For empty `vs`, it returns int32(0).
 // This should only be used for aggregation, aggregation does not return
 // error on overflow.
@@ -34,7 +38,9 @@ func SumNumbers(vs ...any) any {
 	// TODO https://github.com/FerretDB/FerretDB/issues/2300
 	var floatSum float64
 
-	var hasFloat64, hasInt64 bool
+	decimalSum := types.NewDecimal128FromInt64(0)
+
+	var hasFloat64, hasInt64, hasDecimal bool
 
 	for _, v := range vs {
 		switch v := v.(type) {
@@ -48,11 +54,26 @@ func SumNumbers(vs ...any) any {
 			hasInt64 = true
 
 			intSum.Add(intSum, big.NewInt(v))
+		case types.Decimal128:
+			hasDecimal = true
+
+			decimalSum = decimalSum.Add(v)
 		default:
 			// ignore non-number
 		}
 	}
 
+	if hasDecimal {
+		neg := intSum.Sign() < 0
+		decimalSum = decimalSum.Add(types.NewDecimal128(neg, intSum.Abs(intSum), 0))
+
+		if hasFloat64 {
+			decimalSum = decimalSum.Add(types.NewDecimal128FromFloat64(floatSum))
+		}
+
+		return decimalSum
+	}
+
 	if hasFloat64 || !intSum.IsInt64() {
 		// ignore accuracy because there is no rounding from int64.
 		intAsFloat, _ := new(big.Float).SetInt(intSum).Float64()
@@ -69,3 +90,210 @@ func SumNumbers(vs ...any) any {
 
 	return integer
 }
+
+// AddNumbers returns the result of v1 and v2 addition and error if addition failed.
+// The v1 and v2 parameters could be float64, int32, int64, Decimal128.
+//
+// The result has the broader type of both values, i.e. int32 + int64 produces int64,
+// and any number + Decimal128 produces Decimal128.
+// If the sum of two int32 values does not fit into int32, it is promoted to int64.
+// If the sum of int64 values overflows, handlerparams.ErrLongExceededPositive or
+// handlerparams.ErrLongExceededNegative is returned if v2 is int64, and handlerparams.ErrIntExceeded
+// is returned if v2 is int32. That allows update operators to report the type of the document value.
+// Float64 and Decimal128 values are added without overflow checks.
+func AddNumbers(v1, v2 any) (any, error) {
+	switch v1 := v1.(type) {
+	case float64:
+		switch v2 := v2.(type) {
+		case float64:
+			return v1 + v2, nil
+		case int32:
+			return v1 + float64(v2), nil
+		case int64:
+			return v1 + float64(v2), nil
+		case types.Decimal128:
+			return types.NewDecimal128FromFloat64(v1).Add(v2), nil
+		default:
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+	case int32:
+		switch v2 := v2.(type) {
+		case float64:
+			return v2 + float64(v1), nil
+		case int32:
+			res := int64(v1) + int64(v2)
+			if res > math.MaxInt32 || res < math.MinInt32 {
+				return res, nil
+			}
+
+			return int32(res), nil
+		case int64:
+			return addLongSafely(int64(v1), v2)
+		case types.Decimal128:
+			return types.NewDecimal128FromInt64(int64(v1)).Add(v2), nil
+		default:
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+	case int64:
+		switch v2 := v2.(type) {
+		case float64:
+			return v2 + float64(v1), nil
+		case int32:
+			res, err := addLongSafely(v1, int64(v2))
+			if err != nil {
+				return nil, handlerparams.ErrIntExceeded
+			}
+
+			return res, nil
+		case int64:
+			return addLongSafely(v1, v2)
+		case types.Decimal128:
+			return types.NewDecimal128FromInt64(v1).Add(v2), nil
+		default:
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+	case types.Decimal128:
+		d2, ok := toDecimal128(v2)
+		if !ok {
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+
+		return v1.Add(d2), nil
+	default:
+		return nil, handlerparams.ErrUnexpectedLeftOpType
+	}
+}
+
+// addLongSafely returns the sum of two int64 values.
+// It handles int64 overflows, and returns errLongExceeded error on one.
+//
+// Please always use AddNumbers as it calls addLongSafely when needed.
+func addLongSafely(v1, v2 int64) (int64, error) {
+	if v2 > 0 && v1 > math.MaxInt64-v2 {
+		return 0, handlerparams.ErrLongExceededPositive
+	}
+
+	if v2 < 0 && v1 < math.MinInt64-v2 {
+		return 0, handlerparams.ErrLongExceededNegative
+	}
+
+	return v1 + v2, nil
+}
+
+// MultiplyNumbers returns the multiplication of v1 and v2.
+// The v1 and v2 parameters could be float64, int32, int64 and Decimal128.
+// Multiplication of negative number with zero produces 0, not -0.
+//
+// The result has the broader type of both values, i.e. int32 * int64 produces int64.
+// Overflows are handled the same way as in [AddNumbers].
+func MultiplyNumbers(v1, v2 any) (any, error) {
+	switch v1 := v1.(type) {
+	case float64:
+		var res float64
+
+		switch v2 := v2.(type) {
+		case float64:
+			res = v1 * v2
+		case int32:
+			res = v1 * float64(v2)
+		case int64:
+			res = v1 * float64(v2)
+		case types.Decimal128:
+			return types.NewDecimal128FromFloat64(v1).Mul(v2), nil
+		default:
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+
+		return res, nil
+	case int32:
+		switch v2 := v2.(type) {
+		case float64:
+			return float64(v1) * v2, nil
+		case int32:
+			res := int64(v1) * int64(v2)
+			if res > math.MaxInt32 || res < math.MinInt32 {
+				return res, nil
+			}
+
+			return int32(res), nil
+		case int64:
+			return multiplyLongSafely(int64(v1), v2)
+		case types.Decimal128:
+			return types.NewDecimal128FromInt64(int64(v1)).Mul(v2), nil
+
+		default:
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+	case int64:
+		switch v2 := v2.(type) {
+		case float64:
+			return float64(v1) * v2, nil
+		case int32:
+			v, err := multiplyLongSafely(v1, int64(v2))
+			if err != nil {
+				return 0, handlerparams.ErrIntExceeded
+			}
+
+			return v, nil
+		case int64:
+			return multiplyLongSafely(v1, v2)
+		case types.Decimal128:
+			return types.NewDecimal128FromInt64(v1).Mul(v2), nil
+
+		default:
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+	case types.Decimal128:
+		d2, ok := toDecimal128(v2)
+		if !ok {
+			return nil, handlerparams.ErrUnexpectedRightOpType
+		}
+
+		return v1.Mul(d2), nil
+	default:
+		return nil, handlerparams.ErrUnexpectedLeftOpType
+	}
+}
+
+// multiplyLongSafely returns the multiplication of two int64 values.
+// It handles int64 overflows, and returns errLongExceeded error on one.
+//
+// Please always use MultiplyNumbers as it calls multiplyLongSafely when needed.
+func multiplyLongSafely(v1, v2 int64) (int64, error) {
+	switch {
+	// 0 and 1 values are excluded, because those are only values that
+	// can multiply `MinInt64` without exceeding the range.
+	case v1 == 0 || v2 == 0 || v1 == 1 || v2 == 1:
+		return v1 * v2, nil
+
+	// Multiplying MinInt64 by any other value than above results in overflow.
+	// This check is necessary only for MinInt64, as multiplying MinInt64 by -1
+	// results in overflow with the MinInt64 as result.
+	case v1 == math.MinInt64 || v2 == math.MinInt64:
+		return 0, handlerparams.ErrLongExceededNegative
+	}
+
+	res := v1 * v2
+	if res/v2 != v1 {
+		return 0, handlerparams.ErrLongExceededPositive
+	}
+
+	return res, nil
+}
+
+// toDecimal128 converts float64, int32, int64 or Decimal128 value to Decimal128.
+// It returns false for other types.
+func toDecimal128(v any) (types.Decimal128, bool) {
+	switch v := v.(type) {
+	case float64:
+		return types.NewDecimal128FromFloat64(v), true
+	case int32:
+		return types.NewDecimal128FromInt64(int64(v)), true
+	case int64:
+		return types.NewDecimal128FromInt64(v), true
+	case types.Decimal128:
+		return v, true
+	default:
+		return types.Decimal128{}, false
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregations
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestMultiplyLongSafely(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		err              error
+		v1, v2, expected int64
+	}{
+		"Zero": {
+			v1:       0,
+			v2:       1000,
+			expected: 0,
+		},
+		"One": {
+			v1:       42,
+			v2:       1,
+			expected: 42,
+		},
+		"DoubleMaxPrecision": {
+			v1:       1 << 53,
+			v2:       42,
+			expected: 378302368699121664,
+		},
+		"DoubleMaxPrecisionPlus": {
+			v1:       (1 << 53) + 1,
+			v2:       42,
+			expected: 378302368699121706,
+		},
+		"OverflowLarge": {
+			v1:  1 << 60,
+			v2:  42,
+			err: handlerparams.ErrLongExceededPositive,
+		},
+		"OverflowMax": {
+			v1:  math.MaxInt64,
+			v2:  2,
+			err: handlerparams.ErrLongExceededPositive,
+		},
+		"MaxMinusOne": {
+			v1:       math.MaxInt64,
+			v2:       -1,
+			expected: -math.MaxInt64,
+		},
+		"OverflowMaxMinusTwo": {
+			v1:  math.MaxInt64,
+			v2:  -2,
+			err: handlerparams.ErrLongExceededPositive,
+		},
+		"OverflowMin": {
+			v1:  math.MinInt64,
+			v2:  2,
+			err: handlerparams.ErrLongExceededNegative,
+		},
+		"OverflowMinMinusOne": {
+			v1:  math.MinInt64,
+			v2:  -1,
+			err: handlerparams.ErrLongExceededNegative,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actualRes, err := multiplyLongSafely(tc.v1, tc.v2)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, actualRes)
+		})
+	}
+}
+
+func TestAddNumbers(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		v1, v2   any
+		expected any
+		err      error
+	}{
+		"Int32": {
+			v1:       int32(40),
+			v2:       int32(2),
+			expected: int32(42),
+		},
+		"Int32OverflowPositive": {
+			v1:       int32(42),
+			v2:       int32(math.MaxInt32 - 1),
+			expected: int64(math.MaxInt32 + 41),
+		},
+		"Int32OverflowNegative": {
+			v1:       int32(-42),
+			v2:       int32(math.MinInt32 + 1),
+			expected: int64(math.MinInt32 - 41),
+		},
+		"Int32Int64": {
+			v1:       int32(1),
+			v2:       int64(math.MaxInt32),
+			expected: int64(math.MaxInt32 + 1),
+		},
+		"Int64Int32": {
+			v1:       int64(1),
+			v2:       int32(41),
+			expected: int64(42),
+		},
+		"Int32Double": {
+			v1:       int32(1),
+			v2:       float64(41.5),
+			expected: float64(42.5),
+		},
+		"Int64Double": {
+			v1:       int64(math.MaxInt64),
+			v2:       float64(0),
+			expected: float64(math.MaxInt64),
+		},
+		"Int64OverflowPositive": {
+			v1:  int64(1),
+			v2:  int64(math.MaxInt64),
+			err: handlerparams.ErrLongExceededPositive,
+		},
+		"Int64OverflowNegative": {
+			v1:  int64(-1),
+			v2:  int64(math.MinInt64),
+			err: handlerparams.ErrLongExceededNegative,
+		},
+		"Int32Int64Overflow": {
+			v1:  int32(1),
+			v2:  int64(math.MaxInt64),
+			err: handlerparams.ErrLongExceededPositive,
+		},
+		"Int64Int32Overflow": {
+			v1:  int64(math.MaxInt64),
+			v2:  int32(1),
+			err: handlerparams.ErrIntExceeded,
+		},
+		"Decimal": {
+			v1:       must.NotFail(types.ParseDecimal128("0.1")),
+			v2:       must.NotFail(types.ParseDecimal128("0.2")),
+			expected: must.NotFail(types.ParseDecimal128("0.3")),
+		},
+		"DecimalInt64": {
+			v1:       must.NotFail(types.ParseDecimal128("1.50")),
+			v2:       int64(math.MaxInt64),
+			expected: must.NotFail(types.ParseDecimal128("9223372036854775808.50")),
+		},
+		"Int32Decimal": {
+			v1:       int32(1),
+			v2:       must.NotFail(types.ParseDecimal128("-1.5")),
+			expected: must.NotFail(types.ParseDecimal128("-0.5")),
+		},
+		"DoubleDecimal": {
+			v1:       float64(0.1),
+			v2:       must.NotFail(types.ParseDecimal128("0.2")),
+			expected: must.NotFail(types.ParseDecimal128("0.300000000000000")),
+		},
+		"DecimalInfinity": {
+			v1:       must.NotFail(types.ParseDecimal128("Infinity")),
+			v2:       math.Inf(-1),
+			expected: types.NewDecimal128NaN(),
+		},
+		"UnexpectedLeft": {
+			v1:  "foo",
+			v2:  int32(1),
+			err: handlerparams.ErrUnexpectedLeftOpType,
+		},
+		"UnexpectedRight": {
+			v1:  int32(1),
+			v2:  "foo",
+			err: handlerparams.ErrUnexpectedRightOpType,
+		},
+		"UnexpectedRightDecimal": {
+			v1:  must.NotFail(types.ParseDecimal128("1")),
+			v2:  "foo",
+			err: handlerparams.ErrUnexpectedRightOpType,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := AddNumbers(tc.v1, tc.v2)
+			if tc.err != nil {
+				assert.Equal(t, tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMultiplyNumbers(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		v1, v2   any
+		expected any
+		err      error
+	}{
+		"Int32": {
+			v1:       int32(21),
+			v2:       int32(2),
+			expected: int32(42),
+		},
+		"Int32OverflowPositive": {
+			v1:       int32(2),
+			v2:       int32(math.MaxInt32),
+			expected: int64(2 * math.MaxInt32),
+		},
+		"Int32OverflowNegative": {
+			v1:       int32(-1),
+			v2:       int32(math.MinInt32),
+			expected: int64(-math.MinInt32),
+		},
+		"Int32Int64": {
+			v1:       int32(2),
+			v2:       int64(21),
+			expected: int64(42),
+		},
+		"Int64Double": {
+			v1:       int64(2),
+			v2:       float64(21.5),
+			expected: float64(43),
+		},
+		"Int64Overflow": {
+			v1:  int64(2),
+			v2:  int64(math.MaxInt64),
+			err: handlerparams.ErrLongExceededPositive,
+		},
+		"Int32Int64Overflow": {
+			v1:  int32(2),
+			v2:  int64(math.MaxInt64),
+			err: handlerparams.ErrLongExceededPositive,
+		},
+		"Int64Int32Overflow": {
+			v1:  int64(math.MaxInt64),
+			v2:  int32(2),
+			err: handlerparams.ErrIntExceeded,
+		},
+		"Decimal": {
+			v1:       must.NotFail(types.ParseDecimal128("1.5")),
+			v2:       must.NotFail(types.ParseDecimal128("1.5")),
+			expected: must.NotFail(types.ParseDecimal128("2.25")),
+		},
+		"Int64Decimal": {
+			v1:       int64(math.MaxInt64),
+			v2:       must.NotFail(types.ParseDecimal128("2")),
+			expected: must.NotFail(types.ParseDecimal128("18446744073709551614")),
+		},
+		"DecimalDouble": {
+			v1:       must.NotFail(types.ParseDecimal128("2")),
+			v2:       float64(21.5),
+			expected: must.NotFail(types.ParseDecimal128("43.0000000000000")),
+		},
+		"DecimalInfinityZero": {
+			v1:       must.NotFail(types.ParseDecimal128("-Infinity")),
+			v2:       int32(0),
+			expected: types.NewDecimal128NaN(),
+		},
+		"UnexpectedRightDecimal": {
+			v1:  must.NotFail(types.ParseDecimal128("1")),
+			v2:  "foo",
+			err: handlerparams.ErrUnexpectedRightOpType,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := MultiplyNumbers(tc.v1, tc.v2)
+			if tc.err != nil {
+				assert.Equal(t, tc.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestSumNumbers(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		vs       []any
+		expected any
+	}{
+		"Empty": {
+			expected: int32(0),
+		},
+		"Int32": {
+			vs:       []any{int32(40), int32(2), "foo"},
+			expected: int32(42),
+		},
+		"Int64": {
+			vs:       []any{int32(40), int64(2)},
+			expected: int64(42),
+		},
+		"Double": {
+			vs:       []any{int32(40), float64(2.5)},
+			expected: float64(42.5),
+		},
+		"Decimal": {
+			vs: []any{
+				int64(math.MaxInt64), int32(1), float64(0.5),
+				must.NotFail(types.ParseDecimal128("0.10")), types.NullType{},
+			},
+			expected: must.NotFail(types.ParseDecimal128("9223372036854775808.600000000000000")),
+		},
+		"DecimalNaN": {
+			vs:       []any{int32(1), types.NewDecimal128NaN()},
+			expected: types.NewDecimal128NaN(),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, SumNumbers(tc.vs...))
+		})
+	}
+}
@@ -71,7 +71,7 @@ func newSum(args ...any) (Accumulator, error) {
 				// $sum returns 0 on non-existent field.
 				accumulator.number = int32(0)
 			}
-		case int32, int64:
+		case int32, int64, types.Decimal128:
 			accumulator.number = arg
 		default:
 			accumulator.number = int32(0)
@@ -120,7 +120,7 @@ func (s *sum) Accumulate(iter types.DocumentsIterator) (any, error) {
 		}
 
 		switch number := s.number.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			// For number types, the result is equivalent of iterator len*number,
 			// with conversion handled upon overflow of int32 and int64.
 			// For example, { $sum: 1 } is equivalent of { $count: { } }.
@@ -0,0 +1,645 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+const (
+	// rangeMemoryLimit is the maximum approximate size in bytes of the array produced by `$range`.
+	rangeMemoryLimit = 100 * 1024 * 1024
+
+	// rangeElementSize is the approximate size in bytes of a single element of the array produced by `$range`.
+	rangeElementSize = 16
+)
+
+// arrayElemAt represents `$arrayElemAt`, `$first` and `$last` operators.
+type arrayElemAt struct {
+	array any
+	index any
+	name  string
+}
+
+// newArrayElemAt returns `$arrayElemAt` operator.
+func newArrayElemAt(args ...any) (Operator, error) {
+	if len(args) != 2 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$arrayElemAt",
+			fmt.Sprintf("Expression $arrayElemAt takes exactly 2 arguments. %d were passed in.", len(args)),
+		)
+	}
+
+	return &arrayElemAt{
+		array: args[0],
+		index: args[1],
+		name:  "$arrayElemAt",
+	}, nil
+}
+
+// newArrayEdge returns a function that creates `$first` or `$last` operator
+// that returns the array element with the given index.
+func newArrayEdge(name string, index int32) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				name,
+				fmt.Sprintf("Expression %s takes exactly 1 arguments. %d were passed in.", name, len(args)),
+			)
+		}
+
+		return &arrayElemAt{
+			array: args[0],
+			index: index,
+			name:  name,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// Negative index counts from the end of the array.
+// If the array or the index is null or missing, null is returned.
+// If the index is out of array bounds, the missing value is returned.
+func (a *arrayElemAt) Process(doc *types.Document) (any, error) {
+	array, err := evaluate(doc, a.array)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := evaluate(doc, a.index)
+	if err != nil {
+		return nil, err
+	}
+
+	if array == types.Null || index == types.Null {
+		return types.Null, nil
+	}
+
+	arr, ok := array.(*types.Array)
+	if !ok {
+		msg := fmt.Sprintf("%s's argument must be an array, but is %s", a.name, handlerparams.AliasFromType(array))
+		if a.name == "$arrayElemAt" {
+			msg = fmt.Sprintf("$arrayElemAt's first argument must be an array, but is %s", handlerparams.AliasFromType(array))
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrArrayElemAtNotArray, msg, a.name)
+	}
+
+	if !isNumber(index) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtIndexNotNumber,
+			fmt.Sprintf(
+				"$arrayElemAt's second argument must be a numeric value, but is %s",
+				handlerparams.AliasFromType(index),
+			),
+			a.name,
+		)
+	}
+
+	i, ok := arrayInt32(index)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayElemAtIndexNotInt,
+			fmt.Sprintf(
+				"$arrayElemAt's second argument must be representable as a 32-bit integer: %s",
+				formatMathValue(mathToDouble(index)),
+			),
+			a.name,
+		)
+	}
+
+	pos := int(i)
+	if pos < 0 {
+		pos += arr.Len()
+	}
+
+	if pos < 0 || pos >= arr.Len() {
+		return nil, nil
+	}
+
+	return must.NotFail(arr.Get(pos)), nil
+}
+
+// slice represents `$slice` operator.
+type slice struct {
+	array    any
+	position any // nil if not set
+	n        any
+}
+
+// newSlice returns `$slice` operator.
+func newSlice(args ...any) (Operator, error) {
+	switch len(args) {
+	case 2:
+		return &slice{
+			array: args[0],
+			n:     args[1],
+		}, nil
+	case 3:
+		return &slice{
+			array:    args[0],
+			position: args[1],
+			n:        args[2],
+		}, nil
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidArg,
+			fmt.Sprintf("Expression $slice takes at least 2 arguments, and at most 3, but %d were passed in.", len(args)),
+			"$slice",
+		)
+	}
+}
+
+// Process implements Operator interface.
+//
+// With two arguments, the first n elements are returned, or the last -n elements if n is negative.
+// With three arguments, n elements starting from the position are returned;
+// negative position counts from the end of the array.
+// If any argument is null or missing, null is returned.
+func (s *slice) Process(doc *types.Document) (any, error) {
+	array, err := evaluate(doc, s.array)
+	if err != nil {
+		return nil, err
+	}
+
+	if array == types.Null {
+		return types.Null, nil
+	}
+
+	arr, ok := array.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceFirstArg,
+			fmt.Sprintf("First argument to $slice must be an array, but is of type: %s", handlerparams.AliasFromType(array)),
+			"$slice",
+		)
+	}
+
+	second := s.n
+	if s.position != nil {
+		second = s.position
+	}
+
+	v, err := evaluate(doc, second)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == types.Null {
+		return types.Null, nil
+	}
+
+	if !isNumber(v) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceSecondArgNotNumber,
+			fmt.Sprintf("Second argument to $slice must be a numeric value, but is of type: %s", handlerparams.AliasFromType(v)),
+			"$slice",
+		)
+	}
+
+	secondInt, ok := arrayInt32(v)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrSliceSecondArgNotInt,
+			fmt.Sprintf(
+				"Second argument to $slice can't be represented as a 32-bit integer: %s",
+				formatMathValue(mathToDouble(v)),
+			),
+			"$slice",
+		)
+	}
+
+	l := arr.Len()
+
+	var start, n int
+
+	if s.position == nil {
+		n = int(secondInt)
+		if n < 0 {
+			start = max(l+n, 0)
+			n = l
+		}
+	} else {
+		if v, err = evaluate(doc, s.n); err != nil {
+			return nil, err
+		}
+
+		if v == types.Null {
+			return types.Null, nil
+		}
+
+		if !isNumber(v) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSliceThirdArgNotNumber,
+				fmt.Sprintf("Third argument to $slice must be numeric, but is of type: %s", handlerparams.AliasFromType(v)),
+				"$slice",
+			)
+		}
+
+		third, ok := arrayInt32(v)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSliceThirdArgNotInt,
+				fmt.Sprintf(
+					"Third argument to $slice can't be represented as a 32-bit integer: %s",
+					formatMathValue(mathToDouble(v)),
+				),
+				"$slice",
+			)
+		}
+
+		if third <= 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrSliceThirdArgNotPositive,
+				fmt.Sprintf("Third argument to $slice must be positive: %d", third),
+				"$slice",
+			)
+		}
+
+		n = int(third)
+
+		start = int(secondInt)
+		if start < 0 {
+			start = max(l+start, 0)
+		} else {
+			start = min(start, l)
+		}
+	}
+
+	end := min(start+n, l)
+
+	res := types.MakeArray(end - start)
+	for i := start; i < end; i++ {
+		res.Append(must.NotFail(arr.Get(i)))
+	}
+
+	return res, nil
+}
+
+// indexOfArray represents `$indexOfArray` operator.
+type indexOfArray struct {
+	array  any
+	search any
+	start  any // nil if not set
+	end    any // nil if not set
+}
+
+// newIndexOfArray returns `$indexOfArray` operator.
+func newIndexOfArray(args ...any) (Operator, error) {
+	if len(args) < 2 || len(args) > 4 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidArg,
+			fmt.Sprintf(
+				"Expression $indexOfArray takes at least 2 arguments, and at most 4, but %d were passed in.",
+				len(args),
+			),
+			"$indexOfArray",
+		)
+	}
+
+	op := &indexOfArray{
+		array:  args[0],
+		search: args[1],
+	}
+
+	if len(args) > 2 {
+		op.start = args[2]
+	}
+
+	if len(args) > 3 {
+		op.end = args[3]
+	}
+
+	return op, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns the index of the first array element equal to the searched value
+// in the range from the starting index (inclusive) to the ending index (exclusive), or -1.
+// If the array is null or missing, null is returned.
+func (i *indexOfArray) Process(doc *types.Document) (any, error) {
+	array, err := evaluate(doc, i.array)
+	if err != nil {
+		return nil, err
+	}
+
+	if array == types.Null {
+		return types.Null, nil
+	}
+
+	arr, ok := array.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfArrayNotArray,
+			fmt.Sprintf(
+				"$indexOfArray requires an array as a first argument, found: %s",
+				handlerparams.AliasFromType(array),
+			),
+			"$indexOfArray",
+		)
+	}
+
+	search, err := evaluate(doc, i.search)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := 0, arr.Len()
+
+	if i.start != nil {
+		if start, err = i.index(doc, i.start, "starting"); err != nil {
+			return nil, err
+		}
+	}
+
+	if i.end != nil {
+		var e int
+		if e, err = i.index(doc, i.end, "ending"); err != nil {
+			return nil, err
+		}
+
+		end = min(e, end)
+	}
+
+	for pos := start; pos < end; pos++ {
+		if types.CompareForAggregation(must.NotFail(arr.Get(pos)), search) == types.Equal {
+			return int32(pos), nil
+		}
+	}
+
+	return int32(-1), nil
+}
+
+// index evaluates the starting or ending index argument.
+func (i *indexOfArray) index(doc *types.Document, arg any, kind string) (int, error) {
+	v, err := evaluate(doc, arg)
+	if err != nil {
+		return 0, err
+	}
+
+	index, ok := arrayInt32(v)
+	if !ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfArrayIndexNotInt,
+			fmt.Sprintf(
+				"$indexOfArray requires an integral %s index, found a value of type: %s, with value: %s",
+				kind, handlerparams.AliasFromType(v), types.FormatAnyValue(v),
+			),
+			"$indexOfArray",
+		)
+	}
+
+	if index < 0 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexOfArrayIndexNegative,
+			fmt.Sprintf("$indexOfArray requires a nonnegative %s index, found: %d", kind, index),
+			"$indexOfArray",
+		)
+	}
+
+	return int(index), nil
+}
+
+// rangeOp represents `$range` operator.
+type rangeOp struct {
+	start any
+	end   any
+	step  any
+}
+
+// newRange returns `$range` operator.
+func newRange(args ...any) (Operator, error) {
+	switch len(args) {
+	case 2:
+		return &rangeOp{
+			start: args[0],
+			end:   args[1],
+			step:  int32(1),
+		}, nil
+	case 3:
+		return &rangeOp{
+			start: args[0],
+			end:   args[1],
+			step:  args[2],
+		}, nil
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidArg,
+			fmt.Sprintf("Expression $range takes at least 2 arguments, and at most 3, but %d were passed in.", len(args)),
+			"$range",
+		)
+	}
+}
+
+// Process implements Operator interface.
+//
+// It returns an array of integers from start (inclusive) to end (exclusive) with the given step.
+func (r *rangeOp) Process(doc *types.Document) (any, error) {
+	start, err := r.value(doc, r.start, "starting", handlererrors.ErrRangeStartNotNumber, handlererrors.ErrRangeStartNotInt)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := r.value(doc, r.end, "ending", handlererrors.ErrRangeEndNotNumber, handlererrors.ErrRangeEndNotInt)
+	if err != nil {
+		return nil, err
+	}
+
+	step, err := r.value(doc, r.step, "step", handlererrors.ErrRangeStepNotNumber, handlererrors.ErrRangeStepNotInt)
+	if err != nil {
+		return nil, err
+	}
+
+	if step == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRangeStepZero,
+			"$range requires a non-zero step value",
+			"$range",
+		)
+	}
+
+	// number of elements, rounded up
+	var n int64
+
+	switch {
+	case step > 0 && start < end:
+		n = (end-start-1)/step + 1
+	case step < 0 && start > end:
+		n = (end-start+1)/step + 1
+	}
+
+	if size := n * rangeElementSize; size > rangeMemoryLimit {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrExceededMemoryLimit,
+			fmt.Sprintf(
+				"$range would use too much memory (%d bytes) and cannot spill to disk. Memory limit: %d bytes",
+				size, rangeMemoryLimit,
+			),
+			"$range",
+		)
+	}
+
+	res := types.MakeArray(int(n))
+	for i := int64(0); i < n; i++ {
+		res.Append(int32(start + i*step))
+	}
+
+	return res, nil
+}
+
+// value evaluates the starting, ending or step value argument.
+func (r *rangeOp) value(doc *types.Document, arg any, kind string, notNumber, notInt handlererrors.ErrorCode) (int64, error) {
+	v, err := evaluate(doc, arg)
+	if err != nil {
+		return 0, err
+	}
+
+	if !isNumber(v) {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			notNumber,
+			fmt.Sprintf("$range requires a numeric %s value, found value of type: %s", kind, handlerparams.AliasFromType(v)),
+			"$range",
+		)
+	}
+
+	i, ok := arrayInt32(v)
+	if !ok {
+		article := "a"
+		if kind == "ending" {
+			article = "an"
+		}
+
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			notInt,
+			fmt.Sprintf(
+				"$range requires %s %s value that can be represented as a 32-bit integer, found value: %s",
+				article, kind, types.FormatAnyValue(v),
+			),
+			"$range",
+		)
+	}
+
+	return int64(i), nil
+}
+
+// concatArrays represents `$concatArrays` operator.
+type concatArrays struct {
+	args []any
+}
+
+// newConcatArrays returns `$concatArrays` operator.
+func newConcatArrays(args ...any) (Operator, error) {
+	return &concatArrays{
+		args: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// If any argument is null or missing, null is returned.
+func (c *concatArrays) Process(doc *types.Document) (any, error) {
+	res := types.MakeArray(0)
+
+	for _, arg := range c.args {
+		v, err := evaluate(doc, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == types.Null {
+			return types.Null, nil
+		}
+
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConcatArraysNotArray,
+				fmt.Sprintf("$concatArrays only supports arrays, not %s", handlerparams.AliasFromType(v)),
+				"$concatArrays",
+			)
+		}
+
+		iter := arr.Iterator()
+
+		for {
+			_, elem, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				iter.Close()
+				return nil, lazyerrors.Error(err)
+			}
+
+			res.Append(elem)
+		}
+
+		iter.Close()
+	}
+
+	return res, nil
+}
+
+// arrayInt32 returns the number as int32 if it is integral and fits into int32.
+func arrayInt32(v any) (int32, bool) {
+	switch v := v.(type) {
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt32 || v > math.MaxInt32 {
+			return 0, false
+		}
+
+		return int32(v), true
+	case int32:
+		return v, true
+	case int64:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return 0, false
+		}
+
+		return int32(v), true
+	case types.Decimal128:
+		n, err := handlerparams.GetWholeNumberParam(v)
+		if err != nil || n < math.MinInt32 || n > math.MaxInt32 {
+			return 0, false
+		}
+
+		return int32(n), true
+	default:
+		return 0, false
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*arrayElemAt)(nil)
+	_ Operator = (*slice)(nil)
+	_ Operator = (*indexOfArray)(nil)
+	_ Operator = (*rangeOp)(nil)
+	_ Operator = (*concatArrays)(nil)
+)
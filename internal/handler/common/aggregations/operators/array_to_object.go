@@ -0,0 +1,235 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// arrayToObject represents `$arrayToObject` operator.
+type arrayToObject struct {
+	input any
+}
+
+// newArrayToObject returns `$arrayToObject` operator.
+func newArrayToObject(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$arrayToObject",
+			fmt.Sprintf("Expression $arrayToObject takes exactly 1 arguments. %d were passed in.", len(args)),
+		)
+	}
+
+	return &arrayToObject{
+		input: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// The input should be an array of `[<key>, <value>]` arrays or `{k: <key>, v: <value>}` documents.
+// If the key is repeated, the last value is used.
+// If the input is null or missing, null is returned.
+func (a *arrayToObject) Process(doc *types.Document) (any, error) {
+	input, err := evaluate(doc, a.input)
+	if err != nil {
+		return nil, err
+	}
+
+	var arr *types.Array
+
+	switch input := input.(type) {
+	case types.NullType:
+		return types.Null, nil
+	case *types.Array:
+		arr = input
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectNotArray,
+			fmt.Sprintf("$arrayToObject requires an array input, found: %s", handlerparams.AliasFromType(input)),
+			"$arrayToObject",
+		)
+	}
+
+	res := types.MakeDocument(arr.Len())
+
+	if arr.Len() == 0 {
+		return res, nil
+	}
+
+	first := must.NotFail(arr.Get(0))
+
+	var parse func(v any) (string, any, error)
+
+	switch first.(type) {
+	case *types.Array:
+		parse = arrayToObjectPair
+	case *types.Document:
+		parse = arrayToObjectKV
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectInvalidElement,
+			fmt.Sprintf("Unrecognised input type format for $arrayToObject: %s", handlerparams.AliasFromType(first)),
+			"$arrayToObject",
+		)
+	}
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		k, v, err := parse(v)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Set(k, v)
+	}
+
+	return res, nil
+}
+
+// arrayToObjectPair returns the key and the value of `[<key>, <value>]` array element.
+func arrayToObjectPair(elem any) (string, any, error) {
+	pair, ok := elem.(*types.Array)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectExpectedArray,
+			fmt.Sprintf(
+				"$arrayToObject requires a consistent input format. "+
+					"Elements must all be arrays or all be objects. Array was detected, now found: %s",
+				handlerparams.AliasFromType(elem),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	if pair.Len() != 2 {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectInvalidArrayLen,
+			fmt.Sprintf("$arrayToObject requires an array of size 2 arrays,found array of size: %d", pair.Len()),
+			"$arrayToObject",
+		)
+	}
+
+	key := must.NotFail(pair.Get(0))
+
+	k, ok := key.(string)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectArrayKeyNotString,
+			fmt.Sprintf(
+				"$arrayToObject requires an array of key-value pairs, where the key must be of type string. Found key type: %s",
+				handlerparams.AliasFromType(key),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	if strings.ContainsRune(k, 0) {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectArrayKeyNullByte,
+			"Key field cannot contain an embedded null byte",
+			"$arrayToObject",
+		)
+	}
+
+	return k, must.NotFail(pair.Get(1)), nil
+}
+
+// arrayToObjectKV returns the key and the value of `{k: <key>, v: <value>}` document element.
+func arrayToObjectKV(elem any) (string, any, error) {
+	kv, ok := elem.(*types.Document)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectExpectedDocument,
+			fmt.Sprintf(
+				"$arrayToObject requires a consistent input format. "+
+					"Elements must all be arrays or all be objects. Object was detected, now found: %s",
+				handlerparams.AliasFromType(elem),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	if kv.Len() != 2 {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectInvalidKeysLen,
+			fmt.Sprintf("$arrayToObject requires an object keys of 'k' and 'v'. Found incorrect number of keys:%d", kv.Len()),
+			"$arrayToObject",
+		)
+	}
+
+	key, keyErr := kv.Get("k")
+	value, valueErr := kv.Get("v")
+
+	if keyErr != nil || valueErr != nil {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectMissingKeys,
+			fmt.Sprintf(
+				"$arrayToObject requires an object with keys 'k' and 'v'. Missing either or both keys from: %s",
+				types.FormatAnyValue(kv),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	k, ok := key.(string)
+	if !ok {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectDocumentKeyNotString,
+			fmt.Sprintf(
+				"$arrayToObject requires an object with keys 'k' and 'v', where the value of 'k' must be of type string. "+
+					"Found type: %s",
+				handlerparams.AliasFromType(key),
+			),
+			"$arrayToObject",
+		)
+	}
+
+	if strings.ContainsRune(k, 0) {
+		return "", nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrArrayToObjectDocumentKeyNullByte,
+			"Key field cannot contain an embedded null byte",
+			"$arrayToObject",
+		)
+	}
+
+	return k, value, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*arrayToObject)(nil)
+)
@@ -0,0 +1,636 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// convert represents `$convert` operator and its shorthands like `$toInt`.
+type convert struct {
+	input      any
+	to         any
+	onError    any
+	onNull     any
+	hasOnError bool
+	hasOnNull  bool
+}
+
+// newConvert returns `$convert` operator.
+func newConvert(args ...any) (Operator, error) {
+	doc, found := namedArgs(args)
+	if doc == nil {
+		return nil, newOperatorError(
+			ErrFailedToParse,
+			"$convert",
+			fmt.Sprintf("$convert expects an object of named arguments but found: %s", found),
+		)
+	}
+
+	op := new(convert)
+
+	var hasInput, hasTo bool
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "input":
+			op.input = v
+			hasInput = true
+		case "to":
+			op.to = v
+			hasTo = true
+		case "onError":
+			op.onError = v
+			op.hasOnError = true
+		case "onNull":
+			op.onNull = v
+			op.hasOnNull = true
+		default:
+			return nil, newOperatorError(
+				ErrFailedToParse,
+				"$convert",
+				fmt.Sprintf("$convert found an unknown argument: %s", k),
+			)
+		}
+	}
+
+	if !hasInput {
+		return nil, newOperatorError(ErrFailedToParse, "$convert", "Missing 'input' parameter to $convert")
+	}
+
+	if !hasTo {
+		return nil, newOperatorError(ErrFailedToParse, "$convert", "Missing 'to' parameter to $convert")
+	}
+
+	// validate constant target type early; expressions are evaluated for each document
+	switch to := op.to.(type) {
+	case *types.Document:
+		if !IsOperator(to) {
+			if _, err := convertTargetType(to); err != nil {
+				return nil, err
+			}
+		}
+	case string:
+		if !strings.HasPrefix(to, "$") {
+			if _, err := convertTargetType(to); err != nil {
+				return nil, err
+			}
+		}
+	case types.NullType:
+	default:
+		if _, err := convertTargetType(to); err != nil {
+			return nil, err
+		}
+	}
+
+	return op, nil
+}
+
+// newConvertTo returns a function that creates a shorthand operator like `$toInt`,
+// which is the same as `$convert` to the given type without `onError` and `onNull`.
+func newConvertTo(name string, to handlerparams.TypeCode) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				name,
+				fmt.Sprintf("Expression %s takes exactly 1 arguments. %d were passed in.", name, len(args)),
+			)
+		}
+
+		return &convert{
+			input: args[0],
+			to:    int32(to),
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+func (c *convert) Process(doc *types.Document) (any, error) {
+	to, err := evaluate(doc, c.to)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := evaluate(doc, c.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if to == types.Null {
+		return types.Null, nil
+	}
+
+	code, err := convertTargetType(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if input == types.Null {
+		if c.hasOnNull {
+			return evaluate(doc, c.onNull)
+		}
+
+		return types.Null, nil
+	}
+
+	res, err := convertValue(input, code)
+	if err != nil {
+		var opErr OperatorError
+		if c.hasOnError && errors.As(err, &opErr) && opErr.Code() == ErrConversionFailure {
+			return evaluate(doc, c.onError)
+		}
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// convertTargetType returns the type code for the `to` argument of `$convert`.
+// It could be specified by type name or type number.
+func convertTargetType(to any) (handlerparams.TypeCode, error) {
+	switch to := to.(type) {
+	case string:
+		code, err := handlerparams.ParseTypeCode(to)
+		if err != nil || code == handlerparams.TypeCodeNumber {
+			return 0, newOperatorError(ErrBadValue, "$convert", fmt.Sprintf("Unknown type name: %s", to))
+		}
+
+		return code, nil
+
+	case float64, int32, int64, types.Decimal128:
+		n, err := handlerparams.GetWholeNumberParam(to)
+		if err == nil && n >= math.MinInt32 && n <= math.MaxInt32 {
+			var code handlerparams.TypeCode
+			if code, err = handlerparams.NewTypeCode(int32(n)); err == nil && code != handlerparams.TypeCodeNumber {
+				return code, nil
+			}
+		}
+
+		return 0, newOperatorError(
+			ErrFailedToParse,
+			"$convert",
+			fmt.Sprintf(
+				"In $convert, numeric value for 'to' does not correspond to a BSON type: %s",
+				types.FormatAnyValue(to),
+			),
+		)
+
+	default:
+		return 0, newOperatorError(
+			ErrFailedToParse,
+			"$convert",
+			fmt.Sprintf(
+				"$convert's 'to' argument must be a string or number, but is %s",
+				handlerparams.AliasFromType(to),
+			),
+		)
+	}
+}
+
+// convertValue converts non-null value to the given type.
+//
+// It returns OperatorError with ErrConversionFailure code if value cannot be converted.
+func convertValue(v any, to handlerparams.TypeCode) (any, error) {
+	switch to {
+	case handlerparams.TypeCodeBool:
+		return convertToBool(v), nil
+	case handlerparams.TypeCodeDouble:
+		return convertToDouble(v)
+	case handlerparams.TypeCodeInt:
+		return convertToInt(v)
+	case handlerparams.TypeCodeLong:
+		return convertToLong(v)
+	case handlerparams.TypeCodeString:
+		return convertToString(v)
+	case handlerparams.TypeCodeObjectID:
+		return convertToObjectID(v)
+	case handlerparams.TypeCodeDate:
+		return convertToDate(v)
+	case handlerparams.TypeCodeDecimal:
+		return convertToDecimal(v)
+	default:
+		return nil, unsupportedConversionError(v, to)
+	}
+}
+
+// convertToBool converts value to bool.
+// Numbers are converted to false if they are zero, all other values are converted to true.
+func convertToBool(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case int32:
+		return v != 0
+	case int64:
+		return v != 0
+	case types.Decimal128:
+		return !v.IsZero()
+	default:
+		return true
+	}
+}
+
+// convertToDouble converts value to float64.
+func convertToDouble(v any) (any, error) {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return float64(1), nil
+		}
+
+		return float64(0), nil
+	case float64:
+		return v, nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case types.Decimal128:
+		f := v.Float64()
+		if math.IsInf(f, 0) && !v.IsInf(0) {
+			return nil, overflowError(v)
+		}
+
+		return f, nil
+	case string:
+		return parseDouble(v)
+	case time.Time:
+		return float64(v.UnixMilli()), nil
+	default:
+		return nil, unsupportedConversionError(v, handlerparams.TypeCodeDouble)
+	}
+}
+
+// convertToInt converts value to int32.
+func convertToInt(v any) (any, error) {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return int32(1), nil
+		}
+
+		return int32(0), nil
+	case float64:
+		n, err := truncateDouble(v, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+
+		return int32(n), nil
+	case int32:
+		return v, nil
+	case int64:
+		if v < math.MinInt32 || v > math.MaxInt32 {
+			return nil, overflowError(v)
+		}
+
+		return int32(v), nil
+	case types.Decimal128:
+		n, err := truncateDecimal(v, math.MinInt32, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+
+		return int32(n), nil
+	case string:
+		n, err := parseInteger(v, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		return int32(n), nil
+	default:
+		return nil, unsupportedConversionError(v, handlerparams.TypeCodeInt)
+	}
+}
+
+// convertToLong converts value to int64.
+func convertToLong(v any) (any, error) {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return int64(1), nil
+		}
+
+		return int64(0), nil
+	case float64:
+		return truncateDouble(v, math.MinInt64, math.MaxInt64)
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case types.Decimal128:
+		return truncateDecimal(v, math.MinInt64, math.MaxInt64)
+	case string:
+		return parseInteger(v, 64)
+	case time.Time:
+		return v.UnixMilli(), nil
+	default:
+		return nil, unsupportedConversionError(v, handlerparams.TypeCodeLong)
+	}
+}
+
+// convertToString converts value to string.
+func convertToString(v any) (any, error) {
+	switch v := v.(type) {
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return formatDouble(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case types.Decimal128:
+		return v.String(), nil
+	case string:
+		return v, nil
+	case types.ObjectID:
+		return hex.EncodeToString(v[:]), nil
+	case time.Time:
+		return v.UTC().Format("2006-01-02T15:04:05.000Z"), nil
+	default:
+		return nil, unsupportedConversionError(v, handlerparams.TypeCodeString)
+	}
+}
+
+// convertToDecimal converts value to Decimal128.
+// Like MongoDB, doubles are converted with 15 significant digits.
+func convertToDecimal(v any) (any, error) {
+	switch v := v.(type) {
+	case bool:
+		if v {
+			return types.NewDecimal128FromInt64(1), nil
+		}
+
+		return types.NewDecimal128FromInt64(0), nil
+	case float64:
+		return types.NewDecimal128FromFloat64(v), nil
+	case int32:
+		return types.NewDecimal128FromInt64(int64(v)), nil
+	case int64:
+		return types.NewDecimal128FromInt64(v), nil
+	case types.Decimal128:
+		return v, nil
+	case string:
+		d, err := types.ParseDecimal128(v)
+		if err != nil {
+			return nil, parseNumberError(v)
+		}
+
+		return d, nil
+	case time.Time:
+		return types.NewDecimal128FromInt64(v.UnixMilli()), nil
+	default:
+		return nil, unsupportedConversionError(v, handlerparams.TypeCodeDecimal)
+	}
+}
+
+// convertToObjectID converts value to ObjectID.
+func convertToObjectID(v any) (any, error) {
+	switch v := v.(type) {
+	case types.ObjectID:
+		return v, nil
+	case string:
+		if len(v) != 2*types.ObjectIDLen {
+			return nil, newOperatorError(
+				ErrConversionFailure,
+				"$convert",
+				fmt.Sprintf(
+					"Failed to parse objectId '%s' in $convert with no onError value: "+
+						"Invalid string length for parsing to OID, expected %d but found %d",
+					v, 2*types.ObjectIDLen, len(v),
+				),
+			)
+		}
+
+		b, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, newOperatorError(
+				ErrConversionFailure,
+				"$convert",
+				fmt.Sprintf(
+					"Failed to parse objectId '%s' in $convert with no onError value: Invalid character found in hex string",
+					v,
+				),
+			)
+		}
+
+		return types.ObjectID(b), nil
+	default:
+		return nil, unsupportedConversionError(v, handlerparams.TypeCodeObjectID)
+	}
+}
+
+// convertToDate converts value to date.
+func convertToDate(v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		ms, err := truncateDouble(v, math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+
+		return time.UnixMilli(ms).UTC(), nil
+	case int64:
+		return time.UnixMilli(v).UTC(), nil
+	case types.Decimal128:
+		ms, err := truncateDecimal(v, math.MinInt64, math.MaxInt64)
+		if err != nil {
+			return nil, err
+		}
+
+		return time.UnixMilli(ms).UTC(), nil
+	case string:
+		return parseDate(v)
+	case types.ObjectID, types.Timestamp, time.Time:
+		t, _ := dateFromValue(v)
+		return t, nil
+	default:
+		return nil, unsupportedConversionError(v, handlerparams.TypeCodeDate)
+	}
+}
+
+// parseDate parses date string in one of the recognized layouts.
+func parseDate(s string) (time.Time, error) {
+	t, err := parseDateString(s, time.UTC)
+	if err != nil {
+		return time.Time{}, newOperatorError(
+			ErrConversionFailure,
+			"$convert",
+			fmt.Sprintf("Error parsing date string '%s' in $convert with no onError value", s),
+		)
+	}
+
+	return t, nil
+}
+
+// parseDouble parses base 10 string as float64.
+func parseDouble(s string) (float64, error) {
+	digits := strings.TrimLeft(s, "+-")
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") || strings.Contains(s, "_") {
+		return 0, parseNumberError(s)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, parseNumberError(s)
+	}
+
+	return f, nil
+}
+
+// parseInteger parses base 10 string as an integer of the given bit size.
+func parseInteger(s string, bitSize int) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, bitSize)
+	if err != nil {
+		return 0, parseNumberError(s)
+	}
+
+	return n, nil
+}
+
+// truncateDouble truncates float64 value to integer in the given range.
+func truncateDouble(v float64, minValue, maxValue int64) (int64, error) {
+	switch {
+	case math.IsNaN(v):
+		return 0, newOperatorError(
+			ErrConversionFailure,
+			"$convert",
+			"Attempt to convert NaN value to integer type in $convert with no onError value",
+		)
+	case math.IsInf(v, 0):
+		return 0, newOperatorError(
+			ErrConversionFailure,
+			"$convert",
+			"Attempt to convert infinity value to integer type in $convert with no onError value",
+		)
+	}
+
+	v = math.Trunc(v)
+
+	// float64(math.MaxInt64) is rounded up to 2^63
+	if v < float64(minValue) || v >= float64(maxValue)+1 {
+		return 0, overflowError(v)
+	}
+
+	return int64(v), nil
+}
+
+// truncateDecimal truncates Decimal128 value to integer in the given range.
+func truncateDecimal(v types.Decimal128, minValue, maxValue int64) (int64, error) {
+	switch {
+	case v.IsNaN():
+		return 0, newOperatorError(
+			ErrConversionFailure,
+			"$convert",
+			"Attempt to convert NaN value to integer type in $convert with no onError value",
+		)
+	case v.IsInf(0):
+		return 0, newOperatorError(
+			ErrConversionFailure,
+			"$convert",
+			"Attempt to convert infinity value to integer type in $convert with no onError value",
+		)
+	}
+
+	n := roundDecimal(v, 0, decimalRoundDown).Rat().Num()
+
+	if !n.IsInt64() || n.Int64() < minValue || n.Int64() > maxValue {
+		return 0, overflowError(v)
+	}
+
+	return n.Int64(), nil
+}
+
+// formatDouble formats float64 value as string the same way as MongoDB does.
+func formatDouble(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	}
+
+	if exp := math.Floor(math.Log10(math.Abs(v))); v == 0 || (exp >= -5 && exp < 17) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// parseNumberError returns ConversionFailure error for a string that is not a valid number.
+func parseNumberError(s string) error {
+	return newOperatorError(
+		ErrConversionFailure,
+		"$convert",
+		fmt.Sprintf("Failed to parse number '%s' in $convert with no onError value", s),
+	)
+}
+
+// overflowError returns ConversionFailure error for a number that does not fit into the target type.
+func overflowError(v any) error {
+	return newOperatorError(
+		ErrConversionFailure,
+		"$convert",
+		fmt.Sprintf("Conversion would overflow target type in $convert with no onError value: %s", types.FormatAnyValue(v)),
+	)
+}
+
+// unsupportedConversionError returns ConversionFailure error for unsupported conversion.
+func unsupportedConversionError(v any, to handlerparams.TypeCode) error {
+	return newOperatorError(
+		ErrConversionFailure,
+		"$convert",
+		fmt.Sprintf(
+			"Unsupported conversion from %s to %s in $convert with no onError value",
+			handlerparams.AliasFromType(v), to.String(),
+		),
+	)
+}
+
+// check interfaces
+var (
+	_ Operator = (*convert)(nil)
+)
@@ -0,0 +1,441 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // time zone database is not always available in containers
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// dateFormatDefault is the default format of `$dateToString` without timezone.
+const dateFormatDefault = "%Y-%m-%dT%H:%M:%S.%LZ"
+
+// dateFormatDefaultTimezone is the default format of `$dateToString` with timezone.
+const dateFormatDefaultTimezone = "%Y-%m-%dT%H:%M:%S.%L"
+
+// dateFormatSpecifiers contains format specifiers supported by `$dateToString`.
+const dateFormatSpecifiers = "bBdGHjLmMSuUVwYzZ%"
+
+// dateParseSpecifiers contains format specifiers supported by `$dateFromString`.
+const dateParseSpecifiers = "bBdGHLmMSuVYzZ%"
+
+// timezoneOffsetRe matches UTC offsets like `+03`, `-0330` and `+03:30`.
+var timezoneOffsetRe = regexp.MustCompile(`^([+-])(\d{2}):?(\d{2})?$`)
+
+// errDateStringOffset indicates that date string contains UTC offset and timezone is also specified.
+var errDateStringOffset = errors.New("you cannot pass in a date/time string with GMT offset together with a timezone argument")
+
+// evaluateTimezone evaluates timezone argument of date operators.
+// It returns nil location if argument is null or missing, and UTC if it is not set.
+func evaluateTimezone(name string, doc *types.Document, arg any) (*time.Location, error) {
+	if arg == nil {
+		return time.UTC, nil
+	}
+
+	v, err := evaluate(doc, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := v.(type) {
+	case types.NullType:
+		return nil, nil
+	case string:
+		return parseTimezone(name, v)
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTimeZoneNotString,
+			fmt.Sprintf("timezone must evaluate to a string, found %s", handlerparams.AliasFromType(v)),
+			name,
+		)
+	}
+}
+
+// parseTimezone returns location for Olson time zone identifier or UTC offset.
+func parseTimezone(name, tz string) (*time.Location, error) {
+	if m := timezoneOffsetRe.FindStringSubmatch(tz); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+
+		offset := hours*60*60 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+
+		return time.FixedZone(tz, offset), nil
+	}
+
+	if tz != "" && tz != "Local" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc, nil
+		}
+	}
+
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrUnrecognizedTimeZone,
+		fmt.Sprintf("unrecognized time zone identifier: \"%s\"", tz),
+		name,
+	)
+}
+
+// validateDateFormat checks that the format string contains only given specifiers.
+func validateDateFormat(name, format, specifiers string) error {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		if i == len(format)-1 {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatUnmatchedPercent,
+				"Unmatched '%' at end of format string",
+				name,
+			)
+		}
+
+		i++
+
+		if !strings.ContainsRune(specifiers, rune(format[i])) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFormatInvalidCharacter,
+				fmt.Sprintf("Invalid format character '%%%c' in format string", format[i]),
+				name,
+			)
+		}
+	}
+
+	return nil
+}
+
+// formatDate formats date using validated format string.
+func formatDate(t time.Time, format string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			sb.WriteByte(format[i])
+			continue
+		}
+
+		i++
+
+		switch format[i] {
+		case 'b':
+			sb.WriteString(t.Month().String()[:3])
+		case 'B':
+			sb.WriteString(t.Month().String())
+		case 'd':
+			fmt.Fprintf(&sb, "%02d", t.Day())
+		case 'G':
+			year, _ := t.ISOWeek()
+			fmt.Fprintf(&sb, "%04d", year)
+		case 'H':
+			fmt.Fprintf(&sb, "%02d", t.Hour())
+		case 'j':
+			fmt.Fprintf(&sb, "%03d", t.YearDay())
+		case 'L':
+			fmt.Fprintf(&sb, "%03d", t.Nanosecond()/int(time.Millisecond))
+		case 'm':
+			fmt.Fprintf(&sb, "%02d", int(t.Month()))
+		case 'M':
+			fmt.Fprintf(&sb, "%02d", t.Minute())
+		case 'S':
+			fmt.Fprintf(&sb, "%02d", t.Second())
+		case 'u':
+			fmt.Fprintf(&sb, "%d", (int(t.Weekday())+6)%7+1)
+		case 'U':
+			fmt.Fprintf(&sb, "%02d", (t.YearDay()+6-int(t.Weekday()))/7)
+		case 'V':
+			_, week := t.ISOWeek()
+			fmt.Fprintf(&sb, "%02d", week)
+		case 'w':
+			fmt.Fprintf(&sb, "%d", int(t.Weekday())+1)
+		case 'Y':
+			fmt.Fprintf(&sb, "%04d", t.Year())
+		case 'z':
+			_, offset := t.Zone()
+			sign := '+'
+
+			if offset < 0 {
+				sign = '-'
+				offset = -offset
+			}
+
+			fmt.Fprintf(&sb, "%c%02d%02d", sign, offset/3600, offset%3600/60)
+		case 'Z':
+			_, offset := t.Zone()
+			fmt.Fprintf(&sb, "%+d", offset/60)
+		case '%':
+			sb.WriteByte('%')
+		default:
+			panic(fmt.Sprintf("unexpected format character %q", format[i]))
+		}
+	}
+
+	return sb.String()
+}
+
+// dateStringLayouts contains layouts of date strings recognized without format.
+// Layouts with UTC offset go first.
+var dateStringLayouts = []struct {
+	layout    string
+	hasOffset bool
+}{
+	{"2006-01-02T15:04:05.999999999Z07:00", true},
+	{"2006-01-02T15:04:05.999999999Z0700", true},
+	{"2006-01-02T15:04:05.999999999Z07", true},
+	{"2006-01-02 15:04:05.999999999Z07:00", true},
+	{"2006-01-02 15:04:05.999999999 Z0700", true},
+	{"2006-01-02T15:04Z07:00", true},
+	{"2006-01-02T15:04:05.999999999", false},
+	{"2006-01-02 15:04:05.999999999", false},
+	{"2006-01-02T15:04", false},
+	{"2006-01-02 15:04", false},
+	{"2006-01-02", false},
+	{"2006/01/02 15:04:05.999999999", false},
+	{"2006/01/02", false},
+	{"January 2, 2006 15:04:05", false},
+	{"January 2, 2006", false},
+	{"Jan 2, 2006 15:04:05", false},
+	{"Jan 2, 2006", false},
+	{"2 January 2006", false},
+	{"2 Jan 2006", false},
+}
+
+// parseDateString parses date string in one of recognized layouts.
+// Date strings without UTC offset are parsed in the given location.
+//
+// It returns errDateStringOffset if the string contains UTC offset and the location is not UTC.
+func parseDateString(s string, loc *time.Location) (time.Time, error) {
+	for _, l := range dateStringLayouts {
+		t, err := time.ParseInLocation(l.layout, s, loc)
+		if err != nil {
+			continue
+		}
+
+		if l.hasOffset && loc != time.UTC {
+			return time.Time{}, errDateStringOffset
+		}
+
+		return t.UTC().Truncate(time.Millisecond), nil
+	}
+
+	return time.Time{}, fmt.Errorf("Error parsing date string '%s'", s)
+}
+
+// parseDateFormat parses date string using validated format string.
+// Date strings without UTC offset are parsed in the given location.
+//
+// It returns errDateStringOffset if the string contains UTC offset and the location is not UTC.
+func parseDateFormat(s, format string, loc *time.Location) (time.Time, error) {
+	year, month, day := 1970, 1, 1
+	var hour, minute, second, ms int
+	var isoYear, isoWeek, isoDay int
+	var offset *int
+
+	errParse := fmt.Errorf("Error parsing date string '%s' with format '%s'", s, format)
+
+	// number reads up to n digits
+	number := func(n int) (int, bool) {
+		var i int
+		for i < n && i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+
+		if i == 0 {
+			return 0, false
+		}
+
+		v, _ := strconv.Atoi(s[:i])
+		s = s[i:]
+
+		return v, true
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || format[i+1] == '%' {
+			if format[i] == '%' {
+				i++
+			}
+
+			if s == "" || s[0] != format[i] {
+				return time.Time{}, errParse
+			}
+
+			s = s[1:]
+
+			continue
+		}
+
+		i++
+
+		var ok bool
+
+		switch format[i] {
+		case 'b', 'B':
+			ok = false
+
+			for m := time.January; m <= time.December; m++ {
+				name := m.String()
+				if format[i] == 'b' {
+					name = name[:3]
+				}
+
+				if len(s) >= len(name) && strings.EqualFold(s[:len(name)], name) {
+					month, ok = int(m), true
+					s = s[len(name):]
+
+					break
+				}
+			}
+		case 'd':
+			day, ok = number(2)
+		case 'G':
+			isoYear, ok = number(4)
+		case 'H':
+			hour, ok = number(2)
+		case 'L':
+			ms, ok = number(3)
+		case 'm':
+			month, ok = number(2)
+		case 'M':
+			minute, ok = number(2)
+		case 'S':
+			second, ok = number(2)
+		case 'u':
+			isoDay, ok = number(1)
+		case 'V':
+			isoWeek, ok = number(2)
+		case 'Y':
+			year, ok = number(4)
+		case 'z':
+			ok = s != ""
+
+			if ok && s[0] == 'Z' {
+				s = s[1:]
+				offset = new(int)
+
+				break
+			}
+
+			var end int
+			for end < len(s) && strings.ContainsRune("+-:0123456789", rune(s[end])) {
+				end++
+			}
+
+			var z *time.Location
+			if z, ok = parseOffset(s[:end]); ok {
+				_, o := time.Time{}.In(z).Zone()
+				offset = &o
+				s = s[end:]
+			}
+		case 'Z':
+			if ok = s != "" && (s[0] == '+' || s[0] == '-'); !ok {
+				break
+			}
+
+			sign := s[0]
+			s = s[1:]
+
+			var minutes int
+			if minutes, ok = number(4); ok {
+				o := minutes * 60
+				if sign == '-' {
+					o = -o
+				}
+
+				offset = &o
+			}
+		default:
+			panic(fmt.Sprintf("unexpected format character %q", format[i]))
+		}
+
+		if !ok {
+			return time.Time{}, errParse
+		}
+	}
+
+	if s != "" {
+		return time.Time{}, errParse
+	}
+
+	if offset != nil {
+		if loc != time.UTC {
+			return time.Time{}, errDateStringOffset
+		}
+
+		loc = time.FixedZone("", *offset)
+	}
+
+	var t time.Time
+
+	if isoYear != 0 || isoWeek != 0 || isoDay != 0 {
+		if isoYear == 0 {
+			isoYear = year
+		}
+
+		isoWeek = max(isoWeek, 1)
+		isoDay = max(isoDay, 1)
+
+		// January 4th is always in the first ISO week
+		jan4 := time.Date(isoYear, time.January, 4, hour, minute, second, ms*int(time.Millisecond), loc)
+		monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+		t = monday.AddDate(0, 0, (isoWeek-1)*7+isoDay-1)
+	} else {
+		t = time.Date(year, time.Month(month), day, hour, minute, second, ms*int(time.Millisecond), loc)
+	}
+
+	// time.Date normalizes out-of-range values, MongoDB does not
+	if t.Month() != time.Month(month) && isoWeek == 0 || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, errParse
+	}
+
+	return t.UTC(), nil
+}
+
+// parseOffset parses UTC offset like `+03`, `-0330` and `+03:30`.
+func parseOffset(s string) (*time.Location, bool) {
+	if !timezoneOffsetRe.MatchString(s) {
+		return nil, false
+	}
+
+	loc, err := parseTimezone("", s)
+
+	return loc, err == nil
+}
+
+// dateFromValue returns date for date, timestamp and ObjectID values.
+func dateFromValue(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case types.Timestamp:
+		return v.Time().UTC(), true
+	case types.ObjectID:
+		sec := int64(v[0])<<24 | int64(v[1])<<16 | int64(v[2])<<8 | int64(v[3])
+		return time.Unix(sec, 0).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
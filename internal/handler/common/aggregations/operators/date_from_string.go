@@ -0,0 +1,216 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// dateFromString represents `$dateFromString` operator.
+type dateFromString struct {
+	dateString any
+	format     any
+	timezone   any
+	onError    any
+	onNull     any
+	hasOnError bool
+	hasOnNull  bool
+}
+
+// newDateFromString returns `$dateFromString` operator.
+func newDateFromString(args ...any) (Operator, error) {
+	doc, found := namedArgs(args)
+	if doc == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromStringNotObject,
+			fmt.Sprintf("$dateFromString only supports an object as an argument, found: %s", found),
+			"$dateFromString",
+		)
+	}
+
+	op := new(dateFromString)
+
+	var hasDateString bool
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "dateString":
+			op.dateString = v
+			hasDateString = true
+		case "format":
+			op.format = v
+		case "timezone":
+			op.timezone = v
+		case "onError":
+			op.onError = v
+			op.hasOnError = true
+		case "onNull":
+			op.onNull = v
+			op.hasOnNull = true
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromStringUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateFromString: %s", k),
+				"$dateFromString",
+			)
+		}
+	}
+
+	if !hasDateString {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateFromStringMissingDateString,
+			"$dateFromString requires that 'dateString' be present",
+			"$dateFromString",
+		)
+	}
+
+	// validate constant format and timezone early; expressions are evaluated for each document
+	if format, ok := op.format.(string); ok && !strings.HasPrefix(format, "$") {
+		if err := validateDateFormat("$dateFromString", format, dateParseSpecifiers); err != nil {
+			return nil, err
+		}
+	}
+
+	if tz, ok := op.timezone.(string); ok && !strings.HasPrefix(tz, "$") {
+		if _, err := parseTimezone("$dateFromString", tz); err != nil {
+			return nil, err
+		}
+	}
+
+	return op, nil
+}
+
+// Process implements Operator interface.
+//
+// If the format or the timezone is null or missing field, null is returned.
+// If the date string is null or missing field, onNull value or null is returned.
+// If the date string could not be parsed, onError value is returned if it is set.
+func (d *dateFromString) Process(doc *types.Document) (any, error) {
+	dateString, err := evaluate(doc, d.dateString)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := evaluateTimezone("$dateFromString", doc, d.timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var format string
+	var hasFormat bool
+
+	if d.format != nil {
+		var v any
+		if v, err = evaluate(doc, d.format); err != nil {
+			return nil, err
+		}
+
+		switch v := v.(type) {
+		case types.NullType:
+			return types.Null, nil
+		case string:
+			if err = validateDateFormat("$dateFromString", v, dateParseSpecifiers); err != nil {
+				return nil, err
+			}
+
+			format, hasFormat = v, true
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateFromStringFormatNotString,
+				fmt.Sprintf("$dateFromString requires that 'format' be a string, found: %s", handlerparams.AliasFromType(v)),
+				"$dateFromString",
+			)
+		}
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	if dateString == types.Null {
+		if d.hasOnNull {
+			return evaluate(doc, d.onNull)
+		}
+
+		return types.Null, nil
+	}
+
+	res, err := parseDateFromString(dateString, format, hasFormat, loc)
+	if err != nil {
+		if d.hasOnError {
+			return evaluate(doc, d.onError)
+		}
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// parseDateFromString parses the date string with the given format or in one of the recognized layouts.
+func parseDateFromString(dateString any, format string, hasFormat bool, loc *time.Location) (time.Time, error) {
+	s, ok := dateString.(string)
+	if !ok {
+		return time.Time{}, newOperatorError(
+			ErrConversionFailure,
+			"$dateFromString",
+			fmt.Sprintf(
+				"$dateFromString requires that 'dateString' be a string, found: %s",
+				handlerparams.AliasFromType(dateString),
+			),
+		)
+	}
+
+	var t time.Time
+	var err error
+
+	if hasFormat {
+		t, err = parseDateFormat(s, format, loc)
+	} else {
+		t, err = parseDateString(s, loc)
+	}
+
+	if err != nil {
+		return time.Time{}, newOperatorError(ErrConversionFailure, "$dateFromString", err.Error())
+	}
+
+	return t.Truncate(time.Millisecond), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateFromString)(nil)
+)
@@ -0,0 +1,181 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// dateToString represents `$dateToString` operator.
+type dateToString struct {
+	date      any
+	format    any
+	timezone  any
+	onNull    any
+	hasOnNull bool
+}
+
+// newDateToString returns `$dateToString` operator.
+func newDateToString(args ...any) (Operator, error) {
+	doc, _ := namedArgs(args)
+	if doc == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateToStringNotObject,
+			"$dateToString only supports an object as its argument",
+			"$dateToString",
+		)
+	}
+
+	op := new(dateToString)
+
+	var hasDate bool
+
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "date":
+			op.date = v
+			hasDate = true
+		case "format":
+			op.format = v
+		case "timezone":
+			op.timezone = v
+		case "onNull":
+			op.onNull = v
+			op.hasOnNull = true
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrDateToStringUnknownArgument,
+				fmt.Sprintf("Unrecognized argument to $dateToString: %s", k),
+				"$dateToString",
+			)
+		}
+	}
+
+	if !hasDate {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateToStringMissingDate,
+			"Missing 'date' parameter to $dateToString",
+			"$dateToString",
+		)
+	}
+
+	// validate constant format and timezone early; expressions are evaluated for each document
+	if format, ok := op.format.(string); ok && !strings.HasPrefix(format, "$") {
+		if err := validateDateFormat("$dateToString", format, dateFormatSpecifiers); err != nil {
+			return nil, err
+		}
+	}
+
+	if tz, ok := op.timezone.(string); ok && !strings.HasPrefix(tz, "$") {
+		if _, err := parseTimezone("$dateToString", tz); err != nil {
+			return nil, err
+		}
+	}
+
+	return op, nil
+}
+
+// Process implements Operator interface.
+//
+// If the format or the timezone is null or missing field, null is returned.
+// If the date is null or missing field, onNull value or null is returned.
+func (d *dateToString) Process(doc *types.Document) (any, error) {
+	date, err := evaluate(doc, d.date)
+	if err != nil {
+		return nil, err
+	}
+
+	format := any(dateFormatDefault)
+
+	if d.format != nil {
+		if format, err = evaluate(doc, d.format); err != nil {
+			return nil, err
+		}
+	} else if d.timezone != nil {
+		format = dateFormatDefaultTimezone
+	}
+
+	loc, err := evaluateTimezone("$dateToString", doc, d.timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	var formatStr string
+
+	switch f := format.(type) {
+	case types.NullType:
+		return types.Null, nil
+	case string:
+		formatStr = f
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDateToStringFormatNotString,
+			fmt.Sprintf("$dateToString requires that 'format' be a string, found: %s", handlerparams.AliasFromType(f)),
+			"$dateToString",
+		)
+	}
+
+	if err = validateDateFormat("$dateToString", formatStr, dateFormatSpecifiers); err != nil {
+		return nil, err
+	}
+
+	if loc == nil {
+		return types.Null, nil
+	}
+
+	if date == types.Null {
+		if d.hasOnNull {
+			return evaluate(doc, d.onNull)
+		}
+
+		return types.Null, nil
+	}
+
+	t, ok := dateFromValue(date)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrCannotConvertToDate,
+			fmt.Sprintf("can't convert from BSON type %s to Date", handlerparams.AliasFromType(date)),
+			"$dateToString",
+		)
+	}
+
+	return formatDate(t.In(loc), formatStr), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*dateToString)(nil)
+)
@@ -69,8 +69,7 @@ func (e *expr) validateExpr(exprValue any) error {
 				return processExprOperatorErrors(err, e.errArgument)
 			}
 
-			_, err = op.Process(nil)
-			if err != nil {
+			if err = Validate(op, nil); err != nil {
 				// TODO https://github.com/FerretDB/FerretDB/issues/3129
 				return processExprOperatorErrors(err, e.errArgument)
 			}
@@ -146,8 +145,13 @@ func (e *expr) processExpr(exprValue any, doc *types.Document) (any, error) {
 
 			v, err := op.Process(doc)
 			if err != nil {
-				// Process does not return error for existing operators
-				return nil, lazyerrors.Error(err)
+				// for example, a value could not be converted by $convert
+				return nil, processExprOperatorErrors(err, e.errArgument)
+			}
+
+			if v == nil {
+				// missing value is set to null
+				return types.Null, nil
 			}
 
 			return v, nil
@@ -268,6 +272,24 @@ func processExprOperatorErrors(err error, argument string) error {
 				opErr.Error(),
 				argument,
 			)
+		case ErrFailedToParse:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				opErr.Error(),
+				argument,
+			)
+		case ErrBadValue:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				opErr.Error(),
+				argument,
+			)
+		case ErrConversionFailure:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConversionFailure,
+				opErr.Error(),
+				argument,
+			)
 		}
 
 	case errors.As(err, &exErr):
@@ -285,10 +307,9 @@ func processExprOperatorErrors(err error, argument string) error {
 				argument,
 			)
 		case aggregations.ErrUndefinedVariable:
-			// TODO https://github.com/FerretDB/FerretDB/issues/2275
 			return handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"Aggregation expression variables are not implemented yet",
+				handlererrors.ErrUndefinedVariable,
+				fmt.Sprintf("Use of undefined variable: %s", exErr.Name()),
 				argument,
 			)
 		case aggregations.ErrEmptyVariable:
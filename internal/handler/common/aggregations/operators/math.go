@@ -0,0 +1,504 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// mathFunc returns the result of unary math operator for a number.
+type mathFunc func(name string, v any) (any, error)
+
+// mathOp represents unary math operators like `$abs`, `$sqrt` and `$sin`.
+type mathOp struct {
+	arg  any
+	name string
+	f    mathFunc
+}
+
+// newMath returns a function that creates unary math operator with the given name and function.
+func newMath(name string, f mathFunc) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				name,
+				fmt.Sprintf("Expression %s takes exactly 1 arguments. %d were passed in.", name, len(args)),
+			)
+		}
+
+		return &mathOp{
+			arg:  args[0],
+			name: name,
+			f:    f,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// If the argument is null or missing, null is returned.
+func (m *mathOp) Process(doc *types.Document) (any, error) {
+	v, err := evaluate(doc, m.arg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.(type) {
+	case types.NullType:
+		return types.Null, nil
+	case float64, int32, int64, types.Decimal128:
+		return m.f(m.name, v)
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMathNotNumber,
+			fmt.Sprintf("%s only supports numeric types, not %s", m.name, handlerparams.AliasFromType(v)),
+			m.name,
+		)
+	}
+}
+
+// mathAbs returns the absolute value of a number.
+// The type of the number is preserved, apart from the minimal int that becomes long.
+func mathAbs(name string, v any) (any, error) {
+	switch v := v.(type) {
+	case float64:
+		return math.Abs(v), nil
+	case types.Decimal128:
+		return v.Abs(), nil
+	case int32:
+		if v == math.MinInt32 {
+			return -int64(v), nil
+		}
+
+		return max(v, -v), nil
+	case int64:
+		if v == math.MinInt64 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrAbsLongMin,
+				"can't take $abs of long long min",
+				name,
+			)
+		}
+
+		return max(v, -v), nil
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// mathRound returns a function that applies the given rounding function to doubles,
+// and rounds decimals to integral values in the given direction.
+// Integers are returned as is.
+func mathRound(f func(float64) float64, mode decimalRounding) mathFunc {
+	return func(_ string, v any) (any, error) {
+		switch v := v.(type) {
+		case float64:
+			return f(v), nil
+		case types.Decimal128:
+			return roundDecimal(v, 0, mode), nil
+		default:
+			return v, nil
+		}
+	}
+}
+
+// mathDouble returns a function that applies the given function to a number converted to double.
+// The domain function returns an error if the number is out of the function domain;
+// NaN is always in the domain and results in NaN.
+// The result for a decimal is converted back to decimal.
+func mathDouble(f func(float64) float64, domain func(name string, v float64) error) mathFunc {
+	return func(name string, v any) (any, error) {
+		d := mathToDouble(v)
+
+		if math.IsNaN(d) {
+			return mathResult(math.NaN(), v), nil
+		}
+
+		if domain != nil {
+			if err := domain(name, d); err != nil {
+				return nil, err
+			}
+		}
+
+		return mathResult(f(d), v), nil
+	}
+}
+
+// mathSqrtDomain checks that the argument of `$sqrt` is not negative.
+func mathSqrtDomain(name string, v float64) error {
+	if v >= 0 {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrSqrtNegative,
+		"$sqrt's argument must be greater than or equal to 0",
+		name,
+	)
+}
+
+// mathPositiveDomain returns a function that checks that the argument is positive.
+func mathPositiveDomain(code handlererrors.ErrorCode) func(name string, v float64) error {
+	return func(name string, v float64) error {
+		if v > 0 {
+			return nil
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			code,
+			fmt.Sprintf("%s's argument must be a positive number, but is %s", name, formatMathValue(v)),
+			name,
+		)
+	}
+}
+
+// mathRangeDomain returns a function that checks that the argument of trigonometric function
+// is in the given range.
+func mathRangeDomain(minValue float64, minInclusive bool, maxValue float64, maxInclusive bool) func(string, float64) error {
+	return func(name string, v float64) error {
+		if (v > minValue || (minInclusive && v == minValue)) && (v < maxValue || (maxInclusive && v == maxValue)) {
+			return nil
+		}
+
+		lower, upper := "(", ")"
+		if minInclusive {
+			lower = "["
+		}
+
+		if maxInclusive {
+			upper = "]"
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTrigonometricOutOfRange,
+			fmt.Sprintf(
+				"cannot apply %s to %s, value must in %s%s,%s%s",
+				name, formatMathValue(v), lower, formatMathValue(minValue), formatMathValue(maxValue), upper,
+			),
+			name,
+		)
+	}
+}
+
+// Domains of math functions.
+var (
+	// mathLnDomain is the domain of `$ln`.
+	mathLnDomain = mathPositiveDomain(handlererrors.ErrLnNotPositive)
+
+	// mathLog10Domain is the domain of `$log10`.
+	mathLog10Domain = mathPositiveDomain(handlererrors.ErrLog10NotPositive)
+
+	// mathFiniteDomain is the domain of `$sin`, `$cos` and `$tan`.
+	mathFiniteDomain = mathRangeDomain(math.Inf(-1), false, math.Inf(1), false)
+
+	// mathUnitDomain is the domain of `$asin`, `$acos` and `$atanh`.
+	mathUnitDomain = mathRangeDomain(-1, true, 1, true)
+
+	// mathAcoshDomain is the domain of `$acosh`.
+	mathAcoshDomain = mathRangeDomain(1, true, math.Inf(1), true)
+)
+
+// degreesToRadians converts degrees to radians.
+func degreesToRadians(v float64) float64 {
+	return v * math.Pi / 180
+}
+
+// radiansToDegrees converts radians to degrees.
+func radiansToDegrees(v float64) float64 {
+	return v * 180 / math.Pi
+}
+
+// mathBinaryOp represents binary math operators `$log`, `$pow` and `$atan2`.
+type mathBinaryOp struct {
+	first  any
+	second any
+	name   string
+}
+
+// newMathBinary returns a function that creates binary math operator with the given name.
+func newMathBinary(name string) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 2 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				name,
+				fmt.Sprintf("Expression %s takes exactly 2 arguments. %d were passed in.", name, len(args)),
+			)
+		}
+
+		return &mathBinaryOp{
+			first:  args[0],
+			second: args[1],
+			name:   name,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// If any argument is null or missing, null is returned.
+func (m *mathBinaryOp) Process(doc *types.Document) (any, error) {
+	first, err := evaluate(doc, m.first)
+	if err != nil {
+		return nil, err
+	}
+
+	second, err := evaluate(doc, m.second)
+	if err != nil {
+		return nil, err
+	}
+
+	if first == types.Null || second == types.Null {
+		return types.Null, nil
+	}
+
+	switch m.name {
+	case "$log":
+		return mathLog(first, second)
+	case "$pow":
+		return mathPow(first, second)
+	case "$atan2":
+		if !isNumber(first) || !isNumber(second) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrAtan2NotNumber,
+				fmt.Sprintf(
+					"$atan2 only supports numeric types, not %s and %s",
+					handlerparams.AliasFromType(first), handlerparams.AliasFromType(second),
+				),
+				m.name,
+			)
+		}
+
+		return mathResult(math.Atan2(mathToDouble(first), mathToDouble(second)), first, second), nil
+	default:
+		panic(fmt.Sprintf("unexpected operator %s", m.name))
+	}
+}
+
+// mathLog returns the logarithm of a number in the given base.
+func mathLog(number, base any) (any, error) {
+	if !isNumber(number) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogNotNumber,
+			fmt.Sprintf("$log's argument must be numeric, not %s", handlerparams.AliasFromType(number)),
+			"$log",
+		)
+	}
+
+	if !isNumber(base) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogBaseNotNumber,
+			fmt.Sprintf("$log's base must be numeric, not %s", handlerparams.AliasFromType(base)),
+			"$log",
+		)
+	}
+
+	n, b := mathToDouble(number), mathToDouble(base)
+
+	if math.IsNaN(n) || math.IsNaN(b) {
+		return mathResult(math.NaN(), number, base), nil
+	}
+
+	if n <= 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogNotPositive,
+			fmt.Sprintf("$log's argument must be a positive number, but is %s", formatMathValue(n)),
+			"$log",
+		)
+	}
+
+	if b <= 0 || b == 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrLogInvalidBase,
+			fmt.Sprintf("$log's base must be a positive number not equal to 1, but is %s", formatMathValue(b)),
+			"$log",
+		)
+	}
+
+	return mathResult(math.Log(n)/math.Log(b), number, base), nil
+}
+
+// mathPow returns the base raised to the exponent.
+//
+// If both arguments are integers, the result is an integer if it fits into long,
+// and int if both arguments are ints and the result fits into int.
+// Otherwise, the result is a decimal if any argument is a decimal, and a double if not.
+func mathPow(base, exponent any) (any, error) {
+	if !isNumber(base) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowBaseNotNumber,
+			fmt.Sprintf("$pow's base must be numeric, not %s", handlerparams.AliasFromType(base)),
+			"$pow",
+		)
+	}
+
+	if !isNumber(exponent) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowExponentNotNumber,
+			fmt.Sprintf("$pow's exponent must be numeric, not %s", handlerparams.AliasFromType(exponent)),
+			"$pow",
+		)
+	}
+
+	b, e := mathToDouble(base), mathToDouble(exponent)
+
+	if b == 0 && e < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPowZeroNegativeExponent,
+			"$pow cannot take a base of 0 and a negative exponent",
+			"$pow",
+		)
+	}
+
+	_, baseDecimal := base.(types.Decimal128)
+	_, exponentDecimal := exponent.(types.Decimal128)
+
+	if baseDecimal || exponentDecimal {
+		return mathResult(math.Pow(b, e), base, exponent), nil
+	}
+
+	_, baseDouble := base.(float64)
+	_, exponentDouble := exponent.(float64)
+
+	if baseDouble || exponentDouble {
+		return math.Pow(b, e), nil
+	}
+
+	_, baseLong := base.(int64)
+	_, exponentLong := exponent.(int64)
+	ints := !baseLong && !exponentLong
+
+	bi, ei := int64(b), int64(e)
+
+	switch {
+	case bi == 1:
+		return mathInteger(1, ints), nil
+	case bi == -1:
+		if ei%2 == 0 {
+			return mathInteger(1, ints), nil
+		}
+
+		return mathInteger(-1, ints), nil
+	case ei < 0:
+		return math.Pow(b, e), nil
+	}
+
+	// multiply magnitudes to detect overflow
+	neg := bi < 0 && ei%2 != 0
+	limit := uint64(math.MaxInt64)
+
+	if neg {
+		limit++
+	}
+
+	magnitude := uint64(bi)
+	if bi < 0 {
+		magnitude = uint64(-bi)
+	}
+
+	res := uint64(1)
+
+	for range ei {
+		hi, lo := bits.Mul64(res, magnitude)
+		if hi != 0 || lo > limit {
+			return math.Pow(b, e), nil
+		}
+
+		if res = lo; res == 0 {
+			break
+		}
+	}
+
+	if neg {
+		return mathInteger(int64(-res), ints), nil
+	}
+
+	return mathInteger(int64(res), ints), nil
+}
+
+// mathInteger returns int if ints is true and the value fits into int, long otherwise.
+func mathInteger(v int64, ints bool) any {
+	if ints && v >= math.MinInt32 && v <= math.MaxInt32 {
+		return int32(v)
+	}
+
+	return v
+}
+
+// isNumber returns true if the value is a number.
+func isNumber(v any) bool {
+	switch v.(type) {
+	case float64, int32, int64, types.Decimal128:
+		return true
+	default:
+		return false
+	}
+}
+
+// mathToDouble converts a number to double.
+func mathToDouble(v any) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case types.Decimal128:
+		return v.Float64()
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+// mathResult returns the result of a function computed in doubles.
+// Like MongoDB, it returns a decimal if any argument is a decimal.
+func mathResult(res float64, args ...any) any {
+	for _, arg := range args {
+		if _, ok := arg.(types.Decimal128); ok {
+			return types.NewDecimal128FromFloat64(res)
+		}
+	}
+
+	return res
+}
+
+// formatMathValue formats a double for error messages the same way as MongoDB does.
+func formatMathValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "nan"
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+// check interfaces
+var (
+	_ Operator = (*mathOp)(nil)
+	_ Operator = (*mathBinaryOp)(nil)
+)
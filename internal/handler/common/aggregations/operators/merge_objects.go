@@ -0,0 +1,79 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// mergeObjects represents `$mergeObjects` operator.
+type mergeObjects struct {
+	inputs []any
+}
+
+// newMergeObjects returns `$mergeObjects` operator.
+func newMergeObjects(args ...any) (Operator, error) {
+	return &mergeObjects{
+		inputs: args,
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// Fields of later documents override fields of earlier documents.
+// Null and missing inputs are ignored.
+func (m *mergeObjects) Process(doc *types.Document) (any, error) {
+	res := types.MakeDocument(0)
+
+	for _, arg := range m.inputs {
+		input, err := evaluate(doc, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		switch input := input.(type) {
+		case types.NullType:
+			continue
+		case *types.Document:
+			keys := input.Keys()
+			values := input.Values()
+
+			for i, k := range keys {
+				res.Set(k, values[i])
+			}
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMergeObjectsNotDocument,
+				fmt.Sprintf(
+					"$mergeObjects requires object inputs, but input %s is of type %s",
+					types.FormatAnyValue(input),
+					handlerparams.AliasFromType(input),
+				),
+				"$mergeObjects",
+			)
+		}
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*mergeObjects)(nil)
+)
@@ -0,0 +1,85 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// objectToArray represents `$objectToArray` operator.
+type objectToArray struct {
+	input any
+}
+
+// newObjectToArray returns `$objectToArray` operator.
+func newObjectToArray(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$objectToArray",
+			fmt.Sprintf("Expression $objectToArray takes exactly 1 arguments. %d were passed in.", len(args)),
+		)
+	}
+
+	return &objectToArray{
+		input: args[0],
+	}, nil
+}
+
+// Process implements Operator interface.
+//
+// It returns an array of `{k: <field name>, v: <field value>}` documents.
+// If the input is null or missing, null is returned.
+func (o *objectToArray) Process(doc *types.Document) (any, error) {
+	input, err := evaluate(doc, o.input)
+	if err != nil {
+		return nil, err
+	}
+
+	var d *types.Document
+
+	switch input := input.(type) {
+	case types.NullType:
+		return types.Null, nil
+	case *types.Document:
+		d = input
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrObjectToArrayNotDocument,
+			fmt.Sprintf("$objectToArray requires a document input, found: %s", handlerparams.AliasFromType(input)),
+			"$objectToArray",
+		)
+	}
+
+	keys := d.Keys()
+	values := d.Values()
+
+	res := types.MakeArray(len(keys))
+	for i, k := range keys {
+		res.Append(must.NotFail(types.NewDocument("k", k, "v", values[i])))
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*objectToArray)(nil)
+)
@@ -24,8 +24,14 @@ package operators
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
 	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -45,6 +51,9 @@ type newOperatorFunc func(args ...any) (Operator, error)
 // Operator is a common interface for standard aggregation operators.
 type Operator interface {
 	// Process document and returns the result of applying operator.
+	//
+	// Nil result without error is the missing value (for example, `$arrayElemAt` with out-of-bounds index).
+	// Callers like `$project` omit fields with that value; arguments of other operators treat it as null.
 	Process(in *types.Document) (any, error)
 }
 
@@ -101,7 +110,8 @@ func NewOperator(doc *types.Document) (Operator, error) {
 
 	var args []any
 
-	if arr, ok := expr.(*types.Array); ok {
+	// the whole $literal value is a single argument, even if it is an array
+	if arr, ok := expr.(*types.Array); ok && operator != "$literal" {
 		iter := arr.Iterator()
 		defer iter.Close()
 
@@ -136,164 +146,277 @@ func NewOperator(doc *types.Document) (Operator, error) {
 	}
 }
 
+// Validate processes the operator with the given document to check its arguments early,
+// before any documents are processed.
+//
+// Errors that depend on the document values (like a field of unexpected type) are not returned;
+// they are returned when the actual documents are processed.
+func Validate(op Operator, doc *types.Document) error {
+	_, err := op.Process(doc)
+
+	var cmdErr *handlererrors.CommandError
+	if errors.As(err, &cmdErr) {
+		return nil
+	}
+
+	return err
+}
+
+// isConstant returns true if the operator argument does not depend on the document,
+// so it could be validated early.
+func isConstant(arg any) bool {
+	switch arg := arg.(type) {
+	case *types.Document:
+		return !IsOperator(arg)
+	case string:
+		return !strings.HasPrefix(arg, "$")
+	default:
+		return true
+	}
+}
+
+// evaluate returns the value of the operator argument for the given document.
+//
+// Path expressions are evaluated, missing fields are returned as null.
+// Nested operators are processed.
+// Other values are returned as is.
+func evaluate(doc *types.Document, arg any) (any, error) {
+	v, err := evaluateOrMissing(doc, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return types.Null, nil
+	}
+
+	return v, nil
+}
+
+// evaluateOrMissing is like evaluate, but returns nil for missing fields and missing values of nested operators.
+func evaluateOrMissing(doc *types.Document, arg any) (any, error) {
+	switch arg := arg.(type) {
+	case *types.Document:
+		if !IsOperator(arg) {
+			return arg, nil
+		}
+
+		operator, err := NewOperator(arg)
+		if err != nil {
+			var opErr OperatorError
+			if errors.As(err, &opErr) && opErr.Code() == ErrInvalidExpression {
+				opErr.code = ErrInvalidNestedExpression
+				return nil, opErr
+			}
+
+			return nil, err
+		}
+
+		return operator.Process(doc)
+
+	case string:
+		if !strings.HasPrefix(arg, "$") {
+			return arg, nil
+		}
+
+		expression, err := aggregations.NewExpression(arg, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := expression.Evaluate(doc)
+		if err != nil {
+			return nil, nil
+		}
+
+		return v, nil
+
+	default:
+		return arg, nil
+	}
+}
+
+// namedArgs returns the document of named arguments for operators like `{$trim: {input: "$v"}}`.
+// If arguments are not a single document, it returns nil and the type alias of arguments for error messages.
+func namedArgs(args []any) (*types.Document, string) {
+	if len(args) != 1 {
+		return nil, handlerparams.TypeCodeArray.String()
+	}
+
+	doc, ok := args[0].(*types.Document)
+	if !ok {
+		return nil, handlerparams.AliasFromType(args[0])
+	}
+
+	return doc, ""
+}
+
+// Unsupported returns sorted names of known standard aggregation operators that are not supported yet.
+func Unsupported() []string {
+	return slices.Sorted(maps.Keys(unsupportedOperators))
+}
+
 // Operators maps all standard aggregation operators.
 var Operators = map[string]newOperatorFunc{
 	// sorted alphabetically
-	"$sum":  newSum,
-	"$type": newType,
+	"$abs":              newMath("$abs", mathAbs),
+	"$acos":             newMath("$acos", mathDouble(math.Acos, mathUnitDomain)),
+	"$acosh":            newMath("$acosh", mathDouble(math.Acosh, mathAcoshDomain)),
+	"$allElementsTrue":  newElementsTrue("$allElementsTrue", true),
+	"$anyElementTrue":   newElementsTrue("$anyElementTrue", false),
+	"$arrayElemAt":      newArrayElemAt,
+	"$arrayToObject":    newArrayToObject,
+	"$asin":             newMath("$asin", mathDouble(math.Asin, mathUnitDomain)),
+	"$asinh":            newMath("$asinh", mathDouble(math.Asinh, nil)),
+	"$atan":             newMath("$atan", mathDouble(math.Atan, nil)),
+	"$atan2":            newMathBinary("$atan2"),
+	"$atanh":            newMath("$atanh", mathDouble(math.Atanh, mathUnitDomain)),
+	"$ceil":             newMath("$ceil", mathRound(math.Ceil, decimalRoundCeiling)),
+	"$concatArrays":     newConcatArrays,
+	"$convert":          newConvert,
+	"$cos":              newMath("$cos", mathDouble(math.Cos, mathFiniteDomain)),
+	"$cosh":             newMath("$cosh", mathDouble(math.Cosh, nil)),
+	"$dateFromString":   newDateFromString,
+	"$dateToString":     newDateToString,
+	"$degreesToRadians": newMath("$degreesToRadians", mathDouble(degreesToRadians, nil)),
+	"$exp":              newMath("$exp", mathDouble(math.Exp, nil)),
+	"$first":            newArrayEdge("$first", 0),
+	"$floor":            newMath("$floor", mathRound(math.Floor, decimalRoundFloor)),
+	"$indexOfArray":     newIndexOfArray,
+	"$isNumber":         newIsNumber,
+	"$last":             newArrayEdge("$last", -1),
+	"$literal":          newLiteral,
+	"$ln":               newMath("$ln", mathDouble(math.Log, mathLnDomain)),
+	"$log":              newMathBinary("$log"),
+	"$log10":            newMath("$log10", mathDouble(math.Log10, mathLog10Domain)),
+	"$ltrim":            newTrim("$ltrim", true, false),
+	"$mergeObjects":     newMergeObjects,
+	"$objectToArray":    newObjectToArray,
+	"$pow":              newMathBinary("$pow"),
+	"$radiansToDegrees": newMath("$radiansToDegrees", mathDouble(radiansToDegrees, nil)),
+	"$rand":             newRand,
+	"$range":            newRange,
+	"$regexFind":        newRegex("$regexFind", regexFind),
+	"$regexFindAll":     newRegex("$regexFindAll", regexFindAll),
+	"$regexMatch":       newRegex("$regexMatch", regexMatch),
+	"$replaceAll":       newReplace("$replaceAll", true),
+	"$replaceOne":       newReplace("$replaceOne", false),
+	"$round":            newRound("$round", false),
+	"$rtrim":            newTrim("$rtrim", false, true),
+	"$setDifference":    newSet("$setDifference", setDifference),
+	"$setIntersection":  newSet("$setIntersection", setIntersection),
+	"$setIsSubset":      newSet("$setIsSubset", setIsSubset),
+	"$setUnion":         newSet("$setUnion", setUnion),
+	"$sin":              newMath("$sin", mathDouble(math.Sin, mathFiniteDomain)),
+	"$sinh":             newMath("$sinh", mathDouble(math.Sinh, nil)),
+	"$slice":            newSlice,
+	"$split":            newSplit,
+	"$sqrt":             newMath("$sqrt", mathDouble(math.Sqrt, mathSqrtDomain)),
+	"$sum":              newSum,
+	"$tan":              newMath("$tan", mathDouble(math.Tan, mathFiniteDomain)),
+	"$tanh":             newMath("$tanh", mathDouble(math.Tanh, nil)),
+	"$toBool":           newConvertTo("$toBool", handlerparams.TypeCodeBool),
+	"$toDate":           newConvertTo("$toDate", handlerparams.TypeCodeDate),
+	"$toDecimal":        newConvertTo("$toDecimal", handlerparams.TypeCodeDecimal),
+	"$toDouble":         newConvertTo("$toDouble", handlerparams.TypeCodeDouble),
+	"$toInt":            newConvertTo("$toInt", handlerparams.TypeCodeInt),
+	"$toLong":           newConvertTo("$toLong", handlerparams.TypeCodeLong),
+	"$toObjectId":       newConvertTo("$toObjectId", handlerparams.TypeCodeObjectID),
+	"$toString":         newConvertTo("$toString", handlerparams.TypeCodeString),
+	"$trim":             newTrim("$trim", true, true),
+	"$trunc":            newRound("$trunc", true),
+	"$type":             newType,
 	// please keep sorted alphabetically
 }
 
 // unsupportedOperators maps all unsupported yet operators.
 var unsupportedOperators = map[string]struct{}{
 	// sorted alphabetically
-	"$abs":              {},
-	"$acos":             {},
-	"$acosh":            {},
-	"$add":              {},
-	"$allElementsTrue":  {},
-	"$and":              {},
-	"$anyElementTrue":   {},
-	"$arrayElemAt":      {},
-	"$arrayToObject":    {},
-	"$asin":             {},
-	"$asinh":            {},
-	"$atan":             {},
-	"$atan2":            {},
-	"$atanh":            {},
-	"$avg":              {},
-	"$binarySize":       {},
-	"$bsonSize":         {},
-	"$ceil":             {},
-	"$cmp":              {},
-	"$concat":           {},
-	"$concatArrays":     {},
-	"$cond":             {},
-	"$convert":          {},
-	"$cos":              {},
-	"$cosh":             {},
-	"$covariancePop":    {},
-	"$covarianceSamp":   {},
-	"$dateAdd":          {},
-	"$dateDiff":         {},
-	"$dateFromParts":    {},
-	"$dateSubtract":     {},
-	"$dateTrunc":        {},
-	"$dateToParts":      {},
-	"$dateFromString":   {},
-	"$dateToString":     {},
-	"$dayOfMonth":       {},
-	"$dayOfWeek":        {},
-	"$dayOfYear":        {},
-	"$degreesToRadians": {},
-	"$denseRank":        {},
-	"$derivative":       {},
-	"$divide":           {},
-	"$documentNumber":   {},
-	"$eq":               {},
-	"$exp":              {},
-	"$expMovingAvg":     {},
-	"$filter":           {},
-	"$floor":            {},
-	"$function":         {},
-	"$getField":         {},
-	"$gt":               {},
-	"$gte":              {},
-	"$hour":             {},
-	"$ifNull":           {},
-	"$in":               {},
-	"$indexOfArray":     {},
-	"$indexOfBytes":     {},
-	"$indexOfCP":        {},
-	"$integral":         {},
-	"$isArray":          {},
-	"$isNumber":         {},
-	"$isoDayOfWeek":     {},
-	"$isoWeek":          {},
-	"$isoWeekYear":      {},
-	"$let":              {},
-	"$linearFill":       {},
-	"$literal":          {},
-	"$ln":               {},
-	"$locf":             {},
-	"$log":              {},
-	"$log10":            {},
-	"$lt":               {},
-	"$lte":              {},
-	"$ltrim":            {},
-	"$map":              {},
-	"$max":              {},
-	"$meta":             {},
-	"$min":              {},
-	"$minN":             {},
-	"$millisecond":      {},
-	"$minute":           {},
-	"$mod":              {},
-	"$month":            {},
-	"$multiply":         {},
-	"$ne":               {},
-	"$not":              {},
-	"$objectToArray":    {},
-	"$or":               {},
-	"$pow":              {},
-	"$radiansToDegrees": {},
-	"$rand":             {},
-	"$range":            {},
-	"$rank":             {},
-	"$reduce":           {},
-	"$regexFind":        {},
-	"$regexFindAll":     {},
-	"$regexMatch":       {},
-	"$replaceOne":       {},
-	"$replaceAll":       {},
-	"$reverseArray":     {},
-	"$round":            {},
-	"$rtrim":            {},
-	"$sampleRate":       {},
-	"$second":           {},
-	"$setDifference":    {},
-	"$setEquals":        {},
-	"$setField":         {},
-	"$setIntersection":  {},
-	"$setIsSubset":      {},
-	"$setUnion":         {},
-	"$shift":            {},
-	"$size":             {},
-	"$sin":              {},
-	"$sinh":             {},
-	"$slice":            {},
-	"$sortArray":        {},
-	"$split":            {},
-	"$sqrt":             {},
-	"$stdDevPop":        {},
-	"$stdDevSamp":       {},
-	"$strcasecmp":       {},
-	"$strLenBytes":      {},
-	"$strLenCP":         {},
-	"$substr":           {},
-	"$substrBytes":      {},
-	"$substrCP":         {},
-	"$subtract":         {},
-	"$switch":           {},
-	"$tan":              {},
-	"$tanh":             {},
-	"$toBool":           {},
-	"$toDate":           {},
-	"$toDecimal":        {},
-	"$toDouble":         {},
-	"$toInt":            {},
-	"$toLong":           {},
-	"$toObjectId":       {},
-	"$toString":         {},
-	"$toLower":          {},
-	"$toUpper":          {},
-	"$trim":             {},
-	"$trunc":            {},
-	"$tsIncrement":      {},
-	"$tsSecond":         {},
-	"$unsetField":       {},
-	"$week":             {},
-	"$year":             {},
-	"$zip":              {},
+	"$add":            {},
+	"$and":            {},
+	"$avg":            {},
+	"$binarySize":     {},
+	"$bsonSize":       {},
+	"$cmp":            {},
+	"$concat":         {},
+	"$cond":           {},
+	"$covariancePop":  {},
+	"$covarianceSamp": {},
+	"$dateAdd":        {},
+	"$dateDiff":       {},
+	"$dateFromParts":  {},
+	"$dateSubtract":   {},
+	"$dateTrunc":      {},
+	"$dateToParts":    {},
+	"$dayOfMonth":     {},
+	"$dayOfWeek":      {},
+	"$dayOfYear":      {},
+	"$denseRank":      {},
+	"$derivative":     {},
+	"$divide":         {},
+	"$documentNumber": {},
+	"$eq":             {},
+	"$expMovingAvg":   {},
+	"$filter":         {},
+	"$function":       {},
+	"$getField":       {},
+	"$gt":             {},
+	"$gte":            {},
+	"$hour":           {},
+	"$ifNull":         {},
+	"$in":             {},
+	"$indexOfBytes":   {},
+	"$indexOfCP":      {},
+	"$integral":       {},
+	"$isArray":        {},
+	"$isoDayOfWeek":   {},
+	"$isoWeek":        {},
+	"$isoWeekYear":    {},
+	"$let":            {},
+	"$linearFill":     {},
+	"$locf":           {},
+	"$lt":             {},
+	"$lte":            {},
+	"$map":            {},
+	"$max":            {},
+	"$meta":           {},
+	"$min":            {},
+	"$minN":           {},
+	"$millisecond":    {},
+	"$minute":         {},
+	"$mod":            {},
+	"$month":          {},
+	"$multiply":       {},
+	"$ne":             {},
+	"$not":            {},
+	"$or":             {},
+	"$rank":           {},
+	"$reduce":         {},
+	"$reverseArray":   {},
+	"$sampleRate":     {},
+	"$second":         {},
+	"$setEquals":      {},
+	"$setField":       {},
+	"$shift":          {},
+	"$size":           {},
+	"$sortArray":      {},
+	"$stdDevPop":      {},
+	"$stdDevSamp":     {},
+	"$strcasecmp":     {},
+	"$strLenBytes":    {},
+	"$strLenCP":       {},
+	"$substr":         {},
+	"$substrBytes":    {},
+	"$substrCP":       {},
+	"$subtract":       {},
+	"$switch":         {},
+	"$toLower":        {},
+	"$toUpper":        {},
+	"$tsIncrement":    {},
+	"$tsSecond":       {},
+	"$unsetField":     {},
+	"$week":           {},
+	"$year":           {},
+	"$zip":            {},
 	// please keep sorted alphabetically
 }
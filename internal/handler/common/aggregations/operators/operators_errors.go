@@ -34,6 +34,15 @@ const (
 
 	// ErrInvalidNestedExpression indicates that operator inside the target operator does not exist.
 	ErrInvalidNestedExpression
+
+	// ErrFailedToParse indicates that operator arguments cannot be parsed.
+	ErrFailedToParse
+
+	// ErrBadValue indicates that operator argument has an invalid value.
+	ErrBadValue
+
+	// ErrConversionFailure indicates that operator failed to convert a value to the requested type.
+	ErrConversionFailure
 )
 
 // newOperatorError returns new OperatorError.
@@ -0,0 +1,72 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// randSource is the source of pseudo-random numbers for `$rand` and `$sampleRate`.
+var (
+	randSourceM sync.Mutex
+	randSource  = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+)
+
+// SeedRand makes `$rand` and `$sampleRate` results deterministic.
+// It should be used only for testing.
+func SeedRand(seed uint64) {
+	randSourceM.Lock()
+	defer randSourceM.Unlock()
+
+	randSource = rand.New(rand.NewPCG(seed, seed))
+}
+
+// Rand returns a pseudo-random number in [0, 1).
+func Rand() float64 {
+	randSourceM.Lock()
+	defer randSourceM.Unlock()
+
+	return randSource.Float64()
+}
+
+// randOp represents `$rand` operator.
+type randOp struct{}
+
+// newRand returns `$rand` operator.
+func newRand(args ...any) (Operator, error) {
+	if doc, _ := namedArgs(args); doc == nil || doc.Len() != 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRandArguments,
+			"$rand does not currently accept arguments",
+			"$rand",
+		)
+	}
+
+	return new(randOp), nil
+}
+
+// Process implements Operator interface.
+func (r *randOp) Process(*types.Document) (any, error) {
+	return Rand(), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*randOp)(nil)
+)
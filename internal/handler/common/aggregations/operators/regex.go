@@ -0,0 +1,317 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// regexMode represents the result returned by regex operator.
+type regexMode int
+
+const (
+	// regexMatch returns true if there is a match.
+	regexMatch regexMode = iota
+
+	// regexFind returns the first match.
+	regexFind
+
+	// regexFindAll returns all matches.
+	regexFindAll
+)
+
+// regexOp represents `$regexMatch`, `$regexFind` and `$regexFindAll` operators.
+type regexOp struct {
+	input   any
+	regex   any
+	options any
+	name    string
+	mode    regexMode
+}
+
+// newRegex returns a function that creates regex operator with the given name and mode.
+func newRegex(name string, mode regexMode) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		doc, found := namedArgs(args)
+		if doc == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexNotObject,
+				fmt.Sprintf("%s expects an object of named arguments but found: %s", name, found),
+				name,
+			)
+		}
+
+		op := &regexOp{
+			name: name,
+			mode: mode,
+		}
+
+		var hasInput, hasRegex bool
+
+		iter := doc.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			switch k {
+			case "input":
+				op.input = v
+				hasInput = true
+			case "regex":
+				op.regex = v
+				hasRegex = true
+			case "options":
+				op.options = v
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrRegexUnknownArgument,
+					fmt.Sprintf("%s found an unknown argument: %s", name, k),
+					name,
+				)
+			}
+		}
+
+		if !hasInput {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexMissingInput,
+				fmt.Sprintf("%s requires 'input' parameter", name),
+				name,
+			)
+		}
+
+		if !hasRegex {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexMissingRegex,
+				fmt.Sprintf("%s requires 'regex' parameter", name),
+				name,
+			)
+		}
+
+		// validate constant regex and options early; expressions are evaluated for each document
+		if isConstant(op.regex) && isConstant(op.options) {
+			if _, err := compileRegex(name, op.regex, op.options); err != nil {
+				return nil, err
+			}
+		}
+
+		return op, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// If the input or the regex is null or missing, there is no match.
+func (r *regexOp) Process(doc *types.Document) (any, error) {
+	re, err := r.compile(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := evaluate(doc, r.input)
+	if err != nil {
+		return nil, err
+	}
+
+	var str string
+
+	switch v := input.(type) {
+	case types.NullType:
+		re = nil
+	case string:
+		str = v
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexInputNotString,
+			fmt.Sprintf("%s needs 'input' to be of type string", r.name),
+			r.name,
+		)
+	}
+
+	switch r.mode {
+	case regexMatch:
+		return re != nil && re.MatchString(str), nil
+
+	case regexFind:
+		if re == nil {
+			return types.Null, nil
+		}
+
+		loc := re.FindStringSubmatchIndex(str)
+		if loc == nil {
+			return types.Null, nil
+		}
+
+		return regexMatchDocument(str, loc), nil
+
+	case regexFindAll:
+		res := types.MakeArray(0)
+
+		if re == nil {
+			return res, nil
+		}
+
+		for _, loc := range re.FindAllStringSubmatchIndex(str, -1) {
+			res.Append(regexMatchDocument(str, loc))
+		}
+
+		return res, nil
+
+	default:
+		panic(fmt.Sprintf("unknown regex mode %d", r.mode))
+	}
+}
+
+// compile evaluates regex and options arguments and compiles the regular expression.
+// It returns nil if regex is null or missing.
+func (r *regexOp) compile(doc *types.Document) (*regexp.Regexp, error) {
+	var options any
+
+	if r.options != nil {
+		var err error
+		if options, err = evaluate(doc, r.options); err != nil {
+			return nil, err
+		}
+	}
+
+	regex, err := evaluate(doc, r.regex)
+	if err != nil {
+		return nil, err
+	}
+
+	return compileRegex(r.name, regex, options)
+}
+
+// compileRegex compiles the regular expression for evaluated regex and options arguments.
+// Options are nil if they are not set.
+// It returns nil if regex is null or missing.
+func compileRegex(name string, regexArg, optionsArg any) (*regexp.Regexp, error) {
+	var options string
+
+	if optionsArg != nil {
+		switch v := optionsArg.(type) {
+		case types.NullType:
+		case string:
+			options = v
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexOptionsNotString,
+				fmt.Sprintf("%s needs 'options' to be of type string", name),
+				name,
+			)
+		}
+	}
+
+	var regex types.Regex
+
+	switch v := regexArg.(type) {
+	case types.NullType:
+		return nil, nil
+	case string:
+		regex = types.Regex{Pattern: v, Options: options}
+	case types.Regex:
+		if v.Options != "" && options != "" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrRegexOptionsConflict,
+				fmt.Sprintf("%s found regex option(s) specified in both 'regex' and 'option' fields", name),
+				name,
+			)
+		}
+
+		regex = v
+		if options != "" {
+			regex.Options = options
+		}
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexNotStringOrRegex,
+			fmt.Sprintf("%s needs 'regex' to be of type string or regex", name),
+			name,
+		)
+	}
+
+	for _, o := range regex.Options {
+		if !strings.ContainsRune("imsx", o) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadRegexOption,
+				fmt.Sprintf("%s invalid flag in regex options: %c", name, o),
+				name,
+			)
+		}
+	}
+
+	re, err := regex.Compile()
+	switch {
+	case err == nil:
+		return re, nil
+	case errors.Is(err, types.ErrOptionNotImplemented):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			fmt.Sprintf("%s option 'x' is not implemented yet", name),
+			name,
+		)
+	default:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRegexInvalid,
+			fmt.Sprintf("Invalid Regular Expression: %s", err),
+			name,
+		)
+	}
+}
+
+// regexMatchDocument returns the document describing the match at the given location
+// as returned by regexp.Regexp.FindStringSubmatchIndex.
+//
+// The index of the match is measured in code points, not bytes.
+func regexMatchDocument(str string, loc []int) *types.Document {
+	captures := types.MakeArray(len(loc)/2 - 1)
+
+	for i := 2; i < len(loc); i += 2 {
+		if loc[i] < 0 {
+			captures.Append(types.Null)
+			continue
+		}
+
+		captures.Append(str[loc[i]:loc[i+1]])
+	}
+
+	return must.NotFail(types.NewDocument(
+		"match", str[loc[0]:loc[1]],
+		"idx", int32(utf8.RuneCountInString(str[:loc[0]])),
+		"captures", captures,
+	))
+}
+
+// check interfaces
+var (
+	_ Operator = (*regexOp)(nil)
+)
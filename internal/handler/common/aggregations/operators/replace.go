@@ -0,0 +1,161 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// replace represents `$replaceOne` and `$replaceAll` operators.
+type replace struct {
+	input       any
+	find        any
+	replacement any
+	name        string
+	all         bool
+}
+
+// newReplace returns a function that creates `$replaceOne` operator,
+// or `$replaceAll` operator if all is true.
+func newReplace(name string, all bool) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		doc, found := namedArgs(args)
+		if doc == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrReplaceNotObject,
+				fmt.Sprintf("%s requires an object as an argument, found: %s", name, found),
+				name,
+			)
+		}
+
+		op := &replace{
+			name: name,
+			all:  all,
+		}
+
+		var hasInput, hasFind, hasReplacement bool
+
+		iter := doc.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			switch k {
+			case "input":
+				op.input = v
+				hasInput = true
+			case "find":
+				op.find = v
+				hasFind = true
+			case "replacement":
+				op.replacement = v
+				hasReplacement = true
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrReplaceUnknownArgument,
+					fmt.Sprintf("%s found an unknown argument: %s", name, k),
+					name,
+				)
+			}
+		}
+
+		for _, p := range []struct {
+			code    handlererrors.ErrorCode
+			name    string
+			present bool
+		}{
+			{handlererrors.ErrReplaceMissingInput, "input", hasInput},
+			{handlererrors.ErrReplaceMissingFind, "find", hasFind},
+			{handlererrors.ErrReplaceMissingReplacement, "replacement", hasReplacement},
+		} {
+			if !p.present {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					p.code,
+					fmt.Sprintf("%s requires '%s' to be specified", name, p.name),
+					name,
+				)
+			}
+		}
+
+		return op, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// It returns null if any argument is null or missing.
+func (r *replace) Process(doc *types.Document) (any, error) {
+	var values [3]string
+	var null bool
+
+	for i, p := range []struct {
+		arg  any
+		name string
+		code handlererrors.ErrorCode
+	}{
+		{r.input, "input", handlererrors.ErrReplaceInputNotString},
+		{r.find, "find", handlererrors.ErrReplaceFindNotString},
+		{r.replacement, "replacement", handlererrors.ErrReplaceReplacementNotString},
+	} {
+		v, err := evaluate(doc, p.arg)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := v.(type) {
+		case types.NullType:
+			null = true
+		case string:
+			values[i] = v
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				p.code,
+				fmt.Sprintf("%s requires that '%s' be a string, found: %s", r.name, p.name, types.FormatAnyValue(v)),
+				r.name,
+			)
+		}
+	}
+
+	if null {
+		return types.Null, nil
+	}
+
+	n := 1
+	if r.all {
+		n = -1
+	}
+
+	return strings.Replace(values[0], values[1], values[2], n), nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*replace)(nil)
+)
@@ -0,0 +1,284 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// roundOp represents `$round` and `$trunc` operators.
+type roundOp struct {
+	number any
+	place  any
+	name   string
+	trunc  bool
+}
+
+// newRound returns a function that creates `$round` or `$trunc` operator.
+func newRound(name string, trunc bool) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				name,
+				fmt.Sprintf("Expression %s takes at least 1 arguments, and at most 2. %d were passed in.", name, len(args)),
+			)
+		}
+
+		op := &roundOp{
+			number: args[0],
+			place:  int32(0),
+			name:   name,
+			trunc:  trunc,
+		}
+
+		if len(args) == 2 {
+			op.place = args[1]
+		}
+
+		return op, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// Doubles are rounded as decimal numbers with 34 significant digits,
+// so 2.675 is rounded to 2.67 as it is stored, not to 2.68 as it is written.
+// Decimals are quantized to the place, so 1.5 rounded to 2 places is 1.50.
+// `$round` rounds half to even.
+// If the number or the place is null or missing, null is returned.
+func (r *roundOp) Process(doc *types.Document) (any, error) {
+	number, err := evaluate(doc, r.number)
+	if err != nil {
+		return nil, err
+	}
+
+	place, err := evaluate(doc, r.place)
+	if err != nil {
+		return nil, err
+	}
+
+	if number == types.Null || place == types.Null {
+		return types.Null, nil
+	}
+
+	if !isNumber(number) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundNotNumber,
+			fmt.Sprintf("%s only supports numeric types, not %s", r.name, handlerparams.AliasFromType(number)),
+			r.name,
+		)
+	}
+
+	p := math.NaN()
+	if isNumber(place) {
+		p = mathToDouble(place)
+	}
+
+	if p != math.Trunc(p) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundPlaceNotIntegral,
+			fmt.Sprintf("precision argument to  %s must be a integral value", r.name),
+			r.name,
+		)
+	}
+
+	if p < -20 || p > 100 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrRoundPlaceOutOfRange,
+			fmt.Sprintf("cannot apply %s with precision value %s value must be in [-20, 100]", r.name, formatMathValue(p)),
+			r.name,
+		)
+	}
+
+	switch number := number.(type) {
+	case float64:
+		return roundDouble(number, int(p), r.trunc), nil
+	case types.Decimal128:
+		mode := decimalRoundHalfEven
+		if r.trunc {
+			mode = decimalRoundDown
+		}
+
+		return roundDecimal(number, -int(p), mode), nil
+	case int32:
+		if p >= 0 {
+			return number, nil
+		}
+
+		res := roundInteger(int64(number), int(-p), r.trunc)
+		if res.IsInt64() && res.Int64() >= math.MinInt32 && res.Int64() <= math.MaxInt32 {
+			return int32(res.Int64()), nil
+		}
+
+		return mathIntegerOrDouble(res), nil
+	case int64:
+		if p >= 0 {
+			return number, nil
+		}
+
+		return mathIntegerOrDouble(roundInteger(number, int(-p), r.trunc)), nil
+	default:
+		panic(fmt.Sprintf("unexpected type %T", number))
+	}
+}
+
+// roundDouble rounds or truncates a double to the given number of decimal places.
+func roundDouble(v float64, place int, trunc bool) float64 {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+
+	// d.ddd…e±XX with 34 significant digits, like Decimal128
+	s := strconv.FormatFloat(math.Abs(v), 'e', 33, 64)
+	mantissa, exp, _ := strings.Cut(s, "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, _ := strconv.Atoi(exp)
+
+	// the number of digits to keep
+	keep := e + 1 + place
+	if keep >= len(digits) {
+		return v
+	}
+
+	var kept big.Int
+
+	if keep > 0 {
+		kept.SetString(digits[:keep], 10)
+	}
+
+	if !trunc {
+		rest := digits[max(keep, 0):]
+		if keep < 0 {
+			rest = ""
+		}
+
+		half := "5" + strings.Repeat("0", max(len(rest)-1, 0))
+
+		switch {
+		case rest == "":
+		case rest > half:
+			kept.Add(&kept, big.NewInt(1))
+		case rest == half && kept.Bit(0) == 1:
+			kept.Add(&kept, big.NewInt(1))
+		}
+	}
+
+	res, _ := strconv.ParseFloat(fmt.Sprintf("%se%d", kept.String(), -place), 64)
+
+	return math.Copysign(res, v)
+}
+
+// decimalRounding represents the rounding direction of roundDecimal.
+type decimalRounding int
+
+const (
+	decimalRoundHalfEven decimalRounding = iota
+	decimalRoundDown                     // toward zero
+	decimalRoundCeiling                  // toward positive infinity
+	decimalRoundFloor                    // toward negative infinity
+)
+
+// roundDecimal rounds a decimal to the given exponent like IEEE 754 quantize operation does,
+// so the result has exactly that exponent: 1.25 rounded to -1 is 1.2, and 1.5 rounded to -2 is 1.50.
+// NaN and infinities are returned as is, as well as values that would not fit into 34 digits.
+func roundDecimal(v types.Decimal128, exponent int, mode decimalRounding) types.Decimal128 {
+	if v.IsNaN() || v.IsInf(0) {
+		return v
+	}
+
+	neg, c, e := v.Parts()
+
+	if e >= exponent {
+		c.Mul(c, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(e-exponent)), nil))
+
+		if len(c.String()) > types.Decimal128MaxDigits {
+			return v
+		}
+
+		return types.NewDecimal128(neg, c, exponent)
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent-e)), nil)
+	q, m := new(big.Int).QuoRem(c, unit, new(big.Int))
+
+	var up bool
+
+	switch mode {
+	case decimalRoundHalfEven:
+		// compare the doubled remainder with the unit
+		cmp := m.Lsh(m, 1).Cmp(unit)
+		up = cmp > 0 || (cmp == 0 && q.Bit(0) == 1)
+	case decimalRoundDown:
+	case decimalRoundCeiling:
+		up = m.Sign() != 0 && !neg
+	case decimalRoundFloor:
+		up = m.Sign() != 0 && neg
+	default:
+		panic(fmt.Sprintf("unexpected rounding mode %d", mode))
+	}
+
+	if up {
+		q.Add(q, big.NewInt(1))
+	}
+
+	return types.NewDecimal128(neg, q, exponent)
+}
+
+// roundInteger rounds or truncates an integer to the given number of tens.
+func roundInteger(v int64, tens int, trunc bool) *big.Int {
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tens)), nil)
+
+	q, m := new(big.Int).QuoRem(big.NewInt(v), unit, new(big.Int))
+
+	if !trunc {
+		// compare the doubled remainder with the unit to round half to even
+		m.Abs(m).Lsh(m, 1)
+
+		if c := m.Cmp(unit); c > 0 || (c == 0 && q.Bit(0) == 1) {
+			if v < 0 {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+
+	return q.Mul(q, unit)
+}
+
+// mathIntegerOrDouble returns long if the value fits into long, double otherwise.
+func mathIntegerOrDouble(v *big.Int) any {
+	if v.IsInt64() {
+		return v.Int64()
+	}
+
+	f, _ := new(big.Float).SetInt(v).Float64()
+
+	return f
+}
+
+// check interfaces
+var (
+	_ Operator = (*roundOp)(nil)
+)
@@ -0,0 +1,341 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// setMode represents the set operation performed by set operator.
+type setMode int
+
+const (
+	// setUnion returns elements of any array.
+	setUnion setMode = iota
+
+	// setIntersection returns elements of all arrays.
+	setIntersection
+
+	// setDifference returns elements of the first array that are not in the second array.
+	setDifference
+
+	// setIsSubset returns true if all elements of the first array are in the second array.
+	setIsSubset
+)
+
+// setOp represents `$setUnion`, `$setIntersection`, `$setDifference` and `$setIsSubset` operators.
+type setOp struct {
+	args []any
+	name string
+	mode setMode
+}
+
+// newSet returns a function that creates set operator with the given name and mode.
+func newSet(name string, mode setMode) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if (mode == setDifference || mode == setIsSubset) && len(args) != 2 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				name,
+				fmt.Sprintf("Expression %s takes exactly 2 arguments. %d were passed in.", name, len(args)),
+			)
+		}
+
+		return &setOp{
+			args: args,
+			name: name,
+			mode: mode,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// Arrays are treated as sets: duplicate elements are removed,
+// and numbers of different types with the same value are considered equal.
+// Apart from `$setIsSubset`, if any argument is null or missing, null is returned.
+func (s *setOp) Process(doc *types.Document) (any, error) {
+	values := make([]any, len(s.args))
+
+	for i, arg := range s.args {
+		v, err := evaluate(doc, arg)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = v
+	}
+
+	switch s.mode {
+	case setUnion, setIntersection:
+		sets := make([][]any, len(values))
+
+		for i, v := range values {
+			switch v := v.(type) {
+			case types.NullType:
+				return types.Null, nil
+			case *types.Array:
+				sets[i] = setElements(v)
+			default:
+				code := handlererrors.ErrSetUnionNotArray
+				if s.mode == setIntersection {
+					code = handlererrors.ErrSetIntersectionNotArray
+				}
+
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					code,
+					fmt.Sprintf(
+						"All operands of %s must be arrays. One argument is of type: %s",
+						s.name, handlerparams.AliasFromType(v),
+					),
+					s.name,
+				)
+			}
+		}
+
+		if s.mode == setUnion {
+			return setArray(setElements(types.MakeArray(0), sets...)), nil
+		}
+
+		if len(sets) == 0 {
+			return types.MakeArray(0), nil
+		}
+
+		res := sets[0]
+		for _, set := range sets[1:] {
+			res = slices.DeleteFunc(res, func(v any) bool { return !setContains(set, v) })
+		}
+
+		return setArray(res), nil
+
+	case setDifference:
+		if values[0] == types.Null || values[1] == types.Null {
+			return types.Null, nil
+		}
+
+		first, second, err := s.twoArrays(values,
+			handlererrors.ErrSetDifferenceFirstNotArray, handlererrors.ErrSetDifferenceSecondNotArray,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		exclude := setElements(second)
+		res := types.MakeArray(first.Len())
+
+		var added []any
+
+		iter := first.Iterator()
+		defer iter.Close()
+
+		for {
+			_, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			// keep the order of the first array
+			if setContains(exclude, v) || slices.ContainsFunc(added, func(a any) bool {
+				return types.CompareForAggregation(a, v) == types.Equal
+			}) {
+				continue
+			}
+
+			added = append(added, v)
+			res.Append(v)
+		}
+
+		return res, nil
+
+	case setIsSubset:
+		first, second, err := s.twoArrays(values,
+			handlererrors.ErrSetIsSubsetFirstNotArray, handlererrors.ErrSetIsSubsetSecondNotArray,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		set := setElements(second)
+
+		for _, v := range setElements(first) {
+			if !setContains(set, v) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+
+	default:
+		panic(fmt.Sprintf("unexpected set mode %d", s.mode))
+	}
+}
+
+// twoArrays checks that both values are arrays and returns them.
+// Given error codes are used if the first or the second value is not an array.
+func (s *setOp) twoArrays(values []any, firstCode, secondCode handlererrors.ErrorCode) (*types.Array, *types.Array, error) {
+	first, ok := values[0].(*types.Array)
+	if !ok {
+		return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+			firstCode,
+			fmt.Sprintf(
+				"both operands of %s must be arrays. First argument is of type: %s",
+				s.name, handlerparams.AliasFromType(values[0]),
+			),
+			s.name,
+		)
+	}
+
+	second, ok := values[1].(*types.Array)
+	if !ok {
+		return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+			secondCode,
+			fmt.Sprintf(
+				"both operands of %s must be arrays. Second argument is of type: %s",
+				s.name, handlerparams.AliasFromType(values[1]),
+			),
+			s.name,
+		)
+	}
+
+	return first, second, nil
+}
+
+// setElements returns unique elements of the given array and additional sets
+// sorted in BSON order.
+func setElements(arr *types.Array, sets ...[]any) []any {
+	res := make([]any, 0, arr.Len())
+
+	for i := range arr.Len() {
+		res = append(res, must.NotFail(arr.Get(i)))
+	}
+
+	for _, set := range sets {
+		res = append(res, set...)
+	}
+
+	slices.SortStableFunc(res, func(a, b any) int {
+		return int(types.CompareForAggregation(a, b))
+	})
+
+	return slices.CompactFunc(res, func(a, b any) bool {
+		return types.CompareForAggregation(a, b) == types.Equal
+	})
+}
+
+// setContains returns true if the set contains the given value.
+func setContains(set []any, v any) bool {
+	return slices.ContainsFunc(set, func(e any) bool {
+		return types.CompareForAggregation(e, v) == types.Equal
+	})
+}
+
+// setArray returns array with the given set elements.
+func setArray(set []any) *types.Array {
+	res := types.MakeArray(len(set))
+	res.Append(set...)
+
+	return res
+}
+
+// elementsTrue represents `$anyElementTrue` and `$allElementsTrue` operators.
+type elementsTrue struct {
+	arg  any
+	name string
+	all  bool
+}
+
+// newElementsTrue returns a function that creates `$anyElementTrue` or `$allElementsTrue` operator.
+func newElementsTrue(name string, all bool) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		if len(args) != 1 {
+			return nil, newOperatorError(
+				ErrArgsInvalidLen,
+				name,
+				fmt.Sprintf("Expression %s takes exactly 1 arguments. %d were passed in.", name, len(args)),
+			)
+		}
+
+		return &elementsTrue{
+			arg:  args[0],
+			name: name,
+			all:  all,
+		}, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// Null, false and zero elements are false, all other elements are true.
+func (e *elementsTrue) Process(doc *types.Document) (any, error) {
+	v, err := evaluate(doc, e.arg)
+	if err != nil {
+		return nil, err
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		code := handlererrors.ErrAnyElementTrueNotArray
+		if e.all {
+			code = handlererrors.ErrAllElementsTrueNotArray
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			code,
+			fmt.Sprintf("%s's argument must be an array, but is %s", e.name, handlerparams.AliasFromType(v)),
+			e.name,
+		)
+	}
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, elem, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		isTrue := elem != types.Null && convertToBool(elem)
+
+		if isTrue != e.all {
+			return isTrue, nil
+		}
+	}
+
+	return e.all, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*setOp)(nil)
+	_ Operator = (*elementsTrue)(nil)
+)
@@ -64,7 +64,7 @@ func newSum(args ...any) (Operator, error) {
 			}
 
 			operator.expressions = append(operator.expressions, ex)
-		case int32, int64:
+		case int32, int64, types.Decimal128:
 			operator.numbers = append(operator.numbers, arg)
 		}
 	}
@@ -74,7 +74,7 @@ func newSum(args ...any) (Operator, error) {
 
 // Process implements Operator interface.
 // It evaluates expressions if any to fetch a value, creates new operator and processes them if any
-// and sums all int32, int64 and float64 numbers ignoring other types.
+// and sums all int32, int64, float64 and Decimal128 numbers ignoring other types.
 func (s *sum) Process(doc *types.Document) (any, error) {
 	var numbers []any
 
@@ -135,7 +135,7 @@ func (s *sum) Process(doc *types.Document) (any, error) {
 
 	for _, number := range s.numbers {
 		switch number := number.(type) {
-		case float64, int32, int64:
+		case float64, int32, int64, types.Decimal128:
 			numbers = append(numbers, number)
 		}
 	}
@@ -0,0 +1,179 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// trimWhitespace contains characters removed by `$trim` operators when `chars` is not specified.
+var trimWhitespace = []rune{
+	'\u0000', '\u0020', '\u0009', '\u000A', '\u000B', '\u000C', '\u000D', '\u00A0', '\u1680',
+	'\u2000', '\u2001', '\u2002', '\u2003', '\u2004', '\u2005', '\u2006', '\u2007', '\u2008', '\u2009', '\u200A',
+}
+
+// trim represents `$trim`, `$ltrim` and `$rtrim` operators.
+type trim struct {
+	input any
+	chars any
+	name  string
+	left  bool
+	right bool
+}
+
+// newTrim returns a function that creates `$trim`-like operator with the given name.
+// It removes characters from the start of the string if left is true,
+// and from the end if right is true.
+func newTrim(name string, left, right bool) newOperatorFunc {
+	return func(args ...any) (Operator, error) {
+		doc, found := namedArgs(args)
+		if doc == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTrimNotObject,
+				fmt.Sprintf("%s only supports an object as its argument, found: %s", name, found),
+				name,
+			)
+		}
+
+		op := &trim{
+			name:  name,
+			left:  left,
+			right: right,
+		}
+
+		var hasInput bool
+
+		iter := doc.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			switch k {
+			case "input":
+				op.input = v
+				hasInput = true
+			case "chars":
+				op.chars = v
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTrimUnknownArgument,
+					fmt.Sprintf("%s found an unknown argument: %s", name, k),
+					name,
+				)
+			}
+		}
+
+		if !hasInput {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTrimMissingInput,
+				fmt.Sprintf("%s requires an 'input' field", name),
+				name,
+			)
+		}
+
+		return op, nil
+	}
+}
+
+// Process implements Operator interface.
+//
+// It returns null if the input or chars is null or missing.
+// Characters are trimmed by code points, not by bytes.
+func (t *trim) Process(doc *types.Document) (any, error) {
+	input, err := evaluate(doc, t.input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input == types.Null {
+		return types.Null, nil
+	}
+
+	str, ok := input.(string)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTrimInputNotString,
+			fmt.Sprintf(
+				"%s requires its input to be a string, got %s (of type %s) instead.",
+				t.name, types.FormatAnyValue(input), handlerparams.AliasFromType(input),
+			),
+			t.name,
+		)
+	}
+
+	set := trimWhitespace
+
+	if t.chars != nil {
+		var chars any
+
+		if chars, err = evaluate(doc, t.chars); err != nil {
+			return nil, err
+		}
+
+		if chars == types.Null {
+			return types.Null, nil
+		}
+
+		s, ok := chars.(string)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTrimCharsNotString,
+				fmt.Sprintf(
+					"%s requires 'chars' to be a string, got %s (of type %s) instead.",
+					t.name, types.FormatAnyValue(chars), handlerparams.AliasFromType(chars),
+				),
+				t.name,
+			)
+		}
+
+		set = []rune(s)
+	}
+
+	trimmed := func(r rune) bool {
+		return slices.Contains(set, r)
+	}
+
+	if t.left {
+		str = strings.TrimLeftFunc(str, trimmed)
+	}
+
+	if t.right {
+		str = strings.TrimRightFunc(str, trimmed)
+	}
+
+	return str, nil
+}
+
+// check interfaces
+var (
+	_ Operator = (*trim)(nil)
+)
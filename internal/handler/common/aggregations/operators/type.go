@@ -16,15 +16,10 @@
 package operators
 
 import (
-	"errors"
 	"fmt"
-	"strings"
-	"time"
 
-	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
-	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
 // typeOp represents `$type` operator.
@@ -48,81 +43,55 @@ func newType(args ...any) (Operator, error) {
 }
 
 // Process implements Operator interface.
+//
+// For missing fields and missing values of nested operators, "missing" is returned.
 func (t *typeOp) Process(doc *types.Document) (any, error) {
-	typeParam := t.param
-
-	var paramEvaluated bool
-
-	var res any
-
-	for !paramEvaluated {
-		paramEvaluated = true
-
-		switch param := typeParam.(type) {
-		case *types.Document:
-			if !IsOperator(param) {
-				res = param
-				break
-			}
-
-			operator, err := NewOperator(param)
-			if err != nil {
-				var opErr OperatorError
-				if !errors.As(err, &opErr) {
-					return nil, lazyerrors.Error(err)
-				}
-
-				if opErr.Code() == ErrInvalidExpression {
-					opErr.code = ErrInvalidNestedExpression
-				}
-
-				return nil, opErr
-			}
-
-			if typeParam, err = operator.Process(doc); err != nil {
-				var opErr OperatorError
-				if !errors.As(err, &opErr) {
-					return nil, lazyerrors.Error(err)
-				}
-
-				return nil, err
-			}
-
-			// the result of nested operator needs to be evaluated
-			paramEvaluated = false
-
-		case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time,
-			types.NullType, types.Regex, int32, types.Timestamp, int64:
-			res = param
+	v, err := evaluateOrMissing(doc, t.param)
+	if err != nil {
+		return nil, err
+	}
 
-		case string:
-			if strings.HasPrefix(param, "$") {
-				expression, err := aggregations.NewExpression(param, nil)
-				if err != nil {
-					return nil, err
-				}
+	if v == nil {
+		return "missing", nil
+	}
 
-				value, err := expression.Evaluate(doc)
-				if err != nil {
-					return "missing", nil
-				}
+	return handlerparams.AliasFromType(v), nil
+}
 
-				res = value
+// isNumberOp represents `$isNumber` operator.
+type isNumberOp struct {
+	param any
+}
 
-				continue
-			}
+// newIsNumber returns `$isNumber` operator.
+func newIsNumber(args ...any) (Operator, error) {
+	if len(args) != 1 {
+		return nil, newOperatorError(
+			ErrArgsInvalidLen,
+			"$isNumber",
+			fmt.Sprintf("Expression $isNumber takes exactly 1 arguments. %d were passed in.", len(args)),
+		)
+	}
 
-			res = param
+	return &isNumberOp{
+		param: args[0],
+	}, nil
+}
 
-		default:
-			panic(fmt.Sprint("wrong type of value: ", typeParam))
-		}
+// Process implements Operator interface.
+//
+// For null and missing values, false is returned.
+func (i *isNumberOp) Process(doc *types.Document) (any, error) {
+	v, err := evaluate(doc, i.param)
+	if err != nil {
+		return nil, err
 	}
 
-	return handlerparams.AliasFromType(res), nil
+	return isNumber(v), nil
 }
 
 // check interfaces
 var (
 	_ Operator = (*typeOp)(nil)
+	_ Operator = (*isNumberOp)(nil)
 )
@@ -15,6 +15,8 @@
 package aggregations
 
 import (
+	"strings"
+
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -72,3 +74,83 @@ func GetPushdownQuery(stagesDocs []any) (match *types.Document, sort *types.Docu
 
 	return
 }
+
+// GroupCountPushdown represents `$group` stage that only counts documents grouped by a top-level field,
+// optionally preceded by `$match` stage.
+type GroupCountPushdown struct {
+	Filter  *types.Document // nil if $match stage is not present
+	GroupBy string          // top-level field name, empty for null group key
+	Field   string          // output field for the count
+}
+
+// GetGroupCountPushdown returns GroupCountPushdown for pipelines
+// `[{$match: <filter>}, {$group: {_id: <key>, <field>: <accumulator>}}]` and `[{$group: ...}]`,
+// where key is null or a path to a top-level field,
+// and accumulator is either `{$sum: 1}` or `{$count: {}}`.
+// For other pipelines, nil is returned.
+func GetGroupCountPushdown(stagesDocs []any) *GroupCountPushdown {
+	var res GroupCountPushdown
+
+	switch len(stagesDocs) {
+	case 1:
+	case 2:
+		stage, _ := stagesDocs[0].(*types.Document)
+		if stage.Len() != 1 {
+			return nil
+		}
+
+		filter, _ := stage.Get("$match")
+		if res.Filter, _ = filter.(*types.Document); res.Filter == nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	stage, _ := stagesDocs[len(stagesDocs)-1].(*types.Document)
+	if stage.Len() != 1 {
+		return nil
+	}
+
+	v, _ := stage.Get("$group")
+
+	group, _ := v.(*types.Document)
+	if group.Len() != 2 || group.Keys()[0] != "_id" {
+		return nil
+	}
+
+	switch key := must.NotFail(group.Get("_id")).(type) {
+	case types.NullType:
+	case string:
+		res.GroupBy = strings.TrimPrefix(key, "$")
+
+		if res.GroupBy == key || res.GroupBy == "" ||
+			strings.HasPrefix(res.GroupBy, "$") || strings.ContainsRune(res.GroupBy, '.') {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	res.Field = group.Keys()[1]
+
+	accumulator, _ := must.NotFail(group.Get(res.Field)).(*types.Document)
+	if accumulator.Len() != 1 {
+		return nil
+	}
+
+	switch op := accumulator.Keys()[0]; op {
+	case "$sum":
+		if n := must.NotFail(accumulator.Get(op)); n != int32(1) {
+			return nil
+		}
+	case "$count":
+		if args, _ := must.NotFail(accumulator.Get(op)).(*types.Document); args == nil || args.Len() != 0 {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return &res
+}
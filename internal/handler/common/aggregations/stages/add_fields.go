@@ -57,6 +57,10 @@ func newAddFields(stage *types.Document) (aggregations.Stage, error) {
 		return nil, err
 	}
 
+	if err := common.ValidateAddFields(fieldsDoc); err != nil {
+		return nil, err
+	}
+
 	return &addFields{
 		newField: fieldsDoc,
 	}, nil
@@ -0,0 +1,107 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// currentOp represents $currentOp stage.
+type currentOp struct {
+	allUsers bool
+}
+
+// newCurrentOp creates a new $currentOp stage.
+func newCurrentOp(stage *types.Document) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$currentOp"))
+
+	fields, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$currentOp options must be specified in an object, but found: %s", handlerparams.AliasFromType(v)),
+			"$currentOp (stage)",
+		)
+	}
+
+	var c currentOp
+
+	for _, k := range fields.Keys() {
+		v := must.NotFail(fields.Get(k))
+
+		switch k {
+		case "allUsers", "idleConnections", "idleCursors", "idleSessions", "localOps", "backtrace", "truncateOps":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					fmt.Sprintf(
+						"The '%s' parameter of the $currentOp stage must be a boolean value, but found: %s",
+						k, handlerparams.AliasFromType(v),
+					),
+					"$currentOp (stage)",
+				)
+			}
+
+			// idle connections, cursors and sessions are not reported,
+			// there is only one node, and backtraces and long commands are never included
+			if k == "allUsers" {
+				c.allUsers = b
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("Unrecognized option '%s' in $currentOp stage.", k),
+				"$currentOp (stage)",
+			)
+		}
+	}
+
+	return &c, nil
+}
+
+// Process implements Stage interface.
+//
+// Documents describing operations in progress are provided by the handler,
+// so they are returned as is.
+func (c *currentOp) Process(_ context.Context, iter types.DocumentsIterator, _ *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	return iter, nil
+}
+
+// CurrentOpAllUsers returns true if the given stages contain $currentOp stage
+// that reports operations of all users, not only the current one.
+func CurrentOpAllUsers(stages []aggregations.Stage) bool {
+	for _, stage := range stages {
+		if st, ok := stage.(*currentOp); ok && st.allUsers {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*currentOp)(nil)
+)
@@ -0,0 +1,236 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// facetNotAllowedStages contains stages that can't be used in $facet sub-pipelines.
+var facetNotAllowedStages = map[string]struct{}{
+	"$collStats": {},
+	"$currentOp": {},
+	"$facet":     {},
+}
+
+// facet represents $facet stage.
+type facet struct {
+	names     []string
+	pipelines [][]aggregations.Stage
+}
+
+// newFacet creates a new $facet stage.
+func newFacet(stage *types.Document) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$facet"))
+
+	fields, ok := v.(*types.Document)
+	if !ok || fields.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageFacetInvalidSpec,
+			fmt.Sprintf("the $facet specification must be a non-empty object, but found: %s", types.FormatAnyValue(v)),
+			"$facet (stage)",
+		)
+	}
+
+	if k, ok := fields.FindDuplicateKey(); ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStageFacetInvalidSpec,
+			fmt.Sprintf("the $facet specification contains duplicate field name: %s", k),
+			"$facet (stage)",
+		)
+	}
+
+	f := &facet{
+		names:     make([]string, 0, fields.Len()),
+		pipelines: make([][]aggregations.Stage, 0, fields.Len()),
+	}
+
+	for _, name := range fields.Keys() {
+		if err := validateFacetName(name); err != nil {
+			return nil, err
+		}
+
+		v := must.NotFail(fields.Get(name))
+
+		pipeline, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageFacetNotArray,
+				fmt.Sprintf("arguments to $facet must be arrays, %s is type %s", name, handlerparams.AliasFromType(v)),
+				"$facet (stage)",
+			)
+		}
+
+		stages := make([]aggregations.Stage, 0, pipeline.Len())
+
+		for _, v := range must.NotFail(iterator.ConsumeValues(pipeline.Iterator())) {
+			d, ok := v.(*types.Document)
+			if !ok || d.Len() == 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrStageFacetInvalidStage,
+					fmt.Sprintf(
+						"elements of arrays in $facet spec must be non-empty objects, %s argument contained an element of type %s",
+						name, handlerparams.AliasFromType(v),
+					),
+					"$facet (stage)",
+				)
+			}
+
+			if _, ok := facetNotAllowedStages[d.Command()]; ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrStageFacetNotAllowed,
+					fmt.Sprintf("%s is not allowed to be used within a $facet stage", d.Command()),
+					"$facet (stage)",
+				)
+			}
+
+			s, err := NewStage(d)
+			if err != nil {
+				return nil, err
+			}
+
+			stages = append(stages, s)
+		}
+
+		f.names = append(f.names, name)
+		f.pipelines = append(f.pipelines, stages)
+	}
+
+	return f, nil
+}
+
+// validateFacetName returns CommandError if the given name can't be used as an output field name.
+func validateFacetName(name string) error {
+	switch {
+	case name == "":
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrPathContainsEmptyElement,
+			"FieldPath field names may not be empty strings.",
+			"$facet (stage)",
+		)
+	case strings.HasPrefix(name, "$"):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFieldPathInvalidName,
+			fmt.Sprintf("FieldPath field names may not start with '$'. Consider using $getField or $setField. (%s)", name),
+			"$facet (stage)",
+		)
+	case strings.Contains(name, "."):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFieldPathContainsDot,
+			fmt.Sprintf("FieldPath field names may not contain '.'. Consider using $getField or $setField. (%s)", name),
+			"$facet (stage)",
+		)
+	}
+
+	return nil
+}
+
+// Process implements Stage interface.
+//
+// All input documents are loaded into memory, then each sub-pipeline processes its own copy of them.
+// Both the input and the copies are accounted by the operation's memory tracker.
+// A single document with sub-pipelines results is returned.
+func (f *facet) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	docs, err := iterator.ConsumeValues(common.TrackMemory(ctx, iter, "$facet"))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res := types.MakeDocument(len(f.names))
+
+	for i, name := range f.names {
+		var arr *types.Array
+
+		if arr, err = f.processPipeline(ctx, f.pipelines[i], docs); err != nil {
+			return nil, err
+		}
+
+		res.Set(name, arr)
+	}
+
+	resIter := iterator.Values(iterator.ForSlice([]*types.Document{res}))
+	closer.Add(resIter)
+
+	return resIter, nil
+}
+
+// processPipeline runs the given sub-pipeline stages over copies of the given documents.
+//
+// Copies are made lazily, so they are accounted by the memory tracker before the limit is exceeded.
+func (f *facet) processPipeline(ctx context.Context, stages []aggregations.Stage, docs []*types.Document) (*types.Array, error) { //nolint:lll // for readability
+	var i int
+
+	// stages may modify documents in place
+	copies := iterator.ForFunc(func() (struct{}, *types.Document, error) {
+		var unused struct{}
+
+		if i >= len(docs) {
+			return unused, nil, iterator.ErrIteratorDone
+		}
+
+		i++
+
+		return unused, docs[i-1].DeepCopy(), nil
+	})
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	closer.Add(copies)
+
+	iter := common.TrackMemory(ctx, copies, "$facet")
+	closer.Add(iter)
+
+	for _, s := range stages {
+		var err error
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	res := types.MakeArray(len(docs))
+
+	for {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		res.Append(doc)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage = (*facet)(nil)
+)
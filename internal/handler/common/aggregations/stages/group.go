@@ -117,8 +117,15 @@ func newGroup(stage *types.Document) (aggregations.Stage, error) {
 }
 
 // Process implements Stage interface.
+//
+// If the context's memory tracker allows spilling to disk, documents are sorted by the group key
+// on disk, and groups are accumulated one by one.
 func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	groupedDocuments, err := g.groupDocuments(iter)
+	if limit := common.SpillLimit(ctx); limit > 0 {
+		return g.processSpilled(iter, closer, limit)
+	}
+
+	groupedDocuments, err := g.groupDocuments(common.TrackMemory(ctx, iter, "$group"))
 	if err != nil {
 		return nil, err
 	}
@@ -126,31 +133,100 @@ func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, close
 	var res []*types.Document
 
 	for _, groupedDocument := range groupedDocuments {
-		doc := must.NotFail(types.NewDocument("_id", groupedDocument.groupID))
+		doc, err := g.accumulate(groupedDocument)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, doc)
+	}
 
-		groupIter := iterator.Values(iterator.ForSlice(groupedDocument.documents))
-		defer groupIter.Close()
+	iter = iterator.Values(iterator.ForSlice(res))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// processSpilled groups documents using [common.ExternalSorter] with the given memory limit.
+//
+// Each document is wrapped into `{k: <group key>, d: <document>}`, all wrapped documents are sorted by the group key,
+// and consecutive documents with equal keys form a group.
+// Only documents of a single group are kept in memory at once.
+func (g *group) processSpilled(iter types.DocumentsIterator, closer *iterator.MultiCloser, limit int64) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	defer iter.Close()
 
-		for _, accumulation := range g.groupBy {
-			out, err := accumulation.accumulator.Accumulate(groupIter)
+	sorter := common.NewExternalSorter(func(a, b *types.Document) bool {
+		return types.CompareForAggregation(must.NotFail(a.Get("k")), must.NotFail(b.Get("k"))) == types.Less
+	}, limit)
+
+	for {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			sorter.Iterator().Close()
+			return nil, lazyerrors.Error(err)
+		}
+
+		key, err := g.groupKey(doc)
+		if err != nil {
+			sorter.Iterator().Close()
+			return nil, err
+		}
+
+		if err = sorter.Add(must.NotFail(types.NewDocument("k", key, "d", doc))); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	sorted := sorter.Iterator()
+	defer sorted.Close()
+
+	var res []*types.Document
+	var current *groupedDocuments
+
+	for {
+		_, wrapped, err := sorted.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		key := must.NotFail(wrapped.Get("k"))
+		doc := must.NotFail(wrapped.Get("d")).(*types.Document)
+
+		if current != nil && types.CompareForAggregation(key, current.groupID) == types.Equal {
+			current.documents = append(current.documents, doc)
+			continue
+		}
+
+		if current != nil {
+			out, err := g.accumulate(*current)
 			if err != nil {
-				// existing accumulators do not return error
-				return nil, processGroupStageError(err)
+				return nil, err
 			}
 
-			if doc.Has(accumulation.outputField) {
-				// document has duplicate key
-				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrStageIndexedStringVectorDuplicate,
-					fmt.Sprintf("duplicate field: %s", accumulation.outputField),
-					"$group (stage)",
-				)
-			}
+			res = append(res, out)
+		}
 
-			doc.Set(accumulation.outputField, out)
+		current = &groupedDocuments{
+			groupID:   key,
+			documents: []*types.Document{doc},
 		}
+	}
 
-		res = append(res, doc)
+	if current != nil {
+		out, err := g.accumulate(*current)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, out)
 	}
 
 	iter = iterator.Values(iterator.ForSlice(res))
@@ -159,6 +235,35 @@ func (g *group) Process(ctx context.Context, iter types.DocumentsIterator, close
 	return iter, nil
 }
 
+// accumulate applies accumulators to the documents of a single group.
+func (g *group) accumulate(groupedDocument groupedDocuments) (*types.Document, error) {
+	doc := must.NotFail(types.NewDocument("_id", groupedDocument.groupID))
+
+	groupIter := iterator.Values(iterator.ForSlice(groupedDocument.documents))
+	defer groupIter.Close()
+
+	for _, accumulation := range g.groupBy {
+		out, err := accumulation.accumulator.Accumulate(groupIter)
+		if err != nil {
+			// existing accumulators do not return error
+			return nil, processGroupStageError(err)
+		}
+
+		if doc.Has(accumulation.outputField) {
+			// document has duplicate key
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrStageIndexedStringVectorDuplicate,
+				fmt.Sprintf("duplicate field: %s", accumulation.outputField),
+				"$group (stage)",
+			)
+		}
+
+		doc.Set(accumulation.outputField, out)
+	}
+
+	return doc, nil
+}
+
 // validateGroupKey returns error on invalid group key.
 // If group key is a document, it recursively validates operator and expression.
 func validateGroupKey(groupKey any) error {
@@ -173,8 +278,7 @@ func validateGroupKey(groupKey any) error {
 			return processGroupStageError(err)
 		}
 
-		_, err = op.Process(nil)
-		if err != nil {
+		if err = operators.Validate(op, nil); err != nil {
 			// TODO https://github.com/FerretDB/FerretDB/issues/3129
 			return processGroupStageError(err)
 		}
@@ -239,47 +343,56 @@ func (g *group) groupDocuments(iter types.DocumentsIterator) ([]groupedDocuments
 			return nil, lazyerrors.Error(err)
 		}
 
-		switch groupKey := g.groupExpression.(type) {
-		case *types.Document:
-			val, err := evaluateDocument(groupKey, doc, false)
-			if err != nil {
-				// operator and expression errors are validated in newGroup
-				return nil, lazyerrors.Error(err)
-			}
+		groupKey, err := g.groupKey(doc)
+		if err != nil {
+			return nil, err
+		}
 
-			m.addOrAppend(val, doc)
-		case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time, types.NullType,
-			types.Regex, int32, types.Timestamp, int64:
-			m.addOrAppend(groupKey, doc)
-		case string:
-			expression, err := aggregations.NewExpression(groupKey, nil)
-			if err != nil {
-				var exprErr *aggregations.ExpressionError
-				if errors.As(err, &exprErr) {
-					if exprErr.Code() == aggregations.ErrNotExpression {
-						m.addOrAppend(groupKey, doc)
-						continue
-					}
-
-					return nil, processGroupStageError(err)
+		m.addOrAppend(groupKey, doc)
+	}
+
+	return m.docs, nil
+}
+
+// groupKey returns the group key of the given document.
+func (g *group) groupKey(doc *types.Document) (any, error) {
+	switch groupKey := g.groupExpression.(type) {
+	case *types.Document:
+		val, err := evaluateDocument(groupKey, doc, false)
+		if err != nil {
+			// operator and expression errors are validated in newGroup
+			return nil, lazyerrors.Error(err)
+		}
+
+		return val, nil
+	case *types.Array, float64, types.Binary, types.ObjectID, bool, time.Time, types.NullType,
+		types.Regex, int32, types.Timestamp, int64:
+		return groupKey, nil
+	case string:
+		expression, err := aggregations.NewExpression(groupKey, nil)
+		if err != nil {
+			var exprErr *aggregations.ExpressionError
+			if errors.As(err, &exprErr) {
+				if exprErr.Code() == aggregations.ErrNotExpression {
+					return groupKey, nil
 				}
 
-				return nil, lazyerrors.Error(err)
+				return nil, processGroupStageError(err)
 			}
 
-			val, err := expression.Evaluate(doc)
-			if err != nil {
-				// $group treats non-existent fields as nulls
-				val = types.Null
-			}
+			return nil, lazyerrors.Error(err)
+		}
 
-			m.addOrAppend(val, doc)
-		default:
-			panic(fmt.Sprintf("unexpected type %[1]T (%#[1]v)", groupKey))
+		val, err := expression.Evaluate(doc)
+		if err != nil {
+			// $group treats non-existent fields as nulls
+			val = types.Null
 		}
-	}
 
-	return m.docs, nil
+		return val, nil
+	default:
+		panic(fmt.Sprintf("unexpected type %[1]T (%#[1]v)", groupKey))
+	}
 }
 
 // evaluateDocument recursively evaluates document's field expressions and operators.
@@ -297,6 +410,11 @@ func evaluateDocument(expr, doc *types.Document, nestedField bool) (any, error)
 			return nil, processGroupStageError(err)
 		}
 
+		if v == nil {
+			// $group treats missing values as nulls
+			return types.Null, nil
+		}
+
 		return v, nil
 	}
 
@@ -427,6 +545,24 @@ func processGroupStageError(err error) error {
 				opErr.Error(),
 				"$group (stage)",
 			)
+		case operators.ErrFailedToParse:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				opErr.Error(),
+				"$group (stage)",
+			)
+		case operators.ErrBadValue:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				opErr.Error(),
+				"$group (stage)",
+			)
+		case operators.ErrConversionFailure:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConversionFailure,
+				opErr.Error(),
+				"$group (stage)",
+			)
 		}
 
 	case errors.As(err, &exErr):
@@ -447,10 +583,9 @@ func processGroupStageError(err error) error {
 				"$group (stage)",
 			)
 		case aggregations.ErrUndefinedVariable:
-			// TODO https://github.com/FerretDB/FerretDB/issues/2275
 			return handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"Aggregation expression variables are not implemented yet",
+				handlererrors.ErrUndefinedVariable,
+				fmt.Sprintf("Use of undefined variable: %s", exErr.Name()),
 				"$group (stage)",
 			)
 		case aggregations.ErrEmptyVariable:
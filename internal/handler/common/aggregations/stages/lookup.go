@@ -0,0 +1,597 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stages
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/commonpath"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// LookupSource returns an iterator for all documents of the given collection in the current database.
+//
+// Non-existent collections should be treated as empty.
+type LookupSource func(ctx context.Context, collection string) (types.DocumentsIterator, error)
+
+// lookup represents $lookup stage.
+type lookup struct {
+	source LookupSource
+
+	from         string
+	localField   types.Path
+	foreignField types.Path // empty if there are no localField and foreignField
+	let          *types.Document
+	as           types.Path
+
+	// pipeline stages; nil if there is no pipeline
+	pipeline []*types.Document
+
+	// pipeline stages created once if there are no let variables
+	stages []aggregations.Stage
+}
+
+// newLookup creates a new $lookup stage.
+func newLookup(stage *types.Document) (aggregations.Stage, error) {
+	v := must.NotFail(stage.Get("$lookup"))
+
+	fields, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("the $lookup stage specification must be an object, but found %s", handlerparams.AliasFromType(v)),
+			"$lookup (stage)",
+		)
+	}
+
+	var l lookup
+	var localField, foreignField, as string
+	var hasLocalField, hasForeignField, hasFrom, hasAs bool
+	var pipeline *types.Array
+
+	for _, k := range fields.Keys() {
+		v := must.NotFail(fields.Get(k))
+
+		switch k {
+		case "from", "localField", "foreignField", "as":
+			s, ok := v.(string)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					fmt.Sprintf(
+						"$lookup argument '%s: %s' must be a string, is type %s",
+						k, types.FormatAnyValue(v), handlerparams.AliasFromType(v),
+					),
+					"$lookup (stage)",
+				)
+			}
+
+			switch k {
+			case "from":
+				l.from, hasFrom = s, true
+			case "localField":
+				localField, hasLocalField = s, true
+			case "foreignField":
+				foreignField, hasForeignField = s, true
+			case "as":
+				as, hasAs = s, true
+			}
+
+		case "let":
+			if l.let, ok = v.(*types.Document); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					fmt.Sprintf("$lookup argument 'let' must be an object, is type %s", handlerparams.AliasFromType(v)),
+					"$lookup (stage)",
+				)
+			}
+
+		case "pipeline":
+			if pipeline, ok = v.(*types.Array); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					"'pipeline' option must be specified as an array",
+					"$lookup (stage)",
+				)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("unknown argument to $lookup: %s", k),
+				"$lookup (stage)",
+			)
+		}
+	}
+
+	if !hasAs {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"must specify 'as' field for a $lookup",
+			"$lookup (stage)",
+		)
+	}
+
+	if !hasFrom {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"must specify 'from' field for a $lookup",
+			"$lookup (stage)",
+		)
+	}
+
+	if hasLocalField != hasForeignField {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$lookup requires both or neither of 'localField' and 'foreignField' to be specified",
+			"$lookup (stage)",
+		)
+	}
+
+	if !hasLocalField && pipeline == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$lookup requires either 'pipeline' or both 'localField' and 'foreignField' to be specified",
+			"$lookup (stage)",
+		)
+	}
+
+	var err error
+
+	if l.as, err = lookupFieldPath("as", as); err != nil {
+		return nil, err
+	}
+
+	if hasLocalField {
+		if l.localField, err = lookupFieldPath("localField", localField); err != nil {
+			return nil, err
+		}
+
+		if l.foreignField, err = lookupFieldPath("foreignField", foreignField); err != nil {
+			return nil, err
+		}
+	}
+
+	if pipeline == nil {
+		return &l, nil
+	}
+
+	// validate let variables and pipeline stages with variables set to null,
+	// real values are only known for each input document
+	vars, err := common.GetDocumentLetVariables("$lookup (stage)", l.let, new(types.Document))
+	if err != nil {
+		return nil, err
+	}
+
+	l.pipeline = make([]*types.Document, 0, pipeline.Len())
+
+	for _, v := range must.NotFail(iterator.ConsumeValues(pipeline.Iterator())) {
+		d, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"Each element of the 'pipeline' array must be an object",
+				"$lookup (stage)",
+			)
+		}
+
+		if name := d.Command(); name == "$collStats" || name == "$currentOp" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("%s is not allowed within a $lookup's sub-pipeline", name),
+				"$lookup (stage)",
+			)
+		}
+
+		l.pipeline = append(l.pipeline, d)
+	}
+
+	stages, err := l.newStages(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.let.Len() == 0 {
+		l.stages = stages
+	}
+
+	return &l, nil
+}
+
+// lookupFieldPath returns the path for the given $lookup argument.
+func lookupFieldPath(arg, s string) (types.Path, error) {
+	if strings.HasPrefix(s, "$") {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFieldPathInvalidName,
+			fmt.Sprintf("FieldPath field names may not start with '$'. Consider using $getField or $setField. (%s)", arg),
+			"$lookup (stage)",
+		)
+	}
+
+	path, err := types.NewPathFromString(s)
+	if err != nil {
+		return types.Path{}, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$lookup argument '%s: %s' is not a valid field path", arg, s),
+			"$lookup (stage)",
+		)
+	}
+
+	return path, nil
+}
+
+// newStages creates pipeline stages with the given variables substituted.
+func (l *lookup) newStages(vars map[string]any) ([]aggregations.Stage, error) {
+	res := make([]aggregations.Stage, 0, len(l.pipeline))
+
+	for _, d := range l.pipeline {
+		s, err := NewStage(common.LetStage(d, vars))
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, s)
+	}
+
+	// nested $lookup stages use the same source
+	SetLookupSource(res, l.source)
+
+	return res, nil
+}
+
+// Process implements Stage interface.
+//
+// All documents of the foreign collection are loaded into memory once
+// and accounted by the operation's memory tracker, and indexed by foreignField values if it is set;
+// then joined documents are computed lazily, one input document at a time.
+func (l *lookup) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	if l.source == nil {
+		return nil, lazyerrors.New("$lookup source is not set")
+	}
+
+	source, err := l.source(ctx, l.from)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	foreign, err := iterator.ConsumeValues(common.TrackMemory(ctx, source, "$lookup"))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &lookupIterator{
+		ctx:     ctx,
+		iter:    iter,
+		l:       l,
+		foreign: newLookupForeign(foreign, l.foreignField),
+	}
+	closer.Add(res)
+
+	return res, nil
+}
+
+// joined returns foreign documents joined with the given input document.
+func (l *lookup) joined(ctx context.Context, doc *types.Document, foreign *lookupForeign) (*types.Array, error) {
+	candidates := foreign.docs
+
+	if l.foreignField.Len() > 0 {
+		candidates = foreign.equal(lookupValues(doc, l.localField, false))
+	}
+
+	if l.pipeline == nil {
+		res := types.MakeArray(len(candidates))
+		for _, c := range candidates {
+			res.Append(c.DeepCopy())
+		}
+
+		return res, nil
+	}
+
+	stages := l.stages
+
+	if stages == nil {
+		vars, err := common.GetDocumentLetVariables("$lookup (stage)", l.let, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if stages, err = l.newStages(vars); err != nil {
+			return nil, err
+		}
+	}
+
+	// stages may modify documents in place
+	docs := make([]*types.Document, len(candidates))
+	for i, c := range candidates {
+		docs[i] = c.DeepCopy()
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	var iter types.DocumentsIterator = iterator.Values(iterator.ForSlice(docs))
+	closer.Add(iter)
+
+	for _, s := range stages {
+		var err error
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := iterator.ConsumeValues(iter)
+	if err != nil {
+		return nil, err
+	}
+
+	arr := types.MakeArray(len(res))
+	for _, d := range res {
+		arr.Append(d)
+	}
+
+	return arr, nil
+}
+
+// lookupValues returns values of the given field to match local and foreign documents.
+//
+// Arrays are matched by their elements, and also as a whole if wholeArrays is true;
+// missing field is matched as null.
+func lookupValues(doc *types.Document, path types.Path, wholeArrays bool) []any {
+	values, _ := commonpath.FindValues(doc, path, &commonpath.FindValuesOpts{
+		FindArrayDocuments: true,
+		FindArrayIndex:     true,
+	})
+
+	res := make([]any, 0, len(values))
+
+	for _, v := range values {
+		arr, ok := v.(*types.Array)
+		if !ok {
+			res = append(res, v)
+			continue
+		}
+
+		if wholeArrays {
+			res = append(res, arr)
+		}
+
+		res = append(res, must.NotFail(iterator.ConsumeValues(arr.Iterator()))...)
+	}
+
+	if len(res) == 0 {
+		res = append(res, types.Null)
+	}
+
+	return res
+}
+
+// lookupForeign contains documents of the foreign collection
+// indexed by foreignField values for equality matching.
+type lookupForeign struct {
+	docs []*types.Document
+
+	// values of foreignField for each document, including whole arrays
+	values [][]any
+
+	// indexes of documents by keys of their values
+	byKey map[string][]int
+
+	// indexes of documents with values without keys, in order
+	unkeyed []int
+}
+
+// newLookupForeign indexes the given documents by values of the given field, if it is not empty.
+func newLookupForeign(docs []*types.Document, path types.Path) *lookupForeign {
+	f := &lookupForeign{
+		docs: docs,
+	}
+
+	if path.Len() == 0 {
+		return f
+	}
+
+	f.values = make([][]any, len(docs))
+	f.byKey = make(map[string][]int, len(docs))
+
+	for i, doc := range docs {
+		// unlike local arrays, foreign arrays are matched as a whole too
+		values := lookupValues(doc, path, true)
+		f.values[i] = values
+
+		for _, v := range values {
+			k, ok := lookupKey(v)
+			if !ok {
+				if n := len(f.unkeyed); n == 0 || f.unkeyed[n-1] != i {
+					f.unkeyed = append(f.unkeyed, i)
+				}
+
+				continue
+			}
+
+			if ids := f.byKey[k]; len(ids) == 0 || ids[len(ids)-1] != i {
+				f.byKey[k] = append(ids, i)
+			}
+		}
+	}
+
+	return f
+}
+
+// equal returns foreign documents with values equal to any of the given local values, in their original order.
+//
+// Values are compared with [types.Compare], so different number types could be equal,
+// but regular expressions are compared as values, not as patterns.
+func (f *lookupForeign) equal(local []any) []*types.Document {
+	var ids []int
+
+	var unkeyed bool
+
+	for _, v := range local {
+		k, ok := lookupKey(v)
+		if !ok {
+			unkeyed = true
+			continue
+		}
+
+		ids = append(ids, f.byKey[k]...)
+	}
+
+	if unkeyed {
+		ids = append(ids, f.unkeyed...)
+	}
+
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	res := make([]*types.Document, 0, len(ids))
+
+	for _, i := range ids {
+		if lookupEqual(f.values[i], local) {
+			res = append(res, f.docs[i])
+		}
+	}
+
+	return res
+}
+
+// lookupEqual returns true if any of the foreign values is equal to any of the local values.
+func lookupEqual(foreign, local []any) bool {
+	for _, fv := range foreign {
+		for _, lv := range local {
+			// arrays are compared element by element only with other arrays
+			if _, ok := fv.(*types.Array); ok {
+				if _, ok = lv.(*types.Array); !ok {
+					continue
+				}
+			}
+
+			if types.Compare(fv, lv) == types.Equal {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// lookupKey returns a key for the given value, such that values equal by [types.Compare]
+// have the same key. Different values could have the same key too.
+//
+// It returns false for values of other types that are matched without the index.
+func lookupKey(v any) (string, bool) {
+	switch v := v.(type) {
+	case float64:
+		return lookupNumberKey(v), true
+	case int32:
+		return lookupNumberKey(float64(v)), true
+	case int64:
+		return lookupNumberKey(float64(v)), true
+	case types.Decimal128:
+		return lookupNumberKey(v.Float64()), true
+	case string:
+		return "s" + v, true
+	case types.ObjectID:
+		return "o" + string(v[:]), true
+	case bool:
+		return "b" + strconv.FormatBool(v), true
+	case types.NullType:
+		return "z", true
+	case time.Time:
+		return "t" + strconv.FormatInt(v.UnixMilli(), 10), true
+	default:
+		return "", false
+	}
+}
+
+// lookupNumberKey returns a key for the given number.
+//
+// Large integers lose precision, so different numbers may have the same key.
+func lookupNumberKey(f float64) string {
+	if f == 0 {
+		// -0 and 0 are equal
+		f = 0
+	}
+
+	return "n" + strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// SetLookupSource sets the source of foreign documents for all $lookup stages,
+// including ones in $facet sub-pipelines.
+func SetLookupSource(stages []aggregations.Stage, source LookupSource) {
+	for _, stage := range stages {
+		switch st := stage.(type) {
+		case *lookup:
+			st.source = source
+			SetLookupSource(st.stages, source)
+		case *facet:
+			for _, p := range st.pipelines {
+				SetLookupSource(p, source)
+			}
+		}
+	}
+}
+
+// lookupIterator is returned by lookup.Process.
+type lookupIterator struct {
+	ctx     context.Context
+	iter    types.DocumentsIterator
+	l       *lookup
+	foreign *lookupForeign
+}
+
+// Next implements iterator.Interface.
+func (iter *lookupIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	_, doc, err := iter.iter.Next()
+	if err != nil {
+		return unused, nil, lazyerrors.Error(err)
+	}
+
+	joined, err := iter.l.joined(iter.ctx, doc, iter.foreign)
+	if err != nil {
+		return unused, nil, err
+	}
+
+	if err = doc.SetByPath(iter.l.as, joined); err != nil {
+		return unused, nil, lazyerrors.Error(err)
+	}
+
+	return unused, doc, nil
+}
+
+// Close implements iterator.Interface.
+func (iter *lookupIterator) Close() {
+	iter.iter.Close()
+	iter.foreign = nil
+}
+
+// check interfaces
+var (
+	_ aggregations.Stage      = (*lookup)(nil)
+	_ types.DocumentsIterator = (*lookupIterator)(nil)
+)
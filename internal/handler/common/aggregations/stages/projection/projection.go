@@ -163,7 +163,7 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 				return nil, false, err
 			}
 
-			_, err = op.Process(must.NotFail(types.NewDocument("key", "value")))
+			err = operators.Validate(op, must.NotFail(types.NewDocument("key", "value")))
 			if err = processOperatorError(err); err != nil {
 				return nil, false, err
 			}
@@ -173,12 +173,24 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 
 			result = true
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case string:
+			// path expressions and variables are evaluated later
+			if strings.HasPrefix(value, "$") {
+				if _, err = aggregations.NewExpression(value, nil); err != nil {
+					return nil, false, processOperatorError(err)
+				}
+			}
+
+			result = true
+
+			validated.Set(key, value)
+
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
 			result = true
 
 			validated.Set(key, value)
-		case float64, int32, int64:
+		case float64, types.Decimal128, int32, int64:
 			// projection treats 0 as false and any other value as true
 			comparison := types.Compare(value, int32(0))
 
@@ -238,11 +250,15 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 
 // ProjectDocument applies projection to the copy of the document.
 func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Document, error) {
-	projected, err := types.NewDocument("_id", must.NotFail(doc.Get("_id")))
-	if err != nil {
-		return nil, err
+	// documents produced by some stages, such as $currentOp, don't have _id
+	projected := new(types.Document)
+
+	if id, _ := doc.Get("_id"); id != nil {
+		projected.Set("_id", id)
 	}
 
+	var err error
+
 	if projection.Has("_id") {
 		idValue := must.NotFail(projection.Get("_id"))
 
@@ -267,13 +283,27 @@ func ProjectDocument(doc, projection *types.Document, inclusion bool) (*types.Do
 
 			value, err = op.Process(doc)
 			if err != nil {
+				return nil, processOperatorError(err)
+			}
+
+			// the field is not set if the operator returns the missing value
+			if value != nil {
+				set = true
+				projected.Set("_id", value)
+			}
+
+		case string:
+			var value any
+
+			if value, set, err = evaluateExpression(doc, idValue); err != nil {
 				return nil, err
 			}
 
-			set = true
-			projected.Set("_id", value)
+			if set {
+				projected.Set("_id", value)
+			}
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all this types are treated as new fields value
 			projected.Set("_id", idValue)
 
@@ -354,13 +384,27 @@ func projectDocumentWithoutID(doc *types.Document, projection *types.Document, i
 			}
 
 			v, err = op.Process(doc)
+			if err != nil {
+				return nil, processOperatorError(err)
+			}
+
+			// the field is not set if the operator returns the missing value
+			if v != nil {
+				projected.Set(key, v)
+			}
+
+		case string:
+			v, ok, err := evaluateExpression(doc, value)
 			if err != nil {
 				return nil, err
 			}
 
-			projected.Set(key, v)
+			// the field is not set if the expression evaluates to the missing value
+			if ok {
+				projected.Set(key, v)
+			}
 
-		case *types.Array, string, types.Binary, types.ObjectID,
+		case *types.Array, types.Binary, types.ObjectID,
 			time.Time, types.NullType, types.Regex, types.Timestamp: // all these types are treated as new fields value
 			projected.Set(key, value)
 
@@ -384,6 +428,31 @@ func projectDocumentWithoutID(doc *types.Document, projection *types.Document, i
 	return projected, nil
 }
 
+// evaluateExpression returns the value of a string projected as a new field.
+// Path expressions and variables prefixed with `$` are evaluated with the given document;
+// other strings are returned as is.
+//
+// It returns false if the expression evaluates to the missing value,
+// such as a path to the non-existent field or `$$REMOVE`.
+// In that case, the field should not be set.
+func evaluateExpression(doc *types.Document, value string) (any, bool, error) {
+	if !strings.HasPrefix(value, "$") {
+		return value, true, nil
+	}
+
+	expression, err := aggregations.NewExpression(value, nil)
+	if err != nil {
+		return nil, false, processOperatorError(err)
+	}
+
+	v, err := expression.Evaluate(doc)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	return v, true, nil
+}
+
 // includeProjection copies the field on the path from source to projected.
 // When an array is on the path, it returns the array containing any document
 // with the same key. Dot notation with array index path does not include
@@ -632,6 +701,8 @@ func setBySourceOrder(key string, val any, source, projected *types.Document) {
 // - ErrInvalidPipelineOperator when the operator does not exist.
 // - ErrFailedToParse when operator has invalid variable expression.
 // - ErrGroupInvalidFieldPath when operator has empty path expression.
+// - ErrFailedToParse or ErrBadValue when operator arguments are invalid.
+// - ErrConversionFailure when operator fails to convert a value.
 func processOperatorError(err error) error {
 	if err == nil {
 		return nil
@@ -674,6 +745,24 @@ func processOperatorError(err error) error {
 				"Invalid $project :: caused by :: "+opErr.Error(),
 				"$project (stage)",
 			)
+		case operators.ErrFailedToParse:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				"Invalid $project :: caused by :: "+opErr.Error(),
+				"$project (stage)",
+			)
+		case operators.ErrBadValue:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"Invalid $project :: caused by :: "+opErr.Error(),
+				"$project (stage)",
+			)
+		case operators.ErrConversionFailure:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrConversionFailure,
+				opErr.Error(),
+				"$project (stage)",
+			)
 		}
 
 	case errors.As(err, &exErr):
@@ -694,10 +783,9 @@ func processOperatorError(err error) error {
 				"$project (stage)",
 			)
 		case aggregations.ErrUndefinedVariable:
-			// TODO https://github.com/FerretDB/FerretDB/issues/2275
 			return handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"Aggregation expression variables are not implemented yet",
+				handlererrors.ErrUndefinedVariable,
+				fmt.Sprintf("Invalid $project :: caused by :: Use of undefined variable: %s", exErr.Name()),
 				"$project (stage)",
 			)
 		case aggregations.ErrEmptyVariable:
@@ -59,6 +59,10 @@ func newSet(stage *types.Document) (aggregations.Stage, error) {
 		return nil, err
 	}
 
+	if err := common.ValidateAddFields(fieldsDoc); err != nil {
+		return nil, err
+	}
+
 	return &set{
 		newField: fieldsDoc,
 	}, nil
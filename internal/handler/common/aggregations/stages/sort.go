@@ -61,7 +61,7 @@ func newSort(stage *types.Document) (aggregations.Stage, error) {
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func (s *sort) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	iter, err := common.SortIterator(iter, closer, s.fields)
+	iter, err := common.SortIterator(ctx, iter, closer, s.fields)
 	if err != nil {
 		// TODO https://github.com/FerretDB/FerretDB/issues/3125
 		var pathErr *types.PathError
@@ -17,6 +17,8 @@ package stages
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
@@ -32,6 +34,7 @@ var Stages = map[string]newStageFunc{
 	"$addFields": newAddFields,
 	"$collStats": newCollStats,
 	"$count":     newCount,
+	"$currentOp": newCurrentOp,
 	"$group":     newGroup,
 	"$limit":     newLimit,
 	"$match":     newMatch,
@@ -44,23 +47,26 @@ var Stages = map[string]newStageFunc{
 	// please keep sorted alphabetically
 }
 
+func init() {
+	// $facet and $lookup create stages of their pipelines, so they can't be a part of the initializer
+	Stages["$facet"] = newFacet
+	Stages["$lookup"] = newLookup
+}
+
 // unsupportedStages maps all unsupported yet stages.
 var unsupportedStages = map[string]struct{}{
 	// sorted alphabetically
 	"$bucket":                 {},
 	"$bucketAuto":             {},
 	"$changeStream":           {},
-	"$currentOp":              {},
 	"$densify":                {},
 	"$documents":              {},
-	"$facet":                  {},
 	"$fill":                   {},
 	"$geoNear":                {},
 	"$graphLookup":            {},
 	"$indexStats":             {},
 	"$listLocalSessions":      {},
 	"$listSessions":           {},
-	"$lookup":                 {},
 	"$merge":                  {},
 	"$out":                    {},
 	"$planCacheStats":         {},
@@ -77,6 +83,11 @@ var unsupportedStages = map[string]struct{}{
 	// please keep sorted alphabetically
 }
 
+// Unsupported returns sorted names of known aggregation stages that are not supported yet.
+func Unsupported() []string {
+	return slices.Sorted(maps.Keys(unsupportedStages))
+}
+
 // NewStage creates a new aggregation stage.
 func NewStage(stage *types.Document) (aggregations.Stage, error) {
 	if stage.Len() != 1 {
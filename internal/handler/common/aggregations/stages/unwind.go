@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
@@ -55,6 +56,14 @@ func newUnwind(stage *types.Document) (aggregations.Stage, error) {
 			)
 		}
 
+		if strings.HasPrefix(field, "$$") {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFieldPathInvalidName,
+				"Expression field names may not start with '$'. Consider using $getField or $setField",
+				"$unwind (stage)",
+			)
+		}
+
 		// For $unwind to deconstruct an array from dot notation, array must be at the suffix.
 		// It returns empty result if array is found at other parts of dot notation,
 		// so it does not return value by index of array nor values for given key in array's document.
@@ -108,23 +117,56 @@ func newUnwind(stage *types.Document) (aggregations.Stage, error) {
 }
 
 // Process implements Stage interface.
+//
+// Documents are unwound lazily, one input document at a time.
 func (u *unwind) Process(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser) (types.DocumentsIterator, error) { //nolint:lll // for readability
-	// TODO https://github.com/FerretDB/FerretDB/issues/2490
-	docs, err := iterator.ConsumeValues(iter)
-	if err != nil {
-		return nil, lazyerrors.Error(err)
+	res := &unwindIterator{
+		iter:  iter,
+		field: u.field,
+		key:   u.field.GetExpressionSuffix(),
 	}
+	closer.Add(res)
 
-	var out []*types.Document
+	return res, nil
+}
 
-	if u.field == nil {
-		return nil, nil
-	}
+// unwindIterator is returned by unwind.Process.
+type unwindIterator struct {
+	iter  types.DocumentsIterator
+	field *aggregations.Expression
+	key   string
+
+	// state of the current input document
+	id  any
+	arr *types.Array
+	i   int
+}
+
+// Next implements iterator.Interface.
+func (iter *unwindIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	for {
+		if iter.arr != nil && iter.i < iter.arr.Len() {
+			v := must.NotFail(iter.arr.Get(iter.i))
+			iter.i++
 
-	key := u.field.GetExpressionSuffix()
+			// documents produced by stages like $facet do not have _id
+			if iter.id == nil {
+				return unused, must.NotFail(types.NewDocument(iter.key, v)), nil
+			}
+
+			return unused, must.NotFail(types.NewDocument("_id", iter.id, iter.key, v)), nil
+		}
+
+		iter.arr = nil
+
+		_, doc, err := iter.iter.Next()
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
 
-	for _, doc := range docs {
-		d, err := u.field.Evaluate(doc)
+		d, err := iter.field.Evaluate(doc)
 		if err != nil {
 			// Ignore non-existent values
 			continue
@@ -132,38 +174,25 @@ func (u *unwind) Process(ctx context.Context, iter types.DocumentsIterator, clos
 
 		switch d := d.(type) {
 		case *types.Array:
-			iter := d.Iterator()
-			defer iter.Close()
-
-			id := must.NotFail(doc.Get("_id"))
-
-			for {
-				_, v, err := iter.Next()
-				if err != nil {
-					if errors.Is(err, iterator.ErrIteratorDone) {
-						break
-					}
-
-					return nil, err
-				}
-
-				newDoc := must.NotFail(types.NewDocument("_id", id, key, v))
-				out = append(out, newDoc)
-			}
+			iter.id, _ = doc.Get("_id")
+			iter.arr = d
+			iter.i = 0
 		case types.NullType:
 			// Ignore Nulls
 		default:
-			out = append(out, doc)
+			return unused, doc, nil
 		}
 	}
+}
 
-	iter = iterator.Values(iterator.ForSlice(out))
-	closer.Add(iter)
-
-	return iter, nil
+// Close implements iterator.Interface.
+func (iter *unwindIterator) Close() {
+	iter.iter.Close()
+	iter.arr = nil
 }
 
 // check interfaces
 var (
-	_ aggregations.Stage = (*unwind)(nil)
+	_ aggregations.Stage      = (*unwind)(nil)
+	_ types.DocumentsIterator = (*unwindIterator)(nil)
 )
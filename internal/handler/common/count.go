@@ -16,6 +16,7 @@ package common
 
 import (
 	"log/slog"
+	"math"
 
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -27,8 +28,9 @@ type CountParams struct {
 	DB         string          `ferretdb:"$db"`
 	Collection string          `ferretdb:"count,collection"`
 
-	Skip  int64 `ferretdb:"skip,opt,positiveNumber"`
-	Limit int64 `ferretdb:"limit,opt,positiveNumber"`
+	Skip     int64 `ferretdb:"skip,opt,positiveNumber"`
+	Limit    int64 `ferretdb:"-"`
+	RawLimit any   `ferretdb:"limit,opt"`
 
 	Collation *types.Document `ferretdb:"collation,unimplemented"`
 
@@ -37,7 +39,7 @@ type CountParams struct {
 	MaxTimeMS      int64           `ferretdb:"maxTimeMS,ignored"`
 	Hint           any             `ferretdb:"hint,ignored"`
 	ReadConcern    *types.Document `ferretdb:"readConcern,ignored"`
-	Comment        string          `ferretdb:"comment,ignored"`
+	Comment        string          `ferretdb:"comment,opt"`
 	LSID           any             `ferretdb:"lsid,ignored"`
 	ClusterTime    any             `ferretdb:"$clusterTime,ignored"`
 	ReadPreference *types.Document `ferretdb:"$readPreference,ignored"`
@@ -56,5 +58,25 @@ func GetCountParams(document *types.Document, l *slog.Logger) (*CountParams, err
 		return nil, err
 	}
 
+	if count.RawLimit != nil {
+		// Unlike other commands, count treats negative limit the same way as positive one.
+		limit := count.RawLimit
+
+		switch l := limit.(type) {
+		case float64:
+			limit = math.Abs(l)
+		case int32:
+			limit = max(int64(l), -int64(l))
+		case int64:
+			limit = max(l, -l)
+		case types.Decimal128:
+			limit = l.Abs()
+		}
+
+		if count.Limit, err = handlerparams.GetValidatedNumberParamWithMinValue("count", "limit", limit, 0); err != nil {
+			return nil, err
+		}
+	}
+
 	return &count, nil
 }
@@ -32,7 +32,7 @@ type DeleteParams struct {
 	Comment string   `ferretdb:"comment,opt"`
 	Ordered bool     `ferretdb:"ordered,opt"`
 
-	Let *types.Document `ferretdb:"let,unimplemented"`
+	Let *types.Document `ferretdb:"let,opt"`
 
 	MaxTimeMS      int64           `ferretdb:"maxTimeMS,ignored"`
 	WriteConcern   *types.Document `ferretdb:"writeConcern,ignored"`
@@ -69,5 +69,14 @@ func GetDeleteParams(document *types.Document, l *slog.Logger) (*DeleteParams, e
 		return nil, err
 	}
 
+	vars, err := GetLetVariables("delete", params.Let)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range params.Deletes {
+		params.Deletes[i].Filter = LetFilter(params.Deletes[i].Filter, vars)
+	}
+
 	return &params, nil
 }
@@ -41,7 +41,7 @@ type ExplainParams struct {
 	Aggregate  bool            `ferretdb:"-"`
 	Command    *types.Document `ferretdb:"-"`
 
-	Verbosity string `ferretdb:"verbosity,ignored"`
+	Verbosity string `ferretdb:"verbosity,opt"`
 
 	ApiVersion           string `ferretdb:"apiVersion,ignored"`
 	ApiStrict            bool   `ferretdb:"apiStrict,ignored"`
@@ -58,7 +58,10 @@ func GetExplainParams(document *types.Document, l *slog.Logger) (*ExplainParams,
 		return nil, lazyerrors.Error(err)
 	}
 
-	Ignored(document, l, "verbosity")
+	verbosity, err := GetOptionalParam(document, "verbosity", "allPlansExecution")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
 
 	var cmd *types.Document
 
@@ -93,6 +96,17 @@ func GetExplainParams(document *types.Document, l *slog.Logger) (*ExplainParams,
 		return nil, lazyerrors.Error(err)
 	}
 
+	hint, _ := explain.Get("hint")
+
+	natural, err := GetNaturalHint(hint, "explain")
+	if err != nil {
+		return nil, err
+	}
+
+	if natural != nil && sort.Len() == 0 {
+		sort = natural
+	}
+
 	var limit, skip int64
 
 	if limit, err = GetLimitParam(explain); err != nil {
@@ -147,5 +161,6 @@ func GetExplainParams(document *types.Document, l *slog.Logger) (*ExplainParams,
 		StagesDocs: stagesDocs,
 		Aggregate:  cmd.Command() == "aggregate",
 		Command:    cmd,
+		Verbosity:  verbosity,
 	}, nil
 }
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"slices"
 	"strings"
 	"time"
@@ -289,6 +290,10 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 
 	case "$expr":
 		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)))
+
+	case "$sampleRate":
+		return filterSampleRateOperator(filterValue)
+
 	default:
 		msg := fmt.Sprintf(
 			`unknown top level operator: %s. `+
@@ -321,7 +326,7 @@ func filterExprOperator(doc, filter *types.Document) (bool, error) {
 	switch v := v.(type) {
 	case *types.Document, *types.Array, string, types.Binary, types.ObjectID, time.Time, types.Regex, types.Timestamp:
 		return true, nil
-	case float64, int32, int64:
+	case float64, types.Decimal128, int32, int64:
 		return types.Compare(v, int32(0)) != types.Equal, nil
 	case bool:
 		return v, nil
@@ -332,6 +337,39 @@ func filterExprOperator(doc, filter *types.Document) (bool, error) {
 	}
 }
 
+// filterSampleRateOperator handles {$sampleRate: rate} filter.
+// It matches a document with the given probability.
+func filterSampleRateOperator(filterValue any) (bool, error) {
+	var rate float64
+
+	switch v := filterValue.(type) {
+	case float64:
+		rate = v
+	case int32:
+		rate = float64(v)
+	case int64:
+		rate = float64(v)
+	case types.Decimal128:
+		rate = v.Float64()
+	default:
+		return false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"argument to $sampleRate must be a numeric type",
+			"$sampleRate",
+		)
+	}
+
+	if !(rate >= 0 && rate <= 1) {
+		return false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"numeric argument to $sampleRate must be in [0, 1]",
+			"$sampleRate",
+		)
+	}
+
+	return operators.Rand() < rate, nil
+}
+
 // filterFieldExpr handles {field: {expr}} or {field: {document}} filter.
 func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *types.Document) (bool, error) {
 	// check if both documents are empty
@@ -1188,6 +1226,20 @@ func filterFieldMod(fieldValue, exprValue any) (bool, error) {
 	case int64:
 		field = f
 
+	case types.Decimal128:
+		if f.IsNaN() || f.IsInf(0) {
+			return false, nil
+		}
+
+		r := f.Rat()
+		t := new(big.Int).Quo(r.Num(), r.Denom())
+
+		if !t.IsInt64() {
+			return false, nil
+		}
+
+		field = t.Int64()
+
 	default:
 		return false, nil
 	}
@@ -1424,15 +1476,19 @@ func filterFieldValueByTypeCode(fieldValue any, code handlerparams.TypeCode) (bo
 		if _, ok := fieldValue.(int64); !ok {
 			return false, nil
 		}
+	case handlerparams.TypeCodeDecimal:
+		if _, ok := fieldValue.(types.Decimal128); !ok {
+			return false, nil
+		}
 	case handlerparams.TypeCodeNumber:
-		// TypeCodeNumber should match int32, int64 and float64 types
+		// TypeCodeNumber should match int32, int64, float64 and Decimal128 types
 		switch fieldValue.(type) {
-		case float64, int32, int64:
+		case float64, types.Decimal128, int32, int64:
 			return true, nil
 		default:
 			return false, nil
 		}
-	case handlerparams.TypeCodeDecimal, handlerparams.TypeCodeMinKey, handlerparams.TypeCodeMaxKey:
+	case handlerparams.TypeCodeMinKey, handlerparams.TypeCodeMaxKey:
 		return false, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrNotImplemented,
 			fmt.Sprintf(`Type code %v not implemented`, code),
@@ -42,21 +42,21 @@ type FindParams struct {
 	AwaitData    bool            `ferretdb:"awaitData,opt"`
 
 	Collation *types.Document `ferretdb:"collation,unimplemented"`
-	Let       *types.Document `ferretdb:"let,unimplemented"`
+	Let       *types.Document `ferretdb:"let,opt"`
 
-	AllowDiskUse     bool            `ferretdb:"allowDiskUse,ignored"`
+	AllowDiskUse     bool            `ferretdb:"allowDiskUse,opt"`
 	ReadConcern      *types.Document `ferretdb:"readConcern,ignored"`
-	Max              *types.Document `ferretdb:"max,ignored"`
-	Min              *types.Document `ferretdb:"min,ignored"`
-	Hint             any             `ferretdb:"hint,ignored"`
-	LSID             any             `ferretdb:"lsid,ignored"`
+	Max              *types.Document `ferretdb:"max,opt"`
+	Min              *types.Document `ferretdb:"min,opt"`
+	Hint             any             `ferretdb:"hint,opt"`
+	ReturnKey        bool            `ferretdb:"returnKey,opt"`
+	LSID             any             `ferretdb:"lsid,opt"`
 	TxnNumber        int64           `ferretdb:"txnNumber,ignored"`
 	StartTransaction bool            `ferretdb:"startTransaction,ignored"`
 	Autocommit       bool            `ferretdb:"autocommit,ignored"`
 	ClusterTime      any             `ferretdb:"$clusterTime,ignored"`
 	ReadPreference   *types.Document `ferretdb:"$readPreference,ignored"`
 
-	ReturnKey           bool `ferretdb:"returnKey,unimplemented-non-default"`
 	OplogReplay         bool `ferretdb:"oplogReplay,ignored"`
 	AllowPartialResults bool `ferretdb:"allowPartialResults,unimplemented-non-default"`
 
@@ -66,6 +66,10 @@ type FindParams struct {
 	ApiVersion           string `ferretdb:"apiVersion,ignored"`
 	ApiStrict            bool   `ferretdb:"apiStrict,ignored"`
 	ApiDeprecationErrors bool   `ferretdb:"apiDeprecationErrors,ignored"`
+
+	// HintIndexKey is the key pattern of the index specified by Hint, if any.
+	// It is set by the handler for `min`, `max`, and `returnKey`.
+	HintIndexKey *types.Document `ferretdb:"-"`
 }
 
 // GetFindParams returns `find` command parameters.
@@ -86,5 +90,21 @@ func GetFindParams(doc *types.Document, l *slog.Logger) (*FindParams, error) {
 		)
 	}
 
+	vars, err := GetLetVariables("find", params.Let)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Filter = LetFilter(params.Filter, vars)
+
+	natural, err := GetNaturalHint(params.Hint, "find")
+	if err != nil {
+		return nil, err
+	}
+
+	if natural != nil && params.Sort.Len() == 0 {
+		params.Sort = natural
+	}
+
 	return &params, nil
 }
@@ -43,7 +43,7 @@ type FindAndModifyParams struct {
 
 	HasUpdateOperators bool `ferretdb:"-"`
 
-	Let          *types.Document `ferretdb:"let,unimplemented"`
+	Let          *types.Document `ferretdb:"let,opt"`
 	Collation    *types.Document `ferretdb:"collation,unimplemented"`
 	Fields       *types.Document `ferretdb:"fields,unimplemented"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
@@ -131,5 +131,12 @@ func GetFindAndModifyParams(doc *types.Document, l *slog.Logger) (*FindAndModify
 
 	params.HasUpdateOperators = hasUpdateOperators
 
+	vars, err := GetLetVariables("findAndModify", params.Let)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Query = LetFilter(params.Query, vars)
+
 	return &params, nil
 }
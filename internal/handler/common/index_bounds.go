@@ -0,0 +1,221 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// ValidateIndexBounds checks that `min` and `max` bounds of the find command
+// match each other and the key pattern of the hinted index.
+// Nil key pattern means that the hint does not specify an index.
+func ValidateIndexBounds(keyPattern, min, max *types.Document) error {
+	if min == nil && max == nil {
+		return nil
+	}
+
+	if keyPattern == nil {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMinMaxNoHint,
+			"When using min()/max() a hint of which index to use must be provided",
+			"find",
+		)
+	}
+
+	if min != nil && max != nil && !slices.Equal(min.Keys(), max.Keys()) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMinMaxFieldsMismatch,
+			"min() and max() must have the same field names",
+			"find",
+		)
+	}
+
+	for _, bound := range []*types.Document{min, max} {
+		if bound != nil && !slices.Equal(bound.Keys(), keyPattern.Keys()) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMinMaxIndexMismatch,
+				"The index chosen is not equivalent to min/max bounds",
+				"find",
+			)
+		}
+	}
+
+	for _, v := range keyPattern.Values() {
+		if _, ok := v.(string); ok {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				"min() and max() are not supported for hashed indexes",
+				"find",
+			)
+		}
+	}
+
+	return nil
+}
+
+// IndexBoundsIterator returns an iterator that filters out documents with index keys
+// outside of [min, max) range of the given index key pattern.
+// Nil bound is not checked.
+// It will be added to the given closer.
+//
+// Bounds should be validated with ValidateIndexBounds.
+//
+// Close method closes the underlying iterator.
+func IndexBoundsIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, keyPattern, min, max *types.Document) types.DocumentsIterator { //nolint:lll // for readability
+	if min == nil && max == nil {
+		return iter
+	}
+
+	res := &indexBoundsIterator{
+		iter:       iter,
+		keyPattern: keyPattern,
+		min:        min,
+		max:        max,
+	}
+	closer.Add(res)
+
+	return res
+}
+
+// indexBoundsIterator is returned by IndexBoundsIterator.
+type indexBoundsIterator struct {
+	iter       types.DocumentsIterator
+	keyPattern *types.Document
+	min        *types.Document
+	max        *types.Document
+}
+
+// Next implements iterator.Interface. See IndexBoundsIterator for details.
+func (iter *indexBoundsIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	for {
+		_, doc, err := iter.iter.Next()
+		if err != nil {
+			return unused, nil, lazyerrors.Error(err)
+		}
+
+		if iter.min != nil && compareIndexKey(doc, iter.keyPattern, iter.min) == types.Less {
+			continue
+		}
+
+		if iter.max != nil && compareIndexKey(doc, iter.keyPattern, iter.max) != types.Less {
+			continue
+		}
+
+		return unused, doc, nil
+	}
+}
+
+// Close implements iterator.Interface. See IndexBoundsIterator for details.
+func (iter *indexBoundsIterator) Close() {
+	iter.iter.Close()
+}
+
+// compareIndexKey compares the index key of the given document with the given bound
+// in the order of the given index key pattern.
+func compareIndexKey(doc, keyPattern, bound *types.Document) types.CompareResult {
+	values := keyPattern.Values()
+
+	for i, field := range keyPattern.Keys() {
+		order := types.Ascending
+		if v, _ := handlerparams.GetWholeNumberParam(values[i]); v == -1 {
+			order = types.Descending
+		}
+
+		res := types.CompareOrderForSort(indexKeyValue(doc, field), must.NotFail(bound.Get(field)), order)
+		if res != types.Equal {
+			return res
+		}
+	}
+
+	return types.Equal
+}
+
+// ReturnKeyIterator returns an iterator that replaces documents with their keys for the given index key pattern.
+// Nil key pattern means that no index is used; empty documents are returned in that case.
+// RecordIDs of documents are preserved.
+// It will be added to the given closer.
+//
+// Close method closes the underlying iterator.
+func ReturnKeyIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, keyPattern *types.Document) types.DocumentsIterator { //nolint:lll // for readability
+	res := &returnKeyIterator{
+		iter:       iter,
+		keyPattern: keyPattern,
+	}
+	closer.Add(res)
+
+	return res
+}
+
+// returnKeyIterator is returned by ReturnKeyIterator.
+type returnKeyIterator struct {
+	iter       types.DocumentsIterator
+	keyPattern *types.Document
+}
+
+// Next implements iterator.Interface. See ReturnKeyIterator for details.
+func (iter *returnKeyIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	_, doc, err := iter.iter.Next()
+	if err != nil {
+		return unused, nil, lazyerrors.Error(err)
+	}
+
+	key := types.MakeDocument(iter.keyPattern.Len())
+
+	for _, field := range iter.keyPattern.Keys() {
+		key.Set(field, indexKeyValue(doc, field))
+	}
+
+	key.SetRecordID(doc.RecordID())
+
+	return unused, key, nil
+}
+
+// Close implements iterator.Interface. See ReturnKeyIterator for details.
+func (iter *returnKeyIterator) Close() {
+	iter.iter.Close()
+}
+
+// indexKeyValue returns the value of the given index field of the document.
+// Missing fields are indexed as null.
+func indexKeyValue(doc *types.Document, field string) any {
+	path, err := types.NewPathFromString(field)
+	if err != nil {
+		return types.Null
+	}
+
+	v, err := doc.GetByPath(path)
+	if err != nil {
+		return types.Null
+	}
+
+	return v
+}
+
+// check interfaces
+var (
+	_ types.DocumentsIterator = (*indexBoundsIterator)(nil)
+	_ types.DocumentsIterator = (*returnKeyIterator)(nil)
+)
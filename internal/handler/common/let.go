@@ -0,0 +1,398 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// GetLetVariables validates and evaluates `let` variables of the given command.
+//
+// Each variable value is evaluated only once as an aggregation expression,
+// variables defined earlier could be used in the values of later ones.
+// The returned variables also include system variables `$$NOW`, `$$CLUSTER_TIME` and `$$USER_ROLES`
+// that have the same values for the whole command execution.
+// Other system variables depend on the document and are handled by aggregations.Expression.
+// The let could be nil.
+func GetLetVariables(command string, let *types.Document) (map[string]any, error) {
+	now := time.Now().UTC()
+
+	vars := map[string]any{
+		"NOW":          now,
+		"CLUSTER_TIME": types.NextTimestamp(now),
+
+		// roles are not supported yet, see connectionStatus command
+		"USER_ROLES": types.MakeArray(0),
+	}
+
+	if err := evalLetVariables(command, let, new(types.Document), vars); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+// GetDocumentLetVariables validates and evaluates `let` variables against the given document.
+//
+// It is used by stages like `$lookup` that define variables for each input document.
+// System variables are not included; they are substituted by LetStage for the whole command.
+func GetDocumentLetVariables(command string, let, doc *types.Document) (map[string]any, error) {
+	vars := make(map[string]any, let.Len())
+
+	if err := evalLetVariables(command, let, doc, vars); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+// evalLetVariables validates and evaluates `let` variables against the given document,
+// adding them to vars.
+//
+// The let could be nil.
+func evalLetVariables(command string, let, doc *types.Document, vars map[string]any) error {
+	if let == nil {
+		return nil
+	}
+
+	iter := let.Iterator()
+	defer iter.Close()
+
+	for {
+		name, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if err = validateVariableName(command, name); err != nil {
+			return err
+		}
+
+		expr, err := operators.NewExpr(must.NotFail(types.NewDocument("$expr", substituteLetVariables(v, vars))), command)
+		if err != nil {
+			return err
+		}
+
+		if vars[name], err = expr.Process(doc); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// validateVariableName returns CommandError if the given name can't be used as a user variable name.
+func validateVariableName(command, name string) error {
+	if name == "" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrEmptyVariableName,
+			"empty variable names are not allowed",
+			command,
+		)
+	}
+
+	for i, r := range name {
+		if r > unicode.MaxASCII {
+			continue
+		}
+
+		if i == 0 && !unicode.IsLower(r) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrVariableNameInvalidStart,
+				fmt.Sprintf("'%s' starts with an invalid character for a user variable name", name),
+				command,
+			)
+		}
+
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrVariableNameInvalidChar,
+				fmt.Sprintf("'%s' contains an invalid character for a variable name: '%c'", name, r),
+				command,
+			)
+		}
+	}
+
+	return nil
+}
+
+// LetFilter returns a copy of the filter with variables substituted in `$expr` operators.
+//
+// It returns the filter as is if there are no variables.
+func LetFilter(filter *types.Document, vars map[string]any) *types.Document {
+	if filter == nil || len(vars) == 0 {
+		return filter
+	}
+
+	// key/value pairs are used to keep duplicate keys for later validation
+	pairs := make([]any, 0, filter.Len()*2)
+
+	iter := filter.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		// filter was already parsed and can't be invalid
+		must.NoError(err)
+
+		switch k {
+		case "$expr":
+			v = substituteLetVariables(v, vars)
+		case "$and", "$or", "$nor":
+			if arr, ok := v.(*types.Array); ok {
+				exprs := types.MakeArray(arr.Len())
+
+				for _, e := range must.NotFail(iterator.ConsumeValues(arr.Iterator())) {
+					if d, ok := e.(*types.Document); ok {
+						e = LetFilter(d, vars)
+					}
+
+					exprs.Append(e)
+				}
+
+				v = exprs
+			}
+		}
+
+		pairs = append(pairs, k, v)
+	}
+
+	return must.NotFail(types.NewDocument(pairs...))
+}
+
+// LetStage returns a copy of the aggregation pipeline stage with variables substituted in expressions.
+//
+// Only stages that accept expressions are affected; for `$match` stage, only `$expr` operators are.
+// It returns the stage as is if there are no variables.
+func LetStage(stage *types.Document, vars map[string]any) *types.Document {
+	if len(vars) == 0 || stage.Len() != 1 {
+		return stage
+	}
+
+	switch name := stage.Command(); name {
+	case "$match":
+		if filter, ok := must.NotFail(stage.Get(name)).(*types.Document); ok {
+			return must.NotFail(types.NewDocument(name, LetFilter(filter, vars)))
+		}
+	case "$addFields", "$group", "$project", "$set":
+		return must.NotFail(types.NewDocument(name, substituteLetVariables(must.NotFail(stage.Get(name)), vars)))
+	case "$facet":
+		if spec, ok := must.NotFail(stage.Get(name)).(*types.Document); ok {
+			return must.NotFail(types.NewDocument(name, letFacet(spec, vars)))
+		}
+	case "$lookup":
+		if spec, ok := must.NotFail(stage.Get(name)).(*types.Document); ok {
+			return must.NotFail(types.NewDocument(name, letLookup(spec, vars)))
+		}
+	}
+
+	return stage
+}
+
+// letFacet returns a copy of the `$facet` stage specification with variables substituted
+// in stages of all sub-pipelines.
+func letFacet(spec *types.Document, vars map[string]any) *types.Document {
+	// key/value pairs are used to keep duplicate keys for later validation
+	pairs := make([]any, 0, spec.Len()*2)
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		must.NoError(err)
+
+		if pipeline, ok := v.(*types.Array); ok {
+			v = letPipeline(pipeline, vars)
+		}
+
+		pairs = append(pairs, k, v)
+	}
+
+	return must.NotFail(types.NewDocument(pairs...))
+}
+
+// letPipeline returns a copy of the aggregation pipeline with variables substituted in all stages.
+func letPipeline(pipeline *types.Array, vars map[string]any) *types.Array {
+	res := types.MakeArray(pipeline.Len())
+
+	for _, s := range must.NotFail(iterator.ConsumeValues(pipeline.Iterator())) {
+		if d, ok := s.(*types.Document); ok {
+			s = LetStage(d, vars)
+		}
+
+		res.Append(s)
+	}
+
+	return res
+}
+
+// letLookup returns a copy of the `$lookup` stage specification with variables substituted
+// in `let` values and `pipeline` stages.
+//
+// Variables defined by the `let` of the stage itself shadow outer variables with the same names
+// in the `pipeline`.
+func letLookup(spec *types.Document, vars map[string]any) *types.Document {
+	pipelineVars := vars
+
+	if let, ok := spec.Map()["let"].(*types.Document); ok && let.Len() > 0 {
+		pipelineVars = maps.Clone(vars)
+
+		for _, name := range let.Keys() {
+			delete(pipelineVars, name)
+		}
+	}
+
+	// key/value pairs are used to keep duplicate keys for later validation
+	pairs := make([]any, 0, spec.Len()*2)
+
+	iter := spec.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		must.NoError(err)
+
+		switch k {
+		case "let":
+			if let, ok := v.(*types.Document); ok {
+				v = substituteLetVariables(let, vars)
+			}
+		case "pipeline":
+			if pipeline, ok := v.(*types.Array); ok {
+				v = letPipeline(pipeline, pipelineVars)
+			}
+		}
+
+		pairs = append(pairs, k, v)
+	}
+
+	return must.NotFail(types.NewDocument(pairs...))
+}
+
+// substituteLetVariables returns a copy of the expression with `$$<name>` and `$$<name>.<path>` strings
+// replaced by `$literal` operators with values of defined variables.
+//
+// Values of `$literal` operators and undefined variables are left as is.
+func substituteLetVariables(expr any, vars map[string]any) any {
+	switch expr := expr.(type) {
+	case *types.Document:
+		pairs := make([]any, 0, expr.Len()*2)
+
+		iter := expr.Iterator()
+		defer iter.Close()
+
+		for {
+			k, v, err := iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			must.NoError(err)
+
+			if k != "$literal" {
+				v = substituteLetVariables(v, vars)
+			}
+
+			pairs = append(pairs, k, v)
+		}
+
+		return must.NotFail(types.NewDocument(pairs...))
+
+	case *types.Array:
+		res := types.MakeArray(expr.Len())
+
+		for _, v := range must.NotFail(iterator.ConsumeValues(expr.Iterator())) {
+			res.Append(substituteLetVariables(v, vars))
+		}
+
+		return res
+
+	case string:
+		if !strings.HasPrefix(expr, "$$") {
+			return expr
+		}
+
+		name, rest, _ := strings.Cut(strings.TrimPrefix(expr, "$$"), ".")
+
+		v, ok := vars[name]
+		if !ok {
+			return expr
+		}
+
+		if rest != "" {
+			v = letVariableValueByPath(v, rest)
+		}
+
+		return must.NotFail(types.NewDocument("$literal", v))
+
+	default:
+		return expr
+	}
+}
+
+// letVariableValueByPath returns the value of the variable at the given dot notation path.
+// It returns Null if the path does not exist.
+func letVariableValueByPath(v any, path string) any {
+	p, err := types.NewPathFromString(path)
+	if err != nil {
+		return types.Null
+	}
+
+	var res any
+
+	switch v := v.(type) {
+	case *types.Document:
+		res, err = v.GetByPath(p)
+	case *types.Array:
+		res, err = v.GetByPath(p)
+	default:
+		return types.Null
+	}
+
+	if err != nil {
+		return types.Null
+	}
+
+	return res
+}
@@ -0,0 +1,186 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MemoryTracker accounts approximate memory used by blocking stages
+// (in-memory sort and grouping) of a single operation.
+//
+// If `allowDiskUse` option is set, sort and $group stages spill to temporary files
+// (see [ExternalSorter]) instead of returning an error when the limit is reached.
+type MemoryTracker struct {
+	limit        int64 // zero or negative means no limit
+	used         int64
+	allowDiskUse bool
+}
+
+// memoryTrackerKey is a context key for the *MemoryTracker.
+type memoryTrackerKey struct{}
+
+// NewMemoryTracker returns a new tracker with the given limit in bytes; zero disables the limit.
+func NewMemoryTracker(limit int64, allowDiskUse bool) *MemoryTracker {
+	return &MemoryTracker{
+		limit:        limit,
+		allowDiskUse: allowDiskUse,
+	}
+}
+
+// WithMemoryTracker returns a derived context with the given tracker.
+func WithMemoryTracker(ctx context.Context, t *MemoryTracker) context.Context {
+	return context.WithValue(ctx, memoryTrackerKey{}, t)
+}
+
+// Used returns the approximate number of bytes used by blocking stages so far.
+func (t *MemoryTracker) Used() int64 {
+	return t.used
+}
+
+// SpillLimit returns the number of bytes that blocking stages could keep in memory
+// before spilling to disk, or 0 if the context's tracker does not allow spilling.
+func SpillLimit(ctx context.Context) int64 {
+	t, _ := ctx.Value(memoryTrackerKey{}).(*MemoryTracker)
+	if t == nil || t.limit <= 0 || !t.allowDiskUse {
+		return 0
+	}
+
+	return t.limit
+}
+
+// TrackMemory returns an iterator that accounts the sizes of documents returned by the given iterator
+// in the context's tracker, and returns an error if the limit is exceeded.
+//
+// It should be used for the input of blocking stages that keep all documents in memory.
+// The stage is used for error messages, for example, "$group" or "sort".
+// If the context does not have a tracker, the given iterator is returned as is.
+func TrackMemory(ctx context.Context, iter types.DocumentsIterator, stage string) types.DocumentsIterator {
+	t, _ := ctx.Value(memoryTrackerKey{}).(*MemoryTracker)
+	if t == nil {
+		return iter
+	}
+
+	return &memoryTrackingIterator{
+		iter:    iter,
+		tracker: t,
+		stage:   stage,
+	}
+}
+
+// memoryTrackingIterator is returned by TrackMemory.
+type memoryTrackingIterator struct {
+	iter    types.DocumentsIterator
+	tracker *MemoryTracker
+	stage   string
+}
+
+// Next implements iterator.Interface. See TrackMemory for details.
+func (iter *memoryTrackingIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	_, doc, err := iter.iter.Next()
+	if err != nil {
+		return unused, nil, err
+	}
+
+	t := iter.tracker
+	t.used += approxSize(doc)
+
+	if t.limit > 0 && t.used > t.limit && !t.allowDiskUse {
+		return unused, nil, memoryLimitError(iter.stage, t.limit)
+	}
+
+	return unused, doc, nil
+}
+
+// Close implements iterator.Interface. See TrackMemory for details.
+func (iter *memoryTrackingIterator) Close() {
+	iter.iter.Close()
+}
+
+// memoryLimitError returns an error for the given stage that exceeded the given limit.
+func memoryLimitError(stage string, limit int64) error {
+	var msg string
+
+	switch stage {
+	case "sort":
+		msg = fmt.Sprintf(
+			"Sort exceeded memory limit of %d bytes, but did not opt in to external sorting. "+
+				"Pass allowDiskUse:true to opt in.",
+			limit,
+		)
+	default:
+		msg = fmt.Sprintf("Exceeded memory limit for %s, but didn't allow external sort. Pass allowDiskUse:true to opt in.", stage)
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrQueryExceededMemoryLimitNoDiskUseAllowed, msg, stage)
+}
+
+// approxSize returns the approximate in-memory size of the given value in bytes.
+func approxSize(v any) int64 {
+	switch v := v.(type) {
+	case *types.Document:
+		size := int64(64)
+
+		values := v.Values()
+		for i, k := range v.Keys() {
+			size += int64(len(k)) + 16 + approxSize(values[i])
+		}
+
+		return size
+
+	case *types.Array:
+		size := int64(48)
+
+		for i := range v.Len() {
+			size += 16 + approxSize(must.NotFail(v.Get(i)))
+		}
+
+		return size
+
+	case string:
+		return int64(len(v))
+
+	case types.Binary:
+		return int64(len(v.B)) + 8
+
+	case types.Regex:
+		return int64(len(v.Pattern) + len(v.Options))
+
+	case types.ObjectID:
+		return 12
+
+	case float64, int32, int64, bool, types.NullType, types.Timestamp:
+		return 8
+
+	case types.Decimal128:
+		return 16
+
+	default:
+		// time.Time and anything unexpected
+		return 24
+	}
+}
+
+// check interfaces
+var (
+	_ types.DocumentsIterator = (*memoryTrackingIterator)(nil)
+)
@@ -316,174 +316,6 @@ func getBinaryMaskParam(operator string, mask any) (uint64, error) {
 	return bitmask, nil
 }
 
-// addNumbers returns the result of v1 and v2 addition and error if addition failed.
-// The v1 and v2 parameters could be float64, int32, int64.
-// The result would be the broader type possible, i.e. int32 + int64 produces int64.
-func addNumbers(v1, v2 any) (any, error) {
-	switch v1 := v1.(type) {
-	case float64:
-		switch v2 := v2.(type) {
-		case float64:
-			return v1 + v2, nil
-		case int32:
-			return v1 + float64(v2), nil
-		case int64:
-			return v1 + float64(v2), nil
-		default:
-			return nil, handlerparams.ErrUnexpectedRightOpType
-		}
-	case int32:
-		switch v2 := v2.(type) {
-		case float64:
-			return v2 + float64(v1), nil
-		case int32:
-			if v2 == math.MaxInt32 && v1 > 0 {
-				return int64(v1) + int64(v2), nil
-			}
-
-			if v2 == math.MinInt32 && v1 < 0 {
-				return int64(v1) + int64(v2), nil
-			}
-
-			return v1 + v2, nil
-		case int64:
-			if v2 > 0 {
-				if int64(v1) > math.MaxInt64-v2 {
-					return nil, handlerparams.ErrLongExceededPositive
-				}
-			} else {
-				if int64(v1) < math.MinInt64-v2 {
-					return nil, handlerparams.ErrLongExceededNegative
-				}
-			}
-
-			return v2 + int64(v1), nil
-		default:
-			return nil, handlerparams.ErrUnexpectedRightOpType
-		}
-	case int64:
-		switch v2 := v2.(type) {
-		case float64:
-			return v2 + float64(v1), nil
-		case int32:
-			if v2 > 0 {
-				if v1 > math.MaxInt64-int64(v2) {
-					return nil, handlerparams.ErrIntExceeded
-				}
-			} else {
-				if v1 < math.MinInt64-int64(v2) {
-					return nil, handlerparams.ErrIntExceeded
-				}
-			}
-
-			return v1 + int64(v2), nil
-		case int64:
-			if v2 > 0 {
-				if v1 > math.MaxInt64-v2 {
-					return nil, handlerparams.ErrLongExceededPositive
-				}
-			} else {
-				if v1 < math.MinInt64-v2 {
-					return nil, handlerparams.ErrLongExceededNegative
-				}
-			}
-
-			return v1 + v2, nil
-		default:
-			return nil, handlerparams.ErrUnexpectedRightOpType
-		}
-	default:
-		return nil, handlerparams.ErrUnexpectedLeftOpType
-	}
-}
-
-// multiplyNumbers returns the multiplication of v1 and v2.
-// The v1 and v2 parameters could be float64, int32 and int64.
-// Multiplication of negative number with zero produces 0, not -0.
-// The produced result maybe be the broader type:
-// int32 * int64 produces int64.
-func multiplyNumbers(v1, v2 any) (any, error) {
-	switch v1 := v1.(type) {
-	case float64:
-		var res float64
-
-		switch v2 := v2.(type) {
-		case float64:
-			res = v1 * v2
-		case int32:
-			res = v1 * float64(v2)
-		case int64:
-			res = v1 * float64(v2)
-		default:
-			return nil, handlerparams.ErrUnexpectedRightOpType
-		}
-
-		return res, nil
-	case int32:
-		switch v2 := v2.(type) {
-		case float64:
-			return float64(v1) * v2, nil
-		case int32:
-			res := v1 * v2
-			if int64(res) != int64(v1)*int64(v2) {
-				return int64(v1) * int64(v2), nil
-			}
-
-			return res, nil
-		case int64:
-			return multiplyLongSafely(int64(v1), v2)
-
-		default:
-			return nil, handlerparams.ErrUnexpectedRightOpType
-		}
-	case int64:
-		switch v2 := v2.(type) {
-		case float64:
-			return float64(v1) * v2, nil
-		case int32:
-			v, err := multiplyLongSafely(v1, int64(v2))
-			if err != nil {
-				return 0, handlerparams.ErrIntExceeded
-			}
-
-			return v, nil
-		case int64:
-			return multiplyLongSafely(v1, v2)
-
-		default:
-			return nil, handlerparams.ErrUnexpectedRightOpType
-		}
-	default:
-		return nil, handlerparams.ErrUnexpectedLeftOpType
-	}
-}
-
-// multiplyLongSafely returns the multiplication of two int64 values.
-// It handles int64 overflows, and returns errLongExceeded error on one.
-//
-// Please always use multiplyNumbers as it calls multiplyLongSafely when needed.
-func multiplyLongSafely(v1, v2 int64) (int64, error) {
-	switch {
-	// 0 and 1 values are excluded, because those are only values that
-	// can multiply `MinInt64` without exceeding the range.
-	case v1 == 0 || v2 == 0 || v1 == 1 || v2 == 1:
-		return v1 * v2, nil
-
-	// Multiplying MinInt64 by any other value than above results in overflow.
-	// This check is necessary only for MinInt64, as multiplying MinInt64 by -1
-	// results in overflow with the MinInt64 as result.
-	case v1 == math.MinInt64 || v2 == math.MinInt64:
-		return 0, handlerparams.ErrLongExceededNegative
-	}
-
-	res := v1 * v2
-	if res/v2 != v1 {
-		return 0, handlerparams.ErrLongExceededPositive
-	}
-
-	return res, nil
-}
-
 // performBitLogic returns the result of a bitwise operation on two Integer(int32/int64) values.
 // It only supports 'and', 'or' and 'xor' bitwise operators.
 //
@@ -156,7 +156,7 @@ func ValidateProjection(projection *types.Document) (*types.Document, bool, erro
 			inclusionField = true
 
 			validated.Set(key, value)
-		case float64, int32, int64:
+		case float64, types.Decimal128, int32, int64:
 			// projection treats 0 as false and any other value as true
 			comparison := types.Compare(value, int32(0))
 
@@ -31,12 +31,32 @@ import (
 //
 // If sort path is invalid, it returns a possibly wrapped types.PathError.
 func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
-	if sortDoc.Len() == 0 {
+	less, err := documentsLessFunc(sortDoc)
+	if err != nil {
+		return err
+	}
+
+	if less == nil {
+		// no keys to sort by
 		return nil
 	}
 
+	sort.Slice(docs, func(i, j int) bool { return less(docs[i], docs[j]) })
+
+	return nil
+}
+
+// documentsLessFunc returns a function that reports whether document a sorts before document b
+// according to the given sorting conditions, or nil if there are no keys to sort by.
+//
+// If sort path is invalid, it returns a possibly wrapped types.PathError.
+func documentsLessFunc(sortDoc *types.Document) (func(a, b *types.Document) bool, error) {
+	if sortDoc.Len() == 0 {
+		return nil, nil
+	}
+
 	if sortDoc.Len() > 32 {
-		return lazyerrors.Errorf("maximum sort keys exceeded: %v", sortDoc.Len())
+		return nil, lazyerrors.Errorf("maximum sort keys exceeded: %v", sortDoc.Len())
 	}
 
 	sortFuncs := make([]sortFunc, sortDoc.Len())
@@ -50,7 +70,7 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 			// TODO https://github.com/FerretDB/FerretDB/issues/3127
 			for _, field := range fields {
 				if strings.HasPrefix(field, "$") {
-					return handlererrors.NewCommandErrorMsgWithArgument(
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
 						handlererrors.ErrFieldPathInvalidName,
 						"FieldPath field names may not start with '$'. Consider using $getField or $setField.",
 						"sort",
@@ -63,26 +83,49 @@ func SortDocuments(docs []*types.Document, sortDoc *types.Document) error {
 
 		sortType, err := GetSortType(sortKey, sortField)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		sortPath, err := types.NewPathFromString(sortKey)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		sortFuncs[i] = lessFunc(sortPath, sortType)
 	}
 
-	if len(sortFuncs) == 0 {
-		// no keys to sort by
-		return nil
+	return func(a, b *types.Document) bool {
+		return lessDocuments(sortFuncs, a, b)
+	}, nil
+}
+
+// GetNaturalHint returns `{$natural: 1}` or `{$natural: -1}` sort document
+// if the given hint of the given command forces a collection scan in natural order.
+// For other hints (that are ignored), it returns nil.
+func GetNaturalHint(hint any, command string) (*types.Document, error) {
+	doc, ok := hint.(*types.Document)
+	if !ok || !doc.Has("$natural") {
+		return nil, nil
 	}
 
-	sorter := &docsSorter{docs: docs, sorts: sortFuncs}
-	sort.Sort(sorter)
+	if doc.Len() != 1 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("$natural hint cannot be combined with other fields: %v", types.FormatAnyValue(doc)),
+			command,
+		)
+	}
 
-	return nil
+	v, err := handlerparams.GetWholeNumberParam(must.NotFail(doc.Get("$natural")))
+	if err != nil || (v != 1 && v != -1) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"$natural hint must be 1 (for ascending) or -1 (for descending)",
+			command,
+		)
+	}
+
+	return must.NotFail(types.NewDocument("$natural", v)), nil
 }
 
 // ValidateSortDocument validates sort documents, and return
@@ -166,25 +209,12 @@ func lessFunc(sortPath types.Path, sortType types.SortType) func(a, b *types.Doc
 
 type sortFunc func(a, b *types.Document) bool
 
-type docsSorter struct {
-	docs  []*types.Document
-	sorts []sortFunc
-}
-
-func (ds *docsSorter) Len() int {
-	return len(ds.docs)
-}
-
-func (ds *docsSorter) Swap(i, j int) {
-	ds.docs[i], ds.docs[j] = ds.docs[j], ds.docs[i]
-}
-
-func (ds *docsSorter) Less(i, j int) bool {
-	p, q := ds.docs[i], ds.docs[j]
+// lessDocuments reports whether p sorts before q using the given comparisons in order.
+func lessDocuments(sorts []sortFunc, p, q *types.Document) bool {
 	// Try all but the last comparison.
 	var k int
-	for k = 0; k < len(ds.sorts)-1; k++ {
-		sortFunc := ds.sorts[k]
+	for k = 0; k < len(sorts)-1; k++ {
+		sortFunc := sorts[k]
 
 		switch {
 		case sortFunc(p, q):
@@ -198,7 +228,7 @@ func (ds *docsSorter) Less(i, j int) bool {
 	}
 	// All comparisons to here said "equal", so just return whatever
 	// the final comparison reports.
-	return ds.sorts[k](p, q)
+	return sorts[k](p, q)
 }
 
 // GetSortType determines SortType from input sort value.
@@ -15,6 +15,9 @@
 package common
 
 import (
+	"context"
+	"errors"
+
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -25,13 +28,24 @@ import (
 //
 // Since sorting iterator is impossible, this function fully consumes and closes the underlying iterator,
 // sorts documents in memory and returns a new iterator over the sorted slice.
-func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
+// Memory used by documents is accounted in the context's [MemoryTracker], if any.
+// If the tracker allows that, documents that do not fit into the limit are sorted on disk instead.
+func SortIterator(ctx context.Context, iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document) (types.DocumentsIterator, error) { //nolint:lll // for readability
 	// don't consume all documents if there is no sort
 	if sort.Len() == 0 {
 		return iter, nil
 	}
 
-	docs, err := iterator.ConsumeValues(iter)
+	// natural order is applied by the backend
+	if sort.Len() == 1 && sort.Has("$natural") {
+		return iter, nil
+	}
+
+	if limit := SpillLimit(ctx); limit > 0 {
+		return externalSortIterator(iter, closer, sort, limit)
+	}
+
+	docs, err := iterator.ConsumeValues(TrackMemory(ctx, iter, "sort"))
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -45,3 +59,39 @@ func SortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, so
 
 	return res, nil
 }
+
+// externalSortIterator is a variant of SortIterator that uses [ExternalSorter] with the given limit.
+func externalSortIterator(iter types.DocumentsIterator, closer *iterator.MultiCloser, sort *types.Document, limit int64) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	less, err := documentsLessFunc(sort)
+	if err != nil {
+		iter.Close()
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer iter.Close()
+
+	sorter := NewExternalSorter(less, limit)
+
+	for {
+		var doc *types.Document
+
+		if _, doc, err = iter.Next(); err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			sorter.Iterator().Close()
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err = sorter.Add(doc); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	res := sorter.Iterator()
+	closer.Add(res)
+
+	return res, nil
+}
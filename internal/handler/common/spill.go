@@ -0,0 +1,380 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// ExternalSorter sorts documents that may not fit into the memory limit.
+//
+// Documents are buffered in memory until the limit is reached;
+// then the buffer is sorted and written to a temporary file as a sorted run.
+// The final iterator merges all runs and the remaining buffer.
+// Temporary files are created in the [os.TempDir] (that could be changed with TMPDIR environment variable)
+// and removed when the iterator is closed.
+//
+// Documents that compare equal are returned in the order they were added.
+type ExternalSorter struct {
+	less    func(a, b *types.Document) bool
+	limit   int64
+	buf     []*types.Document
+	bufSize int64
+	runs    []*os.File
+}
+
+// NewExternalSorter returns a new sorter with the given comparison function and memory limit in bytes.
+func NewExternalSorter(less func(a, b *types.Document) bool, limit int64) *ExternalSorter {
+	return &ExternalSorter{
+		less:  less,
+		limit: limit,
+	}
+}
+
+// Add adds a document to the sorter, spilling buffered documents to disk if the limit is reached.
+func (s *ExternalSorter) Add(doc *types.Document) error {
+	s.buf = append(s.buf, doc)
+	s.bufSize += approxSize(doc)
+
+	if s.bufSize <= s.limit {
+		return nil
+	}
+
+	if err := s.spill(); err != nil {
+		s.removeRuns()
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// Iterator returns an iterator over all added documents in sorted order.
+// The sorter should not be used after that.
+func (s *ExternalSorter) Iterator() types.DocumentsIterator {
+	s.sortBuf()
+
+	if len(s.runs) == 0 {
+		return iterator.Values(iterator.ForSlice(s.buf))
+	}
+
+	iter := &mergeIterator{
+		h: mergeHeap{
+			less: s.less,
+		},
+		runs: s.runs,
+	}
+
+	for i, f := range s.runs {
+		iter.h.sources = append(iter.h.sources, &mergeSource{
+			r:     bufio.NewReader(f),
+			index: i,
+		})
+	}
+
+	iter.h.sources = append(iter.h.sources, &mergeSource{
+		docs:  s.buf,
+		index: len(s.runs),
+	})
+
+	s.buf = nil
+	s.runs = nil
+
+	return iter
+}
+
+// sortBuf sorts buffered documents.
+func (s *ExternalSorter) sortBuf() {
+	sort.SliceStable(s.buf, func(i, j int) bool { return s.less(s.buf[i], s.buf[j]) })
+}
+
+// spill sorts buffered documents and writes them to a new temporary file.
+func (s *ExternalSorter) spill() error {
+	s.sortBuf()
+
+	f, err := os.CreateTemp("", "ferretdb-spill-*")
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	s.runs = append(s.runs, f)
+
+	w := bufio.NewWriter(f)
+
+	for _, doc := range s.buf {
+		if err = writeSpilled(w, doc); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	if err = w.Flush(); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	s.buf = nil
+	s.bufSize = 0
+
+	return nil
+}
+
+// removeRuns closes and removes all temporary files.
+func (s *ExternalSorter) removeRuns() {
+	removeSpilled(s.runs)
+	s.runs = nil
+}
+
+// removeSpilled closes and removes given temporary files.
+func removeSpilled(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+}
+
+// writeSpilled writes a document with its RecordID to w.
+func writeSpilled(w io.Writer, doc *types.Document) error {
+	d, err := bson.FromDocument(doc)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	raw, err := d.Encode()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if err = binary.Write(w, binary.LittleEndian, doc.RecordID()); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = w.Write(raw); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// readSpilled reads a document written by writeSpilled from r.
+//
+// It returns [io.EOF] if there are no more documents.
+func readSpilled(r io.Reader) (*types.Document, error) {
+	var recordID int64
+	if err := binary.Read(r, binary.LittleEndian, &recordID); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	var l [4]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	size := int(binary.LittleEndian.Uint32(l[:]))
+	if size < len(l) {
+		return nil, lazyerrors.Errorf("invalid spilled document size %d", size)
+	}
+
+	b := make([]byte, size)
+	copy(b, l[:])
+
+	if _, err := io.ReadFull(r, b[len(l):]); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc, err := bson.ToDocument(wirebson.RawDocument(b))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	doc.SetRecordID(recordID)
+
+	return doc, nil
+}
+
+// mergeSource is a single sorted run: either a temporary file or an in-memory slice.
+type mergeSource struct {
+	r     *bufio.Reader
+	docs  []*types.Document
+	head  *types.Document
+	index int
+}
+
+// next sets the next document of the run as the head.
+// It returns [io.EOF] if the run is exhausted.
+func (src *mergeSource) next() error {
+	if src.r == nil {
+		if len(src.docs) == 0 {
+			return io.EOF
+		}
+
+		src.head, src.docs = src.docs[0], src.docs[1:]
+
+		return nil
+	}
+
+	doc, err := readSpilled(src.r)
+	if err != nil {
+		return err
+	}
+
+	src.head = doc
+
+	return nil
+}
+
+// mergeHeap is a min-heap of runs ordered by their heads.
+// Runs with equal heads are ordered by index to keep the sort stable.
+type mergeHeap struct {
+	less    func(a, b *types.Document) bool
+	sources []*mergeSource
+}
+
+// Len implements heap.Interface.
+func (h *mergeHeap) Len() int {
+	return len(h.sources)
+}
+
+// Less implements heap.Interface.
+func (h *mergeHeap) Less(i, j int) bool {
+	a, b := h.sources[i], h.sources[j]
+
+	switch {
+	case h.less(a.head, b.head):
+		return true
+	case h.less(b.head, a.head):
+		return false
+	default:
+		return a.index < b.index
+	}
+}
+
+// Swap implements heap.Interface.
+func (h *mergeHeap) Swap(i, j int) {
+	h.sources[i], h.sources[j] = h.sources[j], h.sources[i]
+}
+
+// Push implements heap.Interface.
+func (h *mergeHeap) Push(x any) {
+	h.sources = append(h.sources, x.(*mergeSource))
+}
+
+// Pop implements heap.Interface.
+func (h *mergeHeap) Pop() any {
+	n := len(h.sources)
+	src := h.sources[n-1]
+	h.sources = h.sources[:n-1]
+
+	return src
+}
+
+// mergeIterator merges sorted runs of [ExternalSorter].
+type mergeIterator struct {
+	h           mergeHeap
+	runs        []*os.File
+	initialized bool
+	err         error
+}
+
+// Next implements iterator.Interface. See ExternalSorter for details.
+func (iter *mergeIterator) Next() (struct{}, *types.Document, error) {
+	var unused struct{}
+
+	if iter.err != nil {
+		return unused, nil, iter.err
+	}
+
+	if !iter.initialized {
+		iter.initialized = true
+
+		sources := iter.h.sources
+		iter.h.sources = make([]*mergeSource, 0, len(sources))
+
+		for _, src := range sources {
+			if err := iter.advance(src); err != nil {
+				return unused, nil, err
+			}
+		}
+	}
+
+	if iter.h.Len() == 0 {
+		iter.Close()
+		iter.err = iterator.ErrIteratorDone
+
+		return unused, nil, iter.err
+	}
+
+	src := heap.Pop(&iter.h).(*mergeSource)
+	doc := src.head
+
+	if err := iter.advance(src); err != nil {
+		return unused, nil, err
+	}
+
+	return unused, doc, nil
+}
+
+// advance moves the given run to the next document and pushes it back to the heap unless it is exhausted.
+func (iter *mergeIterator) advance(src *mergeSource) error {
+	err := src.next()
+
+	switch {
+	case err == nil:
+		heap.Push(&iter.h, src)
+		return nil
+	case errors.Is(err, io.EOF):
+		return nil
+	default:
+		iter.Close()
+		iter.err = lazyerrors.Error(err)
+
+		return iter.err
+	}
+}
+
+// Close implements iterator.Interface. See ExternalSorter for details.
+func (iter *mergeIterator) Close() {
+	removeSpilled(iter.runs)
+	iter.runs = nil
+	iter.h.sources = nil
+
+	if iter.err == nil {
+		iter.err = iterator.ErrIteratorDone
+	}
+}
+
+// check interfaces
+var (
+	_ types.DocumentsIterator = (*mergeIterator)(nil)
+	_ heap.Interface          = (*mergeHeap)(nil)
+)
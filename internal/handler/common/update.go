@@ -20,10 +20,12 @@ import (
 	"fmt"
 	"math"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -80,6 +82,13 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 			}
 		}
 
+		var version int64
+		if param.Versioned && !upsert {
+			if version, err = documentVersion(cmd, doc); err != nil {
+				return nil, err
+			}
+		}
+
 		if !param.HasUpdateOperators {
 			modified, err = processReplacementDoc(cmd, doc, param.Update)
 		} else {
@@ -94,6 +103,12 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 			doc.Set("_id", types.NewObjectID())
 		}
 
+		if param.Versioned && (upsert || modified) {
+			if err = setVersion(cmd, doc, version, upsert); err != nil {
+				return nil, err
+			}
+		}
+
 		// TODO https://github.com/FerretDB/FerretDB/issues/3454
 		if err = doc.ValidateData(); err != nil {
 			return nil, lazyerrors.Error(err)
@@ -109,11 +124,19 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 			// upsert happens only once, no need to iterate further
 			return result, nil
 		} else if modified {
-			_, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
+			res, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
 			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
 
+			if param.Versioned && res.Updated == 0 {
+				return nil, NewUpdateError(
+					handlererrors.ErrWriteConflict,
+					"Document was modified concurrently, retry the operation",
+					cmd,
+				)
+			}
+
 			result.Modified.Count++
 			if isFindAndModify {
 				result.Modified.Doc = doc
@@ -122,6 +145,50 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 	}
 }
 
+// documentVersion returns the version of the stored document of versioned collection.
+//
+// Missing version is treated as 0.
+func documentVersion(command string, doc *types.Document) (int64, error) {
+	v, _ := doc.Get(backends.VersionField)
+
+	switch v := v.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v, nil
+	default:
+		return 0, NewUpdateError(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("'%s' field of a document in a versioned collection must be a long", backends.VersionField),
+			command,
+		)
+	}
+}
+
+// setVersion sets the version of the updated or upserted document of versioned collection.
+//
+// Upserted documents get version 1, updated documents get the next version.
+// If the update sets the version field to a value other than the current version,
+// it was computed from a stale document, and WriteConflict error is returned.
+func setVersion(command string, doc *types.Document, current int64, upsert bool) error {
+	if upsert {
+		doc.Set(backends.VersionField, int64(1))
+		return nil
+	}
+
+	if v, _ := doc.Get(backends.VersionField); v != nil && types.Compare(v, current) != types.Equal {
+		return NewUpdateError(
+			handlererrors.ErrWriteConflict,
+			fmt.Sprintf("Document version %v is stale, the current version is %d", v, current),
+			command,
+		)
+	}
+
+	doc.Set(backends.VersionField, current+1)
+
+	return nil
+}
+
 // processFilterEqualityCondition copies the fields with equality condition from filter to doc.
 func processFilterEqualityCondition(doc, filter *types.Document) error {
 	iter := filter.Iterator()
@@ -248,18 +315,7 @@ func processUpdateOperator(command string, doc, update *types.Document, upsert b
 			}
 
 		case "$unset":
-			var path types.Path
-
-			path, err = types.NewPathFromString(key)
-			if err != nil {
-				// ValidateUpdateOperators checked already $unset contains valid path.
-				panic(err)
-			}
-
-			if doc.HasByPath(path) {
-				doc.RemoveByPath(path)
-				updated = true
-			}
+			updated = processUnsetFieldExpression(doc, key)
 
 		case "$inc":
 			updated, err = processIncFieldExpression(command, doc, key, value)
@@ -427,6 +483,29 @@ func processSetFieldExpression(command string, doc *types.Document, setKey strin
 	return true, nil
 }
 
+// processUnsetFieldExpression changes document according to $unset operator.
+// Array elements are set to null instead of being removed, so indexes of other elements do not change.
+// If the document was changed it returns true.
+func processUnsetFieldExpression(doc *types.Document, unsetKey string) bool {
+	// unsetKey has valid path, checked in ValidateUpdateOperators.
+	path := must.NotFail(types.NewPathFromString(unsetKey))
+
+	if !doc.HasByPath(path) {
+		return false
+	}
+
+	if path.Len() > 1 {
+		if _, ok := must.NotFail(doc.GetByPath(path.TrimSuffix())).(*types.Array); ok {
+			must.NoError(doc.SetByPath(path, types.Null))
+			return true
+		}
+	}
+
+	doc.RemoveByPath(path)
+
+	return true
+}
+
 // processRenameFieldExpression changes document according to $rename operator.
 // If the document was changed it returns true.
 func processRenameFieldExpression(command string, doc *types.Document, key string, value any) (bool, error) {
@@ -464,26 +543,26 @@ func processRenameFieldExpression(command string, doc *types.Document, key strin
 	}
 
 	// Get value to move
-	val, err := doc.GetByPath(sourcePath)
+	val, arrayKey, err := getRenameSource(doc, sourcePath)
 	if err != nil {
-		var dpe *types.PathError
-		if !errors.As(err, &dpe) {
-			panic("getByPath returned error with invalid type")
-		}
-
-		if dpe.Code() == types.ErrPathKeyNotFound || dpe.Code() == types.ErrPathIndexOutOfBound {
-			return false, nil
-		}
+		return false, NewUpdateError(handlererrors.ErrUnsuitableValueType, err.Error(), command)
+	}
 
-		if dpe.Code() == types.ErrPathIndexInvalid {
-			return false, NewUpdateError(
-				handlererrors.ErrUnsuitableValueType,
-				fmt.Sprintf("cannot use path '%s' to traverse the document", sourcePath),
-				command,
-			)
-		}
+	if val == nil {
+		return false, nil
+	}
 
-		return false, NewUpdateError(handlererrors.ErrUnsuitableValueType, dpe.Error(), command)
+	if arrayKey != "" {
+		return false, NewUpdateError(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf(
+				"The source field cannot be an array element, '%s' in doc with _id: %s has an array field called '%s'",
+				sourcePath,
+				types.FormatAnyValue(must.NotFail(doc.Get("_id"))),
+				arrayKey,
+			),
+			command,
+		)
 	}
 
 	// Remove old document
@@ -491,18 +570,96 @@ func processRenameFieldExpression(command string, doc *types.Document, key strin
 
 	// Set new path with old value
 	if err = doc.SetByPath(targetPath, val); err != nil {
-		return false, lazyerrors.Error(err)
+		return false, NewUpdateError(handlererrors.ErrUnsuitableValueType, err.Error(), command)
+	}
+
+	if arrayKey = findArrayKey(doc, targetPath); arrayKey != "" {
+		return false, NewUpdateError(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf(
+				"The destination field cannot be an array element, '%s' in doc with _id: %s has an array field called '%s'",
+				targetPath,
+				types.FormatAnyValue(must.NotFail(doc.Get("_id"))),
+				arrayKey,
+			),
+			command,
+		)
 	}
 
 	return true, nil
 }
 
+// getRenameSource returns the value at the given $rename source path, or nil if there is no such value.
+// It also returns the key of the deepest array on the path, or empty string if the path contains no arrays.
+//
+// It returns an error if the path goes through a scalar value or
+// uses an element that is not a non-negative index to access an array.
+func getRenameSource(doc *types.Document, path types.Path) (any, string, error) {
+	var arrayKey, parentKey string
+	var next any = doc
+
+	for _, elem := range path.Slice() {
+		switch comp := next.(type) {
+		case *types.Document:
+			if next, _ = comp.Get(elem); next == nil {
+				return nil, "", nil
+			}
+
+		case *types.Array:
+			index, err := strconv.Atoi(elem)
+			if err != nil || index < 0 {
+				return nil, "", cannotTraverseError(path, parentKey, comp)
+			}
+
+			if index >= comp.Len() {
+				return nil, "", nil
+			}
+
+			arrayKey = parentKey
+			next = must.NotFail(comp.Get(index))
+
+		default:
+			return nil, "", cannotTraverseError(path, parentKey, comp)
+		}
+
+		parentKey = elem
+	}
+
+	return next, arrayKey, nil
+}
+
+// findArrayKey returns the key of the deepest array that contains the value at the given existing path,
+// or empty string if there is no such array.
+func findArrayKey(doc *types.Document, path types.Path) string {
+	for prefix := path; prefix.Len() > 1; {
+		prefix = prefix.TrimSuffix()
+
+		if _, ok := must.NotFail(doc.GetByPath(prefix)).(*types.Array); ok {
+			return prefix.Suffix()
+		}
+	}
+
+	return ""
+}
+
+// cannotTraverseError returns an error for the path that can't be traversed
+// because of the given value with the given key.
+func cannotTraverseError(path types.Path, key string, value any) error {
+	return fmt.Errorf(
+		"cannot use the part (%s of %s) to traverse the element ({%s: %s})",
+		key,
+		path,
+		key,
+		types.FormatAnyValue(value),
+	)
+}
+
 // processIncFieldExpression changes document according to $inc operator.
 // If the document was changed it returns true.
 func processIncFieldExpression(command string, doc *types.Document, incKey string, incValue any) (bool, error) {
 	// ensure incValue is a valid number type.
 	switch incValue.(type) {
-	case float64, int32, int64:
+	case float64, types.Decimal128, int32, int64:
 	default:
 		return false, NewUpdateError(
 			handlererrors.ErrTypeMismatch,
@@ -539,7 +696,7 @@ func processIncFieldExpression(command string, doc *types.Document, incKey strin
 		return false, err
 	}
 
-	incremented, err := addNumbers(incValue, docValue)
+	incremented, err := aggregations.AddNumbers(incValue, docValue)
 	if err == nil {
 		if err = doc.SetByPath(path, incremented); err != nil {
 			return false, lazyerrors.Error(err)
@@ -554,6 +711,11 @@ func processIncFieldExpression(command string, doc *types.Document, incKey strin
 			return false, nil
 		}
 
+		// decimal value is not changed only if the representation is the same, 1 + 0.0 is changed to 1.0
+		if docDecimal, ok := docValue.(types.Decimal128); ok && !docDecimal.IsNaN() && docDecimal == incremented {
+			return false, nil
+		}
+
 		return true, nil
 	}
 
@@ -689,13 +851,16 @@ func processMulFieldExpression(command string, doc *types.Document, mulKey strin
 
 	if !doc.HasByPath(path) {
 		// $mul sets the field to zero if the field does not exist.
-		switch mulValue.(type) {
+		switch v := mulValue.(type) {
 		case float64:
 			mulValue = float64(0)
 		case int32:
 			mulValue = int32(0)
 		case int64:
 			mulValue = int64(0)
+		case types.Decimal128:
+			// like MongoDB, multiply by zero to keep the exponent, so 1.5 sets 0.0
+			mulValue = v.Mul(types.NewDecimal128FromInt64(0))
 		default:
 			return false, NewUpdateError(
 				handlererrors.ErrTypeMismatch,
@@ -722,7 +887,7 @@ func processMulFieldExpression(command string, doc *types.Document, mulKey strin
 	}
 
 	var multiplied any
-	multiplied, err = multiplyNumbers(mulValue, docValue)
+	multiplied, err = aggregations.MultiplyNumbers(mulValue, docValue)
 
 	switch {
 	case err == nil:
@@ -735,6 +900,15 @@ func processMulFieldExpression(command string, doc *types.Document, mulKey strin
 			)
 		}
 
+		if multiplied, ok := multiplied.(types.Decimal128); ok && multiplied.IsInf(0) {
+			return false, handlererrors.NewCommandErrorMsg(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("update produces invalid value: { %q: %s } "+
+					"(update operations that produce infinity values are not allowed)", path, multiplied,
+				),
+			)
+		}
+
 		// after successfully getting value from path, setting it back cannot fail.
 		must.NoError(doc.SetByPath(path, multiplied))
 
@@ -34,9 +34,9 @@ type UpdateParams struct {
 	Comment   string `ferretdb:"comment,opt"`
 	MaxTimeMS int64  `ferretdb:"maxTimeMS,ignored"`
 
-	Let *types.Document `ferretdb:"let,unimplemented"`
+	Let *types.Document `ferretdb:"let,opt"`
 
-	Ordered                  bool            `ferretdb:"ordered,ignored"`
+	Ordered                  bool            `ferretdb:"ordered,opt"`
 	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
 	WriteConcern             *types.Document `ferretdb:"writeConcern,ignored"`
 	LSID                     any             `ferretdb:"lsid,ignored"`
@@ -61,6 +61,9 @@ type Update struct {
 
 	HasUpdateOperators bool `ferretdb:"-"`
 
+	// Versioned is true if the collection is versioned; set by the handler.
+	Versioned bool `ferretdb:"-"`
+
 	C            *types.Document `ferretdb:"c,unimplemented"`
 	Collation    *types.Document `ferretdb:"collation,unimplemented"`
 	ArrayFilters *types.Array    `ferretdb:"arrayFilters,unimplemented"`
@@ -89,13 +92,24 @@ type UpdateResult struct {
 
 // GetUpdateParams returns parameters for update command.
 func GetUpdateParams(document *types.Document, l *slog.Logger) (*UpdateParams, error) {
-	var params UpdateParams
+	params := UpdateParams{
+		Ordered: true,
+	}
 
 	err := handlerparams.ExtractParams(document, "update", &params, l)
 	if err != nil {
 		return nil, err
 	}
 
+	vars, err := GetLetVariables("update", params.Let)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range params.Updates {
+		params.Updates[i].Filter = LetFilter(params.Updates[i].Filter, vars)
+	}
+
 	if len(params.Updates) > 0 {
 		for i := range params.Updates {
 			update := &params.Updates[i]
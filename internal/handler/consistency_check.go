@@ -0,0 +1,375 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Anomalies found by the consistency check are stored in the same collection
+// as MongoDB's dbCheck command uses.
+const (
+	healthLogDatabase   = "local"
+	healthLogCollection = "system.healthlog"
+)
+
+// consistencyAnomaly represents a single problem found by the consistency check.
+type consistencyAnomaly struct {
+	id    any    // _id of the document, nil if unknown
+	index string // name of the index, empty for document problems
+	msg   string
+}
+
+// runConsistencyCheck checks consistency of all collections according to the given interval.
+func (h *Handler) runConsistencyCheck() {
+	if h.ConsistencyCheckInterval <= 0 {
+		h.L.Info("Consistency check disabled.")
+		return
+	}
+
+	h.L.Info(
+		"Consistency check enabled.",
+		slog.Duration("interval", h.ConsistencyCheckInterval),
+		slog.Int("sample_size", h.ConsistencyCheckSampleSize),
+	)
+
+	ticker := time.NewTicker(h.ConsistencyCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.checkAllCollections(context.Background()); err != nil {
+				h.L.Error("Failed to check consistency.", logging.Error(err))
+			}
+
+		case <-h.consistencyCheckStop:
+			h.L.Info("Consistency check stopped.")
+			return
+		}
+	}
+}
+
+// checkAllCollections checks consistency of all collections and reports found anomalies.
+func (h *Handler) checkAllCollections(ctx context.Context) error {
+	ctx, span := otel.Tracer("").Start(ctx, "HandlerCheckAllCollections")
+	h.L.DebugContext(ctx, "checkAllCollections: started", slog.Int("sample_size", h.ConsistencyCheckSampleSize))
+
+	start := time.Now()
+	defer func() {
+		span.End()
+		h.L.DebugContext(ctx, "checkAllCollections: finished", slog.Duration("duration", time.Since(start)))
+	}()
+
+	connInfo := conninfo.New()
+	connInfo.SetBypassBackendAuth()
+	ctx = conninfo.Ctx(ctx, connInfo)
+
+	dbList, err := h.b.ListDatabases(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, dbInfo := range dbList.Databases {
+		if dbInfo.Name == healthLogDatabase {
+			continue
+		}
+
+		db, err := h.b.Database(dbInfo.Name)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		cList, err := db.ListCollections(ctx, nil)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for _, cInfo := range cList.Collections {
+			c, err := db.Collection(cInfo.Name)
+			if err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			anomalies, err := checkCollectionConsistency(ctx, c, h.ConsistencyCheckSampleSize)
+			if err != nil {
+				if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) ||
+					backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+					continue
+				}
+
+				return lazyerrors.Error(err)
+			}
+
+			if err = h.reportAnomalies(ctx, dbInfo.Name, cInfo.Name, anomalies); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkCollectionConsistency validates all documents of the given collection
+// and checks that sampleSize random documents could be found by keys of all collection's indexes.
+func checkCollectionConsistency(ctx context.Context, c backends.Collection, sampleSize int) ([]consistencyAnomaly, error) {
+	queryRes, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	iter := queryRes.Iter
+	defer iter.Close()
+
+	var anomalies []consistencyAnomaly
+	var seen int
+
+	sample := make([]*types.Document, 0, sampleSize)
+
+	for {
+		var doc *types.Document
+
+		_, doc, err = iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// validation could modify the document
+		if err = doc.DeepCopy().ValidateData(); err != nil {
+			id, _ := doc.Get("_id")
+			anomalies = append(anomalies, consistencyAnomaly{id: id, msg: "Invalid document: " + err.Error()})
+
+			continue
+		}
+
+		// reservoir sampling, so all documents have the same chance to be checked
+		seen++
+
+		if len(sample) < sampleSize {
+			sample = append(sample, doc)
+			continue
+		}
+
+		if i := rand.IntN(seen); i < sampleSize {
+			sample[i] = doc
+		}
+	}
+
+	iter.Close()
+
+	if len(sample) == 0 {
+		return anomalies, nil
+	}
+
+	indexes, err := c.ListIndexes(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, doc := range sample {
+		for _, index := range indexes.Indexes {
+			var msg string
+
+			if msg, err = checkIndexEntry(ctx, c, &index, doc); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if msg != "" {
+				anomalies = append(anomalies, consistencyAnomaly{
+					id:    must.NotFail(doc.Get("_id")),
+					index: index.Name,
+					msg:   msg,
+				})
+			}
+		}
+	}
+
+	return anomalies, nil
+}
+
+// checkIndexEntry checks that the document could be found by the index key,
+// and that no other document has the same key for the unique index.
+//
+// It returns a description of found problem, or an empty string.
+// Documents with missing or non-scalar key values are not checked.
+func checkIndexEntry(ctx context.Context, c backends.Collection, index *backends.IndexInfo, doc *types.Document) (string, error) {
+	filter := types.MakeDocument(len(index.Key))
+
+	for _, k := range index.Key {
+		path, err := types.NewPathFromString(k.Field)
+		if err != nil {
+			return "", lazyerrors.Error(err)
+		}
+
+		v, err := doc.GetByPath(path)
+		if err != nil {
+			return "", nil
+		}
+
+		switch v.(type) {
+		case *types.Document, *types.Array, types.Regex:
+			return "", nil
+		}
+
+		filter.Set(k.Field, v)
+	}
+
+	var partial *types.Document
+
+	if index.Options != nil {
+		v, _ := index.Options.Get("partialFilterExpression")
+		partial, _ = v.(*types.Document)
+	}
+
+	if partial != nil {
+		matches, err := common.FilterDocument(doc, partial)
+		if err != nil {
+			return "", lazyerrors.Error(err)
+		}
+
+		if !matches {
+			return "", nil
+		}
+	}
+
+	queryRes, err := c.Query(ctx, &backends.QueryParams{Filter: filter})
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	closer := iterator.NewMultiCloser(queryRes.Iter)
+	defer closer.Close()
+
+	iter := common.FilterIterator(queryRes.Iter, closer, filter)
+
+	id := must.NotFail(doc.Get("_id"))
+
+	var found bool
+	var n int
+
+	for {
+		_, other, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return "", lazyerrors.Error(err)
+		}
+
+		if partial != nil {
+			var matches bool
+			if matches, err = common.FilterDocument(other, partial); err != nil {
+				return "", lazyerrors.Error(err)
+			}
+
+			if !matches {
+				continue
+			}
+		}
+
+		n++
+
+		if types.Compare(must.NotFail(other.Get("_id")), id) == types.Equal {
+			found = true
+		}
+	}
+
+	switch {
+	case !found:
+		return fmt.Sprintf("Document is not found by index key %s", types.FormatAnyValue(filter)), nil
+	case index.Unique && n > 1:
+		return fmt.Sprintf("%d documents have the same unique index key %s", n, types.FormatAnyValue(filter)), nil
+	default:
+		return "", nil
+	}
+}
+
+// reportAnomalies logs found anomalies and stores them in the health log collection.
+func (h *Handler) reportAnomalies(ctx context.Context, dbName, cName string, anomalies []consistencyAnomaly) error {
+	if len(anomalies) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]*types.Document, len(anomalies))
+
+	for i, a := range anomalies {
+		data := types.MakeDocument(2)
+
+		attrs := []slog.Attr{
+			slog.String("db", dbName),
+			slog.String("collection", cName),
+		}
+
+		if a.id != nil {
+			data.Set("_id", a.id)
+			attrs = append(attrs, slog.String("id", types.FormatAnyValue(a.id)))
+		}
+
+		if a.index != "" {
+			data.Set("index", a.index)
+			attrs = append(attrs, slog.String("index", a.index))
+		}
+
+		h.L.LogAttrs(ctx, slog.LevelWarn, "Consistency check: "+a.msg, attrs...)
+
+		docs[i] = must.NotFail(types.NewDocument(
+			"_id", types.NewObjectID(),
+			"timestamp", now,
+			"severity", "error",
+			"operation", "consistencyCheck",
+			"namespace", dbName+"."+cName,
+			"msg", a.msg,
+			"data", data,
+		))
+	}
+
+	db, err := h.b.Database(healthLogDatabase)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(healthLogCollection)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: docs}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestCheckCollectionConsistency(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	h := newTestHandler(t, new(NewOpts))
+
+	db, err := h.b.Database(testutil.DatabaseName(t))
+	require.NoError(t, err)
+
+	c, err := db.Collection(testutil.CollectionName(t))
+	require.NoError(t, err)
+
+	_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", "a")),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", "b")),
+		must.NotFail(types.NewDocument("_id", int32(3), "v", must.NotFail(types.NewArray("c", "d")))),
+	}})
+	require.NoError(t, err)
+
+	_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: []backends.IndexInfo{{
+		Name:   "v_1",
+		Key:    []backends.IndexKeyPair{{Field: "v"}},
+		Unique: true,
+	}}})
+	require.NoError(t, err)
+
+	t.Run("Valid", func(t *testing.T) {
+		for _, sampleSize := range []int{1, 100} {
+			anomalies, err := checkCollectionConsistency(ctx, c, sampleSize)
+			require.NoError(t, err)
+			assert.Empty(t, anomalies)
+		}
+	})
+
+	t.Run("InvalidDocument", func(t *testing.T) {
+		_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+			must.NotFail(types.NewDocument("_id", int32(4), "$v", "e")),
+		}})
+		require.NoError(t, err)
+
+		anomalies, err := checkCollectionConsistency(ctx, c, 100)
+		require.NoError(t, err)
+
+		expected := []consistencyAnomaly{{
+			id:  int32(4),
+			msg: `Invalid document: invalid key: "$v" (key must not start with '$' sign)`,
+		}}
+		assert.Equal(t, expected, anomalies)
+
+		require.NoError(t, h.reportAnomalies(ctx, "db", "coll", anomalies))
+
+		healthLog, err := h.b.Database(healthLogDatabase)
+		require.NoError(t, err)
+
+		hc, err := healthLog.Collection(healthLogCollection)
+		require.NoError(t, err)
+
+		res, err := hc.Query(ctx, nil)
+		require.NoError(t, err)
+
+		docs, err := iterator.ConsumeValues(res.Iter)
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+
+		assert.Equal(t, "db.coll", must.NotFail(docs[0].Get("namespace")))
+		assert.Equal(t, expected[0].msg, must.NotFail(docs[0].Get("msg")))
+		assert.Equal(t, int32(4), must.NotFail(must.NotFail(docs[0].Get("data")).(*types.Document).Get("_id")))
+	})
+}
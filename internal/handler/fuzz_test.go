@@ -0,0 +1,366 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// Database and collection used by fuzz tests.
+const (
+	fuzzDB         = "fuzz"
+	fuzzCollection = "fuzz"
+)
+
+// fuzzCommands contains commands that FuzzCommand passes to the handler.
+//
+// Only commands that work with data are fuzzed.
+// Commands that could block (like getMore with awaitData), affect the whole handler (like setParameter),
+// or use external resources (like createBackup) are excluded.
+var fuzzCommands = map[string]struct{}{
+	"aggregate":        {},
+	"collMod":          {},
+	"collStats":        {},
+	"count":            {},
+	"create":           {},
+	"createIndexes":    {},
+	"dataSize":         {},
+	"dbHash":           {},
+	"dbStats":          {},
+	"delete":           {},
+	"distinct":         {},
+	"drop":             {},
+	"dropDatabase":     {},
+	"dropIndexes":      {},
+	"explain":          {},
+	"find":             {},
+	"findAndModify":    {},
+	"insert":           {},
+	"listCollections":  {},
+	"listIndexes":      {},
+	"renameCollection": {},
+	"update":           {},
+	"validate":         {},
+}
+
+// setupFuzz returns a handler with the memory backend and a collection with a few documents.
+func setupFuzz(f *testing.F) (context.Context, *Handler) {
+	f.Helper()
+
+	return setupFuzzHandler(f, &NewOpts{
+		L: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+}
+
+// setupFuzzHandler returns a handler with the given options and a collection with a few documents.
+func setupFuzzHandler(f *testing.F, opts *NewOpts) (context.Context, *Handler) {
+	f.Helper()
+
+	ctx := conninfo.Ctx(context.Background(), conninfo.New())
+
+	h := newTestHandler(f, opts)
+
+	_, err := h.MsgInsert(ctx, wire.MustOpMsg(
+		"insert", fuzzCollection,
+		"documents", wirebson.MustArray(
+			wirebson.MustDocument("_id", int32(1), "v", int32(42), "a", wirebson.MustArray("foo", 1.5)),
+			wirebson.MustDocument("_id", "string", "v", "foo", "d", wirebson.MustDocument("v", int64(1))),
+			wirebson.MustDocument("_id", 3.14, "v", wirebson.Null),
+		),
+		"$db", fuzzDB,
+	))
+	require.NoError(f, err)
+
+	return ctx, h
+}
+
+// fuzzHandle passes the command to the handler.
+//
+// It fails if the handler returns an internal error or an error with undocumented code.
+// Panics are detected by the fuzzing engine itself.
+func fuzzHandle(t *testing.T, ctx context.Context, h *Handler, command *wirebson.Document) {
+	t.Helper()
+
+	name := command.Command()
+
+	cmd := h.Commands()[name]
+	if cmd == nil {
+		return
+	}
+
+	msg, err := wire.NewOpMsg(command)
+	if err != nil {
+		return
+	}
+
+	if _, err = cmd.Handler(ctx, msg); err == nil {
+		return
+	}
+
+	var writeErr *handlererrors.WriteErrors
+	if errors.As(err, &writeErr) {
+		writeErrors, _ := writeErr.Document().Get("writeErrors").(*wirebson.Array)
+		require.NotNil(t, writeErrors)
+
+		for i := range writeErrors.Len() {
+			we := writeErrors.Get(i).(*wirebson.Document)
+			checkErrorCode(t, name, handlererrors.ErrorCode(we.Get("code").(int32)), err)
+		}
+
+		return
+	}
+
+	var cmdErr *handlererrors.CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("%s: unexpected non-protocol error: %v", name, err)
+	}
+
+	checkErrorCode(t, name, cmdErr.Code(), err)
+}
+
+// checkErrorCode fails if the given error code is the internal error or undocumented.
+func checkErrorCode(t *testing.T, name string, code handlererrors.ErrorCode, err error) {
+	t.Helper()
+
+	// 1 is InternalError that is used for non-protocol errors
+	if code == handlererrors.ErrorCode(1) || strings.HasPrefix(code.String(), "ErrorCode(") {
+		t.Fatalf("%s: unexpected error code %d (%s): %v", name, code, code, err)
+	}
+}
+
+// addSeed adds a BSON document to the seed corpus.
+func addSeed(f *testing.F, pairs ...any) {
+	f.Helper()
+
+	f.Add([]byte(must.NotFail(wirebson.MustDocument(pairs...).Encode())))
+}
+
+// decodeFuzz decodes fuzzed BSON document, returning nil if it is not valid.
+func decodeFuzz(b []byte) *wirebson.Document {
+	doc, err := wirebson.RawDocument(b).DecodeDeep()
+	if err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+func FuzzCommand(f *testing.F) {
+	addSeed(f, "find", fuzzCollection, "filter", wirebson.MustDocument("v", int32(42)), "$db", fuzzDB)
+	addSeed(f, "count", fuzzCollection, "query", wirebson.MustDocument("v", wirebson.MustDocument("$gt", int32(1))), "$db", fuzzDB)
+	addSeed(f, "distinct", fuzzCollection, "key", "v", "$db", fuzzDB)
+	addSeed(f, "insert", fuzzCollection, "documents", wirebson.MustArray(wirebson.MustDocument("v", "bar")), "$db", fuzzDB)
+	addSeed(f, "listIndexes", fuzzCollection, "$db", fuzzDB)
+	addSeed(f, "createIndexes", fuzzCollection, "indexes", wirebson.MustArray(
+		wirebson.MustDocument("key", wirebson.MustDocument("v", int32(1)), "name", "v_1"),
+	), "$db", fuzzDB)
+	addSeed(f, "aggregate", fuzzCollection, "pipeline", wirebson.MustArray(
+		wirebson.MustDocument("$group", wirebson.MustDocument("_id", "$v", "n", wirebson.MustDocument("$sum", int32(1)))),
+	), "cursor", wirebson.MustDocument(), "$db", fuzzDB)
+
+	ctx, h := setupFuzz(f)
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		command := decodeFuzz(b)
+		if command == nil || command.Len() == 0 {
+			return
+		}
+
+		if _, ok := fuzzCommands[command.Command()]; !ok {
+			return
+		}
+
+		fuzzHandle(t, ctx, h, command)
+	})
+}
+
+func FuzzFilter(f *testing.F) {
+	addSeed(f, "v", int32(42))
+	addSeed(f, "v", wirebson.MustDocument("$gt", int32(1), "$lt", "z"))
+	addSeed(f, "a", wirebson.MustDocument("$elemMatch", wirebson.MustDocument("$eq", 1.5)))
+	addSeed(f, "$or", wirebson.MustArray(wirebson.MustDocument("v", "foo"), wirebson.MustDocument("d.v", int64(1))))
+	addSeed(f, "$expr", wirebson.MustDocument("$gt", wirebson.MustArray("$v", int32(1))))
+
+	ctx, h := setupFuzz(f)
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		filter := decodeFuzz(b)
+		if filter == nil {
+			return
+		}
+
+		fuzzHandle(t, ctx, h, wirebson.MustDocument("find", fuzzCollection, "filter", filter, "$db", fuzzDB))
+		fuzzHandle(t, ctx, h, wirebson.MustDocument("count", fuzzCollection, "query", filter, "$db", fuzzDB))
+		fuzzHandle(t, ctx, h, wirebson.MustDocument(
+			"aggregate", fuzzCollection,
+			"pipeline", wirebson.MustArray(wirebson.MustDocument("$match", filter)),
+			"cursor", wirebson.MustDocument(),
+			"$db", fuzzDB,
+		))
+	})
+}
+
+func FuzzUpdate(f *testing.F) {
+	addSeed(f, "$set", wirebson.MustDocument("v", int32(43)))
+	addSeed(f, "$inc", wirebson.MustDocument("v", int32(1)))
+	addSeed(f, "$unset", wirebson.MustDocument("d.v", ""))
+	addSeed(f, "$push", wirebson.MustDocument("a", "bar"))
+	addSeed(f, "$rename", wirebson.MustDocument("v", "w"))
+	addSeed(f, "v", "replacement")
+
+	ctx, h := setupFuzz(f)
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		update := decodeFuzz(b)
+		if update == nil {
+			return
+		}
+
+		fuzzHandle(t, ctx, h, wirebson.MustDocument(
+			"update", fuzzCollection,
+			"updates", wirebson.MustArray(wirebson.MustDocument(
+				"q", wirebson.MustDocument(),
+				"u", update,
+				"multi", update.Len() > 0 && strings.HasPrefix(update.FieldNames()[0], "$"),
+			)),
+			"$db", fuzzDB,
+		))
+	})
+}
+
+// FuzzHostileData passes hostile field names and values through the handler to the SQLite backend
+// and checks that they are stored and queried as data.
+//
+// Queries are checked by sqlguard only in debug builds, so run it with `-race` or `-tags=ferretdb_debug`.
+func FuzzHostileData(f *testing.F) {
+	for _, s := range []string{
+		"v",
+		"'",
+		`"`,
+		"`",
+		"?",
+		"?1",
+		"$1",
+		"$$",
+		"--",
+		"#",
+		"/*",
+		"*/",
+		";",
+		"\\",
+		"x'); DROP TABLE test; --",
+		`x"; DROP TABLE test; --`,
+		"' OR '1'='1",
+		"$.v",
+		"\x00",
+	} {
+		f.Add(s, s)
+	}
+
+	l := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	sp, err := state.NewProvider("")
+	require.NoError(f, err)
+
+	b, err := sqlite.NewBackend(&sqlite.NewBackendParams{
+		URI:       testutil.TestSQLiteURI(f, ""),
+		L:         l,
+		P:         sp,
+		BatchSize: 100,
+	})
+	require.NoError(f, err)
+
+	f.Cleanup(b.Close)
+
+	ctx, h := setupFuzzHandler(f, &NewOpts{
+		Backend:       b,
+		L:             l,
+		StateProvider: sp,
+	})
+
+	f.Fuzz(func(t *testing.T, key, value string) {
+		// dots, leading dollars, and _id have special meaning; paths can't have leading or trailing spaces;
+		// NUL can't be encoded in BSON keys
+		if !utf8.ValidString(key) || !utf8.ValidString(value) || key == "" || key == "_id" ||
+			strings.ContainsAny(key, ".\x00") || strings.HasPrefix(key, "$") || strings.TrimSpace(key) != key {
+			return
+		}
+
+		doc := wirebson.MustDocument("_id", value, key, value)
+
+		_, err := h.MsgInsert(ctx, wire.MustOpMsg(
+			"insert", fuzzCollection,
+			"documents", wirebson.MustArray(doc),
+			"$db", fuzzDB,
+		))
+		if err != nil {
+			// for example, value is too large
+			var cmdErr *handlererrors.CommandError
+			require.ErrorAs(t, err, &cmdErr)
+
+			return
+		}
+
+		t.Cleanup(func() {
+			_, err = h.MsgDelete(ctx, wire.MustOpMsg(
+				"delete", fuzzCollection,
+				"deletes", wirebson.MustArray(wirebson.MustDocument("q", wirebson.MustDocument("_id", value), "limit", int32(1))),
+				"$db", fuzzDB,
+			))
+			require.NoError(t, err)
+		})
+
+		fuzzHandle(t, ctx, h, wirebson.MustDocument(
+			"createIndexes", fuzzCollection,
+			"indexes", wirebson.MustArray(wirebson.MustDocument("key", wirebson.MustDocument(key, int32(1)), "name", "fuzz")),
+			"$db", fuzzDB,
+		))
+
+		res, err := h.MsgFind(ctx, wire.MustOpMsg(
+			"find", fuzzCollection,
+			"filter", wirebson.MustDocument(key, value),
+			"sort", wirebson.MustDocument(key, int32(1)),
+			"$db", fuzzDB,
+		))
+		require.NoError(t, err)
+
+		resDoc := must.NotFail(res.RawSection0().DecodeDeep())
+		firstBatch := resDoc.Get("cursor").(*wirebson.Document).Get("firstBatch").(*wirebson.Array)
+		require.Equal(t, 1, firstBatch.Len())
+
+		actual := firstBatch.Get(0).(*wirebson.Document)
+		require.Equal(t, value, actual.Get("_id"))
+		require.Equal(t, value, actual.Get(key))
+
+		fuzzHandle(t, ctx, h, wirebson.MustDocument("dropIndexes", fuzzCollection, "index", "fuzz", "$db", fuzzDB))
+	})
+}
@@ -20,17 +20,25 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/FerretDB/wire"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/decorators/oplog"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/timeout"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
+	"github.com/FerretDB/FerretDB/internal/clientconn/resultcache"
+	"github.com/FerretDB/FerretDB/internal/clientconn/session"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/migrations"
 	"github.com/FerretDB/FerretDB/internal/handler/users"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
@@ -38,6 +46,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/objectstore"
 	"github.com/FerretDB/FerretDB/internal/util/password"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
@@ -53,8 +62,17 @@ const (
 	// Required by C# driver for `IsMaster` and `hello` op reply, without it `DPANIC` is thrown.
 	connectionID = int32(42)
 
-	// Default session timeout in minutes.
-	logicalSessionTimeoutMinutes = int32(30)
+	// Default logical session timeout.
+	defaultSessionTimeout = 30 * time.Minute
+
+	// Default interval of expired logical sessions cleanup.
+	defaultSessionCleanupInterval = 5 * time.Minute
+
+	// Default maximal delay after failed authentication attempts.
+	defaultAuthFailureMaxDelay = 10 * time.Second
+
+	// Default duration of authentication lockout.
+	defaultAuthLockoutDuration = 15 * time.Minute
 )
 
 // Handler provides a set of methods to process clients' requests sent over wire protocol.
@@ -68,13 +86,53 @@ type Handler struct {
 
 	b backends.Backend
 
+	// nil if ExportURL is not set
+	exportStore objectstore.Store
+
+	// nil if BackupURL is not set
+	backupStore objectstore.Store
+
 	cursors  *cursor.Registry
+	sessions *session.Registry
 	commands map[string]*command
 	wg       sync.WaitGroup
 
+	// interceptors registered with Use, and built-in ones called after them
+	interceptorsM       sync.RWMutex
+	interceptors        []Interceptor
+	builtinInterceptors []Interceptor
+
+	// serializes user creation with the localhost exception
+	localhostExceptionM sync.Mutex
+
+	sessionCleanupInterval atomic.Int64 // time.Duration
+	sessionsCleanupStop    chan struct{}
+
+	readOnly atomic.Bool
+
+	scramIterationCount       atomic.Int32
+	scramSHA256IterationCount atomic.Int32
+	passwordMinLength         atomic.Int32
+	passwordRequireComplexity atomic.Bool
+
+	shuttingDown atomic.Bool
+	warmedUp     atomic.Bool
+	warmupCancel context.CancelFunc
+
+	operations *operations
+
+	// audit events logger
+	auditL      *slog.Logger
+	authLockout *authLockout
+
+	// nil if ResultCacheSize is zero
+	resultCache *resultcache.Cache
+
 	cappedCleanupStop             chan struct{}
 	cleanupCappedCollectionsDocs  *prometheus.CounterVec
 	cleanupCappedCollectionsBytes *prometheus.CounterVec
+
+	consistencyCheckStop chan struct{}
 }
 
 // NewOpts represents handler configuration.
@@ -85,11 +143,115 @@ type NewOpts struct {
 	TCPHost     string
 	ReplSetName string
 
+	// ExportURL is a base URL for exportCollection and importCollection commands;
+	// see [objectstore.New].
+	ExportURL string
+
+	// BackupURL is a base URL for createBackup and restoreBackup commands.
+	// Other Backup fields set S3 server-side encryption of backups; see [objectstore.Opts].
+	BackupURL                  string
+	BackupServerSideEncryption string
+	BackupKMSKeyID             string
+
+	// SessionTimeout is the logical session timeout in whole minutes.
+	// SessionCleanupInterval is the interval of expired sessions cleanup.
+	// Defaults are used if they are zero; both can be changed with `setParameter` command.
+	SessionTimeout         time.Duration
+	SessionCleanupInterval time.Duration
+
+	// Setup fields create initial database and user on the first start.
+	// If SetupDatabase is empty, SetupUsername is created in the admin database
+	// with readWriteAnyDatabase role, but only if there are no users yet.
 	SetupDatabase string
 	SetupUsername string
 	SetupPassword password.Password
 	SetupTimeout  time.Duration
 
+	// SCRAMIterationCount and SCRAMSHA256IterationCount are used for new SCRAM-SHA-1 and SCRAM-SHA-256 credentials.
+	// Defaults are used if they are zero.
+	// PasswordMinLength and PasswordRequireComplexity define password policy
+	// checked by `createUser` and `updateUser` commands.
+	// All of them can be changed with `setParameter` command.
+	SCRAMIterationCount       int
+	SCRAMSHA256IterationCount int
+	PasswordMinLength         int
+	PasswordRequireComplexity bool
+
+	// AuthFailureDelay is the delay after the first failed authentication attempt of the user from the client address;
+	// it doubles after each next failure up to AuthFailureMaxDelay. Zero value disables delays.
+	// AuthLockoutThreshold is the number of consecutive failures after which further attempts
+	// are rejected for AuthLockoutDuration. Zero value disables lockouts.
+	// Defaults are used if AuthFailureMaxDelay or AuthLockoutDuration are zero.
+	AuthFailureDelay     time.Duration
+	AuthFailureMaxDelay  time.Duration
+	AuthLockoutThreshold int
+	AuthLockoutDuration  time.Duration
+
+	// DisableAutoMigrate disables applying metadata migrations on startup;
+	// pending migrations are only logged.
+	DisableAutoMigrate bool
+
+	// ReadOnly makes write commands fail with NotWritablePrimary error.
+	// It can be changed with `setParameter` command.
+	ReadOnly bool
+
+	// QuotaMaxCollections limits the number of collections in each database.
+	// QuotaMaxDocuments and QuotaMaxSize limit the number of documents and the size in bytes of each collection.
+	// Zero values disable limits.
+	QuotaMaxCollections int
+	QuotaMaxDocuments   int64
+	QuotaMaxSize        int64
+
+	// MaxBsonObjectSizeBytes is the maximum document size advertised to clients and checked on inserts.
+	// The default is used if it is zero.
+	MaxBsonObjectSizeBytes int
+
+	// ResultCacheSize is the maximum size of read commands results cache in bytes.
+	// Zero value disables the cache.
+	// It should not be enabled if the backend is shared with other FerretDB instances
+	// or changed directly, as such changes do not invalidate cached results.
+	ResultCacheSize int64
+
+	// OperationMemoryLimit is the maximum approximate memory in bytes used by in-memory sorting and grouping
+	// of a single operation without `allowDiskUse` option. Zero value disables the limit.
+	OperationMemoryLimit int64
+
+	// CursorReadaheadSize is the maximum total size in bytes of documents
+	// prefetched by normal cursors in the background between `getMore` commands.
+	// Zero value disables readahead.
+	CursorReadaheadSize int64
+
+	// SlowQueryThreshold is the duration after which finished commands are logged as slow queries.
+	// Zero value disables logging.
+	// If SlowQueryExplain is true, the backend's execution plan of slow `find` and `aggregate` commands
+	// is added to the log entry.
+	SlowQueryThreshold time.Duration
+	SlowQueryExplain   bool
+
+	// BackendTimeout limits the duration of each backend call; zero value disables it.
+	// BackendHedgeReads enables hedged read queries; see [timeout.Opts].
+	BackendTimeout    time.Duration
+	BackendHedgeReads bool
+
+	// ConsistencyCheckInterval is the interval of background consistency check; zero value disables it.
+	// ConsistencyCheckSampleSize is the number of documents per collection checked against indexes.
+	ConsistencyCheckInterval   time.Duration
+	ConsistencyCheckSampleSize int
+
+	// WarmupTimeout limits the startup warm-up that prepares the backend before
+	// [Handler.WarmedUp] returns true; zero value disables it.
+	// WarmupConnections is the number of backend connections established during warm-up.
+	WarmupTimeout     time.Duration
+	WarmupConnections int
+
+	// Shutdown is called by `shutdown` command to stop FerretDB.
+	// If it is nil, that command returns an error.
+	Shutdown func()
+
+	// TenantPrefixes maps usernames to database name prefixes of their tenants.
+	// Empty map disables tenant isolation.
+	TenantPrefixes map[string]string
+
 	L             *slog.Logger
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
@@ -97,11 +259,13 @@ type NewOpts struct {
 	// test options
 	DisablePushdown         bool
 	EnableNestedPushdown    bool
+	EnableSortPushdown      bool
+	EnableExplainSQL        bool
 	CappedCleanupInterval   time.Duration
 	CappedCleanupPercentage uint8
 	EnableNewAuth           bool
 	BatchSize               int
-	MaxBsonObjectSizeBytes  int
+	RandSeed                uint64
 }
 
 // New returns a new handler.
@@ -117,16 +281,174 @@ func New(opts *NewOpts) (*Handler, error) {
 		)
 	}
 
+	if opts.ConsistencyCheckSampleSize == 0 {
+		opts.ConsistencyCheckSampleSize = 100
+	}
+
+	if opts.ConsistencyCheckSampleSize < 0 {
+		return nil, fmt.Errorf(
+			"consistency check sample size must be positive, but %d given",
+			opts.ConsistencyCheckSampleSize,
+		)
+	}
+
+	if opts.WarmupConnections == 0 {
+		opts.WarmupConnections = defaultWarmupConnections
+	}
+
+	if opts.WarmupConnections < 0 {
+		return nil, fmt.Errorf(
+			"number of warm-up connections must be positive, but %d given",
+			opts.WarmupConnections,
+		)
+	}
+
+	if opts.RandSeed != 0 {
+		operators.SeedRand(opts.RandSeed)
+	}
+
+	if opts.OperationMemoryLimit < 0 {
+		return nil, fmt.Errorf(
+			"operation memory limit must be positive, but %d given",
+			opts.OperationMemoryLimit,
+		)
+	}
+
+	if opts.CursorReadaheadSize < 0 {
+		return nil, fmt.Errorf(
+			"cursor readahead size must be positive, but %d given",
+			opts.CursorReadaheadSize,
+		)
+	}
+
 	if opts.MaxBsonObjectSizeBytes == 0 {
 		opts.MaxBsonObjectSizeBytes = types.MaxDocumentLen
 	}
 
-	b := oplog.NewBackend(opts.Backend, logging.WithName(opts.L, "oplog"))
+	if opts.MaxBsonObjectSizeBytes < 0 || opts.MaxBsonObjectSizeBytes > wire.MaxMsgLen {
+		return nil, fmt.Errorf(
+			"maximum document size must be in range (0, %d], but %d given",
+			wire.MaxMsgLen, opts.MaxBsonObjectSizeBytes,
+		)
+	}
+
+	if opts.SCRAMIterationCount == 0 {
+		opts.SCRAMIterationCount = password.DefaultSCRAMSHA1IterationCount
+	}
+
+	if opts.SCRAMSHA256IterationCount == 0 {
+		opts.SCRAMSHA256IterationCount = password.DefaultSCRAMSHA256IterationCount
+	}
+
+	for _, count := range []int{opts.SCRAMIterationCount, opts.SCRAMSHA256IterationCount} {
+		if count < password.MinSCRAMIterationCount || count > math.MaxInt32 {
+			return nil, fmt.Errorf(
+				"SCRAM iteration count must be in range [%d, %d], but %d given",
+				password.MinSCRAMIterationCount, math.MaxInt32, count,
+			)
+		}
+	}
+
+	if opts.PasswordMinLength < 0 || opts.PasswordMinLength > math.MaxInt32 {
+		return nil, fmt.Errorf(
+			"minimal password length must be positive, but %d given",
+			opts.PasswordMinLength,
+		)
+	}
+
+	if opts.AuthFailureMaxDelay == 0 {
+		opts.AuthFailureMaxDelay = defaultAuthFailureMaxDelay
+	}
+
+	if opts.AuthLockoutDuration == 0 {
+		opts.AuthLockoutDuration = defaultAuthLockoutDuration
+	}
+
+	if opts.AuthFailureDelay < 0 || opts.AuthFailureDelay > opts.AuthFailureMaxDelay {
+		return nil, fmt.Errorf(
+			"authentication failure delay must be in range [0, %s], but %s given",
+			opts.AuthFailureMaxDelay, opts.AuthFailureDelay,
+		)
+	}
+
+	if opts.AuthLockoutThreshold < 0 || opts.AuthLockoutDuration < 0 {
+		return nil, fmt.Errorf(
+			"authentication lockout threshold and duration must be positive, but %d and %s given",
+			opts.AuthLockoutThreshold, opts.AuthLockoutDuration,
+		)
+	}
+
+	if opts.BackendTimeout < 0 {
+		return nil, fmt.Errorf("backend timeout must be positive, but %s given", opts.BackendTimeout)
+	}
+
+	var exportStore, backupStore objectstore.Store
+
+	if opts.ExportURL != "" {
+		var err error
+		if exportStore, err = objectstore.New(opts.ExportURL, nil); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if opts.BackupURL != "" {
+		var err error
+		backupStore, err = objectstore.New(opts.BackupURL, &objectstore.Opts{
+			ServerSideEncryption: opts.BackupServerSideEncryption,
+			KMSKeyID:             opts.BackupKMSKeyID,
+		})
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	sessionTimeout := opts.SessionTimeout
+	if sessionTimeout == 0 {
+		sessionTimeout = defaultSessionTimeout
+	}
+
+	if sessionTimeout < time.Minute || sessionTimeout%time.Minute != 0 {
+		return nil, lazyerrors.Errorf("session timeout should be a positive whole number of minutes, got %s", sessionTimeout)
+	}
+
+	sessionCleanupInterval := opts.SessionCleanupInterval
+	if sessionCleanupInterval == 0 {
+		sessionCleanupInterval = defaultSessionCleanupInterval
+	}
+
+	if sessionCleanupInterval < 0 {
+		return nil, lazyerrors.Errorf("session cleanup interval should be positive, got %s", sessionCleanupInterval)
+	}
+
+	if err := validateTenantPrefixes(opts.TenantPrefixes); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	b := timeout.NewBackend(opts.Backend, &timeout.Opts{
+		Timeout:    opts.BackendTimeout,
+		HedgeReads: opts.BackendHedgeReads,
+	})
+	b = oplog.NewBackend(b, logging.WithName(opts.L, "oplog"))
 
 	h := &Handler{
-		b:       b,
-		NewOpts: opts,
-		cursors: cursor.NewRegistry(logging.WithName(opts.L, "cursors")),
+		b:           b,
+		NewOpts:     opts,
+		exportStore: exportStore,
+		backupStore: backupStore,
+		cursors:     cursor.NewRegistry(logging.WithName(opts.L, "cursors"), opts.CursorReadaheadSize),
+		sessions:    session.NewRegistry(sessionTimeout, logging.WithName(opts.L, "sessions")),
+		operations:  newOperations(),
+
+		auditL: logging.WithName(opts.L, "audit"),
+		authLockout: newAuthLockout(&authLockoutOpts{
+			Delay:     opts.AuthFailureDelay,
+			MaxDelay:  opts.AuthFailureMaxDelay,
+			Threshold: opts.AuthLockoutThreshold,
+			Duration:  opts.AuthLockoutDuration,
+		}),
+
+		sessionsCleanupStop:  make(chan struct{}),
+		consistencyCheckStop: make(chan struct{}),
 
 		cappedCleanupStop: make(chan struct{}),
 		cleanupCappedCollectionsDocs: prometheus.NewCounterVec(
@@ -149,11 +471,28 @@ func New(opts *NewOpts) (*Handler, error) {
 		),
 	}
 
+	if opts.ResultCacheSize > 0 {
+		h.resultCache = resultcache.New(opts.ResultCacheSize)
+	}
+
+	h.sessionCleanupInterval.Store(int64(sessionCleanupInterval))
+	h.readOnly.Store(opts.ReadOnly)
+	h.scramIterationCount.Store(int32(opts.SCRAMIterationCount))
+	h.scramSHA256IterationCount.Store(int32(opts.SCRAMSHA256IterationCount))
+	h.passwordMinLength.Store(int32(opts.PasswordMinLength))
+	h.passwordRequireComplexity.Store(opts.PasswordRequireComplexity)
+
 	if err := h.setup(); err != nil {
 		h.Close()
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err := h.migrate(); err != nil {
+		h.Close()
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.initBuiltinInterceptors()
 	h.initCommands()
 
 	h.wg.Add(1)
@@ -164,12 +503,39 @@ func New(opts *NewOpts) (*Handler, error) {
 		h.runCappedCleanup()
 	}()
 
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+
+		h.runSessionsCleanup()
+	}()
+
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+
+		h.runConsistencyCheck()
+	}()
+
+	var warmupCtx context.Context
+	warmupCtx, h.warmupCancel = context.WithCancel(context.Background())
+
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+
+		h.runWarmup(warmupCtx)
+	}()
+
 	return h, nil
 }
 
-// Setup creates initial database and user if needed.
+// Setup creates initial database and user, or initial administrative user, if needed.
 func (h *Handler) setup() error {
-	if h.SetupDatabase == "" {
+	if h.SetupUsername == "" {
 		return nil
 	}
 
@@ -203,6 +569,10 @@ func (h *Handler) setup() error {
 		ctxutil.SleepWithJitter(ctx, time.Second, retry)
 	}
 
+	if h.SetupDatabase == "" {
+		return h.setupAdmin(ctx, l)
+	}
+
 	res, err := h.b.ListDatabases(ctx, &backends.ListDatabasesParams{Name: h.SetupDatabase})
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -238,6 +608,39 @@ func (h *Handler) setup() error {
 		Database: h.SetupDatabase,
 		Username: h.SetupUsername,
 		Password: h.SetupPassword,
+
+		IterationCounts: h.iterationCounts(),
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// setupAdmin creates initial administrative user with readWriteAnyDatabase role
+// in the admin database if there are no users yet.
+func (h *Handler) setupAdmin(ctx context.Context, l *slog.Logger) error {
+	exist, err := h.usersExist(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if exist {
+		l.DebugContext(ctx, "Users already exist")
+		return nil
+	}
+
+	l.InfoContext(ctx, "Setting up administrative user", slog.String("username", h.SetupUsername))
+
+	err = users.CreateUser(ctx, h.b, &users.CreateUserParams{
+		Database: "admin",
+		Username: h.SetupUsername,
+		Password: h.SetupPassword,
+		Roles: must.NotFail(types.NewArray(
+			must.NotFail(types.NewDocument("role", "readWriteAnyDatabase", "db", "admin")),
+		)),
+		IterationCounts: h.iterationCounts(),
 	})
 	if err != nil {
 		return lazyerrors.Error(err)
@@ -246,6 +649,58 @@ func (h *Handler) setup() error {
 	return nil
 }
 
+// isSetupAdmin returns true if the given user is the initial administrative user created by [Handler.setup].
+func (h *Handler) isSetupAdmin(username, db string) bool {
+	return h.SetupUsername != "" && h.SetupDatabase == "" && username == h.SetupUsername && db == "admin"
+}
+
+// migrate applies pending metadata migrations, or only logs them if auto-migration is disabled.
+//
+// Backend errors are logged, but do not prevent FerretDB from starting,
+// as the backend may be unavailable at that time.
+// A newer metadata version is an error.
+func (h *Handler) migrate() error {
+	ctx, span := otel.Tracer("").Start(context.TODO(), "HandlerMigrate")
+	defer span.End()
+
+	if h.SetupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.SetupTimeout)
+		defer cancel()
+	}
+
+	info := conninfo.New()
+	info.SetBypassBackendAuth()
+
+	ctx = conninfo.Ctx(ctx, info)
+
+	l := logging.WithName(h.L, "migrations")
+
+	res, err := migrations.Run(ctx, h.b, &migrations.RunParams{
+		DryRun: h.DisableAutoMigrate,
+		L:      l,
+	})
+
+	switch {
+	case errors.Is(err, migrations.ErrNewerVersion):
+		return err
+	case err != nil:
+		l.WarnContext(ctx, "Failed to migrate metadata", logging.Error(err))
+		return nil
+	}
+
+	if h.DisableAutoMigrate {
+		for _, m := range res {
+			l.WarnContext(
+				ctx, "Metadata migration is pending, but auto-migration is disabled",
+				slog.Int("version", int(m.Version)), slog.String("description", m.Description),
+			)
+		}
+	}
+
+	return nil
+}
+
 // runCappedCleanup calls capped collections cleanup function according to the given interval.
 func (h *Handler) runCappedCleanup() {
 	if h.CappedCleanupInterval <= 0 {
@@ -272,11 +727,68 @@ func (h *Handler) runCappedCleanup() {
 	}
 }
 
+// runSessionsCleanup periodically removes expired logical sessions and closes their cursors.
+//
+// A new interval set by `setParameter` takes effect after the current one passes.
+func (h *Handler) runSessionsCleanup() {
+	timer := time.NewTimer(time.Duration(h.sessionCleanupInterval.Load()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if ids := h.sessions.RemoveExpired(); len(ids) > 0 {
+				h.L.Debug("Expired sessions removed.", slog.Int("count", len(ids)))
+				h.endSessions(ids)
+			}
+
+			timer.Reset(time.Duration(h.sessionCleanupInterval.Load()))
+
+		case <-h.sessionsCleanupStop:
+			return
+		}
+	}
+}
+
+// logicalSessionTimeoutMinutes returns the current logical session timeout in minutes.
+func (h *Handler) logicalSessionTimeoutMinutes() int32 {
+	return int32(h.sessions.Timeout() / time.Minute)
+}
+
+// logicalSessionRefreshMillis returns the current interval of expired sessions cleanup in milliseconds.
+func (h *Handler) logicalSessionRefreshMillis() int32 {
+	return int32(time.Duration(h.sessionCleanupInterval.Load()) / time.Millisecond)
+}
+
+// iterationCounts returns the current iteration counts for new SCRAM credentials.
+func (h *Handler) iterationCounts() *users.IterationCounts {
+	return &users.IterationCounts{
+		SCRAMSHA1:   int(h.scramIterationCount.Load()),
+		SCRAMSHA256: int(h.scramSHA256IterationCount.Load()),
+	}
+}
+
+// passwordPolicy returns the current password policy.
+func (h *Handler) passwordPolicy() *password.Policy {
+	return &password.Policy{
+		MinLength:         int(h.passwordMinLength.Load()),
+		RequireComplexity: h.passwordRequireComplexity.Load(),
+	}
+}
+
 // Close gracefully shutdowns handler.
 // It should be called after listener closes all client connections and stops listening.
 func (h *Handler) Close() {
 	h.cursors.Close()
 	close(h.cappedCleanupStop)
+	close(h.sessionsCleanupStop)
+	close(h.consistencyCheckStop)
+
+	if h.warmupCancel != nil {
+		h.warmupCancel()
+	}
+
+	h.operations.killAll()
 	h.wg.Wait()
 }
 
@@ -284,16 +796,28 @@ func (h *Handler) Close() {
 func (h *Handler) Describe(ch chan<- *prometheus.Desc) {
 	h.b.Describe(ch)
 	h.cursors.Describe(ch)
+	h.sessions.Describe(ch)
 	h.cleanupCappedCollectionsDocs.Describe(ch)
 	h.cleanupCappedCollectionsBytes.Describe(ch)
+	h.authLockout.Describe(ch)
+
+	if h.resultCache != nil {
+		h.resultCache.Describe(ch)
+	}
 }
 
 // Collect implements [prometheus.Collector].
 func (h *Handler) Collect(ch chan<- prometheus.Metric) {
 	h.b.Collect(ch)
 	h.cursors.Collect(ch)
+	h.sessions.Collect(ch)
 	h.cleanupCappedCollectionsDocs.Collect(ch)
 	h.cleanupCappedCollectionsBytes.Collect(ch)
+	h.authLockout.Collect(ch)
+
+	if h.resultCache != nil {
+		h.resultCache.Collect(ch)
+	}
 }
 
 // cleanupAllCappedCollections drops the given percent of documents from all capped collections.
@@ -353,6 +877,10 @@ func (h *Handler) cleanupAllCappedCollections(ctx context.Context) error {
 				)
 			}
 
+			if deleted > 0 {
+				h.invalidateResultCache(dbInfo.Name)
+			}
+
 			h.cleanupCappedCollectionsDocs.WithLabelValues(dbInfo.Name, cInfo.Name).Add(float64(deleted))
 			h.cleanupCappedCollectionsBytes.WithLabelValues(dbInfo.Name, cInfo.Name).Add(float64(bytesFreed))
 		}
@@ -0,0 +1,134 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/password"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestSetupAdmin(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := memory.NewBackend(&memory.NewBackendParams{L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+
+	t.Cleanup(b.Close)
+
+	for _, username := range []string{"admin", "other"} {
+		newTestHandler(t, &NewOpts{
+			Backend:       b,
+			StateProvider: sp,
+			SetupUsername: username,
+			SetupPassword: password.WrapPassword("password"),
+		})
+	}
+
+	c := must.NotFail(must.NotFail(b.Database("admin")).Collection("system.users"))
+
+	res, err := c.Query(ctx, nil)
+	require.NoError(t, err)
+
+	docs, err := iterator.ConsumeValues(res.Iter)
+	require.NoError(t, err)
+
+	// the second user is not created because the first one already exists
+	require.Len(t, docs, 1)
+
+	doc := docs[0]
+	assert.Equal(t, "admin.admin", must.NotFail(doc.Get("_id")))
+	assert.Equal(t, "admin", must.NotFail(doc.Get("db")))
+
+	expected := must.NotFail(types.NewArray(must.NotFail(types.NewDocument("role", "readWriteAnyDatabase", "db", "admin"))))
+	testutil.AssertEqual(t, expected, must.NotFail(doc.Get("roles")).(*types.Array))
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	h := newTestHandler(t, &NewOpts{
+		EnableNewAuth:             true,
+		SCRAMSHA256IterationCount: 6_000,
+		PasswordMinLength:         8,
+	})
+
+	createUser := func(username, pwd string) error {
+		_, err := h.MsgCreateUser(ctx, wire.MustOpMsg(
+			"createUser", username,
+			"roles", wirebson.MustArray(),
+			"pwd", pwd,
+			"$db", "test",
+		))
+
+		return err
+	}
+
+	var cmdErr *handlererrors.CommandError
+	require.ErrorAs(t, createUser("short", "pass"), &cmdErr)
+	assert.Equal(t, handlererrors.ErrBadValue, cmdErr.Code())
+
+	require.NoError(t, createUser("long", "password"))
+
+	_, err := h.MsgSetParameter(ctx, wire.MustOpMsg(
+		"setParameter", int32(1),
+		"passwordRequireComplexity", true,
+		"$db", "admin",
+	))
+	require.NoError(t, err)
+
+	require.ErrorAs(t, createUser("simple", "password"), &cmdErr)
+	assert.Equal(t, handlererrors.ErrBadValue, cmdErr.Code())
+
+	require.NoError(t, createUser("complex", "Password1"))
+
+	c := must.NotFail(must.NotFail(h.b.Database("admin")).Collection("system.users"))
+
+	res, err := c.Query(ctx, nil)
+	require.NoError(t, err)
+
+	docs, err := iterator.ConsumeValues(res.Iter)
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	for _, doc := range docs {
+		credentials := must.NotFail(doc.Get("credentials")).(*types.Document)
+		sha1 := must.NotFail(credentials.Get("SCRAM-SHA-1")).(*types.Document)
+		sha256 := must.NotFail(credentials.Get("SCRAM-SHA-256")).(*types.Document)
+
+		assert.Equal(t, int32(password.DefaultSCRAMSHA1IterationCount), must.NotFail(sha1.Get("iterationCount")))
+		assert.Equal(t, int32(6_000), must.NotFail(sha256.Get("iterationCount")))
+	}
+}
@@ -47,6 +47,9 @@ const (
 	// ErrTypeMismatch for $sort indicates that the expression in the $sort is not an object.
 	ErrTypeMismatch = ErrorCode(14) // TypeMismatch
 
+	// ErrInvalidLength indicates that the number of elements is invalid.
+	ErrInvalidLength = ErrorCode(16) // InvalidLength
+
 	// ErrProtocolError indicates SASL handshake failed.
 	ErrProtocolError = ErrorCode(17) // ProtocolError
 
@@ -104,6 +107,9 @@ const (
 	// ErrInvalidNamespace indicates that the collection name is invalid.
 	ErrInvalidNamespace = ErrorCode(73) // InvalidNamespace
 
+	// ErrNoReplicationEnabled indicates that the command requires a replica set.
+	ErrNoReplicationEnabled = ErrorCode(76) // NoReplicationEnabled
+
 	// ErrIndexOptionsConflict indicates that index build process failed due to options conflict.
 	ErrIndexOptionsConflict = ErrorCode(85) // IndexOptionsConflict
 
@@ -113,9 +119,15 @@ const (
 	// ErrOperationFailed indicates that the operation failed.
 	ErrOperationFailed = ErrorCode(96) // OperationFailed
 
+	// ErrWriteConflict indicates that the document was modified concurrently.
+	ErrWriteConflict = ErrorCode(112) // WriteConflict
+
 	// ErrDocumentValidationFailure indicates that document validation failed.
 	ErrDocumentValidationFailure = ErrorCode(121) // DocumentValidationFailure
 
+	// ErrExceededMemoryLimit indicates that an operation exceeded the memory limit.
+	ErrExceededMemoryLimit = ErrorCode(146) // ExceededMemoryLimit
+
 	// ErrInvalidIndexSpecificationOption indicates that the index option is invalid.
 	ErrInvalidIndexSpecificationOption = ErrorCode(197) // InvalidIndexSpecificationOption
 
@@ -125,9 +137,22 @@ const (
 	// ErrClientMetadataCannotBeMutated indicates that client metadata cannot be mutated.
 	ErrClientMetadataCannotBeMutated = ErrorCode(186) // ClientMetadataCannotBeMutated
 
+	// ErrStaleClusterTime indicates that the requested cluster time is not after the current one.
+	ErrStaleClusterTime = ErrorCode(209) // StaleClusterTime
+
 	// ErrNotImplemented indicates that a flag or command is not implemented.
 	ErrNotImplemented = ErrorCode(238) // NotImplemented
 
+	// ErrConversionFailure indicates that a value cannot be converted to the requested type.
+	ErrConversionFailure = ErrorCode(241) // ConversionFailure
+
+	// ErrIndexBuildAborted indicates that the index build was aborted.
+	ErrIndexBuildAborted = ErrorCode(276) // IndexBuildAborted
+
+	// ErrQueryExceededMemoryLimitNoDiskUseAllowed indicates that a blocking stage exceeded the memory limit
+	// without `allowDiskUse` option.
+	ErrQueryExceededMemoryLimitNoDiskUseAllowed = ErrorCode(292) // QueryExceededMemoryLimitNoDiskUseAllowed
+
 	// ErrMechanismUnavailable indicates that the authentication mechanism is unavailable.
 	ErrMechanismUnavailable = ErrorCode(334)
 
@@ -137,9 +162,21 @@ const (
 	// ErrIndexesWrongType indicates that indexes parameter has wrong type.
 	ErrIndexesWrongType = ErrorCode(10065) // Location10065
 
+	// ErrNotWritablePrimary indicates that the write command was rejected because writes are not allowed.
+	ErrNotWritablePrimary = ErrorCode(10107) // NotWritablePrimary
+
 	// ErrDuplicateKeyInsert indicates duplicate key violation on inserting document.
 	ErrDuplicateKeyInsert = ErrorCode(11000) // DuplicateKey
 
+	// ErrInterrupted indicates that the operation was interrupted.
+	ErrInterrupted = ErrorCode(11601) // Interrupted
+
+	// ErrQuotaExceeded indicates that the database or collection quota was exceeded.
+	ErrQuotaExceeded = ErrorCode(12501) // Location12501
+
+	// ErrDatabaseDifferCase indicates that the database with the same name in different case already exists.
+	ErrDatabaseDifferCase = ErrorCode(13297) // DatabaseDifferCase
+
 	// ErrSetBadExpression indicates set expression is not object.
 	ErrSetBadExpression = ErrorCode(40272) // Location40272
 
@@ -185,6 +222,9 @@ const (
 	// ErrPathContainsEmptyElement indicates that the path contains an empty element.
 	ErrPathContainsEmptyElement = ErrorCode(15998) // Location15998
 
+	// ErrCannotConvertToDate indicates that a value cannot be converted to date.
+	ErrCannotConvertToDate = ErrorCode(16006) // Location16006
+
 	// ErrOperatorWrongLenOfArgs indicates that aggregation operator contains
 	// wrong amount of arguments.
 	ErrOperatorWrongLenOfArgs = ErrorCode(16020) // Location16020
@@ -192,21 +232,141 @@ const (
 	// ErrFieldPathInvalidName indicates that FieldPath is invalid.
 	ErrFieldPathInvalidName = ErrorCode(16410) // Location16410
 
+	// ErrFieldPathContainsDot indicates that FieldPath field name contains a dot.
+	ErrFieldPathContainsDot = ErrorCode(16412) // Location16412
+
+	// ErrHashedIndexUnique indicates that hashed index can't be unique.
+	ErrHashedIndexUnique = ErrorCode(16764) // Location16764
+
+	// ErrEmptyVariableName indicates that variable name is empty.
+	ErrEmptyVariableName = ErrorCode(16866) // Location16866
+
+	// ErrVariableNameInvalidStart indicates that user variable name starts with invalid character.
+	ErrVariableNameInvalidStart = ErrorCode(16870) // Location16870
+
+	// ErrVariableNameInvalidChar indicates that variable name contains invalid character.
+	ErrVariableNameInvalidChar = ErrorCode(16871) // Location16871
+
 	// ErrGroupInvalidFieldPath indicates invalid path is given for group _id.
 	ErrGroupInvalidFieldPath = ErrorCode(16872) // Location16872
 
 	// ErrBadNumberToReturn indicates that invalid number to return was given for op query.
 	ErrBadNumberToReturn = ErrorCode(16979) // Location16979
 
-	// ErrGroupUndefinedVariable indicates the variable is not defined.
-	ErrGroupUndefinedVariable = ErrorCode(17276) // Location17276
+	// ErrAllElementsTrueNotArray indicates that $allElementsTrue argument is not an array.
+	ErrAllElementsTrueNotArray = ErrorCode(17040) // Location17040
+
+	// ErrAnyElementTrueNotArray indicates that $anyElementTrue argument is not an array.
+	ErrAnyElementTrueNotArray = ErrorCode(17041) // Location17041
+
+	// ErrSetIsSubsetSecondNotArray indicates that $setIsSubset second argument is not an array.
+	ErrSetIsSubsetSecondNotArray = ErrorCode(17042) // Location17042
+
+	// ErrSetUnionNotArray indicates that $setUnion argument is not an array.
+	ErrSetUnionNotArray = ErrorCode(17043) // Location17043
+
+	// ErrSetIsSubsetFirstNotArray indicates that $setIsSubset first argument is not an array.
+	ErrSetIsSubsetFirstNotArray = ErrorCode(17046) // Location17046
+
+	// ErrSetIntersectionNotArray indicates that $setIntersection argument is not an array.
+	ErrSetIntersectionNotArray = ErrorCode(17047) // Location17047
+
+	// ErrSetDifferenceFirstNotArray indicates that $setDifference first argument is not an array.
+	ErrSetDifferenceFirstNotArray = ErrorCode(17048) // Location17048
+
+	// ErrSetDifferenceSecondNotArray indicates that $setDifference second argument is not an array.
+	ErrSetDifferenceSecondNotArray = ErrorCode(17049) // Location17049
+
+	// ErrUndefinedVariable indicates the variable is not defined.
+	ErrUndefinedVariable = ErrorCode(17276) // Location17276
+
+	// ErrDateToStringFormatNotString indicates that $dateToString format is not a string.
+	ErrDateToStringFormatNotString = ErrorCode(18533) // Location18533
+
+	// ErrDateToStringUnknownArgument indicates that $dateToString found an unknown argument.
+	ErrDateToStringUnknownArgument = ErrorCode(18534) // Location18534
+
+	// ErrDateFormatUnmatchedPercent indicates that date format string ends with unmatched %.
+	ErrDateFormatUnmatchedPercent = ErrorCode(18535) // Location18535
+
+	// ErrDateFormatInvalidCharacter indicates that date format string contains invalid format character.
+	ErrDateFormatInvalidCharacter = ErrorCode(18536) // Location18536
+
+	// ErrDateToStringMissingDate indicates that $dateToString requires the date parameter.
+	ErrDateToStringMissingDate = ErrorCode(18628) // Location18628
+
+	// ErrDateToStringNotObject indicates that $dateToString requires an object as an argument.
+	ErrDateToStringNotObject = ErrorCode(18629) // Location18629
+
+	// ErrConcatArraysNotArray indicates that $concatArrays argument is not an array.
+	ErrConcatArraysNotArray = ErrorCode(28664) // Location28664
 
 	// ErrInvalidArg indicates invalid argument in projection document.
 	ErrInvalidArg = ErrorCode(28667) // Location28667
 
+	// ErrAbsLongMin indicates that $abs is applied to the minimal long value.
+	ErrAbsLongMin = ErrorCode(28680) // Location28680
+
+	// ErrArrayElemAtNotArray indicates that $arrayElemAt, $first or $last argument is not an array.
+	ErrArrayElemAtNotArray = ErrorCode(28689) // Location28689
+
+	// ErrArrayElemAtIndexNotNumber indicates that $arrayElemAt index is not a number.
+	ErrArrayElemAtIndexNotNumber = ErrorCode(28690) // Location28690
+
+	// ErrArrayElemAtIndexNotInt indicates that $arrayElemAt index is not a 32-bit integer.
+	ErrArrayElemAtIndexNotInt = ErrorCode(28691) // Location28691
+
+	// ErrSqrtNegative indicates that $sqrt argument is negative.
+	ErrSqrtNegative = ErrorCode(28714) // Location28714
+
 	// ErrSliceFirstArg for $slice indicates that the first argument is not an array.
 	ErrSliceFirstArg = ErrorCode(28724) // Location28724
 
+	// ErrSliceSecondArgNotNumber indicates that $slice second argument is not a number.
+	ErrSliceSecondArgNotNumber = ErrorCode(28725) // Location28725
+
+	// ErrSliceSecondArgNotInt indicates that $slice second argument is not a 32-bit integer.
+	ErrSliceSecondArgNotInt = ErrorCode(28726) // Location28726
+
+	// ErrSliceThirdArgNotNumber indicates that $slice third argument is not a number.
+	ErrSliceThirdArgNotNumber = ErrorCode(28727) // Location28727
+
+	// ErrSliceThirdArgNotInt indicates that $slice third argument is not a 32-bit integer.
+	ErrSliceThirdArgNotInt = ErrorCode(28728) // Location28728
+
+	// ErrSliceThirdArgNotPositive indicates that $slice third argument is not positive.
+	ErrSliceThirdArgNotPositive = ErrorCode(28729) // Location28729
+
+	// ErrLogNotNumber indicates that $log argument is not a number.
+	ErrLogNotNumber = ErrorCode(28756) // Location28756
+
+	// ErrLogBaseNotNumber indicates that $log base is not a number.
+	ErrLogBaseNotNumber = ErrorCode(28757) // Location28757
+
+	// ErrLogNotPositive indicates that $log argument is not a positive number.
+	ErrLogNotPositive = ErrorCode(28758) // Location28758
+
+	// ErrLogInvalidBase indicates that $log base is not a positive number or equal to 1.
+	ErrLogInvalidBase = ErrorCode(28759) // Location28759
+
+	// ErrLog10NotPositive indicates that $log10 argument is not a positive number.
+	ErrLog10NotPositive = ErrorCode(28761) // Location28761
+
+	// ErrPowBaseNotNumber indicates that $pow base is not a number.
+	ErrPowBaseNotNumber = ErrorCode(28762) // Location28762
+
+	// ErrPowExponentNotNumber indicates that $pow exponent is not a number.
+	ErrPowExponentNotNumber = ErrorCode(28763) // Location28763
+
+	// ErrPowZeroNegativeExponent indicates that $pow is applied to zero base and negative exponent.
+	ErrPowZeroNegativeExponent = ErrorCode(28764) // Location28764
+
+	// ErrMathNotNumber indicates that math operator argument is not a number.
+	ErrMathNotNumber = ErrorCode(28765) // Location28765
+
+	// ErrLnNotPositive indicates that $ln argument is not a positive number.
+	ErrLnNotPositive = ErrorCode(28766) // Location28766
+
 	// ErrStageUnsetNoPath indicates that $unwind aggregation stage is empty.
 	ErrStageUnsetNoPath = ErrorCode(31119) // Location31119
 
@@ -216,6 +376,15 @@ const (
 	// ErrStageUnsetInvalidType indicates that $unset stage arguments has unexpected type.
 	ErrStageUnsetInvalidType = ErrorCode(31002) // Location31002
 
+	// ErrRegexMissingInput indicates that regex operator requires the input parameter.
+	ErrRegexMissingInput = ErrorCode(31022) // Location31022
+
+	// ErrRegexMissingRegex indicates that regex operator requires the regex parameter.
+	ErrRegexMissingRegex = ErrorCode(31023) // Location31023
+
+	// ErrRegexUnknownArgument indicates that regex operator found an unknown argument.
+	ErrRegexUnknownArgument = ErrorCode(31024) // Location31024
+
 	// ErrStageUnwindNoPath indicates that $unwind aggregation stage is empty.
 	ErrStageUnwindNoPath = ErrorCode(28812) // Location28812
 
@@ -236,6 +405,9 @@ const (
 	// while projection document already marked as inclusion.
 	ErrProjectionExIn = ErrorCode(31254) // Location31254
 
+	// ErrHashedIndexMultipleFields indicates that index key contains more than one hashed field.
+	ErrHashedIndexMultipleFields = ErrorCode(31303) // Location31303
+
 	// ErrAggregatePositionalProject indicates that positional projection cannot be used in aggregation.
 	ErrAggregatePositionalProject = ErrorCode(31324) // Location31324
 
@@ -249,6 +421,45 @@ const (
 	// ErrExclusionPositionalProjection indicates that exclusion cannot use positional projection.
 	ErrExclusionPositionalProjection = ErrorCode(31395) // Location31395
 
+	// ErrRangeStartNotNumber indicates that $range starting value is not a number.
+	ErrRangeStartNotNumber = ErrorCode(34443) // Location34443
+
+	// ErrRangeStartNotInt indicates that $range starting value is not a 32-bit integer.
+	ErrRangeStartNotInt = ErrorCode(34444) // Location34444
+
+	// ErrRangeEndNotNumber indicates that $range ending value is not a number.
+	ErrRangeEndNotNumber = ErrorCode(34445) // Location34445
+
+	// ErrRangeEndNotInt indicates that $range ending value is not a 32-bit integer.
+	ErrRangeEndNotInt = ErrorCode(34446) // Location34446
+
+	// ErrRangeStepNotNumber indicates that $range step value is not a number.
+	ErrRangeStepNotNumber = ErrorCode(34447) // Location34447
+
+	// ErrRangeStepNotInt indicates that $range step value is not a 32-bit integer.
+	ErrRangeStepNotInt = ErrorCode(34448) // Location34448
+
+	// ErrRangeStepZero indicates that $range step value is zero.
+	ErrRangeStepZero = ErrorCode(34449) // Location34449
+
+	// ErrSplitInvalidInput indicates that $split input is not a string.
+	ErrSplitInvalidInput = ErrorCode(40085) // Location40085
+
+	// ErrSplitInvalidSeparator indicates that $split separator is not a string.
+	ErrSplitInvalidSeparator = ErrorCode(40086) // Location40086
+
+	// ErrSplitEmptySeparator indicates that $split separator is empty.
+	ErrSplitEmptySeparator = ErrorCode(40087) // Location40087
+
+	// ErrIndexOfArrayNotArray indicates that $indexOfArray first argument is not an array.
+	ErrIndexOfArrayNotArray = ErrorCode(40090) // Location40090
+
+	// ErrIndexOfArrayIndexNotInt indicates that $indexOfArray starting or ending index is not an integer.
+	ErrIndexOfArrayIndexNotInt = ErrorCode(40096) // Location40096
+
+	// ErrIndexOfArrayIndexNegative indicates that $indexOfArray starting or ending index is negative.
+	ErrIndexOfArrayIndexNegative = ErrorCode(40097) // Location40097
+
 	// ErrStageCountNonString indicates that $count aggregation stage expected string.
 	ErrStageCountNonString = ErrorCode(40156) // Location40156
 
@@ -261,6 +472,15 @@ const (
 	// ErrStageCountBadValue indicates that $count stage contains invalid value.
 	ErrStageCountBadValue = ErrorCode(40160) // Location40160
 
+	// ErrStageFacetInvalidSpec indicates that $facet stage specification is not a non-empty document.
+	ErrStageFacetInvalidSpec = ErrorCode(40169) // Location40169
+
+	// ErrStageFacetNotArray indicates that $facet stage contains a sub-pipeline that is not an array.
+	ErrStageFacetNotArray = ErrorCode(40170) // Location40170
+
+	// ErrStageFacetInvalidStage indicates that $facet sub-pipeline contains a stage that is not a document.
+	ErrStageFacetInvalidStage = ErrorCode(40171) // Location40171
+
 	// ErrAddFieldsExpressionWrongAmountOfArgs indicates that $addFields stage expression contain invalid
 	// amount of arguments.
 	ErrAddFieldsExpressionWrongAmountOfArgs = ErrorCode(40181) // Location40181
@@ -283,43 +503,156 @@ const (
 	// ErrInvalidFieldPath indicates that the field path is not valid.
 	ErrInvalidFieldPath = ErrorCode(40353) // Location40353
 
+	// ErrArrayToObjectNotArray indicates that $arrayToObject input is not an array.
+	ErrArrayToObjectNotArray = ErrorCode(40386) // Location40386
+
+	// ErrObjectToArrayNotDocument indicates that $objectToArray input is not a document.
+	ErrObjectToArrayNotDocument = ErrorCode(40390) // Location40390
+
+	// ErrArrayToObjectExpectedDocument indicates that $arrayToObject input element is not a document
+	// while the first element is.
+	ErrArrayToObjectExpectedDocument = ErrorCode(40391) // Location40391
+
+	// ErrArrayToObjectInvalidKeysLen indicates that $arrayToObject document element does not have exactly two fields.
+	ErrArrayToObjectInvalidKeysLen = ErrorCode(40392) // Location40392
+
+	// ErrArrayToObjectMissingKeys indicates that $arrayToObject document element does not have k and v fields.
+	ErrArrayToObjectMissingKeys = ErrorCode(40393) // Location40393
+
+	// ErrArrayToObjectDocumentKeyNotString indicates that $arrayToObject document element k field is not a string.
+	ErrArrayToObjectDocumentKeyNotString = ErrorCode(40394) // Location40394
+
+	// ErrArrayToObjectArrayKeyNotString indicates that $arrayToObject array element key is not a string.
+	ErrArrayToObjectArrayKeyNotString = ErrorCode(40395) // Location40395
+
+	// ErrArrayToObjectExpectedArray indicates that $arrayToObject input element is not an array
+	// while the first element is.
+	ErrArrayToObjectExpectedArray = ErrorCode(40396) // Location40396
+
+	// ErrArrayToObjectInvalidArrayLen indicates that $arrayToObject array element does not have exactly two elements.
+	ErrArrayToObjectInvalidArrayLen = ErrorCode(40397) // Location40397
+
+	// ErrArrayToObjectInvalidElement indicates that $arrayToObject input element is neither an array nor a document.
+	ErrArrayToObjectInvalidElement = ErrorCode(40398) // Location40398
+
+	// ErrMergeObjectsNotDocument indicates that $mergeObjects input is not a document.
+	ErrMergeObjectsNotDocument = ErrorCode(40400) // Location40400
+
 	// ErrMissingField indicates that the required field in document is missing.
 	ErrMissingField = ErrorCode(40414) // Location40414
 
 	// ErrFailedToParseInput indicates invalid input (absent or malformed fields).
 	ErrFailedToParseInput = ErrorCode(40415) // Location40415
 
+	// ErrUnrecognizedTimeZone indicates that time zone identifier is not recognized.
+	ErrUnrecognizedTimeZone = ErrorCode(40485) // Location40485
+
+	// ErrTimeZoneNotString indicates that timezone does not evaluate to a string.
+	ErrTimeZoneNotString = ErrorCode(40517) // Location40517
+
+	// ErrDateFromStringNotObject indicates that $dateFromString requires an object as an argument.
+	ErrDateFromStringNotObject = ErrorCode(40540) // Location40540
+
+	// ErrDateFromStringUnknownArgument indicates that $dateFromString found an unknown argument.
+	ErrDateFromStringUnknownArgument = ErrorCode(40541) // Location40541
+
+	// ErrDateFromStringMissingDateString indicates that $dateFromString requires the dateString parameter.
+	ErrDateFromStringMissingDateString = ErrorCode(40542) // Location40542
+
+	// ErrStageFacetNotAllowed indicates that the stage is not allowed in $facet sub-pipeline.
+	ErrStageFacetNotAllowed = ErrorCode(40600) // Location40600
+
 	// ErrCollStatsIsNotFirstStage indicates that $collStats must be the first stage in the pipeline.
 	ErrCollStatsIsNotFirstStage = ErrorCode(40602) // Location40602
 
 	// ErrOpQueryInvalidField indicates that the field is not allowed for op query.
 	ErrOpQueryInvalidField = ErrorCode(40621) // Location40621
 
+	// ErrDateFromStringFormatNotString indicates that $dateFromString format is not a string.
+	ErrDateFromStringFormatNotString = ErrorCode(40684) // Location40684
+
 	// ErrSetEmptyPassword indicates that a password must not be empty.
 	ErrSetEmptyPassword = ErrorCode(50687) // Location50687
 
 	// ErrStringProhibited indicates that a password contains prohibited runes.
 	ErrStringProhibited = ErrorCode(50692) // Location50692
 
+	// ErrTrimUnknownArgument indicates that $trim operator found an unknown argument.
+	ErrTrimUnknownArgument = ErrorCode(50694) // Location50694
+
+	// ErrTrimMissingInput indicates that $trim operator requires the input parameter.
+	ErrTrimMissingInput = ErrorCode(50695) // Location50695
+
+	// ErrTrimNotObject indicates that $trim operator requires an object as an argument.
+	ErrTrimNotObject = ErrorCode(50696) // Location50696
+
+	// ErrTrimInputNotString indicates that $trim input is not a string.
+	ErrTrimInputNotString = ErrorCode(50699) // Location50699
+
+	// ErrTrimCharsNotString indicates that $trim chars is not a string.
+	ErrTrimCharsNotString = ErrorCode(50700) // Location50700
+
 	// ErrFreeMonitoringDisabled indicates that free monitoring is disabled
 	// by command-line or config file.
 	ErrFreeMonitoringDisabled = ErrorCode(50840) // Location50840
 
+	// ErrTrigonometricOutOfRange indicates that trigonometric operator argument is out of range.
+	ErrTrigonometricOutOfRange = ErrorCode(50989) // Location50989
+
 	// ErrUserAlreadyExists indicates that user already exists.
 	ErrUserAlreadyExists = ErrorCode(51003) // Location51003
 
 	// ErrValueNegative indicates that value must not be negative.
 	ErrValueNegative = ErrorCode(51024) // Location51024
 
+	// ErrAtan2NotNumber indicates that $atan2 argument is not a number.
+	ErrAtan2NotNumber = ErrorCode(51044) // Location51044
+
 	// ErrRegexOptions indicates regex options error.
 	ErrRegexOptions = ErrorCode(51075) // Location51075
 
+	// ErrRoundNotNumber indicates that $round or $trunc argument is not a number.
+	ErrRoundNotNumber = ErrorCode(51081) // Location51081
+
+	// ErrRoundPlaceNotIntegral indicates that $round or $trunc place argument is not an integral number.
+	ErrRoundPlaceNotIntegral = ErrorCode(51082) // Location51082
+
+	// ErrRoundPlaceOutOfRange indicates that $round or $trunc place argument is out of range.
+	ErrRoundPlaceOutOfRange = ErrorCode(51083) // Location51083
+
 	// ErrRegexMissingParen indicates missing parentheses in regex expression.
 	ErrRegexMissingParen = ErrorCode(51091) // Location51091
 
+	// ErrRegexNotObject indicates that regex operator requires an object of named arguments.
+	ErrRegexNotObject = ErrorCode(51103) // Location51103
+
+	// ErrRegexInputNotString indicates that regex operator input is not a string.
+	ErrRegexInputNotString = ErrorCode(51104) // Location51104
+
+	// ErrRegexNotStringOrRegex indicates that regex operator regex is neither a string nor a regex.
+	ErrRegexNotStringOrRegex = ErrorCode(51105) // Location51105
+
+	// ErrRegexOptionsNotString indicates that regex operator options is not a string.
+	ErrRegexOptionsNotString = ErrorCode(51106) // Location51106
+
+	// ErrRegexOptionsConflict indicates that regex options are specified in both regex and options fields.
+	ErrRegexOptionsConflict = ErrorCode(51107) // Location51107
+
 	// ErrBadRegexOption indicates bad regex option value passed.
 	ErrBadRegexOption = ErrorCode(51108) // Location51108
 
+	// ErrRegexInvalid indicates that regex operator regular expression is invalid.
+	ErrRegexInvalid = ErrorCode(51111) // Location51111
+
+	// ErrMinMaxNoHint indicates that min or max is used without an index hint.
+	ErrMinMaxNoHint = ErrorCode(51173) // Location51173
+
+	// ErrMinMaxIndexMismatch indicates that min or max bounds do not match the hinted index.
+	ErrMinMaxIndexMismatch = ErrorCode(51174) // Location51174
+
+	// ErrMinMaxFieldsMismatch indicates that min and max have different field names.
+	ErrMinMaxFieldsMismatch = ErrorCode(51176) // Location51176
+
 	// ErrBadPositionalProjection indicates that positional operator could not find a matching element in the array.
 	ErrBadPositionalProjection = ErrorCode(51246) // Location51246
 
@@ -332,9 +665,42 @@ const (
 	// ErrEmptyProject indicates that projection specification must have at least one field.
 	ErrEmptyProject = ErrorCode(51272) // Location51272
 
+	// ErrReplaceReplacementNotString indicates that replacement of $replaceOne or $replaceAll is not a string.
+	ErrReplaceReplacementNotString = ErrorCode(51744) // Location51744
+
+	// ErrReplaceFindNotString indicates that find of $replaceOne or $replaceAll is not a string.
+	ErrReplaceFindNotString = ErrorCode(51745) // Location51745
+
+	// ErrReplaceInputNotString indicates that input of $replaceOne or $replaceAll is not a string.
+	ErrReplaceInputNotString = ErrorCode(51746) // Location51746
+
+	// ErrReplaceMissingReplacement indicates that $replaceOne or $replaceAll requires the replacement parameter.
+	ErrReplaceMissingReplacement = ErrorCode(51747) // Location51747
+
+	// ErrReplaceMissingFind indicates that $replaceOne or $replaceAll requires the find parameter.
+	ErrReplaceMissingFind = ErrorCode(51748) // Location51748
+
+	// ErrReplaceMissingInput indicates that $replaceOne or $replaceAll requires the input parameter.
+	ErrReplaceMissingInput = ErrorCode(51749) // Location51749
+
+	// ErrReplaceUnknownArgument indicates that $replaceOne or $replaceAll found an unknown argument.
+	ErrReplaceUnknownArgument = ErrorCode(51750) // Location51750
+
+	// ErrReplaceNotObject indicates that $replaceOne or $replaceAll requires an object as an argument.
+	ErrReplaceNotObject = ErrorCode(51751) // Location51751
+
+	// ErrRandArguments indicates that $rand operator has arguments.
+	ErrRandArguments = ErrorCode(3040500) // Location3040500
+
 	// ErrDuplicateField indicates duplicate field is specified.
 	ErrDuplicateField = ErrorCode(4822819) // Location4822819
 
+	// ErrArrayToObjectArrayKeyNullByte indicates that $arrayToObject array element key contains a null byte.
+	ErrArrayToObjectArrayKeyNullByte = ErrorCode(4940400) // Location4940400
+
+	// ErrArrayToObjectDocumentKeyNullByte indicates that $arrayToObject document element key contains a null byte.
+	ErrArrayToObjectDocumentKeyNullByte = ErrorCode(4940401) // Location4940401
+
 	// ErrStageSkipBadValue indicates that $skip stage contains invalid value.
 	ErrStageSkipBadValue = ErrorCode(5107200) // Location5107200
 
@@ -15,6 +15,7 @@ func _() {
 	_ = x[ErrUserNotFound-11]
 	_ = x[ErrUnauthorized-13]
 	_ = x[ErrTypeMismatch-14]
+	_ = x[ErrInvalidLength-16]
 	_ = x[ErrProtocolError-17]
 	_ = x[ErrAuthenticationFailed-18]
 	_ = x[ErrIllegalOperation-20]
@@ -34,18 +35,29 @@ func _() {
 	_ = x[ErrIndexAlreadyExists-68]
 	_ = x[ErrInvalidOptions-72]
 	_ = x[ErrInvalidNamespace-73]
+	_ = x[ErrNoReplicationEnabled-76]
 	_ = x[ErrIndexOptionsConflict-85]
 	_ = x[ErrIndexKeySpecsConflict-86]
 	_ = x[ErrOperationFailed-96]
+	_ = x[ErrWriteConflict-112]
 	_ = x[ErrDocumentValidationFailure-121]
+	_ = x[ErrExceededMemoryLimit-146]
 	_ = x[ErrInvalidIndexSpecificationOption-197]
 	_ = x[ErrInvalidPipelineOperator-168]
 	_ = x[ErrClientMetadataCannotBeMutated-186]
+	_ = x[ErrStaleClusterTime-209]
 	_ = x[ErrNotImplemented-238]
+	_ = x[ErrConversionFailure-241]
+	_ = x[ErrIndexBuildAborted-276]
+	_ = x[ErrQueryExceededMemoryLimitNoDiskUseAllowed-292]
 	_ = x[ErrMechanismUnavailable-334]
 	_ = x[ErrUnsupportedOpQueryCommand-352]
 	_ = x[ErrIndexesWrongType-10065]
+	_ = x[ErrNotWritablePrimary-10107]
 	_ = x[ErrDuplicateKeyInsert-11000]
+	_ = x[ErrInterrupted-11601]
+	_ = x[ErrQuotaExceeded-12501]
+	_ = x[ErrDatabaseDifferCase-13297]
 	_ = x[ErrSetBadExpression-40272]
 	_ = x[ErrStageGroupInvalidFields-15947]
 	_ = x[ErrStageGroupID-15948]
@@ -61,30 +73,91 @@ func _() {
 	_ = x[ErrStageUnwindWrongType-15981]
 	_ = x[ErrExpressionWrongLenOfFields-15983]
 	_ = x[ErrPathContainsEmptyElement-15998]
+	_ = x[ErrCannotConvertToDate-16006]
 	_ = x[ErrOperatorWrongLenOfArgs-16020]
 	_ = x[ErrFieldPathInvalidName-16410]
+	_ = x[ErrFieldPathContainsDot-16412]
+	_ = x[ErrHashedIndexUnique-16764]
+	_ = x[ErrEmptyVariableName-16866]
+	_ = x[ErrVariableNameInvalidStart-16870]
+	_ = x[ErrVariableNameInvalidChar-16871]
 	_ = x[ErrGroupInvalidFieldPath-16872]
 	_ = x[ErrBadNumberToReturn-16979]
-	_ = x[ErrGroupUndefinedVariable-17276]
+	_ = x[ErrAllElementsTrueNotArray-17040]
+	_ = x[ErrAnyElementTrueNotArray-17041]
+	_ = x[ErrSetIsSubsetSecondNotArray-17042]
+	_ = x[ErrSetUnionNotArray-17043]
+	_ = x[ErrSetIsSubsetFirstNotArray-17046]
+	_ = x[ErrSetIntersectionNotArray-17047]
+	_ = x[ErrSetDifferenceFirstNotArray-17048]
+	_ = x[ErrSetDifferenceSecondNotArray-17049]
+	_ = x[ErrUndefinedVariable-17276]
+	_ = x[ErrDateToStringFormatNotString-18533]
+	_ = x[ErrDateToStringUnknownArgument-18534]
+	_ = x[ErrDateFormatUnmatchedPercent-18535]
+	_ = x[ErrDateFormatInvalidCharacter-18536]
+	_ = x[ErrDateToStringMissingDate-18628]
+	_ = x[ErrDateToStringNotObject-18629]
+	_ = x[ErrConcatArraysNotArray-28664]
 	_ = x[ErrInvalidArg-28667]
+	_ = x[ErrAbsLongMin-28680]
+	_ = x[ErrArrayElemAtNotArray-28689]
+	_ = x[ErrArrayElemAtIndexNotNumber-28690]
+	_ = x[ErrArrayElemAtIndexNotInt-28691]
+	_ = x[ErrSqrtNegative-28714]
 	_ = x[ErrSliceFirstArg-28724]
+	_ = x[ErrSliceSecondArgNotNumber-28725]
+	_ = x[ErrSliceSecondArgNotInt-28726]
+	_ = x[ErrSliceThirdArgNotNumber-28727]
+	_ = x[ErrSliceThirdArgNotInt-28728]
+	_ = x[ErrSliceThirdArgNotPositive-28729]
+	_ = x[ErrLogNotNumber-28756]
+	_ = x[ErrLogBaseNotNumber-28757]
+	_ = x[ErrLogNotPositive-28758]
+	_ = x[ErrLogInvalidBase-28759]
+	_ = x[ErrLog10NotPositive-28761]
+	_ = x[ErrPowBaseNotNumber-28762]
+	_ = x[ErrPowExponentNotNumber-28763]
+	_ = x[ErrPowZeroNegativeExponent-28764]
+	_ = x[ErrMathNotNumber-28765]
+	_ = x[ErrLnNotPositive-28766]
 	_ = x[ErrStageUnsetNoPath-31119]
 	_ = x[ErrStageUnsetArrElementInvalidType-31120]
 	_ = x[ErrStageUnsetInvalidType-31002]
+	_ = x[ErrRegexMissingInput-31022]
+	_ = x[ErrRegexMissingRegex-31023]
+	_ = x[ErrRegexUnknownArgument-31024]
 	_ = x[ErrStageUnwindNoPath-28812]
 	_ = x[ErrStageUnwindNoPrefix-28818]
 	_ = x[ErrUnsetPathCollision-31249]
 	_ = x[ErrUnsetPathOverwrite-31250]
 	_ = x[ErrProjectionInEx-31253]
 	_ = x[ErrProjectionExIn-31254]
+	_ = x[ErrHashedIndexMultipleFields-31303]
 	_ = x[ErrAggregatePositionalProject-31324]
 	_ = x[ErrAggregateInvalidExpression-31325]
 	_ = x[ErrWrongPositionalOperatorLocation-31394]
 	_ = x[ErrExclusionPositionalProjection-31395]
+	_ = x[ErrRangeStartNotNumber-34443]
+	_ = x[ErrRangeStartNotInt-34444]
+	_ = x[ErrRangeEndNotNumber-34445]
+	_ = x[ErrRangeEndNotInt-34446]
+	_ = x[ErrRangeStepNotNumber-34447]
+	_ = x[ErrRangeStepNotInt-34448]
+	_ = x[ErrRangeStepZero-34449]
+	_ = x[ErrSplitInvalidInput-40085]
+	_ = x[ErrSplitInvalidSeparator-40086]
+	_ = x[ErrSplitEmptySeparator-40087]
+	_ = x[ErrIndexOfArrayNotArray-40090]
+	_ = x[ErrIndexOfArrayIndexNotInt-40096]
+	_ = x[ErrIndexOfArrayIndexNegative-40097]
 	_ = x[ErrStageCountNonString-40156]
 	_ = x[ErrStageCountNonEmptyString-40157]
 	_ = x[ErrStageCountBadPrefix-40158]
 	_ = x[ErrStageCountBadValue-40160]
+	_ = x[ErrStageFacetInvalidSpec-40169]
+	_ = x[ErrStageFacetNotArray-40170]
+	_ = x[ErrStageFacetInvalidStage-40171]
 	_ = x[ErrAddFieldsExpressionWrongAmountOfArgs-40181]
 	_ = x[ErrStageGroupUnaryOperator-40237]
 	_ = x[ErrStageGroupMultipleAccumulator-40238]
@@ -92,23 +165,71 @@ func _() {
 	_ = x[ErrStageInvalid-40323]
 	_ = x[ErrEmptyFieldPath-40352]
 	_ = x[ErrInvalidFieldPath-40353]
+	_ = x[ErrArrayToObjectNotArray-40386]
+	_ = x[ErrObjectToArrayNotDocument-40390]
+	_ = x[ErrArrayToObjectExpectedDocument-40391]
+	_ = x[ErrArrayToObjectInvalidKeysLen-40392]
+	_ = x[ErrArrayToObjectMissingKeys-40393]
+	_ = x[ErrArrayToObjectDocumentKeyNotString-40394]
+	_ = x[ErrArrayToObjectArrayKeyNotString-40395]
+	_ = x[ErrArrayToObjectExpectedArray-40396]
+	_ = x[ErrArrayToObjectInvalidArrayLen-40397]
+	_ = x[ErrArrayToObjectInvalidElement-40398]
+	_ = x[ErrMergeObjectsNotDocument-40400]
 	_ = x[ErrMissingField-40414]
 	_ = x[ErrFailedToParseInput-40415]
+	_ = x[ErrUnrecognizedTimeZone-40485]
+	_ = x[ErrTimeZoneNotString-40517]
+	_ = x[ErrDateFromStringNotObject-40540]
+	_ = x[ErrDateFromStringUnknownArgument-40541]
+	_ = x[ErrDateFromStringMissingDateString-40542]
+	_ = x[ErrStageFacetNotAllowed-40600]
 	_ = x[ErrCollStatsIsNotFirstStage-40602]
 	_ = x[ErrOpQueryInvalidField-40621]
+	_ = x[ErrDateFromStringFormatNotString-40684]
 	_ = x[ErrSetEmptyPassword-50687]
 	_ = x[ErrStringProhibited-50692]
+	_ = x[ErrTrimUnknownArgument-50694]
+	_ = x[ErrTrimMissingInput-50695]
+	_ = x[ErrTrimNotObject-50696]
+	_ = x[ErrTrimInputNotString-50699]
+	_ = x[ErrTrimCharsNotString-50700]
 	_ = x[ErrFreeMonitoringDisabled-50840]
+	_ = x[ErrTrigonometricOutOfRange-50989]
 	_ = x[ErrUserAlreadyExists-51003]
 	_ = x[ErrValueNegative-51024]
+	_ = x[ErrAtan2NotNumber-51044]
 	_ = x[ErrRegexOptions-51075]
+	_ = x[ErrRoundNotNumber-51081]
+	_ = x[ErrRoundPlaceNotIntegral-51082]
+	_ = x[ErrRoundPlaceOutOfRange-51083]
 	_ = x[ErrRegexMissingParen-51091]
+	_ = x[ErrRegexNotObject-51103]
+	_ = x[ErrRegexInputNotString-51104]
+	_ = x[ErrRegexNotStringOrRegex-51105]
+	_ = x[ErrRegexOptionsNotString-51106]
+	_ = x[ErrRegexOptionsConflict-51107]
 	_ = x[ErrBadRegexOption-51108]
+	_ = x[ErrRegexInvalid-51111]
+	_ = x[ErrMinMaxNoHint-51173]
+	_ = x[ErrMinMaxIndexMismatch-51174]
+	_ = x[ErrMinMaxFieldsMismatch-51176]
 	_ = x[ErrBadPositionalProjection-51246]
 	_ = x[ErrElementMismatchPositionalProjection-51247]
 	_ = x[ErrEmptySubProject-51270]
 	_ = x[ErrEmptyProject-51272]
+	_ = x[ErrReplaceReplacementNotString-51744]
+	_ = x[ErrReplaceFindNotString-51745]
+	_ = x[ErrReplaceInputNotString-51746]
+	_ = x[ErrReplaceMissingReplacement-51747]
+	_ = x[ErrReplaceMissingFind-51748]
+	_ = x[ErrReplaceMissingInput-51749]
+	_ = x[ErrReplaceUnknownArgument-51750]
+	_ = x[ErrReplaceNotObject-51751]
+	_ = x[ErrRandArguments-3040500]
 	_ = x[ErrDuplicateField-4822819]
+	_ = x[ErrArrayToObjectArrayKeyNullByte-4940400]
+	_ = x[ErrArrayToObjectDocumentKeyNullByte-4940401]
 	_ = x[ErrStageSkipBadValue-5107200]
 	_ = x[ErrStageLimitInvalidArg-5107201]
 	_ = x[ErrStageCollStatsInvalidArg-5447000]
@@ -116,7 +237,7 @@ func _() {
 	_ = x[ErrStageIndexedStringVectorDuplicate-7582300]
 }
 
-const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUserNotFoundUnauthorizedTypeMismatchProtocolErrorAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIdFieldEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedErrMechanismUnavailableUnsupportedOpQueryCommandLocation10065DuplicateKeyLocation15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location16979Location17276Location28667Location28724Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location40621Location50687Location50692Location50840Location51003Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000Location5739101Location7582300"
+const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUserNotFoundUnauthorizedTypeMismatchInvalidLengthProtocolErrorAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIdFieldEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceNoReplicationEnabledIndexOptionsConflictIndexKeySpecsConflictOperationFailedWriteConflictDocumentValidationFailureExceededMemoryLimitInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionStaleClusterTimeNotImplementedConversionFailureIndexBuildAbortedQueryExceededMemoryLimitNoDiskUseAllowedErrMechanismUnavailableUnsupportedOpQueryCommandLocation10065NotWritablePrimaryDuplicateKeyInterruptedLocation12501DatabaseDifferCaseLocation15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16006Location16020Location16406Location16410Location16412Location16764Location16866Location16870Location16871Location16872Location16979Location17040Location17041Location17042Location17043Location17046Location17047Location17048Location17049Location17276Location18533Location18534Location18535Location18536Location18628Location18629Location28664Location28667Location28680Location28689Location28690Location28691Location28714Location28724Location28725Location28726Location28727Location28728Location28729Location28756Location28757Location28758Location28759Location28761Location28762Location28763Location28764Location28765Location28766Location28812Location28818Location31002Location31022Location31023Location31024Location31119Location31120Location31249Location31250Location31253Location31254Location31303Location31324Location31325Location31394Location31395Location34443Location34444Location34445Location34446Location34447Location34448Location34449Location40085Location40086Location40087Location40090Location40096Location40097Location40156Location40157Location40158Location40160Location40169Location40170Location40171Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40386Location40390Location40391Location40392Location40393Location40394Location40395Location40396Location40397Location40398Location40400Location40414Location40415Location40485Location40517Location40540Location40541Location40542Location40600Location40602Location40621Location40684Location50687Location50692Location50694Location50695Location50696Location50699Location50700Location50840Location50989Location51003Location51024Location51044Location51075Location51081Location51082Location51083Location51091Location51103Location51104Location51105Location51106Location51107Location51108Location51111Location51173Location51174Location51176Location51246Location51247Location51270Location51272Location51744Location51745Location51746Location51747Location51748Location51749Location51750Location51751Location3040500Location4822819Location4940400Location4940401Location5107200Location5107201Location5447000Location5739101Location7582300"
 
 var _ErrorCode_map = map[ErrorCode]string{
 	0:       _ErrorCode_name[0:5],
@@ -126,105 +247,226 @@ var _ErrorCode_map = map[ErrorCode]string{
 	11:      _ErrorCode_name[39:51],
 	13:      _ErrorCode_name[51:63],
 	14:      _ErrorCode_name[63:75],
-	17:      _ErrorCode_name[75:88],
-	18:      _ErrorCode_name[88:108],
-	20:      _ErrorCode_name[108:124],
-	26:      _ErrorCode_name[124:141],
-	27:      _ErrorCode_name[141:154],
-	28:      _ErrorCode_name[154:167],
-	40:      _ErrorCode_name[167:193],
-	43:      _ErrorCode_name[193:207],
-	48:      _ErrorCode_name[207:222],
-	50:      _ErrorCode_name[222:238],
-	52:      _ErrorCode_name[238:261],
-	53:      _ErrorCode_name[261:275],
-	56:      _ErrorCode_name[275:289],
-	59:      _ErrorCode_name[289:304],
-	66:      _ErrorCode_name[304:318],
-	67:      _ErrorCode_name[318:335],
-	68:      _ErrorCode_name[335:353],
-	72:      _ErrorCode_name[353:367],
-	73:      _ErrorCode_name[367:383],
-	85:      _ErrorCode_name[383:403],
-	86:      _ErrorCode_name[403:424],
-	96:      _ErrorCode_name[424:439],
-	121:     _ErrorCode_name[439:464],
-	168:     _ErrorCode_name[464:487],
-	186:     _ErrorCode_name[487:516],
-	197:     _ErrorCode_name[516:547],
-	238:     _ErrorCode_name[547:561],
-	334:     _ErrorCode_name[561:584],
-	352:     _ErrorCode_name[584:609],
-	10065:   _ErrorCode_name[609:622],
-	11000:   _ErrorCode_name[622:634],
-	15947:   _ErrorCode_name[634:647],
-	15948:   _ErrorCode_name[647:660],
-	15955:   _ErrorCode_name[660:673],
-	15958:   _ErrorCode_name[673:686],
-	15959:   _ErrorCode_name[686:699],
-	15969:   _ErrorCode_name[699:712],
-	15973:   _ErrorCode_name[712:725],
-	15974:   _ErrorCode_name[725:738],
-	15975:   _ErrorCode_name[738:751],
-	15976:   _ErrorCode_name[751:764],
-	15981:   _ErrorCode_name[764:777],
-	15983:   _ErrorCode_name[777:790],
-	15998:   _ErrorCode_name[790:803],
-	16020:   _ErrorCode_name[803:816],
-	16406:   _ErrorCode_name[816:829],
-	16410:   _ErrorCode_name[829:842],
-	16872:   _ErrorCode_name[842:855],
-	16979:   _ErrorCode_name[855:868],
-	17276:   _ErrorCode_name[868:881],
-	28667:   _ErrorCode_name[881:894],
-	28724:   _ErrorCode_name[894:907],
-	28812:   _ErrorCode_name[907:920],
-	28818:   _ErrorCode_name[920:933],
-	31002:   _ErrorCode_name[933:946],
-	31119:   _ErrorCode_name[946:959],
-	31120:   _ErrorCode_name[959:972],
-	31249:   _ErrorCode_name[972:985],
-	31250:   _ErrorCode_name[985:998],
-	31253:   _ErrorCode_name[998:1011],
-	31254:   _ErrorCode_name[1011:1024],
-	31324:   _ErrorCode_name[1024:1037],
-	31325:   _ErrorCode_name[1037:1050],
-	31394:   _ErrorCode_name[1050:1063],
-	31395:   _ErrorCode_name[1063:1076],
-	40156:   _ErrorCode_name[1076:1089],
-	40157:   _ErrorCode_name[1089:1102],
-	40158:   _ErrorCode_name[1102:1115],
-	40160:   _ErrorCode_name[1115:1128],
-	40181:   _ErrorCode_name[1128:1141],
-	40234:   _ErrorCode_name[1141:1154],
-	40237:   _ErrorCode_name[1154:1167],
-	40238:   _ErrorCode_name[1167:1180],
-	40272:   _ErrorCode_name[1180:1193],
-	40323:   _ErrorCode_name[1193:1206],
-	40352:   _ErrorCode_name[1206:1219],
-	40353:   _ErrorCode_name[1219:1232],
-	40414:   _ErrorCode_name[1232:1245],
-	40415:   _ErrorCode_name[1245:1258],
-	40602:   _ErrorCode_name[1258:1271],
-	40621:   _ErrorCode_name[1271:1284],
-	50687:   _ErrorCode_name[1284:1297],
-	50692:   _ErrorCode_name[1297:1310],
-	50840:   _ErrorCode_name[1310:1323],
-	51003:   _ErrorCode_name[1323:1336],
-	51024:   _ErrorCode_name[1336:1349],
-	51075:   _ErrorCode_name[1349:1362],
-	51091:   _ErrorCode_name[1362:1375],
-	51108:   _ErrorCode_name[1375:1388],
-	51246:   _ErrorCode_name[1388:1401],
-	51247:   _ErrorCode_name[1401:1414],
-	51270:   _ErrorCode_name[1414:1427],
-	51272:   _ErrorCode_name[1427:1440],
-	4822819: _ErrorCode_name[1440:1455],
-	5107200: _ErrorCode_name[1455:1470],
-	5107201: _ErrorCode_name[1470:1485],
-	5447000: _ErrorCode_name[1485:1500],
-	5739101: _ErrorCode_name[1500:1515],
-	7582300: _ErrorCode_name[1515:1530],
+	16:      _ErrorCode_name[75:88],
+	17:      _ErrorCode_name[88:101],
+	18:      _ErrorCode_name[101:121],
+	20:      _ErrorCode_name[121:137],
+	26:      _ErrorCode_name[137:154],
+	27:      _ErrorCode_name[154:167],
+	28:      _ErrorCode_name[167:180],
+	40:      _ErrorCode_name[180:206],
+	43:      _ErrorCode_name[206:220],
+	48:      _ErrorCode_name[220:235],
+	50:      _ErrorCode_name[235:251],
+	52:      _ErrorCode_name[251:274],
+	53:      _ErrorCode_name[274:288],
+	56:      _ErrorCode_name[288:302],
+	59:      _ErrorCode_name[302:317],
+	66:      _ErrorCode_name[317:331],
+	67:      _ErrorCode_name[331:348],
+	68:      _ErrorCode_name[348:366],
+	72:      _ErrorCode_name[366:380],
+	73:      _ErrorCode_name[380:396],
+	76:      _ErrorCode_name[396:416],
+	85:      _ErrorCode_name[416:436],
+	86:      _ErrorCode_name[436:457],
+	96:      _ErrorCode_name[457:472],
+	112:     _ErrorCode_name[472:485],
+	121:     _ErrorCode_name[485:510],
+	146:     _ErrorCode_name[510:529],
+	168:     _ErrorCode_name[529:552],
+	186:     _ErrorCode_name[552:581],
+	197:     _ErrorCode_name[581:612],
+	209:     _ErrorCode_name[612:628],
+	238:     _ErrorCode_name[628:642],
+	241:     _ErrorCode_name[642:659],
+	276:     _ErrorCode_name[659:676],
+	292:     _ErrorCode_name[676:716],
+	334:     _ErrorCode_name[716:739],
+	352:     _ErrorCode_name[739:764],
+	10065:   _ErrorCode_name[764:777],
+	10107:   _ErrorCode_name[777:795],
+	11000:   _ErrorCode_name[795:807],
+	11601:   _ErrorCode_name[807:818],
+	12501:   _ErrorCode_name[818:831],
+	13297:   _ErrorCode_name[831:849],
+	15947:   _ErrorCode_name[849:862],
+	15948:   _ErrorCode_name[862:875],
+	15955:   _ErrorCode_name[875:888],
+	15958:   _ErrorCode_name[888:901],
+	15959:   _ErrorCode_name[901:914],
+	15969:   _ErrorCode_name[914:927],
+	15973:   _ErrorCode_name[927:940],
+	15974:   _ErrorCode_name[940:953],
+	15975:   _ErrorCode_name[953:966],
+	15976:   _ErrorCode_name[966:979],
+	15981:   _ErrorCode_name[979:992],
+	15983:   _ErrorCode_name[992:1005],
+	15998:   _ErrorCode_name[1005:1018],
+	16006:   _ErrorCode_name[1018:1031],
+	16020:   _ErrorCode_name[1031:1044],
+	16406:   _ErrorCode_name[1044:1057],
+	16410:   _ErrorCode_name[1057:1070],
+	16412:   _ErrorCode_name[1070:1083],
+	16764:   _ErrorCode_name[1083:1096],
+	16866:   _ErrorCode_name[1096:1109],
+	16870:   _ErrorCode_name[1109:1122],
+	16871:   _ErrorCode_name[1122:1135],
+	16872:   _ErrorCode_name[1135:1148],
+	16979:   _ErrorCode_name[1148:1161],
+	17040:   _ErrorCode_name[1161:1174],
+	17041:   _ErrorCode_name[1174:1187],
+	17042:   _ErrorCode_name[1187:1200],
+	17043:   _ErrorCode_name[1200:1213],
+	17046:   _ErrorCode_name[1213:1226],
+	17047:   _ErrorCode_name[1226:1239],
+	17048:   _ErrorCode_name[1239:1252],
+	17049:   _ErrorCode_name[1252:1265],
+	17276:   _ErrorCode_name[1265:1278],
+	18533:   _ErrorCode_name[1278:1291],
+	18534:   _ErrorCode_name[1291:1304],
+	18535:   _ErrorCode_name[1304:1317],
+	18536:   _ErrorCode_name[1317:1330],
+	18628:   _ErrorCode_name[1330:1343],
+	18629:   _ErrorCode_name[1343:1356],
+	28664:   _ErrorCode_name[1356:1369],
+	28667:   _ErrorCode_name[1369:1382],
+	28680:   _ErrorCode_name[1382:1395],
+	28689:   _ErrorCode_name[1395:1408],
+	28690:   _ErrorCode_name[1408:1421],
+	28691:   _ErrorCode_name[1421:1434],
+	28714:   _ErrorCode_name[1434:1447],
+	28724:   _ErrorCode_name[1447:1460],
+	28725:   _ErrorCode_name[1460:1473],
+	28726:   _ErrorCode_name[1473:1486],
+	28727:   _ErrorCode_name[1486:1499],
+	28728:   _ErrorCode_name[1499:1512],
+	28729:   _ErrorCode_name[1512:1525],
+	28756:   _ErrorCode_name[1525:1538],
+	28757:   _ErrorCode_name[1538:1551],
+	28758:   _ErrorCode_name[1551:1564],
+	28759:   _ErrorCode_name[1564:1577],
+	28761:   _ErrorCode_name[1577:1590],
+	28762:   _ErrorCode_name[1590:1603],
+	28763:   _ErrorCode_name[1603:1616],
+	28764:   _ErrorCode_name[1616:1629],
+	28765:   _ErrorCode_name[1629:1642],
+	28766:   _ErrorCode_name[1642:1655],
+	28812:   _ErrorCode_name[1655:1668],
+	28818:   _ErrorCode_name[1668:1681],
+	31002:   _ErrorCode_name[1681:1694],
+	31022:   _ErrorCode_name[1694:1707],
+	31023:   _ErrorCode_name[1707:1720],
+	31024:   _ErrorCode_name[1720:1733],
+	31119:   _ErrorCode_name[1733:1746],
+	31120:   _ErrorCode_name[1746:1759],
+	31249:   _ErrorCode_name[1759:1772],
+	31250:   _ErrorCode_name[1772:1785],
+	31253:   _ErrorCode_name[1785:1798],
+	31254:   _ErrorCode_name[1798:1811],
+	31303:   _ErrorCode_name[1811:1824],
+	31324:   _ErrorCode_name[1824:1837],
+	31325:   _ErrorCode_name[1837:1850],
+	31394:   _ErrorCode_name[1850:1863],
+	31395:   _ErrorCode_name[1863:1876],
+	34443:   _ErrorCode_name[1876:1889],
+	34444:   _ErrorCode_name[1889:1902],
+	34445:   _ErrorCode_name[1902:1915],
+	34446:   _ErrorCode_name[1915:1928],
+	34447:   _ErrorCode_name[1928:1941],
+	34448:   _ErrorCode_name[1941:1954],
+	34449:   _ErrorCode_name[1954:1967],
+	40085:   _ErrorCode_name[1967:1980],
+	40086:   _ErrorCode_name[1980:1993],
+	40087:   _ErrorCode_name[1993:2006],
+	40090:   _ErrorCode_name[2006:2019],
+	40096:   _ErrorCode_name[2019:2032],
+	40097:   _ErrorCode_name[2032:2045],
+	40156:   _ErrorCode_name[2045:2058],
+	40157:   _ErrorCode_name[2058:2071],
+	40158:   _ErrorCode_name[2071:2084],
+	40160:   _ErrorCode_name[2084:2097],
+	40169:   _ErrorCode_name[2097:2110],
+	40170:   _ErrorCode_name[2110:2123],
+	40171:   _ErrorCode_name[2123:2136],
+	40181:   _ErrorCode_name[2136:2149],
+	40234:   _ErrorCode_name[2149:2162],
+	40237:   _ErrorCode_name[2162:2175],
+	40238:   _ErrorCode_name[2175:2188],
+	40272:   _ErrorCode_name[2188:2201],
+	40323:   _ErrorCode_name[2201:2214],
+	40352:   _ErrorCode_name[2214:2227],
+	40353:   _ErrorCode_name[2227:2240],
+	40386:   _ErrorCode_name[2240:2253],
+	40390:   _ErrorCode_name[2253:2266],
+	40391:   _ErrorCode_name[2266:2279],
+	40392:   _ErrorCode_name[2279:2292],
+	40393:   _ErrorCode_name[2292:2305],
+	40394:   _ErrorCode_name[2305:2318],
+	40395:   _ErrorCode_name[2318:2331],
+	40396:   _ErrorCode_name[2331:2344],
+	40397:   _ErrorCode_name[2344:2357],
+	40398:   _ErrorCode_name[2357:2370],
+	40400:   _ErrorCode_name[2370:2383],
+	40414:   _ErrorCode_name[2383:2396],
+	40415:   _ErrorCode_name[2396:2409],
+	40485:   _ErrorCode_name[2409:2422],
+	40517:   _ErrorCode_name[2422:2435],
+	40540:   _ErrorCode_name[2435:2448],
+	40541:   _ErrorCode_name[2448:2461],
+	40542:   _ErrorCode_name[2461:2474],
+	40600:   _ErrorCode_name[2474:2487],
+	40602:   _ErrorCode_name[2487:2500],
+	40621:   _ErrorCode_name[2500:2513],
+	40684:   _ErrorCode_name[2513:2526],
+	50687:   _ErrorCode_name[2526:2539],
+	50692:   _ErrorCode_name[2539:2552],
+	50694:   _ErrorCode_name[2552:2565],
+	50695:   _ErrorCode_name[2565:2578],
+	50696:   _ErrorCode_name[2578:2591],
+	50699:   _ErrorCode_name[2591:2604],
+	50700:   _ErrorCode_name[2604:2617],
+	50840:   _ErrorCode_name[2617:2630],
+	50989:   _ErrorCode_name[2630:2643],
+	51003:   _ErrorCode_name[2643:2656],
+	51024:   _ErrorCode_name[2656:2669],
+	51044:   _ErrorCode_name[2669:2682],
+	51075:   _ErrorCode_name[2682:2695],
+	51081:   _ErrorCode_name[2695:2708],
+	51082:   _ErrorCode_name[2708:2721],
+	51083:   _ErrorCode_name[2721:2734],
+	51091:   _ErrorCode_name[2734:2747],
+	51103:   _ErrorCode_name[2747:2760],
+	51104:   _ErrorCode_name[2760:2773],
+	51105:   _ErrorCode_name[2773:2786],
+	51106:   _ErrorCode_name[2786:2799],
+	51107:   _ErrorCode_name[2799:2812],
+	51108:   _ErrorCode_name[2812:2825],
+	51111:   _ErrorCode_name[2825:2838],
+	51173:   _ErrorCode_name[2838:2851],
+	51174:   _ErrorCode_name[2851:2864],
+	51176:   _ErrorCode_name[2864:2877],
+	51246:   _ErrorCode_name[2877:2890],
+	51247:   _ErrorCode_name[2890:2903],
+	51270:   _ErrorCode_name[2903:2916],
+	51272:   _ErrorCode_name[2916:2929],
+	51744:   _ErrorCode_name[2929:2942],
+	51745:   _ErrorCode_name[2942:2955],
+	51746:   _ErrorCode_name[2955:2968],
+	51747:   _ErrorCode_name[2968:2981],
+	51748:   _ErrorCode_name[2981:2994],
+	51749:   _ErrorCode_name[2994:3007],
+	51750:   _ErrorCode_name[3007:3020],
+	51751:   _ErrorCode_name[3020:3033],
+	3040500: _ErrorCode_name[3033:3048],
+	4822819: _ErrorCode_name[3048:3063],
+	4940400: _ErrorCode_name[3063:3078],
+	4940401: _ErrorCode_name[3078:3093],
+	5107200: _ErrorCode_name[3093:3108],
+	5107201: _ErrorCode_name[3108:3123],
+	5447000: _ErrorCode_name[3123:3138],
+	5739101: _ErrorCode_name[3138:3153],
+	7582300: _ErrorCode_name[3153:3168],
 }
 
 func (i ErrorCode) String() string {
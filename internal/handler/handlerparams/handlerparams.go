@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
@@ -45,7 +46,7 @@ var (
 	ErrUnexpectedType = fmt.Errorf("unexpected type")
 )
 
-// GetWholeNumberParam checks if the given value is int32, int64, or float64 containing a whole number,
+// GetWholeNumberParam checks if the given value is int32, int64, float64 or Decimal128 containing a whole number,
 // such as used in the limit, $size, etc.
 func GetWholeNumberParam(value any) (int64, error) {
 	switch value := value.(type) {
@@ -64,6 +65,28 @@ func GetWholeNumberParam(value any) (int64, error) {
 		}
 
 		return int64(value), nil
+	case types.Decimal128:
+		switch {
+		case value.IsInf(1):
+			return 0, ErrInfinity
+		case value.IsInf(-1):
+			return 0, ErrLongExceededNegative
+		case value.IsNaN():
+			return 0, ErrNotWholeNumber
+		}
+
+		r := value.Rat()
+
+		switch {
+		case r.Cmp(new(big.Rat).SetInt64(math.MaxInt64)) > 0:
+			return 0, ErrLongExceededPositive
+		case r.Cmp(new(big.Rat).SetInt64(math.MinInt64)) < 0:
+			return 0, ErrLongExceededNegative
+		case !r.IsInt():
+			return 0, ErrNotWholeNumber
+		}
+
+		return r.Num().Int64(), nil
 	case int32:
 		return int64(value), nil
 	case int64:
@@ -73,6 +96,15 @@ func GetWholeNumberParam(value any) (int64, error) {
 	}
 }
 
+// fractionalToFloat64 returns float64 or Decimal128 value as float64.
+func fractionalToFloat64(value any) float64 {
+	if d, ok := value.(types.Decimal128); ok {
+		return d.Float64()
+	}
+
+	return value.(float64)
+}
+
 // GetValidatedNumberParamWithMinValue converts and validates a value into a number.
 //
 // The function checks the type, ensures it can be represented as a whole number,
@@ -100,19 +132,19 @@ func GetValidatedNumberParamWithMinValue(command string, param string, value any
 				command,
 			)
 		case errors.Is(err, ErrNotWholeNumber):
-			if math.Signbit(value.(float64)) {
+			if math.Signbit(fractionalToFloat64(value)) {
 				return 0, handlererrors.NewCommandErrorMsgWithArgument(
 					handlererrors.ErrValueNegative,
 					fmt.Sprintf(
 						"BSON field '%s' value must be >= %d, actual value '%d'",
-						param, minValue, int(math.Ceil(value.(float64))),
+						param, minValue, int(math.Ceil(fractionalToFloat64(value))),
 					),
 					command,
 				)
 			}
 
 			// for non-integer numbers, value is rounded to the greatest integer value less than the given value.
-			return int64(math.Floor(value.(float64))), nil
+			return int64(math.Floor(fractionalToFloat64(value))), nil
 
 		case errors.Is(err, ErrLongExceededPositive):
 			return math.MaxInt32, nil
@@ -122,7 +154,7 @@ func GetValidatedNumberParamWithMinValue(command string, param string, value any
 				handlererrors.ErrValueNegative,
 				fmt.Sprintf(
 					"BSON field '%s' value must be >= %d, actual value '%d'",
-					param, minValue, int(math.Ceil(value.(float64))),
+					param, minValue, int(math.Ceil(fractionalToFloat64(value))),
 				),
 				command,
 			)
@@ -159,7 +191,8 @@ func getOptionalPositiveNumber(key string, value any) (int64, error) {
 				key,
 			)
 		case errors.Is(err, ErrNotWholeNumber):
-			if _, ok := value.(float64); ok {
+			switch value.(type) {
+			case float64, types.Decimal128:
 				return 0, handlererrors.NewCommandErrorMsgWithArgument(
 					handlererrors.ErrBadValue,
 					fmt.Sprintf("%v has non-integral value", key),
@@ -197,7 +230,7 @@ func getOptionalPositiveNumber(key string, value any) (int64, error) {
 }
 
 // GetBoolOptionalParam returns bool value of v.
-// Non-zero double, long, and int values return true.
+// Non-zero double, long, int, and decimal values return true.
 // Zero values for those types, as well as nulls and missing fields, return false.
 // Other types return a protocol error.
 func GetBoolOptionalParam(key string, v any) (bool, error) {
@@ -212,6 +245,8 @@ func GetBoolOptionalParam(key string, v any) (bool, error) {
 		return v != 0, nil
 	case int64:
 		return v != 0, nil
+	case types.Decimal128:
+		return !v.IsZero(), nil
 	default:
 		msg := fmt.Sprintf(
 			`BSON field '%s' is the wrong type '%s', expected types '[bool, long, int, decimal, double]'`,
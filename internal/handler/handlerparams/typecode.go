@@ -29,7 +29,7 @@ import (
 // TypeCode represents BSON type codes.
 // BSON type codes represent corresponding codes in BSON specification.
 // They could be used to query fields with particular type values using $type operator.
-// Type code `number` is added to support MongoDB surrogate alias `number` which matches double, int and long type values.
+// Type code `number` is added to support MongoDB surrogate alias `number` which matches double, int, long and decimal type values.
 type TypeCode int32
 
 const (
@@ -59,17 +59,17 @@ const (
 	TypeCodeTimestamp = TypeCode(17) // timestamp
 	// TypeCodeLong is a long type code.
 	TypeCodeLong = TypeCode(18) // long
+	// TypeCodeDecimal is a decimal type code.
+	TypeCodeDecimal = TypeCode(19) // decimal
 
 	// Not implemented.
 
-	// TypeCodeDecimal is a decimal type code.
-	TypeCodeDecimal = TypeCode(19) // decimal
 	// TypeCodeMinKey is a minKey type code.
 	TypeCodeMinKey = TypeCode(-1) // minKey
 	// TypeCodeMaxKey is a maxKey type code.
 	TypeCodeMaxKey = TypeCode(127) // maxKey
 
-	// Not actual type code. `number` matches double, int and long.
+	// Not actual type code. `number` matches double, int, long and decimal.
 
 	// TypeCodeNumber is a number type code.
 	TypeCodeNumber = TypeCode(-128) // number
@@ -81,9 +81,9 @@ func NewTypeCode(code int32) (TypeCode, error) {
 	switch c {
 	case TypeCodeDouble, TypeCodeString, TypeCodeObject, TypeCodeArray,
 		TypeCodeBinData, TypeCodeObjectID, TypeCodeBool, TypeCodeDate,
-		TypeCodeNull, TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeNumber:
+		TypeCodeNull, TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeDecimal, TypeCodeNumber:
 		return c, nil
-	case TypeCodeDecimal, TypeCodeMinKey, TypeCodeMaxKey:
+	case TypeCodeMinKey, TypeCodeMaxKey:
 		return 0, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrNotImplemented,
 			fmt.Sprintf(`Type code %v not implemented`, code),
@@ -134,7 +134,7 @@ func init() {
 	for _, i := range []TypeCode{
 		TypeCodeDouble, TypeCodeString, TypeCodeObject, TypeCodeArray,
 		TypeCodeBinData, TypeCodeObjectID, TypeCodeBool, TypeCodeDate, TypeCodeNull,
-		TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeNumber,
+		TypeCodeRegex, TypeCodeInt, TypeCodeTimestamp, TypeCodeLong, TypeCodeDecimal, TypeCodeNumber,
 	} {
 		aliasToTypeCode[i.String()] = i
 	}
@@ -169,6 +169,8 @@ func AliasFromType(v any) string {
 		return TypeCodeTimestamp.String()
 	case int64:
 		return TypeCodeLong.String()
+	case types.Decimal128:
+		return TypeCodeDecimal.String()
 	default:
 		panic(fmt.Sprintf("not supported type %T", v))
 	}
@@ -183,6 +185,8 @@ func isWholeNumber(v any) bool {
 		return true
 	case int64:
 		return true
+	case types.Decimal128:
+		return !v.IsNaN() && !v.IsInf(0) && v.Rat().IsInt()
 	default:
 		return false
 	}
@@ -0,0 +1,70 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
+	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+	"github.com/FerretDB/FerretDB/internal/util/testutil/testtb"
+)
+
+// newTestHandler returns a new handler for tests.
+//
+// Unset Backend, L, ConnMetrics, StateProvider, and BatchSize options are filled with defaults;
+// the memory backend is used if no backend is given.
+// The handler and the created backend are closed when the test finishes;
+// the given backend should be closed by the caller.
+func newTestHandler(tb testtb.TB, opts *NewOpts) *Handler {
+	tb.Helper()
+
+	if opts.L == nil {
+		opts.L = testutil.Logger(tb)
+	}
+
+	if opts.ConnMetrics == nil {
+		opts.ConnMetrics = connmetrics.NewListenerMetrics().ConnMetrics
+	}
+
+	if opts.StateProvider == nil {
+		sp, err := state.NewProvider("")
+		require.NoError(tb, err)
+
+		opts.StateProvider = sp
+	}
+
+	if opts.BatchSize == 0 {
+		opts.BatchSize = 100
+	}
+
+	if opts.Backend == nil {
+		b, err := memory.NewBackend(&memory.NewBackendParams{L: opts.L, P: opts.StateProvider})
+		require.NoError(tb, err)
+
+		tb.Cleanup(b.Close)
+
+		opts.Backend = b
+	}
+
+	h, err := New(opts)
+	require.NoError(tb, err)
+
+	tb.Cleanup(h.Close)
+
+	return h
+}
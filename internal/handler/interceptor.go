@@ -0,0 +1,214 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/timeout"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+)
+
+// CommandFunc handles a single command.
+//
+// The passed context is canceled when the client disconnects.
+type CommandFunc func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error)
+
+// CommandInfo describes the command passed to interceptors.
+type CommandInfo struct {
+	// Name is the command name, for example, `find`.
+	Name string
+
+	// Anonymous indicates that the command does not require authentication.
+	Anonymous bool
+
+	// AdminOnly indicates that the command affects the whole server and should be run against the admin database.
+	AdminOnly bool
+
+	// Write indicates that the command could modify data.
+	Write bool
+}
+
+// Interceptor is a part of the command handling chain.
+//
+// It may inspect or rewrite the request before calling next,
+// return a response or an error without calling next at all,
+// or inspect and replace the response returned by next.
+type Interceptor func(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error)
+
+// Use registers interceptors for all commands.
+//
+// Interceptors are called in the registration order before built-in ones
+// (backend timeouts, authentication, tenant isolation, read-only mode, sessions, operation tracking, and results cache),
+// so they see all requests, including unauthenticated ones.
+// It is safe to call Use concurrently with command handling,
+// but requests that are already being handled are not affected.
+func (h *Handler) Use(interceptors ...Interceptor) {
+	h.interceptorsM.Lock()
+	defer h.interceptorsM.Unlock()
+
+	// make a copy so running chains are not affected
+	h.interceptors = append(slices.Clip(h.interceptors), interceptors...)
+}
+
+// intercept returns a function that passes command through the interceptors chain to the given handler.
+func (h *Handler) intercept(info *CommandInfo, handler CommandFunc) CommandFunc {
+	return func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		h.interceptorsM.RLock()
+		interceptors := h.interceptors
+		h.interceptorsM.RUnlock()
+
+		return callInterceptors(ctx, info, msg, interceptors, h.builtinInterceptors, handler)
+	}
+}
+
+// callInterceptors calls the first interceptor with the rest of the chain as next.
+func callInterceptors(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, interceptors, builtin []Interceptor, handler CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if len(interceptors) == 0 {
+		if len(builtin) == 0 {
+			return handler(ctx, msg)
+		}
+
+		interceptors, builtin = builtin, nil
+	}
+
+	next := func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		return callInterceptors(ctx, info, msg, interceptors[1:], builtin, handler)
+	}
+
+	return interceptors[0](ctx, info, msg, next)
+}
+
+// initBuiltinInterceptors initializes built-in interceptors in the order they are called.
+func (h *Handler) initBuiltinInterceptors() {
+	if h.BackendTimeout > 0 {
+		h.builtinInterceptors = append(h.builtinInterceptors, h.backendTimeoutInterceptor)
+	}
+
+	if h.EnableNewAuth {
+		h.builtinInterceptors = append(h.builtinInterceptors, h.authInterceptor)
+	} else {
+		h.builtinInterceptors = append(h.builtinInterceptors, h.backendAuthInterceptor)
+	}
+
+	if len(h.TenantPrefixes) > 0 {
+		h.builtinInterceptors = append(h.builtinInterceptors, h.tenantInterceptor)
+	}
+
+	h.builtinInterceptors = append(h.builtinInterceptors, h.readOnlyInterceptor, h.sessionInterceptor, h.operationsInterceptor)
+
+	if h.resultCache != nil {
+		h.builtinInterceptors = append(h.builtinInterceptors, h.resultCacheInterceptor)
+	}
+}
+
+// authInterceptor checks that the connection is authenticated for non-anonymous commands.
+func (h *Handler) authInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if !info.Anonymous {
+		if err := checkSCRAMConversation(ctx, info.Name, h.L); err != nil {
+			resp, ok, excErr := h.localhostException(ctx, info, msg, next)
+			if ok || excErr != nil {
+				return resp, excErr
+			}
+
+			return nil, err
+		}
+	}
+
+	return next(ctx, msg)
+}
+
+// backendAuthInterceptor tracks authentication attempts with the PLAIN mechanism.
+//
+// Credentials passed with that mechanism are checked by the backend on the first command
+// that accesses it, so failed attempts are recorded there.
+func (h *Handler) backendAuthInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	res, err := next(ctx, msg)
+
+	connInfo := conninfo.Get(ctx)
+	if connInfo.BypassBackendAuth() {
+		return res, err
+	}
+
+	username, _, _, dbName := connInfo.Auth()
+	if username == "" {
+		return res, err
+	}
+
+	if err != nil && errors.Is(err, backends.ErrAuthenticationFailed) {
+		h.authFailed(ctx, dbName, username, false)
+		return res, err
+	}
+
+	if err == nil && !info.Anonymous {
+		h.authLockout.succeeded(authLockoutKeyFor(ctx, dbName, username))
+	}
+
+	return res, err
+}
+
+// backendTimeoutInterceptor returns MaxTimeMSExpired error if the backend call exceeded the statement timeout.
+func (h *Handler) backendTimeoutInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	res, err := next(ctx, msg)
+	if err != nil && errors.Is(err, timeout.ErrTimeout) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMaxTimeMSExpired,
+			"Executor error during "+info.Name+" command :: caused by :: operation exceeded backend time limit",
+			info.Name,
+		)
+	}
+
+	return res, err
+}
+
+// readOnlyInterceptor rejects write commands in read-only mode.
+func (h *Handler) readOnlyInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	// setParameter should be allowed to disable read-only mode
+	if info.Write && info.Name != "setParameter" && h.readOnly.Load() {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotWritablePrimary,
+			"not primary: FerretDB is running in read-only mode",
+			info.Name,
+		)
+	}
+
+	return next(ctx, msg)
+}
+
+// sessionInterceptor refreshes the logical session used by the command.
+func (h *Handler) sessionInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if err := h.refreshSession(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	return next(ctx, msg)
+}
+
+// operationsInterceptor tracks the command as the current operation and logs it if it was slow.
+func (h *Handler) operationsInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	op := h.operations.start(ctx, info.Name, msg)
+	defer func() {
+		h.operations.finish(op)
+		h.logSlowOperation(ctx, op)
+	}()
+
+	return next(ctx, msg)
+}
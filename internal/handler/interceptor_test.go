@@ -0,0 +1,118 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	h := newTestHandler(t, &NewOpts{
+		ReadOnly: true,
+	})
+
+	var calls []string
+
+	record := func(prefix string) Interceptor {
+		return func(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) {
+			calls = append(calls, prefix+" "+info.Name)
+			return next(ctx, msg)
+		}
+	}
+
+	errDenied := errors.New("denied")
+
+	h.Use(record("first"), record("second"))
+	h.Use(func(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) {
+		switch info.Name {
+		case "dropDatabase":
+			return nil, errDenied
+
+		case "buildInfo":
+			// rewrite response
+			return wire.NewOpMsg(must.NotFail(wirebson.NewDocument("ok", float64(1), "intercepted", true)))
+
+		default:
+			return next(ctx, msg)
+		}
+	})
+
+	handle := func(command string) (*wirebson.Document, error) {
+		res, err := h.Commands()[command].Handler(ctx, wire.MustOpMsg(command, int32(1), "$db", "admin"))
+		if err != nil {
+			return nil, err
+		}
+
+		return must.NotFail(res.RawSection0().DecodeDeep()), nil
+	}
+
+	t.Run("Order", func(t *testing.T) {
+		calls = nil
+
+		res, err := handle("ping")
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), res.Get("ok"))
+		assert.Equal(t, []string{"first ping", "second ping"}, calls)
+	})
+
+	t.Run("ShortCircuit", func(t *testing.T) {
+		calls = nil
+
+		_, err := handle("dropDatabase")
+		assert.Equal(t, errDenied, err)
+		assert.Equal(t, []string{"first dropDatabase", "second dropDatabase"}, calls)
+	})
+
+	t.Run("Response", func(t *testing.T) {
+		res, err := handle("buildInfo")
+		require.NoError(t, err)
+		assert.Equal(t, true, res.Get("intercepted"))
+	})
+
+	t.Run("Builtin", func(t *testing.T) {
+		calls = nil
+
+		_, err := handle("create")
+
+		var cmdErr *handlererrors.CommandError
+		require.ErrorAs(t, err, &cmdErr)
+		assert.Equal(t, handlererrors.ErrNotWritablePrimary, cmdErr.Code())
+		assert.Equal(t, []string{"first create", "second create"}, calls, "registered interceptors run before built-in ones")
+	})
+
+	t.Run("ReadOnlyAllowed", func(t *testing.T) {
+		for _, command := range []string{"getLastError", "getlasterror", "debugError"} {
+			res, err := h.Commands()[command].Handler(ctx, wire.MustOpMsg(command, "ok", "$db", "admin"))
+			require.NoError(t, err, command)
+			assert.Equal(t, float64(1), must.NotFail(res.RawSection0().DecodeDeep()).Get("ok"), command)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// localhostException handles unauthenticated command that failed authentication check.
+//
+// Like MongoDB, it allows unauthenticated clients connected over a Unix socket or from the loopback address
+// to create the first user in the admin database.
+// The exception is closed as soon as any user exists.
+//
+// It returns false if the exception does not apply;
+// the caller should return the original authentication error in that case.
+func (h *Handler) localhostException(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, bool, error) { //nolint:lll // for readability
+	if info.Name != "createUser" {
+		return nil, false, nil
+	}
+
+	connInfo := conninfo.Get(ctx)
+	if !connInfo.LocalPeer() {
+		return nil, false, nil
+	}
+
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	if db, _ := document.Get("$db"); db != "admin" {
+		return nil, false, nil
+	}
+
+	// prevent concurrent requests from creating several users
+	h.localhostExceptionM.Lock()
+	defer h.localhostExceptionM.Unlock()
+
+	// users are checked without backend authentication as the client has no credentials yet
+	bgConnInfo := conninfo.New()
+	bgConnInfo.Peer = connInfo.Peer
+	bgConnInfo.SetBypassBackendAuth()
+	ctx = conninfo.Ctx(ctx, bgConnInfo)
+
+	exist, err := h.usersExist(ctx)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	if exist {
+		return nil, false, nil
+	}
+
+	h.L.InfoContext(ctx, "Creating the first user using localhost exception", slog.String("peer", connInfo.Peer.String()))
+
+	resp, err := next(ctx, msg)
+
+	return resp, true, err
+}
+
+// usersExist returns true if at least one user exists.
+func (h *Handler) usersExist(ctx context.Context) (bool, error) {
+	db, err := h.b.Database("admin")
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	coll, err := db.Collection("system.users")
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	qr, err := coll.Query(ctx, &backends.QueryParams{Limit: 1})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	_, _, err = qr.Iter.Next()
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, iterator.ErrIteratorDone):
+		return false, nil
+	default:
+		return false, lazyerrors.Error(err)
+	}
+}
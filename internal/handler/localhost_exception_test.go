@@ -0,0 +1,77 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestLocalhostException(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler(t, &NewOpts{
+		EnableNewAuth: true,
+	})
+
+	peerCtx := func(peer string) context.Context {
+		connInfo := conninfo.New()
+		if peer != "" {
+			connInfo.Peer = netip.MustParseAddrPort(peer)
+		}
+
+		return conninfo.Ctx(testutil.Ctx(t), connInfo)
+	}
+
+	createUser := func(ctx context.Context, username, db string) error {
+		msg := wire.MustOpMsg(
+			"createUser", username,
+			"roles", wirebson.MustArray(),
+			"pwd", "password",
+			"$db", db,
+		)
+
+		_, err := h.Commands()["createUser"].Handler(ctx, msg)
+
+		return err
+	}
+
+	assertUnauthorized := func(t *testing.T, err error) {
+		t.Helper()
+
+		var cmdErr *handlererrors.CommandError
+		require.ErrorAs(t, err, &cmdErr)
+		assert.Equal(t, handlererrors.ErrUnauthorized, cmdErr.Code())
+	}
+
+	assertUnauthorized(t, createUser(peerCtx("192.0.2.1:12345"), "remote", "admin"))
+	assertUnauthorized(t, createUser(peerCtx("127.0.0.1:12345"), "other", "test"))
+
+	require.NoError(t, createUser(peerCtx("127.0.0.1:12345"), "first", "admin"))
+
+	// exception is closed after the first user is created
+	assertUnauthorized(t, createUser(peerCtx("127.0.0.1:12345"), "second", "admin"))
+	assertUnauthorized(t, createUser(peerCtx(""), "second", "admin"))
+}
@@ -0,0 +1,339 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrations provides versioned forward migrations of FerretDB-managed metadata
+// stored in regular collections, such as users in `admin.system.users`.
+//
+// The current metadata version is stored in the `admin.system.version` collection
+// (the same collection MongoDB uses for its `authSchema` version).
+// Migrations are applied in order, and the version is updated after each one,
+// so an interrupted run continues from the first not yet applied migration.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+const (
+	// Database of FerretDB-managed metadata collections.
+	adminDB = "admin"
+
+	// Collection and document _id where the metadata version is stored.
+	versionCollection = "system.version"
+	versionID         = "ferretdbMetadata"
+)
+
+// ErrNewerVersion is returned when the stored metadata version is newer than the latest known one,
+// for example, after a downgrade of FerretDB.
+var ErrNewerVersion = errors.New("metadata version is newer than supported")
+
+// Migration describes a single metadata migration.
+type Migration struct {
+	Version     int32
+	Description string
+
+	// up applies the migration.
+	// It should be idempotent, as migrations could be re-applied after a failure
+	// or by multiple FerretDB instances sharing the same backend.
+	up func(ctx context.Context, b backends.Backend) error
+}
+
+// all contains all migrations ordered by version.
+//
+// Versions start from 1 and have no gaps.
+// New migrations should only be appended.
+var all = []Migration{{
+	Version:     1,
+	Description: "add missing roles and userId fields to users",
+	up:          addUsersFields,
+}, {
+	Version:     2,
+	Description: "create unique index on user and db fields of users",
+	up:          createUsersIndex,
+}}
+
+// Latest returns the latest known metadata version.
+func Latest() int32 {
+	return all[len(all)-1].Version
+}
+
+// RunParams represents the parameters of Run function.
+type RunParams struct {
+	// DryRun reports pending migrations without applying them.
+	DryRun bool
+
+	L *slog.Logger
+}
+
+// Run applies pending migrations and returns them.
+// In dry-run mode, pending migrations are returned but not applied.
+//
+// If the admin database does not exist, there is no metadata yet, and nothing is done.
+// If the stored version is newer than the latest known one, [ErrNewerVersion] is returned.
+func Run(ctx context.Context, b backends.Backend, params *RunParams) ([]Migration, error) {
+	must.NotBeZero(params)
+
+	dbs, err := b.ListDatabases(ctx, &backends.ListDatabasesParams{Name: adminDB})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(dbs.Databases) == 0 {
+		params.L.DebugContext(ctx, "No metadata to migrate")
+		return nil, nil
+	}
+
+	current, err := Current(ctx, b)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if current > Latest() {
+		return nil, fmt.Errorf("%w: %d > %d", ErrNewerVersion, current, Latest())
+	}
+
+	pending := all[current:]
+
+	if params.DryRun {
+		return pending, nil
+	}
+
+	for _, m := range pending {
+		params.L.InfoContext(
+			ctx, "Applying metadata migration",
+			slog.Int("version", int(m.Version)), slog.String("description", m.Description),
+		)
+
+		if err = m.up(ctx, b); err != nil {
+			return nil, lazyerrors.Errorf("migration %d: %w", m.Version, err)
+		}
+
+		if err = setCurrent(ctx, b, m.Version); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	return pending, nil
+}
+
+// Current returns the stored metadata version, or 0 if it is not stored yet.
+func Current(ctx context.Context, b backends.Backend) (int32, error) {
+	doc, err := getVersionDocument(ctx, b)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	if doc == nil {
+		return 0, nil
+	}
+
+	v, err := doc.Get("currentVersion")
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	version, ok := v.(int32)
+	if !ok {
+		return 0, lazyerrors.Errorf("unexpected metadata version type %T", v)
+	}
+
+	return version, nil
+}
+
+// getVersionDocument returns the document with the stored metadata version, or nil if there is none.
+func getVersionDocument(ctx context.Context, b backends.Backend) (*types.Document, error) {
+	coll, err := versionColl(b)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := coll.Query(ctx, &backends.QueryParams{
+		Filter: must.NotFail(types.NewDocument("_id", versionID)),
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	for {
+		var doc *types.Document
+
+		_, doc, err = res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// the filter may be ignored by the backend
+		if id, _ := doc.Get("_id"); id == versionID {
+			return doc, nil
+		}
+	}
+}
+
+// setCurrent stores the given metadata version.
+func setCurrent(ctx context.Context, b backends.Backend, version int32) error {
+	coll, err := versionColl(b)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	doc, err := getVersionDocument(ctx, b)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if doc == nil {
+		_, err = coll.InsertAll(ctx, &backends.InsertAllParams{
+			Docs: []*types.Document{must.NotFail(types.NewDocument("_id", versionID, "currentVersion", version))},
+		})
+	} else {
+		doc = doc.DeepCopy()
+		doc.Set("currentVersion", version)
+
+		_, err = coll.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
+	}
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// versionColl returns the collection where the metadata version is stored.
+func versionColl(b backends.Backend) (backends.Collection, error) {
+	db, err := b.Database(adminDB)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return db.Collection(versionCollection)
+}
+
+// usersColl returns the users collection and whether it exists.
+func usersColl(ctx context.Context, b backends.Backend) (backends.Collection, bool, error) {
+	db, err := b.Database(adminDB)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	list, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: "system.users"})
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	coll, err := db.Collection("system.users")
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	return coll, len(list.Collections) > 0, nil
+}
+
+// addUsersFields adds empty roles and random userId to users created without them.
+func addUsersFields(ctx context.Context, b backends.Backend) error {
+	coll, exists, err := usersColl(ctx, b)
+	if err != nil || !exists {
+		return err
+	}
+
+	res, err := coll.Query(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	var docs []*types.Document
+
+	for {
+		var doc *types.Document
+
+		_, doc, err = res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if doc.Has("roles") && doc.Has("userId") {
+			continue
+		}
+
+		doc = doc.DeepCopy()
+
+		if !doc.Has("roles") {
+			doc.Set("roles", types.MakeArray(0))
+		}
+
+		if !doc.Has("userId") {
+			id := uuid.New()
+			doc.Set("userId", types.Binary{Subtype: types.BinaryUUID, B: must.NotFail(id.MarshalBinary())})
+		}
+
+		docs = append(docs, doc)
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if _, err = coll.UpdateAll(ctx, &backends.UpdateAllParams{Docs: docs}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// createUsersIndex creates the same unique index on users as MongoDB does.
+func createUsersIndex(ctx context.Context, b backends.Backend) error {
+	coll, exists, err := usersColl(ctx, b)
+	if err != nil || !exists {
+		return err
+	}
+
+	_, err = coll.CreateIndexes(ctx, &backends.CreateIndexesParams{
+		Indexes: []backends.IndexInfo{{
+			Name: "user_1_db_1",
+			Key: []backends.IndexKeyPair{
+				{Field: "user"},
+				{Field: "db"},
+			},
+			Unique: true,
+		}},
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
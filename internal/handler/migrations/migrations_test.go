@@ -0,0 +1,145 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// testBackend returns a new SQLite backend for testing.
+func testBackend(t *testing.T) backends.Backend {
+	t.Helper()
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := sqlite.NewBackend(&sqlite.NewBackendParams{
+		URI:       testutil.TestSQLiteURI(t, ""),
+		L:         testutil.Logger(t),
+		P:         sp,
+		BatchSize: 100,
+	})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	return b
+}
+
+func TestVersions(t *testing.T) {
+	t.Parallel()
+
+	for i, m := range all {
+		assert.Equal(t, int32(i+1), m.Version)
+		assert.NotEmpty(t, m.Description)
+		assert.NotNil(t, m.up)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	params := &RunParams{L: testutil.Logger(t)}
+
+	t.Run("NoMetadata", func(t *testing.T) {
+		t.Parallel()
+
+		b := testBackend(t)
+
+		applied, err := Run(ctx, b, params)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+
+		res, err := b.ListDatabases(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, res.Databases)
+	})
+
+	t.Run("Users", func(t *testing.T) {
+		t.Parallel()
+
+		b := testBackend(t)
+
+		users := must.NotFail(must.NotFail(b.Database(adminDB)).Collection("system.users"))
+		_, err := users.InsertAll(ctx, &backends.InsertAllParams{
+			Docs: []*types.Document{must.NotFail(types.NewDocument(
+				"_id", "test.user",
+				"user", "user",
+				"db", "test",
+			))},
+		})
+		require.NoError(t, err)
+
+		pending, err := Run(ctx, b, &RunParams{DryRun: true, L: params.L})
+		require.NoError(t, err)
+		assert.Equal(t, all, pending)
+
+		current, err := Current(ctx, b)
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), current)
+
+		applied, err := Run(ctx, b, params)
+		require.NoError(t, err)
+		assert.Equal(t, all, applied)
+
+		current, err = Current(ctx, b)
+		require.NoError(t, err)
+		assert.Equal(t, Latest(), current)
+
+		res, err := users.Query(ctx, nil)
+		require.NoError(t, err)
+
+		docs, err := iterator.ConsumeValues(iterator.Interface[struct{}, *types.Document](res.Iter))
+		require.NoError(t, err)
+		require.Len(t, docs, 1)
+		assert.True(t, docs[0].Has("roles"))
+		assert.True(t, docs[0].Has("userId"))
+
+		indexes, err := users.ListIndexes(ctx, nil)
+		require.NoError(t, err)
+		assert.Contains(t, indexes.Indexes, backends.IndexInfo{
+			Name:   "user_1_db_1",
+			Key:    []backends.IndexKeyPair{{Field: "user"}, {Field: "db"}},
+			Unique: true,
+		})
+
+		applied, err = Run(ctx, b, params)
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+	})
+
+	t.Run("NewerVersion", func(t *testing.T) {
+		t.Parallel()
+
+		b := testBackend(t)
+
+		require.NoError(t, setCurrent(ctx, b, Latest()+1))
+
+		_, err := Run(ctx, b, params)
+		require.ErrorIs(t, err, ErrNewerVersion)
+	})
+}
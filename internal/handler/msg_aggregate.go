@@ -21,6 +21,7 @@ import (
 	"log/slog"
 	"math"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -49,15 +50,20 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
-	common.Ignored(document, h.L, "lsid")
+	lsidV, _ := document.Get("lsid")
 
-	if err = common.Unimplemented(document, "explain", "collation", "let"); err != nil {
+	lsid, err := optionalSessionID(lsidV)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = common.Unimplemented(document, "explain", "collation"); err != nil {
 		return nil, err
 	}
 
 	common.Ignored(
 		document, h.L,
-		"allowDiskUse", "bypassDocumentValidation", "readConcern", "hint", "comment", "writeConcern",
+		"bypassDocumentValidation", "readConcern", "comment", "writeConcern",
 	)
 
 	var dbName string
@@ -71,17 +77,22 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
-	// handle collection-agnostic pipelines ({aggregate: 1})
+	// handle collection-agnostic pipelines ({aggregate: 1}); only $currentOp is supported
 	// TODO https://github.com/FerretDB/FerretDB/issues/1890
-	var ok bool
+	var ok, agnostic bool
 	var cName string
 
 	if cName, ok = collectionParam.(string); !ok {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrFailedToParse,
-			"Invalid command format: the 'aggregate' field must specify a collection name or 1",
-			document.Command(),
-		)
+		if n, err := handlerparams.GetWholeNumberParam(collectionParam); err != nil || n != 1 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				"Invalid command format: the 'aggregate' field must specify a collection name or 1",
+				document.Command(),
+			)
+		}
+
+		agnostic = true
+		cName = "$cmd.aggregate"
 	}
 
 	db, err := h.b.Database(dbName)
@@ -94,14 +105,17 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, lazyerrors.Error(err)
 	}
 
-	c, err := db.Collection(cName)
-	if err != nil {
-		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
-			msg := fmt.Sprintf("Invalid collection name: %s", cName)
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
-		}
+	var c backends.Collection
 
-		return nil, lazyerrors.Error(err)
+	if !agnostic {
+		if c, err = db.Collection(cName); err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+				msg := fmt.Sprintf("Invalid collection name: %s", cName)
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
 	username := conninfo.Get(connCtx).Username()
@@ -180,9 +194,30 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		)
 	}
 
+	var let *types.Document
+
+	if v, _ = document.Get("let"); v != nil {
+		if let, ok = v.(*types.Document); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field 'aggregate.let' is the wrong type '%s', expected type 'object'",
+					handlerparams.AliasFromType(v),
+				),
+				document.Command(),
+			)
+		}
+	}
+
+	vars, err := common.GetLetVariables(document.Command(), let)
+	if err != nil {
+		return nil, err
+	}
+
 	aggregationStages := must.NotFail(iterator.ConsumeValues(pipeline.Iterator()))
 	stagesDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
 	collStatsDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
+	var currentOp bool
 
 	for i, v := range aggregationStages {
 		var d *types.Document
@@ -197,6 +232,8 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 
 		var s aggregations.Stage
 
+		d = common.LetStage(d, vars)
+
 		if s, err = stages.NewStage(d); err != nil {
 			return nil, err
 		}
@@ -212,12 +249,43 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 			}
 
 			collStatsDocuments = append(collStatsDocuments, s)
+		case "$currentOp":
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCollStatsIsNotFirstStage,
+					"$currentOp is only valid as the first stage in a pipeline",
+					document.Command(),
+				)
+			}
+
+			if !agnostic || dbName != "admin" {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidNamespace,
+					"$currentOp must be run against the 'admin' database with {aggregate: 1}",
+					document.Command(),
+				)
+			}
+
+			currentOp = true
+			stagesDocuments = append(stagesDocuments, s)
 		default:
 			stagesDocuments = append(stagesDocuments, s)
 			collStatsDocuments = append(collStatsDocuments, s) // It's possible to apply any stage after $collStats stage
 		}
 	}
 
+	stages.SetLookupSource(stagesDocuments, lookupSource(db))
+
+	if agnostic && !currentOp {
+		msg := "{aggregate: 1} is not valid for an empty pipeline."
+		if len(aggregationStages) > 0 {
+			stage := aggregationStages[0].(*types.Document).Command()
+			msg = fmt.Sprintf("{aggregate: 1} is not valid for '%s'; a collection is required.", stage)
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
+	}
+
 	// validate cursor after validating pipeline stages to keep compatibility
 	v, _ = document.Get("cursor")
 	if v == nil {
@@ -250,7 +318,22 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
-	ctx := connCtx
+	var allowDiskUse bool
+
+	if v, _ := document.Get("allowDiskUse"); v != nil {
+		if allowDiskUse, err = handlerparams.GetBoolOptionalParam("allowDiskUse", v); err != nil {
+			return nil, err
+		}
+	}
+
+	hint, _ := document.Get("hint")
+
+	naturalHint, err := common.GetNaturalHint(hint, document.Command())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := common.WithMemoryTracker(connCtx, common.NewMemoryTracker(h.OperationMemoryLimit, allowDiskUse))
 	cancel := func() {}
 
 	// TODO https://github.com/FerretDB/FerretDB/issues/2983
@@ -276,7 +359,19 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 
 	var iter iterator.Interface[struct{}, *types.Document]
 
-	if len(collStatsDocuments) == len(stagesDocuments) {
+	switch {
+	case currentOp:
+		allUsers := stages.CurrentOpAllUsers(stagesDocuments)
+		iter, err = processStagesCurrentOp(ctx, closer, h.currentOpDocuments(connCtx, allUsers), stagesDocuments)
+
+	case len(collStatsDocuments) == len(stagesDocuments):
+		if !h.DisablePushdown && naturalHint == nil {
+			// count grouped documents in the backend if possible, fallback to regular processing otherwise
+			if iter, err = processGroupCountPushdown(ctx, closer, c, aggregationStages); iter != nil || err != nil {
+				break
+			}
+		}
+
 		filter, sort := aggregations.GetPushdownQuery(aggregationStages)
 
 		// only documents stages or no stages - fetch documents from the DB and apply stages to them
@@ -327,31 +422,24 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 			cInfo = cList.Collections[0]
 		}
 
+		if sort.Len() == 0 && naturalHint != nil {
+			sort = naturalHint
+		}
+
 		switch {
+		case sort.Len() == 1 && sort.Keys()[0] == "$natural":
+			// Natural order is known only to the backend, so it is pushed down even if pushdown is disabled
+			qp.Sort = sort
 		case h.DisablePushdown:
 			// Pushdown disabled
 		case sort.Len() == 0 && cInfo.Capped():
 			// Pushdown default recordID sorting for capped collections
 			qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
-		case sort.Len() == 1:
-			if sort.Keys()[0] != "$natural" {
-				break
-			}
-
-			if !cInfo.Capped() {
-				closer.Close()
-				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrNotImplemented,
-					"$natural sort for non-capped collection is not supported.",
-					"aggregate",
-				)
-			}
-
-			qp.Sort = sort
 		}
 
 		iter, err = processStagesDocuments(ctx, closer, &stagesDocumentsParams{c, qp, stagesDocuments})
-	} else {
+
+	default:
 		// TODO https://github.com/FerretDB/FerretDB/issues/2423
 		statistics := stages.GetStatistics(collStatsDocuments)
 
@@ -371,6 +459,7 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		DB:         dbName,
 		Collection: cName,
 		Username:   username,
+		Session:    lsid,
 		Type:       cursor.Normal,
 	})
 
@@ -400,6 +489,8 @@ func (h *Handler) MsgAggregate(connCtx context.Context, msg *wire.OpMsg) (*wire.
 		cursorID = 0
 
 		cursor.Close()
+	} else {
+		cursor.Readahead(int(batchSize))
 	}
 
 	return documentOpMsg(
@@ -442,6 +533,106 @@ func processStagesDocuments(ctx context.Context, closer *iterator.MultiCloser, p
 	return iter, nil
 }
 
+// processGroupCountPushdown counts documents grouped by a top-level field in the backend
+// for pipelines supported by [aggregations.GetGroupCountPushdown].
+//
+// It returns nil iterator and nil error if the pipeline or its filter can't be pushed down.
+func processGroupCountPushdown(ctx context.Context, closer *iterator.MultiCloser, c backends.Collection, stagesDocs []any) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	pushdown := aggregations.GetGroupCountPushdown(stagesDocs)
+	if pushdown == nil {
+		return nil, nil
+	}
+
+	res, err := c.GroupCount(ctx, &backends.GroupCountParams{
+		Filter:  pushdown.Filter,
+		GroupBy: pushdown.GroupBy,
+	})
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeGroupCountNotSupported) {
+			return nil, nil
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	// backend may return separate groups for equal IDs of different types (like 1 and 1.0);
+	// sort them to merge adjacent groups, like $group stage does
+	slices.SortStableFunc(res.Groups, func(a, b backends.GroupCount) int {
+		switch types.CompareForAggregation(a.ID, b.ID) {
+		case types.Less:
+			return -1
+		case types.Greater:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	docs := make([]*types.Document, 0, len(res.Groups))
+
+	for i := 0; i < len(res.Groups); {
+		group := res.Groups[i]
+
+		for i++; i < len(res.Groups); i++ {
+			if types.CompareForAggregation(group.ID, res.Groups[i].ID) != types.Equal {
+				break
+			}
+
+			group.Count += res.Groups[i].Count
+		}
+
+		var count any = group.Count
+		if group.Count <= math.MaxInt32 {
+			count = int32(group.Count)
+		}
+
+		docs = append(docs, must.NotFail(types.NewDocument("_id", group.ID, pushdown.Field, count)))
+	}
+
+	iter := iterator.Values(iterator.ForSlice(docs))
+	closer.Add(iter)
+
+	return iter, nil
+}
+
+// processStagesCurrentOp processes the given documents of operations in progress through the stages.
+func processStagesCurrentOp(ctx context.Context, closer *iterator.MultiCloser, docs []*types.Document, stages []aggregations.Stage) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	iter := iterator.Values(iterator.ForSlice(docs))
+	closer.Add(iter)
+
+	var err error
+
+	for _, s := range stages {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iter, nil
+}
+
+// lookupSource returns the source of foreign documents for $lookup stages of the given database.
+func lookupSource(db backends.Database) stages.LookupSource {
+	return func(ctx context.Context, collection string) (types.DocumentsIterator, error) {
+		c, err := db.Collection(collection)
+		if err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+				msg := fmt.Sprintf("Invalid collection name: %s", collection)
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "$lookup (stage)")
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		qr, err := c.Query(ctx, nil)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		return qr.Iter, nil
+	}
+}
+
 // stagesStatsParams contains the parameters for processStagesStats.
 type stagesStatsParams struct {
 	c          backends.Collection
@@ -0,0 +1,102 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgAnalyze implements `analyze` command.
+//
+// It runs backend's ANALYZE on the collection's table,
+// so the planner statistics and count estimates are refreshed.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgAnalyze(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// backends analyze all fields with their own sampling
+	common.Ignored(document, h.L, "key", "sampleRate", "sampleSize", "numberBuckets", "writeConcern", "comment")
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, collection),
+				command,
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(collection)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, collection),
+				command,
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	_, err = c.Stats(connCtx, &backends.CollectionStatsParams{Refresh: true})
+	if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceNotFound,
+			fmt.Sprintf("Couldn't find collection %s.%s", dbName, collection),
+			command,
+		)
+	}
+
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/oplog"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgAppendOplogNote implements `appendOplogNote` command.
+//
+// It writes a no-op record with the given data to the OpLog collection.
+// If `maxClusterTime` is set, the logical clock is advanced past it first,
+// so the record marks a consistent cut point for backup tools.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgAppendOplogNote(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	command := document.Command()
+
+	common.Ignored(document, h.L, "comment")
+
+	v, _ := document.Get("data")
+	if v == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			fmt.Sprintf("BSON field '%s.data' is missing but a required field", command),
+			command,
+		)
+	}
+
+	data, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.data' is the wrong type '%s', expected type 'object'",
+				command, handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	var maxClusterTime types.Timestamp
+
+	if v, _ = document.Get("maxClusterTime"); v != nil {
+		if maxClusterTime, ok = v.(types.Timestamp); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.maxClusterTime' is the wrong type '%s', expected type 'timestamp'",
+					command, handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+	}
+
+	// see oplog decorator
+	const oplogDB, oplogCollection = "local", "oplog.rs"
+
+	db, err := h.b.Database(oplogDB)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	list, err := db.ListCollections(connCtx, &backends.ListCollectionsParams{Name: oplogCollection})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(list.Collections) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNoReplicationEnabled,
+			fmt.Sprintf(`Must have replication set up to run "%s"`, command),
+			command,
+		)
+	}
+
+	if maxClusterTime != 0 && !types.AdvanceTimestamp(maxClusterTime) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrStaleClusterTime,
+			fmt.Sprintf(
+				"Requested maxClusterTime %s is less or equal to the last primary OpTime: %s",
+				types.FormatAnyValue(maxClusterTime), types.FormatAnyValue(types.LastTimestamp()),
+			),
+			command,
+		)
+	}
+
+	note, err := oplog.NewNote(data, time.Now())
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(oplogCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, err = c.InsertAll(connCtx, &backends.InsertAllParams{Docs: []*types.Document{note}}); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"operationTime", must.NotFail(note.Get("ts")),
+			"ok", float64(1),
+		)),
+	)
+}
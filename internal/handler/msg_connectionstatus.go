@@ -20,7 +20,9 @@ import (
 	"github.com/FerretDB/wire"
 
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
@@ -28,21 +30,47 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgConnectionStatus(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var showPrivileges bool
+
+	if v, _ := document.Get("showPrivileges"); v != nil {
+		if showPrivileges, err = handlerparams.GetBoolOptionalParam("showPrivileges", v); err != nil {
+			return nil, err
+		}
+	}
+
 	users := types.MakeArray(1)
+	privileges := types.MakeArray(1)
 
 	if username, _, _, db := conninfo.Get(connCtx).Auth(); username != "" {
 		users.Append(must.NotFail(types.NewDocument(
 			"user", username,
 			"db", db,
 		)))
+
+		// roles are not supported yet, so authenticated users are not restricted
+		privileges.Append(must.NotFail(types.NewDocument(
+			"resource", must.NotFail(types.NewDocument("anyResource", true)),
+			"actions", must.NotFail(types.NewArray("anyAction")),
+		)))
+	}
+
+	authInfo := must.NotFail(types.NewDocument(
+		"authenticatedUsers", users,
+		"authenticatedUserRoles", must.NotFail(types.NewArray()),
+	))
+
+	if showPrivileges {
+		authInfo.Set("authenticatedUserPrivileges", privileges)
 	}
 
 	return documentOpMsg(
 		must.NotFail(types.NewDocument(
-			"authInfo", must.NotFail(types.NewDocument(
-				"authenticatedUsers", users,
-				"authenticatedUserRoles", must.NotFail(types.NewArray()),
-			)),
+			"authInfo", authInfo,
 			"ok", float64(1),
 		)),
 	)
@@ -0,0 +1,36 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+)
+
+// MsgCopyDB implements removed `copydb` command.
+//
+// It always returns an error that explains how to copy a database instead.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgCopyDB(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrCommandNotFound,
+		"copydb was removed in MongoDB 4.2; use mongodump and mongorestore to copy a database",
+		"copydb",
+	)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/FerretDB/wire"
 
@@ -64,7 +65,39 @@ func (h *Handler) MsgCount(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		return nil, lazyerrors.Error(err)
 	}
 
-	var qp backends.QueryParams
+	comment, err := queryComment(connCtx, params.Comment, params.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if !h.DisablePushdown && countPushdown(params.Filter) {
+		var res *backends.CountResult
+		if res, err = c.Count(connCtx, &backends.CountParams{Comment: comment}); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		n := max(res.Count-params.Skip, 0)
+		if params.Limit != 0 {
+			n = min(n, params.Limit)
+		}
+
+		var count any = n
+		if n <= math.MaxInt32 {
+			count = int32(n)
+		}
+
+		return documentOpMsg(
+			must.NotFail(types.NewDocument(
+				"n", count,
+				"ok", float64(1),
+			)),
+		)
+	}
+
+	qp := backends.QueryParams{
+		Comment: comment,
+	}
+
 	if !h.DisablePushdown {
 		qp.Filter = params.Filter
 	}
@@ -106,3 +139,16 @@ func (h *Handler) MsgCount(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		)),
 	)
 }
+
+// countPushdown returns true if the backend could count documents matching the given filter itself.
+//
+// Backend filter pushdown is not exact, so only filters without conditions are supported.
+func countPushdown(filter *types.Document) bool {
+	for _, k := range filter.Keys() {
+		if k != "$comment" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -16,7 +16,10 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/FerretDB/wire"
 
@@ -25,6 +28,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -54,7 +58,6 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 
 	ignoredFields := []string{
 		"autoIndexId",
-		"storageEngine",
 		"indexOptionDefaults",
 		"writeConcern",
 		"comment",
@@ -105,6 +108,21 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		}
 	}
 
+	if err = setPartitionParams(document, &params); err != nil {
+		return nil, err
+	}
+
+	if err = setStorageOptions(document, &params); err != nil {
+		return nil, err
+	}
+
+	// FerretDB-specific option, see backends.VersionField
+	if v, _ := document.Get("versioned"); v != nil {
+		if params.Versioned, err = handlerparams.GetBoolOptionalParam("versioned", v); err != nil {
+			return nil, err
+		}
+	}
+
 	db, err := h.b.Database(dbName)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -115,6 +133,14 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = h.checkDatabaseCase(connCtx, dbName, "create"); err != nil {
+		return nil, err
+	}
+
+	if err = h.checkCollectionsQuota(connCtx, db, dbName, collectionName, "create"); err != nil {
+		return nil, err
+	}
+
 	err = db.CreateCollection(connCtx, &params)
 
 	switch {
@@ -133,7 +159,286 @@ func (h *Handler) MsgCreate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		msg := fmt.Sprintf("Collection %s.%s already exists.", dbName, collectionName)
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNamespaceExists, msg, "create")
 
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionPartitioningNotSupported):
+		msg := "Partitioned collections are not supported by this backend"
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNotImplemented, msg, "create")
+
 	default:
 		return nil, lazyerrors.Error(err)
 	}
 }
+
+const (
+	// defaultPartitions is the number of partitions of hashed partitioned collection
+	// if `partitions` option is not set.
+	defaultPartitions = 8
+
+	// maxPartitions is the maximum number of partitions of partitioned collection.
+	maxPartitions = 1024
+)
+
+// setPartitionParams sets partitioning parameters from FerretDB-specific `create` command options.
+//
+// The `partitionKey` option uses the same format as a shard key,
+// but only a single top-level field is supported.
+// For hashed partitioning, `partitions` sets the number of partitions.
+// For ranged partitioning, `partitionBounds` sets split points between partitions.
+func setPartitionParams(document *types.Document, params *backends.CreateCollectionParams) error {
+	command := document.Command()
+
+	v, _ := document.Get("partitionKey")
+	if v == nil {
+		if document.Has("partitions") || document.Has("partitionBounds") {
+			msg := "the 'partitionKey' field is required when 'partitions' or 'partitionBounds' is set"
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, command)
+		}
+
+		return nil
+	}
+
+	key, ok := v.(*types.Document)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field 'create.partitionKey' is the wrong type '%s', expected type 'object'",
+				handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if err := validateShardKey(key, command); err != nil {
+		return err
+	}
+
+	if key.Len() != 1 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Compound partition keys are not supported",
+			command,
+		)
+	}
+
+	field := key.Keys()[0]
+	if strings.Contains(field, ".") {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Partition key must be a top-level field",
+			command,
+		)
+	}
+
+	if params.Capped() {
+		msg := "Capped collections cannot be partitioned"
+		return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, command)
+	}
+
+	params.PartitionKey = field
+
+	// shard key validation ensures that the value is either 1 or "hashed"
+	_, params.PartitionHashed = must.NotFail(key.Get(field)).(string)
+
+	if params.PartitionHashed {
+		if document.Has("partitionBounds") {
+			msg := "the 'partitionBounds' field can't be used with hashed partition key"
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, command)
+		}
+
+		params.Partitions = defaultPartitions
+
+		if v, _ = document.Get("partitions"); v != nil {
+			n, err := handlerparams.GetValidatedNumberParamWithMinValue(command, "partitions", v, 1)
+			if err != nil {
+				return err
+			}
+
+			if n > maxPartitions {
+				msg := fmt.Sprintf("the 'partitions' field must not be greater than %d", maxPartitions)
+				return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, command)
+			}
+
+			params.Partitions = n
+		}
+
+		return nil
+	}
+
+	if document.Has("partitions") {
+		msg := "the 'partitions' field can't be used with ranged partition key"
+		return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, command)
+	}
+
+	if v, _ = document.Get("partitionBounds"); v == nil {
+		return nil
+	}
+
+	bounds, ok := v.(*types.Array)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field 'create.partitionBounds' is the wrong type '%s', expected type 'array'",
+				handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if bounds.Len() >= maxPartitions {
+		msg := fmt.Sprintf("the 'partitionBounds' field must have less than %d values", maxPartitions)
+		return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, command)
+	}
+
+	params.PartitionBounds = make([]any, bounds.Len())
+
+	for i := range bounds.Len() {
+		b := must.NotFail(bounds.Get(i))
+
+		switch b := b.(type) {
+		case float64:
+			if math.IsNaN(b) || math.IsInf(b, 0) {
+				msg := "the 'partitionBounds' values must be finite numbers or strings"
+				return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+			}
+
+		case int32, int64, string:
+			// nothing
+
+		default:
+			msg := "the 'partitionBounds' values must be finite numbers or strings"
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+		}
+
+		if i > 0 {
+			prev := params.PartitionBounds[i-1]
+
+			// PostgreSQL orders numbers and strings differently, so they can't be mixed
+			_, prevString := prev.(string)
+			_, curString := b.(string)
+
+			if prevString != curString || types.CompareOrder(prev, b, types.Ascending) != types.Less {
+				msg := "the 'partitionBounds' values must be of the same type and in strictly ascending order"
+				return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+			}
+		}
+
+		params.PartitionBounds[i] = b
+	}
+
+	return nil
+}
+
+// setStorageOptions sets backend storage options from `storageEngine` option of `create` command.
+//
+// Like MongoDB, it accepts a document with options for each storage engine.
+// Options for engines other than `postgresql` are ignored.
+func setStorageOptions(document *types.Document, params *backends.CreateCollectionParams) error {
+	command := document.Command()
+
+	v, _ := document.Get("storageEngine")
+	if v == nil {
+		return nil
+	}
+
+	engines, ok := v.(*types.Document)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field 'create.storageEngine' is the wrong type '%s', expected type 'object'",
+				handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	var opts *types.Document
+
+	enginesIter := engines.Iterator()
+	defer enginesIter.Close()
+
+	for {
+		engine, v, err := enginesIter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		doc, ok := v.(*types.Document)
+		if !ok {
+			msg := fmt.Sprintf("'storageEngine.%s' has to be an embedded document.", engine)
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+		}
+
+		if engine == "postgresql" {
+			opts = doc
+		}
+	}
+
+	if opts == nil {
+		return nil
+	}
+
+	iter := opts.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "fillfactor":
+			var n int64
+			if n, err = handlerparams.GetWholeNumberParam(v); err != nil || n < 10 || n > 100 {
+				msg := fmt.Sprintf(
+					"'storageEngine.postgresql.fillfactor' must be a whole number between 10 and 100, got %s",
+					types.FormatAnyValue(v),
+				)
+
+				return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+			}
+
+			params.StorageOptions.FillFactor = n
+
+		case "toastCompression":
+			if s, _ := v.(string); s != "pglz" && s != "lz4" {
+				msg := fmt.Sprintf(
+					`'storageEngine.postgresql.toastCompression' must be "pglz" or "lz4", got %s`,
+					types.FormatAnyValue(v),
+				)
+
+				return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+			}
+
+			params.StorageOptions.ToastCompression = v.(string)
+
+		case "tablespace":
+			if s, _ := v.(string); s == "" {
+				msg := fmt.Sprintf(
+					"'storageEngine.postgresql.tablespace' must be a non-empty string, got %s",
+					types.FormatAnyValue(v),
+				)
+
+				return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+			}
+
+			params.StorageOptions.Tablespace = v.(string)
+
+		default:
+			msg := fmt.Sprintf("Unknown storage option 'storageEngine.postgresql.%s'", k)
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrBadValue, msg, command)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgCreateBackup implements `createBackup` command.
+//
+// The logical backup of all database collections is stored as a single object
+// named `<database>/<UTC timestamp>.bson` in the backup storage,
+// so the backups of the same database share the prefix and are sorted by time.
+// The object contains export streams (see exportWriter) of all collections one after another;
+// their headers also contain collection options, indexes,
+// and the cluster time of the backup start used for point-in-time recovery.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgCreateBackup(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	common.Ignored(document, h.L, "comment")
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.checkBackupStore(command); err != nil {
+		return nil, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid database name: %s", dbName)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	now := time.Now()
+	startClusterTime := types.NextTimestamp(now)
+
+	list, err := db.ListCollections(connCtx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var collections []backends.CollectionInfo
+
+	for _, cInfo := range list.Collections {
+		if !strings.HasPrefix(cInfo.Name, "system.") {
+			collections = append(collections, cInfo)
+		}
+	}
+
+	if len(collections) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceNotFound,
+			fmt.Sprintf("database %s not found", dbName),
+			command,
+		)
+	}
+
+	name := backupName(dbName, now)
+
+	w, err := h.backupStore.Create(connCtx, name)
+	if err != nil {
+		return nil, objectStoreError(err, name, command)
+	}
+
+	ew := &exportWriter{
+		w:      w,
+		stream: sha256.New(),
+		block:  sha256.New(),
+	}
+
+	for _, cInfo := range collections {
+		header := exportHeader(dbName + "." + cInfo.Name)
+		must.NotFail(header.Get("$export")).(*types.Document).Set("startClusterTime", startClusterTime)
+
+		if err = ew.backup(connCtx, db, &cInfo, header); err != nil {
+			w.Abort()
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, objectStoreError(err, name, command)
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"name", name,
+			"collections", int32(len(collections)),
+			"documents", ew.total,
+			"bytes", ew.bytes,
+			"sha256", hex.EncodeToString(ew.stream.Sum(nil)),
+			"startClusterTime", startClusterTime,
+			"ok", float64(1),
+		)),
+	)
+}
+
+// checkBackupStore returns an error if backup store is not configured.
+func (h *Handler) checkBackupStore(command string) error {
+	if h.backupStore != nil {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrIllegalOperation,
+		fmt.Sprintf("%s requires FerretDB to be started with --backup-url flag", command),
+		command,
+	)
+}
+
+// backupName returns the name of the backup object for the given database and time.
+func backupName(dbName string, t time.Time) string {
+	return path.Join(dbName, t.UTC().Format("20060102T150405.000Z")+".bson")
+}
+
+// backup writes the export stream of the given collection,
+// adding collection options and indexes to the given header.
+func (ew *exportWriter) backup(ctx context.Context, db backends.Database, cInfo *backends.CollectionInfo, header *types.Document) error {
+	c, err := db.Collection(cInfo.Name)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	indexes, err := c.ListIndexes(ctx, new(backends.ListIndexesParams))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	indexesArr := types.MakeArray(len(indexes.Indexes))
+
+	for _, index := range indexes.Indexes {
+		indexDoc := must.NotFail(types.NewDocument(
+			"name", index.Name,
+			"key", indexKeyDocument(index.Key),
+			"unique", index.Unique,
+		))
+
+		if index.Options != nil {
+			indexDoc.Set("options", index.Options)
+		}
+
+		indexesArr.Append(indexDoc)
+	}
+
+	spec := must.NotFail(header.Get("$export")).(*types.Document)
+
+	if cInfo.Capped() {
+		spec.Set("options", must.NotFail(types.NewDocument(
+			"capped", true,
+			"size", cInfo.CappedSize,
+			"max", cInfo.CappedDocuments,
+		)))
+	}
+
+	spec.Set("indexes", indexesArr)
+
+	return ew.export(ctx, c, header, cInfo.Capped())
+}
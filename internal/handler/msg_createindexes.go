@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"slices"
 	"strings"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
@@ -80,6 +82,9 @@ func (h *Handler) MsgCreateIndexes(connCtx context.Context, msg *wire.OpMsg) (*w
 		return nil, lazyerrors.Error(err)
 	}
 
+	// there is no replication, so the build is always committed by the single node
+	common.Ignored(document, h.L, "commitQuorum")
+
 	v, _ := document.Get("indexes")
 	if v == nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -150,9 +155,18 @@ func (h *Handler) MsgCreateIndexes(connCtx context.Context, msg *wire.OpMsg) (*w
 		return nil, err
 	}
 
-	_, err = c.CreateIndexes(connCtx, &backends.CreateIndexesParams{Indexes: toCreate})
-	if err != nil {
-		return nil, lazyerrors.Error(err)
+	if createCollection {
+		if err = h.checkDatabaseCase(connCtx, dbName, command); err != nil {
+			return nil, err
+		}
+
+		if err = h.checkCollectionsQuota(connCtx, db, dbName, collection, command); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = h.buildIndexes(connCtx, c, toCreate, msg); err != nil {
+		return nil, err
 	}
 
 	resp := new(types.Document)
@@ -173,6 +187,80 @@ func (h *Handler) MsgCreateIndexes(connCtx context.Context, msg *wire.OpMsg) (*w
 	)
 }
 
+// buildIndexes creates the given indexes in the background operation
+// that is visible in `currentOp` output and could be killed with `killOp` command.
+//
+// It waits for the build to finish, but closing the client connection does not abort the build.
+func (h *Handler) buildIndexes(connCtx context.Context, c backends.Collection, indexes []backends.IndexInfo, msg *wire.OpMsg) error { //nolint:lll // for readability
+	ctx, op := h.operations.startBackground(connCtx, "createIndexes", "IndexBuildsCoordinator", msg)
+
+	done := make(chan error, 1)
+
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+
+		err := h.runIndexBuild(ctx, c, indexes, op)
+
+		op.cancel(nil)
+		h.operations.finish(op)
+
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-connCtx.Done():
+		return lazyerrors.Error(context.Cause(connCtx))
+	}
+}
+
+// runIndexBuild creates the given indexes one by one, reporting the progress of the operation.
+//
+// If the build fails or is killed, already created indexes are dropped.
+func (h *Handler) runIndexBuild(ctx context.Context, c backends.Collection, indexes []backends.IndexInfo, op *operation) error { //nolint:lll // for readability
+	created := make([]string, 0, len(indexes))
+
+	var err error
+
+	for i, idx := range indexes {
+		op.setProgress("Index Build: building index "+idx.Name, int64(i), int64(len(indexes)))
+
+		if err = context.Cause(ctx); err != nil {
+			break
+		}
+
+		if _, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: []backends.IndexInfo{idx}}); err != nil {
+			break
+		}
+
+		created = append(created, idx.Name)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	if len(created) > 0 {
+		params := &backends.DropIndexesParams{Indexes: created}
+		if _, dropErr := c.DropIndexes(context.WithoutCancel(ctx), params); dropErr != nil {
+			h.L.ErrorContext(ctx, "Failed to drop indexes of aborted build", logging.Error(dropErr))
+		}
+	}
+
+	if errors.Is(context.Cause(ctx), errOperationKilled) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexBuildAborted,
+			"Index build aborted: "+errOperationKilled.Error(),
+			"createIndexes",
+		)
+	}
+
+	return lazyerrors.Error(err)
+}
+
 // processIndexesArray processes the given array of indexes and returns a slice of backends.IndexInfo elements.
 func processIndexesArray(command string, indexesArray *types.Array) ([]backends.IndexInfo, error) {
 	iter := indexesArray.Iterator()
@@ -242,6 +330,10 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 				)
 			}
 
+			if err = validateIndexOptions(command, &index); err != nil {
+				return nil, err
+			}
+
 			return &index, nil
 		default:
 			return nil, lazyerrors.Error(err)
@@ -336,7 +428,7 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 				)
 			}
 
-			if len(index.Key) == 1 && index.Key[0].Field == "_id" {
+			if len(index.Key) == 1 && index.Key[0].Field == "_id" && !index.Key[0].Hashed {
 				return nil, handlererrors.NewCommandErrorMsgWithArgument(
 					handlererrors.ErrInvalidIndexSpecificationOption,
 					fmt.Sprintf("The field 'unique' is not valid for an _id index specification. "+
@@ -354,13 +446,14 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 		case "background":
 			// ignore deprecated options
 
-		case "sparse":
-			// Ignore for now to make Meteor apps work.
-			// TODO https://github.com/FerretDB/FerretDB/issues/2448
+		case "sparse", "hidden", "expireAfterSeconds", "partialFilterExpression", "collation":
+			// options are stored as is and returned by `listIndexes`
+			if err = processIndexOption(command, indexDoc, &index, opt); err != nil {
+				return nil, err
+			}
 
-		case "partialFilterExpression", "expireAfterSeconds", "hidden", "storageEngine",
-			"weights", "default_language", "language_override", "textIndexVersion", "2dsphereIndexVersion",
-			"bits", "min", "max", "bucketSize", "collation", "wildcardProjection":
+		case "storageEngine", "weights", "default_language", "language_override", "textIndexVersion",
+			"2dsphereIndexVersion", "bits", "min", "max", "bucketSize", "wildcardProjection":
 			return nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrNotImplemented,
 				fmt.Sprintf("Index option %q is not implemented yet", opt),
@@ -377,6 +470,131 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 	}
 }
 
+// processIndexOption validates the given index option and adds it to index options.
+func processIndexOption(command string, indexDoc *types.Document, index *backends.IndexInfo, opt string) error {
+	v := must.NotFail(indexDoc.Get(opt))
+
+	var expected string
+
+	switch opt {
+	case "sparse", "hidden":
+		if _, ok := v.(bool); !ok {
+			expected = "bool"
+		}
+
+	case "expireAfterSeconds":
+		seconds, err := handlerparams.GetWholeNumberParam(v)
+
+		switch {
+		case errors.Is(err, handlerparams.ErrUnexpectedType):
+			expected = "number"
+		case err != nil || seconds < 0 || seconds > math.MaxInt32:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidIndexSpecificationOption,
+				fmt.Sprintf(
+					"TTL index 'expireAfterSeconds' option must be within an acceptable range, got %s",
+					types.FormatAnyValue(v),
+				),
+				command,
+			)
+		}
+
+	case "partialFilterExpression":
+		filter, ok := v.(*types.Document)
+		if !ok {
+			expected = "object"
+			break
+		}
+
+		// validate operators of the filter
+		if _, err := common.FilterDocument(new(types.Document), filter); err != nil {
+			return err
+		}
+
+	case "collation":
+		if _, ok := v.(*types.Document); !ok {
+			expected = "object"
+		}
+
+	default:
+		panic(fmt.Sprintf("unexpected index option %q", opt))
+	}
+
+	if expected != "" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"Error in specification { key: %s, name: %q } :: caused by :: "+
+					"The field '%s' must be of type %s, but got %s",
+				types.FormatAnyValue(must.NotFail(indexDoc.Get("key"))), index.Name,
+				opt, expected, handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+
+	if index.Options == nil {
+		index.Options = new(types.Document)
+	}
+
+	index.Options.Set(opt, v)
+
+	return nil
+}
+
+// validateIndexOptions checks that the given index options are valid together.
+func validateIndexOptions(command string, index *backends.IndexInfo) error {
+	if index.Unique && slices.ContainsFunc(index.Key, func(pair backends.IndexKeyPair) bool { return pair.Hashed }) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrHashedIndexUnique,
+			"Currently hashed indexes cannot guarantee uniqueness. Use a regular index.",
+			command,
+		)
+	}
+
+	if index.Options == nil {
+		return nil
+	}
+
+	isID := len(index.Key) == 1 && index.Key[0].Field == "_id" && !index.Key[0].Hashed
+
+	for _, opt := range index.Options.Keys() {
+		switch {
+		case isID && opt == "hidden":
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"can't hide _id index",
+				command,
+			)
+
+		case isID && (opt == "sparse" || opt == "expireAfterSeconds" || opt == "partialFilterExpression"):
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidIndexSpecificationOption,
+				fmt.Sprintf("The field '%s' is not valid for an _id index specification.", opt),
+				command,
+			)
+
+		case opt == "expireAfterSeconds" && len(index.Key) > 1:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				"TTL indexes are single-field indexes, compound indexes do not support TTL. "+
+					fmt.Sprintf("Index spec: { key: { %s }, name: %q }", formatIndexKey(index.Key), index.Name),
+				command,
+			)
+
+		case index.Unique && (opt == "partialFilterExpression" || opt == "collation"):
+			// they change the uniqueness constraint that is enforced by backends
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("Index option %q is not implemented yet for unique indexes", opt),
+				command,
+			)
+		}
+	}
+
+	return nil
+}
+
 // processIndexKey processes the document containing the index key (set of "field-order" pairs).
 func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKeyPair, error) {
 	res := make([]backends.IndexKeyPair, 0, keyDoc.Len())
@@ -386,6 +604,8 @@ func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKe
 
 	duplicateChecker := make(map[string]struct{}, keyDoc.Len())
 
+	var hashed int
+
 	for {
 		field, order, err := keyIter.Next()
 
@@ -393,6 +613,17 @@ func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKe
 		case err == nil:
 			// do nothing
 		case errors.Is(err, iterator.ErrIteratorDone):
+			if hashed > 1 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrHashedIndexMultipleFields,
+					fmt.Sprintf(
+						"A maximum of one index field is allowed to be hashed but found %d for 'key' %s",
+						hashed, types.FormatAnyValue(keyDoc),
+					),
+					command,
+				)
+			}
+
 			return res, nil
 		default:
 			return nil, lazyerrors.Error(err)
@@ -411,6 +642,17 @@ func processIndexKey(command string, keyDoc *types.Document) ([]backends.IndexKe
 
 		duplicateChecker[field] = struct{}{}
 
+		if order == "hashed" {
+			hashed++
+
+			res = append(res, backends.IndexKeyPair{
+				Field:  field,
+				Hashed: true,
+			})
+
+			continue
+		}
+
 		var orderParam int64
 
 		if orderParam, err = handlerparams.GetWholeNumberParam(order); err != nil {
@@ -449,7 +691,11 @@ func formatIndexKey(key []backends.IndexKeyPair) string {
 
 	for i, pair := range key {
 		order := "1"
-		if pair.Descending {
+
+		switch {
+		case pair.Hashed:
+			order = `"hashed"`
+		case pair.Descending:
 			order = "-1"
 		}
 
@@ -459,6 +705,26 @@ func formatIndexKey(key []backends.IndexKeyPair) string {
 	return strings.Join(res, ", ")
 }
 
+// indexKeyDocument returns the document representation of the given index key.
+func indexKeyDocument(key []backends.IndexKeyPair) *types.Document {
+	res := types.MakeDocument(len(key))
+
+	for _, pair := range key {
+		var order any = int32(1)
+
+		switch {
+		case pair.Hashed:
+			order = "hashed"
+		case pair.Descending:
+			order = int32(-1)
+		}
+
+		res.Set(pair.Field, order)
+	}
+
+	return res
+}
+
 // validateIndexesForCreation validates the given list of indexes to create against the existing ones.
 // It filters out duplicate indexes and returns a slice of indexes to create.
 // It returns an error if at least one provided index has an invalid specification.
@@ -524,6 +790,17 @@ func validateIndexesForCreation(command string, existing, toCreate []backends.In
 		for _, existingIdx := range existing {
 			existingKey := formatIndexKey(existingIdx.Key)
 
+			if newIdx.Name == existingIdx.Name && newKey == existingKey && newKey != "_id: 1" &&
+				!sameIndexOptions(&newIdx, &existingIdx) {
+				msg := fmt.Sprintf(
+					"An equivalent index already exists with the same name but different options. "+
+						"Requested index: %s, existing index: %s",
+					types.FormatAnyValue(indexDocument(&newIdx)), types.FormatAnyValue(indexDocument(&existingIdx)),
+				)
+
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrIndexOptionsConflict, msg, command)
+			}
+
 			if (newIdx.Name == existingIdx.Name && newKey == existingKey) || newKey == "_id: 1" {
 				// Fully identical indexes are ignored, no need to attempt to create them.
 				filteredToCreate = slices.Delete(filteredToCreate, i, i+1)
@@ -551,3 +828,22 @@ func validateIndexesForCreation(command string, existing, toCreate []backends.In
 
 	return filteredToCreate, nil
 }
+
+// sameIndexOptions returns true if the given indexes have the same options.
+func sameIndexOptions(a, b *backends.IndexInfo) bool {
+	if a.Unique != b.Unique {
+		return false
+	}
+
+	aOpts, bOpts := a.Options, b.Options
+
+	if aOpts == nil {
+		aOpts = new(types.Document)
+	}
+
+	if bOpts == nil {
+		bOpts = new(types.Document)
+	}
+
+	return types.Identical(aOpts, bOpts)
+}
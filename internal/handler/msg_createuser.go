@@ -170,11 +170,17 @@ func (h *Handler) MsgCreateUser(connCtx context.Context, msg *wire.OpMsg) (*wire
 			)
 		}
 
+		if err = h.passwordPolicy().Check(password.WrapPassword(userPassword)); err != nil {
+			return nil, handlererrors.NewCommandErrorMsg(handlererrors.ErrBadValue, err.Error())
+		}
+
 		err = users.CreateUser(connCtx, h.b, &users.CreateUserParams{
 			Database:   dbName,
 			Username:   username,
 			Password:   password.WrapPassword(userPassword),
 			Mechanisms: mechanisms,
+
+			IterationCounts: h.iterationCounts(),
 		})
 		if err != nil {
 			if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
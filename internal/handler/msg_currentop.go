@@ -16,10 +16,15 @@ package handler
 
 import (
 	"context"
+	"time"
 
 	"github.com/FerretDB/wire"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
@@ -27,9 +32,58 @@ import (
 //
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgCurrentOp(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var ownOps bool
+
+	if v, _ := document.Get("$ownOps"); v != nil {
+		if ownOps, err = handlerparams.GetBoolOptionalParam("$ownOps", v); err != nil {
+			return nil, err
+		}
+	}
+
+	common.Ignored(document, h.L, "$all", "comment")
+
+	// remaining top-level fields are used as a filter
+	filter := must.NotFail(types.NewDocument())
+
+	for _, k := range document.Keys() {
+		switch k {
+		case "currentOp", "$all", "$ownOps", "comment", "$db", "lsid", "$clusterTime", "$readPreference":
+			continue
+		}
+
+		filter.Set(k, must.NotFail(document.Get(k)))
+	}
+
+	connInfo := conninfo.Get(connCtx)
+	now := time.Now()
+
+	inprog := types.MakeArray(0)
+
+	for _, op := range h.operations.list() {
+		if ownOps && op.connInfo != connInfo {
+			continue
+		}
+
+		doc := op.currentOpDocument(now)
+
+		matches, err := common.FilterDocument(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		if matches {
+			inprog.Append(doc)
+		}
+	}
+
 	return documentOpMsg(
 		must.NotFail(types.NewDocument(
-			"inprog", must.NotFail(types.NewArray()),
+			"inprog", inprog,
 			"ok", float64(1),
 		)),
 	)
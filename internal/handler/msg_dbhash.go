@@ -0,0 +1,233 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgDBHash implements `dbHash` command.
+//
+// Like MongoDB, it returns MD5 hash of BSON documents of each collection in `_id` order
+// (or in natural order for capped collections),
+// and MD5 hash of concatenated collections' hashes in collection name order.
+// That allows users to compare data between MongoDB and FerretDB instances.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgDBHash(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	common.Ignored(document, h.L, "comment")
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	if v, _ := document.Get("collections"); v != nil {
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field 'dbHash.collections' is the wrong type '%s', expected type 'array'",
+					handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		names = make([]string, arr.Len())
+
+		for i := range arr.Len() {
+			v := must.NotFail(arr.Get(i))
+
+			s, ok := v.(string)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field 'dbHash.collections.%d' is the wrong type '%s', expected type 'string'",
+						i, handlerparams.AliasFromType(v),
+					),
+					command,
+				)
+			}
+
+			names[i] = s
+		}
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid database specified '%s'", dbName)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	started := time.Now()
+
+	list, err := db.ListCollections(connCtx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	collections := types.MakeDocument(len(list.Collections))
+	capped := types.MakeArray(0)
+	uuids := types.MakeDocument(len(list.Collections))
+
+	global := md5.New()
+
+	// list is sorted by name
+	for _, cInfo := range list.Collections {
+		if strings.HasPrefix(cInfo.Name, "system.") {
+			continue
+		}
+
+		if names != nil && !slices.Contains(names, cInfo.Name) {
+			continue
+		}
+
+		var c backends.Collection
+
+		if c, err = db.Collection(cInfo.Name); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var hash string
+
+		if hash, err = collectionHash(connCtx, c, cInfo.Capped()); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		collections.Set(cInfo.Name, hash)
+		must.NotFail(global.Write([]byte(hash)))
+
+		if cInfo.Capped() {
+			capped.Append(cInfo.Name)
+		}
+
+		if cInfo.UUID != "" {
+			var u uuid.UUID
+
+			if u, err = uuid.Parse(cInfo.UUID); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			uuids.Set(cInfo.Name, types.Binary{
+				Subtype: types.BinaryUUID,
+				B:       must.NotFail(u.MarshalBinary()),
+			})
+		}
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"host", h.TCPHost,
+			"collections", collections,
+			"capped", capped,
+			"uuids", uuids,
+			"md5", hex.EncodeToString(global.Sum(nil)),
+			"timeMillis", time.Since(started).Milliseconds(),
+			"fromCache", types.MakeArray(0),
+			"ok", float64(1),
+		)),
+	)
+}
+
+// collectionHash returns hex-encoded MD5 hash of BSON documents of the given collection.
+//
+// Documents of capped collections are hashed in natural order,
+// documents of other collections are hashed in `_id` order.
+func collectionHash(ctx context.Context, c backends.Collection, capped bool) (string, error) {
+	var qp backends.QueryParams
+	if capped {
+		qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
+	}
+
+	qr, err := c.Query(ctx, &qp)
+	if err != nil {
+		return "", lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	var docs []*types.Document
+
+	for {
+		var doc *types.Document
+
+		_, doc, err = qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return "", lazyerrors.Error(err)
+		}
+
+		docs = append(docs, doc)
+	}
+
+	if !capped {
+		if err = common.SortDocuments(docs, must.NotFail(types.NewDocument("_id", int64(1)))); err != nil {
+			return "", lazyerrors.Error(err)
+		}
+	}
+
+	h := md5.New()
+
+	for _, doc := range docs {
+		var b []byte
+
+		if b, err = must.NotFail(bson.FromDocument(doc)).Encode(); err != nil {
+			return "", lazyerrors.Error(err)
+		}
+
+		must.NotFail(h.Write(b))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -70,7 +70,7 @@ func (h *Handler) MsgDelete(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 
 	for i, p := range params.Deletes {
 		var d int32
-		d, err = h.execDelete(connCtx, c, &p)
+		d, err = h.execDelete(connCtx, c, &p, params.Comment)
 
 		deleted += d
 
@@ -115,12 +115,17 @@ func (h *Handler) MsgDelete(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 //
 // It returns a number of deleted documents or error.
 // The error is either a (wrapped) *handlererrors.CommandError or something fatal.
-func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *common.Delete) (int32, error) {
+func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *common.Delete, comment string) (int32, error) {
 	var qp backends.QueryParams
 	if !h.DisablePushdown {
 		qp.Filter = p.Filter
 	}
 
+	var err error
+	if qp.Comment, err = queryComment(ctx, comment, p.Filter); err != nil {
+		return 0, err
+	}
+
 	q, err := c.Query(ctx, &qp)
 	if err != nil {
 		return 0, lazyerrors.Error(err)
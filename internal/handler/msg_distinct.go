@@ -71,6 +71,10 @@ func (h *Handler) MsgDistinct(connCtx context.Context, msg *wire.OpMsg) (*wire.O
 		qp.Filter = params.Filter
 	}
 
+	if qp.Comment, err = queryComment(connCtx, params.Comment, params.Filter); err != nil {
+		return nil, err
+	}
+
 	// TODO https://github.com/FerretDB/FerretDB/issues/3235
 	queryRes, err := c.Query(connCtx, &qp)
 	if err != nil {
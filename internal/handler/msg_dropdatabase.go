@@ -16,16 +16,23 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/FerretDB/wire"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
+// dropDatabaseBatchSize is the number of collections dropped between progress updates
+// of `dropDatabase` command.
+const dropDatabaseBatchSize = 100
+
 // MsgDropDatabase implements `dropDatabase` command.
 //
 // The passed context is canceled when the client connection is closed.
@@ -53,9 +60,7 @@ func (h *Handler) MsgDropDatabase(connCtx context.Context, msg *wire.OpMsg) (*wi
 		}
 	}
 
-	err = h.b.DropDatabase(connCtx, &backends.DropDatabaseParams{
-		Name: dbName,
-	})
+	err = h.dropDatabase(connCtx, dbName, msg)
 
 	res := must.NotFail(types.NewDocument())
 
@@ -67,7 +72,7 @@ func (h *Handler) MsgDropDatabase(connCtx context.Context, msg *wire.OpMsg) (*wi
 	case backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist):
 		// nothing
 	default:
-		return nil, lazyerrors.Error(err)
+		return nil, err
 	}
 
 	res.Set("ok", float64(1))
@@ -76,3 +81,94 @@ func (h *Handler) MsgDropDatabase(connCtx context.Context, msg *wire.OpMsg) (*wi
 		res,
 	)
 }
+
+// dropDatabase drops the given database in the background operation
+// that is visible in `currentOp` output and could be killed with `killOp` command.
+//
+// It waits for the drop to finish, but closing the client connection does not abort it.
+func (h *Handler) dropDatabase(connCtx context.Context, dbName string, msg *wire.OpMsg) error {
+	ctx, op := h.operations.startBackground(connCtx, "dropDatabase", "DropDatabase", msg)
+
+	done := make(chan error, 1)
+
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+
+		err := h.runDropDatabase(ctx, dbName, op)
+
+		op.cancel(nil)
+		h.operations.finish(op)
+
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-connCtx.Done():
+		return lazyerrors.Error(context.Cause(connCtx))
+	}
+}
+
+// runDropDatabase drops collections of the given database in batches, reporting the progress of the operation,
+// and then drops the database itself.
+//
+// Each collection is dropped separately, so the backend does not hold locks on its catalog
+// for the whole duration of the operation.
+// If the operation is killed, already dropped collections are not restored.
+func (h *Handler) runDropDatabase(ctx context.Context, dbName string, op *operation) error {
+	// the operation could continue after the command returned and invalidated the cache
+	defer h.invalidateResultCache(dbName)
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		return err
+	}
+
+	list, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	total := int64(len(list.Collections))
+
+	for i, c := range list.Collections {
+		if i%dropDatabaseBatchSize == 0 {
+			op.setProgress("Drop Database: dropping collections", int64(i), total)
+
+			if err = context.Cause(ctx); err != nil {
+				break
+			}
+		}
+
+		err = db.DropCollection(ctx, &backends.DropCollectionParams{Name: c.Name})
+		if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			break
+		}
+
+		err = nil
+	}
+
+	if err == nil {
+		op.setProgress("Drop Database: dropping collections", total, total)
+
+		// drop collections created concurrently and the database itself
+		err = h.b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: dbName})
+	}
+
+	if err == nil || backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+		return err
+	}
+
+	if errors.Is(context.Cause(ctx), errOperationKilled) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInterrupted,
+			fmt.Sprintf("Drop database %s aborted: %s", dbName, errOperationKilled),
+			"dropDatabase",
+		)
+	}
+
+	return lazyerrors.Error(err)
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestDropDatabase(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler(t, new(NewOpts))
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	// createDatabase creates a database with the given number of collections.
+	createDatabase := func(t *testing.T, dbName string, n int) backends.Database {
+		t.Helper()
+
+		db, err := h.b.Database(dbName)
+		require.NoError(t, err)
+
+		for i := range n {
+			err = db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: fmt.Sprintf("c%d", i)})
+			require.NoError(t, err)
+		}
+
+		return db
+	}
+
+	t.Run("Batches", func(t *testing.T) {
+		t.Parallel()
+
+		dbName := testutil.DatabaseName(t)
+		createDatabase(t, dbName, dropDatabaseBatchSize+1)
+
+		msg := wire.MustOpMsg("dropDatabase", int32(1), "$db", dbName)
+		opCtx, op := h.operations.startBackground(ctx, "dropDatabase", "DropDatabase", msg)
+
+		t.Cleanup(func() {
+			op.cancel(nil)
+			h.operations.finish(op)
+		})
+
+		err := h.runDropDatabase(opCtx, dbName, op)
+		require.NoError(t, err)
+
+		doc := op.currentOpDocument(op.start)
+		assert.Equal(t, "DropDatabase", must.NotFail(doc.Get("desc")))
+		assert.Equal(t, dbName, must.NotFail(doc.Get("ns")))
+		assert.Equal(t, "Drop Database: dropping collections 101/101", must.NotFail(doc.Get("msg")))
+
+		res, err := h.b.ListDatabases(ctx, &backends.ListDatabasesParams{Name: dbName})
+		require.NoError(t, err)
+		assert.Empty(t, res.Databases)
+	})
+
+	t.Run("Killed", func(t *testing.T) {
+		t.Parallel()
+
+		dbName := testutil.DatabaseName(t)
+		db := createDatabase(t, dbName, 3)
+
+		msg := wire.MustOpMsg("dropDatabase", int32(1), "$db", dbName)
+		opCtx, op := h.operations.startBackground(ctx, "dropDatabase", "DropDatabase", msg)
+
+		h.operations.kill(op.id)
+		h.operations.finish(op)
+
+		err := h.runDropDatabase(opCtx, dbName, op)
+
+		var cmdErr *handlererrors.CommandError
+		require.ErrorAs(t, err, &cmdErr)
+		assert.Equal(t, handlererrors.ErrInterrupted, cmdErr.Code())
+
+		res, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+		require.NoError(t, err)
+		assert.Len(t, res.Collections, 3)
+	})
+}
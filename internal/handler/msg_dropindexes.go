@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/FerretDB/wire"
 
@@ -134,7 +135,7 @@ func processDropIndexOptions(command, ns string, v any, existing []backends.Inde
 			return nil, false, lazyerrors.Error(err)
 		}
 
-		if len(spec) == 1 && spec[0].Field == "_id" && !spec[0].Descending {
+		if len(spec) == 1 && spec[0].Field == "_id" && !spec[0].Descending && !spec[0].Hashed {
 			return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrInvalidOptions, "cannot drop _id index", command,
 			)
@@ -147,16 +148,7 @@ func processDropIndexOptions(command, ns string, v any, existing []backends.Inde
 		}
 
 		for _, index := range existing {
-			matches := true
-
-			for i, key := range index.Key {
-				if key.Field != spec[i].Field || key.Descending != spec[i].Descending {
-					matches = false
-					break
-				}
-			}
-
-			if matches {
+			if slices.Equal(index.Key, spec) {
 				return []string{index.Name}, false, nil
 			}
 		}
@@ -209,6 +201,14 @@ func processDropIndexOptions(command, ns string, v any, existing []backends.Inde
 				)
 			}
 
+			if index == "*" {
+				return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"'*' is not a valid index name when dropping multiple indexes",
+					command,
+				)
+			}
+
 			var found bool
 
 			for _, existingIndex := range existing {
@@ -230,6 +230,12 @@ func processDropIndexOptions(command, ns string, v any, existing []backends.Inde
 		}
 
 	case string:
+		if len(existing) == 0 {
+			return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNamespaceNotFound, fmt.Sprintf("ns not found %s", ns), command,
+			)
+		}
+
 		if v == "*" {
 			toDrop := make([]string, 0, len(existing))
 
@@ -245,12 +251,6 @@ func processDropIndexOptions(command, ns string, v any, existing []backends.Inde
 			return toDrop, true, nil
 		}
 
-		if len(existing) == 0 {
-			return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNamespaceNotFound, fmt.Sprintf("ns not found %s", ns), command,
-			)
-		}
-
 		if v == backends.DefaultIndexName {
 			return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrInvalidOptions, "cannot drop _id index", command,
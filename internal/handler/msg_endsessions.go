@@ -0,0 +1,95 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgEndSessions implements `endSessions` command.
+//
+// Drivers send up to 10 000 session IDs at once when the client is closed,
+// so well-formed commands are handled without converting them to [*types.Document].
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgEndSessions(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	ids, ok := rawSessionIDs(msg)
+	if !ok {
+		var err error
+		if ids, err = sessionIDsParam(msg); err != nil {
+			return nil, err
+		}
+	}
+
+	h.endSessions(ids)
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)
+}
+
+// rawSessionIDs returns logical session IDs from the array of `{id: UUID}` documents
+// named after the command, decoding only the required parts of the raw document.
+//
+// It returns false if the command is not well-formed;
+// [sessionIDsParam] should be used then to return a proper error.
+func rawSessionIDs(msg *wire.OpMsg) ([]uuid.UUID, bool) {
+	doc, err := msg.RawSection0().Decode()
+	if err != nil {
+		return nil, false
+	}
+
+	raw, ok := doc.Get(doc.Command()).(wirebson.RawArray)
+	if !ok {
+		return nil, false
+	}
+
+	arr, err := raw.Decode()
+	if err != nil {
+		return nil, false
+	}
+
+	res := make([]uuid.UUID, arr.Len())
+
+	for i := range arr.Len() {
+		var rawDoc wirebson.RawDocument
+		if rawDoc, ok = arr.Get(i).(wirebson.RawDocument); !ok {
+			return nil, false
+		}
+
+		var lsid *wirebson.Document
+		if lsid, err = rawDoc.Decode(); err != nil {
+			return nil, false
+		}
+
+		var id wirebson.Binary
+		if id, ok = lsid.Get("id").(wirebson.Binary); !ok || id.Subtype != wirebson.BinaryUUID || len(id.B) != 16 {
+			return nil, false
+		}
+
+		res[i] = uuid.UUID(id.B)
+	}
+
+	return res, true
+}
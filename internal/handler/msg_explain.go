@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/FerretDB/wire"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -84,10 +86,39 @@ func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 		return nil, lazyerrors.Error(err)
 	}
 
+	switch cmd.Command() {
+	case "update", "delete", "findAndModify", "findandmodify":
+		res, stats, err := h.explainWrite(connCtx, coll, params)
+		if err != nil {
+			return nil, err
+		}
+
+		return documentOpMsg(h.explainDocument(res, stats, cmd, serverInfo))
+	}
+
+	res, err := h.explainQuery(connCtx, db, coll, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return documentOpMsg(h.explainDocument(res, nil, cmd, serverInfo))
+}
+
+// explainQuery returns the backend's execution plan for the given read command parameters.
+func (h *Handler) explainQuery(ctx context.Context, db backends.Database, coll backends.Collection, params *common.ExplainParams) (*backends.ExplainResult, error) { //nolint:lll // for readability
+	var err error
+
 	qp := new(backends.ExplainParams)
 
 	if params.Aggregate {
+		// aggregate command has no sort parameter, so it is set only by $natural hint
+		naturalHint := params.Sort
+
 		params.Filter, params.Sort = aggregations.GetPushdownQuery(params.StagesDocs)
+
+		if params.Sort.Len() == 0 {
+			params.Sort = naturalHint
+		}
 	}
 
 	if !h.DisablePushdown {
@@ -112,7 +143,7 @@ func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 			return nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrPathContainsEmptyElement,
 				"Empty field names in path are not allowed",
-				document.Command(),
+				"explain",
 			)
 		}
 
@@ -122,7 +153,7 @@ func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 	var cList *backends.ListCollectionsResult
 
 	collectionParam := backends.ListCollectionsParams{Name: params.Collection}
-	if cList, err = db.ListCollections(connCtx, &collectionParam); err != nil {
+	if cList, err = db.ListCollections(ctx, &collectionParam); err != nil {
 		return nil, err
 	}
 
@@ -133,24 +164,16 @@ func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 	}
 
 	switch {
+	case params.Sort.Len() == 1 && params.Sort.Keys()[0] == "$natural":
+		// Natural order is known only to the backend, so it is pushed down even if pushdown is disabled
+		qp.Sort = params.Sort
 	case h.DisablePushdown:
 		// Pushdown disabled
 	case params.Sort.Len() == 0 && cInfo.Capped():
 		// Pushdown default recordID sorting for capped collections
 		qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
-	case params.Sort.Len() == 1:
-		if params.Sort.Keys()[0] != "$natural" {
-			break
-		}
-
-		if !cInfo.Capped() {
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"$natural sort for non-capped collection is not supported.",
-				"explain",
-			)
-		}
-
+	case params.Sort.Len() != 0 && h.EnableSortPushdown && !params.Aggregate:
+		// Backend applies it only if an index could be used
 		qp.Sort = params.Sort
 	}
 
@@ -163,25 +186,223 @@ func (h *Handler) MsgExplain(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 		qp.Limit = params.Limit
 	}
 
-	res, err := coll.Explain(connCtx, qp)
+	res, err := coll.Explain(ctx, qp)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	return documentOpMsg(
-		must.NotFail(types.NewDocument(
-			"queryPlanner", res.QueryPlanner,
-			"explainVersion", "1",
-			"command", cmd,
-			"serverInfo", serverInfo,
+	return res, nil
+}
+
+// explainDocument returns the `explain` command response.
+//
+// Execution stats are added only if they are not nil.
+// Generated SQL query is added only if it is enabled and the backend uses SQL.
+func (h *Handler) explainDocument(res *backends.ExplainResult, stats, cmd, serverInfo *types.Document) *types.Document {
+	doc := must.NotFail(types.NewDocument(
+		"queryPlanner", res.QueryPlanner,
+	))
+
+	if stats != nil {
+		doc.Set("executionStats", stats)
+	}
+
+	doc.Set("explainVersion", "1")
+	doc.Set("command", cmd)
+	doc.Set("serverInfo", serverInfo)
+
+	// our extensions
+	// TODO https://github.com/FerretDB/FerretDB/issues/3235
+	doc.Set("filterPushdown", res.FilterPushdown)
+	doc.Set("sortPushdown", res.SortPushdown)
+	doc.Set("limitPushdown", res.LimitPushdown)
+
+	if h.EnableExplainSQL && res.Query != "" {
+		args := types.MakeArray(len(res.Args))
+
+		for _, a := range res.Args {
+			switch a := a.(type) {
+			case string, float64, int32, int64, bool, time.Time:
+				args.Append(a)
+			case []byte:
+				args.Append(string(a))
+			default:
+				args.Append(fmt.Sprint(a))
+			}
+		}
+
+		doc.Set("sql", must.NotFail(types.NewDocument(
+			"query", res.Query,
+			"args", args,
+		)))
+	}
+
+	doc.Set("ok", float64(1))
+
+	return doc
+}
+
+// explainWrite returns the query plan and, unless only the plan is requested by verbosity,
+// execution stats for the given `update`, `delete` or `findAndModify` command.
+//
+// Matching documents are updated or deleted only in memory; changes are not applied.
+func (h *Handler) explainWrite(ctx context.Context, c backends.Collection, params *common.ExplainParams) (*backends.ExplainResult, *types.Document, error) { //nolint:lll // for readability
+	cmd := params.Command
+	command := cmd.Command()
+
+	var filter, sort *types.Document
+	var update *common.Update
+	var limited bool
+
+	switch command {
+	case "update":
+		updateParams, err := common.GetUpdateParams(cmd, h.L)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(updateParams.Updates) != 1 {
+			return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidLength,
+				"explained write batches must be of size 1",
+				"explain",
+			)
+		}
+
+		update = &updateParams.Updates[0]
+		filter = update.Filter
+		limited = !update.Multi
+
+	case "delete":
+		deleteParams, err := common.GetDeleteParams(cmd, h.L)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(deleteParams.Deletes) != 1 {
+			return nil, nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidLength,
+				"explained write batches must be of size 1",
+				"explain",
+			)
+		}
+
+		filter = deleteParams.Deletes[0].Filter
+		limited = deleteParams.Deletes[0].Limited
+
+	default:
+		famParams, err := common.GetFindAndModifyParams(cmd, h.L)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if famParams.Update != nil {
+			if err = common.ValidateUpdateOperators(command, famParams.Update); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		filter = famParams.Query
+		sort = famParams.Sort
+		limited = true
+
+		if !famParams.Remove {
+			update = &common.Update{
+				Filter:             famParams.Query,
+				Update:             famParams.Update,
+				Upsert:             famParams.Upsert,
+				HasUpdateOperators: famParams.HasUpdateOperators,
+			}
+		}
+	}
+
+	// the same filter is pushed down as for the command itself
+	var qp backends.QueryParams
+	if !h.DisablePushdown {
+		qp.Filter = filter
+	}
+
+	res, err := c.Explain(ctx, &backends.ExplainParams{Filter: qp.Filter})
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	if params.Verbosity == "queryPlanner" {
+		return res, nil, nil
+	}
+
+	queryRes, err := c.Query(ctx, &qp)
+	if err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	closer.Add(queryRes.Iter)
+
+	iter := common.FilterIterator(queryRes.Iter, closer, filter)
+
+	if iter, err = common.SortIterator(ctx, iter, closer, sort); err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	if limited {
+		iter = common.LimitIterator(iter, closer, 1)
+	}
+
+	var stages *types.Document
+
+	if update == nil {
+		docs, err := iterator.ConsumeValues(iter)
+		if err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		stages = must.NotFail(types.NewDocument(
+			"stage", "DELETE",
+			"nWouldDelete", int32(len(docs)),
+		))
+	} else {
+		updateRes, err := common.UpdateDocument(ctx, dryRunCollection{c}, command, iter, update)
+		if err != nil {
+			return nil, nil, handleUpdateError(params.DB, params.Collection, command, err)
+		}
 
-			// our extensions
-			// TODO https://github.com/FerretDB/FerretDB/issues/3235
-			"filterPushdown", res.FilterPushdown,
-			"sortPushdown", res.SortPushdown,
-			"limitPushdown", res.LimitPushdown,
+		var upserted int32
+		if updateRes.Upserted.Doc != nil {
+			upserted = 1
+		}
+
+		stages = must.NotFail(types.NewDocument(
+			"stage", "UPDATE",
+			"nMatched", updateRes.Matched.Count,
+			"nWouldModify", updateRes.Modified.Count,
+			"nWouldUpsert", upserted,
+		))
+	}
+
+	stats := must.NotFail(types.NewDocument(
+		"executionSuccess", true,
+		"executionStages", stages,
+	))
+
+	return res, stats, nil
+}
+
+// dryRunCollection wraps [backends.Collection] and discards inserts and updates.
+//
+// It is used to explain write commands without applying changes.
+type dryRunCollection struct {
+	backends.Collection
+}
+
+// InsertAll implements backends.Collection interface.
+func (dryRunCollection) InsertAll(context.Context, *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	return new(backends.InsertAllResult), nil
+}
 
-			"ok", float64(1),
-		)),
-	)
+// UpdateAll implements backends.Collection interface.
+func (dryRunCollection) UpdateAll(_ context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	return &backends.UpdateAllResult{Updated: int32(len(params.Docs))}, nil
 }
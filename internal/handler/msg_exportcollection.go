@@ -0,0 +1,307 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/objectstore"
+)
+
+// Export stream format.
+//
+// The stream is a sequence of BSON documents:
+// a header `{$export: {version: 1, ns: "db.coll"}}`,
+// blocks of up to exportBlockSize collection documents,
+// each followed by `{$checksum: {n: <documents in block>, sha256: "<hex of block's raw documents>"}}`,
+// and a trailer `{$end: {n: <total documents>}}`.
+const (
+	exportVersion   = int32(1)
+	exportBlockSize = 1000
+)
+
+// MsgExportCollection implements `exportCollection` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgExportCollection(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	common.Ignored(document, h.L, "comment")
+
+	command := document.Command()
+
+	ns, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := common.GetRequiredParam[string](document, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.checkExportStore(command); err != nil {
+		return nil, err
+	}
+
+	c, capped, err := h.exportedCollection(connCtx, ns, command)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := h.exportStore.Create(connCtx, to)
+	if err != nil {
+		return nil, objectStoreError(err, to, command)
+	}
+
+	ew := &exportWriter{
+		w:      w,
+		stream: sha256.New(),
+		block:  sha256.New(),
+	}
+
+	if err = ew.export(connCtx, c, exportHeader(ns), capped); err != nil {
+		w.Abort()
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, objectStoreError(err, to, command)
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"exported", ew.total,
+			"bytes", ew.bytes,
+			"sha256", hex.EncodeToString(ew.stream.Sum(nil)),
+			"ok", float64(1),
+		)),
+	)
+}
+
+// checkExportStore returns an error if export store is not configured.
+func (h *Handler) checkExportStore(command string) error {
+	if h.exportStore != nil {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrIllegalOperation,
+		fmt.Sprintf("%s requires FerretDB to be started with --export-url flag", command),
+		command,
+	)
+}
+
+// exportedCollection returns an existing collection for the given namespace
+// and whether it is capped.
+func (h *Handler) exportedCollection(ctx context.Context, ns, command string) (backends.Collection, bool, error) {
+	dbName, cName, err := handlerparams.SplitNamespace(ns, command)
+	if err != nil {
+		return nil, false, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s'", ns)
+			return nil, false, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	list, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: cName})
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	if len(list.Collections) == 0 {
+		return nil, false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceNotFound,
+			fmt.Sprintf("ns not found %s", ns),
+			command,
+		)
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	return c, list.Collections[0].Capped(), nil
+}
+
+// objectStoreError converts object store errors to command errors.
+func objectStoreError(err error, name, command string) error {
+	switch {
+	case errors.Is(err, objectstore.ErrInvalidName):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Invalid object name %q", name),
+			command,
+		)
+
+	case errors.Is(err, objectstore.ErrNotExist):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrOperationFailed,
+			fmt.Sprintf("Object %q does not exist", name),
+			command,
+		)
+
+	default:
+		return lazyerrors.Error(err)
+	}
+}
+
+// exportHeader returns the header of the export stream for the given namespace.
+//
+// Additional fields could be added to the returned document's `$export` document.
+func exportHeader(ns string) *types.Document {
+	return must.NotFail(types.NewDocument("$export", must.NotFail(types.NewDocument(
+		"version", exportVersion,
+		"ns", ns,
+	))))
+}
+
+// exportWriter writes the export stream.
+//
+// Streams of several collections could be written one after another.
+type exportWriter struct {
+	w      io.Writer
+	stream hash.Hash // all written bytes
+	block  hash.Hash // documents of the current block
+
+	blockN int32
+	n      int64 // documents of the current collection
+	total  int64 // documents of all collections
+	bytes  int64
+}
+
+// export writes the given header and all documents of the given collection.
+func (ew *exportWriter) export(ctx context.Context, c backends.Collection, header *types.Document, capped bool) error {
+	ew.n = 0
+
+	if err := ew.write(header, nil); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	var qp backends.QueryParams
+	if capped {
+		qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
+	}
+
+	qr, err := c.Query(ctx, &qp)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	for {
+		var doc *types.Document
+
+		_, doc, err = qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if err = ew.write(doc, ew.block); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		ew.blockN++
+		ew.n++
+		ew.total++
+
+		if ew.blockN == exportBlockSize {
+			if err = ew.writeChecksum(); err != nil {
+				return lazyerrors.Error(err)
+			}
+		}
+	}
+
+	if ew.blockN > 0 {
+		if err = ew.writeChecksum(); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	trailer := must.NotFail(types.NewDocument("$end", must.NotFail(types.NewDocument("n", ew.n))))
+
+	return ew.write(trailer, nil)
+}
+
+// writeChecksum writes the checksum of the current block and starts a new one.
+func (ew *exportWriter) writeChecksum() error {
+	doc := must.NotFail(types.NewDocument("$checksum", must.NotFail(types.NewDocument(
+		"n", ew.blockN,
+		"sha256", hex.EncodeToString(ew.block.Sum(nil)),
+	))))
+
+	ew.block.Reset()
+	ew.blockN = 0
+
+	return ew.write(doc, nil)
+}
+
+// write encodes and writes a single document, also adding it to the given block hash if it is not nil.
+func (ew *exportWriter) write(doc *types.Document, block hash.Hash) error {
+	raw, err := must.NotFail(bson.FromDocument(doc)).Encode()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = ew.w.Write(raw); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	must.NotFail(ew.stream.Write(raw))
+
+	if block != nil {
+		must.NotFail(block.Write(raw))
+	}
+
+	ew.bytes += int64(len(raw))
+
+	return nil
+}
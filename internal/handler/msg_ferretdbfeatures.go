@@ -0,0 +1,224 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/stages"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Feature support statuses returned by `ferretdbFeatures` command.
+const (
+	featureFull        = "full"
+	featurePartial     = "partial"
+	featureUnsupported = "unsupported"
+)
+
+// feature describes the support status of a single operator, stage, or index type.
+type feature struct {
+	name   string
+	status string
+	issue  string // empty for fully supported features
+}
+
+// issueURL returns a link to FerretDB issue with the given number.
+func issueURL(n int) string {
+	return fmt.Sprintf("https://github.com/FerretDB/FerretDB/issues/%d", n)
+}
+
+// issueSearchURL returns a link to open FerretDB issues that mention the given feature
+// for features without a dedicated tracking issue.
+func issueSearchURL(name string) string {
+	return "https://github.com/FerretDB/FerretDB/issues?q=" + url.QueryEscape("is:issue is:open "+name)
+}
+
+// partialStages maps supported aggregation stages with known limitations to their issues.
+var partialStages = map[string]string{
+	"$collStats": issueURL(2341),
+	"$group":     issueURL(2275),
+	"$lookup":    issueURL(1427),
+}
+
+// queryFeatures lists query operators.
+var queryFeatures = []feature{
+	// sorted alphabetically
+	{name: "$all", status: featureFull},
+	{name: "$and", status: featureFull},
+	{name: "$bitsAllClear", status: featurePartial, issue: issueURL(508)},
+	{name: "$bitsAllSet", status: featurePartial, issue: issueURL(508)},
+	{name: "$bitsAnyClear", status: featurePartial, issue: issueURL(508)},
+	{name: "$bitsAnySet", status: featurePartial, issue: issueURL(508)},
+	{name: "$comment", status: featureFull},
+	{name: "$elemMatch", status: featurePartial, issue: issueURL(730)},
+	{name: "$eq", status: featureFull},
+	{name: "$exists", status: featureFull},
+	{name: "$expr", status: featurePartial, issue: issueURL(3170)},
+	{name: "$geoIntersects", status: featureUnsupported, issue: issueSearchURL("$geoIntersects")},
+	{name: "$geoWithin", status: featureUnsupported, issue: issueSearchURL("$geoWithin")},
+	{name: "$gt", status: featureFull},
+	{name: "$gte", status: featureFull},
+	{name: "$in", status: featureFull},
+	{name: "$jsonSchema", status: featureUnsupported, issue: issueSearchURL("$jsonSchema")},
+	{name: "$lt", status: featureFull},
+	{name: "$lte", status: featureFull},
+	{name: "$mod", status: featureFull},
+	{name: "$ne", status: featureFull},
+	{name: "$near", status: featureUnsupported, issue: issueSearchURL("$near")},
+	{name: "$nearSphere", status: featureUnsupported, issue: issueSearchURL("$nearSphere")},
+	{name: "$nin", status: featureFull},
+	{name: "$nor", status: featureFull},
+	{name: "$not", status: featureFull},
+	{name: "$or", status: featureFull},
+	{name: "$regex", status: featureFull},
+	{name: "$sampleRate", status: featureFull},
+	{name: "$size", status: featureFull},
+	{name: "$text", status: featureUnsupported, issue: issueSearchURL("$text")},
+	{name: "$type", status: featureFull},
+	{name: "$where", status: featureUnsupported, issue: issueSearchURL("$where")},
+	// please keep sorted alphabetically
+}
+
+// updateFeatures lists update operators.
+var updateFeatures = []feature{
+	// sorted alphabetically
+	{name: "$addToSet", status: featureFull},
+	{name: "$bit", status: featureFull},
+	{name: "$currentDate", status: featureFull},
+	{name: "$inc", status: featureFull},
+	{name: "$max", status: featureFull},
+	{name: "$min", status: featureFull},
+	{name: "$mul", status: featureFull},
+	{name: "$pop", status: featureFull},
+	{name: "$pull", status: featureFull},
+	{name: "$pullAll", status: featureFull},
+	{name: "$push", status: featureFull},
+	{name: "$rename", status: featureFull},
+	{name: "$set", status: featureFull},
+	{name: "$setOnInsert", status: featureFull},
+	{name: "$unset", status: featureFull},
+	// please keep sorted alphabetically
+}
+
+// indexTypeFeatures lists index types.
+var indexTypeFeatures = []feature{
+	{name: "single", status: featureFull},
+	{name: "compound", status: featureFull},
+	{name: "2d", status: featureUnsupported, issue: issueSearchURL("2d index")},
+	{name: "2dsphere", status: featureUnsupported, issue: issueSearchURL("2dsphere index")},
+	{name: "hashed", status: featurePartial, issue: issueSearchURL("hashed index")},
+	{name: "text", status: featureUnsupported, issue: issueSearchURL("text index")},
+	{name: "wildcard", status: featureUnsupported, issue: issueSearchURL("wildcard index")},
+}
+
+// stageFeatures returns aggregation stages.
+func stageFeatures() []feature {
+	res := make([]feature, 0, len(stages.Stages)+len(stages.Unsupported()))
+
+	for _, name := range slices.Sorted(maps.Keys(stages.Stages)) {
+		f := feature{name: name, status: featureFull}
+
+		if issue, ok := partialStages[name]; ok {
+			f.status = featurePartial
+			f.issue = issue
+		}
+
+		res = append(res, f)
+	}
+
+	for _, name := range stages.Unsupported() {
+		res = append(res, feature{name: name, status: featureUnsupported, issue: issueSearchURL(name)})
+	}
+
+	return sortedFeatures(res)
+}
+
+// aggregationOperatorFeatures returns standard aggregation operators.
+func aggregationOperatorFeatures() []feature {
+	res := make([]feature, 0, len(operators.Operators)+len(operators.Unsupported()))
+
+	for _, name := range slices.Sorted(maps.Keys(operators.Operators)) {
+		res = append(res, feature{name: name, status: featureFull})
+	}
+
+	for _, name := range operators.Unsupported() {
+		res = append(res, feature{name: name, status: featureUnsupported, issue: issueSearchURL(name)})
+	}
+
+	return sortedFeatures(res)
+}
+
+// sortedFeatures sorts features by name.
+func sortedFeatures(features []feature) []feature {
+	slices.SortFunc(features, func(a, b feature) int { return cmp.Compare(a.name, b.name) })
+
+	return features
+}
+
+// featuresArray converts features to an array of documents.
+func featuresArray(features []feature) *types.Array {
+	res := types.MakeArray(len(features))
+
+	for _, f := range features {
+		doc := must.NotFail(types.NewDocument(
+			"name", f.name,
+			"status", f.status,
+		))
+
+		if f.issue != "" {
+			doc.Set("issue", f.issue)
+		}
+
+		res.Append(doc)
+	}
+
+	return res
+}
+
+// MsgFerretDBFeatures implements `ferretdbFeatures` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgFerretDBFeatures(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"stages", featuresArray(stageFeatures()),
+			"aggregationOperators", featuresArray(aggregationOperatorFeatures()),
+			"queryOperators", featuresArray(queryFeatures),
+			"updateOperators", featuresArray(updateFeatures),
+			"indexTypes", featuresArray(indexTypeFeatures),
+			"ok", float64(1),
+		)),
+	)
+}
@@ -19,7 +19,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/FerretDB/wire"
@@ -51,6 +53,11 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 
 	username := conninfo.Get(connCtx).Username()
 
+	lsid, err := optionalSessionID(params.LSID)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -84,6 +91,21 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		cInfo = cList.Collections[0]
 	}
 
+	if len(cList.Collections) > 0 && (params.Min != nil || params.Max != nil || params.ReturnKey) {
+		if params.HintIndexKey, err = hintIndexKey(connCtx, coll, params.Hint); err != nil {
+			return nil, err
+		}
+
+		if err = common.ValidateIndexBounds(params.HintIndexKey, params.Min, params.Max); err != nil {
+			return nil, err
+		}
+
+		if (params.Min != nil || params.Max != nil) && params.Sort.Len() == 0 {
+			// documents are returned in the index order, as if the index was scanned
+			params.Sort = params.HintIndexKey
+		}
+	}
+
 	capped := cInfo.Capped()
 	if params.Tailable {
 		if !capped {
@@ -130,7 +152,7 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 	// closer accumulates all things that should be closed / canceled.
 	closer := iterator.NewMultiCloser(iterator.CloserFunc(cancel))
 
-	iter, err := h.makeFindIter(queryRes.Iter, closer, params)
+	iter, err := h.makeFindIter(ctx, queryRes, closer, params)
 	if err != nil {
 		return nil, handleMaxTimeMSError(err, params.MaxTimeMS, "find")
 	}
@@ -145,15 +167,18 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		t = cursor.TailableAwait
 	}
 
+	data := &findCursorData{
+		coll:       coll,
+		qp:         qp,
+		findParams: params,
+	}
+
 	c := h.cursors.NewCursor(ctx, iter, &cursor.NewParams{
-		Data: &findCursorData{
-			coll:       coll,
-			qp:         qp,
-			findParams: params,
-		},
+		Data:         data,
 		DB:           params.DB,
 		Collection:   params.Collection,
 		Username:     username,
+		Session:      lsid,
 		Type:         t,
 		ShowRecordID: params.ShowRecordId,
 	})
@@ -175,7 +200,9 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		slog.Bool("single_batch", params.SingleBatch),
 	)
 
-	if params.SingleBatch || len(docs) < int(params.BatchSize) {
+	limitReached := data.limitReached(len(docs))
+
+	if params.SingleBatch || limitReached || len(docs) < int(params.BatchSize) {
 		c.Close()
 
 		// It is not entirely clear if we should do that; more tests are needed.
@@ -185,6 +212,8 @@ func (h *Handler) MsgFind(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 
 		// let the client know that there are no more results
 		cursorID = 0
+	} else {
+		c.Readahead(int(params.BatchSize))
 	}
 
 	firstBatch := types.MakeArray(len(docs))
@@ -208,19 +237,27 @@ type findCursorData struct {
 	coll       backends.Collection
 	qp         *backends.QueryParams
 	findParams *common.FindParams
+	returned   atomic.Int64 // number of documents returned to the client
+}
+
+// limitReached adds n to the number of returned documents
+// and reports whether the limit of the find command is reached.
+//
+// Like MongoDB, the cursor should be closed in that case,
+// so the client does not need to send one more getMore command to get an empty batch.
+func (data *findCursorData) limitReached(n int) bool {
+	returned := data.returned.Add(int64(n))
+
+	return data.findParams.Limit > 0 && returned >= data.findParams.Limit
 }
 
 // makeFindQueryParams creates the backend's query parameters for the find command.
 func (h *Handler) makeFindQueryParams(ctx context.Context, params *common.FindParams, cInfo *backends.CollectionInfo) (*backends.QueryParams, error) { //nolint:lll // for readability
-	qp := &backends.QueryParams{
-		Comment: params.Comment,
-	}
+	qp := new(backends.QueryParams)
 
 	var err error
-	if params.Filter != nil {
-		if qp.Comment, err = common.GetOptionalParam(params.Filter, "$comment", qp.Comment); err != nil {
-			return nil, err
-		}
+	if qp.Comment, err = queryComment(ctx, params.Comment, params.Filter); err != nil {
+		return nil, err
 	}
 
 	if !h.DisablePushdown {
@@ -253,24 +290,16 @@ func (h *Handler) makeFindQueryParams(ctx context.Context, params *common.FindPa
 	}
 
 	switch {
+	case params.Sort.Len() == 1 && params.Sort.Keys()[0] == "$natural":
+		// Natural order is known only to the backend, so it is pushed down even if pushdown is disabled
+		qp.Sort = params.Sort
 	case h.DisablePushdown:
 		// Pushdown disabled
 	case params.Sort.Len() == 0 && cInfo.Capped():
 		// Pushdown default recordID sorting for capped collections
 		qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
-	case params.Sort.Len() == 1:
-		if params.Sort.Keys()[0] != "$natural" {
-			break
-		}
-
-		if !cInfo.Capped() {
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"$natural sort for non-capped collection is not supported.",
-				"find",
-			)
-		}
-
+	case params.Sort.Len() != 0 && h.EnableSortPushdown:
+		// Backend applies it only if an index could be used
 		qp.Sort = params.Sort
 	}
 
@@ -290,17 +319,28 @@ func (h *Handler) makeFindQueryParams(ctx context.Context, params *common.FindPa
 
 // makeFindIter creates an iterator chain for the find command.
 //
-// Iter is passed from the backend's query.
+// Query result is passed from the backend's query.
 // All iterators, including the initial one, are added to the passed closer,
 // and the returned iterator is wrapped with it.
+// Documents are not sorted again if the backend already did that.
 //
 //nolint:lll // for readability
-func (h *Handler) makeFindIter(iter types.DocumentsIterator, closer *iterator.MultiCloser, params *common.FindParams) (types.DocumentsIterator, error) {
+func (h *Handler) makeFindIter(ctx context.Context, queryRes *backends.QueryResult, closer *iterator.MultiCloser, params *common.FindParams) (types.DocumentsIterator, error) {
+	iter := queryRes.Iter
 	closer.Add(iter)
 
 	iter = common.FilterIterator(iter, closer, params.Filter)
 
-	iter, err := common.SortIterator(iter, closer, params.Sort)
+	iter = common.IndexBoundsIterator(iter, closer, params.HintIndexKey, params.Min, params.Max)
+
+	sort := params.Sort
+	if queryRes.SortPushdown {
+		sort = nil
+	}
+
+	ctx = common.WithMemoryTracker(ctx, common.NewMemoryTracker(h.OperationMemoryLimit, params.AllowDiskUse))
+
+	iter, err := common.SortIterator(ctx, iter, closer, sort)
 	if err != nil {
 		closer.Close()
 
@@ -320,6 +360,12 @@ func (h *Handler) makeFindIter(iter types.DocumentsIterator, closer *iterator.Mu
 
 	iter = common.LimitIterator(iter, closer, params.Limit)
 
+	if params.ReturnKey {
+		// projection is ignored if only index keys are returned
+		iter = common.ReturnKeyIterator(iter, closer, params.HintIndexKey)
+		return iterator.WithClose(iter, closer.Close), nil
+	}
+
 	if iter, err = common.ProjectionIterator(iter, closer, params.Projection, params.Filter); err != nil {
 		closer.Close()
 		return nil, lazyerrors.Error(err)
@@ -328,6 +374,46 @@ func (h *Handler) makeFindIter(iter types.DocumentsIterator, closer *iterator.Mu
 	return iterator.WithClose(iter, closer.Close), nil
 }
 
+// hintIndexKey returns the key pattern of the collection index specified by the given hint
+// (index name or key pattern), or nil if the hint does not specify an index.
+func hintIndexKey(ctx context.Context, coll backends.Collection, hint any) (*types.Document, error) {
+	var name string
+	var key []backends.IndexKeyPair
+
+	switch hint := hint.(type) {
+	case string:
+		name = hint
+	case *types.Document:
+		if hint.Len() == 0 || hint.Has("$natural") {
+			return nil, nil
+		}
+
+		var err error
+		if key, err = processIndexKey("find", hint); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+
+	res, err := coll.ListIndexes(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, index := range res.Indexes {
+		if index.Name == name || (key != nil && slices.Equal(index.Key, key)) {
+			return indexKeyDocument(index.Key), nil
+		}
+	}
+
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrBadValue,
+		"hint provided does not correspond to an existing index",
+		"find",
+	)
+}
+
 // handleMaxTimeMSError returns the MaxTimeMSExpired error if provided error is a result of context cancellation.
 // The MaxTimeMSExpired error won't be returned if maxTimeMS wasn't set.
 func handleMaxTimeMSError(err error, maxTimeMS int64, cmd string) error {
@@ -117,6 +117,22 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		return nil, lazyerrors.Error(err)
 	}
 
+	if !params.Remove {
+		if params.Upsert {
+			if err = h.checkDatabaseCase(ctx, params.DB, "findAndModify"); err != nil {
+				return nil, err
+			}
+
+			if err = h.checkCollectionsQuota(ctx, db, params.DB, params.Collection, "findAndModify"); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = h.checkUpdateQuota(ctx, c, params.Upsert, params.DB, params.Collection, "findAndModify"); err != nil {
+			return nil, err
+		}
+	}
+
 	cancel := func() {}
 	if params.MaxTimeMS != 0 {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2168
@@ -132,6 +148,10 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		qp.Filter = params.Query
 	}
 
+	if qp.Comment, err = queryComment(ctx, params.Comment, params.Query); err != nil {
+		return nil, err
+	}
+
 	queryRes, err := c.Query(ctx, &qp)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -141,7 +161,10 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 
 	iter := common.FilterIterator(queryRes.Iter, closer, params.Query)
 
-	iter, err = common.SortIterator(iter, closer, params.Sort)
+	// findAndModify does not have allowDiskUse option
+	sortCtx := common.WithMemoryTracker(ctx, common.NewMemoryTracker(h.OperationMemoryLimit, false))
+
+	iter, err = common.SortIterator(sortCtx, iter, closer, params.Sort)
 	if err != nil {
 		var pathErr *types.PathError
 		if errors.As(err, &pathErr) && pathErr.Code() == types.ErrPathElementEmpty {
@@ -190,6 +213,10 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		HasUpdateOperators: params.HasUpdateOperators,
 	}
 
+	if update.Versioned, err = collectionVersioned(ctx, db, params.Collection); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	// TODO https://github.com/FerretDB/FerretDB/issues/2168
 	updateRes, err := common.UpdateDocument(ctx, c, "findAndModify", iter, update)
 	if err != nil {
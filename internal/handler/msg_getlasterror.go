@@ -0,0 +1,51 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgGetLastError implements deprecated `getLastError` command.
+//
+// All writes are acknowledged, and their errors are returned by write commands themselves,
+// so there is never a pending error to report.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgGetLastError(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	common.Ignored(document, h.L, "w", "j", "wtimeout", "fsync", "comment")
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"n", int32(0),
+			"syncMillis", int32(0),
+			"writtenTo", types.Null,
+			"err", types.Null,
+			"ok", float64(1),
+		)),
+	)
+}
@@ -206,10 +206,19 @@ func (h *Handler) MsgGetMore(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 
 	switch c.Type {
 	case cursor.Normal:
-		if nextBatch.Len() < int(batchSize) {
+		data, _ := c.Data.(*findCursorData)
+
+		switch {
+		case nextBatch.Len() < int(batchSize):
 			// The cursor is already closed and removed;
 			// let the client know that there are no more results.
 			cursorID = 0
+		case data != nil && data.limitReached(nextBatch.Len()):
+			c.Close()
+
+			cursorID = 0
+		default:
+			c.Readahead(int(batchSize))
 		}
 
 	case cursor.Tailable:
@@ -228,7 +237,7 @@ func (h *Handler) MsgGetMore(connCtx context.Context, msg *wire.OpMsg) (*wire.Op
 			closer := iterator.NewMultiCloser()
 			defer closer.Close()
 
-			iter, err := h.makeFindIter(queryRes.Iter, closer, data.findParams)
+			iter, err := h.makeFindIter(connCtx, queryRes, closer, data.findParams)
 			if err != nil {
 				return nil, lazyerrors.Error(err)
 			}
@@ -345,7 +354,7 @@ func (h *Handler) awaitData(ctx context.Context, params *awaitDataParams) (resBa
 
 		var iter types.DocumentsIterator
 
-		iter, err = h.makeFindIter(queryRes.Iter, closer, data.findParams)
+		iter, err = h.makeFindIter(ctx, queryRes, closer, data.findParams)
 		if err != nil {
 			return
 		}
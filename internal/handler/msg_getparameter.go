@@ -74,11 +74,46 @@ func (h *Handler) MsgGetParameter(connCtx context.Context, msg *wire.OpMsg) (*wi
 			"settableAtRuntime", false,
 			"settableAtStartup", false,
 		)),
+		"localLogicalSessionTimeoutMinutes", must.NotFail(types.NewDocument(
+			"value", h.logicalSessionTimeoutMinutes(),
+			"settableAtRuntime", true,
+			"settableAtStartup", true,
+		)),
+		"logicalSessionRefreshMillis", must.NotFail(types.NewDocument(
+			"value", h.logicalSessionRefreshMillis(),
+			"settableAtRuntime", true,
+			"settableAtStartup", true,
+		)),
+		"passwordMinLength", must.NotFail(types.NewDocument(
+			"value", h.passwordMinLength.Load(),
+			"settableAtRuntime", true,
+			"settableAtStartup", true,
+		)),
+		"passwordRequireComplexity", must.NotFail(types.NewDocument(
+			"value", h.passwordRequireComplexity.Load(),
+			"settableAtRuntime", true,
+			"settableAtStartup", true,
+		)),
 		"quiet", must.NotFail(types.NewDocument(
 			"value", false,
 			"settableAtRuntime", true,
 			"settableAtStartup", true,
 		)),
+		"readOnly", must.NotFail(types.NewDocument(
+			"value", h.readOnly.Load(),
+			"settableAtRuntime", true,
+			"settableAtStartup", true,
+		)),
+		"scramIterationCount", must.NotFail(types.NewDocument(
+			"value", h.scramIterationCount.Load(),
+			"settableAtRuntime", true,
+			"settableAtStartup", true,
+		)),
+		"scramSHA256IterationCount", must.NotFail(types.NewDocument(
+			"value", h.scramSHA256IterationCount.Load(),
+			"settableAtRuntime", true,
+			"settableAtStartup", true,
+		)),
 		// parameters are alphabetically ordered
 	))
 
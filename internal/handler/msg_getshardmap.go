@@ -0,0 +1,63 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgGetShardMap implements `getShardMap` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgGetShardMap(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	name, host := h.shard()
+
+	// host without replica set name prefix
+	_, addr, ok := strings.Cut(host, "/")
+	if !ok {
+		addr = host
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"map", must.NotFail(types.NewDocument(
+				name, host,
+			)),
+			"hosts", must.NotFail(types.NewDocument(
+				addr, name,
+			)),
+			"connStrings", must.NotFail(types.NewDocument(
+				host, name,
+			)),
+			"ok", float64(1),
+		)),
+	)
+}
@@ -23,8 +23,10 @@ import (
 
 	"github.com/FerretDB/wire"
 
+	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -51,10 +53,14 @@ func (h *Handler) MsgHello(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMs
 // hello checks client metadata and returns hello's document fields.
 // It also returns response for deprecated `isMaster` and `ismaster` commands.
 func (h *Handler) hello(ctx context.Context, doc *types.Document, tcpHost, name string) (*types.Document, error) {
-	if err := checkClientMetadata(ctx, doc); err != nil {
+	if err := h.checkClientMetadata(ctx, doc); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err := checkClientWireVersion(doc); err != nil {
+		return nil, err
+	}
+
 	res := must.NotFail(types.NewDocument())
 
 	switch doc.Command() {
@@ -111,11 +117,11 @@ func (h *Handler) hello(ctx context.Context, doc *types.Document, tcpHost, name
 	res.Set("maxMessageSizeBytes", int32(wire.MaxMsgLen))
 	res.Set("maxWriteBatchSize", maxWriteBatchSize)
 	res.Set("localTime", time.Now())
-	res.Set("logicalSessionTimeoutMinutes", logicalSessionTimeoutMinutes)
+	res.Set("logicalSessionTimeoutMinutes", h.logicalSessionTimeoutMinutes())
 	res.Set("connectionId", connectionID)
 	res.Set("minWireVersion", common.MinWireVersion)
 	res.Set("maxWireVersion", common.MaxWireVersion)
-	res.Set("readOnly", false)
+	res.Set("readOnly", h.readOnly.Load())
 
 	if resSupportedMechs != nil && resSupportedMechs.Len() != 0 {
 		res.Set("saslSupportedMechs", resSupportedMechs)
@@ -126,6 +132,77 @@ func (h *Handler) hello(ctx context.Context, doc *types.Document, tcpHost, name
 	return res, nil
 }
 
+// checkClientWireVersion checks that the range of wire versions sent by the client in `internalClient` field
+// intersects with the supported range.
+//
+// Without that check, a client that requires a newer wire version would fail later with less clear errors.
+func checkClientWireVersion(doc *types.Document) error {
+	v, _ := doc.Get("internalClient")
+	if v == nil {
+		return nil
+	}
+
+	internalClient, ok := v.(*types.Document)
+	if !ok {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s.internalClient' is the wrong type '%s', expected type 'object'",
+				doc.Command(), handlerparams.AliasFromType(v),
+			),
+			"internalClient",
+		)
+	}
+
+	var versions [2]int64
+
+	for i, field := range []string{"minWireVersion", "maxWireVersion"} {
+		v, _ = internalClient.Get(field)
+		if v == nil {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMissingField,
+				fmt.Sprintf("BSON field '%s.internalClient.%s' is missing but a required field", doc.Command(), field),
+				"internalClient",
+			)
+		}
+
+		version, err := handlerparams.GetWholeNumberParam(v)
+		if err != nil || version < 0 {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("BSON field '%s.internalClient.%s' should be a non-negative whole number", doc.Command(), field),
+				"internalClient",
+			)
+		}
+
+		versions[i] = version
+	}
+
+	clientMin, clientMax := versions[0], versions[1]
+
+	if clientMin > clientMax {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Client min wire version %d is greater than max wire version %d", clientMin, clientMax),
+			"internalClient",
+		)
+	}
+
+	if clientMin > int64(common.MaxWireVersion) || clientMax < int64(common.MinWireVersion) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			fmt.Sprintf(
+				"Client wire versions (%d, %d) are incompatible with FerretDB wire versions (%d, %d), "+
+					"which correspond to MongoDB %s. Please use a client compatible with that MongoDB version.",
+				clientMin, clientMax, common.MinWireVersion, common.MaxWireVersion, version.Get().MongoDBVersion,
+			),
+			"internalClient",
+		)
+	}
+
+	return nil
+}
+
 // getUserSupportedMechs returns supported mechanisms for the given user.
 // If the user was not found, it returns nil.
 func (h *Handler) getUserSupportedMechs(ctx context.Context, db, username string) (*types.Array, error) {
@@ -0,0 +1,83 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestCheckClientWireVersion(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		internalClient any // nil if not set
+		code           handlererrors.ErrorCode
+	}{
+		"NotSet": {},
+		"Supported": {
+			internalClient: must.NotFail(types.NewDocument("minWireVersion", int32(0), "maxWireVersion", common.MaxWireVersion)),
+		},
+		"Intersects": {
+			internalClient: must.NotFail(types.NewDocument("minWireVersion", int32(17), "maxWireVersion", int64(25))),
+		},
+		"TooNew": {
+			internalClient: must.NotFail(types.NewDocument("minWireVersion", common.MaxWireVersion+1, "maxWireVersion", int32(25))),
+			code:           handlererrors.ErrNotImplemented,
+		},
+		"WrongType": {
+			internalClient: "foo",
+			code:           handlererrors.ErrTypeMismatch,
+		},
+		"Missing": {
+			internalClient: must.NotFail(types.NewDocument("maxWireVersion", int32(25))),
+			code:           handlererrors.ErrMissingField,
+		},
+		"Negative": {
+			internalClient: must.NotFail(types.NewDocument("minWireVersion", int32(-1), "maxWireVersion", int32(25))),
+			code:           handlererrors.ErrBadValue,
+		},
+		"MinGreaterThanMax": {
+			internalClient: must.NotFail(types.NewDocument("minWireVersion", int32(21), "maxWireVersion", int32(17))),
+			code:           handlererrors.ErrBadValue,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			doc := must.NotFail(types.NewDocument("hello", int32(1)))
+			if tc.internalClient != nil {
+				doc.Set("internalClient", tc.internalClient)
+			}
+
+			err := checkClientWireVersion(doc)
+			if tc.code == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var cmdErr *handlererrors.CommandError
+			require.ErrorAs(t, err, &cmdErr)
+			assert.Equal(t, tc.code, cmdErr.Code())
+		})
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// errInvalidExport indicates that the export stream is truncated or corrupted.
+var errInvalidExport = errors.New("invalid export stream")
+
+// MsgImportCollection implements `importCollection` command.
+//
+// Documents are inserted block by block after the block's checksum is verified,
+// so the import is not atomic: documents of already verified blocks stay in the collection on error.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgImportCollection(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	common.Ignored(document, h.L, "comment", "writeConcern")
+
+	command := document.Command()
+
+	ns, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := common.GetRequiredParam[string](document, "from")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.checkExportStore(command); err != nil {
+		return nil, err
+	}
+
+	dbName, cName, err := handlerparams.SplitNamespace(ns, command)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s'", ns)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid collection name: %s", cName)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	r, err := h.exportStore.Open(connCtx, from)
+	if err != nil {
+		return nil, objectStoreError(err, from, command)
+	}
+
+	defer r.Close() //nolint:errcheck // we are only reading it
+
+	ir := newImportReader(r)
+	ir.insert = func(docs []*types.Document) error {
+		return insertBatches(connCtx, c, docs, h.BatchSize)
+	}
+
+	err = ir.importAll()
+
+	switch {
+	case err == nil:
+		// nothing
+	case errors.Is(err, errInvalidExport):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrOperationFailed,
+			fmt.Sprintf("Failed to import %q after %d documents: %s", from, ir.imported, err),
+			command,
+		)
+	case backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrDuplicateKeyInsert,
+			fmt.Sprintf("E11000 duplicate key error collection: %s.%s", dbName, cName),
+			command,
+		)
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"imported", ir.imported,
+			"ok", float64(1),
+		)),
+	)
+}
+
+// insertBatches inserts documents into the collection in batches of the given size.
+//
+// Backend errors are returned as is, so the caller could check their codes.
+func insertBatches(ctx context.Context, c backends.Collection, docs []*types.Document, batchSize int) error {
+	for batch := range slices.Chunk(docs, batchSize) {
+		if _, err := c.InsertAll(ctx, &backends.InsertAllParams{Docs: batch}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importReader reads the export stream written by exportWriter.
+type importReader struct {
+	r     *bufio.Reader
+	block hash.Hash
+
+	// insert is called for each verified block of the current collection
+	insert func([]*types.Document) error
+
+	docs     []*types.Document // documents of the current block
+	n        int64             // documents of the current collection read
+	imported int64             // documents of all collections inserted
+}
+
+// newImportReader returns a new importReader for the given stream.
+func newImportReader(r io.Reader) *importReader {
+	return &importReader{
+		r:     bufio.NewReader(r),
+		block: sha256.New(),
+	}
+}
+
+// importAll reads the stream of a single collection, verifies it, and inserts documents.
+func (ir *importReader) importAll() error {
+	if _, err := ir.readHeader(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: unexpected end of stream", errInvalidExport)
+		}
+
+		return err
+	}
+
+	if err := ir.readDocuments(); err != nil {
+		return err
+	}
+
+	if _, err := ir.r.ReadByte(); err != io.EOF {
+		return fmt.Errorf("%w: unexpected data after trailer", errInvalidExport)
+	}
+
+	return nil
+}
+
+// readHeader reads the stream header and returns its `$export` document.
+//
+// It returns io.EOF if there are no more streams.
+func (ir *importReader) readHeader() (*types.Document, error) {
+	header, _, err := ir.next()
+	if err != nil {
+		return nil, err
+	}
+
+	v, _ := header.Get("$export")
+
+	h, ok := v.(*types.Document)
+	if header.Len() != 1 || !ok {
+		return nil, fmt.Errorf("%w: missing header", errInvalidExport)
+	}
+
+	if version, _ := h.Get("version"); version != exportVersion {
+		return nil, fmt.Errorf("%w: unsupported version %v", errInvalidExport, version)
+	}
+
+	if ns, _ := h.Get("ns"); ns == nil {
+		return nil, fmt.Errorf("%w: missing namespace in header", errInvalidExport)
+	}
+
+	return h, nil
+}
+
+// readDocuments reads blocks of documents of the current collection up to and including the trailer,
+// and inserts them after verification.
+func (ir *importReader) readDocuments() error {
+	ir.n = 0
+
+	for {
+		doc, raw, err := ir.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("%w: unexpected end of stream", errInvalidExport)
+			}
+
+			return err
+		}
+
+		switch doc.Command() {
+		case "$checksum":
+			if err = ir.checkBlock(doc); err != nil {
+				return err
+			}
+
+			// backend errors are returned as is, so the caller could check their codes
+			if err = ir.insert(ir.docs); err != nil {
+				return err
+			}
+
+			ir.imported += int64(len(ir.docs))
+			ir.docs = ir.docs[:0]
+			ir.block.Reset()
+
+		case "$end":
+			if len(ir.docs) > 0 {
+				return fmt.Errorf("%w: missing checksum of the last block", errInvalidExport)
+			}
+
+			v, _ := doc.GetByPath(types.NewStaticPath("$end", "n"))
+			if n, _ := v.(int64); n != ir.n || doc.Len() != 1 {
+				return fmt.Errorf("%w: invalid trailer %s", errInvalidExport, types.FormatAnyValue(doc))
+			}
+
+			return nil
+
+		default:
+			if err = doc.ValidateData(); err != nil {
+				return fmt.Errorf("%w: %s", errInvalidExport, err)
+			}
+
+			must.NotFail(ir.block.Write(raw))
+			ir.docs = append(ir.docs, doc)
+			ir.n++
+
+			if len(ir.docs) > exportBlockSize {
+				return fmt.Errorf("%w: block is too large", errInvalidExport)
+			}
+		}
+	}
+}
+
+// checkBlock verifies the current block with the given `$checksum` document.
+func (ir *importReader) checkBlock(doc *types.Document) error {
+	v, _ := doc.Get("$checksum")
+
+	checksum, ok := v.(*types.Document)
+	if !ok || doc.Len() != 1 {
+		return fmt.Errorf("%w: invalid checksum %s", errInvalidExport, types.FormatAnyValue(doc))
+	}
+
+	n, _ := checksum.Get("n")
+	sum, _ := checksum.Get("sha256")
+
+	if n != int32(len(ir.docs)) || sum != hex.EncodeToString(ir.block.Sum(nil)) {
+		first := ir.n - int64(len(ir.docs)) + 1
+		return fmt.Errorf("%w: checksum mismatch for documents %d-%d", errInvalidExport, first, ir.n)
+	}
+
+	return nil
+}
+
+// next reads the next document and returns it together with its raw bytes.
+//
+// It returns io.EOF if the stream ends before the next document.
+func (ir *importReader) next() (*types.Document, []byte, error) {
+	var l [4]byte
+
+	if _, err := io.ReadFull(ir.r, l[:]); err != nil {
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil, nil, io.EOF
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return nil, nil, fmt.Errorf("%w: unexpected end of stream", errInvalidExport)
+		default:
+			return nil, nil, lazyerrors.Error(err)
+		}
+	}
+
+	size := int(binary.LittleEndian.Uint32(l[:]))
+	if size < 5 || size > wire.MaxMsgLen {
+		return nil, nil, fmt.Errorf("%w: invalid document size %d", errInvalidExport, size)
+	}
+
+	raw := make([]byte, size)
+	copy(raw, l[:])
+
+	if _, err := io.ReadFull(ir.r, raw[4:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, nil, fmt.Errorf("%w: unexpected end of stream", errInvalidExport)
+		}
+
+		return nil, nil, lazyerrors.Error(err)
+	}
+
+	doc, err := bson.ToDocument(wirebson.RawDocument(raw))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errInvalidExport, err)
+	}
+
+	return doc, raw, nil
+}
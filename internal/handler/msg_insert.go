@@ -25,6 +25,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -79,6 +80,19 @@ func (h *Handler) MsgInsert(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = h.checkDatabaseCase(connCtx, params.DB, "insert"); err != nil {
+		return nil, err
+	}
+
+	if err = h.checkCollectionsQuota(connCtx, db, params.DB, params.Collection, "insert"); err != nil {
+		return nil, err
+	}
+
+	versioned, err := collectionVersioned(connCtx, db, params.Collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	docsIter := params.Docs.Iterator()
 	defer docsIter.Close()
 
@@ -110,6 +124,32 @@ func (h *Handler) MsgInsert(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 				doc.Set("_id", types.NewObjectID())
 			}
 
+			if versioned {
+				doc.Set(backends.VersionField, int64(1))
+			}
+
+			var size int
+			if size, err = documentSize(doc); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if size > h.MaxBsonObjectSizeBytes {
+				writeErrors = append(writeErrors, &mongo.WriteError{
+					Index: i,
+					Code:  int(handlererrors.ErrBadValue),
+					Message: fmt.Sprintf(
+						"object to insert too large. size in bytes: %d, max size: %d",
+						size, h.MaxBsonObjectSizeBytes,
+					),
+				})
+
+				if params.Ordered {
+					break
+				}
+
+				continue
+			}
+
 			// TODO https://github.com/FerretDB/FerretDB/issues/3454
 			if err = doc.ValidateData(); err == nil {
 				docs = append(docs, doc)
@@ -144,6 +184,31 @@ func (h *Handler) MsgInsert(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 			}
 		}
 
+		var n int
+		n, err = h.insertQuota(connCtx, c, docs, params.DB, params.Collection, "insert")
+
+		var quotaErr *handlererrors.CommandError
+		if err != nil && !errors.As(err, &quotaErr) {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if quotaErr != nil {
+			for _, i := range docsIndexes[n:] {
+				writeErrors = append(writeErrors, &mongo.WriteError{
+					Index:   i,
+					Code:    int(quotaErr.Code()),
+					Message: quotaErr.Err().Error(),
+				})
+
+				if params.Ordered {
+					done = true
+					break
+				}
+			}
+
+			docs, docsIndexes = docs[:n], docsIndexes[:n]
+		}
+
 		if _, err = c.InsertAll(connCtx, &backends.InsertAllParams{Docs: docs}); err == nil {
 			inserted += int32(len(docs))
 
@@ -178,6 +243,10 @@ func (h *Handler) MsgInsert(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 				break
 			}
 		}
+
+		if params.Ordered && len(writeErrors) > 0 {
+			break
+		}
 	}
 
 	res := must.NotFail(types.NewDocument(
@@ -203,3 +272,13 @@ func (h *Handler) MsgInsert(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		res,
 	)
 }
+
+// documentSize returns the size of the given document encoded as BSON.
+func documentSize(doc *types.Document) (int, error) {
+	b, err := must.NotFail(bson.FromDocument(doc)).Encode()
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return len(b), nil
+}
@@ -42,8 +42,9 @@ func (h *Handler) MsgIsMaster(connCtx context.Context, msg *wire.OpMsg) (*wire.O
 	return documentOpMsg(res)
 }
 
-// checkClientMetadata checks if the message does not contain client metadata after it was received already.
-func checkClientMetadata(ctx context.Context, doc *types.Document) error {
+// checkClientMetadata checks if the message does not contain client metadata after it was received already,
+// stores the application name and the driver information from it, and counts the driver in metrics.
+func (h *Handler) checkClientMetadata(ctx context.Context, doc *types.Document) error {
 	c, _ := doc.Get("client")
 	if c == nil {
 		return nil
@@ -59,5 +60,28 @@ func checkClientMetadata(ctx context.Context, doc *types.Document) error {
 
 	connInfo.SetMetadataRecv()
 
+	md, ok := c.(*types.Document)
+	if !ok {
+		return nil
+	}
+
+	driverName := metadataString(md, "driver", "name")
+	driverVersion := metadataString(md, "driver", "version")
+
+	connInfo.SetClientMetadata(metadataString(md, "application", "name"), driverName, driverVersion)
+
+	if driverName != "" {
+		h.ConnMetrics.Drivers.WithLabelValues(driverName, driverVersion).Inc()
+	}
+
 	return nil
 }
+
+// metadataString returns a string value of the client metadata document by the given path,
+// or empty string if it is not present or has a different type.
+func metadataString(md *types.Document, path ...string) string {
+	v, _ := md.GetByPath(types.NewStaticPath(path...))
+	s, _ := v.(string)
+
+	return s
+}
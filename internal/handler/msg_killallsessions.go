@@ -0,0 +1,134 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// sessionOwner represents a user that owns logical sessions.
+type sessionOwner struct {
+	user string
+	db   string
+}
+
+// MsgKillAllSessions implements `killAllSessions` command.
+//
+// An empty array kills all sessions of all users.
+// Authenticated users other than the administrative setup user can kill only their own sessions;
+// for them, an empty array kills all sessions of the current user.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgKillAllSessions(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	arr, err := common.GetRequiredParam[*types.Array](document, command)
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf("BSON field '%s' is the wrong type, expected type 'array'", command),
+			command,
+		)
+	}
+
+	owners := make(map[sessionOwner]struct{}, arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		var v any
+
+		_, v, err = iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		doc, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("BSON field '%s' must contain user objects", command),
+				command,
+			)
+		}
+
+		var owner sessionOwner
+
+		if owner.user, err = common.GetRequiredParam[string](doc, "user"); err != nil {
+			return nil, err
+		}
+
+		if owner.db, err = common.GetRequiredParam[string](doc, "db"); err != nil {
+			return nil, err
+		}
+
+		owners[owner] = struct{}{}
+	}
+
+	if username, _, _, db := conninfo.Get(connCtx).Auth(); username != "" && !h.isSetupAdmin(username, db) {
+		self := sessionOwner{user: username, db: db}
+
+		for owner := range owners {
+			if owner != self {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrUnauthorized,
+					fmt.Sprintf("not authorized to kill sessions of user %s@%s", owner.user, owner.db),
+					command,
+				)
+			}
+		}
+
+		owners[self] = struct{}{}
+	}
+
+	var ids []uuid.UUID
+
+	for _, s := range h.sessions.All() {
+		if _, ok := owners[sessionOwner{user: s.Username, db: s.DB}]; ok || len(owners) == 0 {
+			ids = append(ids, s.ID)
+		}
+	}
+
+	h.endSessions(ids)
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/password"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestKillAllSessions(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler(t, &NewOpts{
+		SetupUsername: "admin",
+		SetupPassword: password.WrapPassword("password"),
+	})
+
+	userCtx := func(username string) context.Context {
+		connInfo := conninfo.New()
+		connInfo.SetAuth(username, "password", nil, "admin")
+
+		return conninfo.Ctx(testutil.Ctx(t), connInfo)
+	}
+
+	alice, bob, admin := userCtx("alice"), userCtx("bob"), userCtx("admin")
+
+	startSession := func(ctx context.Context) uuid.UUID {
+		id := uuid.New()
+		username, _, _, db := conninfo.Get(ctx).Auth()
+		h.sessions.Refresh(id, username, db)
+
+		return id
+	}
+
+	killAllSessions := func(ctx context.Context, users ...string) error {
+		arr := wirebson.MakeArray(len(users))
+		for _, u := range users {
+			require.NoError(t, arr.Add(wirebson.MustDocument("user", u, "db", "admin")))
+		}
+
+		_, err := h.MsgKillAllSessions(ctx, wire.MustOpMsg("killAllSessions", arr, "$db", "admin"))
+
+		return err
+	}
+
+	sessionIDs := func() []uuid.UUID {
+		var res []uuid.UUID
+		for _, s := range h.sessions.All() {
+			res = append(res, s.ID)
+		}
+
+		return res
+	}
+
+	aliceID, bobID := startSession(alice), startSession(bob)
+
+	err := killAllSessions(alice, "bob")
+
+	var cmdErr *handlererrors.CommandError
+	require.ErrorAs(t, err, &cmdErr)
+	assert.Equal(t, handlererrors.ErrUnauthorized, cmdErr.Code())
+	assert.ElementsMatch(t, []uuid.UUID{aliceID, bobID}, sessionIDs())
+
+	require.NoError(t, killAllSessions(alice))
+	assert.Equal(t, []uuid.UUID{bobID}, sessionIDs())
+
+	require.NoError(t, killAllSessions(admin, "bob"))
+	assert.Empty(t, sessionIDs())
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgKillOp implements `killOp` command.
+//
+// Only background operations such as index builds could be killed;
+// killing other operations is a no-op.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgKillOp(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	command := document.Command()
+
+	common.Ignored(document, h.L, "comment")
+
+	v, _ := document.Get("op")
+	if v == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			`Did not provide "op" field`,
+			command,
+		)
+	}
+
+	id, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil || id < math.MinInt32 || id > math.MaxInt32 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf("BSON field 'killOp.op' is the wrong type '%s', expected type 'int'", handlerparams.AliasFromType(v)),
+			command,
+		)
+	}
+
+	h.operations.kill(int32(id))
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"info", "attempting to kill op",
+			"ok", float64(1),
+		)),
+	)
+}
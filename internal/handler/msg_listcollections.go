@@ -105,6 +105,10 @@ func (h *Handler) MsgListCollections(connCtx context.Context, msg *wire.OpMsg) (
 			options.Set("max", collection.CappedDocuments)
 		}
 
+		if collection.Versioned {
+			options.Set("versioned", true)
+		}
+
 		d.Set("options", options)
 
 		if collection.UUID != "" {
@@ -27,6 +27,10 @@ import (
 
 // MsgListCommands implements `listCommands` command.
 //
+// It returns all commands with help text and flags in the same format as MongoDB,
+// so clients like mongosh could use it for introspection and autocompletion.
+// Hidden commands (without help text) are not returned.
+//
 // The passed context is canceled when the client connection is closed.
 func (h *Handler) MsgListCommands(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
 	cmdList := must.NotFail(types.NewDocument())
@@ -39,8 +43,18 @@ func (h *Handler) MsgListCommands(connCtx context.Context, msg *wire.OpMsg) (*wi
 			continue
 		}
 
+		apiVersions := types.MakeArray(1)
+		if cmd.stableAPI {
+			apiVersions.Append("1")
+		}
+
 		cmdList.Set(name, must.NotFail(types.NewDocument(
 			"help", cmd.Help,
+			"requiresAuth", !cmd.anonymous,
+			"secondaryOk", cmd.secondaryOk,
+			"adminOnly", cmd.adminOnly,
+			"apiVersions", apiVersions,
+			"deprecatedApiVersions", types.MakeArray(0),
 		)))
 	}
 
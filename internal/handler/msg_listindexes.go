@@ -21,9 +21,13 @@ import (
 	"github.com/FerretDB/wire"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -37,6 +41,13 @@ func (h *Handler) MsgListIndexes(connCtx context.Context, msg *wire.OpMsg) (*wir
 		return nil, lazyerrors.Error(err)
 	}
 
+	lsidV, _ := document.Get("lsid")
+
+	lsid, err := optionalSessionID(lsidV)
+	if err != nil {
+		return nil, err
+	}
+
 	command := document.Command()
 
 	dbName, err := common.GetRequiredParam[string](document, "$db")
@@ -79,38 +90,69 @@ func (h *Handler) MsgListIndexes(connCtx context.Context, msg *wire.OpMsg) (*wir
 		return nil, lazyerrors.Error(err)
 	}
 
-	firstBatch := types.MakeArray(len(res.Indexes))
+	docs := make([]*types.Document, len(res.Indexes))
+	for i, index := range res.Indexes {
+		docs[i] = indexDocument(&index)
+	}
 
-	for _, index := range res.Indexes {
-		indexKey := must.NotFail(types.NewDocument())
+	cursorDoc, _ := document.Get("cursor")
+	if cursorDoc == nil {
+		cursorDoc = new(types.Document)
+	}
+
+	cd, ok := cursorDoc.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field 'listIndexes.cursor' is the wrong type '%s', expected type 'object'",
+				handlerparams.AliasFromType(cursorDoc),
+			),
+			command,
+		)
+	}
 
-		for _, key := range index.Key {
-			order := int32(1)
-			if key.Descending {
-				order = -1
-			}
+	batchSize := int64(len(docs))
 
-			indexKey.Set(key.Field, order)
+	if v, _ := cd.Get("batchSize"); v != nil {
+		if batchSize, err = handlerparams.GetValidatedNumberParamWithMinValue(command, "batchSize", v, 0); err != nil {
+			return nil, err
 		}
+	}
+
+	var cursorID int64
 
-		indexDoc := must.NotFail(types.NewDocument(
-			"v", int32(2), // for compatibility, the meaning of this field is not documented
-			"key", indexKey,
-			"name", index.Name,
-		))
+	firstBatch := types.MakeArray(len(docs))
 
-		// only non-default unique indexes should have unique field in the response
-		if index.Unique && index.Name != backends.DefaultIndexName {
-			indexDoc.Set("unique", index.Unique)
+	if batchSize >= int64(len(docs)) {
+		for _, doc := range docs {
+			firstBatch.Append(doc)
+		}
+	} else {
+		c := h.cursors.NewCursor(connCtx, iterator.Values(iterator.ForSlice(docs)), &cursor.NewParams{
+			DB:         dbName,
+			Collection: collection,
+			Username:   conninfo.Get(connCtx).Username(),
+			Session:    lsid,
+			Type:       cursor.Normal,
+		})
+
+		var batch []*types.Document
+		if batch, err = iterator.ConsumeValuesN(c, int(batchSize)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for _, doc := range batch {
+			firstBatch.Append(doc)
 		}
 
-		firstBatch.Append(indexDoc)
+		cursorID = c.ID
 	}
 
 	return documentOpMsg(
 		must.NotFail(types.NewDocument(
 			"cursor", must.NotFail(types.NewDocument(
-				"id", int64(0),
+				"id", cursorID,
 				"ns", fmt.Sprintf("%s.%s", dbName, collection),
 				"firstBatch", firstBatch,
 			)),
@@ -118,3 +160,25 @@ func (h *Handler) MsgListIndexes(connCtx context.Context, msg *wire.OpMsg) (*wir
 		)),
 	)
 }
+
+// indexDocument returns the `listIndexes` representation of the given index.
+func indexDocument(index *backends.IndexInfo) *types.Document {
+	indexDoc := must.NotFail(types.NewDocument(
+		"v", int32(2), // for compatibility, the meaning of this field is not documented
+		"key", indexKeyDocument(index.Key),
+		"name", index.Name,
+	))
+
+	// only non-default unique indexes should have unique field in the response
+	if index.Unique && index.Name != backends.DefaultIndexName {
+		indexDoc.Set("unique", index.Unique)
+	}
+
+	if index.Options != nil {
+		for _, opt := range index.Options.Keys() {
+			indexDoc.Set(opt, must.NotFail(index.Options.Get(opt)))
+		}
+	}
+
+	return indexDoc
+}
@@ -0,0 +1,100 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// defaultShardName is the name of the single logical shard reported by sharding commands
+// when replica set name is not configured.
+const defaultShardName = "shard0"
+
+// MsgListShards implements `listShards` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgListShards(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	name, host := h.shard()
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"shards", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument(
+					"_id", name,
+					"host", host,
+					"state", int32(1),
+				)),
+			)),
+			"ok", float64(1),
+		)),
+	)
+}
+
+// shard returns the name and the host string of the single logical shard
+// that FerretDB reports to tools written for sharded clusters.
+func (h *Handler) shard() (string, string) {
+	host := h.TCPHost
+
+	// see hello for the same limitation
+	if strings.HasPrefix(host, ":") {
+		host = "localhost" + host
+	}
+
+	if h.ReplSetName == "" {
+		return defaultShardName, host
+	}
+
+	return h.ReplSetName, h.ReplSetName + "/" + host
+}
+
+// checkAdminDatabase returns an error if the given command document
+// is not sent to the admin database.
+func checkAdminDatabase(document *types.Document) error {
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return err
+	}
+
+	if dbName != "admin" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			fmt.Sprintf("%s may only be run against the admin database.", command),
+			command,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgReplSetStepDown implements `replSetStepDown` command.
+//
+// FerretDB is always the primary member of a single-member replica set,
+// so after parameters are validated, it is a no-op.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgReplSetStepDown(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	command := document.Command()
+
+	if h.ReplSetName == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNoReplicationEnabled,
+			"not running with --replSet",
+			command,
+		)
+	}
+
+	common.Ignored(document, h.L, "force", "comment")
+
+	stepDownSecs, err := handlerparams.GetWholeNumberParam(must.NotFail(document.Get(command)))
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			"stepdown period must be a number",
+			command,
+		)
+	}
+
+	if stepDownSecs == 0 {
+		stepDownSecs = 60
+	}
+
+	catchUpSecs := int64(10)
+
+	if v, _ := document.Get("secondaryCatchUpPeriodSecs"); v != nil {
+		if catchUpSecs, err = handlerparams.GetWholeNumberParam(v); err != nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"secondaryCatchUpPeriodSecs must be a number",
+				command,
+			)
+		}
+	}
+
+	if catchUpSecs > stepDownSecs {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"stepdown period must be longer than secondaryCatchUpPeriodSecs",
+			command,
+		)
+	}
+
+	return documentOpMsg(must.NotFail(types.NewDocument("ok", float64(1))))
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestReplSetStepDown(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		replSetName string
+		pairs       []any
+		code        handlererrors.ErrorCode // 0 if no error
+	}{
+		"Default": {
+			replSetName: "rs0",
+			pairs:       []any{"replSetStepDown", int32(0)},
+		},
+		"CatchUp": {
+			replSetName: "rs0",
+			pairs:       []any{"replSetStepDown", int32(60), "secondaryCatchUpPeriodSecs", int32(30)},
+		},
+		"CatchUpTooLong": {
+			replSetName: "rs0",
+			pairs:       []any{"replSetStepDown", int32(5), "secondaryCatchUpPeriodSecs", int32(10)},
+			code:        handlererrors.ErrBadValue,
+		},
+		"NotNumber": {
+			replSetName: "rs0",
+			pairs:       []any{"replSetStepDown", "foo"},
+			code:        handlererrors.ErrTypeMismatch,
+		},
+		"NoReplSet": {
+			pairs: []any{"replSetStepDown", int32(60)},
+			code:  handlererrors.ErrNoReplicationEnabled,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			h := newTestHandler(t, &NewOpts{
+				ReplSetName: tc.replSetName,
+			})
+
+			_, err := h.MsgReplSetStepDown(testutil.Ctx(t), wire.MustOpMsg(append(tc.pairs, "$db", "admin")...))
+			if tc.code == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var cmdErr *handlererrors.CommandError
+			require.ErrorAs(t, err, &cmdErr)
+			assert.Equal(t, tc.code, cmdErr.Code())
+		})
+	}
+}
@@ -0,0 +1,557 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgRestoreBackup implements `restoreBackup` command.
+//
+// Collections are restored one by one into the original or the given database;
+// the restore fails if the collection already exists.
+// Like `importCollection`, the restore is not atomic.
+//
+// If `untilClusterTime` is set, changes captured by OpLog after the backup start
+// and up to the given cluster time are applied after the restore (point-in-time recovery).
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgRestoreBackup(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	common.Ignored(document, h.L, "comment", "writeConcern")
+
+	command := document.Command()
+
+	name, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	var to string
+
+	if v, _ := document.Get("to"); v != nil {
+		var ok bool
+		if to, ok = v.(string); !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.to' is the wrong type '%s', expected type 'string'",
+					command, handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+	}
+
+	var until types.Timestamp
+	var pitr bool
+
+	if v, _ := document.Get("untilClusterTime"); v != nil {
+		if until, pitr = v.(types.Timestamp); !pitr {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.untilClusterTime' is the wrong type '%s', expected type 'timestamp'",
+					command, handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+	}
+
+	if err = h.checkBackupStore(command); err != nil {
+		return nil, err
+	}
+
+	r, err := h.backupStore.Open(connCtx, name)
+	if err != nil {
+		return nil, objectStoreError(err, name, command)
+	}
+
+	defer r.Close() //nolint:errcheck // we are only reading it
+
+	ir := newImportReader(r)
+
+	var collections int32
+	var srcDB string
+	var changes []*types.Document
+
+	for {
+		var header *types.Document
+
+		header, err = ir.readHeader()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err == nil && collections == 0 {
+			if srcDB, _, err = backupNamespace(header); err == nil && pitr {
+				changes, err = h.backupChanges(connCtx, header, srcDB, until, command)
+			}
+		}
+
+		if err == nil {
+			err = h.restoreCollection(connCtx, ir, header, to, command)
+		}
+
+		if err != nil {
+			if errors.Is(err, errInvalidExport) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrOperationFailed,
+					fmt.Sprintf("Failed to restore %q after %d documents: %s", name, ir.imported, err),
+					command,
+				)
+			}
+
+			return nil, err
+		}
+
+		collections++
+	}
+
+	if collections == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrOperationFailed,
+			fmt.Sprintf("Failed to restore %q: %s: empty backup", name, errInvalidExport),
+			command,
+		)
+	}
+
+	res := must.NotFail(types.NewDocument(
+		"collections", collections,
+		"documents", ir.imported,
+	))
+
+	if pitr {
+		if to == "" {
+			to = srcDB
+		}
+
+		var replayed int64
+
+		if replayed, err = h.replayChanges(connCtx, changes, to); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.Set("replayed", replayed)
+	}
+
+	res.Set("ok", float64(1))
+
+	return documentOpMsg(res)
+}
+
+// backupNamespace returns database and collection names from the given stream header.
+func backupNamespace(header *types.Document) (string, string, error) {
+	ns, _ := header.Get("ns")
+	nsStr, _ := ns.(string)
+
+	dbName, cName, ok := strings.Cut(nsStr, ".")
+	if !ok || dbName == "" || cName == "" {
+		return "", "", fmt.Errorf("%w: invalid namespace %s", errInvalidExport, types.FormatAnyValue(ns))
+	}
+
+	return dbName, cName, nil
+}
+
+// backupChanges returns OpLog records of the given database
+// after the backup start (from the given stream header) and up to the given cluster time.
+//
+// It returns an error if OpLog does not exist or does not contain all changes since the backup start.
+func (h *Handler) backupChanges(
+	ctx context.Context,
+	header *types.Document,
+	dbName string,
+	until types.Timestamp,
+	command string,
+) ([]*types.Document, error) {
+	v, _ := header.Get("startClusterTime")
+
+	start, ok := v.(types.Timestamp)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrOperationFailed,
+			"Backup does not contain the start cluster time required for point-in-time recovery",
+			command,
+		)
+	}
+
+	if until < start {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf(
+				"untilClusterTime %s is earlier than the backup start cluster time %s",
+				types.FormatAnyValue(until), types.FormatAnyValue(start),
+			),
+			command,
+		)
+	}
+
+	// see oplog decorator
+	const oplogDB, oplogCollection = "local", "oplog.rs"
+
+	db, err := h.b.Database(oplogDB)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	list, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: oplogCollection})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(list.Collections) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceNotFound,
+			fmt.Sprintf("Point-in-time recovery requires OpLog collection %s.%s", oplogDB, oplogCollection),
+			command,
+		)
+	}
+
+	c, err := db.Collection(oplogCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	qr, err := c.Query(ctx, &backends.QueryParams{Sort: must.NotFail(types.NewDocument("$natural", int64(1)))})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	var res []*types.Document
+
+	for first := true; ; first = false {
+		var doc *types.Document
+
+		_, doc, err = qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		tsV, _ := doc.Get("ts")
+		ts, _ := tsV.(types.Timestamp)
+
+		if first && ts > start {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrOperationFailed,
+				"OpLog does not contain all changes since the backup start",
+				command,
+			)
+		}
+
+		if ts <= start || ts > until {
+			continue
+		}
+
+		ns, _ := doc.Get("ns")
+		nsStr, _ := ns.(string)
+
+		if d, _, _ := strings.Cut(nsStr, "."); d == dbName {
+			res = append(res, doc)
+		}
+	}
+
+	return res, nil
+}
+
+// replayChanges applies the given OpLog records to the given database
+// and returns the number of applied records.
+//
+// Records are applied idempotently, as the backup may already contain some of the changes:
+// inserts replace existing documents, updates insert missing ones.
+func (h *Handler) replayChanges(ctx context.Context, changes []*types.Document, dbName string) (int64, error) {
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	var replayed int64
+
+	for _, change := range changes {
+		ns, _ := change.Get("ns")
+		_, cName, _ := strings.Cut(ns.(string), ".")
+
+		if cName == "" || strings.HasPrefix(cName, "system.") {
+			continue
+		}
+
+		var c backends.Collection
+
+		if c, err = db.Collection(cName); err != nil {
+			return replayed, lazyerrors.Error(err)
+		}
+
+		op, _ := change.Get("op")
+		o, _ := change.Get("o")
+
+		oDoc, ok := o.(*types.Document)
+		if !ok {
+			return replayed, lazyerrors.Errorf("invalid OpLog record %s", types.FormatAnyValue(change))
+		}
+
+		switch op {
+		case "i":
+			err = upsertDocument(ctx, c, oDoc, true)
+
+		case "u":
+			v, _ := oDoc.Get("$set")
+
+			doc, ok := v.(*types.Document)
+			if !ok {
+				return replayed, lazyerrors.Errorf("invalid OpLog record %s", types.FormatAnyValue(change))
+			}
+
+			err = upsertDocument(ctx, c, doc, false)
+
+		case "d":
+			id, _ := oDoc.Get("_id")
+			_, err = c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{id}})
+
+		default:
+			continue
+		}
+
+		if err != nil {
+			return replayed, lazyerrors.Error(err)
+		}
+
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+// upsertDocument inserts the document or replaces the existing one with the same _id.
+//
+// If insertFirst is true, insert is tried first, update otherwise.
+func upsertDocument(ctx context.Context, c backends.Collection, doc *types.Document, insertFirst bool) error {
+	if insertFirst {
+		_, err := c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc}})
+		if err == nil {
+			return nil
+		}
+
+		if !backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	res, err := c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if res.Updated > 0 || insertFirst {
+		return nil
+	}
+
+	if _, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc}}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// restoreCollection creates a collection for the given stream header,
+// and restores its documents and indexes.
+//
+// If dbName is empty, the database from the header is used.
+func (h *Handler) restoreCollection(ctx context.Context, ir *importReader, header *types.Document, dbName, command string) error {
+	originalDBName, cName, err := backupNamespace(header)
+	if err != nil {
+		return err
+	}
+
+	if dbName == "" {
+		dbName = originalDBName
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid database name: %s", dbName)
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	params := &backends.CreateCollectionParams{Name: cName}
+
+	if v, _ := header.Get("options"); v != nil {
+		options, ok := v.(*types.Document)
+		if !ok {
+			return fmt.Errorf("%w: invalid options %s", errInvalidExport, types.FormatAnyValue(v))
+		}
+
+		size, _ := options.Get("size")
+		maxDocs, _ := options.Get("max")
+
+		params.CappedSize, _ = size.(int64)
+		params.CappedDocuments, _ = maxDocs.(int64)
+	}
+
+	err = db.CreateCollection(ctx, params)
+
+	switch {
+	case err == nil:
+		// nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceExists,
+			fmt.Sprintf("Collection %s.%s already exists.", dbName, cName),
+			command,
+		)
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			fmt.Sprintf("Invalid collection name: %s", cName),
+			command,
+		)
+	default:
+		return lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	ir.insert = func(docs []*types.Document) error {
+		return insertBatches(ctx, c, docs, h.BatchSize)
+	}
+
+	if err = ir.readDocuments(); err != nil {
+		if errors.Is(err, errInvalidExport) {
+			return err
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	indexes, err := backupIndexes(header)
+	if err != nil {
+		return err
+	}
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	if _, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: indexes}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// backupIndexes returns indexes from the given stream header, except the default `_id` index.
+func backupIndexes(header *types.Document) ([]backends.IndexInfo, error) {
+	v, _ := header.Get("indexes")
+	if v == nil {
+		return nil, nil
+	}
+
+	arr, ok := v.(*types.Array)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid indexes %s", errInvalidExport, types.FormatAnyValue(v))
+	}
+
+	var res []backends.IndexInfo
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		spec, ok := v.(*types.Document)
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid index %s", errInvalidExport, types.FormatAnyValue(v))
+		}
+
+		name, _ := spec.Get("name")
+		key, _ := spec.Get("key")
+		unique, _ := spec.Get("unique")
+
+		index := backends.IndexInfo{}
+		index.Name, _ = name.(string)
+		index.Unique, _ = unique.(bool)
+
+		if options, _ := spec.Get("options"); options != nil {
+			if index.Options, ok = options.(*types.Document); !ok {
+				return nil, fmt.Errorf("%w: invalid index %s", errInvalidExport, types.FormatAnyValue(v))
+			}
+		}
+
+		keyDoc, ok := key.(*types.Document)
+		if index.Name == "" || !ok || keyDoc.Len() == 0 {
+			return nil, fmt.Errorf("%w: invalid index %s", errInvalidExport, types.FormatAnyValue(v))
+		}
+
+		if index.Name == backends.DefaultIndexName {
+			continue
+		}
+
+		for _, field := range keyDoc.Keys() {
+			index.Key = append(index.Key, backends.IndexKeyPair{Field: field})
+		}
+
+		for i, order := range keyDoc.Values() {
+			index.Key[i].Descending = order == int32(-1)
+			index.Key[i].Hashed = order == "hashed"
+		}
+
+		res = append(res, index)
+	}
+
+	return res, nil
+}
@@ -60,7 +60,7 @@ func (h *Handler) saslContinue(connCtx context.Context, doc *types.Document) (*t
 		return nil, err
 	}
 
-	_, _, conv, _ := conninfo.Get(connCtx).Auth()
+	_, _, conv, dbName := conninfo.Get(connCtx).Auth()
 
 	if conv == nil {
 		h.L.WarnContext(connCtx, "saslContinue: no conversation to continue")
@@ -106,6 +106,8 @@ func (h *Handler) saslContinue(connCtx context.Context, doc *types.Document) (*t
 
 		conninfo.Get(connCtx).SetAuth("", "", nil, "")
 
+		h.authFailed(connCtx, dbName, conv.Username(), false)
+
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrAuthenticationFailed,
 			"Authentication failed.",
@@ -115,6 +117,10 @@ func (h *Handler) saslContinue(connCtx context.Context, doc *types.Document) (*t
 
 	h.L.DebugContext(connCtx, "saslContinue: step succeed", attrs...)
 
+	if conv.Valid() {
+		h.authSucceeded(connCtx, dbName, conv.Username())
+	}
+
 	return must.NotFail(types.NewDocument(
 		"conversationId", int32(1),
 		"done", valid, // for compatibility, assign the validity of the conversation before [Step] was called
@@ -90,7 +90,7 @@ func (h *Handler) saslStart(ctx context.Context, dbName string, document *types.
 
 	switch mechanism {
 	case "PLAIN":
-		if err = saslStartPlain(ctx, dbName, document); err != nil {
+		if err = h.saslStartPlain(ctx, dbName, document); err != nil {
 			return nil, err
 		}
 
@@ -109,7 +109,9 @@ func (h *Handler) saslStart(ctx context.Context, dbName string, document *types.
 }
 
 // saslStartPlain extracts username and password from PLAIN `saslStart` payload.
-func saslStartPlain(ctx context.Context, dbName string, doc *types.Document) error {
+//
+// Credentials are checked by the backend later; see [Handler.backendAuthInterceptor].
+func (h *Handler) saslStartPlain(ctx context.Context, dbName string, doc *types.Document) error {
 	var payload []byte
 
 	// some drivers send payload as a string
@@ -152,6 +154,11 @@ func saslStartPlain(ctx context.Context, dbName string, doc *types.Document) err
 	// Ignore authzid for now.
 	_ = authzid
 
+	if err = h.checkAuthLockout(ctx, dbName, string(authcid)); err != nil {
+		h.authFailed(ctx, dbName, string(authcid), true)
+		return err
+	}
+
 	conninfo.Get(ctx).SetAuth(string(authcid), string(passwd), nil, dbName)
 
 	return nil
@@ -262,7 +269,14 @@ func (h *Handler) saslStartSCRAM(ctx context.Context, dbName, mechanism string,
 		panic("unsupported SCRAM mechanism")
 	}
 
+	var locked bool
+
 	scramServer, err := f.NewServer(func(username string) (scram.StoredCredentials, error) {
+		if lockoutErr := h.checkAuthLockout(ctx, dbName, username); lockoutErr != nil {
+			locked = true
+			return scram.StoredCredentials{}, lockoutErr
+		}
+
 		cred, lookupErr := h.scramCredentialLookup(ctx, dbName, username, mechanism)
 		if lookupErr != nil {
 			return scram.StoredCredentials{}, lookupErr
@@ -291,6 +305,10 @@ func (h *Handler) saslStartSCRAM(ctx context.Context, dbName, mechanism string,
 
 		h.L.WarnContext(ctx, "saslStartSCRAM: step failed", attrs...)
 
+		if conv.Username() != "" {
+			h.authFailed(ctx, dbName, conv.Username(), locked)
+		}
+
 		return "", err
 	}
 
@@ -27,7 +27,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
 )
 
-// TestSaslStartPlain tests saslStartPlain function.
+// TestSaslStartPlain tests saslStartPlain method.
 // Integration tests are not possible because the driver
 // used in integration tests doesn't support all possible scenarios.
 // To ensure compatibility, the functionality must be tested by external drivers
@@ -35,6 +35,8 @@ import (
 func TestSaslStartPlain(t *testing.T) {
 	validPayload := []byte("authzid\x00admin\x00pass")
 
+	h := newTestHandler(t, new(NewOpts))
+
 	for name, tc := range map[string]struct { //nolint:vet // for readability
 		doc *types.Document
 
@@ -91,7 +93,7 @@ func TestSaslStartPlain(t *testing.T) {
 	} {
 		t.Run(name, func(t *testing.T) {
 			ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
-			err := saslStartPlain(ctx, "db", tc.doc)
+			err := h.saslStartPlain(ctx, "db", tc.doc)
 			assert.Equal(t, tc.err, err)
 
 			username, password, _, db := conninfo.Get(ctx).Auth()
@@ -16,8 +16,10 @@ package handler
 
 import (
 	"context"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/FerretDB/wire"
@@ -64,6 +66,19 @@ func (h *Handler) MsgServerStatus(connCtx context.Context, msg *wire.OpMsg) (*wi
 		}
 	}
 
+	drivers := types.MakeArray(0)
+
+	driverMetrics := h.ConnMetrics.GetDrivers()
+	for _, name := range slices.Sorted(maps.Keys(driverMetrics)) {
+		for _, v := range slices.Sorted(maps.Keys(driverMetrics[name])) {
+			drivers.Append(must.NotFail(types.NewDocument(
+				"name", name,
+				"version", v,
+				"count", int64(driverMetrics[name][v]),
+			)))
+		}
+	}
+
 	res := must.NotFail(types.NewDocument(
 		"host", host,
 		"version", version.Get().MongoDBVersion,
@@ -82,6 +97,7 @@ func (h *Handler) MsgServerStatus(connCtx context.Context, msg *wire.OpMsg) (*wi
 
 		// our extensions
 		"ferretdbVersion", version.Get().Version,
+		"drivers", drivers,
 
 		"ok", float64(1),
 	))
@@ -0,0 +1,170 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/password"
+)
+
+// setParameterGenericFields are fields of `setParameter` command that are not parameters.
+var setParameterGenericFields = []string{"comment", "lsid", "apiVersion", "apiStrict", "apiDeprecationErrors"}
+
+// MsgSetParameter implements `setParameter` command.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgSetParameter(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	command := document.Command()
+
+	common.Ignored(document, h.L, "comment")
+
+	var was any
+
+	values := document.Values()
+
+	for i, name := range document.Keys() {
+		if i == 0 || strings.HasPrefix(name, "$") || slices.Contains(setParameterGenericFields, name) {
+			continue
+		}
+
+		v := values[i]
+
+		var old any
+
+		switch name {
+		case "localLogicalSessionTimeoutMinutes":
+			var minutes int64
+			if minutes, err = handlerparams.GetValidatedNumberParamWithMinValue(command, name, v, 1); err != nil {
+				return nil, err
+			}
+
+			old = h.logicalSessionTimeoutMinutes()
+			h.sessions.SetTimeout(time.Duration(minutes) * time.Minute)
+
+		case "logicalSessionRefreshMillis":
+			var millis int64
+			if millis, err = handlerparams.GetValidatedNumberParamWithMinValue(command, name, v, 1); err != nil {
+				return nil, err
+			}
+
+			old = h.logicalSessionRefreshMillis()
+			h.sessionCleanupInterval.Store(int64(time.Duration(millis) * time.Millisecond))
+
+		case "passwordMinLength":
+			var length int64
+			if length, err = handlerparams.GetValidatedNumberParamWithMinValue(command, name, v, 0); err != nil {
+				return nil, err
+			}
+
+			if length > math.MaxInt32 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("%s must be less than or equal to %d", name, math.MaxInt32),
+					command,
+				)
+			}
+
+			old = h.passwordMinLength.Swap(int32(length))
+
+		case "passwordRequireComplexity":
+			var require bool
+			if require, err = handlerparams.GetBoolOptionalParam(name, v); err != nil {
+				return nil, err
+			}
+
+			old = h.passwordRequireComplexity.Swap(require)
+
+		case "readOnly":
+			var readOnly bool
+			if readOnly, err = handlerparams.GetBoolOptionalParam(name, v); err != nil {
+				return nil, err
+			}
+
+			old = h.readOnly.Swap(readOnly)
+
+		case "scramIterationCount", "scramSHA256IterationCount":
+			var count int64
+			if count, err = handlerparams.GetValidatedNumberParamWithMinValue(
+				command, name, v, password.MinSCRAMIterationCount,
+			); err != nil {
+				return nil, err
+			}
+
+			if count > math.MaxInt32 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("%s must be less than or equal to %d", name, math.MaxInt32),
+					command,
+				)
+			}
+
+			p := &h.scramIterationCount
+			if name == "scramSHA256IterationCount" {
+				p = &h.scramSHA256IterationCount
+			}
+
+			old = p.Swap(int32(count))
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidOptions,
+				fmt.Sprintf("attempted to set unrecognized parameter [%s], use help:true to see options ", name),
+				command,
+			)
+		}
+
+		if was == nil {
+			was = old
+		}
+	}
+
+	if was == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"no option found to set, use help:true to see options ",
+			command,
+		)
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"was", was,
+			"ok", float64(1),
+		)),
+	)
+}
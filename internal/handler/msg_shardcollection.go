@@ -0,0 +1,193 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// MsgShardCollection implements `shardCollection` command.
+//
+// FerretDB always acts as a single logical shard,
+// so the command validates the shard key and creates the collection if it does not exist.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgShardCollection(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	ignoredFields := []string{
+		"unique",
+		"numInitialChunks",
+		"presplitHashedZones",
+		"collation",
+		"timeseries",
+		"writeConcern",
+		"comment",
+	}
+	common.Ignored(document, h.L, ignoredFields...)
+
+	command := document.Command()
+
+	ns, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	dbName, cName, err := handlerparams.SplitNamespace(ns, command)
+	if err != nil {
+		return nil, err
+	}
+
+	keyV, _ := document.Get("key")
+	if keyV == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field 'shardCollection.key' is missing but a required field",
+			command,
+		)
+	}
+
+	key, ok := keyV.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field 'shardCollection.key' is the wrong type '%s', expected type 'object'",
+				handlerparams.AliasFromType(keyV),
+			),
+			command,
+		)
+	}
+
+	if err = validateShardKey(key, command); err != nil {
+		return nil, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s'", ns)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	err = db.CreateCollection(connCtx, &backends.CreateCollectionParams{Name: cName})
+
+	switch {
+	case err == nil:
+	// do nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
+	// sharding an existing collection is fine
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
+		msg := fmt.Sprintf("Invalid namespace specified '%s'", ns)
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	return documentOpMsg(
+		must.NotFail(types.NewDocument(
+			"collectionsharded", ns,
+			"ok", float64(1),
+		)),
+	)
+}
+
+// validateShardKey returns an error if the given shard key specification is invalid.
+//
+// Each field of the key must be 1 for ranged sharding or "hashed" for hashed sharding;
+// at most one field could be hashed.
+// The command is used as an argument of returned errors.
+func validateShardKey(key *types.Document, command string) error {
+	if key.Len() == 0 {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Shard key cannot be empty",
+			command,
+		)
+	}
+
+	iter := key.Iterator()
+	defer iter.Close()
+
+	var hashed bool
+
+	for {
+		field, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if field == "" {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"Shard key fields cannot be empty",
+				command,
+			)
+		}
+
+		if s, ok := v.(string); ok && s == "hashed" {
+			if hashed {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"Shard key can only contain one hashed field",
+					command,
+				)
+			}
+
+			hashed = true
+
+			continue
+		}
+
+		if n, err := handlerparams.GetWholeNumberParam(v); err == nil && n == 1 {
+			continue
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Shard key %s must have values of 1 or \"hashed\"", types.FormatAnyValue(key)),
+			command,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// defaultShutdownTimeout is the default time to wait for operations in progress to finish.
+const defaultShutdownTimeout = 15 * time.Second
+
+// MsgShutdown implements `shutdown` command.
+//
+// Unless `force` is set, it waits up to `timeoutSecs` for operations in progress to finish
+// before killing background operations and stopping FerretDB.
+// Unlike MongoDB, it responds to the client before stopping.
+//
+// The passed context is canceled when the client connection is closed.
+func (h *Handler) MsgShutdown(connCtx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if err = checkAdminDatabase(document); err != nil {
+		return nil, err
+	}
+
+	command := document.Command()
+
+	common.Ignored(document, h.L, "comment")
+
+	force, err := common.GetOptionalParam(document, "force", false)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := defaultShutdownTimeout
+
+	if v, _ := document.Get("timeoutSecs"); v != nil {
+		secs, err := handlerparams.GetWholeNumberParam(v)
+		if err != nil || secs < 0 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("BSON field 'shutdown.timeoutSecs' should be a non-negative whole number, got %v", v),
+				command,
+			)
+		}
+
+		timeout = time.Duration(secs) * time.Second
+	}
+
+	if h.Shutdown == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"shutdown command is not supported in this configuration",
+			command,
+		)
+	}
+
+	if h.shuttingDown.Swap(true) {
+		return documentOpMsg(must.NotFail(types.NewDocument("ok", float64(1))))
+	}
+
+	h.L.InfoContext(connCtx, "Shutdown requested", slog.Bool("force", force), slog.Duration("timeout", timeout))
+
+	go func() {
+		if !force {
+			h.waitOperations(timeout)
+		}
+
+		h.operations.killAll()
+		h.Shutdown()
+	}()
+
+	return documentOpMsg(must.NotFail(types.NewDocument("ok", float64(1))))
+}
+
+// waitOperations waits until there are no operations in progress or the timeout passes.
+func (h *Handler) waitOperations(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		if len(h.operations.list()) == 0 {
+			return
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	h.L.Warn(fmt.Sprintf("Operations are still in progress after %s, stopping anyway", timeout))
+}
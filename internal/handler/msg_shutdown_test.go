@@ -0,0 +1,68 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+
+	h := newTestHandler(t, &NewOpts{
+		Shutdown: func() { close(done) },
+	})
+
+	ctx := testutil.Ctx(t)
+
+	t.Run("NotAdmin", func(t *testing.T) {
+		_, err := h.MsgShutdown(ctx, wire.MustOpMsg("shutdown", int32(1), "$db", "test"))
+
+		var cmdErr *handlererrors.CommandError
+		require.ErrorAs(t, err, &cmdErr)
+		assert.Equal(t, handlererrors.ErrUnauthorized, cmdErr.Code())
+	})
+
+	t.Run("InvalidTimeout", func(t *testing.T) {
+		_, err := h.MsgShutdown(ctx, wire.MustOpMsg("shutdown", int32(1), "timeoutSecs", int32(-1), "$db", "admin"))
+
+		var cmdErr *handlererrors.CommandError
+		require.ErrorAs(t, err, &cmdErr)
+		assert.Equal(t, handlererrors.ErrBadValue, cmdErr.Code())
+	})
+
+	_, err := h.MsgShutdown(ctx, wire.MustOpMsg("shutdown", int32(1), "timeoutSecs", int32(1), "$db", "admin"))
+	require.NoError(t, err)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown was not called")
+	}
+
+	// repeated calls are no-ops
+	_, err = h.MsgShutdown(ctx, wire.MustOpMsg("shutdown", int32(1), "$db", "admin"))
+	require.NoError(t, err)
+}
@@ -16,11 +16,13 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/FerretDB/wire"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -43,12 +45,9 @@ func (h *Handler) MsgUpdate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 		return nil, lazyerrors.Error(err)
 	}
 
-	// TODO https://github.com/FerretDB/FerretDB/issues/2612
-	_ = params.Ordered
-
-	matched, modified, upserted, err := h.updateDocument(connCtx, params)
+	matched, modified, upserted, writeErrors, err := h.updateDocument(connCtx, params)
 	if err != nil {
-		return nil, handleUpdateError(params.DB, params.Collection, "update", err)
+		return nil, err
 	}
 
 	res := must.NotFail(types.NewDocument(
@@ -60,6 +59,12 @@ func (h *Handler) MsgUpdate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 	}
 
 	res.Set("nModified", modified)
+
+	if writeErrors.Len() > 0 {
+		doc := must.NotFail(bson.ToDocument(writeErrors.Document()))
+		res.Set("writeErrors", must.NotFail(doc.Get("writeErrors")))
+	}
+
 	res.Set("ok", float64(1))
 
 	return documentOpMsg(
@@ -68,18 +73,33 @@ func (h *Handler) MsgUpdate(connCtx context.Context, msg *wire.OpMsg) (*wire.OpM
 }
 
 // updateDocument iterate through all documents in collection and update them.
-func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParams) (int32, int32, *types.Array, error) {
+//
+// Errors of individual update statements are returned as write errors with statement indexes.
+// If updates are ordered, the first such error stops execution of the remaining statements.
+func (h *Handler) updateDocument(
+	ctx context.Context,
+	params *common.UpdateParams,
+) (int32, int32, *types.Array, *handlererrors.WriteErrors, error) {
 	var matched, modified int32
 	var upserted types.Array
+	var writeErrors handlererrors.WriteErrors
 
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
 			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", params.DB, params.Collection)
-			return 0, 0, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "update")
+			return 0, 0, nil, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "update")
 		}
 
-		return 0, 0, nil, lazyerrors.Error(err)
+		return 0, 0, nil, nil, lazyerrors.Error(err)
+	}
+
+	if err = h.checkDatabaseCase(ctx, params.DB, "update"); err != nil {
+		return 0, 0, nil, nil, err
+	}
+
+	if err = h.checkCollectionsQuota(ctx, db, params.DB, params.Collection, "update"); err != nil {
+		return 0, 0, nil, nil, err
 	}
 
 	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: params.Collection})
@@ -91,46 +111,52 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		// nothing
 	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
 		msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
-		return 0, 0, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
+		return 0, 0, nil, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
 	default:
-		return 0, 0, nil, lazyerrors.Error(err)
+		return 0, 0, nil, nil, lazyerrors.Error(err)
 	}
 
-	for _, u := range params.Updates {
-		c, err := db.Collection(params.Collection)
-		if err != nil {
-			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
-				msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
-				return 0, 0, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
-			}
-
-			return 0, 0, nil, lazyerrors.Error(err)
-		}
+	versioned, err := collectionVersioned(ctx, db, params.Collection)
+	if err != nil {
+		return 0, 0, nil, nil, lazyerrors.Error(err)
+	}
 
-		var qp backends.QueryParams
-		if !h.DisablePushdown {
-			qp.Filter = u.Filter
+	c, err := db.Collection(params.Collection)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
+			return 0, 0, nil, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
 		}
 
-		res, err := c.Query(ctx, &qp)
-		if err != nil {
-			return 0, 0, nil, lazyerrors.Error(err)
-		}
+		return 0, 0, nil, nil, lazyerrors.Error(err)
+	}
 
-		closer := iterator.NewMultiCloser()
-		defer closer.Close()
+	for i, u := range params.Updates {
+		u.Versioned = versioned
 
-		closer.Add(res.Iter)
+		var result *common.UpdateResult
 
-		iter := common.FilterIterator(res.Iter, closer, u.Filter)
+		result, err = h.execUpdate(ctx, c, params, &u)
+		if err != nil {
+			err = handleUpdateError(params.DB, params.Collection, "update", err)
+
+			var we *handlererrors.WriteErrors
+			var ce *handlererrors.CommandError
+
+			switch {
+			case errors.As(err, &we):
+				writeErrors.Merge(we, int32(i))
+			case errors.As(err, &ce):
+				writeErrors.Append(ce, int32(i))
+			default:
+				return 0, 0, nil, nil, lazyerrors.Error(err)
+			}
 
-		if !u.Multi {
-			iter = common.LimitIterator(iter, closer, 1)
-		}
+			if params.Ordered {
+				break
+			}
 
-		result, err := common.UpdateDocument(ctx, c, "update", iter, &u)
-		if err != nil {
-			return 0, 0, nil, lazyerrors.Error(err)
+			continue
 		}
 
 		matched += result.Matched.Count
@@ -139,7 +165,7 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		if result.Upserted.Doc != nil {
 			doc := result.Upserted.Doc
 			upserted.Append(must.NotFail(types.NewDocument(
-				"index", int32(upserted.Len()),
+				"index", int32(i),
 				"_id", must.NotFail(doc.Get("_id")),
 			)))
 
@@ -148,5 +174,50 @@ func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParam
 		}
 	}
 
-	return matched, modified, &upserted, nil
+	return matched, modified, &upserted, &writeErrors, nil
+}
+
+// execUpdate performs a single update statement.
+func (h *Handler) execUpdate(
+	ctx context.Context,
+	c backends.Collection,
+	params *common.UpdateParams,
+	u *common.Update,
+) (*common.UpdateResult, error) {
+	err := h.checkUpdateQuota(ctx, c, u.Upsert, params.DB, params.Collection, "update")
+	if err != nil {
+		return nil, err
+	}
+
+	var qp backends.QueryParams
+	if !h.DisablePushdown {
+		qp.Filter = u.Filter
+	}
+
+	if qp.Comment, err = queryComment(ctx, params.Comment, u.Filter); err != nil {
+		return nil, err
+	}
+
+	res, err := c.Query(ctx, &qp)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	closer.Add(res.Iter)
+
+	iter := common.FilterIterator(res.Iter, closer, u.Filter)
+
+	if !u.Multi {
+		iter = common.LimitIterator(iter, closer, 1)
+	}
+
+	result, err := common.UpdateDocument(ctx, c, "update", iter, u)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return result, nil
 }
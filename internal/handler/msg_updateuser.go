@@ -139,7 +139,11 @@ func (h *Handler) MsgUpdateUser(connCtx context.Context, msg *wire.OpMsg) (*wire
 			)
 		}
 
-		credentials, err = users.MakeCredentials(username, password.WrapPassword(userPassword), mechanisms)
+		if err = h.passwordPolicy().Check(password.WrapPassword(userPassword)); err != nil {
+			return nil, handlererrors.NewCommandErrorMsg(handlererrors.ErrBadValue, err.Error())
+		}
+
+		credentials, err = users.MakeCredentials(username, password.WrapPassword(userPassword), mechanisms, h.iterationCounts())
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,58 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// checkDatabaseCase returns an error if the given database does not exist,
+// but there is another database with the name that differs only in case.
+//
+// Like MongoDB, it prevents such databases from being created.
+// Other namespace checks are done by the backend; see backends.Backend.Database.
+func (h *Handler) checkDatabaseCase(ctx context.Context, dbName, command string) error {
+	res, err := h.b.ListDatabases(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	var existing string
+
+	for _, db := range res.Databases {
+		if db.Name == dbName {
+			return nil
+		}
+
+		if strings.EqualFold(db.Name, dbName) {
+			existing = db.Name
+		}
+	}
+
+	if existing == "" {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrDatabaseDifferCase,
+		fmt.Sprintf("db already exists with different case already have: [%s] trying to create [%s]", existing, dbName),
+		command,
+	)
+}
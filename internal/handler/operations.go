@@ -0,0 +1,437 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// slowQueryExplainTimeout limits the duration of the backend's EXPLAIN for slow queries.
+const slowQueryExplainTimeout = 5 * time.Second
+
+// errOperationKilled is used as a cancelation cause for operations killed by `killOp` command.
+var errOperationKilled = errors.New("operation was interrupted")
+
+// operation represents a command in progress.
+type operation struct {
+	start    time.Time
+	connInfo *conninfo.ConnInfo
+	command  string
+	desc     string // set for background operations
+	raw      wirebson.RawDocument
+	id       int32
+
+	// nil if the operation can't be killed
+	cancel context.CancelCauseFunc
+
+	progressM sync.Mutex
+	msg       string
+	done      int64
+	total     int64
+}
+
+// operations tracks commands in progress for `currentOp` command and slow queries logging.
+type operations struct {
+	rw     sync.RWMutex
+	ops    map[int32]*operation
+	lastID int32
+}
+
+// newOperations creates a new operations tracker.
+func newOperations() *operations {
+	return &operations{
+		ops: map[int32]*operation{},
+	}
+}
+
+// start registers a new operation for the given command.
+func (o *operations) start(ctx context.Context, command string, msg *wire.OpMsg) *operation {
+	op := &operation{
+		start:    time.Now(),
+		connInfo: conninfo.Get(ctx),
+		command:  command,
+		raw:      msg.RawSection0(),
+	}
+
+	o.add(op)
+
+	return op
+}
+
+// startBackground registers a new killable background operation for the given command.
+//
+// The returned context is not canceled when the client connection is closed,
+// only when the operation is killed.
+// The caller should cancel it when the operation is finished.
+func (o *operations) startBackground(ctx context.Context, command, desc string, msg *wire.OpMsg) (context.Context, *operation) { //nolint:lll // for readability
+	opCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+
+	op := &operation{
+		start:    time.Now(),
+		connInfo: conninfo.Get(ctx),
+		command:  command,
+		desc:     desc,
+		raw:      msg.RawSection0(),
+		cancel:   cancel,
+	}
+
+	o.add(op)
+
+	return opCtx, op
+}
+
+// add assigns an ID to the given operation and registers it.
+func (o *operations) add(op *operation) {
+	o.rw.Lock()
+	defer o.rw.Unlock()
+
+	o.lastID++
+	op.id = o.lastID
+	o.ops[op.id] = op
+}
+
+// kill kills the operation with the given ID, if it exists and could be killed.
+func (o *operations) kill(id int32) {
+	o.rw.RLock()
+	defer o.rw.RUnlock()
+
+	if op := o.ops[id]; op != nil && op.cancel != nil {
+		op.cancel(errOperationKilled)
+	}
+}
+
+// killAll kills all operations that could be killed.
+func (o *operations) killAll() {
+	o.rw.RLock()
+	defer o.rw.RUnlock()
+
+	for _, op := range o.ops {
+		if op.cancel != nil {
+			op.cancel(errOperationKilled)
+		}
+	}
+}
+
+// finish unregisters the given operation.
+func (o *operations) finish(op *operation) {
+	o.rw.Lock()
+	defer o.rw.Unlock()
+
+	delete(o.ops, op.id)
+}
+
+// list returns all operations in progress ordered by ID.
+func (o *operations) list() []*operation {
+	o.rw.RLock()
+	defer o.rw.RUnlock()
+
+	ids := slices.Sorted(maps.Keys(o.ops))
+
+	res := make([]*operation, len(ids))
+	for i, id := range ids {
+		res[i] = o.ops[id]
+	}
+
+	return res
+}
+
+// setProgress sets the progress message and counters of the operation.
+func (op *operation) setProgress(msg string, done, total int64) {
+	op.progressM.Lock()
+	defer op.progressM.Unlock()
+
+	op.msg = fmt.Sprintf("%s %d/%d", msg, done, total)
+	op.done = done
+	op.total = total
+}
+
+// document returns the command document, or nil if it can't be decoded.
+func (op *operation) document() *types.Document {
+	doc, err := bson.ToDocument(op.raw)
+	if err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+// namespace returns the namespace of the given command document.
+func (op *operation) namespace(doc *types.Document) string {
+	if doc == nil {
+		return ""
+	}
+
+	db, _ := doc.Get("$db")
+	ns, _ := db.(string)
+
+	if c, _ := doc.Get(op.command); c != nil {
+		if c, ok := c.(string); ok && c != "" {
+			ns += "." + c
+		}
+	}
+
+	return ns
+}
+
+// comment returns the comment of the given command document, or nil.
+func (op *operation) comment(doc *types.Document) any {
+	if doc == nil {
+		return nil
+	}
+
+	v, _ := doc.Get("comment")
+
+	return v
+}
+
+// logSlowOperation logs the given finished operation if it took longer than the threshold.
+func (h *Handler) logSlowOperation(ctx context.Context, op *operation) {
+	if h.SlowQueryThreshold <= 0 {
+		return
+	}
+
+	d := time.Since(op.start)
+	if d < h.SlowQueryThreshold {
+		return
+	}
+
+	doc := op.document()
+
+	attrs := []slog.Attr{
+		slog.Int("opid", int(op.id)),
+		slog.String("command", op.command),
+		slog.String("ns", op.namespace(doc)),
+		slog.Duration("duration", d),
+	}
+
+	if comment := op.comment(doc); comment != nil {
+		attrs = append(attrs, slog.Any("comment", comment))
+	}
+
+	appName, driverName, driverVersion := op.connInfo.ClientMetadata()
+
+	if appName != "" {
+		attrs = append(attrs, slog.String("appName", appName))
+	}
+
+	if driverName != "" {
+		attrs = append(attrs, slog.String("driver", driverName+" "+driverVersion))
+	}
+
+	if op.connInfo.Peer.IsValid() {
+		attrs = append(attrs, slog.String("peer", op.connInfo.Peer.String()))
+	}
+
+	if h.SlowQueryExplain {
+		attrs = append(attrs, h.explainSlowOperation(ctx, op, doc)...)
+	}
+
+	h.L.LogAttrs(ctx, slog.LevelInfo, "Slow query", attrs...)
+}
+
+// explainSlowOperation returns log attributes with the backend's execution plan of the given slow operation.
+//
+// Only `find` and `aggregate` commands are explained; nil is returned for other operations.
+// The plan is fetched without executing the query again.
+func (h *Handler) explainSlowOperation(ctx context.Context, op *operation, doc *types.Document) []slog.Attr {
+	if doc == nil || (op.command != "find" && op.command != "aggregate") {
+		return nil
+	}
+
+	db, _ := doc.Get("$db")
+
+	cmd := doc.DeepCopy()
+	cmd.Remove("$db")
+
+	params, err := common.GetExplainParams(must.NotFail(types.NewDocument("explain", cmd, "$db", db)), h.L)
+	if err != nil {
+		return []slog.Attr{logging.Error(err)}
+	}
+
+	// the client might be already disconnected
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), slowQueryExplainTimeout)
+	defer cancel()
+
+	res, err := h.explainSlowQuery(ctx, params)
+	if err != nil {
+		return []slog.Attr{logging.Error(err)}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("plan", types.FormatAnyValue(res.QueryPlanner)),
+	}
+
+	if res.Query != "" {
+		attrs = append(attrs, slog.String("sql", res.Query))
+	}
+
+	return attrs
+}
+
+// explainSlowQuery returns the backend's execution plan for the given read command parameters.
+func (h *Handler) explainSlowQuery(ctx context.Context, params *common.ExplainParams) (*backends.ExplainResult, error) {
+	db, err := h.b.Database(params.DB)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	coll, err := db.Collection(params.Collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return h.explainQuery(ctx, db, coll, params)
+}
+
+// queryComment returns a comment for the backend query.
+//
+// The command's comment is replaced by the filter's `$comment`, if present,
+// and prefixed by the client's application name, if known.
+func queryComment(ctx context.Context, comment string, filter *types.Document) (string, error) {
+	if filter != nil {
+		var err error
+		if comment, err = common.GetOptionalParam(filter, "$comment", comment); err != nil {
+			return "", err
+		}
+	}
+
+	appName, _, _ := conninfo.Get(ctx).ClientMetadata()
+
+	switch {
+	case appName == "":
+		return comment, nil
+	case comment == "":
+		return appName, nil
+	default:
+		return appName + ": " + comment, nil
+	}
+}
+
+// currentOpDocument returns a `currentOp` command's representation of the operation.
+func (op *operation) currentOpDocument(now time.Time) *types.Document {
+	doc := op.document()
+	running := now.Sub(op.start)
+
+	res := must.NotFail(types.NewDocument(
+		"type", "op",
+		"opid", op.id,
+		"active", true,
+		"currentOpTime", now.Format(time.RFC3339Nano),
+		"secs_running", int64(running/time.Second),
+		"microsecs_running", running.Microseconds(),
+		"op", currentOpType(op.command),
+		"ns", op.namespace(doc),
+	))
+
+	if op.desc != "" {
+		res.Set("desc", op.desc)
+	}
+
+	if doc != nil {
+		res.Set("command", doc)
+	}
+
+	op.progressM.Lock()
+
+	if op.msg != "" {
+		res.Set("msg", op.msg)
+		res.Set("progress", must.NotFail(types.NewDocument("done", op.done, "total", op.total)))
+	}
+
+	op.progressM.Unlock()
+
+	if op.connInfo.Peer.IsValid() {
+		res.Set("client", op.connInfo.Peer.String())
+	}
+
+	appName, driverName, driverVersion := op.connInfo.ClientMetadata()
+
+	if appName != "" {
+		res.Set("appName", appName)
+	}
+
+	if appName != "" || driverName != "" {
+		md := must.NotFail(types.NewDocument())
+
+		if appName != "" {
+			md.Set("application", must.NotFail(types.NewDocument("name", appName)))
+		}
+
+		if driverName != "" {
+			md.Set("driver", must.NotFail(types.NewDocument("name", driverName, "version", driverVersion)))
+		}
+
+		res.Set("clientMetadata", md)
+	}
+
+	return res
+}
+
+// currentOpDocuments returns `$currentOp` aggregation stage's representations of operations in progress.
+//
+// If allUsers is false, only operations of the current user are returned.
+func (h *Handler) currentOpDocuments(ctx context.Context, allUsers bool) []*types.Document {
+	username := conninfo.Get(ctx).Username()
+	now := time.Now()
+
+	var res []*types.Document
+
+	for _, op := range h.operations.list() {
+		if !allUsers && op.connInfo.Username() != username {
+			continue
+		}
+
+		res = append(res, op.currentOpDocument(now))
+	}
+
+	return res
+}
+
+// currentOpType returns the `op` field value of `currentOp` command for the given command name.
+func currentOpType(command string) string {
+	switch command {
+	case "find":
+		return "query"
+	case "insert":
+		return "insert"
+	case "update":
+		return "update"
+	case "delete":
+		return "remove"
+	case "getMore":
+		return "getmore"
+	default:
+		return "command"
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestExplainSlowOperation(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler(t, new(NewOpts))
+
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+	_, err := h.Commands()["insert"].Handler(ctx, wire.MustOpMsg(
+		"insert", "test",
+		"documents", wirebson.MustArray(wirebson.MustDocument("_id", int32(1))),
+		"$db", "test",
+	))
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		command string
+		msg     *wire.OpMsg
+		plan    string
+	}{
+		"FindID": {
+			command: "find",
+			msg: wire.MustOpMsg(
+				"find", "test",
+				"filter", wirebson.MustDocument("_id", "1"),
+				"$db", "test",
+			),
+			plan: `{ Plan: "IDHACK" }`,
+		},
+		"Aggregate": {
+			command: "aggregate",
+			msg: wire.MustOpMsg(
+				"aggregate", "test",
+				"pipeline", wirebson.MustArray(wirebson.MustDocument("$match", wirebson.MustDocument("v", int32(1)))),
+				"cursor", wirebson.MustDocument(),
+				"$db", "test",
+			),
+			plan: `{ Plan: "COLLSCAN" }`,
+		},
+		"Insert": {
+			command: "insert",
+			msg: wire.MustOpMsg(
+				"insert", "test",
+				"documents", wirebson.MustArray(wirebson.MustDocument("_id", int32(2))),
+				"$db", "test",
+			),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			op := h.operations.start(ctx, tc.command, tc.msg)
+			defer h.operations.finish(op)
+
+			attrs := h.explainSlowOperation(ctx, op, op.document())
+
+			if tc.plan == "" {
+				assert.Nil(t, attrs)
+				return
+			}
+
+			require.Len(t, attrs, 1)
+			assert.Equal(t, "plan", attrs[0].Key)
+			assert.Equal(t, tc.plan, attrs[0].Value.String())
+		})
+	}
+}
@@ -81,22 +81,38 @@ func (r *Router) Close() {
 }
 
 // Route routes the message by sending it to another wire protocol compatible service.
+//
+// It panics on error; see [Router.Send] for a variant that returns it.
 func (r *Router) Route(ctx context.Context, header *wire.MsgHeader, body wire.MsgBody) (*wire.MsgHeader, wire.MsgBody) {
+	resHeader, resBody, err := r.Send(ctx, header, body)
+	if err != nil {
+		panic(err)
+	}
+
+	return resHeader, resBody
+}
+
+// Send sends the message to another wire protocol compatible service and returns its response.
+//
+// The connection should not be used after an error.
+func (r *Router) Send(ctx context.Context, header *wire.MsgHeader, body wire.MsgBody) (*wire.MsgHeader, wire.MsgBody, error) { //nolint:lll // for readability
 	deadline, _ := ctx.Deadline()
-	r.conn.SetDeadline(deadline)
+	if err := r.conn.SetDeadline(deadline); err != nil {
+		return nil, nil, lazyerrors.Error(err)
+	}
 
 	if err := wire.WriteMessage(r.bufw, header, body); err != nil {
-		panic(err)
+		return nil, nil, lazyerrors.Error(err)
 	}
 
 	if err := r.bufw.Flush(); err != nil {
-		panic(err)
+		return nil, nil, lazyerrors.Error(err)
 	}
 
 	resHeader, resBody, err := wire.ReadMessage(r.bufr)
 	if err != nil {
-		panic(err)
+		return nil, nil, lazyerrors.Error(err)
 	}
 
-	return resHeader, resBody
+	return resHeader, resBody, nil
 }
@@ -0,0 +1,158 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// checkCollectionsQuota returns an error if creating the given collection
+// would exceed the maximum number of collections in the database.
+//
+// It does nothing if the collection already exists or the limit is not set.
+func (h *Handler) checkCollectionsQuota(ctx context.Context, db backends.Database, dbName, cName, command string) error {
+	if h.QuotaMaxCollections <= 0 {
+		return nil
+	}
+
+	res, err := db.ListCollections(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, c := range res.Collections {
+		if c.Name == cName {
+			return nil
+		}
+	}
+
+	if len(res.Collections) < h.QuotaMaxCollections {
+		return nil
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrQuotaExceeded,
+		fmt.Sprintf("quota exceeded: database %s can't have more than %d collections", dbName, h.QuotaMaxCollections),
+		command,
+	)
+}
+
+// collectionUsage returns the number of documents and the size of the collection in bytes
+// used for quota checks.
+//
+// Both values are taken from the collection statistics and may be approximate.
+// Zeros are returned if the collection does not exist.
+func collectionUsage(ctx context.Context, c backends.Collection) (int64, int64, error) {
+	stats, err := c.Stats(ctx, new(backends.CollectionStatsParams))
+
+	switch {
+	case err == nil:
+		return stats.CountDocuments, stats.SizeCollection, nil
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		return 0, 0, nil
+	default:
+		return 0, 0, lazyerrors.Error(err)
+	}
+}
+
+// insertQuota returns the number of leading documents that could be inserted into the collection
+// without exceeding its quotas, and the *handlererrors.CommandError describing the exceeded quota for the rest of them.
+//
+// It returns all documents and nil error if limits are not set.
+func (h *Handler) insertQuota(ctx context.Context, c backends.Collection, docs []*types.Document, dbName, cName, command string) (int, error) {
+	if h.QuotaMaxDocuments <= 0 && h.QuotaMaxSize <= 0 {
+		return len(docs), nil
+	}
+
+	count, size, err := collectionUsage(ctx, c)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	for i, doc := range docs {
+		count++
+		if h.QuotaMaxDocuments > 0 && count > h.QuotaMaxDocuments {
+			return i, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrQuotaExceeded,
+				h.documentsQuotaMsg(dbName, cName),
+				command,
+			)
+		}
+
+		if h.QuotaMaxSize <= 0 {
+			continue
+		}
+
+		var docSize int
+		if docSize, err = documentSize(doc); err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		size += int64(docSize)
+		if size > h.QuotaMaxSize {
+			return i, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrQuotaExceeded,
+				h.sizeQuotaMsg(dbName, cName),
+				command,
+			)
+		}
+	}
+
+	return len(docs), nil
+}
+
+// checkUpdateQuota returns an error if the collection reached its quotas.
+//
+// Updates are rejected when the collection size reaches the limit;
+// upserts are also rejected when the number of documents reaches the limit.
+// Because the size of updated and upserted documents is not known in advance,
+// the collection may slightly exceed the size limit.
+func (h *Handler) checkUpdateQuota(ctx context.Context, c backends.Collection, upsert bool, dbName, cName, command string) error {
+	if h.QuotaMaxSize <= 0 && (h.QuotaMaxDocuments <= 0 || !upsert) {
+		return nil
+	}
+
+	count, size, err := collectionUsage(ctx, c)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if upsert && h.QuotaMaxDocuments > 0 && count >= h.QuotaMaxDocuments {
+		return common.NewUpdateError(handlererrors.ErrQuotaExceeded, h.documentsQuotaMsg(dbName, cName), command)
+	}
+
+	if h.QuotaMaxSize > 0 && size >= h.QuotaMaxSize {
+		return common.NewUpdateError(handlererrors.ErrQuotaExceeded, h.sizeQuotaMsg(dbName, cName), command)
+	}
+
+	return nil
+}
+
+// documentsQuotaMsg returns an error message for the exceeded maximum number of documents in the collection.
+func (h *Handler) documentsQuotaMsg(dbName, cName string) string {
+	return fmt.Sprintf("quota exceeded: collection %s.%s can't have more than %d documents", dbName, cName, h.QuotaMaxDocuments)
+}
+
+// sizeQuotaMsg returns an error message for the exceeded maximum size of the collection.
+func (h *Handler) sizeQuotaMsg(dbName, cName string) string {
+	return fmt.Sprintf("quota exceeded: collection %s.%s can't be larger than %d bytes", dbName, cName, h.QuotaMaxSize)
+}
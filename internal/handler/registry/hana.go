@@ -42,22 +42,68 @@ func init() {
 			Backend:     b,
 			TCPHost:     opts.TCPHost,
 			ReplSetName: opts.ReplSetName,
+			ExportURL:   opts.ExportURL,
+
+			BackupURL:                  opts.BackupURL,
+			BackupServerSideEncryption: opts.BackupServerSideEncryption,
+			BackupKMSKeyID:             opts.BackupKMSKeyID,
+
+			SessionTimeout:         opts.SessionTimeout,
+			SessionCleanupInterval: opts.SessionCleanupInterval,
 
 			SetupDatabase: opts.SetupDatabase,
 			SetupUsername: opts.SetupUsername,
 			SetupPassword: opts.SetupPassword,
 			SetupTimeout:  opts.SetupTimeout,
 
+			SCRAMIterationCount:       opts.SCRAMIterationCount,
+			SCRAMSHA256IterationCount: opts.SCRAMSHA256IterationCount,
+			PasswordMinLength:         opts.PasswordMinLength,
+			PasswordRequireComplexity: opts.PasswordRequireComplexity,
+
+			AuthFailureDelay:     opts.AuthFailureDelay,
+			AuthFailureMaxDelay:  opts.AuthFailureMaxDelay,
+			AuthLockoutThreshold: opts.AuthLockoutThreshold,
+			AuthLockoutDuration:  opts.AuthLockoutDuration,
+
+			DisableAutoMigrate: opts.DisableAutoMigrate,
+			ReadOnly:           opts.ReadOnly,
+
+			QuotaMaxCollections: opts.QuotaMaxCollections,
+			QuotaMaxDocuments:   opts.QuotaMaxDocuments,
+			QuotaMaxSize:        opts.QuotaMaxSize,
+
+			MaxBsonObjectSizeBytes: opts.MaxBsonObjectSizeBytes,
+			ResultCacheSize:        opts.ResultCacheSize,
+			CursorReadaheadSize:    opts.CursorReadaheadSize,
+			OperationMemoryLimit:   opts.OperationMemoryLimit,
+			SlowQueryThreshold:     opts.SlowQueryThreshold,
+			SlowQueryExplain:       opts.SlowQueryExplain,
+
+			BackendTimeout:    opts.BackendTimeout,
+			BackendHedgeReads: opts.BackendHedgeReads,
+
+			ConsistencyCheckInterval:   opts.ConsistencyCheckInterval,
+			ConsistencyCheckSampleSize: opts.ConsistencyCheckSampleSize,
+
+			TenantPrefixes: opts.TenantPrefixes,
+
+			WarmupTimeout:     opts.WarmupTimeout,
+			WarmupConnections: opts.WarmupConnections,
+
+			Shutdown: opts.Shutdown,
+
 			L:             logging.WithName(opts.Logger, "hana"),
 			ConnMetrics:   opts.ConnMetrics,
 			StateProvider: opts.StateProvider,
 
 			DisablePushdown:         opts.DisablePushdown,
+			EnableExplainSQL:        opts.EnableExplainSQL,
 			CappedCleanupPercentage: opts.CappedCleanupPercentage,
 			CappedCleanupInterval:   opts.CappedCleanupInterval,
 			EnableNewAuth:           opts.EnableNewAuth,
 			BatchSize:               opts.BatchSize,
-			MaxBsonObjectSizeBytes:  opts.MaxBsonObjectSizeBytes,
+			RandSeed:                opts.RandSeed,
 		}
 
 		h, err := handler.New(handlerOpts)
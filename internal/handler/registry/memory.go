@@ -0,0 +1,114 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !ferretdb_no_memory
+
+package registry
+
+import (
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
+	"github.com/FerretDB/FerretDB/internal/handler"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+)
+
+// init registers "memory" handler.
+func init() {
+	registry["memory"] = func(opts *NewHandlerOpts) (*handler.Handler, CloseBackendFunc, error) {
+		b, err := memory.NewBackend(&memory.NewBackendParams{
+			L: logging.WithName(opts.Logger, "memory"),
+			P: opts.StateProvider,
+		})
+		if err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		handlerOpts := &handler.NewOpts{
+			Backend:     b,
+			TCPHost:     opts.TCPHost,
+			ReplSetName: opts.ReplSetName,
+			ExportURL:   opts.ExportURL,
+
+			BackupURL:                  opts.BackupURL,
+			BackupServerSideEncryption: opts.BackupServerSideEncryption,
+			BackupKMSKeyID:             opts.BackupKMSKeyID,
+
+			SessionTimeout:         opts.SessionTimeout,
+			SessionCleanupInterval: opts.SessionCleanupInterval,
+
+			SetupDatabase: opts.SetupDatabase,
+			SetupUsername: opts.SetupUsername,
+			SetupPassword: opts.SetupPassword,
+			SetupTimeout:  opts.SetupTimeout,
+
+			SCRAMIterationCount:       opts.SCRAMIterationCount,
+			SCRAMSHA256IterationCount: opts.SCRAMSHA256IterationCount,
+			PasswordMinLength:         opts.PasswordMinLength,
+			PasswordRequireComplexity: opts.PasswordRequireComplexity,
+
+			AuthFailureDelay:     opts.AuthFailureDelay,
+			AuthFailureMaxDelay:  opts.AuthFailureMaxDelay,
+			AuthLockoutThreshold: opts.AuthLockoutThreshold,
+			AuthLockoutDuration:  opts.AuthLockoutDuration,
+
+			DisableAutoMigrate: opts.DisableAutoMigrate,
+			ReadOnly:           opts.ReadOnly,
+
+			QuotaMaxCollections: opts.QuotaMaxCollections,
+			QuotaMaxDocuments:   opts.QuotaMaxDocuments,
+			QuotaMaxSize:        opts.QuotaMaxSize,
+
+			MaxBsonObjectSizeBytes: opts.MaxBsonObjectSizeBytes,
+			ResultCacheSize:        opts.ResultCacheSize,
+			CursorReadaheadSize:    opts.CursorReadaheadSize,
+			OperationMemoryLimit:   opts.OperationMemoryLimit,
+			SlowQueryThreshold:     opts.SlowQueryThreshold,
+			SlowQueryExplain:       opts.SlowQueryExplain,
+
+			BackendTimeout:    opts.BackendTimeout,
+			BackendHedgeReads: opts.BackendHedgeReads,
+
+			ConsistencyCheckInterval:   opts.ConsistencyCheckInterval,
+			ConsistencyCheckSampleSize: opts.ConsistencyCheckSampleSize,
+
+			TenantPrefixes: opts.TenantPrefixes,
+
+			WarmupTimeout:     opts.WarmupTimeout,
+			WarmupConnections: opts.WarmupConnections,
+
+			Shutdown: opts.Shutdown,
+
+			L:             logging.WithName(opts.Logger, "memory"),
+			ConnMetrics:   opts.ConnMetrics,
+			StateProvider: opts.StateProvider,
+
+			DisablePushdown:         opts.DisablePushdown,
+			EnableNestedPushdown:    opts.EnableNestedPushdown,
+			EnableSortPushdown:      opts.EnableSortPushdown,
+			EnableExplainSQL:        opts.EnableExplainSQL,
+			CappedCleanupPercentage: opts.CappedCleanupPercentage,
+			CappedCleanupInterval:   opts.CappedCleanupInterval,
+			EnableNewAuth:           opts.EnableNewAuth,
+			BatchSize:               opts.BatchSize,
+			RandSeed:                opts.RandSeed,
+		}
+
+		h, err := handler.New(handlerOpts)
+		if err != nil {
+			return nil, b.Close, lazyerrors.Error(err)
+		}
+
+		return h, b.Close, nil
+	}
+}
@@ -28,9 +28,16 @@ func init() {
 	registry["postgresql"] = func(opts *NewHandlerOpts) (*handler.Handler, CloseBackendFunc, error) {
 		b, err := postgresql.NewBackend(&postgresql.NewBackendParams{
 			URI:       opts.PostgreSQLURL,
+			URIFile:   opts.PostgreSQLURLFile,
 			L:         logging.WithName(opts.Logger, "postgresql"),
 			P:         opts.StateProvider,
 			BatchSize: opts.BatchSize,
+			TLS: &postgresql.TLSParams{
+				CAFile:     opts.PostgreSQLTLSCAFile,
+				CertFile:   opts.PostgreSQLTLSCertFile,
+				KeyFile:    opts.PostgreSQLTLSKeyFile,
+				VerifyFull: opts.PostgreSQLTLSVerifyFull,
+			},
 		})
 		if err != nil {
 			return nil, nil, lazyerrors.Error(err)
@@ -40,23 +47,70 @@ func init() {
 			Backend:     b,
 			TCPHost:     opts.TCPHost,
 			ReplSetName: opts.ReplSetName,
+			ExportURL:   opts.ExportURL,
+
+			BackupURL:                  opts.BackupURL,
+			BackupServerSideEncryption: opts.BackupServerSideEncryption,
+			BackupKMSKeyID:             opts.BackupKMSKeyID,
+
+			SessionTimeout:         opts.SessionTimeout,
+			SessionCleanupInterval: opts.SessionCleanupInterval,
 
 			SetupDatabase: opts.SetupDatabase,
 			SetupUsername: opts.SetupUsername,
 			SetupPassword: opts.SetupPassword,
 			SetupTimeout:  opts.SetupTimeout,
 
+			SCRAMIterationCount:       opts.SCRAMIterationCount,
+			SCRAMSHA256IterationCount: opts.SCRAMSHA256IterationCount,
+			PasswordMinLength:         opts.PasswordMinLength,
+			PasswordRequireComplexity: opts.PasswordRequireComplexity,
+
+			AuthFailureDelay:     opts.AuthFailureDelay,
+			AuthFailureMaxDelay:  opts.AuthFailureMaxDelay,
+			AuthLockoutThreshold: opts.AuthLockoutThreshold,
+			AuthLockoutDuration:  opts.AuthLockoutDuration,
+
+			DisableAutoMigrate: opts.DisableAutoMigrate,
+			ReadOnly:           opts.ReadOnly,
+
+			QuotaMaxCollections: opts.QuotaMaxCollections,
+			QuotaMaxDocuments:   opts.QuotaMaxDocuments,
+			QuotaMaxSize:        opts.QuotaMaxSize,
+
+			MaxBsonObjectSizeBytes: opts.MaxBsonObjectSizeBytes,
+			ResultCacheSize:        opts.ResultCacheSize,
+			CursorReadaheadSize:    opts.CursorReadaheadSize,
+			OperationMemoryLimit:   opts.OperationMemoryLimit,
+			SlowQueryThreshold:     opts.SlowQueryThreshold,
+			SlowQueryExplain:       opts.SlowQueryExplain,
+
+			BackendTimeout:    opts.BackendTimeout,
+			BackendHedgeReads: opts.BackendHedgeReads,
+
+			ConsistencyCheckInterval:   opts.ConsistencyCheckInterval,
+			ConsistencyCheckSampleSize: opts.ConsistencyCheckSampleSize,
+
+			TenantPrefixes: opts.TenantPrefixes,
+
+			WarmupTimeout:     opts.WarmupTimeout,
+			WarmupConnections: opts.WarmupConnections,
+
+			Shutdown: opts.Shutdown,
+
 			L:             logging.WithName(opts.Logger, "postgresql"),
 			ConnMetrics:   opts.ConnMetrics,
 			StateProvider: opts.StateProvider,
 
 			DisablePushdown:         opts.DisablePushdown,
 			EnableNestedPushdown:    opts.EnableNestedPushdown,
+			EnableSortPushdown:      opts.EnableSortPushdown,
+			EnableExplainSQL:        opts.EnableExplainSQL,
 			CappedCleanupPercentage: opts.CappedCleanupPercentage,
 			CappedCleanupInterval:   opts.CappedCleanupInterval,
 			EnableNewAuth:           opts.EnableNewAuth,
 			BatchSize:               opts.BatchSize,
-			MaxBsonObjectSizeBytes:  opts.MaxBsonObjectSizeBytes,
+			RandSeed:                opts.RandSeed,
 		}
 
 		h, err := handler.New(handlerOpts)
@@ -23,6 +23,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/internal/handler"
 	"github.com/FerretDB/FerretDB/internal/util/password"
+	"github.com/FerretDB/FerretDB/internal/util/secret"
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
 
@@ -46,13 +47,65 @@ type NewHandlerOpts struct {
 	StateProvider *state.Provider
 	TCPHost       string
 	ReplSetName   string
+	ExportURL     string
+
+	BackupURL                  string
+	BackupServerSideEncryption string
+	BackupKMSKeyID             string
+
+	SessionTimeout         time.Duration
+	SessionCleanupInterval time.Duration
+
 	SetupDatabase string
 	SetupUsername string
 	SetupPassword password.Password
 	SetupTimeout  time.Duration
 
+	SCRAMIterationCount       int
+	SCRAMSHA256IterationCount int
+	PasswordMinLength         int
+	PasswordRequireComplexity bool
+
+	AuthFailureDelay     time.Duration
+	AuthFailureMaxDelay  time.Duration
+	AuthLockoutThreshold int
+	AuthLockoutDuration  time.Duration
+
+	DisableAutoMigrate bool
+	ReadOnly           bool
+
+	QuotaMaxCollections int
+	QuotaMaxDocuments   int64
+	QuotaMaxSize        int64
+
+	MaxBsonObjectSizeBytes int
+	ResultCacheSize        int64
+	CursorReadaheadSize    int64
+	OperationMemoryLimit   int64
+	SlowQueryThreshold     time.Duration
+	SlowQueryExplain       bool
+
+	BackendTimeout    time.Duration
+	BackendHedgeReads bool
+
+	ConsistencyCheckInterval   time.Duration
+	ConsistencyCheckSampleSize int
+
+	TenantPrefixes map[string]string
+
+	WarmupTimeout     time.Duration
+	WarmupConnections int
+
+	Shutdown func()
+
 	// for `postgresql` handler
-	PostgreSQLURL string
+	PostgreSQLURL     string
+	PostgreSQLURLFile *secret.File // if set, PostgreSQLURL is ignored
+
+	PostgreSQLTLSCAFile     string
+	PostgreSQLTLSCertFile   string
+	PostgreSQLTLSKeyFile    string
+	PostgreSQLTLSVerifyFull bool
 
 	// for `sqlite` handler
 	SQLiteURL string
@@ -72,11 +125,13 @@ type NewHandlerOpts struct {
 type TestOpts struct {
 	DisablePushdown         bool
 	EnableNestedPushdown    bool
+	EnableSortPushdown      bool
+	EnableExplainSQL        bool
 	CappedCleanupInterval   time.Duration
 	CappedCleanupPercentage uint8
 	EnableNewAuth           bool
 	BatchSize               int
-	MaxBsonObjectSizeBytes  int
+	RandSeed                uint64
 	_                       struct{} // prevent unkeyed literals
 }
 
@@ -106,7 +161,7 @@ func Handlers() []string {
 	res := make([]string, 0, len(registry))
 
 	// double check registered names and return them in the right order
-	for _, h := range []string{"postgresql", "sqlite", "hana", "mysql"} {
+	for _, h := range []string{"postgresql", "sqlite", "memory", "hana", "mysql"} {
 		if _, ok := registry[h]; !ok {
 			continue
 		}
@@ -118,4 +118,11 @@ func TestDeps(t *testing.T) {
 		assert.Contains(t, diff, "github.com/FerretDB/FerretDB/internal/backends/sqlite")
 		assert.Contains(t, diff, "modernc.org/sqlite")
 	})
+
+	t.Run("NoMemory", func(t *testing.T) {
+		t.Parallel()
+
+		diff := negTagPackages(t, "ferretdb_no_memory")
+		assert.Contains(t, diff, "github.com/FerretDB/FerretDB/internal/backends/memory")
+	})
 }
@@ -0,0 +1,225 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// resultCacheCommands contains read commands which results can be cached.
+var resultCacheCommands = map[string]struct{}{
+	"count":    {},
+	"distinct": {},
+	"find":     {},
+}
+
+// resultCacheIgnoredFields contains command fields that do not affect results.
+var resultCacheIgnoredFields = []string{
+	"$clusterTime",
+	"$readPreference",
+	"comment",
+	"lsid",
+	"maxTimeMS",
+	"readConcern",
+}
+
+// resultCacheNondeterministicOperators contains operators which results differ between runs.
+var resultCacheNondeterministicOperators = map[string]struct{}{
+	"$rand":       {},
+	"$sampleRate": {},
+}
+
+// resultCacheNondeterministicVariables contains system variables which values differ between runs.
+var resultCacheNondeterministicVariables = []string{
+	"$$CLUSTER_TIME",
+	"$$NOW",
+}
+
+// resultCacheNondeterministic returns true if the given value uses operators or variables
+// which results differ between runs of the same command.
+func resultCacheNondeterministic(v any) bool {
+	switch v := v.(type) {
+	case *types.Document:
+		for _, k := range v.Keys() {
+			if _, ok := resultCacheNondeterministicOperators[k]; ok {
+				return true
+			}
+
+			if resultCacheNondeterministic(must.NotFail(v.Get(k))) {
+				return true
+			}
+		}
+
+	case *types.Array:
+		for i := range v.Len() {
+			if resultCacheNondeterministic(must.NotFail(v.Get(i))) {
+				return true
+			}
+		}
+
+	case string:
+		for _, name := range resultCacheNondeterministicVariables {
+			if v == name || strings.HasPrefix(v, name+".") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// resultCacheKey returns the database name and the result cache key for the given read command.
+// It returns empty key if the command's result should not be cached.
+func resultCacheKey(ctx context.Context, msg *wire.OpMsg) (string, string) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return "", ""
+	}
+
+	dbName, _ := document.Get("$db")
+
+	db, _ := dbName.(string)
+	switch db {
+	case "", "admin", "config", "local":
+		// local contains the oplog, and admin contains users that could be changed by commands in other databases
+		return "", ""
+	}
+
+	for _, k := range []string{"txnNumber", "tailable", "awaitData"} {
+		if document.Has(k) {
+			return "", ""
+		}
+	}
+
+	for _, k := range resultCacheIgnoredFields {
+		document.Remove(k)
+	}
+
+	if resultCacheNondeterministic(document) {
+		return "", ""
+	}
+
+	doc, err := bson.FromDocument(document)
+	if err != nil {
+		return "", ""
+	}
+
+	b, err := doc.Encode()
+	if err != nil {
+		return "", ""
+	}
+
+	// include username, as backend permissions could be different for different users
+	username, _, _, _ := conninfo.Get(ctx).Auth()
+
+	return db, username + "\x00" + string(b)
+}
+
+// cachingHandler wraps the given read command handler to use the result cache.
+func (h *Handler) cachingHandler(command string, handler CommandFunc) CommandFunc {
+	return func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		db, key := resultCacheKey(ctx, msg)
+		if key == "" {
+			return handler(ctx, msg)
+		}
+
+		if res := h.resultCache.Get(db, key); res != nil {
+			return wire.NewOpMsg(wirebson.RawDocument(res))
+		}
+
+		version := h.resultCache.Version(db)
+
+		reply, err := handler(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+
+		raw := reply.RawSection0()
+
+		if command == "find" {
+			// cache only results that fit into the first batch
+			doc, err := bson.ToDocument(raw)
+			if err != nil {
+				return reply, nil
+			}
+
+			if id, _ := doc.GetByPath(types.NewStaticPath("cursor", "id")); id != int64(0) {
+				return reply, nil
+			}
+		}
+
+		h.resultCache.Put(db, key, version, raw)
+
+		return reply, nil
+	}
+}
+
+// invalidatingHandler wraps the given write command handler to invalidate the result cache.
+//
+// Commands in the admin database could change other databases (for example, `renameCollection`),
+// so they invalidate all cached results.
+func (h *Handler) invalidatingHandler(handler CommandFunc) CommandFunc {
+	return func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		var db string
+
+		if document, err := opMsgDocument(msg); err == nil {
+			v, _ := document.Get("$db")
+			db, _ = v.(string)
+		}
+
+		if db == "admin" {
+			db = ""
+		}
+
+		// invalidate after the command is executed, even if it failed after a partial write
+		defer h.resultCache.Invalidate(db)
+
+		return handler(ctx, msg)
+	}
+}
+
+// invalidateResultCache invalidates cached results for the given database,
+// or all cached results if it is empty.
+//
+// It should be called by background operations that change data
+// after the write command that started them returned, or without any command at all.
+func (h *Handler) invalidateResultCache(db string) {
+	if h.resultCache != nil {
+		h.resultCache.Invalidate(db)
+	}
+}
+
+// resultCacheInterceptor uses the result cache for cacheable read commands
+// and invalidates it after write commands.
+func (h *Handler) resultCacheInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if info.Write {
+		return h.invalidatingHandler(next)(ctx, msg)
+	}
+
+	if _, ok := resultCacheCommands[info.Name]; ok {
+		return h.cachingHandler(info.Name, next)(ctx, msg)
+	}
+
+	return next(ctx, msg)
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestResultCacheKey(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		filter   *wirebson.Document
+		cachable bool
+	}{
+		"Plain": {
+			filter:   wirebson.MustDocument("v", "foo"),
+			cachable: true,
+		},
+		"Rand": {
+			filter: wirebson.MustDocument("$expr", wirebson.MustDocument(
+				"$lt", wirebson.MustArray(wirebson.MustDocument("$rand", wirebson.MakeDocument(0)), 0.5),
+			)),
+		},
+		"SampleRate": {
+			filter: wirebson.MustDocument("$sampleRate", 0.5),
+		},
+		"Now": {
+			filter: wirebson.MustDocument("$expr", wirebson.MustDocument(
+				"$lt", wirebson.MustArray("$date", "$$NOW"),
+			)),
+		},
+		"ClusterTime": {
+			filter: wirebson.MustDocument("$expr", wirebson.MustDocument(
+				"$lt", wirebson.MustArray("$ts", "$$CLUSTER_TIME"),
+			)),
+		},
+		"NowString": {
+			filter:   wirebson.MustDocument("v", "$$NOWHERE"),
+			cachable: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			msg := wire.MustOpMsg("find", "coll", "filter", tc.filter, "comment", "$$NOW", "$db", "test")
+
+			ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+			db, key := resultCacheKey(ctx, msg)
+
+			if tc.cachable {
+				assert.Equal(t, "test", db)
+				assert.NotEmpty(t, key)
+			} else {
+				assert.Empty(t, key)
+			}
+		})
+	}
+}
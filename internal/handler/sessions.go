@@ -0,0 +1,186 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/google/uuid"
+
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// sessionID returns logical session ID from the given `{id: UUID}` document.
+// The key is used in error messages.
+func sessionID(key string, v any) (uuid.UUID, error) {
+	doc, ok := v.(*types.Document)
+	if !ok {
+		return uuid.Nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf("BSON field '%s' is the wrong type '%s', expected type 'object'", key, handlerparams.AliasFromType(v)),
+			key,
+		)
+	}
+
+	idV, _ := doc.Get("id")
+	if idV == nil {
+		return uuid.Nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			fmt.Sprintf("BSON field '%s.id' is missing but a required field", key),
+			key,
+		)
+	}
+
+	id, ok := idV.(types.Binary)
+	if !ok {
+		return uuid.Nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf("BSON field '%s.id' is the wrong type '%s', expected type 'binData'", key, handlerparams.AliasFromType(idV)),
+			key,
+		)
+	}
+
+	if id.Subtype != types.BinaryUUID || len(id.B) != 16 {
+		return uuid.Nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("BSON field '%s.id' must be a 16-byte binary field with UUID (4) subtype", key),
+			key,
+		)
+	}
+
+	return uuid.UUID(id.B), nil
+}
+
+// optionalSessionID returns logical session ID from the optional `lsid` field value,
+// or zero UUID if it is not set.
+func optionalSessionID(v any) (uuid.UUID, error) {
+	if v == nil {
+		return uuid.Nil, nil
+	}
+
+	return sessionID("lsid", v)
+}
+
+// sessionIDs returns logical session IDs from the array of `{id: UUID}` documents.
+func sessionIDs(command string, arr *types.Array) ([]uuid.UUID, error) {
+	res := make([]uuid.UUID, 0, arr.Len())
+
+	iter := arr.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return res, nil
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		id, err := sessionID(command, v)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, id)
+	}
+}
+
+// sessionIDsParam returns logical session IDs from the required array parameter named after the command.
+func sessionIDsParam(msg *wire.OpMsg) ([]uuid.UUID, error) {
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	arr, err := common.GetRequiredParam[*types.Array](document, command)
+	if err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf("BSON field '%s' is the wrong type, expected type 'array'", command),
+			command,
+		)
+	}
+
+	return sessionIDs(command, arr)
+}
+
+// refreshSession registers or refreshes the logical session passed in the `lsid` field of the command, if any.
+func (h *Handler) refreshSession(connCtx context.Context, msg *wire.OpMsg) error {
+	doc, err := msg.RawSection0().Decode()
+	if err != nil {
+		// the error is reported by the command handler
+		return nil
+	}
+
+	v := doc.Get("lsid")
+	if v == nil {
+		return nil
+	}
+
+	lsid, err := bson.ToDocument(must.NotFail(wirebson.NewDocument("lsid", v)))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	id, err := sessionID("lsid", must.NotFail(lsid.Get("lsid")))
+	if err != nil {
+		return err
+	}
+
+	username, _, _, db := conninfo.Get(connCtx).Auth()
+	h.sessions.Refresh(id, username, db)
+
+	return nil
+}
+
+// endSessions closes cursors of the given logical sessions and removes them from the registry.
+func (h *Handler) endSessions(ids []uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+
+	set := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+
+	for _, c := range h.cursors.All() {
+		if c.Session == uuid.Nil {
+			continue
+		}
+
+		if _, ok := set[c.Session]; ok {
+			h.cursors.CloseAndRemove(c)
+		}
+	}
+
+	h.sessions.Remove(ids...)
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strconv"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// decimalType represents BSON 128-bit decimal floating point type.
+//
+// The value is stored as JSON number, so it could be compared with other numbers by the database.
+// The exponent is stored in the schema, because databases could change the representation of the number
+// (for example, PostgreSQL stores 1.5E+3 as 1500).
+type decimalType types.Decimal128
+
+// sjsontype implements sjsontype interface.
+func (d *decimalType) sjsontype() {}
+
+// UnmarshalJSONWithSchema unmarshals the JSON data with the given schema.
+func (d *decimalType) UnmarshalJSONWithSchema(data []byte, sch *elem) error {
+	if bytes.Equal(data, []byte("null")) {
+		panic("null data")
+	}
+
+	r := bytes.NewReader(data)
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var n json.Number
+	if err := dec.Decode(&n); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if err := checkConsumed(dec, r); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if sch.Exponent == nil {
+		return lazyerrors.Errorf("decimal exponent is nil")
+	}
+
+	v, err := types.ParseDecimal128(n.String())
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	neg, c, e := v.Parts()
+
+	// restore the stored exponent
+	switch exponent := *sch.Exponent; {
+	case e > exponent:
+		c.Mul(c, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(e-exponent)), nil))
+	case e < exponent:
+		var m big.Int
+		c.QuoRem(c, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent-e)), nil), &m)
+
+		if m.Sign() != 0 {
+			return lazyerrors.Errorf("decimal %s does not match exponent %d", n, exponent)
+		}
+	}
+
+	*d = decimalType(types.NewDecimal128(neg, c, *sch.Exponent))
+
+	return nil
+}
+
+// MarshalJSON implements sjsontype interface.
+func (d *decimalType) MarshalJSON() ([]byte, error) {
+	v := types.Decimal128(*d)
+
+	if v.IsNaN() || v.IsInf(0) {
+		return nil, lazyerrors.Errorf("unsupported Decimal128 value %s", v)
+	}
+
+	neg, c, e := v.Parts()
+
+	res := c.Append(nil, 10)
+	res = append(res, 'E')
+	res = strconv.AppendInt(res, int64(e), 10)
+
+	if neg {
+		res = append([]byte{'-'}, res...)
+	}
+
+	return res, nil
+}
+
+// check interfaces
+var (
+	_ sjsontype = (*decimalType)(nil)
+)
@@ -0,0 +1,82 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sjson
+
+import (
+	"testing"
+
+	"github.com/AlekSi/pointer"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+var decimalTestCases = []testCase{{
+	name: "fraction",
+	v:    pointer.To(decimalType(must.NotFail(types.ParseDecimal128("1.50")))),
+	sch:  decimalSchema(-2),
+	j:    `150E-2`,
+}, {
+	name: "negative",
+	v:    pointer.To(decimalType(must.NotFail(types.ParseDecimal128("-42")))),
+	sch:  decimalSchema(0),
+	j:    `-42E0`,
+}, {
+	name: "zero",
+	v:    pointer.To(decimalType(must.NotFail(types.ParseDecimal128("0E+3")))),
+	sch:  decimalSchema(3),
+	j:    `0E3`,
+}, {
+	name:   "normalized",
+	v:      pointer.To(decimalType(must.NotFail(types.ParseDecimal128("1.5E+3")))),
+	sch:    decimalSchema(2),
+	j:      `1500`,
+	canonJ: `15E2`,
+}, {
+	name:   "plain",
+	v:      pointer.To(decimalType(must.NotFail(types.ParseDecimal128("0.0100")))),
+	sch:    decimalSchema(-4),
+	j:      `0.01`,
+	canonJ: `100E-4`,
+}, {
+	name: "large",
+	v:    pointer.To(decimalType(must.NotFail(types.ParseDecimal128("9.999999999999999999999999999999999E+6144")))),
+	sch:  decimalSchema(6111),
+	j:    `9999999999999999999999999999999999E6111`,
+}, {
+	name: "ExponentMismatch",
+	sch:  decimalSchema(-1),
+	j:    `0.01`,
+	jErr: `decimal 0.01 does not match exponent -1`,
+}, {
+	name: "NilExponent",
+	sch:  &elem{Type: elemTypeDecimal},
+	j:    `1`,
+	jErr: `decimal exponent is nil`,
+}, {
+	name: "EOF",
+	sch:  decimalSchema(0),
+	j:    `[`,
+	jErr: `unexpected EOF`,
+}}
+
+func TestDecimal(t *testing.T) {
+	t.Parallel()
+	testJSON(t, decimalTestCases, func() sjsontype { return new(decimalType) })
+}
+
+func BenchmarkDecimal(b *testing.B) {
+	benchmark(b, decimalTestCases, func() sjsontype { return new(decimalType) })
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/AlekSi/pointer"
@@ -35,11 +36,12 @@ type schema struct {
 
 // elem describes an element of schema.
 type elem struct {
-	Type    elemType             `json:"t"`            // for each field
-	Schema  *schema              `json:"$s,omitempty"` // only for objects
-	Options *string              `json:"o,omitempty"`  // only for regex
-	Items   []*elem              `json:"i,omitempty"`  // only for arrays
-	Subtype *types.BinarySubtype `json:"s,omitempty"`  // only for binData
+	Type     elemType             `json:"t"`            // for each field
+	Schema   *schema              `json:"$s,omitempty"` // only for objects
+	Options  *string              `json:"o,omitempty"`  // only for regex
+	Items    []*elem              `json:"i,omitempty"`  // only for arrays
+	Subtype  *types.BinarySubtype `json:"s,omitempty"`  // only for binData
+	Exponent *int                 `json:"e,omitempty"`  // only for decimal
 }
 
 // elemType represents possible types of schema elements.
@@ -60,6 +62,7 @@ const (
 	elemTypeInt       elemType = "int"
 	elemTypeTimestamp elemType = "timestamp"
 	elemTypeLong      elemType = "long"
+	elemTypeDecimal   elemType = "decimal"
 )
 
 // GetTypeOfValue returns sjson type of supported value.
@@ -93,6 +96,8 @@ func GetTypeOfValue(v any) string {
 		return string(elemTypeTimestamp)
 	case int64:
 		return string(elemTypeLong)
+	case types.Decimal128:
+		return string(elemTypeDecimal)
 	}
 
 	panic(fmt.Sprintf("Unexpected type: %T", v))
@@ -139,6 +144,12 @@ var (
 	longSchema = &elem{
 		Type: elemTypeLong,
 	}
+	decimalSchema = func(exponent int) *elem {
+		return &elem{
+			Type:     elemTypeDecimal,
+			Exponent: pointer.To(exponent),
+		}
+	}
 )
 
 // marshalSchemaForDoc makes schema for the given document based on its data.
@@ -278,6 +289,17 @@ func marshalElemForSingleValue(value any) ([]byte, error) {
 	case int64:
 		buf.WriteString(`{"t":"long"}`)
 
+	case types.Decimal128:
+		if val.IsNaN() || val.IsInf(0) {
+			return nil, lazyerrors.Errorf("unsupported Decimal128 value %s", val)
+		}
+
+		_, _, exponent := val.Parts()
+
+		buf.WriteString(`{"t":"decimal","e":`)
+		buf.WriteString(strconv.Itoa(exponent))
+		buf.WriteString(`}`)
+
 	default:
 		panic(fmt.Sprintf("sjson.marshalElemForSingleValue: unknown type %[1]T (value %[1]q)", val))
 	}
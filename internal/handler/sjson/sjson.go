@@ -59,6 +59,8 @@
 //	int        int32            *sjson.int32Type      {"t":"int"}                            JSON number
 //	timestamp  types.Timestamp  *sjson.timestampType  {"t":"timestamp"}                      JSON number
 //	long       int64            *sjson.int64Type      {"t":"long"}                           JSON number
+//	decimal    types.Decimal128 *sjson.decimalType    {"t":"decimal",
+//	                                                   "e":<exponent>}                       JSON number
 //
 //nolint:lll // for readability
 package sjson
@@ -133,6 +135,8 @@ func fromSJSON(v sjsontype) any {
 		return types.Timestamp(*v)
 	case *int64Type:
 		return int64(*v)
+	case *decimalType:
+		return types.Decimal128(*v)
 	}
 
 	panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
@@ -167,6 +171,8 @@ func toSJSON(v any) sjsontype {
 		return pointer.To(timestampType(v))
 	case int64:
 		return pointer.To(int64Type(v))
+	case types.Decimal128:
+		return pointer.To(decimalType(v))
 	}
 
 	panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
@@ -237,6 +243,35 @@ func Unmarshal(data []byte) (*types.Document, error) {
 	return d, nil
 }
 
+// UnmarshalSingleValue decodes the given sjson-encoded value by the given sjson-encoded schema element.
+// Use it when you need to decode a single value extracted from the document, for example a group key.
+func UnmarshalSingleValue(data, schema []byte) (any, error) {
+	var sch *elem
+
+	if schema != nil {
+		sch = new(elem)
+
+		r := bytes.NewReader(schema)
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(sch); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if err := checkConsumed(dec, r); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	v, err := unmarshalSingleValue(data, sch)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return v, nil
+}
+
 // unmarshalSingleValue decodes the given sjson-encoded data element by the given schema.
 func unmarshalSingleValue(data json.RawMessage, sch *elem) (any, error) {
 	if bytes.Equal(data, []byte("null")) {
@@ -309,6 +344,10 @@ func unmarshalSingleValue(data json.RawMessage, sch *elem) (any, error) {
 		var l int64Type
 		err = l.UnmarshalJSON(data)
 		res = &l
+	case elemTypeDecimal:
+		var d decimalType
+		err = d.UnmarshalJSONWithSchema(data, sch)
+		res = &d
 	default:
 		return nil, lazyerrors.Errorf("sjson.unmarshalSingleValue: unhandled type %q", sch.Type)
 	}
@@ -342,11 +381,16 @@ func Marshal(d *types.Document) ([]byte, error) {
 
 	for i, key := range keys {
 		buf.WriteByte(',')
-		buf.WriteString(`"`)
-		buf.WriteString(key)
-		buf.WriteString(`":`)
 
-		b, err := toSJSON(values[i]).MarshalJSON()
+		b, err := json.Marshal(key)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		buf.Write(b)
+		buf.WriteByte(':')
+
+		b, err = toSJSON(values[i]).MarshalJSON()
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
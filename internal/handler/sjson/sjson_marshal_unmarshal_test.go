@@ -48,6 +48,18 @@ func TestMarshalUnmarshal(t *testing.T) {
 				"foo", "bar",
 			)),
 		},
+		"EscapedKey": {
+			json: `{
+			"$s": {
+				"p": {"a\",\"b": {"t": "string"}},
+				"$k": ["a\",\"b"]
+			},
+			"a\",\"b": "c"
+		}`,
+			doc: must.NotFail(types.NewDocument(
+				`a","b`, "c",
+			)),
+		},
 	} {
 		tc := tc
 
@@ -141,3 +153,53 @@ func TestUnmarshalInvalid(t *testing.T) {
 		})
 	}
 }
+
+func TestUnmarshalSingleValue(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		data     string
+		schema   string
+		expected any
+	}{
+		"Null": {
+			data:     `null`,
+			expected: types.Null,
+		},
+		"Int": {
+			data:     `42`,
+			schema:   `{"t": "int"}`,
+			expected: int32(42),
+		},
+		"ObjectID": {
+			data:     `"000102030405060708091011"`,
+			schema:   `{"t": "objectId"}`,
+			expected: types.ObjectID{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x10, 0x11},
+		},
+		"Array": {
+			data:     `["foo", 1]`,
+			schema:   `{"t": "array", "i": [{"t": "string"}, {"t": "long"}]}`,
+			expected: must.NotFail(types.NewArray("foo", int64(1))),
+		},
+		"Document": {
+			data:     `{"foo": "bar"}`,
+			schema:   `{"t": "object", "$s": {"p": {"foo": {"t": "string"}}, "$k": ["foo"]}}`,
+			expected: must.NotFail(types.NewDocument("foo", "bar")),
+		},
+	} {
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var schema []byte
+			if tc.schema != "" {
+				schema = []byte(tc.schema)
+			}
+
+			v, err := UnmarshalSingleValue([]byte(tc.data), schema)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+}
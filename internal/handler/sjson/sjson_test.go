@@ -211,6 +211,8 @@ func unmarshalJSON(v sjsontype, tc *testCase) error {
 		err = v.UnmarshalJSON([]byte(tc.j))
 	case *int64Type:
 		err = v.UnmarshalJSON([]byte(tc.j))
+	case *decimalType:
+		err = v.UnmarshalJSONWithSchema([]byte(tc.j), tc.sch)
 	default:
 		panic(fmt.Sprintf("not reached: %T", v)) // for sumtype to work
 	}
@@ -0,0 +1,288 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FerretDB/wire"
+
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// tenantDeniedCommands contains commands that tenant users can't run in addition to admin-only commands.
+//
+// User management commands are denied because created users would not be mapped to any tenant.
+// Other commands are denied because they affect other tenants or return server-wide information.
+var tenantDeniedCommands = map[string]struct{}{
+	"createUser":               {},
+	"dropAllUsersFromDatabase": {},
+	"dropUser":                 {},
+	"getCmdLineOpts":           {},
+	"getLog":                   {},
+	"hostInfo":                 {},
+	"killAllSessions":          {},
+	"serverStatus":             {},
+	"updateUser":               {},
+	"usersInfo":                {},
+}
+
+// validateTenantPrefixes checks that tenant prefixes could be used in database names.
+func validateTenantPrefixes(prefixes map[string]string) error {
+	for user, prefix := range prefixes {
+		if user == "" {
+			return fmt.Errorf("tenant prefix %q: username should not be empty", prefix)
+		}
+
+		if prefix == "" || strings.ContainsAny(prefix, "/\\. \"$\x00") {
+			return fmt.Errorf("tenant prefix %q of user %q is not a valid database name prefix", prefix, user)
+		}
+	}
+
+	return nil
+}
+
+// tenantInterceptor isolates tenants by prefixing database names of users mapped to tenants.
+//
+// Requests are rewritten to use prefixed database names,
+// and prefixes are removed from namespaces and database names in responses.
+// Anonymous commands and commands of unmapped users are not changed.
+func (h *Handler) tenantInterceptor(ctx context.Context, info *CommandInfo, msg *wire.OpMsg, next CommandFunc) (*wire.OpMsg, error) { //nolint:lll // for readability
+	if info.Anonymous {
+		return next(ctx, msg)
+	}
+
+	username, _, _, _ := conninfo.Get(ctx).Auth()
+
+	prefix, ok := h.TenantPrefixes[username]
+	if !ok {
+		return next(ctx, msg)
+	}
+
+	if _, denied := tenantDeniedCommands[info.Name]; denied || info.AdminOnly {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			fmt.Sprintf("Command %s is not allowed for tenant users", info.Name),
+			info.Name,
+		)
+	}
+
+	document, err := opMsgDocument(msg)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	t := &tenant{prefix: prefix}
+
+	var listDatabases *listDatabasesParams
+	if info.Name == "listDatabases" {
+		listDatabases = t.extractListDatabasesParams(document)
+	}
+
+	t.rewriteRequest(info.Name, document)
+
+	if msg, err = documentOpMsg(document); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := next(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	resDoc, err := bson.ToDocument(res.RawSection0())
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if listDatabases != nil {
+		if err = t.filterDatabases(resDoc, listDatabases); err != nil {
+			return nil, err
+		}
+	}
+
+	t.rewriteResponse(resDoc)
+
+	return documentOpMsg(resDoc)
+}
+
+// tenant rewrites requests and responses for a single tenant.
+type tenant struct {
+	prefix string
+}
+
+// listDatabasesParams represents `listDatabases` parameters applied to unprefixed database names.
+type listDatabasesParams struct {
+	filter   *types.Document
+	nameOnly bool
+}
+
+// extractListDatabasesParams removes filter and nameOnly parameters from `listDatabases` request
+// so they could be applied to unprefixed database names.
+func (t *tenant) extractListDatabasesParams(document *types.Document) *listDatabasesParams {
+	var res listDatabasesParams
+
+	v, _ := document.Get("filter")
+	if res.filter, _ = v.(*types.Document); res.filter == nil {
+		res.filter = types.MakeDocument(0)
+	}
+
+	v, _ = document.Get("nameOnly")
+	res.nameOnly, _ = v.(bool)
+
+	document.Remove("filter")
+	document.Remove("nameOnly")
+
+	return &res
+}
+
+// rewriteRequest adds tenant prefix to database names in the request document.
+func (t *tenant) rewriteRequest(command string, document *types.Document) {
+	if db, _ := document.Get("$db"); db != nil {
+		if dbName, ok := db.(string); ok && dbName != "" {
+			document.Set("$db", t.prefix+dbName)
+		}
+	}
+
+	switch command {
+	case "renameCollection":
+		for _, field := range []string{command, "to"} {
+			if ns, _ := document.Get(field); ns != nil {
+				if s, ok := ns.(string); ok && s != "" {
+					document.Set(field, t.prefix+s)
+				}
+			}
+		}
+
+	case "currentOp":
+		// operations of other tenants should not be visible
+		document.Set("$ownOps", true)
+	}
+}
+
+// filterDatabases removes databases of other tenants from `listDatabases` response
+// and applies the original filter and nameOnly parameters.
+func (t *tenant) filterDatabases(document *types.Document, params *listDatabasesParams) error {
+	v, _ := document.Get("databases")
+
+	databases, _ := v.(*types.Array)
+	if databases == nil {
+		return nil
+	}
+
+	res := types.MakeArray(databases.Len())
+
+	var totalSize int64
+
+	iter := databases.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return lazyerrors.Error(err)
+		}
+
+		db, ok := v.(*types.Document)
+		if !ok {
+			continue
+		}
+
+		v, _ = db.Get("name")
+
+		name, _ := v.(string)
+		if !strings.HasPrefix(name, t.prefix) {
+			continue
+		}
+
+		db.Set("name", strings.TrimPrefix(name, t.prefix))
+
+		matches, err := common.FilterDocument(db, params.filter)
+		if err != nil {
+			return err
+		}
+
+		if !matches {
+			continue
+		}
+
+		v, _ = db.Get("sizeOnDisk")
+		size, _ := v.(int64)
+		totalSize += size
+
+		if params.nameOnly {
+			db = must.NotFail(types.NewDocument("name", strings.TrimPrefix(name, t.prefix)))
+		}
+
+		res.Append(db)
+	}
+
+	document.Set("databases", res)
+
+	if params.nameOnly {
+		document.Remove("totalSize")
+		document.Remove("totalSizeMb")
+	} else {
+		document.Set("totalSize", totalSize)
+		document.Set("totalSizeMb", totalSize/1024/1024)
+	}
+
+	return nil
+}
+
+// rewriteResponse removes tenant prefix from namespaces and database names in the response document.
+func (t *tenant) rewriteResponse(document *types.Document) {
+	t.trimField(document, "ns")
+	t.trimField(document, "db")
+
+	if v, _ := document.Get("cursor"); v != nil {
+		if cursor, ok := v.(*types.Document); ok {
+			t.trimField(cursor, "ns")
+		}
+	}
+
+	if v, _ := document.Get("inprog"); v != nil {
+		if inprog, ok := v.(*types.Array); ok {
+			for i := range inprog.Len() {
+				if op, ok := must.NotFail(inprog.Get(i)).(*types.Document); ok {
+					t.trimField(op, "ns")
+				}
+			}
+		}
+	}
+}
+
+// trimField removes tenant prefix from the given string field of the document, if present.
+func (t *tenant) trimField(document *types.Document, field string) {
+	v, _ := document.Get(field)
+
+	if s, ok := v.(string); ok {
+		document.Set(field, strings.TrimPrefix(s, t.prefix))
+	}
+}
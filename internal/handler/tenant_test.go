@@ -0,0 +1,158 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/FerretDB/wire"
+	"github.com/FerretDB/wire/wirebson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestValidateTenantPrefixes(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validateTenantPrefixes(nil))
+	assert.NoError(t, validateTenantPrefixes(map[string]string{"alice": "tenant1_", "bob": "tenant2_"}))
+
+	assert.Error(t, validateTenantPrefixes(map[string]string{"alice": ""}))
+	assert.Error(t, validateTenantPrefixes(map[string]string{"alice": "tenant.1"}))
+	assert.Error(t, validateTenantPrefixes(map[string]string{"": "tenant1_"}))
+}
+
+func TestTenantInterceptor(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHandler(t, &NewOpts{
+		TenantPrefixes: map[string]string{"alice": "tenant1_", "bob": "tenant2_"},
+	})
+
+	userCtx := func(username string) context.Context {
+		connInfo := conninfo.New()
+		connInfo.SetAuth(username, "password", nil, "admin")
+
+		return conninfo.Ctx(testutil.Ctx(t), connInfo)
+	}
+
+	alice, bob, admin := userCtx("alice"), userCtx("bob"), userCtx("admin")
+
+	handle := func(ctx context.Context, pairs ...any) (*wirebson.Document, error) {
+		msg := wire.MustOpMsg(pairs...)
+
+		res, err := h.Commands()[pairs[0].(string)].Handler(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+
+		return must.NotFail(res.RawSection0().DecodeDeep()), nil
+	}
+
+	listDatabases := func(ctx context.Context) []string {
+		res, err := handle(ctx, "listDatabases", int32(1), "nameOnly", true, "$db", "admin")
+		require.NoError(t, err)
+
+		var names []string
+
+		databases := res.Get("databases").(*wirebson.Array)
+		for i := range databases.Len() {
+			names = append(names, databases.Get(i).(*wirebson.Document).Get("name").(string))
+		}
+
+		return names
+	}
+
+	for _, ctx := range []context.Context{alice, bob} {
+		_, err := handle(ctx,
+			"insert", "orders",
+			"documents", wirebson.MustArray(wirebson.MustDocument("_id", int32(1))),
+			"$db", "shop",
+		)
+		require.NoError(t, err)
+	}
+
+	_, err := handle(alice,
+		"insert", "orders",
+		"documents", wirebson.MustArray(wirebson.MustDocument("_id", int32(2))),
+		"$db", "shop",
+	)
+	require.NoError(t, err)
+
+	t.Run("Find", func(t *testing.T) {
+		res, err := handle(alice, "find", "orders", "$db", "shop")
+		require.NoError(t, err)
+
+		cursor := res.Get("cursor").(*wirebson.Document)
+		assert.Equal(t, "shop.orders", cursor.Get("ns"))
+		assert.Equal(t, 2, cursor.Get("firstBatch").(*wirebson.Array).Len())
+
+		res, err = handle(bob, "find", "orders", "$db", "shop")
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.Get("cursor").(*wirebson.Document).Get("firstBatch").(*wirebson.Array).Len())
+	})
+
+	t.Run("ListDatabases", func(t *testing.T) {
+		assert.Equal(t, []string{"shop"}, listDatabases(alice))
+		assert.Equal(t, []string{"shop"}, listDatabases(bob))
+		assert.Subset(t, listDatabases(admin), []string{"tenant1_shop", "tenant2_shop"})
+
+		res, err := handle(alice, "listDatabases", int32(1), "filter", wirebson.MustDocument("name", "shop"), "$db", "admin")
+		require.NoError(t, err)
+		assert.Equal(t, 1, res.Get("databases").(*wirebson.Array).Len())
+		assert.NotNil(t, res.Get("totalSize"))
+	})
+
+	t.Run("RenameCollection", func(t *testing.T) {
+		_, err := handle(bob, "renameCollection", "shop.orders", "to", "shop.archive", "$db", "admin")
+		require.NoError(t, err)
+
+		res, err := handle(admin, "listCollections", int32(1), "nameOnly", true, "$db", "tenant2_shop")
+		require.NoError(t, err)
+
+		batch := res.Get("cursor").(*wirebson.Document).Get("firstBatch").(*wirebson.Array)
+		require.Equal(t, 1, batch.Len())
+		assert.Equal(t, "archive", batch.Get(0).(*wirebson.Document).Get("name"))
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		for _, pairs := range [][]any{
+			{"killOp", int32(1), "op", int32(1), "$db", "admin"},
+			{"killAllSessions", wirebson.MakeArray(0), "$db", "admin"},
+			{"getLog", "global", "$db", "admin"},
+			{"getCmdLineOpts", int32(1), "$db", "admin"},
+			{"serverStatus", int32(1), "$db", "admin"},
+			{"hostInfo", int32(1), "$db", "admin"},
+		} {
+			t.Run(pairs[0].(string), func(t *testing.T) {
+				_, err := handle(alice, pairs...)
+
+				var cmdErr *handlererrors.CommandError
+				require.ErrorAs(t, err, &cmdErr)
+				assert.Equal(t, handlererrors.ErrUnauthorized, cmdErr.Code())
+
+				_, err = handle(admin, pairs...)
+				require.False(t, errors.As(err, &cmdErr) && cmdErr.Code() == handlererrors.ErrUnauthorized, "%v", err)
+			})
+		}
+	})
+}
@@ -38,24 +38,46 @@ type CreateUserParams struct {
 	Username   string
 	Password   password.Password
 	Mechanisms *types.Array
+	Roles      *types.Array // empty if nil
+
+	IterationCounts *IterationCounts // defaults if nil
+}
+
+// IterationCounts represents iteration counts used for SCRAM credentials.
+type IterationCounts struct {
+	SCRAMSHA1   int
+	SCRAMSHA256 int
+}
+
+// DefaultIterationCounts returns default iteration counts.
+func DefaultIterationCounts() *IterationCounts {
+	return &IterationCounts{
+		SCRAMSHA1:   password.DefaultSCRAMSHA1IterationCount,
+		SCRAMSHA256: password.DefaultSCRAMSHA256IterationCount,
+	}
 }
 
 // CreateUser stores a new user in the given backend.
 func CreateUser(ctx context.Context, b backends.Backend, params *CreateUserParams) error {
 	must.NotBeZero(params)
 
-	credentials, err := MakeCredentials(params.Username, params.Password, params.Mechanisms)
+	credentials, err := MakeCredentials(params.Username, params.Password, params.Mechanisms, params.IterationCounts)
 	if err != nil {
 		return err
 	}
 
+	roles := params.Roles
+	if roles == nil {
+		roles = types.MakeArray(0)
+	}
+
 	id := uuid.New()
 	saved := must.NotFail(types.NewDocument(
 		"_id", params.Database+"."+params.Username,
 		"credentials", credentials,
 		"user", params.Username,
 		"db", params.Database,
-		"roles", types.MakeArray(0),
+		"roles", roles,
 		"userId", types.Binary{Subtype: types.BinaryUUID, B: must.NotFail(id.MarshalBinary())},
 	))
 
@@ -71,9 +93,14 @@ func CreateUser(ctx context.Context, b backends.Backend, params *CreateUserParam
 
 // MakeCredentials creates a document with credentials for the chosen mechanisms.
 // The mechanisms array must be validated by the caller.
-func MakeCredentials(username string, userPassword password.Password, mechanisms *types.Array) (*types.Document, error) {
+// Default iteration counts are used if iterationCounts is nil.
+func MakeCredentials(username string, userPassword password.Password, mechanisms *types.Array, iterationCounts *IterationCounts) (*types.Document, error) { //nolint:lll // for readability
 	credentials := types.MakeDocument(0)
 
+	if iterationCounts == nil {
+		iterationCounts = DefaultIterationCounts()
+	}
+
 	if mechanisms == nil {
 		mechanisms = must.NotFail(types.NewArray("SCRAM-SHA-1", "SCRAM-SHA-256"))
 	}
@@ -98,7 +125,7 @@ func MakeCredentials(username string, userPassword password.Password, mechanisms
 
 		switch v {
 		case "SCRAM-SHA-1":
-			if hash, err = password.SCRAMSHA1VariationHash(username, userPassword); err != nil {
+			if hash, err = password.SCRAMSHA1VariationHash(username, userPassword, iterationCounts.SCRAMSHA1); err != nil {
 				return nil, err
 			}
 
@@ -108,7 +135,7 @@ func MakeCredentials(username string, userPassword password.Password, mechanisms
 
 			credentials.Set("SCRAM-SHA-1", hashDoc)
 		case "SCRAM-SHA-256":
-			if hash, err = password.SCRAMSHA256Hash(userPassword); err != nil {
+			if hash, err = password.SCRAMSHA256Hash(userPassword, iterationCounts.SCRAMSHA256); err != nil {
 				return nil, err
 			}
 
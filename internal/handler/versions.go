@@ -0,0 +1,38 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// collectionVersioned returns true if the given collection exists and is versioned.
+//
+// See backends.VersionField.
+func collectionVersioned(ctx context.Context, db backends.Database, cName string) (bool, error) {
+	res, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: cName})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	if len(res.Collections) == 0 {
+		return false, nil
+	}
+
+	return res.Collections[0].Versioned, nil
+}
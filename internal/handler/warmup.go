@@ -0,0 +1,144 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+)
+
+// defaultWarmupConnections is the default number of backend connections established during warm-up.
+const defaultWarmupConnections = 4
+
+// runWarmup prepares the backend for the first requests.
+// [Handler.WarmedUp] returns true after it finishes, successfully or not.
+func (h *Handler) runWarmup(ctx context.Context) {
+	defer h.warmedUp.Store(true)
+
+	if h.WarmupTimeout <= 0 {
+		return
+	}
+
+	h.L.Info("Warm-up started.", slog.Duration("timeout", h.WarmupTimeout), slog.Int("connections", h.WarmupConnections))
+
+	ctx, cancel := context.WithTimeout(ctx, h.WarmupTimeout)
+	defer cancel()
+
+	start := time.Now()
+
+	if err := h.warmup(ctx); err != nil {
+		h.L.Warn("Warm-up failed, reporting readiness anyway.", logging.Error(err))
+		return
+	}
+
+	h.L.Info("Warm-up finished.", slog.Duration("duration", time.Since(start)))
+}
+
+// warmup establishes backend connections, loads metadata of all collections,
+// and runs a small query against each of them to prime backend caches and prepared statements.
+func (h *Handler) warmup(ctx context.Context) error {
+	connInfo := conninfo.New()
+	connInfo.SetBypassBackendAuth()
+	ctx = conninfo.Ctx(ctx, connInfo)
+
+	// concurrent calls make connection pools open several connections
+	var wg sync.WaitGroup
+	errs := make([]error, h.WarmupConnections)
+
+	for i := range h.WarmupConnections {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, errs[i] = h.b.Status(ctx, nil)
+		}()
+	}
+
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	dbList, err := h.b.ListDatabases(ctx, nil)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, dbInfo := range dbList.Databases {
+		db, err := h.b.Database(dbInfo.Name)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		cList, err := db.ListCollections(ctx, nil)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		for _, cInfo := range cList.Collections {
+			if err = warmupCollection(ctx, db, cInfo.Name); err != nil {
+				if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) ||
+					backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+					continue
+				}
+
+				return lazyerrors.Error(err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// warmupCollection loads indexes of the given collection and reads at most one document from it.
+func warmupCollection(ctx context.Context, db backends.Database, name string) error {
+	c, err := db.Collection(name)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = c.ListIndexes(ctx, nil); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	qr, err := c.Query(ctx, &backends.QueryParams{Limit: 1})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	if _, _, err = qr.Iter.Next(); err != nil && !errors.Is(err, iterator.ErrIteratorDone) {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// WarmedUp returns true if the startup warm-up is finished or disabled.
+func (h *Handler) WarmedUp() bool {
+	return h.warmedUp.Load()
+}
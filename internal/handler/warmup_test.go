@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/memory"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestWarmup(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		timeout time.Duration
+	}{
+		"Disabled": {},
+		"Enabled": {
+			timeout: time.Minute,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+
+			sp, err := state.NewProvider("")
+			require.NoError(t, err)
+
+			b, err := memory.NewBackend(&memory.NewBackendParams{L: testutil.Logger(t), P: sp})
+			require.NoError(t, err)
+
+			t.Cleanup(b.Close)
+
+			db, err := b.Database(testutil.DatabaseName(t))
+			require.NoError(t, err)
+
+			c, err := db.Collection(testutil.CollectionName(t))
+			require.NoError(t, err)
+
+			_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+				must.NotFail(types.NewDocument("_id", int32(1))),
+			}})
+			require.NoError(t, err)
+
+			h := newTestHandler(t, &NewOpts{
+				Backend:       b,
+				StateProvider: sp,
+				WarmupTimeout: tc.timeout,
+			})
+
+			assert.Eventually(t, h.WarmedUp, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestWarmupInvalidConnections(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(&NewOpts{WarmupConnections: -1})
+	assert.Error(t, err)
+}
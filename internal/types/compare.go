@@ -16,6 +16,7 @@ package types
 
 import (
 	"bytes"
+	"fmt"
 	"math"
 	"math/big"
 	"time"
@@ -100,14 +101,13 @@ func compareScalars(v1, v2 any) CompareResult {
 	case float64:
 		switch v2 := v2.(type) {
 		case float64:
-			if math.IsNaN(v1) && math.IsNaN(v2) {
-				return Equal
-			}
-			return compareOrdered(v1, v2)
+			return compareFloats(v1, v2)
 		case int32:
 			return compareNumbers(v1, int64(v2))
 		case int64:
 			return compareNumbers(v1, v2)
+		case Decimal128:
+			return compareInvert(compareDecimal(v2, v1))
 		default:
 			return compareTypeOrder(v1, v2)
 		}
@@ -178,10 +178,12 @@ func compareScalars(v1, v2 any) CompareResult {
 	case Regex:
 		v, ok := v2.(Regex)
 		if ok {
-			v1 := must.NotFail(v1.Compile())
-			v := must.NotFail(v.Compile())
+			// patterns are compared first, then options
+			if res := compareOrdered(v1.Pattern, v.Pattern); res != Equal {
+				return res
+			}
 
-			return compareOrdered(v1.String(), v.String())
+			return compareOrdered(v1.Options, v.Options)
 		}
 
 		return compareTypeOrder(v1, v2)
@@ -194,6 +196,8 @@ func compareScalars(v1, v2 any) CompareResult {
 			return compareOrdered(v1, v)
 		case int64:
 			return compareOrdered(int64(v1), v)
+		case Decimal128:
+			return compareInvert(compareDecimal(v, v1))
 		default:
 			return compareTypeOrder(v1, v2)
 		}
@@ -214,6 +218,16 @@ func compareScalars(v1, v2 any) CompareResult {
 			return compareOrdered(v1, int64(v))
 		case int64:
 			return compareOrdered(v1, v)
+		case Decimal128:
+			return compareInvert(compareDecimal(v, v1))
+		default:
+			return compareTypeOrder(v1, v2)
+		}
+
+	case Decimal128:
+		switch v2.(type) {
+		case float64, int32, int64, Decimal128:
+			return compareDecimal(v1, v2)
 		default:
 			return compareTypeOrder(v1, v2)
 		}
@@ -250,14 +264,89 @@ func compareOrdered[T constraints.Ordered](a, b T) CompareResult {
 	}
 }
 
+// compareFloats compares BSON doubles.
+// NaN is equal to NaN and less than any other number.
+func compareFloats(a, b float64) CompareResult {
+	switch aNaN, bNaN := math.IsNaN(a), math.IsNaN(b); {
+	case aNaN && bNaN:
+		return Equal
+	case aNaN:
+		return Less
+	case bNaN:
+		return Greater
+	default:
+		return compareOrdered(a, b)
+	}
+}
+
 // compareNumbers compares BSON numbers.
+// NaN is less than any integer.
 func compareNumbers(a float64, b int64) CompareResult {
+	if math.IsNaN(a) {
+		return Less
+	}
+
 	bigA := new(big.Float).SetFloat64(a).SetPrec(100000)
 	bigB := new(big.Float).SetInt64(b).SetPrec(100000)
 
 	return CompareResult(bigA.Cmp(bigB))
 }
 
+// compareDecimal compares Decimal128 value with a number of any type exactly.
+// NaN is equal to NaN and less than any other number.
+func compareDecimal(a Decimal128, b any) CompareResult {
+	aRat, aInf, aNaN := exactNumber(a)
+	bRat, bInf, bNaN := exactNumber(b)
+
+	switch {
+	case aNaN && bNaN:
+		return Equal
+	case aNaN:
+		return Less
+	case bNaN:
+		return Greater
+	case aInf != 0 || bInf != 0:
+		return compareOrdered(aInf, bInf)
+	default:
+		return CompareResult(aRat.Cmp(bRat))
+	}
+}
+
+// exactNumber returns the exact value of finite number,
+// the sign of infinity (or 0 for finite numbers), and true for NaN.
+func exactNumber(v any) (*big.Rat, int, bool) {
+	switch v := v.(type) {
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			return nil, 0, true
+		case math.IsInf(v, 1):
+			return nil, 1, false
+		case math.IsInf(v, -1):
+			return nil, -1, false
+		}
+
+		return new(big.Rat).SetFloat64(v), 0, false
+	case int32:
+		return new(big.Rat).SetInt64(int64(v)), 0, false
+	case int64:
+		return new(big.Rat).SetInt64(v), 0, false
+	case Decimal128:
+		switch {
+		case v.IsNaN():
+			return nil, 0, true
+		case v.IsInf(1):
+			return nil, 1, false
+		case v.IsInf(-1):
+			return nil, -1, false
+		}
+
+		return v.Rat(), 0, false
+	default:
+		panic(fmt.Sprintf("exactNumber: unexpected type %T", v))
+	}
+}
+
 // compareArrays compares indices of a filter array according to indices of a document array;
 // returns Equal when an array equals to filter array;
 // returns Less when an index of the document array is less than the index of the filter array;
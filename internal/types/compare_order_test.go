@@ -15,7 +15,9 @@
 package types
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -54,3 +56,78 @@ func TestCompareOrderForSort(t *testing.T) {
 		})
 	}
 }
+
+// TestCompareCanonicalOrder checks that values sorted in ascending BSON comparison order
+// compare consistently with each other.
+func TestCompareCanonicalOrder(t *testing.T) {
+	t.Parallel()
+
+	values := []any{
+		Null,
+		math.NaN(),
+		math.Inf(-1),
+		must.NotFail(ParseDecimal128("-1E+400")),
+		int64(math.MinInt64),
+		int32(math.MinInt32),
+		-1.5,
+		must.NotFail(ParseDecimal128("-1.25")),
+		int32(0),
+		0.5,
+		must.NotFail(ParseDecimal128("0.75")),
+		int64(1),
+		float64(1 << 53),
+		must.NotFail(ParseDecimal128("9007199254740992.5")),
+		int64(1<<53 + 1),
+		int64(math.MaxInt64),
+		must.NotFail(ParseDecimal128("1E+400")),
+		math.Inf(1),
+		"",
+		"A",
+		"a",
+		"ab",
+		"b",
+		must.NotFail(NewDocument()),
+		must.NotFail(NewDocument("a", Null)),
+		must.NotFail(NewDocument("a", int32(1))),
+		must.NotFail(NewDocument("a", int32(1), "b", int32(1))),
+		must.NotFail(NewDocument("b", int32(1))),
+		must.NotFail(NewDocument("a", "x")),
+		must.NotFail(NewArray()),
+		must.NotFail(NewArray(Null)),
+		must.NotFail(NewArray(int32(1))),
+		must.NotFail(NewArray(int32(1), int32(1))),
+		must.NotFail(NewArray(int32(2))),
+		must.NotFail(NewArray("a")),
+		Binary{Subtype: BinaryGeneric, B: []byte{}},
+		Binary{Subtype: BinaryGeneric, B: []byte{1}},
+		Binary{Subtype: BinaryUser, B: []byte{0}},
+		Binary{Subtype: BinaryGeneric, B: []byte{1, 2}},
+		ObjectID{},
+		ObjectID{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+		false,
+		true,
+		time.UnixMilli(-1).UTC(),
+		time.UnixMilli(0).UTC(),
+		Timestamp(0),
+		Timestamp(1),
+		Regex{Pattern: "a"},
+		Regex{Pattern: "a", Options: "i"},
+		Regex{Pattern: "b"},
+	}
+
+	for i, a := range values {
+		for j, b := range values {
+			expected := Equal
+
+			switch {
+			case i < j:
+				expected = Less
+			case i > j:
+				expected = Greater
+			}
+
+			require.Equal(t, expected, CompareForAggregation(a, b), "CompareForAggregation(%v, %v)", a, b)
+			require.Equal(t, expected, CompareOrder(a, b, Ascending), "CompareOrder(%v, %v)", a, b)
+		}
+	}
+}
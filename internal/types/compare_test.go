@@ -15,6 +15,7 @@
 package types
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -105,6 +106,41 @@ func TestCompare(t *testing.T) {
 			b:        must.NotFail(NewDocument("foo", "baz")),
 			expected: Less,
 		},
+		"DecimalEqualInt": {
+			a:        must.NotFail(ParseDecimal128("1.00")),
+			b:        int32(1),
+			expected: Equal,
+		},
+		"DecimalEqualDecimal": {
+			a:        must.NotFail(ParseDecimal128("1.5E+3")),
+			b:        must.NotFail(ParseDecimal128("1500.0")),
+			expected: Equal,
+		},
+		"DecimalCompareDouble": {
+			a:        must.NotFail(ParseDecimal128("0.1")),
+			b:        float64(0.1),
+			expected: Less,
+		},
+		"LongCompareDecimal": {
+			a:        int64(9007199254740993),
+			b:        must.NotFail(ParseDecimal128("9007199254740992")),
+			expected: Greater,
+		},
+		"DecimalNaNCompareDoubleNaN": {
+			a:        NewDecimal128NaN(),
+			b:        math.NaN(),
+			expected: Equal,
+		},
+		"DecimalNaNCompareNumber": {
+			a:        NewDecimal128NaN(),
+			b:        math.Inf(-1),
+			expected: Less,
+		},
+		"DecimalInfinityCompareDouble": {
+			a:        NewDecimal128Inf(1),
+			b:        math.MaxFloat64,
+			expected: Greater,
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -0,0 +1,486 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal128 represents BSON type Decimal128:
+// IEEE 754-2008 128-bit decimal floating point number in the binary integer decimal (BID) encoding.
+//
+// Values with different encodings could be numerically equal (for example, 1.0 and 1.00),
+// so they should be compared with [Compare], not with == operator.
+type Decimal128 struct {
+	L uint64 // low 64 bits
+	H uint64 // high 64 bits
+}
+
+const (
+	// Decimal128MaxDigits is the maximum number of significant digits of Decimal128 value.
+	Decimal128MaxDigits = 34
+
+	// Decimal128MinExponent is the minimal exponent of Decimal128 value.
+	Decimal128MinExponent = -6176
+
+	// Decimal128MaxExponent is the maximal exponent of Decimal128 value.
+	Decimal128MaxExponent = 6111
+
+	decimal128SignMask    = uint64(1) << 63
+	decimal128SpecialMask = uint64(0x1f) << 58
+	decimal128InfBits     = uint64(0x1e) << 58
+	decimal128NaNBits     = uint64(0x1f) << 58
+)
+
+var (
+	// decimal128MaxCoefficient is the maximal coefficient of Decimal128 value (34 nines).
+	decimal128MaxCoefficient = new(big.Int).Sub(pow10(Decimal128MaxDigits), big.NewInt(1))
+
+	// bigMask64 is used to get the low 64 bits of big.Int.
+	bigMask64 = new(big.Int).SetUint64(math.MaxUint64)
+)
+
+// NewDecimal128 returns Decimal128 value (-1)^neg × coefficient × 10^exponent for the given non-negative coefficient.
+//
+// Like MongoDB, it rounds the coefficient to 34 significant digits using round half to even mode,
+// rounds values with too small exponents to zero, and returns infinity for too large values.
+func NewDecimal128(neg bool, coefficient *big.Int, exponent int) Decimal128 {
+	if coefficient.Sign() < 0 {
+		panic(fmt.Sprintf("types.NewDecimal128: negative coefficient %s", coefficient))
+	}
+
+	c := new(big.Int).Set(coefficient)
+
+	if extra := decimalDigits(c) - Decimal128MaxDigits; extra > 0 {
+		c = roundHalfEven(c, extra)
+		exponent += extra
+
+		// rounding up could add one more digit
+		if c.Cmp(decimal128MaxCoefficient) > 0 {
+			c.Quo(c, big.NewInt(10))
+			exponent++
+		}
+	}
+
+	if exponent < Decimal128MinExponent {
+		c = roundHalfEven(c, Decimal128MinExponent-exponent)
+		exponent = Decimal128MinExponent
+	}
+
+	if exponent > Decimal128MaxExponent {
+		extra := exponent - Decimal128MaxExponent
+
+		switch {
+		case c.Sign() == 0:
+			// zero just uses the largest exponent
+		case decimalDigits(c)+extra <= Decimal128MaxDigits:
+			// add trailing zeros to the coefficient to make the exponent fit
+			c.Mul(c, pow10(extra))
+		default:
+			if neg {
+				return NewDecimal128Inf(-1)
+			}
+
+			return NewDecimal128Inf(1)
+		}
+
+		exponent = Decimal128MaxExponent
+	}
+
+	res := Decimal128{
+		L: new(big.Int).And(c, bigMask64).Uint64(),
+		H: new(big.Int).Rsh(c, 64).Uint64() | uint64(exponent-Decimal128MinExponent)<<49,
+	}
+
+	if neg {
+		res.H |= decimal128SignMask
+	}
+
+	return res
+}
+
+// NewDecimal128NaN returns Decimal128 NaN value.
+func NewDecimal128NaN() Decimal128 {
+	return Decimal128{H: decimal128NaNBits}
+}
+
+// NewDecimal128Inf returns positive infinity if sign >= 0, negative infinity if sign < 0.
+func NewDecimal128Inf(sign int) Decimal128 {
+	if sign < 0 {
+		return Decimal128{H: decimal128SignMask | decimal128InfBits}
+	}
+
+	return Decimal128{H: decimal128InfBits}
+}
+
+// NewDecimal128FromInt64 returns Decimal128 value for the given integer with zero exponent.
+func NewDecimal128FromInt64(v int64) Decimal128 {
+	c := big.NewInt(v)
+	return NewDecimal128(v < 0, c.Abs(c), 0)
+}
+
+// NewDecimal128FromFloat64 returns Decimal128 value for the given double.
+//
+// Like MongoDB, it rounds non-zero finite values to 15 significant digits keeping trailing zeros,
+// so 0.1 becomes 0.100000000000000.
+func NewDecimal128FromFloat64(v float64) Decimal128 {
+	switch {
+	case math.IsNaN(v):
+		return NewDecimal128NaN()
+	case math.IsInf(v, 1):
+		return NewDecimal128Inf(1)
+	case math.IsInf(v, -1):
+		return NewDecimal128Inf(-1)
+	case v == 0:
+		return NewDecimal128(math.Signbit(v), new(big.Int), 0)
+	}
+
+	// d.dddddddddddddde±dd
+	mantissa, exp, _ := strings.Cut(strconv.FormatFloat(math.Abs(v), 'e', 14, 64), "e")
+
+	c, _ := new(big.Int).SetString(strings.Replace(mantissa, ".", "", 1), 10)
+	e, _ := strconv.Atoi(exp)
+
+	return NewDecimal128(v < 0, c, e-14)
+}
+
+// ParseDecimal128 parses the string representation of Decimal128 value.
+//
+// It accepts decimal numbers with optional sign, fractional part, and exponent,
+// as well as NaN, Inf, and Infinity (case-insensitive, with optional sign).
+// Numbers with more than 34 significant digits are rounded.
+func ParseDecimal128(s string) (Decimal128, error) {
+	var res Decimal128
+
+	rest := s
+	neg := strings.HasPrefix(rest, "-")
+
+	if neg || strings.HasPrefix(rest, "+") {
+		rest = rest[1:]
+	}
+
+	switch {
+	case strings.EqualFold(rest, "nan"):
+		return NewDecimal128NaN(), nil
+	case strings.EqualFold(rest, "inf"), strings.EqualFold(rest, "infinity"):
+		if neg {
+			return NewDecimal128Inf(-1), nil
+		}
+
+		return NewDecimal128Inf(1), nil
+	}
+
+	var exponent int
+
+	if i := strings.IndexAny(rest, "eE"); i >= 0 {
+		var err error
+		if exponent, err = strconv.Atoi(rest[i+1:]); err != nil {
+			return res, fmt.Errorf("types.ParseDecimal128: invalid exponent in %q", s)
+		}
+
+		rest = rest[:i]
+	}
+
+	integer, fraction, _ := strings.Cut(rest, ".")
+	digits := integer + fraction
+
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return res, fmt.Errorf("types.ParseDecimal128: invalid value %q", s)
+	}
+
+	c, _ := new(big.Int).SetString(digits, 10)
+
+	return NewDecimal128(neg, c, exponent-len(fraction)), nil
+}
+
+// IsNaN returns true if d is NaN.
+func (d Decimal128) IsNaN() bool {
+	return d.H&decimal128SpecialMask == decimal128NaNBits
+}
+
+// IsInf returns true if d is an infinity, according to sign, like [math.IsInf].
+// If sign > 0, IsInf reports whether d is positive infinity.
+// If sign < 0, IsInf reports whether d is negative infinity.
+// If sign == 0, IsInf reports whether d is either infinity.
+func (d Decimal128) IsInf(sign int) bool {
+	if d.H&decimal128SpecialMask != decimal128InfBits {
+		return false
+	}
+
+	return sign == 0 || (sign > 0) == !d.Signbit()
+}
+
+// Signbit returns true if d is negative or negative zero.
+func (d Decimal128) Signbit() bool {
+	return d.H&decimal128SignMask != 0
+}
+
+// Abs returns d without the sign.
+func (d Decimal128) Abs() Decimal128 {
+	d.H &^= decimal128SignMask
+	return d
+}
+
+// Neg returns d with the opposite sign.
+func (d Decimal128) Neg() Decimal128 {
+	d.H ^= decimal128SignMask
+	return d
+}
+
+// Add returns the sum of d and v rounded to 34 significant digits like MongoDB does.
+//
+// The exponent of the exact result is the smaller of both exponents, so 1.50 + 1 is 2.50.
+// The sum of infinities with different signs is NaN.
+func (d Decimal128) Add(v Decimal128) Decimal128 {
+	switch {
+	case d.IsNaN() || v.IsNaN():
+		return NewDecimal128NaN()
+	case d.IsInf(0) && v.IsInf(0):
+		if d.Signbit() != v.Signbit() {
+			return NewDecimal128NaN()
+		}
+
+		return d
+	case d.IsInf(0):
+		return d
+	case v.IsInf(0):
+		return v
+	}
+
+	dNeg, dC, dE := d.Parts()
+	vNeg, vC, vE := v.Parts()
+
+	e := min(dE, vE)
+	dC.Mul(dC, pow10(dE-e))
+	vC.Mul(vC, pow10(vE-e))
+
+	if dNeg {
+		dC.Neg(dC)
+	}
+
+	if vNeg {
+		vC.Neg(vC)
+	}
+
+	c := dC.Add(dC, vC)
+
+	// zero sum is negative only if both values are negative
+	neg := c.Sign() < 0 || (c.Sign() == 0 && dNeg && vNeg)
+
+	return NewDecimal128(neg, c.Abs(c), e)
+}
+
+// Mul returns the product of d and v rounded to 34 significant digits like MongoDB does.
+//
+// The exponent of the exact result is the sum of both exponents, so 1.5 × 1.5 is 2.25.
+// The product of infinity and zero is NaN.
+func (d Decimal128) Mul(v Decimal128) Decimal128 {
+	neg := d.Signbit() != v.Signbit()
+
+	switch {
+	case d.IsNaN() || v.IsNaN():
+		return NewDecimal128NaN()
+	case d.IsInf(0) || v.IsInf(0):
+		if d.IsZero() || v.IsZero() {
+			return NewDecimal128NaN()
+		}
+
+		if neg {
+			return NewDecimal128Inf(-1)
+		}
+
+		return NewDecimal128Inf(1)
+	}
+
+	_, dC, dE := d.Parts()
+	_, vC, vE := v.Parts()
+
+	return NewDecimal128(neg, dC.Mul(dC, vC), dE+vE)
+}
+
+// Parts returns the sign, the non-negative coefficient, and the exponent of finite Decimal128 value:
+// it is equal to (-1)^neg × coefficient × 10^exponent.
+//
+// It panics for NaN and infinity values.
+func (d Decimal128) Parts() (neg bool, coefficient *big.Int, exponent int) {
+	if d.IsNaN() || d.IsInf(0) {
+		panic(fmt.Sprintf("types.Decimal128.Parts: %s", d))
+	}
+
+	neg = d.Signbit()
+	coefficient = new(big.Int)
+
+	// Two bits after the sign set mean the other encoding form that is used for coefficients
+	// larger than the maximal one; such non-canonical values are zeros.
+	if d.H>>61&3 == 3 {
+		exponent = int(d.H>>47&0x3fff) + Decimal128MinExponent
+		return
+	}
+
+	exponent = int(d.H>>49&0x3fff) + Decimal128MinExponent
+
+	coefficient.SetUint64(d.H & (1<<49 - 1))
+	coefficient.Lsh(coefficient, 64)
+	coefficient.Or(coefficient, new(big.Int).SetUint64(d.L))
+
+	if coefficient.Cmp(decimal128MaxCoefficient) > 0 {
+		coefficient.SetInt64(0)
+	}
+
+	return
+}
+
+// IsZero returns true if d is positive or negative zero with any exponent.
+func (d Decimal128) IsZero() bool {
+	if d.IsNaN() || d.IsInf(0) {
+		return false
+	}
+
+	_, c, _ := d.Parts()
+
+	return c.Sign() == 0
+}
+
+// Float64 returns the double value nearest to d.
+func (d Decimal128) Float64() float64 {
+	switch {
+	case d.IsNaN():
+		return math.NaN()
+	case d.IsInf(1):
+		return math.Inf(1)
+	case d.IsInf(-1):
+		return math.Inf(-1)
+	}
+
+	neg, c, e := d.Parts()
+
+	// out of range values are returned as infinities or zeros
+	f, _ := strconv.ParseFloat(c.String()+"e"+strconv.Itoa(e), 64)
+
+	if neg {
+		return math.Copysign(f, -1)
+	}
+
+	return f
+}
+
+// Rat returns the exact value of finite Decimal128 value.
+//
+// It panics for NaN and infinity values.
+func (d Decimal128) Rat() *big.Rat {
+	neg, c, e := d.Parts()
+
+	res := new(big.Rat).SetInt(c)
+
+	if e >= 0 {
+		res.Mul(res, new(big.Rat).SetInt(pow10(e)))
+	} else {
+		res.Quo(res, new(big.Rat).SetInt(pow10(-e)))
+	}
+
+	if neg {
+		res.Neg(res)
+	}
+
+	return res
+}
+
+// String returns the string representation of Decimal128 value in the same format as MongoDB
+// (IEEE 754-2008 to-scientific-string conversion), for example, "1.5", "1.50", "1.5E+3", "-0", "NaN", or "Infinity".
+func (d Decimal128) String() string {
+	switch {
+	case d.IsNaN():
+		return "NaN"
+	case d.IsInf(1):
+		return "Infinity"
+	case d.IsInf(-1):
+		return "-Infinity"
+	}
+
+	neg, c, e := d.Parts()
+
+	digits := c.String()
+	adjusted := e + len(digits) - 1
+
+	var res string
+
+	switch {
+	case e == 0:
+		res = digits
+
+	case e < 0 && adjusted >= -6:
+		if point := len(digits) + e; point > 0 {
+			res = digits[:point] + "." + digits[point:]
+		} else {
+			res = "0." + strings.Repeat("0", -point) + digits
+		}
+
+	default:
+		res = digits[:1]
+		if len(digits) > 1 {
+			res += "." + digits[1:]
+		}
+
+		res += "E"
+		if adjusted >= 0 {
+			res += "+"
+		}
+
+		res += strconv.Itoa(adjusted)
+	}
+
+	if neg {
+		res = "-" + res
+	}
+
+	return res
+}
+
+// pow10 returns 10^n for non-negative n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// decimalDigits returns the number of decimal digits of non-negative v.
+func decimalDigits(v *big.Int) int {
+	return len(v.String())
+}
+
+// roundHalfEven returns non-negative v divided by 10^n and rounded using round half to even mode.
+func roundHalfEven(v *big.Int, n int) *big.Int {
+	d := pow10(n)
+
+	q, r := new(big.Int).QuoRem(v, d, new(big.Int))
+
+	switch r.Lsh(r, 1).Cmp(d) {
+	case 1:
+		q.Add(q, big.NewInt(1))
+	case 0:
+		if q.Bit(0) == 1 {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+
+	return q
+}
+
+// check interfaces
+var (
+	_ fmt.Stringer = Decimal128{}
+)
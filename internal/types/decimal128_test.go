@@ -0,0 +1,175 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestDecimal128(t *testing.T) {
+	t.Parallel()
+
+	// encodings are taken from BSON corpus tests
+	for name, tc := range map[string]struct {
+		s string
+		d Decimal128
+	}{
+		"Zero":        {s: "0", d: Decimal128{H: 0x3040000000000000}},
+		"NegZero":     {s: "-0", d: Decimal128{H: 0xb040000000000000}},
+		"ZeroExp":     {s: "0E+3", d: Decimal128{H: 0x3046000000000000}},
+		"One":         {s: "1", d: Decimal128{H: 0x3040000000000000, L: 1}},
+		"NegOne":      {s: "-1", d: Decimal128{H: 0xb040000000000000, L: 1}},
+		"Fraction":    {s: "0.1", d: Decimal128{H: 0x303e000000000000, L: 1}},
+		"Trailing":    {s: "1.50", d: Decimal128{H: 0x303c000000000000, L: 150}},
+		"Small":       {s: "0.001234", d: Decimal128{H: 0x3034000000000000, L: 1234}},
+		"Scientific":  {s: "1.234E-7", d: Decimal128{H: 0x302c000000000000, L: 1234}},
+		"PositiveExp": {s: "1.5E+3", d: Decimal128{H: 0x3044000000000000, L: 15}},
+		"Tiny":        {s: "1E-6176", d: Decimal128{L: 1}},
+		"Largest": {
+			s: "9.999999999999999999999999999999999E+6144",
+			d: Decimal128{H: 0x5fffed09bead87c0, L: 0x378d8e63ffffffff},
+		},
+		"NaN":    {s: "NaN", d: Decimal128{H: 0x7c00000000000000}},
+		"Inf":    {s: "Infinity", d: Decimal128{H: 0x7800000000000000}},
+		"NegInf": {s: "-Infinity", d: Decimal128{H: 0xf800000000000000}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.s, tc.d.String())
+
+			d, err := ParseDecimal128(tc.s)
+			require.NoError(t, err)
+			assert.Equal(t, tc.d, d)
+		})
+	}
+}
+
+func TestDecimal128Parse(t *testing.T) {
+	t.Parallel()
+
+	for s, expected := range map[string]string{
+		"1500":                                "1500",
+		"+1.5e3":                              "1.5E+3",
+		"-.5":                                 "-0.5",
+		"5.":                                  "5",
+		"inf":                                 "Infinity",
+		"-INF":                                "-Infinity",
+		"12345678901234567890123456789012345": "1.234567890123456789012345678901234E+34", // round half to even
+		"12345678901234567890123456789012355": "1.234567890123456789012345678901236E+34",
+		"99999999999999999999999999999999995": "1.000000000000000000000000000000000E+35",
+		"1E+6144":                             "1.000000000000000000000000000000000E+6144", // clamped
+		"1E+6145":                             "Infinity",
+		"1E+6200":                             "Infinity",
+		"1E-6177":                             "0E-6176",
+		"15E-6177":                            "2E-6176",
+	} {
+		t.Run(s, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := ParseDecimal128(s)
+			require.NoError(t, err)
+			assert.Equal(t, expected, d.String())
+		})
+	}
+
+	for _, s := range []string{"", "-", ".", "1.2.3", "1e", "1e1.5", "abc", "0x10"} {
+		_, err := ParseDecimal128(s)
+		assert.Error(t, err, "%q", s)
+	}
+}
+
+func TestDecimal128Conversions(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "42", NewDecimal128FromInt64(42).String())
+	assert.Equal(t, "-9223372036854775808", NewDecimal128FromInt64(math.MinInt64).String())
+
+	assert.Equal(t, "0.100000000000000", NewDecimal128FromFloat64(0.1).String())
+	assert.Equal(t, "1000.55000000000", NewDecimal128FromFloat64(1000.55).String())
+	assert.Equal(t, "-1.00000000000000E+100", NewDecimal128FromFloat64(-1e100).String())
+	assert.Equal(t, "0", NewDecimal128FromFloat64(0).String())
+	assert.Equal(t, "-0", NewDecimal128FromFloat64(math.Copysign(0, -1)).String())
+	assert.True(t, NewDecimal128FromFloat64(math.NaN()).IsNaN())
+	assert.True(t, NewDecimal128FromFloat64(math.Inf(-1)).IsInf(-1))
+
+	assert.Equal(t, 0.1, must.NotFail(ParseDecimal128("0.1")).Float64())
+	assert.Equal(t, -1.5e300, must.NotFail(ParseDecimal128("-1.5E+300")).Float64())
+	assert.Equal(t, math.Inf(1), must.NotFail(ParseDecimal128("1E+400")).Float64())
+	assert.True(t, math.Signbit(must.NotFail(ParseDecimal128("-0")).Float64()))
+
+	assert.Equal(t, big.NewRat(3, 2), must.NotFail(ParseDecimal128("1.50")).Rat())
+	assert.Equal(t, big.NewRat(-1500, 1), must.NotFail(ParseDecimal128("-1.5E+3")).Rat())
+
+	assert.Equal(t, "1.5", must.NotFail(ParseDecimal128("-1.5")).Abs().String())
+	assert.True(t, must.NotFail(ParseDecimal128("-0E+3")).IsZero())
+	assert.False(t, NewDecimal128NaN().IsZero())
+}
+
+func TestDecimal128Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		a, b     string
+		sum, mul string
+	}{
+		"Simple":       {a: "1.50", b: "1", sum: "2.50", mul: "1.50"},
+		"Fraction":     {a: "0.1", b: "0.2", sum: "0.3", mul: "0.02"},
+		"Negative":     {a: "-2.5", b: "1", sum: "-1.5", mul: "-2.5"},
+		"Zero":         {a: "0E+3", b: "0.00", sum: "0.00", mul: "0E+1"},
+		"NegativeZero": {a: "-0", b: "-0", sum: "-0", mul: "0"},
+		"Cancel":       {a: "-1.5", b: "1.5", sum: "0.0", mul: "-2.25"},
+		"Exponents":    {a: "1E+3", b: "1E-3", sum: "1000.001", mul: "1"},
+		"Rounded": {
+			a:   "9999999999999999999999999999999999",
+			b:   "1",
+			sum: "1.000000000000000000000000000000000E+34",
+			mul: "9999999999999999999999999999999999",
+		},
+		"Overflow": {
+			a:   "9.999999999999999999999999999999999E+6144",
+			b:   "9.999999999999999999999999999999999E+6144",
+			sum: "Infinity",
+			mul: "Infinity",
+		},
+		"NaN":       {a: "NaN", b: "1", sum: "NaN", mul: "NaN"},
+		"Inf":       {a: "Infinity", b: "-2", sum: "Infinity", mul: "-Infinity"},
+		"InfZero":   {a: "-Infinity", b: "0", sum: "-Infinity", mul: "NaN"},
+		"InfInf":    {a: "Infinity", b: "-Infinity", sum: "NaN", mul: "-Infinity"},
+		"InfSame":   {a: "Infinity", b: "Infinity", sum: "Infinity", mul: "Infinity"},
+		"Underflow": {a: "1E-6176", b: "0.5", sum: "0.5000000000000000000000000000000000", mul: "0E-6176"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a := must.NotFail(ParseDecimal128(tc.a))
+			b := must.NotFail(ParseDecimal128(tc.b))
+
+			assert.Equal(t, tc.sum, a.Add(b).String())
+			assert.Equal(t, tc.sum, b.Add(a).String())
+			assert.Equal(t, tc.mul, a.Mul(b).String())
+			assert.Equal(t, tc.mul, b.Mul(a).String())
+		})
+	}
+
+	assert.Equal(t, "-1.5", must.NotFail(ParseDecimal128("1.5")).Neg().String())
+}
@@ -382,54 +382,81 @@ func (d *Document) GetByPath(path Path) (any, error) {
 // SetByPath sets value by given path. If the Path has only one element, it sets the value for the given key.
 // If some parts of the path are missing, they will be created.
 // The Document type will be used to create these parts.
+// If the array index is out of range, the gap is filled with null values.
+//
+// If the path goes through a scalar value or uses an element that is not a non-negative index
+// to access an array, *PathError with ErrPathCannotCreateField code is returned,
+// and the document is not modified.
 // If multiple fields match the path it panics.
 func (d *Document) SetByPath(path Path, value any) error {
 	assertType(value)
 	d.checkFrozen()
 
-	if path.Len() == 1 {
-		d.Set(path.Slice()[0], value)
-		return nil
-	}
+	elems := path.Slice()
 
-	if !d.HasByPath(path.TrimSuffix()) {
-		// we should insert the missing part of the path
-		if err := insertByPath(d, path); err != nil {
-			return err
-		}
-	}
+	var parentKey string
+	var next any = d
 
-	innerComp := must.NotFail(d.GetByPath(path.TrimSuffix()))
+	for i, elem := range elems {
+		last := i == len(elems)-1
 
-	switch inner := innerComp.(type) {
-	case *Document:
-		inner.Set(path.Suffix(), value)
-		return nil
-	case *Array:
-		index, err := strconv.Atoi(path.Suffix())
-		if err != nil {
-			return fmt.Errorf(
-				"Cannot create field '%s' in element {%s: %s}",
-				path.Suffix(),
-				path.Slice()[len(path.Slice())-2],
-				FormatAnyValue(innerComp),
-			)
-		}
+		switch comp := next.(type) {
+		case *Document:
+			if last {
+				comp.Set(elem, value)
+				return nil
+			}
+
+			next, _ = comp.Get(elem)
+			if next == nil {
+				next = must.NotFail(NewDocument())
+				comp.Set(elem, next)
+			}
+
+		case *Array:
+			index, err := strconv.Atoi(elem)
+			if err != nil || index < 0 {
+				return cannotCreateFieldError(elem, parentKey, comp)
+			}
 
-		// In case if value is set in the middle of the array, we should fill the gap with Null
-		for i := inner.Len(); i <= index; i++ {
-			inner.Append(Null)
+			if index < comp.Len() {
+				if last {
+					return comp.Set(index, value)
+				}
+
+				next = must.NotFail(comp.Get(index))
+
+				break
+			}
+
+			for j := comp.Len(); j < index; j++ {
+				comp.Append(Null)
+			}
+
+			if last {
+				comp.Append(value)
+				return nil
+			}
+
+			next = must.NotFail(NewDocument())
+			comp.Append(next)
+
+		default:
+			return cannotCreateFieldError(elem, parentKey, comp)
 		}
 
-		return inner.Set(index, value)
-	default:
-		return fmt.Errorf(
-			"Cannot create field '%s' in element {%s: %s}",
-			path.Suffix(),
-			path.Prefix(),
-			FormatAnyValue(must.NotFail(d.Get(path.Prefix()))),
-		)
+		parentKey = elem
 	}
+
+	panic("not reached")
+}
+
+// cannotCreateFieldError returns *PathError for a field that can't be created in the given parent value.
+func cannotCreateFieldError(key, parentKey string, parent any) error {
+	return newPathError(
+		ErrPathCannotCreateField,
+		fmt.Errorf("Cannot create field '%s' in element {%s: %s}", key, parentKey, FormatAnyValue(parent)),
+	)
 }
 
 // RemoveByPath removes document by path, doing nothing if the key does not exist.
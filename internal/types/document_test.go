@@ -15,6 +15,7 @@
 package types
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -257,6 +258,54 @@ func TestDocument(t *testing.T) {
 					"v", must.NotFail(NewArray("a", Null, "bar")),
 				)),
 			},
+			{
+				name: "set array element",
+				document: must.NotFail(NewDocument(
+					"v", must.NotFail(NewArray("a", must.NotFail(NewDocument("foo", "bar")))),
+				)),
+				key:   "v.1.foo",
+				value: "baz",
+				expected: must.NotFail(NewDocument(
+					"v", must.NotFail(NewArray("a", must.NotFail(NewDocument("foo", "baz")))),
+				)),
+			},
+			{
+				name:     "array negative index",
+				document: must.NotFail(NewDocument("v", must.NotFail(NewArray("a")))),
+				key:      "v.-1",
+				value:    "bar",
+				err: newPathError(
+					ErrPathCannotCreateField,
+					errors.New(`Cannot create field '-1' in element {v: [ "a" ]}`),
+				),
+			},
+			{
+				name:     "array field",
+				document: must.NotFail(NewDocument("v", must.NotFail(NewArray("a")))),
+				key:      "v.foo.bar",
+				value:    "bar",
+				err: newPathError(
+					ErrPathCannotCreateField,
+					errors.New(`Cannot create field 'foo' in element {v: [ "a" ]}`),
+				),
+			},
+			{
+				name:     "scalar in array",
+				document: must.NotFail(NewDocument("v", must.NotFail(NewArray("a")))),
+				key:      "v.0.foo",
+				value:    "bar",
+				err: newPathError(
+					ErrPathCannotCreateField,
+					errors.New(`Cannot create field 'foo' in element {0: "a"}`),
+				),
+			},
+			{
+				name:     "document negative key",
+				document: must.NotFail(NewDocument("v", must.NotFail(NewDocument()))),
+				key:      "v.-1",
+				value:    "bar",
+				expected: must.NotFail(NewDocument("v", must.NotFail(NewDocument("-1", "bar")))),
+			},
 		} {
 			tc := tc
 			t.Run(tc.name, func(t *testing.T) {
@@ -65,7 +65,7 @@ func (e *ValidationError) Code() ValidationErrorCode {
 
 // ValidateData checks if the document represents a valid "data document".
 // It places `_id` field into the fields slice 0 index.
-// It replaces negative zero -0 with valid positive zero 0.
+// It replaces negative zero -0 with valid positive zero 0 (keeping the exponent of Decimal128 values).
 // If the document is not valid it returns *ValidationError.
 func (d *Document) ValidateData() error {
 	return d.validateData(true)
@@ -147,6 +147,14 @@ func (d *Document) validateData(isTopLevel bool) error {
 					if item == 0 && math.Signbit(item) {
 						must.NoError(value.Set(i, math.Copysign(0, +1)))
 					}
+				case Decimal128:
+					if err := validateDecimal128(key, item); err != nil {
+						return err
+					}
+
+					if item.IsZero() && item.Signbit() {
+						must.NoError(value.Set(i, item.Abs()))
+					}
 				}
 			}
 		case float64:
@@ -159,6 +167,14 @@ func (d *Document) validateData(isTopLevel bool) error {
 			if value == 0 && math.Signbit(value) {
 				d.Set(key, math.Copysign(0, +1))
 			}
+		case Decimal128:
+			if err := validateDecimal128(key, value); err != nil {
+				return err
+			}
+
+			if value.IsZero() && value.Signbit() {
+				d.Set(key, value.Abs())
+			}
 		case Regex:
 			if isTopLevel && key == "_id" {
 				return newValidationError(ErrWrongIDType, fmt.Errorf("The '_id' value cannot be of type regex"))
@@ -172,3 +188,19 @@ func (d *Document) validateData(isTopLevel bool) error {
 
 	return nil
 }
+
+// validateDecimal128 returns *ValidationError if Decimal128 value can't be stored.
+func validateDecimal128(key string, value Decimal128) error {
+	switch {
+	case value.IsInf(0):
+		return newValidationError(
+			ErrValidation, fmt.Errorf("invalid value: { %q: %s } (infinity values are not allowed)", key, value),
+		)
+	case value.IsNaN():
+		return newValidationError(
+			ErrValidation, fmt.Errorf("invalid value: { %q: %s } (NaN values are not allowed)", key, value),
+		)
+	}
+
+	return nil
+}
@@ -67,6 +67,18 @@ func TestDocumentValidateData(t *testing.T) {
 				doc:    must.NotFail(NewDocument("v", math.Inf(-1))),
 				reason: errors.New(`invalid value: { "v": -Inf } (infinity values are not allowed)`),
 			},
+			"DecimalInfinity": {
+				doc:    must.NotFail(NewDocument("v", NewDecimal128Inf(1))),
+				reason: errors.New(`invalid value: { "v": Infinity } (infinity values are not allowed)`),
+			},
+			"DecimalNaN": {
+				doc:    must.NotFail(NewDocument("v", NewDecimal128NaN())),
+				reason: errors.New(`invalid value: { "v": NaN } (NaN values are not allowed)`),
+			},
+			"ArrayDecimalNegativeInfinity": {
+				doc:    must.NotFail(NewDocument("v", must.NotFail(NewArray(NewDecimal128Inf(-1))))),
+				reason: errors.New(`invalid value: { "v": -Infinity } (infinity values are not allowed)`),
+			},
 
 			"NoID": {
 				doc:    must.NotFail(NewDocument("foo", "bar")),
@@ -181,4 +193,22 @@ func TestDocumentValidateData(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("DecimalNegativeZero", func(t *testing.T) {
+		t.Parallel()
+
+		negativeZero := must.NotFail(ParseDecimal128("-0.00"))
+
+		doc := must.NotFail(NewDocument(
+			"_id", "1",
+			"foo", negativeZero,
+			"bar", must.NotFail(NewArray(negativeZero)),
+		))
+
+		require.NoError(t, doc.ValidateData())
+
+		expected := must.NotFail(ParseDecimal128("0.00"))
+		assert.Equal(t, expected, must.NotFail(doc.GetByPath(NewStaticPath("foo"))))
+		assert.Equal(t, expected, must.NotFail(doc.GetByPath(NewStaticPath("bar", "0"))))
+	})
 }
@@ -171,6 +171,13 @@ func Identical(a, b any) bool {
 			return false
 		}
 
+		return a == b
+	case Decimal128:
+		b, ok := b.(Decimal128)
+		if !ok {
+			return false
+		}
+
 		return a == b
 	}
 
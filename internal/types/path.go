@@ -317,76 +317,3 @@ func removeByPath(v any, path Path) {
 		// no such path: scalar value
 	}
 }
-
-// insertByPath inserts missing parts of the path into Document.
-func insertByPath(doc *Document, path Path) error {
-	var next any = doc
-
-	var insertedPath Path
-	for _, pathElem := range path.TrimSuffix().Slice() {
-		insertedPath = insertedPath.Append(pathElem)
-
-		v, err := doc.GetByPath(insertedPath)
-		if err != nil {
-			suffix := len(insertedPath.Slice()) - 1
-			if suffix < 0 {
-				panic("invalid path")
-			}
-
-			switch v := next.(type) {
-			case *Document:
-				v.Set(insertedPath.Slice()[suffix], must.NotFail(NewDocument()))
-
-			case *Array:
-				ind, err := strconv.Atoi(insertedPath.Slice()[suffix])
-				if err != nil {
-					return newPathError(
-						ErrPathCannotCreateField,
-						fmt.Errorf(
-							"Cannot create field '%s' in element {%s: %s}",
-							pathElem,
-							insertedPath.Slice()[suffix-1],
-							FormatAnyValue(v),
-						),
-					)
-				}
-
-				if ind < 0 {
-					return newPathError(
-						ErrPathIndexOutOfBound,
-						fmt.Errorf(
-							"Index out of bound: %d",
-							ind,
-						),
-					)
-				}
-
-				// If path needs to be reserved in the middle of the array, we should fill the gap with Null
-				for j := v.Len(); j < ind; j++ {
-					v.Append(Null)
-				}
-
-				v.Append(must.NotFail(NewDocument()))
-
-			default:
-				return newPathError(
-					ErrPathCannotCreateField,
-					fmt.Errorf(
-						"Cannot create field '%s' in element {%s: %s}",
-						pathElem,
-						path.Prefix(),
-						FormatAnyValue(must.NotFail(doc.Get(path.Prefix()))),
-					),
-				)
-			}
-
-			next = must.NotFail(doc.GetByPath(insertedPath)).(*Document)
-
-			continue
-		}
-
-		next = v
-	}
-
-	return nil
-}
@@ -23,21 +23,59 @@ import (
 // Timestamp represents BSON type Timestamp.
 type Timestamp uint64
 
-// timestampCounter is a process-wide timestamp counter.
-var timestampCounter atomic.Uint32
+// lastTimestamp is the last value of the process-wide logical clock returned by [NextTimestamp].
+var lastTimestamp atomic.Uint64
 
 // NewTimestamp returns the timestamp for the given time and counter values.
 func NewTimestamp(t time.Time, c uint32) Timestamp {
 	return Timestamp((uint64(t.Unix()) << 32) | uint64(c))
 }
 
-// NextTimestamp returns the next timestamp for the given time value.
+// NextTimestamp returns the next value of the process-wide logical clock for the given time value.
+//
+// The clock is used for cluster time, oplog entries, and `$currentDate` timestamps,
+// and returned values are strictly increasing.
+// The counter starts from 1 for each new second.
+// If the given time is not after the time of the previous value
+// (for example, for several calls within the same second or when the wall clock goes backwards),
+// the counter of the previous value is incremented instead.
 func NextTimestamp(t time.Time) Timestamp {
-	// Technically, that should be a counter within a second, not a process-wide,
-	// but that's good enough for us.
-	c := timestampCounter.Add(1)
+	next := uint64(NewTimestamp(t, 1))
+
+	for {
+		last := lastTimestamp.Load()
+
+		res := next
+		if res <= last {
+			res = last + 1
+		}
+
+		if lastTimestamp.CompareAndSwap(last, res) {
+			return Timestamp(res)
+		}
+	}
+}
+
+// LastTimestamp returns the last value of the process-wide logical clock.
+func LastTimestamp() Timestamp {
+	return Timestamp(lastTimestamp.Load())
+}
+
+// AdvanceTimestamp advances the process-wide logical clock to the given value.
+//
+// It returns true if the clock was advanced,
+// and false if the last value of the clock is already equal or greater than the given value.
+func AdvanceTimestamp(ts Timestamp) bool {
+	for {
+		last := lastTimestamp.Load()
+		if uint64(ts) <= last {
+			return false
+		}
 
-	return NewTimestamp(t, c)
+		if lastTimestamp.CompareAndSwap(last, uint64(ts)) {
+			return true
+		}
+	}
 }
 
 // Time returns timestamp's time component.
@@ -21,12 +21,12 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-//nolint:paralleltest // we modify the global timestampCounter
+//nolint:paralleltest // we modify the global lastTimestamp
 func TestNextTimestamp(t *testing.T) {
 	t.Run("UnixZero", func(t *testing.T) {
 		d := time.Unix(0, 0).UTC()
 
-		timestampCounter.Store(0)
+		lastTimestamp.Store(0)
 		assert.Equal(t, Timestamp(1), NextTimestamp(d))
 		assert.Equal(t, Timestamp(2), NextTimestamp(d))
 
@@ -36,15 +36,50 @@ func TestNextTimestamp(t *testing.T) {
 	t.Run("Normal", func(t *testing.T) {
 		d := time.Date(2023, time.September, 12, 59, 44, 42, 0, time.UTC)
 
-		timestampCounter.Store(0)
+		lastTimestamp.Store(0)
 		assert.Equal(t, Timestamp(7278646209986691073), NextTimestamp(d))
 		assert.Equal(t, Timestamp(7278646209986691074), NextTimestamp(d))
 
 		assert.Equal(t, d, NextTimestamp(d).Time())
 	})
+
+	t.Run("NextSecond", func(t *testing.T) {
+		d := time.Unix(42, 0).UTC()
+
+		lastTimestamp.Store(0)
+		assert.Equal(t, NewTimestamp(d, 1), NextTimestamp(d))
+		assert.Equal(t, NewTimestamp(d, 2), NextTimestamp(d))
+		assert.Equal(t, NewTimestamp(d.Add(time.Second), 1), NextTimestamp(d.Add(time.Second)))
+	})
+
+	t.Run("ClockBackwards", func(t *testing.T) {
+		d := time.Date(2023, time.September, 12, 59, 44, 42, 0, time.UTC)
+
+		lastTimestamp.Store(0)
+		assert.Equal(t, NewTimestamp(d, 1), NextTimestamp(d))
+		assert.Equal(t, NewTimestamp(d, 2), NextTimestamp(d.Add(-time.Minute)))
+		assert.Equal(t, NewTimestamp(d, 3), NextTimestamp(d))
+	})
+
+	t.Run("Advance", func(t *testing.T) {
+		d := time.Date(2023, time.September, 12, 59, 44, 42, 0, time.UTC)
+
+		lastTimestamp.Store(0)
+		assert.Equal(t, NewTimestamp(d, 1), NextTimestamp(d))
+
+		assert.True(t, AdvanceTimestamp(NewTimestamp(d.Add(time.Minute), 5)))
+		assert.Equal(t, NewTimestamp(d.Add(time.Minute), 5), LastTimestamp())
+		assert.Equal(t, NewTimestamp(d.Add(time.Minute), 6), NextTimestamp(d))
+
+		assert.False(t, AdvanceTimestamp(NewTimestamp(d, 42)))
+		assert.Equal(t, NewTimestamp(d.Add(time.Minute), 6), LastTimestamp())
+	})
 }
 
+//nolint:paralleltest // we modify the global lastTimestamp
 func TestNextTimestampSigned(t *testing.T) {
+	lastTimestamp.Store(0)
+
 	// one second before Y2K38
 	now := time.Date(2038, time.January, 19, 3, 14, 6, 0, time.UTC)
 
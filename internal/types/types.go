@@ -47,6 +47,7 @@
 //	int        int32            32-bit integer
 //	timestamp  types.Timestamp  Timestamp
 //	long       int64            64-bit integer
+//	decimal    types.Decimal128 128-bit decimal floating point
 package types
 
 import (
@@ -64,7 +65,7 @@ const MaxSafeDouble = float64(1<<53 - 1) // 52bit mantissa max value = 900719925
 
 // ScalarType represents scalar type.
 type ScalarType interface {
-	float64 | string | Binary | ObjectID | bool | time.Time | NullType | Regex | int32 | Timestamp | int64
+	float64 | string | Binary | ObjectID | bool | time.Time | NullType | Regex | int32 | Timestamp | int64 | Decimal128
 }
 
 // CompositeType represents composite type - *Document or *Array.
@@ -99,7 +100,7 @@ func assertType(value any) {
 	switch value := value.(type) {
 	case *Document, *Array:
 		return
-	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64:
+	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64, Decimal128:
 		return
 	case nil:
 		panic("types: unexpected nil type")
@@ -115,7 +116,7 @@ func isScalar(value any) bool {
 	assertType(value)
 
 	switch value.(type) {
-	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64:
+	case float64, string, Binary, ObjectID, bool, time.Time, NullType, Regex, int32, Timestamp, int64, Decimal128:
 		return true
 	}
 
@@ -176,6 +177,8 @@ func deepCopy(value any) any {
 		return value
 	case int64:
 		return value
+	case Decimal128:
+		return value
 
 	default:
 		panic(fmt.Sprintf("types.deepCopy: unexpected type %[1]T (%#[1]v)", value))
@@ -19,6 +19,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	_ "expvar" // for metrics
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"net"
 	"net/http"
 	_ "net/http/pprof" // for profiling
+	"runtime"
 	"slices"
 	"sync/atomic"
 	"text/template"
@@ -38,6 +40,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"golang.org/x/exp/maps"
 
+	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
@@ -78,6 +81,7 @@ type ListenOpts struct {
 	R       prometheus.Registerer
 	Livez   Probe
 	Readyz  Probe
+	Config  map[string]string // sanitized configuration for the archive; may be nil
 }
 
 // addToZip adds a new file to the zip archive.
@@ -103,6 +107,33 @@ func addToZip(w *zip.Writer, name string, r io.ReadCloser) (err error) {
 	return
 }
 
+// recentLogs returns recent log entries as JSON lines.
+func recentLogs() ([]byte, error) {
+	var b bytes.Buffer
+	if err := logging.RecentEntries.WriteJSON(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// versionInfo returns build and runtime information as JSON.
+func versionInfo() ([]byte, error) {
+	info := version.Get()
+
+	return json.MarshalIndent(map[string]any{
+		"version":    info.Version,
+		"commit":     info.Commit,
+		"branch":     info.Branch,
+		"dirty":      info.Dirty,
+		"package":    info.Package,
+		"debugBuild": info.DebugBuild,
+		"go":         runtime.Version(),
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+	}, "", "  ")
+}
+
 // Listen creates a new debug handler and starts listener on the given TCP address.
 //
 // This function can be called only once because it affects [http.DefaultServeMux].
@@ -176,6 +207,7 @@ func Listen(opts *ListenOpts) (*Handler, error) {
 			query string
 		}{
 			"goroutine.pprof": {path: "/debug/pprof/goroutine"},
+			"goroutines.txt":  {path: "/debug/pprof/goroutine", query: "debug=2"},
 			"heap.pprof":      {path: "/debug/pprof/heap", query: "gc=1"},
 			"profile.pprof":   {path: "/debug/pprof/profile", query: "seconds=5"},
 			"metrics.txt":     {path: "/debug/metrics"}, // includes version, UUID, etc
@@ -193,6 +225,19 @@ func Listen(opts *ListenOpts) (*Handler, error) {
 
 			errs[file] = err
 		}
+
+		for file, f := range map[string]func() ([]byte, error){
+			"config.json":  func() ([]byte, error) { return json.MarshalIndent(opts.Config, "", "  ") },
+			"logs.jsonl":   recentLogs,
+			"version.json": versionInfo,
+		} {
+			b, err := f()
+			if err == nil {
+				err = addToZip(zipWriter, file, io.NopCloser(bytes.NewReader(b)))
+			}
+
+			errs[file] = err
+		}
 	})
 
 	svOpts := []statsviz.Option{
@@ -52,6 +52,7 @@ func TestDebug(t *testing.T) {
 		R:       prometheus.NewRegistry(),
 		Livez:   func(context.Context) bool { return livez.Load() },
 		Readyz:  func(context.Context) bool { return readyz.Load() },
+		Config:  map[string]string{"debug-addr": "127.0.0.1:0"},
 	}))
 
 	ctx, cancel := context.WithCancel(testutil.Ctx(t))
@@ -85,10 +86,14 @@ func TestDebug(t *testing.T) {
 
 		expectedFiles := map[string]struct{}{
 			"goroutine.pprof": {},
+			"goroutines.txt":  {},
 			"heap.pprof":      {},
 			"profile.pprof":   {},
 			"metrics.txt":     {},
 			"vars.json":       {},
+			"config.json":     {},
+			"logs.jsonl":      {},
+			"version.json":    {},
 			"errors.txt":      {},
 		}
 
@@ -27,6 +27,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/resource"
+	"github.com/FerretDB/FerretDB/internal/util/sqlguard"
 )
 
 // DB wraps [*database/sql.DB] with tracing, metrics, logging, and resource tracking.
@@ -39,14 +40,16 @@ type DB struct {
 	sqlDB     *sql.DB
 	l         *slog.Logger
 	token     *resource.Token
+	dialect   sqlguard.Dialect
 	BatchSize int
 }
 
 // WrapDB creates a new DB.
 //
 // Name is used for metric label values, etc.
+// Dialect is used to check queries with [sqlguard.Assert].
 // Logger (that will be named) is used for query logging.
-func WrapDB(db *sql.DB, name string, l *slog.Logger) *DB {
+func WrapDB(db *sql.DB, name string, dialect sqlguard.Dialect, l *slog.Logger) *DB {
 	if db == nil {
 		return nil
 	}
@@ -56,6 +59,7 @@ func WrapDB(db *sql.DB, name string, l *slog.Logger) *DB {
 		sqlDB:            db,
 		l:                logging.WithName(l, name),
 		token:            resource.NewToken(),
+		dialect:          dialect,
 	}
 
 	resource.Track(res, res.token)
@@ -76,6 +80,8 @@ func (db *DB) Ping(ctx context.Context) error {
 
 // QueryContext calls [*sql.DB.QueryContext].
 func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	sqlguard.Assert(db.dialect, query, args)
+
 	start := time.Now()
 
 	fields := []any{slog.Any("args", args)}
@@ -91,6 +97,8 @@ func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*Row
 
 // QueryRowContext calls [*sql.DB.QueryRowContext].
 func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	sqlguard.Assert(db.dialect, query, args)
+
 	start := time.Now()
 
 	fields := []any{slog.Any("args", args)}
@@ -106,6 +114,8 @@ func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *s
 
 // ExecContext calls [*sql.DB.ExecContext].
 func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	sqlguard.Assert(db.dialect, query, args)
+
 	start := time.Now()
 
 	fields := []any{slog.Any("args", args)}
@@ -124,6 +134,19 @@ func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.R
 	return res, err
 }
 
+// BeginTx calls [*sql.DB.BeginTx].
+//
+// The caller is responsible for committing or rolling back the returned transaction.
+// Prefer [DB.InTransaction] when the transaction should not outlive the function call.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	sqlTx, err := db.sqlDB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return wrapTx(sqlTx, db.dialect, db.l), nil
+}
+
 // InTransaction wraps the given function f in a transaction.
 //
 // If f returns an error or context is canceled, the transaction is rolled back.
@@ -135,7 +158,7 @@ func (db *DB) InTransaction(ctx context.Context, f func(*Tx) error) (err error)
 		return
 	}
 
-	tx := wrapTx(sqlTx, db.l)
+	tx := wrapTx(sqlTx, db.dialect, db.l)
 
 	var done bool
 
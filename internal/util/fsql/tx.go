@@ -23,27 +23,30 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/resource"
+	"github.com/FerretDB/FerretDB/internal/util/sqlguard"
 )
 
 // Tx wraps [*database/sql.Tx] with resource tracking.
 //
 // It exposes the subset of *sql.Tx methods we use.
 type Tx struct {
-	sqlTx *sql.Tx
-	l     *slog.Logger
-	token *resource.Token
+	sqlTx   *sql.Tx
+	l       *slog.Logger
+	token   *resource.Token
+	dialect sqlguard.Dialect
 }
 
 // wrapTx creates new Tx.
-func wrapTx(tx *sql.Tx, l *slog.Logger) *Tx {
+func wrapTx(tx *sql.Tx, dialect sqlguard.Dialect, l *slog.Logger) *Tx {
 	if tx == nil {
 		return nil
 	}
 
 	res := &Tx{
-		sqlTx: tx,
-		l:     l,
-		token: resource.NewToken(),
+		sqlTx:   tx,
+		l:       l,
+		token:   resource.NewToken(),
+		dialect: dialect,
 	}
 
 	resource.Track(res, res.token)
@@ -65,6 +68,8 @@ func (tx *Tx) Rollback() error {
 
 // QueryContext calls [*sql.Tx.QueryContext].
 func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	sqlguard.Assert(tx.dialect, query, args)
+
 	start := time.Now()
 
 	fields := []any{slog.Any("args", args)}
@@ -80,6 +85,8 @@ func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*Row
 
 // QueryRowContext calls [*sql.Tx.QueryRowContext].
 func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	sqlguard.Assert(tx.dialect, query, args)
+
 	start := time.Now()
 
 	fields := []any{slog.Any("args", args)}
@@ -95,6 +102,8 @@ func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *s
 
 // ExecContext calls [*sql.Tx.ExecContext].
 func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	sqlguard.Assert(tx.dialect, query, args)
+
 	start := time.Now()
 
 	fields := []any{slog.Any("args", args)}
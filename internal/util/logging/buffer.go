@@ -15,8 +15,10 @@
 package logging
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"sync"
 	"time"
@@ -100,3 +102,16 @@ func (cb *circularBuffer) GetArray() (*wirebson.Array, error) {
 
 	return res, nil
 }
+
+// WriteJSON writes entries from circularBuffer to w as JSON lines.
+func (cb *circularBuffer) WriteJSON(w io.Writer) error {
+	h := slog.NewJSONHandler(w, nil)
+
+	for _, r := range cb.get() {
+		if err := h.Handle(context.Background(), *r); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	return nil
+}
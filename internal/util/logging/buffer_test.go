@@ -15,12 +15,14 @@
 package logging
 
 import (
+	"bytes"
 	"context"
 	"log/slog"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCircularBufferHandler(t *testing.T) {
@@ -96,3 +98,22 @@ func TestCircularBufferHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestCircularBufferWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	cb := NewCircularBuffer(2)
+
+	for _, msg := range []string{"message 1", "message 2", "message 3"} {
+		r := slog.NewRecord(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), slog.LevelWarn, msg, 0)
+		r.AddAttrs(slog.String("name", "test"))
+		cb.add(&r)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cb.WriteJSON(&buf))
+
+	expected := `{"time":"2024-01-02T03:04:05Z","level":"WARN","msg":"message 2","name":"test"}` + "\n" +
+		`{"time":"2024-01-02T03:04:05Z","level":"WARN","msg":"message 3","name":"test"}` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// file is a store for objects in the local directory.
+type file struct {
+	dir string
+}
+
+// newFile returns a new store for the given directory.
+//
+// The directory is created on the first write if it does not exist.
+func newFile(dir string) *file {
+	return &file{
+		dir: dir,
+	}
+}
+
+// Create implements Store interface.
+//
+// Data is written to a temporary file that replaces the object on close,
+// so readers never see partially written objects.
+func (f *file) Create(ctx context.Context, name string) (Writer, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(f.dir, filepath.FromSlash(name))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o777); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &fileWriter{
+		File: tmp,
+		path: path,
+	}, nil
+}
+
+// Open implements Store interface.
+func (f *file) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+
+	r, err := os.Open(filepath.Join(f.dir, filepath.FromSlash(name)))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, lazyerrors.Errorf("%w: %q", ErrNotExist, name)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	return r, nil
+}
+
+// fileWriter writes the object to a temporary file and renames it on close.
+type fileWriter struct {
+	*os.File
+	path string
+}
+
+// Close implements io.Closer interface.
+func (fw *fileWriter) Close() error {
+	err := fw.File.Sync()
+
+	if closeErr := fw.File.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(fw.File.Name(), fw.path)
+	}
+
+	if err != nil {
+		_ = os.Remove(fw.File.Name())
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// Abort implements Writer interface.
+func (fw *fileWriter) Abort() {
+	_ = fw.File.Close()
+	_ = os.Remove(fw.File.Name())
+}
+
+// check interfaces
+var (
+	_ Store  = (*file)(nil)
+	_ Writer = (*fileWriter)(nil)
+)
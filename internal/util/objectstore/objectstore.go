@@ -0,0 +1,115 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package objectstore provides access to named objects stored in a local directory
+// or in an S3-compatible object storage.
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/url"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// Store represents a storage of named objects.
+//
+// Object names are slash-separated relative paths without `.` and `..` elements.
+type Store interface {
+	// Create creates a new object or replaces an existing one.
+	// The object is stored only when the returned writer is closed without errors.
+	Create(ctx context.Context, name string) (Writer, error)
+
+	// Open opens an existing object for reading.
+	// If the object does not exist, the returned error wraps ErrNotExist.
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// Writer writes a single object.
+type Writer interface {
+	io.WriteCloser
+
+	// Abort discards written data without storing the object.
+	Abort()
+}
+
+var (
+	// ErrNotExist indicates that the object does not exist.
+	ErrNotExist = errors.New("object does not exist")
+
+	// ErrInvalidName indicates that the object name is not valid.
+	ErrInvalidName = errors.New("invalid object name")
+)
+
+// Opts represents optional store settings.
+type Opts struct {
+	// ServerSideEncryption is the S3 server-side encryption algorithm
+	// (`AES256`, `aws:kms`, or `aws:kms:dsse`) used for created objects.
+	ServerSideEncryption string
+
+	// KMSKeyID is the AWS KMS key ID for `aws:kms` and `aws:kms:dsse` encryption.
+	// If empty, the default key is used.
+	KMSKeyID string
+}
+
+// New returns a store for the given URL.
+//
+// Supported URLs are `file:///path/to/directory` for a local directory
+// and `s3://bucket/prefix` for an S3-compatible object storage; see [NewS3] for details.
+// Opts may be nil.
+func New(uri string, opts *Opts) (Store, error) {
+	if opts == nil {
+		opts = new(Opts)
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, lazyerrors.Errorf("unexpected host %q in %q", u.Host, uri)
+		}
+
+		if u.Path == "" {
+			return nil, lazyerrors.Errorf("empty directory path in %q", uri)
+		}
+
+		if opts.ServerSideEncryption != "" {
+			return nil, lazyerrors.New("server-side encryption is supported only for S3")
+		}
+
+		return newFile(u.Path), nil
+
+	case "s3":
+		return NewS3(u, opts)
+
+	default:
+		return nil, lazyerrors.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+}
+
+// checkName returns an error wrapping ErrInvalidName if the object name is not valid.
+func checkName(name string) error {
+	if name == "." || !fs.ValidPath(name) {
+		return lazyerrors.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,366 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// s3Credentials represents AWS credentials.
+type s3Credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// s3 is a store for objects in the S3-compatible object storage.
+type s3 struct {
+	client   *http.Client
+	endpoint *url.URL
+	bucket   string
+	prefix   string
+	region   string
+	creds    s3Credentials
+	sse      string
+	kmsKeyID string
+
+	// pathStyle is true if bucket is a part of the path instead of the host name.
+	pathStyle bool
+}
+
+// NewS3 returns a new store for the given `s3://bucket/prefix` URL.
+//
+// Credentials are taken from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// and (optional) AWS_SESSION_TOKEN environment variables.
+// The region is taken from AWS_REGION or AWS_DEFAULT_REGION (us-east-1 by default).
+// AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL could be used to set the endpoint of S3-compatible storage
+// (for example, MinIO); path-style requests are used in that case.
+// Opts may be nil.
+func NewS3(u *url.URL, opts *Opts) (Store, error) {
+	if opts == nil {
+		opts = new(Opts)
+	}
+
+	if u.Host == "" {
+		return nil, lazyerrors.Errorf("empty bucket name in %q", u.String())
+	}
+
+	switch opts.ServerSideEncryption {
+	case "", "AES256":
+		if opts.KMSKeyID != "" {
+			return nil, lazyerrors.New("KMS key ID requires aws:kms or aws:kms:dsse server-side encryption")
+		}
+	case "aws:kms", "aws:kms:dsse":
+	default:
+		return nil, lazyerrors.Errorf("unsupported server-side encryption %q", opts.ServerSideEncryption)
+	}
+
+	s := &s3{
+		client: http.DefaultClient,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		region: cmp.Or(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+		creds: s3Credentials{
+			accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		},
+		sse:      opts.ServerSideEncryption,
+		kmsKeyID: opts.KMSKeyID,
+	}
+
+	if s.creds.accessKeyID == "" || s.creds.secretAccessKey == "" {
+		return nil, lazyerrors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY should be set")
+	}
+
+	endpoint := cmp.Or(os.Getenv("AWS_ENDPOINT_URL_S3"), os.Getenv("AWS_ENDPOINT_URL"))
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.region)
+	} else {
+		s.pathStyle = true
+	}
+
+	var err error
+	if s.endpoint, err = url.Parse(endpoint); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if s.endpoint.Scheme != "http" && s.endpoint.Scheme != "https" {
+		return nil, lazyerrors.Errorf("unexpected S3 endpoint %q", endpoint)
+	}
+
+	return s, nil
+}
+
+// objectURL returns the URL of the object with the given name.
+func (s *s3) objectURL(name string) *url.URL {
+	u := *s.endpoint
+
+	key := path.Join(s.prefix, name)
+
+	if s.pathStyle {
+		u.Path = path.Join("/", u.Path, s.bucket, key)
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+
+	u.RawPath = uriEncode(u.Path)
+
+	return &u
+}
+
+// do signs and sends the request, and checks the response status code.
+//
+// If the object does not exist, the returned error wraps ErrNotExist.
+func (s *s3) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.sign(req, payloadHash, time.Now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if res.StatusCode == http.StatusOK {
+		return res, nil
+	}
+
+	defer res.Body.Close() //nolint:errcheck // we are only reading it
+
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, lazyerrors.Errorf("%w: %s", ErrNotExist, req.URL.Path)
+	}
+
+	return nil, lazyerrors.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, res.Status, b)
+}
+
+// Create implements Store interface.
+//
+// Data is buffered in a temporary file that is uploaded on close.
+func (s *s3) Create(ctx context.Context, name string) (Writer, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "ferretdb-s3-*")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &s3Writer{
+		ctx:  ctx,
+		s:    s,
+		u:    s.objectURL(name),
+		tmp:  tmp,
+		hash: sha256.New(),
+	}, nil
+}
+
+// Open implements Store interface.
+func (s *s3) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(name).String(), nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+// s3Writer buffers the object in a temporary file and uploads it on close.
+type s3Writer struct {
+	ctx  context.Context
+	s    *s3
+	u    *url.URL
+	tmp  *os.File
+	hash hash.Hash
+	size int64
+}
+
+// Write implements io.Writer interface.
+func (w *s3Writer) Write(p []byte) (int, error) {
+	n, err := w.tmp.Write(p)
+	w.hash.Write(p[:n])
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Close implements io.Closer interface.
+func (w *s3Writer) Close() error {
+	defer w.Abort()
+
+	if _, err := w.tmp.Seek(0, io.SeekStart); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPut, w.u.String(), io.NopCloser(w.tmp))
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	req.ContentLength = w.size
+
+	if w.s.sse != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption", w.s.sse)
+	}
+
+	if w.s.kmsKeyID != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", w.s.kmsKeyID)
+	}
+
+	res, err := w.s.do(req, hex.EncodeToString(w.hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+
+	_ = res.Body.Close()
+
+	return nil
+}
+
+// Abort implements Writer interface.
+func (w *s3Writer) Abort() {
+	_ = w.tmp.Close()
+	_ = os.Remove(w.tmp.Name())
+}
+
+// emptyPayloadHash is a SHA-256 hash of the empty payload.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds AWS Signature Version 4 headers to the request without query parameters.
+//
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html.
+func (s *s3) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if s.creds.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.creds.sessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+
+	for k, vs := range req.Header {
+		k = strings.ToLower(k)
+		if k == "range" || k == "content-type" || strings.HasPrefix(k, "x-amz-") {
+			headers[k] = strings.TrimSpace(strings.Join(vs, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+
+	slices.Sort(names)
+
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path),
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.creds.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.creds.accessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// hmacSHA256 returns HMAC-SHA256 of data with the given key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// uriEncode encodes the path as required by AWS Signature Version 4:
+// all bytes except unreserved characters and slashes are percent-encoded.
+func uriEncode(s string) string {
+	var res strings.Builder
+
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			res.WriteByte(b)
+		default:
+			fmt.Fprintf(&res, "%%%02X", b)
+		}
+	}
+
+	return res.String()
+}
+
+// check interfaces
+var (
+	_ Store  = (*s3)(nil)
+	_ Writer = (*s3Writer)(nil)
+)
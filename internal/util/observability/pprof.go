@@ -0,0 +1,180 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+)
+
+// ProfilePusher periodically collects CPU and heap profiles and pushes them
+// to the continuous profiling server using Pyroscope HTTP ingestion API.
+type ProfilePusher struct {
+	l        *slog.Logger
+	c        *http.Client
+	u        *url.URL
+	name     string
+	interval time.Duration
+}
+
+// ProfilePusherOpts represents [ProfilePusher] options.
+type ProfilePusherOpts struct {
+	Logger *slog.Logger
+
+	Service  string
+	Version  string
+	URL      string
+	Interval time.Duration
+}
+
+// NewProfilePusher creates a new [ProfilePusher].
+func NewProfilePusher(opts *ProfilePusherOpts) (*ProfilePusher, error) {
+	if opts.URL == "" {
+		return nil, errors.New("URL is required")
+	}
+
+	if opts.Interval <= 0 {
+		return nil, errors.New("interval should be positive")
+	}
+
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile push URL: %w", err)
+	}
+
+	u = u.JoinPath("ingest")
+
+	opts.Logger.Info("Starting profile pusher...", slog.String("url", opts.URL))
+
+	return &ProfilePusher{
+		l:        opts.Logger,
+		c:        http.DefaultClient,
+		u:        u,
+		name:     fmt.Sprintf("%s{version=%s}", opts.Service, opts.Version),
+		interval: opts.Interval,
+	}, nil
+}
+
+// Run collects and pushes profiles until ctx is canceled.
+//
+// CPU profile is collected during the whole interval;
+// heap profile is collected at the end of it.
+func (pp *ProfilePusher) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		from := time.Now()
+
+		var cpu bytes.Buffer
+
+		// CPU profiling could be already started by the debug handler
+		cpuErr := pprof.StartCPUProfile(&cpu)
+		if cpuErr != nil {
+			pp.l.WarnContext(ctx, "Failed to start CPU profile", logging.Error(cpuErr))
+		}
+
+		ctxutil.Sleep(ctx, pp.interval)
+
+		if cpuErr == nil {
+			pprof.StopCPUProfile()
+		}
+
+		until := time.Now()
+
+		// push the last profiles even if ctx is canceled
+		pushCtx, pushCancel := ctxutil.WithDelay(ctx)
+
+		if cpuErr == nil {
+			if err := pp.push(pushCtx, "cpu", &cpu, from, until); err != nil {
+				pp.l.WarnContext(ctx, "Failed to push CPU profile", logging.Error(err))
+			}
+		}
+
+		var heap bytes.Buffer
+
+		if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+			pp.l.WarnContext(ctx, "Failed to collect heap profile", logging.Error(err))
+		} else if err = pp.push(pushCtx, "heap", &heap, from, until); err != nil {
+			pp.l.WarnContext(ctx, "Failed to push heap profile", logging.Error(err))
+		}
+
+		pushCancel(nil)
+	}
+
+	pp.l.InfoContext(ctx, "Profile pusher stopped")
+}
+
+// push sends a single profile in pprof format.
+func (pp *ProfilePusher) push(ctx context.Context, typ string, profile io.Reader, from, until time.Time) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	fw, err := w.CreateFormFile("profile", typ+".pprof")
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(fw, profile); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	u := *pp.u
+	q := u.Query()
+	q.Set("name", pp.name)
+	q.Set("from", strconv.FormatInt(from.Unix(), 10))
+	q.Set("until", strconv.FormatInt(until.Unix(), 10))
+	q.Set("format", "pprof")
+	q.Set("spyName", "gospy")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	pp.l.DebugContext(ctx, "Pushing profile", slog.String("type", typ), slog.String("url", u.Redacted()))
+
+	res, err := pp.c.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close() //nolint:errcheck // we are only reading it
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("%s %s: unexpected status %d", req.Method, u.Redacted(), res.StatusCode)
+	}
+
+	return nil
+}
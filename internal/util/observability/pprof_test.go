@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestProfilePusher(t *testing.T) {
+	ctx, cancel := context.WithCancel(testutil.Ctx(t))
+	defer cancel()
+
+	var m sync.Mutex
+	received := map[string]int{}
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/prefix/ingest", r.URL.Path)
+
+		q := r.URL.Query()
+		assert.Equal(t, "ferretdb{version=v0.0.1}", q.Get("name"))
+		assert.Equal(t, "pprof", q.Get("format"))
+		assert.NotEmpty(t, q.Get("from"))
+		assert.NotEmpty(t, q.Get("until"))
+
+		f, h, err := r.FormFile("profile")
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		b, err := io.ReadAll(f)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, b)
+
+		m.Lock()
+		received[h.Filename]++
+		m.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(s.Close)
+
+	pp, err := NewProfilePusher(&ProfilePusherOpts{
+		Logger:   testutil.Logger(t),
+		Service:  "ferretdb",
+		Version:  "v0.0.1",
+		URL:      s.URL + "/prefix",
+		Interval: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+
+	go func() {
+		pp.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		m.Lock()
+		defer m.Unlock()
+
+		return received["cpu.pprof"] > 0 && received["heap.pprof"] > 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	cancel()
+	<-done
+}
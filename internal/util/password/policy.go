@@ -0,0 +1,77 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Policy represents password requirements checked on user creation and update.
+type Policy struct {
+	// MinLength is the minimal number of characters; 0 means no limit.
+	MinLength int
+
+	// RequireComplexity requires characters from at least three of four classes:
+	// lowercase letters, uppercase letters, digits, and other characters.
+	RequireComplexity bool
+}
+
+// Check returns an error describing why the given password does not satisfy the policy.
+func (p *Policy) Check(password Password) error {
+	s := password.Password()
+
+	if l := utf8.RuneCountInString(s); l < p.MinLength {
+		return fmt.Errorf("password must be at least %d characters long, got %d", p.MinLength, l)
+	}
+
+	if !p.RequireComplexity {
+		return nil
+	}
+
+	var lower, upper, digit, other bool
+
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	var classes int
+
+	for _, b := range []bool{lower, upper, digit, other} {
+		if b {
+			classes++
+		}
+	}
+
+	if classes < 3 {
+		return errors.New(
+			"password must contain characters from at least three of the following classes: " +
+				"lowercase letters, uppercase letters, digits, and other characters",
+		)
+	}
+
+	return nil
+}
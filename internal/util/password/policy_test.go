@@ -0,0 +1,69 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		policy   Policy
+		password string
+		err      bool
+	}{
+		"Empty": {
+			password: "a",
+		},
+		"MinLength": {
+			policy:   Policy{MinLength: 8},
+			password: "1234567",
+			err:      true,
+		},
+		"MinLengthRunes": {
+			policy:   Policy{MinLength: 4},
+			password: "пароль",
+		},
+		"Complexity": {
+			policy:   Policy{RequireComplexity: true},
+			password: "Password1",
+		},
+		"ComplexityOther": {
+			policy:   Policy{RequireComplexity: true},
+			password: "password-1",
+		},
+		"ComplexityFail": {
+			policy:   Policy{RequireComplexity: true},
+			password: "password1",
+			err:      true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.policy.Check(WrapPassword(tc.password))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
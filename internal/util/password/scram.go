@@ -40,6 +40,9 @@ func computeHash(h func() hash.Hash, b []byte) []byte {
 	return dh.Sum(nil)
 }
 
+// MinSCRAMIterationCount is the minimal allowed iteration count for SCRAM authentication.
+const MinSCRAMIterationCount = 5_000
+
 // scramParams represent password parameters for SCRAM authentication.
 type scramParams struct {
 	iterationCount int
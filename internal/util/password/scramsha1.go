@@ -26,18 +26,30 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
-// SCRAMSHA1VariationHash computes a variation of SCRAM-SHA-1 and returns
+// DefaultSCRAMSHA1IterationCount is the default iteration count for SCRAM-SHA-1 authentication.
+const DefaultSCRAMSHA1IterationCount = 10_000
+
+// SCRAMSHA1VariationHash computes a variation of SCRAM-SHA-1 with the given iteration count and returns
 // a document containing stored key, iteration count, salt, and server key.
 //
 // It does not conform to the SCRAM-SHA-1 standard due to the custom preparation
 // of the password.
-func SCRAMSHA1VariationHash(username string, password Password) (*wirebson.Document, error) {
-	salt := make([]byte, fixedScramSHA1Params.saltLen)
+func SCRAMSHA1VariationHash(username string, password Password, iterationCount int) (*wirebson.Document, error) {
+	if iterationCount < MinSCRAMIterationCount {
+		return nil, lazyerrors.Errorf("unexpected iteration count: %d", iterationCount)
+	}
+
+	params := &scramParams{
+		iterationCount: iterationCount,
+		saltLen:        scramSHA1SaltLen,
+	}
+
+	salt := make([]byte, params.saltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	doc, err := scramSHA1VariationHashParams(username, password, salt, fixedScramSHA1Params)
+	doc, err := scramSHA1VariationHashParams(username, password, salt, params)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -45,11 +57,8 @@ func SCRAMSHA1VariationHash(username string, password Password) (*wirebson.Docum
 	return doc, nil
 }
 
-// fixedScramSHA1Params represent fixed password parameters for SCRAM-SHA-1 authentication.
-var fixedScramSHA1Params = &scramParams{
-	iterationCount: 10_000,
-	saltLen:        16,
-}
+// scramSHA1SaltLen is the salt length for SCRAM-SHA-1 authentication.
+const scramSHA1SaltLen = 16
 
 // scramSHA1VariationHashParams hashes the password using custom preparation with the given salt and parameters,
 // and returns the document that should be stored using a variation of the SCRAM-SHA-1 algorithm
@@ -136,10 +136,10 @@ func TestSCRAMSHA1Variation(t *testing.T) {
 	t.Run("Exported", func(t *testing.T) {
 		t.Parallel()
 
-		doc1, err := SCRAMSHA1VariationHash("user", WrapPassword("password"))
+		doc1, err := SCRAMSHA1VariationHash("user", WrapPassword("password"), DefaultSCRAMSHA1IterationCount)
 		require.NoError(t, err)
 
-		doc2, err := SCRAMSHA1VariationHash("user", WrapPassword("password"))
+		doc2, err := SCRAMSHA1VariationHash("user", WrapPassword("password"), DefaultSCRAMSHA1IterationCount)
 		require.NoError(t, err)
 		require.NotEqual(t, doc1, doc2)
 
@@ -158,7 +158,7 @@ func BenchmarkSCRAMSHA1(b *testing.B) {
 		b.ReportAllocs()
 
 		for range b.N {
-			_, err = SCRAMSHA1VariationHash("user", WrapPassword("password"))
+			_, err = SCRAMSHA1VariationHash("user", WrapPassword("password"), DefaultSCRAMSHA1IterationCount)
 		}
 	})
 
@@ -26,15 +26,27 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
-// SCRAMSHA256Hash computes SCRAM-SHA-256 credentials and returns
+// DefaultSCRAMSHA256IterationCount is the default iteration count for SCRAM-SHA-256 authentication.
+const DefaultSCRAMSHA256IterationCount = 15_000
+
+// SCRAMSHA256Hash computes SCRAM-SHA-256 credentials with the given iteration count and returns
 // a document containing stored key, iteration count, salt, and server key.
-func SCRAMSHA256Hash(password Password) (*wirebson.Document, error) {
-	salt := make([]byte, fixedScramSHA256Params.saltLen)
+func SCRAMSHA256Hash(password Password, iterationCount int) (*wirebson.Document, error) {
+	if iterationCount < MinSCRAMIterationCount {
+		return nil, lazyerrors.Errorf("unexpected iteration count: %d", iterationCount)
+	}
+
+	params := &scramParams{
+		iterationCount: iterationCount,
+		saltLen:        scramSHA256SaltLen,
+	}
+
+	salt := make([]byte, params.saltLen)
 	if _, err := rand.Read(salt); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	doc, err := scramSHA256HashParams(password, salt, fixedScramSHA256Params)
+	doc, err := scramSHA256HashParams(password, salt, params)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -42,11 +54,8 @@ func SCRAMSHA256Hash(password Password) (*wirebson.Document, error) {
 	return doc, nil
 }
 
-// fixedScramSHA256Params represent fixed password parameters for SCRAM-SHA-256 authentication.
-var fixedScramSHA256Params = &scramParams{
-	iterationCount: 15_000,
-	saltLen:        28,
-}
+// scramSHA256SaltLen is the salt length for SCRAM-SHA-256 authentication.
+const scramSHA256SaltLen = 28
 
 // scramSHA256HashParams hashes the password with the given salt and parameters,
 // and returns the document that should be stored.
@@ -243,10 +243,10 @@ func TestSCRAMSHA256(t *testing.T) {
 	t.Run("Exported", func(t *testing.T) {
 		t.Parallel()
 
-		doc1, err := SCRAMSHA256Hash(WrapPassword("password"))
+		doc1, err := SCRAMSHA256Hash(WrapPassword("password"), DefaultSCRAMSHA256IterationCount)
 		require.NoError(t, err)
 
-		doc2, err := SCRAMSHA256Hash(WrapPassword("password"))
+		doc2, err := SCRAMSHA256Hash(WrapPassword("password"), DefaultSCRAMSHA256IterationCount)
 		require.NoError(t, err)
 		require.NotEqual(t, doc1, doc2)
 
@@ -256,6 +256,17 @@ func TestSCRAMSHA256(t *testing.T) {
 		salt = doc2.Get("salt").(string)
 		assert.Len(t, must.NotFail(base64.StdEncoding.DecodeString(salt)), 28)
 	})
+
+	t.Run("IterationCount", func(t *testing.T) {
+		t.Parallel()
+
+		doc, err := SCRAMSHA256Hash(WrapPassword("password"), MinSCRAMIterationCount)
+		require.NoError(t, err)
+		assert.Equal(t, int32(MinSCRAMIterationCount), doc.Get("iterationCount"))
+
+		_, err = SCRAMSHA256Hash(WrapPassword("password"), MinSCRAMIterationCount-1)
+		require.Error(t, err)
+	})
 }
 
 func BenchmarkSCRAMSHA256(b *testing.B) {
@@ -265,7 +276,7 @@ func BenchmarkSCRAMSHA256(b *testing.B) {
 		b.ReportAllocs()
 
 		for range b.N {
-			_, err = SCRAMSHA256Hash(WrapPassword("password"))
+			_, err = SCRAMSHA256Hash(WrapPassword("password"), DefaultSCRAMSHA256IterationCount)
 		}
 	})
 
@@ -0,0 +1,137 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secret provides loading of secrets such as passwords and URLs with credentials from files.
+//
+// Files are typically provided by Docker or Kubernetes secrets,
+// or by agents of external secret stores such as HashiCorp Vault or AWS Secrets Manager.
+package secret
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+)
+
+// ReadFile returns the secret stored in the given file.
+//
+// Trailing newlines are removed.
+func ReadFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// File represents a secret stored in a file that could be rotated.
+//
+//nolint:vet // for readability
+type File struct {
+	path string
+	l    *slog.Logger
+
+	rw       sync.RWMutex
+	value    string
+	onChange []func(string)
+}
+
+// NewFile reads the secret from the given file.
+func NewFile(path string, l *slog.Logger) (*File, error) {
+	value, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		path:  path,
+		l:     l,
+		value: value,
+	}, nil
+}
+
+// Value returns the current value of the secret.
+func (f *File) Value() string {
+	f.rw.RLock()
+	defer f.rw.RUnlock()
+
+	return f.value
+}
+
+// OnChange registers a function that is called with a new value when the secret is rotated.
+func (f *File) OnChange(fn func(value string)) {
+	f.rw.Lock()
+	defer f.rw.Unlock()
+
+	f.onChange = append(f.onChange, fn)
+}
+
+// Reload reads the file again and calls registered functions if the secret was changed.
+//
+// Empty or unreadable file does not change the value,
+// as it could be a temporary state of the rotation.
+func (f *File) Reload() (bool, error) {
+	value, err := ReadFile(f.path)
+	if err != nil {
+		return false, err
+	}
+
+	if value == "" {
+		return false, fmt.Errorf("secret file %q is empty", f.path)
+	}
+
+	f.rw.Lock()
+
+	if value == f.value {
+		f.rw.Unlock()
+		return false, nil
+	}
+
+	f.value = value
+	onChange := f.onChange
+
+	f.rw.Unlock()
+
+	// do not log the value
+	f.l.Info("Secret file changed", slog.String("path", f.path))
+
+	for _, fn := range onChange {
+		fn(value)
+	}
+
+	return true, nil
+}
+
+// Run reloads the file with the given interval until ctx is canceled.
+func (f *File) Run(ctx context.Context, interval time.Duration) {
+	for {
+		ctxutil.Sleep(ctx, interval)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if _, err := f.Reload(); err != nil {
+			f.l.WarnContext(ctx, "Failed to reload secret file", logging.Error(err))
+		}
+	}
+}
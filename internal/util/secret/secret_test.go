@@ -0,0 +1,71 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0o600))
+
+	v, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "first", v)
+
+	f, err := NewFile(path, testutil.Logger(t))
+	require.NoError(t, err)
+	assert.Equal(t, "first", f.Value())
+
+	var changed []string
+	f.OnChange(func(value string) { changed = append(changed, value) })
+
+	ok, err := f.Reload()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+	_, err = f.Reload()
+	require.Error(t, err)
+	assert.Equal(t, "first", f.Value())
+
+	require.NoError(t, os.WriteFile(path, []byte("second\r\n"), 0o600))
+
+	ok, err = f.Reload()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "second", f.Value())
+	assert.Equal(t, []string{"second"}, changed)
+
+	require.NoError(t, os.Remove(path))
+
+	_, err = f.Reload()
+	require.Error(t, err)
+	assert.Equal(t, "second", f.Value())
+
+	_, err = NewFile(path, testutil.Logger(t))
+	require.Error(t, err)
+}
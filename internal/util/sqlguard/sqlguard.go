@@ -0,0 +1,246 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlguard checks that SQL queries sent to backends are single statements
+// with all values passed as parameters.
+//
+// It does not parse SQL; instead, it tokenizes the query just enough to find
+// string literals, quoted identifiers, comments, statement separators, and placeholders.
+// Values interpolated into the query without proper quoting break that tokenization
+// (for example, by terminating a string literal or adding a statement),
+// and that is detected.
+package sqlguard
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/FerretDB/FerretDB/internal/util/debugbuild"
+)
+
+// Dialect represents SQL dialect of the backend.
+type Dialect int
+
+// Supported dialects.
+const (
+	_ Dialect = iota
+
+	// PostgreSQL uses $1, $2, etc. placeholders and nested block comments.
+	PostgreSQL
+
+	// SQLite uses ? and ?NNN placeholders.
+	SQLite
+
+	// MySQL uses ? placeholders and backslash escapes in string literals.
+	MySQL
+
+	// HANA uses ? placeholders.
+	HANA
+)
+
+// Assert panics if the query fails [Check] in debug builds.
+//
+// It does nothing in non-debug builds.
+func Assert(d Dialect, query string, args []any) {
+	if !debugbuild.Enabled {
+		return
+	}
+
+	if err := Check(d, query, args); err != nil {
+		panic(fmt.Sprintf("sqlguard: %v\nquery: %s", err, query))
+	}
+}
+
+// Check returns an error if the query is not a single well-formed statement,
+// contains line comments, dollar-quoted strings,
+// or if its placeholders do not match arguments one-to-one.
+func Check(d Dialect, query string, args []any) error {
+	var (
+		positional int          // the number of ? placeholders
+		numbered   map[int]bool // numbered placeholders
+		ended      bool         // true after statement separator
+	)
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+
+		if ended && !isSpace(c) {
+			return fmt.Errorf("multiple statements at offset %d", i)
+		}
+
+		switch {
+		case isSpace(c):
+			continue
+
+		case c == ';':
+			ended = true
+
+		case c == '\'':
+			// E'...' strings in PostgreSQL use backslash escapes like MySQL strings
+			backslash := d == MySQL || (d == PostgreSQL && i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i == 1 || !isIdent(query[i-2])))
+
+			end, err := skipQuoted(query, i, '\'', backslash)
+			if err != nil {
+				return err
+			}
+
+			i = end
+
+		case c == '"' || c == '`':
+			end, err := skipQuoted(query, i, c, false)
+			if err != nil {
+				return err
+			}
+
+			i = end
+
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			return fmt.Errorf("line comment at offset %d", i)
+
+		case c == '#' && d == MySQL:
+			return fmt.Errorf("line comment at offset %d", i)
+
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end, err := skipBlockComment(query, i, d == PostgreSQL)
+			if err != nil {
+				return err
+			}
+
+			i = end
+
+		case c == '$' && d == PostgreSQL && (i == 0 || !isIdent(query[i-1])):
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+
+			if j == i+1 {
+				return fmt.Errorf("dollar-quoted string at offset %d", i)
+			}
+
+			n, _ := strconv.Atoi(query[i+1 : j])
+			if numbered == nil {
+				numbered = map[int]bool{}
+			}
+
+			numbered[n] = true
+			i = j - 1
+
+		case c == '?' && d != PostgreSQL:
+			j := i + 1
+			for d == SQLite && j < len(query) && isDigit(query[j]) {
+				j++
+			}
+
+			if j == i+1 {
+				positional++
+				continue
+			}
+
+			n, _ := strconv.Atoi(query[i+1 : j])
+			if numbered == nil {
+				numbered = map[int]bool{}
+			}
+
+			numbered[n] = true
+			i = j - 1
+		}
+	}
+
+	switch {
+	case positional > 0 && numbered != nil:
+		return fmt.Errorf("both positional and numbered placeholders are used")
+
+	case numbered != nil:
+		if len(numbered) != len(args) {
+			return fmt.Errorf("%d numbered placeholders are used, but %d arguments are given", len(numbered), len(args))
+		}
+
+		for n := range len(args) {
+			if !numbered[n+1] {
+				return fmt.Errorf("argument %d is not used", n+1)
+			}
+		}
+
+	case positional != len(args):
+		return fmt.Errorf("%d placeholders are used, but %d arguments are given", positional, len(args))
+	}
+
+	return nil
+}
+
+// skipQuoted returns the index of the closing quote of the literal or identifier starting at the given index.
+//
+// Quotes are escaped by doubling them; if backslash is true, they also could be escaped by backslash.
+func skipQuoted(query string, start int, quote byte, backslash bool) (int, error) {
+	for i := start + 1; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+
+		case quote:
+			if i+1 < len(query) && query[i+1] == quote {
+				i++
+				continue
+			}
+
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated %c at offset %d", quote, start)
+}
+
+// skipBlockComment returns the index of the last character of the block comment starting at the given index.
+func skipBlockComment(query string, start int, nested bool) (int, error) {
+	depth := 0
+
+	for i := start; i+1 < len(query); i++ {
+		switch {
+		case query[i] == '/' && query[i+1] == '*':
+			if depth == 0 || nested {
+				depth++
+			}
+
+			i++
+
+		case query[i] == '*' && query[i+1] == '/':
+			depth--
+			i++
+
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unterminated comment at offset %d", start)
+}
+
+// isSpace returns true if c is an ASCII whitespace.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// isDigit returns true if c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isIdent returns true if c could be a part of unquoted identifier.
+func isIdent(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
@@ -0,0 +1,174 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlguard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		dialect Dialect
+		query   string
+		args    []any
+		err     string
+	}{
+		"PostgreSQL": {
+			dialect: PostgreSQL,
+			query:   `SELECT _jsonb FROM "test"."coll" WHERE _jsonb->'_id' = $1 AND _jsonb @? $2 /* 'comment */;`,
+			args:    []any{1, 2},
+		},
+		"PostgreSQLReused": {
+			dialect: PostgreSQL,
+			query:   `SELECT $1, $2, $1`,
+			args:    []any{1, 2},
+		},
+		"PostgreSQLUnused": {
+			dialect: PostgreSQL,
+			query:   `SELECT $1, $3`,
+			args:    []any{1, 2, 3},
+			err:     "2 numbered placeholders are used, but 3 arguments are given",
+		},
+		"PostgreSQLGap": {
+			dialect: PostgreSQL,
+			query:   `SELECT $1, $3`,
+			args:    []any{1, 2},
+			err:     "argument 2 is not used",
+		},
+		"PostgreSQLNestedComment": {
+			dialect: PostgreSQL,
+			query:   `SELECT /* a /* b */ ; */ 1`,
+		},
+		"PostgreSQLEscapeString": {
+			dialect: PostgreSQL,
+			query:   `SELECT E'a\'b', 'c\'`,
+		},
+		"PostgreSQLDollarQuoted": {
+			dialect: PostgreSQL,
+			query:   `SELECT $$a$$`,
+			err:     "dollar-quoted string at offset 7",
+		},
+		"PostgreSQLIdentifierDollar": {
+			dialect: PostgreSQL,
+			query:   `SELECT a$1 FROM t`,
+		},
+		"SQLite": {
+			dialect: SQLite,
+			query:   `SELECT _ferretdb_sjson FROM "coll" WHERE _ferretdb_sjson->'$._id' = ? AND x = '?'`,
+			args:    []any{1},
+		},
+		"SQLiteNumbered": {
+			dialect: SQLite,
+			query:   `SELECT ?1, ?2, ?1`,
+			args:    []any{1, 2},
+		},
+		"SQLiteMixed": {
+			dialect: SQLite,
+			query:   `SELECT ?1, ?`,
+			args:    []any{1, 2},
+			err:     "both positional and numbered placeholders are used",
+		},
+		"SQLiteCount": {
+			dialect: SQLite,
+			query:   `SELECT ?, ?`,
+			args:    []any{1},
+			err:     "2 placeholders are used, but 1 arguments are given",
+		},
+		"MySQL": {
+			dialect: MySQL,
+			query:   "SELECT `a``b` FROM `db`.`coll` WHERE x = 'it\\'s' AND y = ?",
+			args:    []any{1},
+		},
+		"MySQLLineComment": {
+			dialect: MySQL,
+			query:   "SELECT 1 # comment",
+			err:     "line comment at offset 9",
+		},
+		"HANA": {
+			dialect: HANA,
+			query:   `SELECT * FROM "db"."coll" WHERE x = 'a''b' AND y = ?`,
+			args:    []any{1},
+		},
+		"LineComment": {
+			dialect: SQLite,
+			query:   `SELECT 1 -- comment`,
+			err:     "line comment at offset 9",
+		},
+		"MultipleStatements": {
+			dialect: SQLite,
+			query:   `SELECT 1; DROP TABLE t`,
+			err:     "multiple statements at offset 10",
+		},
+		"TrailingSemicolon": {
+			dialect: HANA,
+			query:   "SELECT 1;\n",
+		},
+		"InterpolatedString": {
+			dialect: SQLite,
+			query:   `SELECT 1 WHERE x = 'a'; DROP TABLE t; --'`,
+			err:     "multiple statements at offset 24",
+		},
+		"UnterminatedString": {
+			dialect: PostgreSQL,
+			query:   `SELECT 'a`,
+			err:     "unterminated ' at offset 7",
+		},
+		"UnterminatedIdentifier": {
+			dialect: SQLite,
+			query:   `SELECT "a`,
+			err:     `unterminated " at offset 7`,
+		},
+		"UnterminatedComment": {
+			dialect: MySQL,
+			query:   `SELECT 1 /* a`,
+			err:     "unterminated comment at offset 9",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Check(tc.dialect, tc.query, tc.args)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+}
+
+func FuzzCheck(f *testing.F) {
+	for _, q := range []string{
+		`SELECT 1`,
+		`SELECT 'a''b', "c""d", $1 /* e */;`,
+		"SELECT `a`, ?, ?1, 'b\\'c'",
+	} {
+		f.Add(uint8(PostgreSQL), q)
+	}
+
+	f.Fuzz(func(t *testing.T, d uint8, query string) {
+		t.Parallel()
+
+		// must not panic
+		_ = Check(Dialect(d%uint8(HANA)+1), query, nil)
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package state
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the size of the state encryption key in bytes.
+const KeySize = 32
+
+// encryptedPrefix marks encrypted state files.
+// Encrypted data (a nonce followed by AES-256-GCM ciphertext) follows it.
+var encryptedPrefix = []byte("FerretDB encrypted state v1\n")
+
+// ParseKey decodes a base64-encoded state encryption key.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode state encryption key: %w", err)
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("state encryption key should be %d bytes long, got %d", KeySize, len(key))
+	}
+
+	return key, nil
+}
+
+// isEncrypted returns true if the given state file content is encrypted.
+func isEncrypted(b []byte) bool {
+	return bytes.HasPrefix(b, encryptedPrefix)
+}
+
+// newAEAD returns AES-256-GCM cipher for the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// encrypt returns encrypted state file content for the given plaintext.
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	res := append(bytes.Clone(encryptedPrefix), nonce...)
+
+	// use prefix as additional data to authenticate it too
+	return aead.Seal(res, nonce, plaintext, encryptedPrefix), nil
+}
+
+// decrypt returns plaintext for the given encrypted state file content.
+func decrypt(key, b []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	b = bytes.TrimPrefix(b, encryptedPrefix)
+
+	if len(b) < aead.NonceSize() {
+		return nil, errors.New("encrypted state is too short")
+	}
+
+	nonce, ciphertext := b[:aead.NonceSize()], b[aead.NonceSize():]
+
+	res, err := aead.Open(nil, nonce, ciphertext, encryptedPrefix)
+	if err != nil {
+		return nil, errors.New("failed to decrypt state: wrong key or corrupted file")
+	}
+
+	return res, nil
+}
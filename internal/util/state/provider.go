@@ -27,6 +27,7 @@ import (
 // Provider provides access to FerretDB process state.
 type Provider struct {
 	filename string
+	key      []byte // nil if state is not encrypted
 
 	rw   sync.RWMutex
 	s    *State
@@ -39,14 +40,46 @@ type Provider struct {
 //
 // All provider's methods are thread-safe.
 func NewProvider(filename string) (*Provider, error) {
+	return newProvider(filename, nil)
+}
+
+// NewEncryptedProvider creates a new Provider that stores state in the given file
+// encrypted with the given key; see [ParseKey].
+//
+// Existing unencrypted state file is read and then encrypted.
+// Encrypted state file that could not be decrypted with the given key is an error.
+func NewEncryptedProvider(filename string, key []byte) (*Provider, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("state encryption key should be %d bytes long, got %d", KeySize, len(key))
+	}
+
+	return newProvider(filename, key)
+}
+
+// newProvider creates a new Provider with optional encryption key.
+func newProvider(filename string, key []byte) (*Provider, error) {
 	p := &Provider{
 		filename: filename,
+		key:      key,
 		s:        new(State),
 		subs:     make(map[chan struct{}]struct{}, 1),
 	}
 
 	if p.filename != "" {
 		b, _ := os.ReadFile(p.filename)
+
+		// do not overwrite encrypted state that we can't read
+		if isEncrypted(b) {
+			if p.key == nil {
+				return nil, fmt.Errorf("state file %q is encrypted, but encryption key is not set", p.filename)
+			}
+
+			var err error
+			if b, err = decrypt(p.key, b); err != nil {
+				return nil, err
+			}
+		}
+
 		_ = json.Unmarshal(b, p.s)
 	}
 
@@ -55,7 +88,7 @@ func NewProvider(filename string) (*Provider, error) {
 	// Simply overwrite state to handle all errors and edge cases
 	// like missing directory, corrupted file, invalid UUID, etc.,
 	// and also to check permissions.
-	if err := persistState(p.s, p.filename); err != nil {
+	if err := persistState(p.s, p.filename, p.key); err != nil {
 		return p, fmt.Errorf("failed to persist state: %w", err)
 	}
 
@@ -103,7 +136,7 @@ func (p *Provider) Update(update func(s *State)) error {
 	p.s = p.s.deepCopy()
 	p.s.fill()
 
-	err := persistState(p.s, p.filename)
+	err := persistState(p.s, p.filename, p.key)
 	if err != nil {
 		err = fmt.Errorf("failed to persist state: %w", err)
 	}
@@ -120,15 +153,20 @@ func (p *Provider) Update(update func(s *State)) error {
 }
 
 // persistState saves state to the given file without modifying (filling) it.
+// State is encrypted if key is not nil.
 //
 // It exist immediately if filename is empty.
-func persistState(s *State, filename string) error {
+func persistState(s *State, filename string, key []byte) error {
 	if filename == "" {
 		return nil
 	}
 
 	b, err := json.Marshal(s)
 
+	if err == nil && key != nil {
+		b, err = encrypt(key, b)
+	}
+
 	if err == nil {
 		_ = os.MkdirAll(filepath.Dir(filename), 0o777)
 		err = os.WriteFile(filename, b, 0o666)
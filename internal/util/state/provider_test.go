@@ -106,4 +106,53 @@ func TestProvider(t *testing.T) {
 
 		<-got
 	})
+
+	t.Run("Encrypted", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := ParseKey("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+		require.NoError(t, err)
+
+		_, err = ParseKey("AAECAwQFBgc=")
+		require.Error(t, err)
+
+		filename := filepath.Join(t.TempDir(), "state.json")
+
+		// existing unencrypted state is encrypted
+		p1, err := NewProvider(filename)
+		require.NoError(t, err)
+
+		s1 := p1.Get()
+
+		p2, err := NewEncryptedProvider(filename, key)
+		require.NoError(t, err)
+
+		s2 := p2.Get()
+		assert.Equal(t, s1.UUID, s2.UUID)
+
+		b, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		assert.True(t, isEncrypted(b))
+		assert.NotContains(t, string(b), s1.UUID)
+
+		p3, err := NewEncryptedProvider(filename, key)
+		require.NoError(t, err)
+		assert.Equal(t, s1.UUID, p3.Get().UUID)
+
+		b, err = os.ReadFile(filename)
+		require.NoError(t, err)
+
+		// encrypted state is not overwritten without the right key
+		_, err = NewProvider(filename)
+		require.Error(t, err)
+
+		wrongKey := make([]byte, KeySize)
+
+		_, err = NewEncryptedProvider(filename, wrongKey)
+		require.Error(t, err)
+
+		b2, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		assert.Equal(t, b, b2)
+	})
 }
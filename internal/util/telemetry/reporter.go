@@ -22,10 +22,12 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"runtime"
 	"time"
 
 	"github.com/AlekSi/pointer"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
@@ -60,6 +62,11 @@ type request struct {
 	// result (e.g. "NotImplemented", "InternalError"; or "ok") ->
 	// count.
 	CommandMetrics map[string]map[string]map[string]map[string]int `json:"command_metrics"`
+
+	// driver name (e.g. "mongo-go-driver", "nodejs") ->
+	// driver version (e.g. "1.14.0") ->
+	// count.
+	DriverMetrics map[string]map[string]int `json:"driver_metrics"`
 }
 
 // response represents telemetry response.
@@ -69,15 +76,26 @@ type response struct {
 	UpdateAvailable bool   `json:"update_available"`
 }
 
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "telemetry"
+)
+
 // Reporter sends telemetry reports if telemetry is enabled.
 type Reporter struct {
 	*NewReporterOpts
 	c *http.Client
+
+	reports     prometheus.Counter
+	failures    prometheus.Counter
+	lastSuccess prometheus.Gauge
 }
 
 // NewReporterOpts represents reporter options.
 type NewReporterOpts struct {
 	URL            string
+	Proxy          string // HTTP proxy URL; if empty, proxy environment variables are used
 	F              *Flag
 	DNT            string
 	ExecName       string
@@ -104,9 +122,46 @@ func NewReporter(opts *NewReporterOpts) (*Reporter, error) {
 		return nil, err
 	}
 
+	c := http.DefaultClient
+
+	if opts.Proxy != "" {
+		var u *url.URL
+		if u, err = url.Parse(opts.Proxy); err != nil {
+			return nil, fmt.Errorf("invalid telemetry proxy URL: %w", err)
+		}
+
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(u)
+		c = &http.Client{Transport: t}
+	}
+
 	return &Reporter{
 		NewReporterOpts: opts,
-		c:               http.DefaultClient,
+		c:               c,
+		reports: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "reports_total",
+				Help:      "Total number of telemetry report attempts.",
+			},
+		),
+		failures: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "report_failures_total",
+				Help:      "Total number of failed telemetry report attempts.",
+			},
+		),
+		lastSuccess: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "last_success_timestamp_seconds",
+				Help:      "Unix time of the last successful telemetry report.",
+			},
+		),
 	}, nil
 }
 
@@ -238,6 +293,7 @@ func makeRequest(s *state.State, m *connmetrics.ConnMetrics) *request {
 		Uptime: time.Since(s.Start),
 
 		CommandMetrics: commandMetrics,
+		DriverMetrics:  m.GetDrivers(),
 	}
 }
 
@@ -253,6 +309,19 @@ func (r *Reporter) report(ctx context.Context) {
 		return
 	}
 
+	r.reports.Inc()
+
+	var success bool
+
+	defer func() {
+		if success {
+			r.lastSuccess.SetToCurrentTime()
+			return
+		}
+
+		r.failures.Inc()
+	}()
+
 	request := makeRequest(s, r.ConnMetrics)
 	r.L.InfoContext(ctx, "Reporting telemetry.", slog.String("url", r.URL), slog.Any("data", request))
 
@@ -291,6 +360,8 @@ func (r *Reporter) report(ctx context.Context) {
 		return
 	}
 
+	success = true
+
 	r.L.DebugContext(ctx, "Read telemetry response.", slog.Any("response", response))
 
 	if response.UpdateInfo != "" || response.UpdateAvailable {
@@ -315,3 +386,22 @@ func (r *Reporter) report(ctx context.Context) {
 		return
 	}
 }
+
+// Describe implements [prometheus.Collector].
+func (r *Reporter) Describe(ch chan<- *prometheus.Desc) {
+	r.reports.Describe(ch)
+	r.failures.Describe(ch)
+	r.lastSuccess.Describe(ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (r *Reporter) Collect(ch chan<- prometheus.Metric) {
+	r.reports.Collect(ch)
+	r.failures.Collect(ch)
+	r.lastSuccess.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*Reporter)(nil)
+)
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/AlekSi/pointer"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -224,3 +225,70 @@ func TestReporterReport(t *testing.T) {
 		assert.Empty(t, s.LatestVersion)
 	})
 }
+
+func TestReporterMetrics(t *testing.T) {
+	t.Parallel()
+
+	var serverCalled int
+	bs := beaconServer(t, &serverCalled, &response{LatestVersion: "v1.2.1"})
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	opts := NewReporterOpts{
+		URL:           bs.URL,
+		F:             &Flag{v: pointer.ToBool(true)},
+		ConnMetrics:   connmetrics.NewListenerMetrics().ConnMetrics,
+		P:             sp,
+		L:             testutil.Logger(t),
+		ReportTimeout: 1 * time.Minute,
+	}
+
+	r, err := NewReporter(&opts)
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	r.report(testutil.Ctx(t))
+	assert.Equal(t, 1, serverCalled)
+
+	bs.Close()
+
+	r.report(testutil.Ctx(t))
+	assert.Equal(t, 1, serverCalled)
+
+	assert.Equal(t, float64(2), promtestutil.ToFloat64(r.reports))
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(r.failures))
+	assert.GreaterOrEqual(t, promtestutil.ToFloat64(r.lastSuccess), float64(start.Unix()))
+}
+
+func TestReporterProxy(t *testing.T) {
+	t.Parallel()
+
+	var serverCalled int
+	proxy := beaconServer(t, &serverCalled, &response{LatestVersion: "v1.2.1"})
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	opts := NewReporterOpts{
+		URL:           "http://beacon.invalid/",
+		Proxy:         proxy.URL,
+		F:             &Flag{v: pointer.ToBool(true)},
+		ConnMetrics:   connmetrics.NewListenerMetrics().ConnMetrics,
+		P:             sp,
+		L:             testutil.Logger(t),
+		ReportTimeout: 1 * time.Minute,
+	}
+
+	r, err := NewReporter(&opts)
+	require.NoError(t, err)
+
+	r.report(testutil.Ctx(t))
+	assert.Equal(t, 1, serverCalled)
+	assert.Equal(t, "v1.2.1", r.P.Get().LatestVersion)
+
+	opts.Proxy = "http://[::1"
+	_, err = NewReporter(&opts)
+	require.ErrorContains(t, err, "invalid telemetry proxy URL")
+}
@@ -144,6 +144,7 @@ func diffSlices[T types.Type](tb testtb.TB, expected, actual []T) (expectedS str
 // equal compares any BSON values in a way that is useful for tests:
 //   - float64 NaNs are equal to each other;
 //   - float64 zero values are compared with sign (math.Copysign(0, -1) != math.Copysign(0, +1));
+//   - types.Decimal128 values are compared by representation (1.0 != 1.00);
 //   - time.Time values are compared using Equal method.
 //
 // This function is for tests; it should not try to convert values to different types before comparing them.
@@ -318,6 +319,13 @@ func equalScalars(tb testtb.TB, v1, v2 any) bool {
 		}
 		return s1 == s2
 
+	case types.Decimal128:
+		s2, ok := v2.(types.Decimal128)
+		if !ok {
+			return false
+		}
+		return s1 == s2
+
 	default:
 		tb.Fatalf("unhandled types %T, %T", v1, v2)
 		panic("not reached")
@@ -0,0 +1,193 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+)
+
+const (
+	// ocspRetryInterval is the interval between OCSP requests after a failure.
+	ocspRetryInterval = time.Minute
+
+	// ocspDefaultInterval is the interval between OCSP requests if the response has no next update time.
+	ocspDefaultInterval = time.Hour
+
+	// ocspMaxResponseSize is the maximum size of OCSP response.
+	ocspMaxResponseSize = 1 << 20
+)
+
+// OCSPStapler fetches OCSP responses for the server certificate from its OCSP responder
+// and staples them to TLS handshakes.
+type OCSPStapler struct {
+	l      *slog.Logger
+	client *http.Client
+
+	leaf   *x509.Certificate
+	issuer *x509.Certificate
+
+	rw   sync.RWMutex
+	cert *tls.Certificate // copy with the current OCSP response
+}
+
+// NewOCSPStapler creates a new OCSPStapler for the given certificate.
+//
+// The certificate chain should include the issuer certificate,
+// and the leaf certificate should include OCSP responder URL.
+func NewOCSPStapler(cert *tls.Certificate, l *slog.Logger) (*OCSPStapler, error) {
+	if len(cert.Certificate) < 2 {
+		return nil, errors.New("OCSP stapling: TLS certificate file should include the issuer certificate")
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("OCSP stapling: %w", err)
+	}
+
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil {
+		return nil, fmt.Errorf("OCSP stapling: %w", err)
+	}
+
+	if len(leaf.OCSPServer) == 0 {
+		return nil, errors.New("OCSP stapling: TLS certificate has no OCSP responder URL")
+	}
+
+	c := *cert
+
+	return &OCSPStapler{
+		l:      l,
+		client: &http.Client{Timeout: 10 * time.Second},
+		leaf:   leaf,
+		issuer: issuer,
+		cert:   &c,
+	}, nil
+}
+
+// GetCertificate returns the certificate with the current OCSP response, if any.
+//
+// It should be used as [tls.Config.GetCertificate].
+func (s *OCSPStapler) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.rw.RLock()
+	defer s.rw.RUnlock()
+
+	return s.cert, nil
+}
+
+// Run fetches OCSP responses until ctx is canceled.
+func (s *OCSPStapler) Run(ctx context.Context) {
+	for {
+		next, err := s.refresh(ctx)
+		if err != nil {
+			s.l.WarnContext(ctx, "Failed to refresh OCSP response", logging.Error(err))
+			next = ocspRetryInterval
+		}
+
+		ctxutil.Sleep(ctx, next)
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// refresh fetches and staples a new OCSP response.
+// It returns the duration after which the next refresh should happen.
+func (s *OCSPStapler) refresh(ctx context.Context) (time.Duration, error) {
+	req, err := ocsp.CreateRequest(s.leaf, s.issuer, &ocsp.RequestOptions{Hash: crypto.SHA1})
+	if err != nil {
+		return 0, err
+	}
+
+	url := s.leaf.OCSPServer[0]
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(req))
+	if err != nil {
+		return 0, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+	httpReq.Header.Set("Accept", "application/ocsp-response")
+
+	httpResp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+
+	defer httpResp.Body.Close() //nolint:errcheck // we are only reading it
+
+	if httpResp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected OCSP responder status %s", httpResp.Status)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(httpResp.Body, ocspMaxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := ocsp.ParseResponseForCert(b, s.leaf, s.issuer)
+	if err != nil {
+		return 0, err
+	}
+
+	staple := b
+
+	switch resp.Status {
+	case ocsp.Good:
+		s.l.DebugContext(ctx, "OCSP response stapled", slog.Time("next_update", resp.NextUpdate))
+
+	case ocsp.Revoked:
+		s.l.ErrorContext(ctx, "TLS certificate is revoked", slog.Time("revoked_at", resp.RevokedAt))
+
+	default:
+		s.l.WarnContext(ctx, "TLS certificate status is unknown to OCSP responder")
+
+		staple = nil
+	}
+
+	s.rw.Lock()
+	c := *s.cert
+	c.OCSPStaple = staple
+	s.cert = &c
+	s.rw.Unlock()
+
+	if resp.NextUpdate.IsZero() {
+		return ocspDefaultInterval, nil
+	}
+
+	// refresh halfway to the next update
+	next := resp.NextUpdate.Sub(resp.ThisUpdate) / 2
+	if until := time.Until(resp.NextUpdate); next > until {
+		next = until
+	}
+
+	return max(next, ocspRetryInterval), nil
+}
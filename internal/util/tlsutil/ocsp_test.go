@@ -0,0 +1,160 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestOCSPStapler(t *testing.T) {
+	t.Parallel()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	require.NoError(t, err)
+
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	var status int
+
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		req, err := ocsp.ParseRequest(b)
+		require.NoError(t, err)
+
+		now := time.Now()
+
+		resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   now,
+			NextUpdate:   now.Add(time.Hour),
+			RevokedAt:    now,
+		}, caKey)
+		require.NoError(t, err)
+
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(responder.Close)
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		OCSPServer:   []string{responder.URL},
+	}
+
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, leafKey.Public(), caKey)
+	require.NoError(t, err)
+
+	t.Run("NoIssuer", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewOCSPStapler(&tls.Certificate{Certificate: [][]byte{leafDER}}, testutil.Logger(t))
+		require.ErrorContains(t, err, "should include the issuer certificate")
+	})
+
+	t.Run("NoResponder", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewOCSPStapler(&tls.Certificate{Certificate: [][]byte{caDER, caDER}}, testutil.Logger(t))
+		require.ErrorContains(t, err, "no OCSP responder URL")
+	})
+
+	// subtests below are not parallel as they change responder status
+
+	cert := &tls.Certificate{
+		Certificate: [][]byte{leafDER, caDER},
+		PrivateKey:  crypto.Signer(leafKey),
+	}
+
+	s, err := NewOCSPStapler(cert, testutil.Logger(t))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	c, err := s.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Nil(t, c.OCSPStaple)
+
+	for name, tc := range map[string]struct {
+		status  int
+		stapled bool
+	}{
+		"Good":    {status: ocsp.Good, stapled: true},
+		"Revoked": {status: ocsp.Revoked, stapled: true},
+		"Unknown": {status: ocsp.Unknown, stapled: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			status = tc.status
+
+			next, err := s.refresh(ctx)
+			require.NoError(t, err)
+			assert.InDelta(t, 30*time.Minute, next, float64(time.Minute))
+
+			c, err := s.GetCertificate(nil)
+			require.NoError(t, err)
+
+			if !tc.stapled {
+				assert.Nil(t, c.OCSPStaple)
+				return
+			}
+
+			resp, err := ocsp.ParseResponseForCert(c.OCSPStaple, s.leaf, s.issuer)
+			require.NoError(t, err)
+			assert.Equal(t, tc.status, resp.Status)
+		})
+	}
+}
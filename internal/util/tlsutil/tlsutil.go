@@ -20,6 +20,8 @@ import (
 	"crypto/x509"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 )
 
 // Config provides TLS configuration for the given certificate and key files.
@@ -64,3 +66,82 @@ func Config(certFile, keyFile, caFile string) (*tls.Config, error) {
 
 	return config, nil
 }
+
+// ServerOpts represents additional TLS settings for servers.
+type ServerOpts struct {
+	// MinVersion is the minimal TLS version: "1.0", "1.1", "1.2", or "1.3".
+	// Go's default (1.2) is used if empty.
+	MinVersion string
+
+	// CipherSuites are names of allowed TLS 1.0-1.2 cipher suites, for example,
+	// TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384. Go's defaults are used if empty.
+	// Insecure cipher suites are not allowed.
+	// TLS 1.3 cipher suites are not configurable.
+	CipherSuites []string
+}
+
+// Apply sets TLS settings on the given configuration.
+func (opts *ServerOpts) Apply(config *tls.Config) error {
+	if opts.MinVersion != "" {
+		v, err := parseVersion(opts.MinVersion)
+		if err != nil {
+			return err
+		}
+
+		config.MinVersion = v
+	}
+
+	if len(opts.CipherSuites) > 0 {
+		ids, err := parseCipherSuites(opts.CipherSuites)
+		if err != nil {
+			return err
+		}
+
+		config.CipherSuites = ids
+	}
+
+	return nil
+}
+
+// parseVersion returns TLS version constant for the given version string.
+func parseVersion(s string) (uint16, error) {
+	switch s {
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unknown TLS version %q, expected one of 1.0, 1.1, 1.2, 1.3", s)
+	}
+}
+
+// parseCipherSuites returns cipher suite IDs for the given names.
+func parseCipherSuites(names []string) ([]uint16, error) {
+	res := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+
+		if slices.ContainsFunc(tls.InsecureCipherSuites(), func(cs *tls.CipherSuite) bool { return cs.Name == name }) {
+			return nil, fmt.Errorf("TLS cipher suite %q is insecure", name)
+		}
+
+		i := slices.IndexFunc(tls.CipherSuites(), func(cs *tls.CipherSuite) bool { return cs.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown TLS cipher suite %q", name)
+		}
+
+		cs := tls.CipherSuites()[i]
+		if !slices.ContainsFunc(cs.SupportedVersions, func(v uint16) bool { return v < tls.VersionTLS13 }) {
+			return nil, fmt.Errorf("TLS 1.3 cipher suite %q is not configurable", name)
+		}
+
+		res = append(res, cs.ID)
+	}
+
+	return res, nil
+}
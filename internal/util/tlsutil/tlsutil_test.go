@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tlsutil
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerOpts(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		opts         ServerOpts
+		minVersion   uint16
+		cipherSuites []uint16
+		err          string
+	}{
+		"Empty": {},
+		"MinVersion": {
+			opts:       ServerOpts{MinVersion: "1.3"},
+			minVersion: tls.VersionTLS13,
+		},
+		"MinVersionUnknown": {
+			opts: ServerOpts{MinVersion: "1.4"},
+			err:  `unknown TLS version "1.4", expected one of 1.0, 1.1, 1.2, 1.3`,
+		},
+		"CipherSuites": {
+			opts: ServerOpts{
+				CipherSuites: []string{
+					"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+					" TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256",
+				},
+			},
+			cipherSuites: []uint16{
+				tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+				tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			},
+		},
+		"CipherSuitesInsecure": {
+			opts: ServerOpts{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}},
+			err:  `TLS cipher suite "TLS_RSA_WITH_RC4_128_SHA" is insecure`,
+		},
+		"CipherSuitesUnknown": {
+			opts: ServerOpts{CipherSuites: []string{"TLS_FOO"}},
+			err:  `unknown TLS cipher suite "TLS_FOO"`,
+		},
+		"CipherSuitesTLS13": {
+			opts: ServerOpts{CipherSuites: []string{"TLS_AES_128_GCM_SHA256"}},
+			err:  `TLS 1.3 cipher suite "TLS_AES_128_GCM_SHA256" is not configurable`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var config tls.Config
+
+			err := tc.opts.Apply(&config)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.minVersion, config.MinVersion)
+			assert.Equal(t, tc.cipherSuites, config.CipherSuites)
+		})
+	}
+}